@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+// checkCompareTemplates fails on a compared template the config doesn't define.
+// Rendering would quietly fall back to classic and compare it against itself.
+func checkCompareTemplates(cfg *config.Config, names []string) error {
+	for _, name := range names {
+		if _, ok := cfg.Templates.Config[name]; !ok && !template.IsBuiltinTemplate(name) {
+			return fmt.Errorf("-compare-templates: template %s not found (see -list-templates)", name)
+		}
+	}
+	return nil
+}
+
+// prepareCompareOutput creates the -compare-output directory
+func prepareCompareOutput(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating -compare-output directory: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"github.com/nowwaveradio/mixcloud-updater/internal/cli"
+	"github.com/nowwaveradio/mixcloud-updater/internal/processor"
+)
+
+// processShow runs -show, once per date when -date lists several
+func processShow(showProcessor *processor.ShowProcessor) error {
+	if dates := cli.DateList(flags.DateOverride); len(dates) > 1 {
+		return showProcessor.ProcessShowDates(flags.ShowAlias, flags.TemplateName, dates, flags.DryRun)
+	}
+	return showProcessor.ProcessShow(flags.ShowAlias, flags.TemplateName, flags.DateOverride, flags.DryRun)
+}
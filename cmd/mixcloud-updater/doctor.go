@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/doctor"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// runDoctor runs the health checks and prints them as a checklist, or as JSON
+// with asJSON. It never starts the OAuth flow or changes the config file.
+func runDoctor(configPath string, asJSON bool, out io.Writer) (*doctor.Report, error) {
+	report := doctor.Run(context.Background(), doctor.Options{
+		ConfigPath:   filepath.Clean(configPath),
+		StrictConfig: flags.StrictConfig,
+		Now:          time.Now(),
+		NewAPI: func(cfg *config.Config) (doctor.API, error) {
+			// No config path: the checks must not write refreshed tokens back
+			return mixcloud.NewClient(cfg, "")
+		},
+	})
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return nil, fmt.Errorf("writing report: %w", err)
+		}
+		return report, nil
+	}
+
+	fmt.Fprintf(out, "Doctor:\n")
+	fmt.Fprintf(out, "=======\n\n")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	counts := make(map[doctor.Status]int)
+	for _, check := range report.Checks {
+		counts[check.Status]++
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.ToUpper(string(check.Status)), check.Name, check.Message)
+		if check.Hint != "" {
+			fmt.Fprintf(w, "\t\t→ %s\n", check.Hint)
+		}
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%s: %d passed, %d warning(s), %d failure(s)\n", strings.ToUpper(string(report.Status)),
+		counts[doctor.StatusPass], counts[doctor.StatusWarn], counts[doctor.StatusFail])
+	return report, nil
+}
+
+// openJSONOutput reserves stdout for -json output and moves human output to
+// stderr, like -progress-json. Call it before the logger is initialized.
+func openJSONOutput() io.Writer {
+	jsonOut := os.Stdout
+	os.Stdout = os.Stderr
+	return jsonOut
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// runPrintEffectiveConfig prints the config in force - the local file with its
+// overlay, defaults and environment overrides merged in - as TOML. The OAuth
+// secrets and proxy password are redacted so the output can be shared.
+func runPrintEffectiveConfig(configPath string, out io.Writer) error {
+	cfg, err := loadConfig(filepath.Clean(configPath))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, warning := range cfg.Warnings {
+		console.Warnf("Warning: %s\n", warning)
+	}
+
+	data, err := cfg.EffectiveTOML()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "# Effective configuration of %s\n", configPath)
+	if status := cfg.OverlayStatus(); status != "" {
+		fmt.Fprintf(out, "# Overlay: %s\n", status)
+	}
+	fmt.Fprintf(out, "# OAuth secrets and the proxy password are redacted\n\n")
+	_, err = out.Write(data)
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// runPrintEnvVars lists every environment variable that overrides the config,
+// marking the ones currently set. Values aren't printed since several are
+// secrets. A config that fails to load - often because of a bad override - is
+// reported and the list printed without per-show variables.
+func runPrintEnvVars(configPath string, out io.Writer) error {
+	cfg, err := loadConfig(filepath.Clean(configPath))
+	if err != nil {
+		console.Warnf("Warning: %v\n", err)
+		console.Warnf("Listing variables without per-show entries\n\n")
+		cfg = config.DefaultConfig()
+	}
+
+	fmt.Fprintf(out, "Environment Variables:\n")
+	fmt.Fprintf(out, "======================\n\n")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "VARIABLE\tTYPE\tCONFIG KEY\tSET\n")
+	for _, envVar := range cfg.EnvVars() {
+		set := ""
+		if os.Getenv(envVar.Name) != "" {
+			set = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", envVar.Name, envVar.Type, envVar.Key, set)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing variable list: %w", err)
+	}
+
+	fmt.Fprintf(out, "\nEmpty variables are ignored. Lists and maps ([filtering], [shows], [templates.config])\n")
+	fmt.Fprintf(out, "can't be overridden, apart from NWRMIXCLOUD_SHOWS_<KEY>_ENABLED.\n")
+	return nil
+}
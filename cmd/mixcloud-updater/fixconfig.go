@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// runFixConfig rewrites a BOM, smart quotes, non-breaking spaces and
+// Windows-1252 text in the config file to plain ASCII (-fix-config). It runs
+// before the config is first loaded, so the rest of the run sees the fixed file.
+func runFixConfig(configPath string, out io.Writer) error {
+	result, err := config.FixConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+	if !result.Changed() {
+		fmt.Fprintf(out, "Config %s has no smart quotes or BOM to fix\n", configPath)
+		return nil
+	}
+
+	lines := make([]string, len(result.Lines))
+	for i, line := range result.Lines {
+		lines[i] = strconv.Itoa(line)
+	}
+	logger.Get().Info("Fixed config file encoding",
+		slog.String("path", configPath),
+		slog.String("lines", strings.Join(lines, ",")),
+		slog.Bool("stripped_bom", result.StrippedBOM),
+		slog.String("backup", result.BackupPath))
+
+	var fixes []string
+	if result.StrippedBOM {
+		fixes = append(fixes, "removed byte order mark")
+	}
+	if len(lines) > 0 {
+		noun := "line"
+		if len(lines) > 1 {
+			noun = "lines"
+		}
+		fixes = append(fixes, fmt.Sprintf("rewrote %s %s to plain ASCII", noun, strings.Join(lines, ", ")))
+	}
+	fmt.Fprintf(out, "Fixed %s: %s\n", configPath, strings.Join(fixes, "; "))
+	fmt.Fprintf(out, "Original saved as %s\n", result.BackupPath)
+	return nil
+}
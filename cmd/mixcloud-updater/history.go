@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// historyReport is the -history -json output
+type historyReport struct {
+	Show        string               `json:"show"`
+	HistoryFile string               `json:"history_file"`
+	Entries     []state.HistoryEntry `json:"entries"` // Newest first
+}
+
+// runHistory prints a show's most recent publishes, newest first, as a table or
+// as JSON with asJSON. Like runWhichShow it loads the config directly so no
+// OAuth flow is started. Returns the number of entries printed.
+func runHistory(configPath, nameOrAlias string, n int, asJSON bool, out io.Writer) (int, error) {
+	cfg, err := loadConfig(filepath.Clean(configPath))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, warning := range cfg.Warnings {
+		console.Warnf("Warning: %s\n", warning)
+	}
+
+	// Read-only: a history not written yet is seeded from the state file in memory
+	runState := loadRunState(cfg, configPath)
+	history, err := state.LoadHistory(state.ResolveHistoryPath(cfg.Processing.HistoryFile, configPath), runState)
+	if err != nil {
+		return 0, err
+	}
+
+	resolver, err := shows.NewResolver(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("creating show resolver: %w", err)
+	}
+	showKey := resolver.FindShowKey(nameOrAlias)
+	if showKey == "" {
+		// Shows removed from the config keep their history
+		showKey = config.CanonicalShowKey(nameOrAlias)
+		if _, ok := history.Shows[showKey]; !ok {
+			return 0, resolver.NotFoundError(nameOrAlias)
+		}
+	}
+
+	entries := history.Entries(showKey, n)
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(historyReport{Show: showKey, HistoryFile: history.Path(), Entries: entries}); err != nil {
+			return 0, fmt.Errorf("writing history: %w", err)
+		}
+		return len(entries), nil
+	}
+
+	fmt.Fprintf(out, "Publish History: %s\n", showKey)
+	fmt.Fprintf(out, "================\n\n")
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "No publishes recorded for this show.\n")
+	} else {
+		migrated := false
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "PUBLISHED\tSHOW DATE\tCUE FILE\tTRACKS\tLENGTH\tTRUNCATED\tTEMPLATE\tURL\n")
+		for _, entry := range entries {
+			published := entry.PublishedAt.Local().Format("2006-01-02 15:04")
+			if entry.Migrated {
+				published += " *"
+				migrated = true
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", published, orDash(entry.ShowDate), orDash(entry.CueFile),
+				describeHistoryTracks(entry), describeHistoryLength(entry), describeHistoryTruncated(entry),
+				orDash(entry.Template), orDash(entry.ShowURL))
+		}
+		w.Flush()
+		if migrated {
+			fmt.Fprintf(out, "\n* Recorded before the history existed: only the date, track count and URL are known\n")
+		}
+	}
+
+	fmt.Fprintf(out, "\nHistory file: %s\n", history.Path())
+	return len(entries), nil
+}
+
+// describeHistoryTracks formats an entry's track counts as "21 (3 excluded)"
+func describeHistoryTracks(entry state.HistoryEntry) string {
+	if entry.ExcludedTracks > 0 {
+		return fmt.Sprintf("%d (%d excluded)", entry.Tracks, entry.ExcludedTracks)
+	}
+	return strconv.Itoa(entry.Tracks)
+}
+
+func describeHistoryLength(entry state.HistoryEntry) string {
+	if entry.Migrated {
+		return "-"
+	}
+	return strconv.Itoa(entry.DescriptionLength)
+}
+
+func describeHistoryTruncated(entry state.HistoryEntry) string {
+	switch {
+	case entry.Migrated:
+		return "-"
+	case entry.Truncated:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+// orDash returns s, or "-" when it's empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// prompter asks questions on the terminal, re-asking until the answer validates
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prompts for a value, offering defaultValue when the answer is left empty
+func (p *prompter) ask(question, defaultValue string, validate func(string) error) (string, error) {
+	for {
+		if defaultValue != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+
+		line, err := p.in.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			return "", fmt.Errorf("reading answer: %w", err)
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = defaultValue
+		}
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "  ✗ %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// initQuestion is one wizard prompt and the starter field it fills in
+type initQuestion struct {
+	question string
+	value    *string
+	validate func(string) error
+}
+
+// askAll asks each question in turn, storing the answers
+func (p *prompter) askAll(questions []initQuestion) error {
+	for _, q := range questions {
+		answer, err := p.ask(q.question, *q.value, q.validate)
+		if err != nil {
+			return err
+		}
+		*q.value = answer
+	}
+	return nil
+}
+
+// runInitWizard prompts for every starter config answer. Values already in
+// starter (from -init-* flags) are offered as defaults.
+func runInitWizard(starter *config.StarterConfig, in io.Reader, out io.Writer) error {
+	p := &prompter{in: bufio.NewReader(in), out: out}
+
+	fmt.Fprintf(out, "Mixcloud Updater setup\n")
+	fmt.Fprintf(out, "Press Enter to accept the value in [brackets].\n\n")
+
+	err := p.askAll([]initQuestion{
+		{"Station name", &starter.StationName, config.ValidateRequired},
+		{"Mixcloud username (from your profile URL)", &starter.MixcloudUsername, config.ValidateMixcloudUsername},
+		{"OAuth client ID (https://www.mixcloud.com/developers/create/)", &starter.ClientID, config.ValidateRequired},
+		{"OAuth client secret", &starter.ClientSecret, config.ValidateRequired},
+		{"CUE file directory", &starter.CueFileDirectory, config.ValidateCueDirectory},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nNow define your first show (add more to the config later).\n")
+	err = p.askAll([]initQuestion{
+		{"Show key (e.g. sounds-like)", &starter.Show.Key, config.ValidateShowKey},
+		{"Mixcloud title pattern, {date} is replaced (e.g. Sounds Like - {date})", &starter.Show.NamePattern, config.ValidateRequired},
+		{"CUE file pattern (e.g. MYR_SoundsLike_*.cue)", &starter.Show.FilePattern, config.ValidateFilePattern},
+	})
+	if err != nil {
+		return err
+	}
+
+	aliases, err := p.ask("Aliases, comma separated (optional)", strings.Join(starter.Show.Aliases, ","), nil)
+	if err != nil {
+		return err
+	}
+	starter.Show.Aliases = config.ParseAliases(aliases)
+
+	fmt.Fprintf(out, "\nStarter templates:\n")
+	fmt.Fprintf(out, "  (empty)   classic built-in format: 00:00 - \"Title\" by Artist\n")
+	fmt.Fprintf(out, "  detailed  numbered tracks with a header and footer\n")
+	fmt.Fprintf(out, "  minimal   00:00 Artist - Title\n")
+	template, err := p.ask("Template", starter.Template, config.ValidateStarterTemplate)
+	if err != nil {
+		return err
+	}
+	starter.Template = template
+
+	return nil
+}
+
+// runInit creates a commented starter config at configPath, prompting for the
+// answers unless -no-prompt is set
+func runInit(configPath string, in io.Reader, out io.Writer) error {
+	// Refuse up front rather than after the user has answered every question
+	if _, err := os.Stat(configPath); err == nil && !flags.Force {
+		return fmt.Errorf("%w: %s (use -force to overwrite)", config.ErrConfigExists, configPath)
+	}
+
+	starter := &config.StarterConfig{
+		StationName:      flags.InitStationName,
+		MixcloudUsername: flags.InitUsername,
+		ClientID:         flags.InitClientID,
+		ClientSecret:     flags.InitClientSecret,
+		CueFileDirectory: flags.InitCueDir,
+		Show: config.StarterShow{
+			Key:         flags.InitShowKey,
+			NamePattern: flags.InitShowName,
+			FilePattern: flags.InitShowPattern,
+			Aliases:     config.ParseAliases(flags.InitShowAliases),
+		},
+		Template: flags.InitTemplate,
+	}
+
+	if !flags.NoPrompt {
+		if err := runInitWizard(starter, in, out); err != nil {
+			return err
+		}
+	}
+
+	if err := config.WriteStarterConfig(configPath, starter, flags.Force); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nCreated config file: %s\n", configPath)
+	fmt.Fprintf(out, "Next steps:\n")
+	fmt.Fprintf(out, "  1. Review the file - every setting is commented\n")
+	fmt.Fprintf(out, "  2. Preview your show: %s -show %s -dry-run %s\n", os.Args[0], starter.Show.Key, configPath)
+	fmt.Fprintf(out, "  3. Run without -dry-run to authorize with Mixcloud and publish\n")
+	return nil
+}
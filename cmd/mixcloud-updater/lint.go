@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/lint"
+)
+
+// runLint reports config cruft grouped by severity. It loads the config directly
+// rather than through loadConfiguration so no OAuth flow is started, and returns
+// the number of findings.
+func runLint(configPath string, out io.Writer) (int, error) {
+	cfg, err := loadConfig(filepath.Clean(configPath))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, warning := range cfg.Warnings {
+		console.Warnf("Warning: %s\n", warning)
+	}
+
+	runState := loadRunState(cfg, configPath)
+	findings := lint.Check(cfg, runState, time.Now())
+
+	fmt.Fprintf(out, "Config Lint:\n")
+	fmt.Fprintf(out, "============\n\n")
+	if len(findings) == 0 {
+		fmt.Fprintf(out, "No issues found.\n")
+		return 0, nil
+	}
+
+	for _, severity := range lint.Severities {
+		var group []lint.Finding
+		for _, finding := range findings {
+			if finding.Severity == severity {
+				group = append(group, finding)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(out, "%s (%d):\n", strings.ToUpper(string(severity)), len(group))
+		for _, finding := range group {
+			fmt.Fprintf(out, "  [%s] %s\n", finding.Section, finding.Message)
+		}
+		fmt.Fprintf(out, "\n")
+	}
+	fmt.Fprintf(out, "%d finding(s). Lint findings are advisory and never block processing.\n", len(findings))
+	return len(findings), nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/runlock"
+)
+
+// acquireRunLock takes the config's lock file so overlapping runs don't both
+// refresh tokens and rewrite the config and state files, waiting up to wait for
+// another instance to finish. cfg is nil when the config didn't load.
+func acquireRunLock(cfg *config.Config, configPath string, wait time.Duration) (*runlock.Lock, error) {
+	staleMinutes := constants.DefaultLockStaleMinutes
+	if cfg != nil {
+		staleMinutes = cfg.Processing.LockStaleMinutes
+	}
+
+	return runlock.Acquire(runlock.PathFor(configPath), runlock.Options{
+		StaleAfter: time.Duration(staleMinutes) * time.Minute,
+		Wait:       wait,
+		OnWait: func(holder runlock.Info) {
+			logger.Get().Info("Waiting for another instance to finish",
+				slog.Int("pid", holder.PID),
+				slog.Time("started_at", holder.StartedAt),
+				slog.Duration("wait", wait))
+			console.Printf("⏳ Another instance is running (PID %d); waiting up to %s for it to finish...\n", holder.PID, wait)
+		},
+	})
+}
@@ -1,144 +1,53 @@
 package main
 
 import (
-	"flag"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
+	_ "time/tzdata" // processing.timezone on Windows hosts without a zoneinfo database
+	"unicode/utf8"
 
+	"golang.org/x/oauth2"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cli"
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/dateutil"
 	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
 	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
 	"github.com/nowwaveradio/mixcloud-updater/internal/processor"
+	"github.com/nowwaveradio/mixcloud-updater/internal/runlock"
 	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textcut"
 )
 
-const version = "1.0.0"
-
-var (
-	configFile  = flag.String("config", "config.toml", "Path to the configuration file")
-	showAlias   = flag.String("show", "", "Process specific show by name/alias (optional)")
-	templateName = flag.String("template", "", "Template name to use for formatting (optional)")
-	dateOverride = flag.String("date", "", "Override date for show (format must match show's date_format config)")
-	dryRun      = flag.Bool("dry-run", false, "Preview changes without updating Mixcloud")
-	showVersion = flag.Bool("version", false, "Show version information")
-	help        = flag.Bool("help", false, "Show help information")
-	listShows   = flag.Bool("list-shows", false, "List available shows and their aliases")
-	listTemplates = flag.Bool("list-templates", false, "List available templates")
-)
-
-func init() {
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Mixcloud Updater v%s\n\n", version)
-		fmt.Fprintf(os.Stderr, "Automatically updates Mixcloud show descriptions with formatted tracklists from CUE files.\n")
-		fmt.Fprintf(os.Stderr, "Uses a unified config-driven architecture for batch and single-show processing.\n\n")
-		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  %s [config.toml]                    # Process all enabled shows\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s [OPTIONS] [config.toml]          # Process with options\n\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  # Process all enabled shows from config\n")
-		fmt.Fprintf(os.Stderr, "  %s config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\n  # Process specific show by alias\n")
-		fmt.Fprintf(os.Stderr, "  %s -show nnw config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -show \"newer-new-wave\" config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\n  # Override show date (format must match show's date_format)\n")
-		fmt.Fprintf(os.Stderr, "  %s -show nnw -date \"6/28/2025\" config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\n  # Preview without updating\n")
-		fmt.Fprintf(os.Stderr, "  %s -dry-run config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -show sounds-like -dry-run config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\n  # List available shows and templates\n")
-		fmt.Fprintf(os.Stderr, "  %s -list-shows config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -list-templates config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\n  # Use specific template override\n")
-		fmt.Fprintf(os.Stderr, "  %s -show morning -template detailed config.toml\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\n  # Automation with cron (process all shows)\n")
-		fmt.Fprintf(os.Stderr, "  0 */2 * * * /path/to/mixcloud-updater /path/to/config.toml\n")
-	}
-}
-
-// validateArguments performs comprehensive validation of command-line arguments
-func validateArguments(configFilePath string) error {
-	// Validate and check config file
-	if err := validateConfigFile(configFilePath); err != nil {
-		return fmt.Errorf("config file validation failed: %w", err)
-	}
-
-	// Validate show alias format if provided
-	if *showAlias != "" {
-		if err := validateShowAlias(*showAlias); err != nil {
-			return fmt.Errorf("show alias validation failed: %w", err)
-		}
-	}
-
-	return nil
-}
-
-// validateShowAlias checks if the show alias format is valid  
-func validateShowAlias(alias string) error {
-	// Check if show alias is not just whitespace
-	trimmed := strings.TrimSpace(alias)
-	if trimmed == "" {
-		return fmt.Errorf("show alias cannot be empty or just whitespace")
-	}
-
-	// Check reasonable length limits
-	if len(trimmed) < 1 {
-		return fmt.Errorf("show alias too short (minimum 1 character): %q", trimmed)
-	}
-
-	if len(trimmed) > 50 {
-		return fmt.Errorf("show alias too long (maximum 50 characters): %q", trimmed)
-	}
-
-	// Update the global variable to use the trimmed version
-	*showAlias = trimmed
-
-	return nil
-}
-
-
-// validateConfigFile checks if the config file exists or can be created
-func validateConfigFile(filePath string) error {
-	// Clean the path
-	cleanPath := filepath.Clean(filePath)
-	
-	// Check if file exists
-	info, err := os.Stat(cleanPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// Config file doesn't exist - check if directory is writable for creating default
-			dir := filepath.Dir(cleanPath)
-			if dirInfo, dirErr := os.Stat(dir); dirErr != nil {
-				return fmt.Errorf("config file directory does not exist: %s", dir)
-			} else if !dirInfo.IsDir() {
-				return fmt.Errorf("config file directory path is not a directory: %s", dir)
-			}
-			// Directory exists, we can create config file later if needed
-			return nil
-		}
-		return fmt.Errorf("cannot access config file: %w", err)
-	}
-
-	// Config file exists - check if it's readable
-	if info.IsDir() {
-		return fmt.Errorf("config file path is a directory, not a file: %s", cleanPath)
-	}
+const version = constants.Version
 
-	file, err := os.Open(cleanPath)
-	if err != nil {
-		return fmt.Errorf("config file is not readable: %w", err)
-	}
-	file.Close()
+// Exit codes - scripts can tell "re-auth fixes everything" apart from other failures.
+// -doctor exits with its own monitoring scale instead, see doctor.Status.ExitCode,
+// and runs with no show to process exit with processing.empty_run_exit_code.
+const (
+	exitFailure     = 1  // Setup error or at least one show failed
+	exitAuthFailure = 2  // Every failure was an auth failure; re-authenticating will fix them
+	exitOffline     = 4  // Finished offline: descriptions were queued for -retry-failed, not published
+	exitLocked      = 75 // Another instance holds the config's lock file (EX_TEMPFAIL: try again later)
+)
 
-	return nil
+// loadConfig loads the config file; with -strict-config unknown keys fail the load
+func loadConfig(path string) (*config.Config, error) {
+	return config.LoadConfigWithOptions(path, config.LoadOptions{StrictConfig: flags.StrictConfig})
 }
 
-
 // loadConfiguration loads and validates the configuration file with automatic OAuth if needed
 func loadConfiguration(configPath string) (*config.Config, error) {
 	log := logger.Get()
@@ -154,25 +63,25 @@ func loadConfiguration(configPath string) (*config.Config, error) {
 				return nil, fmt.Errorf("failed to create default config file: %w", err)
 			}
 			
-			fmt.Printf("Created config file: %s\n", cleanPath)
-			fmt.Printf("Please edit this file with your Mixcloud OAuth credentials, then run again.\n")
+			console.Printf("Created config file: %s\n", cleanPath)
+			console.Printf("Please edit this file with your Mixcloud OAuth credentials, then run again.\n")
 			return nil, fmt.Errorf("configuration file created")
 		}
 		return nil, fmt.Errorf("cannot access config file: %w", err)
 	}
 	
 	// Load the configuration
-	cfg, err := config.LoadConfig(cleanPath)
+	cfg, err := loadConfig(cleanPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	for _, warning := range cfg.Warnings {
+		log.Warn("Configuration warning", slog.String("warning", warning))
+		console.Warnf("Warning: %s\n", warning)
+	}
 	
-	// Apply environment variable overrides
-	cfg.ApplyEnvironmentOverrides()
-	log.Debug("Applied environment variable overrides")
-	
-	// Check if we need OAuth authorization
-	if needsAuthorization(cfg) {
+	// Check if we need OAuth authorization; offline runs never contact Mixcloud
+	if needsAuthorization(cfg) && !flags.Offline {
 		// Validate OAuth credentials are present
 		if cfg.OAuth.ClientID == "" || cfg.OAuth.ClientSecret == "" {
 			log.Error("OAuth credentials missing", 
@@ -187,7 +96,7 @@ func loadConfiguration(configPath string) (*config.Config, error) {
 		}
 		
 		log.Info("OAuth authorization required", slog.String("username", cfg.Station.MixcloudUsername))
-		fmt.Printf("🔑 OAuth authorization required - launching browser...\n")
+		console.Printf("🔑 OAuth authorization required - launching browser...\n")
 		
 		// Perform the OAuth flow
 		err = mixcloud.AuthorizeAndSave(cfg, cleanPath)
@@ -198,11 +107,10 @@ func loadConfiguration(configPath string) (*config.Config, error) {
 		
 		log.Info("OAuth authorization successful, reloading config")
 		// Reload the configuration with new tokens
-		cfg, err = config.LoadConfig(cleanPath)
+		cfg, err = loadConfig(cleanPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to reload config after authorization: %w", err)
 		}
-		cfg.ApplyEnvironmentOverrides()
 	}
 	
 	// Validate the final configuration
@@ -243,64 +151,102 @@ func needsAuthorization(cfg *config.Config) bool {
 
 
 
+// flags is the parsed command line
+var flags = &cli.Options{}
+
 func main() {
+	os.Exit(cli.Run(os.Args, os.Stdin, os.Stdout, os.Stderr, run))
+}
+
+// run carries out a parsed command line and returns the exit code
+func run(opts *cli.Options, stdin io.Reader, stdout, stderr io.Writer) (exitCode int) {
+	flags = opts
 	startTime := time.Now()
-	var exitCode int
-	var executionResults []string
+	summary := logger.ExecutionSummary{StartTime: startTime} // Completed by finishSummary on exit
 	var log *logger.Logger
+	var statusFile string              // processing.status_file, once a config has loaded
+	var lastRun *processor.BatchResult // The run's outcome, nil when it never started
+	var runErr error                   // Why the run stopped early or failed
 
 	// Ensure cleanup happens on exit
 	defer func() {
-		if log != nil {
-			// Log execution summary
-			mode := "Batch Processing"
-			if *showAlias != "" {
-				mode = fmt.Sprintf("Single Show (%s)", *showAlias)
+		finishSummary(&summary, exitCode, lastRun, runErr)
+		console.Outcome(summary.Headline())
+		// Written first so it fires on every exit path, including early failures
+		if statusFile != "" && recordsStatus() {
+			line := statusLine(summary)
+			if err := writeStatusFile(statusFile, line); err != nil {
+				logger.Get().Warn("Failed to write status file", slog.String("path", statusFile), slog.String("error", err.Error()))
+				console.Warnf("Warning: %v\n", err)
 			}
-			log.LogExecutionSummary(startTime, *configFile, mode, executionResults, exitCode)
+		}
+		if log != nil {
+			log.LogExecutionSummary(summary)
 			log.Close()
 		}
-		os.Exit(exitCode)
 	}()
 
-	flag.Parse()
-
-	// Handle help and version flags
-	if *help {
-		flag.Usage()
-		return
+	if flags.Quiet {
+		console.SetVerbosity(console.Quiet)
 	}
 
-	if *showVersion {
-		fmt.Printf("Mixcloud Updater v%s\n", version)
-		return
-	}
+	// A token or client secret on the command line must not reach the log,
+	// starting with the command line itself
+	flags.RegisterSecrets()
 
-	// Determine config file path - support single positional argument
-	configFilePath := *configFile
-	if flag.NArg() == 1 {
-		configFilePath = flag.Arg(0)
-	} else if flag.NArg() > 1 {
-		fmt.Fprintf(os.Stderr, "Error: Too many arguments. Expected at most one config file path.\n\n")
-		flag.Usage()
+	configFilePath := flags.ConfigPath
+	summary.ConfigFile = configFilePath
+
+	// Progress events - must be set up before logging captures stdout
+	progressOut, closeProgress, err := openProgressOutput(flags.ProgressJSON, flags.ProgressFile)
+	if err != nil {
+		console.Errorf("Error: %v\n", err)
 		exitCode = 1
 		return
 	}
+	defer closeProgress()
+	// The command's own output; -script and -json keep stdout for it alone
+	var dataOut io.Writer = stdout
+	if flags.Script || flags.JSON && (flags.Doctor || flags.ShowHistory != "") {
+		dataOut = openJSONOutput()
+	}
+
+	// Repair word-processor damage before anything reads the config
+	if flags.FixConfig {
+		if err := runFixConfig(configFilePath, dataOut); err != nil {
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+	}
 
 	// Load configuration to get logging settings
 	// Initial load for logging setup - errors go to stderr
-	initialCfg, err := config.LoadConfig(configFilePath)
+	initialCfg, err := loadConfig(configFilePath)
 	if err == nil {
+		// -quiet wins over console_verbosity; an invalid value is reported by validation
+		if flags.Quiet {
+			initialCfg.Logging.ConsoleVerbosity = string(console.Quiet)
+		} else if verbosity, err := console.ParseVerbosity(initialCfg.Logging.ConsoleVerbosity); err == nil {
+			console.SetVerbosity(verbosity)
+		}
 		// Initialize logging system
 		if logErr := logger.Initialize(initialCfg.Logging); logErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to initialize logging: %v\n", logErr)
+			console.Warnf("Warning: Failed to initialize logging: %v\n", logErr)
 		}
 		log = logger.Get()
+		statusFile = initialCfg.StatusFilePath(configFilePath)
 	} else {
+		// Buffered until Initialize so the failure still reaches the log file
+		logger.Get().Warn("Initial config load failed, using default logging settings",
+			slog.String("config_file", configFilePath),
+			slog.String("error", err.Error()))
+
 		// If config doesn't exist yet, use default logging config
 		defaultCfg := config.DefaultConfig()
+		defaultCfg.Logging.ConsoleVerbosity = string(console.Current())
 		if logErr := logger.Initialize(defaultCfg.Logging); logErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to initialize logging: %v\n", logErr)
+			console.Warnf("Warning: Failed to initialize logging: %v\n", logErr)
 		}
 		log = logger.Get()
 	}
@@ -309,49 +255,272 @@ func main() {
 	log.Info("Mixcloud Updater started", 
 		slog.String("version", version),
 		slog.String("config_file", configFilePath),
-		slog.String("command", strings.Join(os.Args, " ")))
+		slog.String("command", strings.Join(logger.RedactArgs(os.Args), " ")))
 
 	// Print banner (keep console output for user experience)
-	fmt.Printf("Mixcloud Updater v%s\n", version)
-	fmt.Printf("=================================\n\n")
+	console.Printf("Mixcloud Updater v%s\n", version)
+	console.Printf("=================================\n\n")
+
+	// Handle starter config creation - runs before the config file is required to exist
+	if flags.InitConfig {
+		log.Info("Creating starter config", slog.String("path", configFilePath), slog.Bool("prompt", !flags.NoPrompt))
+		if err := runInit(configFilePath, stdin, stdout); err != nil {
+			log.Error("Starter config creation failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+		summary.Detail = "created starter config " + configFilePath
+		return
+	}
+
+	// Handle update check - works without a valid config, which only adds the state record
+	if flags.CheckUpdate {
+		log.Info("Checking for updates", slog.String("current", version))
+		if err := runCheckUpdate(initialCfg, configFilePath, stdout); err != nil {
+			log.Warn("Update check failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+		}
+		return
+	}
+
+	// List environment overrides - works without a valid config, which only adds show entries
+	if flags.PrintEnvVars {
+		if err := runPrintEnvVars(configFilePath, dataOut); err != nil {
+			log.Error("Listing environment variables failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+		}
+		return
+	}
+
+	// Print the merged config - offline, and never starts the OAuth flow
+	if flags.PrintEffectiveConfig {
+		if err := runPrintEffectiveConfig(configFilePath, dataOut); err != nil {
+			log.Error("Printing effective config failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+		}
+		return
+	}
+
+	// Handle health checks - they report a broken or missing config rather than
+	// failing on it, and never start the OAuth flow
+	if flags.Doctor {
+		log.Info("Running health checks", slog.String("path", configFilePath), slog.Bool("json", flags.JSON))
+		report, err := runDoctor(configFilePath, flags.JSON, dataOut)
+		if err != nil {
+			log.Error("Health checks failed to run", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = exitFailure
+			return
+		}
+		log.Info("Health checks completed", slog.String("status", string(report.Status)), slog.Int("checks", len(report.Checks)))
+		summary.Detail = "health checks: " + string(report.Status)
+		exitCode = report.Status.ExitCode()
+		return
+	}
 
 	// Validate arguments
-	if err := validateArguments(configFilePath); err != nil {
+	if err := cli.Validate(flags); err != nil {
+		runErr = err
 		log.Error("Argument validation failed", slog.String("error", err.Error()))
-		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
-		flag.Usage()
+		console.Errorf("Error: %v\n\n", err)
+		cli.Usage(stderr, flags)
 		exitCode = 1
 		return
 	}
 
+	// Handle config linting - skips loadConfiguration so no OAuth flow is triggered
+	if flags.Lint {
+		log.Info("Linting configuration", slog.String("path", configFilePath))
+		count, err := runLint(configFilePath, dataOut)
+		if err != nil {
+			log.Error("Config lint failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+		log.Info("Config lint completed", slog.Int("findings", count))
+		return
+	}
+
+	// Handle CUE file ownership lookup - also offline
+	if flags.WhichShow != "" {
+		log.Info("Looking up shows for CUE file", slog.String("file", flags.WhichShow))
+		count, err := runWhichShow(configFilePath, flags.WhichShow, dataOut)
+		if err != nil {
+			log.Error("CUE file lookup failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+		log.Info("CUE file lookup completed", slog.Int("matches", count))
+		return
+	}
+
+	// Handle publish history - also offline
+	if flags.ShowHistory != "" {
+		log.Info("Printing publish history", slog.String("show", flags.ShowHistory), slog.Int("n", flags.HistoryCount))
+		count, err := runHistory(configFilePath, flags.ShowHistory, flags.HistoryCount, flags.JSON, dataOut)
+		if err != nil {
+			log.Error("Publish history failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+		log.Info("Publish history printed", slog.Int("entries", count))
+		return
+	}
+
+	// Handle filter rule testing - also offline
+	if flags.TestFilter {
+		log.Info("Testing filter rules",
+			slog.String("artist", flags.FilterArtist),
+			slog.String("title", flags.FilterTitle),
+			slog.String("csv", flags.FilterCSV))
+		out := dataOut
+		if flags.OutputFile != "" {
+			file, err := os.Create(flags.OutputFile)
+			if err != nil {
+				log.Error("Failed to create output file", slog.String("path", flags.OutputFile), slog.String("error", err.Error()))
+				console.Errorf("Error creating output file: %v\n", err)
+				exitCode = 1
+				return
+			}
+			defer file.Close()
+			out = file
+		}
+		excluded, err := runTestFilter(configFilePath, testFilterOptions{
+			Show:    flags.ShowAlias,
+			Artist:  flags.FilterArtist,
+			Title:   flags.FilterTitle,
+			Genre:   flags.FilterGenre,
+			CSVPath: flags.FilterCSV,
+		}, out)
+		if err != nil {
+			log.Error("Filter test failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+		log.Info("Filter test completed", slog.Int("excluded", excluded))
+		return
+	}
+
+	// Handle template golden-file tests - also offline
+	if flags.TestTemplates {
+		log.Info("Running template tests", slog.String("path", configFilePath), slog.Bool("update_golden", flags.UpdateGolden))
+		failed, err := runTemplateTests(configFilePath, flags.UpdateGolden, dataOut)
+		if err != nil {
+			log.Error("Template tests could not run", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+		if failed > 0 {
+			log.Error("Template tests failed", slog.Int("failed", failed))
+			summary.Detail = fmt.Sprintf("%d template tests failed", failed)
+			exitCode = 1
+			return
+		}
+		return
+	}
+
+	// Everything from here on can refresh tokens or write the config and state
+	// files, so only one instance may run against a config at a time
+	runLock, err := acquireRunLock(initialCfg, configFilePath, time.Duration(flags.WaitLock)*time.Second)
+	if err != nil {
+		var held *runlock.HeldError
+		if errors.As(err, &held) {
+			log.Warn("Another instance is running, exiting",
+				slog.Int("pid", held.Holder.PID),
+				slog.Time("started_at", held.Holder.StartedAt),
+				slog.String("lock_file", held.Path))
+			console.Errorf("Error: %v; exiting\n", err)
+			summary.Status = logger.RunLocked
+			summary.Detail = fmt.Sprintf("another instance is running (PID %d)", held.Holder.PID)
+			exitCode = exitLocked
+			return
+		}
+		runErr = err
+		log.Error("Failed to acquire lock file", slog.String("error", err.Error()))
+		console.Errorf("Error: %v\n", err)
+		exitCode = exitFailure
+		return
+	}
+	defer func() {
+		if err := runLock.Release(); err != nil {
+			log.Warn("Failed to release lock file", slog.String("path", runLock.Path()), slog.String("error", err.Error()))
+		}
+	}()
+
+	// Handle credential migration - holds the lock since it rewrites the config
+	if flags.MigrateCredentials {
+		log.Info("Migrating OAuth credentials", slog.String("path", configFilePath))
+		if err := runMigrateCredentials(configFilePath, dataOut); err != nil {
+			runErr = err
+			log.Error("Credential migration failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+		}
+		return
+	}
+
+	// Handle token provisioning - holds the lock since it rewrites the config
+	if flags.SetToken != "" {
+		log.Info("Setting access token", slog.String("path", configFilePath), slog.Bool("stdin", flags.SetToken == "-"))
+		if err := runSetToken(configFilePath, flags.SetToken, stdin, dataOut); err != nil {
+			runErr = err
+			log.Error("Setting access token failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+		}
+		return
+	}
+
 	// Load configuration
-	fmt.Printf("Loading configuration: %s\n", configFilePath)
+	console.Printf("Loading configuration: %s\n", configFilePath)
 	log.Info("Loading configuration", slog.String("path", configFilePath))
 	cfg, err := loadConfiguration(configFilePath)
 	if err != nil {
+		runErr = err
 		log.Error("Configuration loading failed", slog.String("error", err.Error()))
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		console.Errorf("Error: %v\n", err)
 		exitCode = 1
 		return
 	}
+	statusFile = cfg.StatusFilePath(configFilePath)
 
 	// Handle list operations
-	if *listShows {
+	if flags.ListShows {
 		log.Info("Listing available shows")
-		if err := listAvailableShows(cfg); err != nil {
+		if err := listAvailableShows(cfg, configFilePath, flags.Resolve, dataOut); err != nil {
 			log.Error("Failed to list shows", slog.String("error", err.Error()))
-			fmt.Fprintf(os.Stderr, "Error listing shows: %v\n", err)
+			console.Errorf("Error listing shows: %v\n", err)
 			exitCode = 1
 			return
 		}
 		return
 	}
 
-	if *listTemplates {
+	if flags.ShowStatus {
+		log.Info("Showing publish status")
+		if err := printShowStatus(cfg, configFilePath, dataOut); err != nil {
+			log.Error("Failed to show status", slog.String("error", err.Error()))
+			console.Errorf("Error showing status: %v\n", err)
+			exitCode = 1
+			return
+		}
+		return
+	}
+
+	if flags.ListTemplates {
 		log.Info("Listing available templates")
-		if err := listAvailableTemplates(cfg); err != nil {
+		if err := listAvailableTemplates(cfg, dataOut); err != nil {
 			log.Error("Failed to list templates", slog.String("error", err.Error()))
-			fmt.Fprintf(os.Stderr, "Error listing templates: %v\n", err)
+			console.Errorf("Error listing templates: %v\n", err)
 			exitCode = 1
 			return
 		}
@@ -362,77 +531,302 @@ func main() {
 	log.Info("Initializing show processor")
 	showProcessor, err := processor.NewShowProcessor(cfg, configFilePath)
 	if err != nil {
+		runErr = err
 		log.Error("Failed to initialize processor", slog.String("error", err.Error()))
-		fmt.Fprintf(os.Stderr, "Error initializing processor: %v\n", err)
+		console.Errorf("Error initializing processor: %v\n", err)
 		exitCode = 1
 		return
 	}
+	defer func() { lastRun = showProcessor.LastRun() }()
+	processorOptions := processor.Options{
+		Force:          flags.Force,
+		VerbosePreview: flags.VerbosePreview,
+		Limit:          flags.ShowLimit,
+		NoCache:        flags.NoCache,
+		StrictCue:      flags.StrictCue,
+		Verify:         flags.Verify,
+		Confirm:        flags.Confirm,
+		Offline:        flags.Offline,
+		TimeOffset:     flags.TimeOffset,
+		Resume:         flags.Resume,
+		NoResume:       flags.NoResume,
+		Digest:         flags.DigestPath,
+	}
+	// -1, the flags' default, leaves processing's thresholds in place
+	if flags.MaxConsecutiveFailures >= 0 {
+		processorOptions.MaxConsecutiveFailures = &flags.MaxConsecutiveFailures
+	}
+	if flags.MaxTotalFailures >= 0 {
+		processorOptions.MaxTotalFailures = &flags.MaxTotalFailures
+	}
+	if flags.OutputFile != "" {
+		previewFile, err := os.Create(flags.OutputFile)
+		if err != nil {
+			runErr = err
+			log.Error("Failed to create output file", slog.String("path", flags.OutputFile), slog.String("error", err.Error()))
+			console.Errorf("Error creating output file: %v\n", err)
+			exitCode = 1
+			return
+		}
+		defer previewFile.Close()
+		processorOptions.PreviewOutput = previewFile
+	}
+	if flags.Script && flags.DryRun && flags.ShowAlias != "" {
+		processorOptions.DescriptionOutput = dataOut
+	}
+	if flags.CompareTemplates != "" {
+		names, _ := cli.ParseCompareTemplates(flags.CompareTemplates) // Checked by validateArguments
+		err := checkCompareTemplates(cfg, names)
+		if err == nil && flags.CompareOutput != "" {
+			err = prepareCompareOutput(flags.CompareOutput)
+		}
+		if err != nil {
+			runErr = err
+			log.Error("Template comparison setup failed", slog.String("error", err.Error()))
+			console.Errorf("Error: %v\n", err)
+			exitCode = 1
+			return
+		}
+		processorOptions.CompareTemplates = names
+		processorOptions.CompareOutput = flags.CompareOutput
+	}
+	showProcessor.SetOptions(processorOptions)
+	if cfg.Processing.AutoReauth == config.AutoReauthPrompt {
+		if isInteractive() {
+			showProcessor.SetReauthorizer(func() (*oauth2.Token, error) {
+				return mixcloud.AuthorizeToken(cfg, configFilePath)
+			})
+			defer func() {
+				if pause := showProcessor.ReauthPause(); pause > 0 {
+					summary.ReauthPauseMS = pause.Milliseconds()
+				}
+			}()
+		} else {
+			log.Info("auto_reauth = \"prompt\" ignored: no terminal attached, auth failures fail fast")
+		}
+	}
+	if progressOut != nil {
+		progressWriter := processor.NewJSONProgressWriter(progressOut)
+		showProcessor.SetProgressObserver(progressWriter)
+		defer func() {
+			if err := progressWriter.Err(); err != nil {
+				log.Warn("Failed to write progress events", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	// Overlaps the run; results are recorded once processing is done
+	if !flags.Offline {
+		defer startUpdateCheck(cfg, configFilePath, console.Chrome)()
+	}
+
+	// Execute processing based on arguments, in the mode the summary reports
+	mode, target := runMode()
+	if mode == processor.RunModeRetry {
+		// Publish the updates queued by offline runs
+		log.Info("Publishing pending updates", slog.Bool("dry_run", flags.DryRun))
 
-	// Execute processing based on arguments
-	if *showAlias != "" {
+		if err := showProcessor.ProcessPending(flags.DryRun); processor.IsEmptyRun(err) {
+			summary.Status = logger.RunNoShowsProcessed
+			summary.Detail = err.Error()
+			exitCode = cfg.Processing.EmptyRunExitCode
+			return
+		} else if err != nil {
+			log.Error("Publishing pending updates failed", slog.String("error", err.Error()))
+			console.Errorf("Error publishing pending updates: %v\n", err)
+			handleAuthError(err, showProcessor.TokenCheck())
+			runErr = err
+			exitCode = exitCodeForError(err)
+			return
+		}
+	} else if mode == processor.RunModeSingle || mode == processor.RunModeDates {
 		// Process specific show
-		log.Info("Processing single show", 
-			slog.String("show", *showAlias),
-			slog.String("template", *templateName),
-			slog.String("date_override", *dateOverride),
-			slog.Bool("dry_run", *dryRun))
-		
-		if err := showProcessor.ProcessShow(*showAlias, *templateName, *dateOverride, *dryRun); err != nil {
-			log.Error("Show processing failed", 
-				slog.String("show", *showAlias),
+		log.Info("Processing single show",
+			slog.String("show", flags.ShowAlias),
+			slog.String("template", flags.TemplateName),
+			slog.String("date_override", flags.DateOverride),
+			slog.Bool("dry_run", flags.DryRun))
+
+		if err := processShow(showProcessor); processor.IsEmptyRun(err) {
+			summary.Status = logger.RunNoShowsProcessed
+			summary.Detail = err.Error()
+			exitCode = cfg.Processing.EmptyRunExitCode
+			return
+		} else if processor.IsOfflineRun(err) {
+			summary.Status = logger.RunOffline
+			summary.Detail = err.Error()
+			exitCode = reportOfflineRun(err)
+			return
+		} else if err != nil {
+			log.Error("Show processing failed",
+				slog.String("show", flags.ShowAlias),
 				slog.String("error", err.Error()))
-			executionResults = append(executionResults, fmt.Sprintf("%s: FAILED - %v", *showAlias, err))
-			fmt.Fprintf(os.Stderr, "Error processing show: %v\n", err)
-			handleAuthError(err)
-			exitCode = 1
+			console.Errorf("Error processing show: %v\n", err)
+			handleAuthError(err, showProcessor.TokenCheck())
+			runErr = err
+			exitCode = exitCodeForError(err)
+			return
+		}
+	} else if mode == processor.RunModeBackfill {
+		// Publish a show's archived episodes
+		backfillOptions := processor.BackfillOptions{
+			Dir:   flags.BackfillDir,
+			Limit: flags.BackfillLimit,
+		}
+		if flags.BackfillSince != "" {
+			// Already validated in validateArguments
+			backfillOptions.Since, _ = dateutil.ParseFlexibleDate(flags.BackfillSince)
+		}
+		log.Info("Backfilling show",
+			slog.String("show", target),
+			slog.String("dir", flags.BackfillDir),
+			slog.Int("limit", flags.BackfillLimit),
+			slog.String("since", flags.BackfillSince),
+			slog.Bool("dry_run", flags.DryRun))
+
+		if err := showProcessor.ProcessBackfill(target, backfillOptions, flags.DryRun); err != nil {
+			log.Error("Backfill failed",
+				slog.String("show", target),
+				slog.String("error", err.Error()))
+			console.Errorf("Error backfilling show: %v\n", err)
+			handleAuthError(err, showProcessor.TokenCheck())
+			runErr = err
+			exitCode = exitCodeForError(err)
+			return
+		}
+	} else if mode == processor.RunModeGroup {
+		// Process a show group
+		log.Info("Processing show group",
+			slog.String("group", flags.ShowGroup),
+			slog.Bool("dry_run", flags.DryRun))
+
+		if err := showProcessor.ProcessGroup(flags.ShowGroup, flags.DryRun); err != nil {
+			log.Error("Group processing failed",
+				slog.String("group", flags.ShowGroup),
+				slog.String("error", err.Error()))
+			console.Errorf("Error processing group: %v\n", err)
+			handleAuthError(err, showProcessor.TokenCheck())
+			runErr = err
+			exitCode = exitCodeForError(err)
 			return
 		}
-		executionResults = append(executionResults, fmt.Sprintf("%s: SUCCESS", *showAlias))
 	} else {
 		// Process all enabled shows
-		log.Info("Processing all enabled shows", slog.Bool("dry_run", *dryRun))
-		
-		if err := showProcessor.ProcessAllShows(*dryRun); err != nil {
+		log.Info("Processing all enabled shows", slog.Bool("dry_run", flags.DryRun))
+
+		if err := showProcessor.ProcessAllShows(flags.DryRun); processor.IsEmptyRun(err) {
+			summary.Status = logger.RunNoShowsProcessed
+			summary.Detail = err.Error()
+			exitCode = cfg.Processing.EmptyRunExitCode
+			return
+		} else if processor.IsOfflineRun(err) {
+			summary.Status = logger.RunOffline
+			summary.Detail = err.Error()
+			exitCode = reportOfflineRun(err)
+			return
+		} else if err != nil {
 			log.Error("Batch processing failed", slog.String("error", err.Error()))
 			// The error message already contains the count of failed shows
-			executionResults = append(executionResults, fmt.Sprintf("Batch processing: %v", err))
-			fmt.Fprintf(os.Stderr, "Error processing shows: %v\n", err)
-			handleAuthError(err)
-			exitCode = 1
+			console.Errorf("Error processing shows: %v\n", err)
+			handleAuthError(err, showProcessor.TokenCheck())
+			runErr = err
+			exitCode = exitCodeForError(err)
 			return
 		}
-		executionResults = append(executionResults, "Batch processing: SUCCESS")
 	}
 
 	log.Info("Processing completed successfully")
-	fmt.Println("✓ Done!")
+	console.Println("✓ Done!")
+	return
+}
+
+// isInteractive reports whether stdin and stdout are terminals, i.e. someone can
+// complete the OAuth flow without the run hanging
+func isInteractive() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := f.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// reportOfflineRun tells the user how to publish what an offline run queued and
+// returns the exit code for it
+func reportOfflineRun(err error) int {
+	logger.Get().Warn("Run completed offline", slog.String("result", err.Error()))
+	console.Printf("📥 Run %v\n", err)
+	console.Printf("Publish the queued descriptions with -retry-failed once Mixcloud is reachable.\n")
+	return exitOffline
 }
 
-// handleAuthError provides helpful messages for authentication errors
-func handleAuthError(err error) {
+// exitCodeForError picks the process exit code for a processing failure
+func exitCodeForError(err error) int {
+	if processor.IsAuthFailure(err) {
+		return exitAuthFailure
+	}
+	return exitFailure
+}
+
+// handleAuthError provides helpful messages for authentication errors, telling
+// a revoked or expired token (check) apart from a transient failure
+func handleAuthError(err error, check processor.TokenCheck) {
+	if processor.IsAuthFailure(err) {
+		switch {
+		case check.Cleared:
+			console.Errorf("\nMixcloud rejected the access token (expired or revoked in your Mixcloud settings).\n")
+			console.Errorf("The stored token was cleared - run the command again to authorize.\n")
+		case check.Rejected:
+			console.Errorf("\nMixcloud rejected the access token (expired or revoked), but it couldn't be cleared: %v\n", check.Err)
+			console.Errorf("Remove oauth.access_token from the config, then run the command again to authorize.\n")
+		case check.Checked && check.Err == nil:
+			console.Errorf("\nMixcloud still accepts the access token, so the authentication failure looks transient.\n")
+			console.Errorf("Run the command again later; re-authorizing isn't needed.\n")
+		default:
+			console.Errorf("\nYour OAuth tokens have expired. Please run the command again to re-authenticate.\n")
+		}
+		return
+	}
+
 	errStr := err.Error()
 	if strings.Contains(errStr, "authentication") || strings.Contains(errStr, "unauthorized") || 
 	   strings.Contains(errStr, "token has expired") || strings.Contains(errStr, "OAuthException") {
-		fmt.Fprintf(os.Stderr, "\nYour OAuth tokens have expired. Please run the command again to re-authenticate.\n")
+		console.Errorf("\nYour OAuth tokens have expired. Please run the command again to re-authenticate.\n")
 	}
 }
 
 // listAvailableShows displays all configured shows and their aliases
-func listAvailableShows(cfg *config.Config) error {
+func listAvailableShows(cfg *config.Config, configPath string, resolve bool, out io.Writer) error {
 	resolver, err := shows.NewResolver(cfg)
 	if err != nil {
 		return fmt.Errorf("creating show resolver: %w", err)
 	}
+	runState := loadRunState(cfg, configPath)
+	now := time.Now()
+
+	// -resolve: what each enabled show's CUE file looks like right now
+	var statuses map[string]processor.ShowStatus
+	if resolve {
+		sp, err := processor.NewShowProcessor(cfg, configPath)
+		if err != nil {
+			return fmt.Errorf("creating show processor: %w", err)
+		}
+		statuses = make(map[string]processor.ShowStatus)
+		for _, status := range sp.ListShowStatuses() {
+			statuses[status.ShowKey] = status
+		}
+	}
 
 	allShows := resolver.ListShows()
 	enabledShows := resolver.ListEnabledShows(true) // sorted by priority
 
-	fmt.Printf("Configured Shows:\n")
-	fmt.Printf("================\n\n")
+	fmt.Fprintf(out, "Configured Shows:\n")
+	fmt.Fprintf(out, "================\n\n")
 
 	if len(allShows) == 0 {
-		fmt.Printf("No shows configured in config file.\n")
-		fmt.Printf("Add show configurations to the [shows] section.\n")
+		fmt.Fprintf(out, "No shows configured in config file.\n")
+		fmt.Fprintf(out, "Add show configurations to the [shows] section.\n")
 		return nil
 	}
 
@@ -447,69 +841,196 @@ func listAvailableShows(cfg *config.Config) error {
 			priority = fmt.Sprintf(" (priority: %d)", showCfg.Priority)
 		}
 
-		fmt.Printf("• %s [%s]%s\n", showKey, status, priority)
-		fmt.Printf("  Pattern: %s | %s\n", showCfg.ShowNamePattern, 
+		fmt.Fprintf(out, "• %s [%s]%s\n", cfg.ShowDisplayKey(showKey), status, priority)
+		fmt.Fprintf(out, "  Pattern: %s | %s\n", showCfg.ShowNamePattern, 
 			getSourceDescription(showCfg))
 		
 		if len(aliases) > 0 {
-			fmt.Printf("  Aliases: %s\n", strings.Join(aliases, ", "))
+			fmt.Fprintf(out, "  Aliases: %s\n", strings.Join(aliases, ", "))
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "  Last published: %s\n", describeLastPublished(runState, showKey, now))
+		if status, ok := statuses[showKey]; ok {
+			printShowResolution(out, status, cfg.Shows[showKey].ExpectedIntervalDays, now)
+		}
+		fmt.Fprintf(out, "\n")
 	}
 
 	if len(enabledShows) > 0 {
-		fmt.Printf("Processing Order (enabled shows by priority):\n")
+		fmt.Fprintf(out, "Processing Order (enabled shows by priority):\n")
 		for i, showKey := range enabledShows {
-			fmt.Printf("%d. %s\n", i+1, showKey)
+			fmt.Fprintf(out, "%d. %s\n", i+1, cfg.ShowDisplayKey(showKey))
 		}
 	}
 
 	return nil
 }
 
-// listAvailableTemplates displays all configured templates
-func listAvailableTemplates(cfg *config.Config) error {
-	fmt.Printf("Available Templates:\n")
-	fmt.Printf("===================\n\n")
+// printShowResolution prints what -list-shows -resolve found for a show: the
+// CUE file, its sheet and track counts, or where resolving it failed
+func printShowResolution(out io.Writer, status processor.ShowStatus, expectedIntervalDays int, now time.Time) {
+	if status.CueFile == "" {
+		fmt.Fprintf(out, "  CUE file: ❌ %v\n", status.Error)
+		return
+	}
+	fmt.Fprintf(out, "  CUE file: %s", filepath.Base(status.CueFile))
+	if !status.Modified.IsZero() {
+		fmt.Fprintf(out, " (modified %s, %d bytes)", state.FormatRelative(status.Modified, now), status.Size)
+	}
+	fmt.Fprintf(out, "\n")
+	if status.Stale {
+		fmt.Fprintf(out, "  ⚠️  Older than expected_interval_days (%d): check that Myriad still writes CUE files for this show\n", expectedIntervalDays)
+	}
+	if status.Sidecar != "" {
+		fmt.Fprintf(out, "  Sidecar: %s\n", filepath.Base(status.Sidecar))
+	}
+	if status.Error != nil {
+		fmt.Fprintf(out, "  ❌ %v\n", status.Error)
+		return
+	}
+	fmt.Fprintf(out, "  Sheet: TITLE %q, PERFORMER %q, %d audio file(s)\n", status.Title, status.Performer, status.AudioFiles)
+	tracks := fmt.Sprintf("  Tracks: %d parsed, %d after filters", status.ParsedTracks, status.FilteredTracks)
+	if status.TrackWarnings > 0 {
+		tracks += fmt.Sprintf(", %d malformed skipped", status.TrackWarnings)
+	}
+	fmt.Fprintf(out, "%s\n", tracks)
+}
+
+// printShowStatus displays last publish info for enabled shows, flagging any that
+// are overdue according to their expected_interval_days
+func printShowStatus(cfg *config.Config, configPath string, out io.Writer) error {
+	resolver, err := shows.NewResolver(cfg)
+	if err != nil {
+		return fmt.Errorf("creating show resolver: %w", err)
+	}
+	runState := loadRunState(cfg, configPath)
+	now := time.Now()
+
+	enabledShows := resolver.ListEnabledShows(true)
 
-	if len(cfg.Templates.Config) == 0 {
-		fmt.Printf("No templates configured in config file.\n")
-		fmt.Printf("Add template configurations to the [templates.config] section.\n")
+	fmt.Fprintf(out, "Show Status:\n")
+	fmt.Fprintf(out, "============\n\n")
+
+	if len(enabledShows) == 0 {
+		fmt.Fprintf(out, "No enabled shows found in configuration.\n")
 		return nil
 	}
 
+	staleCount := 0
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "SHOW\tLAST PUBLISHED\tTRACKS\tSTATUS\n")
+	for _, showKey := range enabledShows {
+		showCfg := cfg.Shows[showKey]
+		showState, published := runState.Show(showKey)
+
+		tracks := "-"
+		if published {
+			tracks = fmt.Sprintf("%d", showState.TrackCount)
+		}
+
+		status := "ok"
+		if state.IsStale(showState.LastPublished, showCfg.ExpectedIntervalDays, now) {
+			status = fmt.Sprintf("⚠️  STALE (expected every %d days)", showCfg.ExpectedIntervalDays)
+			staleCount++
+		} else if showCfg.ExpectedIntervalDays <= 0 {
+			status = "no expected_interval_days"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", cfg.ShowDisplayKey(showKey), describeLastPublished(runState, showKey, now), tracks, status)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nState file: %s\n", runState.Path())
+	if staleCount > 0 {
+		fmt.Fprintf(out, "%d show(s) overdue\n", staleCount)
+	}
+	return nil
+}
+
+// loadRunState loads the state file, warning (not failing) if it can't be read
+func loadRunState(cfg *config.Config, configPath string) *state.State {
+	runState, err := state.Load(state.ResolvePath(cfg.Processing.StateFile, configPath))
+	if err != nil {
+		console.Warnf("Warning: %v\n", err)
+	}
+	return runState
+}
+
+// describeLastPublished formats a show's last publish as "3d ago (2025-06-28 14:03)" or "never"
+func describeLastPublished(runState *state.State, showKey string, now time.Time) string {
+	showState, ok := runState.Show(showKey)
+	if !ok || showState.LastPublished.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%s)", state.FormatRelative(showState.LastPublished, now),
+		showState.LastPublished.Local().Format("2006-01-02 15:04"))
+}
+
+// listAvailableTemplates displays the configured and built-in templates
+func listAvailableTemplates(cfg *config.Config, out io.Writer) error {
+	fmt.Fprintf(out, "Available Templates:\n")
+	fmt.Fprintf(out, "===================\n\n")
+
 	defaultTemplate := cfg.Templates.Default
 	if defaultTemplate == "" {
 		defaultTemplate = "classic"
 	}
 
-	for name, templateCfg := range cfg.Templates.Config {
-		isDefault := ""
+	// Configured templates, then the built-in ones they don't replace
+	names := make([]string, 0, len(cfg.Templates.Config))
+	for name := range cfg.Templates.Config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range template.BuiltinTemplateNames() {
+		if _, configured := cfg.Templates.Config[name]; !configured {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		templateCfg, configured := cfg.Templates.Config[name]
+		labels := ""
+		switch {
+		case !configured:
+			labels = " (built-in)"
+			templateCfg, _ = template.BuiltinTemplate(name)
+		case template.IsBuiltinTemplate(name):
+			labels = " (replaces built-in)"
+		}
 		if name == defaultTemplate {
-			isDefault = " (default)"
+			labels += " (default)"
+		}
+
+		fmt.Fprintf(out, "• %s%s\n", name, labels)
+
+		if name == template.CompactTemplateName && !configured {
+			fmt.Fprintf(out, "  Structure: Single line\n")
+			fmt.Fprintf(out, "  Track format: Artist – Title · Artist – Title\n\n")
+			continue
 		}
 
-		fmt.Printf("• %s%s\n", name, isDefault)
-		
 		hasHeader := templateCfg.Header != ""
 		hasFooter := templateCfg.Footer != ""
 		
-		fmt.Printf("  Structure: ")
+		fmt.Fprintf(out, "  Structure: ")
 		if hasHeader {
-			fmt.Printf("Header + ")
+			fmt.Fprintf(out, "Header + ")
+		}
+		if templateCfg.HourHeader != "" {
+			fmt.Fprintf(out, "Hour header + ")
 		}
-		fmt.Printf("Track")
+		fmt.Fprintf(out, "Track")
 		if hasFooter {
-			fmt.Printf(" + Footer")
+			fmt.Fprintf(out, " + Footer")
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
 		
-		fmt.Printf("  Track format: %s\n", 
+		fmt.Fprintf(out, "  Track format: %s\n", 
 			truncateForDisplay(templateCfg.Track, 60))
-		fmt.Printf("\n")
+		fmt.Fprintf(out, "\n")
 	}
 
-	fmt.Printf("Default template: %s\n", defaultTemplate)
+	fmt.Fprintf(out, "Default template: %s\n", defaultTemplate)
 	return nil
 }
 
@@ -519,15 +1040,22 @@ func getSourceDescription(showCfg config.ShowConfig) string {
 		return fmt.Sprintf("file: %s", showCfg.CueFileMapping)
 	}
 	if showCfg.CueFilePattern != "" {
-		return fmt.Sprintf("pattern: %s", showCfg.CueFilePattern)
+		source := fmt.Sprintf("pattern: %s", showCfg.CueFilePattern)
+		if showCfg.CueFileWeekday != "" {
+			source += fmt.Sprintf(", %s only", showCfg.CueFileWeekday)
+		}
+		if showCfg.CueFileIndex > 0 {
+			source += fmt.Sprintf(", index %d", showCfg.CueFileIndex)
+		}
+		return source
 	}
 	return "no source configured"
 }
 
-// truncateForDisplay truncates a string for display purposes
+// truncateForDisplay truncates a string for display purposes to at most maxLen characters
 func truncateForDisplay(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if utf8.RuneCountInString(s) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return textcut.Prefix(s, maxLen-3) + "..."
 }
\ No newline at end of file
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/credstore"
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// runMigrateCredentials moves the OAuth client secret and tokens from the
+// config file into the OS credential store, switches the config to
+// credential_store = "keychain" and blanks the secrets in the file
+// (-migrate-credentials)
+// AIDEV-NOTE: The secrets are read back from the store before the file is
+// rewritten, so a store that silently drops writes can't lose them.
+func runMigrateCredentials(configPath string, out io.Writer) error {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := credstore.Available(configPath); err != nil {
+		return err
+	}
+
+	secrets := credstore.Secrets{
+		ClientSecret: cfg.OAuth.ClientSecret,
+		AccessToken:  cfg.OAuth.AccessToken,
+		RefreshToken: cfg.OAuth.RefreshToken,
+	}
+	if secrets == (credstore.Secrets{}) {
+		return fmt.Errorf("no OAuth secrets in %s to migrate", configPath)
+	}
+
+	if err := credstore.Save(configPath, secrets); err != nil {
+		return err
+	}
+	stored, err := credstore.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if stored != secrets {
+		return fmt.Errorf("OS credential store did not keep the secrets; %s left unchanged", configPath)
+	}
+
+	cfg.OAuth.CredentialStore = credstore.StoreKeychain
+	if err := config.SaveConfig(cfg, configPath); err != nil {
+		return fmt.Errorf("updating config: %w", err)
+	}
+
+	logger.Get().Info("Migrated OAuth credentials to the OS credential store",
+		slog.String("path", configPath),
+		slog.String("service", credstore.ServiceName(configPath)))
+	fmt.Fprintf(out, "Moved the OAuth client secret and tokens of %s to the OS credential store\n", configPath)
+	fmt.Fprintf(out, "Stored under service %q; the config now has credential_store = \"keychain\"\n", credstore.ServiceName(configPath))
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// openProgressOutput returns where -progress-json events are written, or nil
+// when progress events are off. -progress-file selects a file or named pipe
+// (opening a pipe blocks until a reader connects). Events on stdout move all
+// human output to stderr so stdout carries nothing but JSON; call this before
+// the logger is initialized so console logging follows.
+func openProgressOutput(toStdout bool, path string) (io.Writer, func() error, error) {
+	noop := func() error { return nil }
+
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, noop, fmt.Errorf("opening progress file: %w", err)
+		}
+		return file, file.Close, nil
+	}
+
+	if !toStdout {
+		return nil, noop, nil
+	}
+	// AIDEV-NOTE: fmt.Printf and the console log handler resolve os.Stdout when
+	// called/created, so swapping it redirects every existing console write
+	progressOut := os.Stdout
+	os.Stdout = os.Stderr
+	return progressOut, noop, nil
+}
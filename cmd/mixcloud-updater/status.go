@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// AIDEV-NOTE: The status file is for schedulers and monitoring scripts that
+// can't see the exit code (Windows Task Scheduler only shows it in its own UI).
+// Its first word follows the exit code exactly - SUCCESS for 0, FAILURE for
+// anything else - so an empty run with empty_run_exit_code = 0 reads SUCCESS
+// and an offline run (exit 4) reads FAILURE.
+
+// recordsStatus reports whether this invocation writes processing.status_file:
+// runs that publish do, dry runs and informational commands don't, so checking
+// a config by hand doesn't overwrite the scheduled run's outcome
+func recordsStatus() bool {
+	informational := []bool{
+		flags.Help, flags.ShowVersion, flags.InitConfig, flags.CheckUpdate, flags.PrintEnvVars, flags.Doctor,
+		flags.Lint, flags.WhichShow != "", flags.ShowHistory != "", flags.TestFilter, flags.TestTemplates,
+		flags.ListShows, flags.ShowStatus, flags.ListTemplates, flags.DryRun, flags.MigrateCredentials,
+	}
+	for _, set := range informational {
+		if set {
+			return false
+		}
+	}
+	return true
+}
+
+// statusLine formats the one-line status of a finished run, e.g.
+// "SUCCESS 2025-06-28T22:14:03Z 12/12 shows" or
+// "FAILURE 2025-06-28T22:14:03Z 3 failed: nnw,sl,vault"
+func statusLine(summary logger.ExecutionSummary) string {
+	word := "SUCCESS"
+	if summary.ExitCode != 0 {
+		word = "FAILURE"
+	}
+	return fmt.Sprintf("%s %s %s", word, summary.EndTime.UTC().Format(time.RFC3339), statusDetail(summary))
+}
+
+// statusDetail describes the run's outcome after the status word and time
+func statusDetail(summary logger.ExecutionSummary) string {
+	switch {
+	case summary.Status == logger.RunLocked:
+		return "locked: another instance is running"
+	case summary.Status == logger.RunNoShowsProcessed:
+		return "no shows processed: " + firstLine(summary.Detail)
+	case summary.Status == logger.RunOffline && summary.Totals != nil:
+		return fmt.Sprintf("offline: %d queued for -retry-failed", summary.Totals.Queued)
+	}
+
+	if failed := summary.FailedShowKeys(); len(failed) > 0 {
+		kind := "failed"
+		if summary.ExitCode == exitAuthFailure {
+			kind = "failed (auth)"
+		}
+		return fmt.Sprintf("%d %s: %s", len(failed), kind, strings.Join(failed, ","))
+	}
+	if summary.Error != "" {
+		return "error: " + firstLine(summary.Error)
+	}
+	if summary.Totals == nil {
+		return fmt.Sprintf("exit %d", summary.ExitCode)
+	}
+
+	detail := fmt.Sprintf("%d/%d shows", summary.Totals.Successful, summary.Totals.Total)
+	if skipped := summary.Totals.Skipped + summary.Totals.NotAttempted; skipped > 0 {
+		detail += fmt.Sprintf(" (%d skipped)", skipped)
+	}
+	return detail
+}
+
+// firstLine returns s up to its first newline, keeping the status to one line
+func firstLine(s string) string {
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// writeStatusFile replaces the status file at path with line, writing a
+// temporary file first so a reader never sees it half-written
+func writeStatusFile(path, line string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating status file directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(line+"\n"), 0644); err != nil {
+		return fmt.Errorf("writing status file %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("replacing status file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cli"
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/processor"
+)
+
+// runModeCommand is the ExecutionSummary mode of invocations that run a
+// command rather than process shows, e.g. -doctor
+const runModeCommand = "command"
+
+// runMode returns the ExecutionSummary mode and target of this invocation, e.g.
+// "single" and the show alias. run dispatches on it as well, so the summary
+// reports the mode that actually ran.
+func runMode() (mode, target string) {
+	commands := []struct {
+		set  bool
+		name string
+	}{
+		{flags.InitConfig, "init"}, {flags.CheckUpdate, "check-update"}, {flags.PrintEnvVars, "print-env-vars"},
+		{flags.Doctor, "doctor"}, {flags.Lint, "lint"}, {flags.WhichShow != "", "which-show"},
+		{flags.ShowHistory != "", "history"}, {flags.TestFilter, "test-filter"}, {flags.TestTemplates, "test-templates"},
+		{flags.ListShows, "list-shows"}, {flags.ShowStatus, "status"}, {flags.ListTemplates, "list-templates"},
+		{flags.MigrateCredentials, "migrate-credentials"}, {flags.SetToken != "", "set-token"}, {flags.FixConfig, "fix-config"},
+	}
+	for _, command := range commands {
+		if command.set {
+			return runModeCommand, command.name
+		}
+	}
+
+	switch {
+	case flags.RetryFailed:
+		return processor.RunModeRetry, ""
+	case flags.ShowAlias != "" && len(cli.DateList(flags.DateOverride)) > 1:
+		return processor.RunModeDates, flags.ShowAlias
+	case flags.ShowAlias != "":
+		return processor.RunModeSingle, flags.ShowAlias
+	case flags.ShowGroup != "":
+		return processor.RunModeGroup, flags.ShowGroup
+	case flags.BackfillShow != "":
+		return processor.RunModeBackfill, flags.BackfillShow
+	}
+	return processor.RunModeBatch, ""
+}
+
+// finishSummary completes summary once the run is over: its mode, end time and
+// exit code, the outcome of each show of run (nil when no run finished) and
+// runErr, why the run stopped early or failed. A status not already set
+// follows the exit code.
+func finishSummary(summary *logger.ExecutionSummary, exitCode int, run *processor.BatchResult, runErr error) {
+	summary.Mode, summary.Target = runMode()
+	summary.DryRun = flags.DryRun
+	summary.Offline = flags.Offline
+	if summary.ConfigFile == "" {
+		summary.ConfigFile = flags.ConfigPath
+	}
+	summary.EndTime = time.Now()
+	summary.ExitCode = exitCode
+	if run != nil {
+		run.Summarize(summary)
+	}
+	if runErr != nil {
+		summary.Error = runErr.Error()
+	}
+	if summary.Status == "" {
+		summary.Status = logger.RunSuccess
+		if exitCode != 0 {
+			summary.Status = logger.RunFailure
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cli"
+	"github.com/nowwaveradio/mixcloud-updater/internal/processor"
+)
+
+// TestRunMode checks which processing branch of run a command line reaches:
+// run dispatches on runMode, e.g. RunModeBackfill calls ProcessBackfill.
+func TestRunMode(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantMode   string
+		wantTarget string
+	}{
+		{"all shows", []string{configPath}, processor.RunModeBatch, ""},
+		{"single show", []string{"-show", "nnw", configPath}, processor.RunModeSingle, "nnw"},
+		{"several dates", []string{"-show", "nnw", "-date", "6/14/2025,6/21/2025", configPath}, processor.RunModeDates, "nnw"},
+		{"group", []string{"-group", "weekend", configPath}, processor.RunModeGroup, "weekend"},
+		{"backfill", []string{"-backfill", "nnw", configPath}, processor.RunModeBackfill, "nnw"},
+		{"backfill with padded name", []string{"-backfill", " nnw ", "-backfill-limit", "3", configPath}, processor.RunModeBackfill, "nnw"},
+		{"retry failed", []string{"-retry-failed", configPath}, processor.RunModeRetry, ""},
+	}
+
+	saved := flags
+	defer func() { flags = saved }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := cli.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if err := cli.Validate(opts); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			flags = opts
+
+			mode, target := runMode()
+			if mode != tt.wantMode || target != tt.wantTarget {
+				t.Errorf("runMode() = %q, %q; want %q, %q", mode, target, tt.wantMode, tt.wantTarget)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/templatetest"
+)
+
+// runTemplateTests renders the golden-file cases in paths.templates_test_dir and
+// reports pass/fail per case. Like -lint, it skips loadConfiguration so no OAuth
+// flow is started. It returns the number of failed cases.
+func runTemplateTests(configPath string, update bool, out io.Writer) (int, error) {
+	cfg, err := loadConfig(filepath.Clean(configPath))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dir := cfg.Paths.TemplatesTestDir
+	if dir == "" {
+		return 0, fmt.Errorf("paths.templates_test_dir is not set in %s", configPath)
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(configPath), dir) // Relative to the config file
+	}
+
+	results, err := templatetest.Run(cfg, dir, update)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Fprintf(out, "Template Tests: %s\n", dir)
+	fmt.Fprintf(out, "===============\n\n")
+
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Fprintf(out, "❌ ERROR %s: %v\n", result.Name, result.Err)
+		case result.Updated:
+			fmt.Fprintf(out, "✏️  UPDATED %s (%s)\n", result.Name, result.Template)
+		case result.Passed:
+			fmt.Fprintf(out, "✅ PASS %s (%s)\n", result.Name, result.Template)
+		default:
+			failed++
+			fmt.Fprintf(out, "❌ FAIL %s (%s)\n%s\n", result.Name, result.Template, result.Diff)
+		}
+	}
+
+	fmt.Fprintf(out, "\n%d passed, %d failed\n", len(results)-failed, failed)
+	return failed, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+)
+
+// testFilterOptions selects what -test-filter checks: one track given by
+// artist/title/genre, or every row of a CSV file
+type testFilterOptions struct {
+	Show    string // Optional show name or alias
+	Artist  string
+	Title   string
+	Genre   string
+	CSVPath string
+}
+
+// runTestFilter reports whether tracks would be excluded by the configured
+// filtering rules and which rule matched. Like runLint it loads the config
+// directly so no OAuth flow is started. Returns the number of excluded tracks.
+func runTestFilter(configPath string, opts testFilterOptions, out io.Writer) (int, error) {
+	cfg, err := loadConfig(filepath.Clean(configPath))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, warning := range cfg.Warnings {
+		console.Warnf("Warning: %s\n", warning)
+	}
+
+	showKey := ""
+	if opts.Show != "" {
+		resolver, err := shows.NewResolver(cfg)
+		if err != nil {
+			return 0, fmt.Errorf("creating show resolver: %w", err)
+		}
+		if showKey = resolver.FindShowKey(opts.Show); showKey == "" {
+			return 0, resolver.NotFoundError(opts.Show)
+		}
+	}
+
+	trackFilter, err := filter.NewFilter(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("creating filter: %w", err)
+	}
+
+	if opts.CSVPath != "" {
+		return testFilterCSV(trackFilter, opts.CSVPath, out)
+	}
+
+	track := cue.Track{Artist: opts.Artist, Title: opts.Title, Genre: opts.Genre}
+	result := trackFilter.FilterTrack(&track)
+
+	fmt.Fprintf(out, "Filter Test:\n")
+	fmt.Fprintf(out, "============\n\n")
+	if showKey != "" {
+		// AIDEV-NOTE: Filtering is global today; naming the show keeps scripts
+		// working once shows can override it
+		fmt.Fprintf(out, "Show:    %s (uses the [filtering] rules; shows have no filter overrides)\n", showKey)
+	}
+	fmt.Fprintf(out, "Artist:  %q\n", opts.Artist)
+	fmt.Fprintf(out, "Title:   %q\n", opts.Title)
+	if opts.Genre != "" {
+		fmt.Fprintf(out, "Genre:   %q\n", opts.Genre)
+	}
+	fmt.Fprintf(out, "\n")
+
+	if result.ShouldInclude {
+		fmt.Fprintf(out, "Verdict: INCLUDED - no filtering rule matches\n")
+		return 0, nil
+	}
+	fmt.Fprintf(out, "Verdict: EXCLUDED (%s)\n", result.Reason)
+	fmt.Fprintf(out, "Rule:    %s\n", trackFilter.Explain(result))
+	return 1, nil
+}
+
+// testFilterCSV checks every artist,title[,genre] row of a CSV file and writes
+// the rows back with verdict, reason, field and rule columns appended. A header
+// row starting with "artist" is kept and extended, and its "title" and "genre"
+// columns are found by name; extra columns pass through.
+func testFilterCSV(trackFilter *filter.Filter, path string, out io.Writer) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("opening filter test CSV: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Play history exports often carry extra columns
+	reader.TrimLeadingSpace = true
+	writer := csv.NewWriter(out)
+
+	titleCol, genreCol := 1, 2
+	excluded, total := 0, 0
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return excluded, fmt.Errorf("reading filter test CSV: %w", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) < 2 {
+			return excluded, fmt.Errorf("filter test CSV row %d: want artist,title[,genre]", row)
+		}
+		if row == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "artist") {
+			genreCol = -1
+			for i, name := range record {
+				switch strings.ToLower(strings.TrimSpace(name)) {
+				case "title":
+					titleCol = i
+				case "genre":
+					genreCol = i
+				}
+			}
+			writer.Write(append(record, "verdict", "reason", "field", "rule"))
+			continue
+		}
+
+		track := cue.Track{Artist: record[0]}
+		if titleCol < len(record) {
+			track.Title = record[titleCol]
+		}
+		if genreCol >= 0 && genreCol < len(record) {
+			track.Genre = record[genreCol]
+		}
+		result := trackFilter.FilterTrack(&track)
+
+		total++
+		verdict := "included"
+		if !result.ShouldInclude {
+			verdict = "excluded"
+			excluded++
+		}
+		writer.Write(append(record, verdict, result.Reason, filter.ReasonField(result.Reason), trackFilter.Explain(result)))
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return excluded, fmt.Errorf("writing filter test results: %w", err)
+	}
+	console.Notef("%d of %d tracks excluded\n", excluded, total)
+	return excluded, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/httpclient"
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+	"github.com/nowwaveradio/mixcloud-updater/internal/update"
+)
+
+// runCheckUpdate handles -check-update: it always queries GitHub and, when the
+// config loads, records the check so processing.update_check doesn't repeat it
+// today. Unlike the periodic check, failures are reported.
+func runCheckUpdate(cfg *config.Config, configPath string, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), update.Timeout)
+	defer cancel()
+
+	release, err := updateChecker(cfg).Latest(ctx)
+	if err != nil {
+		return fmt.Errorf("checking for updates: %w", err)
+	}
+	if cfg != nil {
+		recordUpdateCheck(cfg, configPath, release, time.Now())
+	}
+
+	newer, err := update.Newer(version, release.Version)
+	if err != nil {
+		return fmt.Errorf("comparing versions: %w", err)
+	}
+	if !newer {
+		fmt.Fprintf(w, "Mixcloud Updater v%s is up to date (latest release: %s)\n", version, release.Version)
+		return nil
+	}
+	reportNewerRelease(w, release)
+	return nil
+}
+
+// updateChecker returns a checker that goes through network.proxy_url, or the
+// environment proxy when cfg is nil or sets none
+func updateChecker(cfg *config.Config) update.Checker {
+	proxyURL := ""
+	if cfg != nil {
+		proxyURL = cfg.Network.ProxyURL
+	}
+	client, err := httpclient.New(proxyURL, update.Timeout)
+	if err != nil {
+		return update.Checker{} // Validation rejects a bad proxy_url before this
+	}
+	return update.Checker{Client: client}
+}
+
+// startUpdateCheck begins the once-a-day processing.update_check in the
+// background so it overlaps the run. The returned function waits for it (never
+// longer than update.Timeout from the start) and records the result; call it
+// after processing so the processor's own state writes aren't overwritten.
+// Offline hosts and API errors are only logged at debug level.
+func startUpdateCheck(cfg *config.Config, configPath string, w io.Writer) func() {
+	noop := func() {}
+	if !cfg.Processing.UpdateCheck {
+		return noop
+	}
+
+	log := logger.Get()
+	now := time.Now()
+	last := loadRunState(cfg, configPath).UpdateCheck
+	if !update.Due(last.CheckedAt, now) {
+		// Keep reminding between checks without asking GitHub again
+		if newer, _ := update.Newer(version, last.LatestVersion); newer {
+			reportNewerRelease(w, update.Release{Version: last.LatestVersion, URL: last.ReleaseURL})
+		}
+		return noop
+	}
+
+	type result struct {
+		release update.Release
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), update.Timeout)
+		defer cancel()
+		release, err := updateChecker(cfg).Latest(ctx)
+		done <- result{release, err}
+	}()
+
+	return func() {
+		res := <-done
+		if res.err != nil {
+			log.Debug("Update check failed", slog.String("error", res.err.Error()))
+			return
+		}
+		recordUpdateCheck(cfg, configPath, res.release, now)
+		if newer, err := update.Newer(version, res.release.Version); err != nil {
+			log.Debug("Update check returned an unrecognized version",
+				slog.String("latest", res.release.Version),
+				slog.String("error", err.Error()))
+		} else if newer {
+			reportNewerRelease(w, res.release)
+		}
+	}
+}
+
+// recordUpdateCheck stores a check in the state file, reloading it first so
+// anything written since startup is kept
+func recordUpdateCheck(cfg *config.Config, configPath string, release update.Release, checkedAt time.Time) {
+	runState := loadRunState(cfg, configPath)
+	runState.RecordUpdateCheck(state.UpdateCheck{
+		CheckedAt:     checkedAt,
+		LatestVersion: release.Version,
+		ReleaseURL:    release.URL,
+	})
+	if err := runState.Save(); err != nil {
+		logger.Get().Warn("Failed to record update check", slog.String("error", err.Error()))
+	}
+}
+
+// reportNewerRelease prints and logs that a newer release is available
+func reportNewerRelease(w io.Writer, release update.Release) {
+	logger.Get().Info("Newer version available",
+		slog.String("current", version),
+		slog.String("latest", release.Version),
+		slog.String("release_url", release.URL))
+	fmt.Fprintf(w, "A newer version is available: %s (running v%s)\n", release.Version, version)
+	if release.URL != "" {
+		fmt.Fprintf(w, "  Release notes: %s\n", release.URL)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+)
+
+// runWhichShow reports which enabled shows pick up a CUE file, in processing
+// order, and warns when more than one does. Like runLint it loads the config
+// directly so no OAuth flow is started. Returns the number of matching shows.
+func runWhichShow(configPath, filename string, out io.Writer) (int, error) {
+	cfg, err := loadConfig(filepath.Clean(configPath))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, warning := range cfg.Warnings {
+		console.Warnf("Warning: %s\n", warning)
+	}
+
+	resolver, err := shows.NewResolver(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("creating show resolver: %w", err)
+	}
+	matches := resolver.MatchShowsForFile(filename)
+
+	fmt.Fprintf(out, "Shows picking up %s:\n", filename)
+	fmt.Fprintf(out, "CUE directory: %s\n\n", cfg.CueDirectory())
+	if len(matches) == 0 {
+		fmt.Fprintf(out, "No enabled show's cue_file_pattern or cue_file_mapping matches this file.\n")
+		return 0, nil
+	}
+
+	for i, showKey := range matches {
+		showCfg := cfg.Shows[showKey]
+		source := fmt.Sprintf("pattern %q", showCfg.CueFilePattern)
+		if showCfg.CueFileMapping != "" {
+			source = fmt.Sprintf("mapping %q", showCfg.CueFileMapping)
+		}
+		fmt.Fprintf(out, "%d. %s (priority %d, %s)\n", i+1, cfg.ShowDisplayKey(showKey), showCfg.Priority, source)
+	}
+
+	if len(matches) > 1 {
+		fmt.Fprintf(out, "\nWarning: %d shows match this file (%s) and would publish the same tracklist.\n",
+			len(matches), strings.Join(matches, ", "))
+		fmt.Fprintf(out, "Narrow their cue_file_pattern settings; -lint lists every overlap.\n")
+	}
+	return len(matches), nil
+}
@@ -0,0 +1,56 @@
+// Package analysis summarises filtered tracklists, e.g. the genres a show played,
+// for templates and the fields sent to Mixcloud.
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+)
+
+// AIDEV-NOTE: Genres feeds both .Genres in templates and tags_from_genres, so
+// the most common genres come first: a cap keeps what best describes the show.
+// Spellings differ between CUE files ("Post-Punk", "post-punk"); the first
+// spelling seen is kept.
+
+// Genres returns the distinct genres of tracks, most common first and in order
+// of first appearance when tied. Genres are merged case-insensitively; empty
+// genres and station content such as "Sweepers" are left out. max caps the
+// list, 0 keeps every genre.
+func Genres(tracks []cue.Track, max int) []string {
+	type genreCount struct {
+		name  string
+		count int
+	}
+	var genres []*genreCount
+	byKey := make(map[string]*genreCount)
+	for _, track := range tracks {
+		name := strings.Join(strings.Fields(track.Genre), " ")
+		if name == "" || filter.IsStationGenre(name) {
+			continue
+		}
+		key := strings.ToLower(name)
+		if genre, ok := byKey[key]; ok {
+			genre.count++
+			continue
+		}
+		genre := &genreCount{name: name, count: 1}
+		byKey[key] = genre
+		genres = append(genres, genre)
+	}
+
+	sort.SliceStable(genres, func(i, j int) bool {
+		return genres[i].count > genres[j].count
+	})
+	if max > 0 && len(genres) > max {
+		genres = genres[:max]
+	}
+
+	names := make([]string, len(genres))
+	for i, genre := range genres {
+		names[i] = genre.name
+	}
+	return names
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+// genreTracks returns one track per genre, in order
+func genreTracks(genres ...string) []cue.Track {
+	tracks := make([]cue.Track, len(genres))
+	for i, genre := range genres {
+		tracks[i] = cue.Track{Index: i + 1, Artist: "Artist", Title: "Title", Genre: genre}
+	}
+	return tracks
+}
+
+func TestGenres(t *testing.T) {
+	tests := []struct {
+		name   string
+		tracks []cue.Track
+		max    int
+		want   []string
+	}{
+		{
+			name:   "mixed casing merged, first spelling kept",
+			tracks: genreTracks("Post-Punk", "Synth-Pop", "post-punk", "POST-PUNK", "synth-pop"),
+			want:   []string{"Post-Punk", "Synth-Pop"},
+		},
+		{
+			name:   "most common first, ties in order of appearance",
+			tracks: genreTracks("Darkwave", "Italo Disco", "Coldwave", "Coldwave", "italo disco"),
+			want:   []string{"Italo Disco", "Coldwave", "Darkwave"},
+		},
+		{
+			name:   "empty and blank genres skipped, whitespace collapsed",
+			tracks: genreTracks("", "  ", "New  Wave ", "new wave"),
+			want:   []string{"New Wave"},
+		},
+		{
+			name:   "station content skipped",
+			tracks: genreTracks("Sweepers", "Station ID", "Minimal Synth", "Jingle"),
+			want:   []string{"Minimal Synth"},
+		},
+		{
+			name:   "cap keeps the most common",
+			tracks: genreTracks("EBM", "Darkwave", "Darkwave", "Industrial", "Industrial", "Industrial"),
+			max:    2,
+			want:   []string{"Industrial", "Darkwave"},
+		},
+		{
+			name:   "cap above the genre count",
+			tracks: genreTracks("EBM", "Darkwave"),
+			max:    5,
+			want:   []string{"EBM", "Darkwave"},
+		},
+		{
+			name:   "no genres",
+			tracks: genreTracks("", ""),
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Genres(tt.tracks, tt.max); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Genres() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package announcement loads the station-wide announcement from
+// station.announcement_source, a local file or an HTTPS URL, so a pledge drive
+// or schedule change reaches every description without editing templates.
+package announcement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AIDEV-NOTE: The announcement is optional decoration, so nothing here may fail
+// a run. Load hands back whatever text it could get together with the error,
+// and callers log the error as a warning. A URL's last good response is cached
+// next to the state file and used while the URL is down; an empty response is
+// cached too, so clearing the remote file switches the announcement off even
+// if a later fetch fails. File sources are read directly and never cached.
+
+// Timeout bounds a URL fetch; a slow host gives up quickly instead of stalling a run
+const Timeout = 5 * time.Second
+
+// MaxSize caps how much of a source is read; announcements are a line or two
+const MaxSize = 64 << 10
+
+// DefaultCacheFilename is the last-known-good copy of a URL source, kept next to the state file
+const DefaultCacheFilename = "mixcloud-updater-announcement.txt"
+
+// Result is a loaded announcement
+type Result struct {
+	Text   string // Trimmed announcement, "" when there is none
+	Cached bool   // Text came from the cache because the URL couldn't be fetched
+}
+
+// Loader reads announcement sources
+type Loader struct {
+	Client    *http.Client // Defaults to a client with Timeout
+	CachePath string       // Cache for URL sources; "" disables caching
+}
+
+// IsURL reports whether source is fetched over HTTP rather than read from disk
+func IsURL(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// Load returns the announcement from source. An empty source yields an empty
+// Result. When a URL can't be fetched, the cached copy (if any) is returned
+// along with the fetch error.
+func (l Loader) Load(ctx context.Context, source string) (Result, error) {
+	if source == "" {
+		return Result{}, nil
+	}
+
+	if !IsURL(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return Result{}, fmt.Errorf("reading announcement file: %w", err)
+		}
+		return Result{Text: clean(data)}, nil
+	}
+
+	text, err := l.fetch(ctx, source)
+	if err != nil {
+		cached, cacheErr := l.Cached()
+		if cacheErr != nil {
+			return Result{}, errors.Join(err, cacheErr)
+		}
+		return cached, err
+	}
+
+	if err := l.store(text); err != nil {
+		return Result{Text: text}, err
+	}
+	return Result{Text: text}, nil
+}
+
+// Cached returns the last-known-good copy of a URL source without fetching it.
+// A missing cache yields an empty Result.
+func (l Loader) Cached() (Result, error) {
+	if l.CachePath == "" {
+		return Result{}, nil
+	}
+	data, err := os.ReadFile(l.CachePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Result{}, nil
+		}
+		return Result{}, fmt.Errorf("reading announcement cache: %w", err)
+	}
+	return Result{Text: clean(data), Cached: true}, nil
+}
+
+func (l Loader) fetch(ctx context.Context, url string) (string, error) {
+	client := l.Client
+	if client == nil {
+		client = &http.Client{Timeout: Timeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating announcement request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching announcement: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching announcement: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize))
+	if err != nil {
+		return "", fmt.Errorf("reading announcement: %w", err)
+	}
+	return clean(data), nil
+}
+
+// store writes the cache atomically (write to temp file, then rename)
+func (l Loader) store(text string) error {
+	if l.CachePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.CachePath), 0755); err != nil {
+		return fmt.Errorf("creating announcement cache directory: %w", err)
+	}
+	tmpPath := l.CachePath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(text), 0644); err != nil {
+		return fmt.Errorf("writing announcement cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, l.CachePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("replacing announcement cache: %w", err)
+	}
+	return nil
+}
+
+// clean normalises line endings and trims surrounding whitespace
+func clean(data []byte) string {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,86 @@
+package announcement
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "announcement.txt")
+	if err := os.WriteFile(path, []byte("\r\nPledge drive all week\r\nnowwave.radio/give\r\n\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loader := Loader{CachePath: filepath.Join(dir, DefaultCacheFilename)}
+
+	got, err := loader.Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := (Result{Text: "Pledge drive all week\nnowwave.radio/give"}); got != want {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+	if _, err := os.Stat(loader.CachePath); !os.IsNotExist(err) {
+		t.Error("file sources should not be cached")
+	}
+
+	if got, err := loader.Load(context.Background(), filepath.Join(dir, "missing.txt")); err == nil || got.Text != "" {
+		t.Errorf("Load() of a missing file = %+v, %v; want an error and no text", got, err)
+	}
+	if got, err := loader.Load(context.Background(), ""); err != nil || got != (Result{}) {
+		t.Errorf("Load(\"\") = %+v, %v; want an empty result", got, err)
+	}
+}
+
+func TestLoadURL(t *testing.T) {
+	status, body := http.StatusOK, "  Pledge drive all week  \n"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	loader := Loader{Client: server.Client(), CachePath: filepath.Join(t.TempDir(), "state", DefaultCacheFilename)}
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    Result
+		wantErr bool
+	}{
+		{"fetched", http.StatusOK, "  Pledge drive all week  \n", Result{Text: "Pledge drive all week"}, false},
+		{"down, cached copy", http.StatusServiceUnavailable, "", Result{Text: "Pledge drive all week", Cached: true}, true},
+		{"cleared", http.StatusOK, "\n", Result{}, false},
+		{"down after clearing", http.StatusInternalServerError, "", Result{Cached: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body = tt.status, tt.body
+			got, err := loader.Load(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadURLWithoutCache(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	loader := Loader{Client: server.Client(), CachePath: filepath.Join(t.TempDir(), DefaultCacheFilename)}
+
+	got, err := loader.Load(context.Background(), server.URL)
+	if err == nil || got != (Result{}) {
+		t.Errorf("Load() = %+v, %v; want an error and no text", got, err)
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+)
+
+// Exit codes for a command line that never reaches App
+const (
+	exitUsage     = 1 // A command line that parses but doesn't make sense
+	exitFlagError = 2 // An unknown flag or bad flag value, as flag.ExitOnError exits
+)
+
+// App runs a parsed command line and returns the process exit code
+type App func(opts *Options, stdin io.Reader, stdout, stderr io.Writer) int
+
+// Run parses args, the full command line with the program name first like
+// os.Args, and hands the options to app. Help and version are printed here
+// without calling app, help winning over any error; so are usage errors, with
+// exit code 1 (2 for a flag the command doesn't take).
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer, app App) int {
+	program := "mixcloud-updater"
+	if len(args) > 0 {
+		program = args[0]
+		args = args[1:]
+	}
+
+	// Flag errors are held back so -help wins over a bad flag after it
+	var flagOutput bytes.Buffer
+	inv, err := parse(program, args, &flagOutput)
+	var usageErr *usageError
+	switch {
+	case inv.opts.Help || errors.Is(err, flag.ErrHelp):
+		inv.usage(stderr)
+		return 0
+	case err != nil && !errors.As(err, &usageErr):
+		io.Copy(stderr, &flagOutput) // The flag package's error and usage
+		return exitFlagError
+	case inv.opts.ShowVersion:
+		fmt.Fprintf(stdout, "Mixcloud Updater v%s\n", constants.Version)
+		return 0
+	case err != nil:
+		fmt.Fprintf(stderr, "Error: %v\n\n", err)
+		inv.usage(stderr)
+		return exitUsage
+	}
+	return app(inv.opts, stdin, stdout, stderr)
+}
@@ -0,0 +1,308 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// legacyParse parses args the way main did before subcommands: one flag set,
+// flag.Parse semantics and at most one positional config path
+func legacyParse(t *testing.T, args []string) *Options {
+	t.Helper()
+	opts := &Options{program: "mixcloud-updater"}
+	fs := newFlagSet("mixcloud-updater", opts, io.Discard)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("legacy parse of %q: %v", args, err)
+	}
+	if fs.NArg() > 1 {
+		t.Fatalf("legacy parse of %q: too many arguments", args)
+	}
+	opts.ConfigPath = opts.ConfigFile
+	if fs.NArg() == 1 {
+		opts.ConfigPath = fs.Arg(0)
+	}
+	return opts
+}
+
+// defaults returns the options of an empty command line changed by set
+func defaults(t *testing.T, set func(opts *Options)) *Options {
+	t.Helper()
+	opts, err := Parse(nil)
+	if err != nil {
+		t.Fatalf("Parse(nil) error = %v", err)
+	}
+	if set != nil {
+		set(opts)
+	}
+	return opts
+}
+
+func TestParseCompatibility(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"config.toml"},
+		{"/etc/nwr/config.toml"},
+		{"-show", "nnw", "config.toml"},
+		{"-show", "newer-new-wave", "config.toml"},
+		{"-dry-run", "config.toml"},
+		{"-show", "sounds-like", "-dry-run", "config.toml"},
+		{"-show", "nnw", "-date", "6/28/2025", "config.toml"},
+		{"-limit", "2", "-dry-run", "config.toml"},
+		{"-config", "other.toml"},
+		{"-config", "other.toml", "positional.toml"},
+		{"-list-shows", "-resolve", "config.toml"},
+		{"-history", "nnw", "-n", "20", "config.toml"},
+		{"-set-token", "-", "config.toml"},
+		{"-init", "-no-prompt", "-init-station", "NWR", "config.toml"},
+	}
+
+	for _, args := range tests {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			got, err := Parse(args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if want := legacyParse(t, args); !reflect.DeepEqual(got, want) {
+				t.Errorf("Parse() = %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseCommands(t *testing.T) {
+	tests := []struct {
+		args []string
+		want func(opts *Options)
+	}{
+		{[]string{"config.toml", "-dry-run"}, func(o *Options) { o.DryRun = true }},
+		{[]string{"-dry-run", "--", "-odd.toml"}, func(o *Options) { o.DryRun = true; o.ConfigPath = "-odd.toml" }},
+		{[]string{"process", "-show", "nnw"}, func(o *Options) { o.Command = "process"; o.ShowAlias = "nnw" }},
+		{[]string{"preview", "-show", "nnw", "station.toml"}, func(o *Options) {
+			o.Command = "preview"
+			o.DryRun = true
+			o.ShowAlias = "nnw"
+			o.ConfigPath = "station.toml"
+		}},
+		{[]string{"list", "shows", "-resolve"}, func(o *Options) { o.Command = "list"; o.ListShows = true; o.Resolve = true }},
+		{[]string{"list", "templates", "station.toml"}, func(o *Options) {
+			o.Command = "list"
+			o.ListTemplates = true
+			o.ConfigPath = "station.toml"
+		}},
+		{[]string{"validate", "-fix-config"}, func(o *Options) { o.Command = "validate"; o.Lint = true; o.FixConfig = true }},
+		{[]string{"doctor", "-json"}, func(o *Options) { o.Command = "doctor"; o.Doctor = true; o.JSON = true }},
+		{[]string{"status", "-config", "station.toml"}, func(o *Options) {
+			o.Command = "status"
+			o.ShowStatus = true
+			o.ConfigFile = "station.toml"
+			o.ConfigPath = "station.toml"
+		}},
+		{[]string{"history", "nnw", "-n", "5"}, func(o *Options) { o.Command = "history"; o.ShowHistory = "nnw"; o.HistoryCount = 5 }},
+		{[]string{"auth", "set-token", "-", "station.toml"}, func(o *Options) {
+			o.Command = "auth"
+			o.SetToken = "-"
+			o.ConfigPath = "station.toml"
+		}},
+		{[]string{"auth", "migrate"}, func(o *Options) { o.Command = "auth"; o.MigrateCredentials = true }},
+		{[]string{"init", "-no-prompt", "-init-station", "NWR"}, func(o *Options) {
+			o.Command = "init"
+			o.InitConfig = true
+			o.NoPrompt = true
+			o.InitStationName = "NWR"
+		}},
+		{[]string{"version"}, func(o *Options) { o.Command = "version"; o.ShowVersion = true; o.ConfigPath = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if want := defaults(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("Parse() = %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		args      []string
+		wantUsage bool // A usageError rather than one the flag package reports
+	}{
+		{[]string{"a.toml", "b.toml"}, true},
+		{[]string{"-show", "nnw", "config.toml", "extra"}, true},
+		{[]string{"list"}, true},
+		{[]string{"list", "episodes"}, true},
+		{[]string{"history"}, true},
+		{[]string{"auth"}, true},
+		{[]string{"auth", "set-token"}, true},
+		{[]string{"help", "publish"}, true},
+		{[]string{"-bogus"}, false},
+		{[]string{"-limit", "two"}, false},
+		{[]string{"doctor", "-show", "nnw"}, false}, // doctor doesn't take -show
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			_, err := Parse(tt.args)
+			var usageErr *usageError
+			if err == nil || errors.As(err, &usageErr) != tt.wantUsage {
+				t.Errorf("Parse() error = %v, want usage error %v", err, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantApp    bool
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "version", args: []string{"-version"}, wantStdout: "Mixcloud Updater v"},
+		{name: "version wins over a bad config path", args: []string{"missing/dir/config.toml", "-version"}, wantStdout: "Mixcloud Updater v"},
+		{name: "version wins over extra arguments", args: []string{"-version", "a.toml", "b.toml"}, wantStdout: "Mixcloud Updater v"},
+		{name: "version wins over a missing command argument", args: []string{"history", "-version"}, wantStdout: "Mixcloud Updater v"},
+		{name: "help wins over version", args: []string{"-help", "-version"}, wantStderr: "Commands:"},
+		{name: "-h", args: []string{"-h"}, wantStderr: "Commands:"},
+		{name: "command help", args: []string{"list", "-help"}, wantStderr: "list shows|templates [OPTIONS]"},
+		{name: "help command", args: []string{"help", "auth"}, wantStderr: "auth set-token"},
+		{name: "help wins over a bad flag", args: []string{"-help", "-bogus"}, wantStderr: "Commands:"},
+		{name: "help wins over a bad flag value", args: []string{"-help", "-limit", "many"}, wantStderr: "Commands:"},
+		{name: "help for an unknown command", args: []string{"help", "publish"}, wantCode: exitUsage, wantStderr: `unknown command "publish"`},
+		{name: "extra arguments", args: []string{"a.toml", "b.toml"}, wantCode: exitUsage, wantStderr: "too many arguments"},
+		{name: "missing command argument", args: []string{"list"}, wantCode: exitUsage, wantStderr: "list needs shows or templates"},
+		{name: "unknown flag", args: []string{"-bogus"}, wantCode: exitFlagError, wantStderr: "flag provided but not defined: -bogus"},
+		{name: "runs the app", args: []string{"-show", "nnw", "config.toml"}, wantCode: 4, wantApp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			called := false
+			app := func(opts *Options, stdin io.Reader, stdout, stderr io.Writer) int {
+				called = true
+				return 4
+			}
+
+			code := Run(append([]string{"mixcloud-updater"}, tt.args...), strings.NewReader(""), &stdout, &stderr, app)
+			if code != tt.wantCode || called != tt.wantApp {
+				t.Errorf("Run() = %d, app called %v; want %d, %v", code, called, tt.wantCode, tt.wantApp)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) || tt.wantStdout == "" && stdout.Len() > 0 {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) || tt.wantStderr == "" && stderr.Len() > 0 {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "bare config path", args: []string{configPath}},
+		{name: "single show", args: []string{"-show", "nnw", configPath}},
+		{name: "missing config directory", args: []string{filepath.Join(configPath, "missing", "config.toml")}, wantErr: "config file directory does not exist"},
+		{name: "show and group", args: []string{"-show", "nnw", "-group", "weekend", configPath}, wantErr: "-show and -group cannot be used together"},
+		{name: "limit with show", args: []string{"-show", "nnw", "-limit", "2", configPath}, wantErr: "-limit cannot be used with -show"},
+		{name: "verify without dry run", args: []string{"-verify", configPath}, wantErr: "-verify requires -dry-run"},
+		{name: "verify in preview", args: []string{"preview", "-verify", configPath}},
+		{name: "json without doctor", args: []string{"-json", configPath}, wantErr: "-json requires -doctor or -history"},
+		{name: "resolve without list", args: []string{"-resolve", configPath}, wantErr: "-resolve can only be used with -list-shows"},
+		{name: "resolve with list templates", args: []string{"list", "templates", "-resolve", configPath}, wantErr: "-resolve can only be used with -list-shows"},
+		{name: "whitespace show", args: []string{"-show", "  ", configPath}, wantErr: "show alias cannot be empty"},
+		{name: "several dates without show", args: []string{"-date", "6/14/2025,6/21/2025", configPath}, wantErr: "-date with several dates requires -show"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			err = Validate(opts)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNormalizesOptions(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+
+	opts, _ := Parse([]string{"-show", " nnw ", configPath})
+	if err := Validate(opts); err != nil || opts.ShowAlias != "nnw" {
+		t.Errorf("Validate() = %v, show %q; want the alias trimmed", err, opts.ShowAlias)
+	}
+
+	opts, _ = Parse([]string{"-compare-templates", "classic,detailed", configPath})
+	if err := Validate(opts); err != nil || !opts.DryRun {
+		t.Errorf("Validate() = %v, dry run %v; want -compare-templates to be a dry run", err, opts.DryRun)
+	}
+
+	// -backfill is checked like an alias without turning into -show
+	opts, _ = Parse([]string{"-backfill", " nnw ", configPath})
+	if err := Validate(opts); err != nil || opts.BackfillShow != "nnw" || opts.ShowAlias != "" {
+		t.Errorf("Validate() = %v, backfill %q, show %q; want only the backfill show set", err, opts.BackfillShow, opts.ShowAlias)
+	}
+}
+
+func TestEveryCommandFlagExists(t *testing.T) {
+	all := newFlagSet("mixcloud-updater", &Options{}, io.Discard)
+	for _, cmd := range commands {
+		for _, name := range append(append([]string{}, commonFlags...), cmd.flags...) {
+			if all.Lookup(name) == nil {
+				t.Errorf("command %s takes undefined flag -%s", cmd.name, name)
+			}
+		}
+	}
+}
+
+func TestRegisterSecrets(t *testing.T) {
+	all := newFlagSet("mixcloud-updater", &Options{}, io.Discard)
+	for name := range secretFlags {
+		if all.Lookup(name) == nil {
+			t.Errorf("secret flag -%s is undefined", name)
+		}
+	}
+
+	tests := [][]string{
+		{"-init", "-no-prompt", "-init-client-id", "my-client", "-init-client-secret", "SUPERSECRET123", "config.toml"},
+		{"init", "-no-prompt", "-init-client-secret=SUPERSECRET123", "config.toml"},
+		{"-set-token", "SUPERSECRET123", "config.toml"},
+		{"-set-token=SUPERSECRET123", "config.toml"},
+		{"auth", "set-token", "SUPERSECRET123", "config.toml"},
+	}
+	for _, args := range tests {
+		opts, err := Parse(args)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", args, err)
+		}
+		opts.RegisterSecrets()
+
+		logged := strings.Join(logger.RedactArgs(append([]string{"mixcloud-updater"}, args...)), " ")
+		if strings.Contains(logged, "SUPERSECRET123") || !strings.Contains(logged, "[REDACTED:") {
+			t.Errorf("logged command line %q, want the secret redacted", logged)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AIDEV-NOTE: Subcommands are spellings of the flags-only command line, which
+// stays fully supported: "preview" sets DryRun, "list shows" sets ListShows and
+// so on, so cmd/mixcloud-updater dispatches on Options alone. A first argument
+// that names a command is that command; a config file with such a name needs
+// -config or a path like ./status.
+
+// command is a subcommand, the flags it takes and the Options it stands for
+type command struct {
+	name    string
+	args    string // Its own arguments, for usage
+	summary string
+	flags   []string // Flags taken besides commonFlags; nil takes every flag
+	// apply sets opts for the command from its leading arguments and returns the rest
+	apply func(opts *Options, args []string) ([]string, error)
+}
+
+// commonFlags are taken by every subcommand
+var commonFlags = []string{"config", "quiet", "strict-config", "script", "help", "version"}
+
+var commands = []*command{
+	{
+		name:    "process",
+		summary: "Process all enabled shows, or the ones -show, -group or -backfill pick (the default)",
+	},
+	{
+		name:    "preview",
+		summary: "Render like process without updating Mixcloud (same as -dry-run)",
+		apply: func(opts *Options, args []string) ([]string, error) {
+			opts.DryRun = true
+			return args, nil
+		},
+	},
+	{
+		name:    "list",
+		args:    "shows|templates",
+		summary: "List the configured shows (-resolve checks their CUE files) or templates",
+		flags:   []string{"resolve"},
+		apply: func(opts *Options, args []string) ([]string, error) {
+			if len(args) == 0 {
+				return nil, usagef("list needs shows or templates, e.g. list shows")
+			}
+			switch args[0] {
+			case "shows":
+				opts.ListShows = true
+			case "templates":
+				opts.ListTemplates = true
+			default:
+				return nil, usagef("list needs shows or templates, got %q", args[0])
+			}
+			return args[1:], nil
+		},
+	},
+	{
+		name:    "validate",
+		summary: "Report unused templates, dead CUE patterns and other config cruft (same as -lint)",
+		flags:   []string{"fix-config"},
+		apply: func(opts *Options, args []string) ([]string, error) {
+			opts.Lint = true
+			return args, nil
+		},
+	},
+	{
+		name:    "doctor",
+		summary: "Check config, OAuth token, Mixcloud connectivity and the rest of the pipeline",
+		flags:   []string{"json"},
+		apply: func(opts *Options, args []string) ([]string, error) {
+			opts.Doctor = true
+			return args, nil
+		},
+	},
+	{
+		name:    "status",
+		summary: "Show last publish info for enabled shows and flag overdue ones",
+		apply: func(opts *Options, args []string) ([]string, error) {
+			opts.ShowStatus = true
+			return args, nil
+		},
+	},
+	{
+		name:    "history",
+		args:    "<show>",
+		summary: "Print the recent publishes of a show by name/alias",
+		flags:   []string{"n", "json"},
+		apply: func(opts *Options, args []string) ([]string, error) {
+			if len(args) == 0 {
+				return nil, usagef("history needs a show name or alias, e.g. history nnw")
+			}
+			opts.ShowHistory = args[0]
+			return args[1:], nil
+		},
+	},
+	{
+		name:    "auth",
+		args:    "set-token <token|-> | migrate",
+		summary: "Save an access token authorized elsewhere (- reads it from stdin), or move the OAuth secrets into the OS keychain",
+		flags:   []string{"wait-lock"},
+		apply: func(opts *Options, args []string) ([]string, error) {
+			switch {
+			case len(args) > 0 && args[0] == "migrate":
+				opts.MigrateCredentials = true
+				return args[1:], nil
+			case len(args) > 1 && args[0] == "set-token":
+				opts.SetToken = args[1]
+				return args[2:], nil
+			case len(args) > 0 && args[0] == "set-token":
+				return nil, usagef("auth set-token needs a token, or - to read it from stdin")
+			}
+			return nil, usagef("auth needs set-token or migrate, e.g. auth set-token - config.toml < token.txt")
+		},
+	},
+	{
+		name:    "init",
+		summary: "Create a commented starter config file interactively",
+		flags: []string{"force", "no-prompt", "init-station", "init-username", "init-client-id", "init-client-secret",
+			"init-cue-dir", "init-show-key", "init-show-name", "init-show-pattern", "init-show-aliases", "init-template"},
+		apply: func(opts *Options, args []string) ([]string, error) {
+			opts.InitConfig = true
+			return args, nil
+		},
+	},
+	{
+		name:    "version",
+		summary: "Show version information",
+		apply: func(opts *Options, args []string) ([]string, error) {
+			opts.ShowVersion = true
+			return args, nil
+		},
+	},
+	{
+		name:    "help",
+		args:    "[command]",
+		summary: "Show help for the program or a command",
+	},
+}
+
+// lookupCommand returns the subcommand called name, or nil
+func lookupCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// flagSet returns the flags cmd takes, bound to opts through all
+func (cmd *command) flagSet(all *flag.FlagSet) *flag.FlagSet {
+	if cmd.flags == nil {
+		return subset(cmd.name, all, allFlagNames(all))
+	}
+	return subset(cmd.name, all, append(append([]string{}, commonFlags...), cmd.flags...))
+}
+
+// allFlagNames lists the flags of fs in lexical order
+func allFlagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	return names
+}
+
+// usageError is a command line that parses but doesn't make sense, as opposed
+// to the flag errors the flag package reports itself
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usagef formats a usageError
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// errTooManyArguments is a command line with more than one config path left
+var errTooManyArguments = usagef("too many arguments: expected at most one config file path")
+
+// invocation is a parsed command line and what its usage looks like
+type invocation struct {
+	opts    *Options
+	cmd     *command // nil for the flags-only form
+	flags   *flag.FlagSet
+	program string
+	topic   *command // The command "help <command>" asked about
+}
+
+// Parse parses a command line without the program name, e.g. os.Args[1:].
+// -help and -version win over every other problem with the command line, so
+// their errors are only reported once neither is set.
+func Parse(args []string) (*Options, error) {
+	inv, err := parse("mixcloud-updater", args, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+	return inv.opts, nil
+}
+
+// parse parses args for program; flag errors and -h usage go to output
+func parse(program string, args []string, output io.Writer) (*invocation, error) {
+	inv := &invocation{opts: &Options{program: program}, program: program}
+	all := newFlagSet(program, inv.opts, output)
+	inv.flags = all
+
+	if len(args) > 0 {
+		inv.cmd = lookupCommand(args[0])
+	}
+	if inv.cmd != nil {
+		inv.opts.Command = inv.cmd.name
+		inv.flags = inv.cmd.flagSet(all)
+		args = args[1:]
+	}
+	inv.flags.Usage = func() { inv.usage(output) }
+
+	positional, err := parseInterspersed(inv.flags, args)
+	if err != nil {
+		return inv, err
+	}
+
+	if inv.cmd != nil && inv.cmd.name == "help" {
+		if len(positional) > 0 {
+			inv.topic = lookupCommand(positional[0])
+			if inv.topic == nil {
+				return inv, usagef("unknown command %q", positional[0])
+			}
+		}
+		inv.opts.Help = true
+		return inv, nil
+	}
+	if inv.opts.Help || inv.opts.ShowVersion {
+		return inv, nil
+	}
+
+	if inv.cmd != nil && inv.cmd.apply != nil {
+		if positional, err = inv.cmd.apply(inv.opts, positional); err != nil {
+			return inv, err
+		}
+		// "version" sets ShowVersion, which ignores the rest like -version does
+		if inv.opts.ShowVersion {
+			return inv, nil
+		}
+	}
+
+	// A positional config path wins over -config
+	inv.opts.ConfigPath = inv.opts.ConfigFile
+	switch {
+	case len(positional) == 1:
+		inv.opts.ConfigPath = positional[0]
+	case len(positional) > 1:
+		return inv, errTooManyArguments
+	}
+	return inv, nil
+}
+
+// parseInterspersed parses fs from args, allowing flags after positional
+// arguments as in "config.toml -dry-run", and returns the positional ones.
+// Everything after "--" is positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// commandList is the Commands section of the usage
+func commandList() string {
+	var b strings.Builder
+	for _, cmd := range commands {
+		name := cmd.name
+		if cmd.args != "" {
+			name += " " + cmd.args
+		}
+		fmt.Fprintf(&b, "  %-36s %s\n", name, cmd.summary)
+	}
+	return b.String()
+}
@@ -0,0 +1,211 @@
+// Package cli parses the command line: the subcommands, the flags each one
+// takes, the positional config path and the checks between flags. Running the
+// commands stays in cmd/mixcloud-updater.
+package cli
+
+import (
+	"flag"
+	"io"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// DefaultConfigPath is the config file used when neither -config nor a
+// positional path is given
+const DefaultConfigPath = "config.toml"
+
+// Options is a parsed command line. Subcommands set the same fields as their
+// flag forms, e.g. "list shows" sets ListShows like -list-shows does.
+type Options struct {
+	Command    string // Subcommand name, "" for the flags-only form
+	ConfigPath string // The positional path, or -config
+	program    string // For usage, e.g. os.Args[0]
+
+	ConfigFile             string
+	ShowAlias              string
+	ShowGroup              string
+	ShowLimit              int
+	WaitLock               int
+	BackfillShow           string
+	BackfillDir            string
+	BackfillLimit          int
+	BackfillSince          string
+	MaxConsecutiveFailures int
+	MaxTotalFailures       int
+	TemplateName           string
+	DateOverride           string
+	TimeOffset             string
+	DryRun                 bool
+	Verify                 bool
+	Confirm                bool
+	Offline                bool
+	RetryFailed            bool
+	Resume                 bool
+	NoResume               bool
+	NoCache                bool
+	StrictCue              bool
+	StrictConfig           bool
+	Force                  bool
+	VerbosePreview         bool
+	OutputFile             string
+	CompareTemplates       string
+	CompareOutput          string
+	DigestPath             string
+	ShowVersion            bool
+	CheckUpdate            bool
+	Help                   bool
+	ListShows              bool
+	Resolve                bool
+	ListTemplates          bool
+	ShowStatus             bool
+	ShowHistory            string
+	HistoryCount           int
+	MigrateCredentials     bool
+	SetToken               string
+	FixConfig              bool
+	WhichShow              string
+	TestFilter             bool
+	FilterArtist           string
+	FilterTitle            string
+	FilterGenre            string
+	FilterCSV              string
+	PrintEnvVars           bool
+	PrintEffectiveConfig   bool
+	Doctor                 bool
+	JSON                   bool
+	Lint                   bool
+	TestTemplates          bool
+	UpdateGolden           bool
+	ProgressJSON           bool
+	ProgressFile           string
+	Quiet                  bool
+	Script                 bool
+
+	// Answers for -init, used as prompt defaults or, with -no-prompt, as the full answer set
+	InitConfig       bool
+	NoPrompt         bool
+	InitStationName  string
+	InitUsername     string
+	InitClientID     string
+	InitClientSecret string
+	InitCueDir       string
+	InitShowKey      string
+	InitShowName     string
+	InitShowPattern  string
+	InitShowAliases  string
+	InitTemplate     string
+}
+
+// newFlagSet binds every flag to opts. Subcommands copy the ones they take
+// from it; the flags-only form takes them all.
+func newFlagSet(name string, opts *Options, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	fs.StringVar(&opts.ConfigFile, "config", DefaultConfigPath, "Path to the configuration file")
+	fs.StringVar(&opts.ShowAlias, "show", "", "Process specific show by name/alias (optional)")
+	fs.StringVar(&opts.ShowGroup, "group", "", "Process the enabled shows of a show_group (optional)")
+	fs.IntVar(&opts.ShowLimit, "limit", 0, "Process only the first N enabled shows by priority, for smoke testing (0 = all)")
+	fs.IntVar(&opts.WaitLock, "wait-lock", 0, "When another instance is running against the same config, wait up to this many seconds for it instead of exiting")
+	fs.StringVar(&opts.BackfillShow, "backfill", "", "Publish every archived episode of a show by name/alias, oldest first")
+	fs.StringVar(&opts.BackfillDir, "backfill-dir", "", "With -backfill, search this directory for the show's CUE files instead of cue_file_directory")
+	fs.IntVar(&opts.BackfillLimit, "backfill-limit", 0, "With -backfill, process only the N oldest episodes (0 = all)")
+	fs.StringVar(&opts.BackfillSince, "backfill-since", "", "With -backfill, skip episodes dated before this date (e.g. 2025-01-31)")
+	fs.IntVar(&opts.MaxConsecutiveFailures, "max-consecutive-failures", -1, "Stop a batch run after this many failed shows in a row, replacing processing.max_consecutive_failures (0 = never)")
+	fs.IntVar(&opts.MaxTotalFailures, "max-total-failures", -1, "Stop a batch run after this many failed shows in all, replacing processing.max_total_failures (0 = never)")
+	fs.StringVar(&opts.TemplateName, "template", "", "Template name to use for formatting (optional)")
+	fs.StringVar(&opts.DateOverride, "date", "", "Override date for show, e.g. 6/28/2025, or today, yesterday, \"N days ago\", \"last friday\" in the station timezone; with -show, a comma-separated list processes each date")
+	fs.StringVar(&opts.TimeOffset, "time-offset", "", "With -show, shift every track start time by [+|-]HH:MM:SS instead of the show's time_offset, e.g. -00:01:30")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "Preview changes without updating Mixcloud")
+	fs.BoolVar(&opts.Verify, "verify", false, "With -dry-run, look each show up on Mixcloud (read-only) and report whether its URL resolves")
+	fs.BoolVar(&opts.Confirm, "confirm", false, "Allow live runs to rename shows that set update_name (their Mixcloud URL changes)")
+	fs.BoolVar(&opts.Offline, "offline", false, "Render and archive every show without contacting Mixcloud, queueing the descriptions for -retry-failed")
+	fs.BoolVar(&opts.RetryFailed, "retry-failed", false, "Publish the descriptions queued by offline runs (-offline or offline_fallback)")
+	fs.BoolVar(&opts.Resume, "resume", false, "Resume an interrupted run of all enabled shows, skipping the shows it already published")
+	fs.BoolVar(&opts.NoResume, "no-resume", false, "Process every enabled show without checking for an interrupted run to resume")
+	fs.BoolVar(&opts.NoCache, "no-cache", false, "Always fetch shows from Mixcloud instead of revalidating cached responses")
+	fs.BoolVar(&opts.StrictCue, "strict-cue", false, "Fail a show on its first malformed CUE track instead of skipping it")
+	fs.BoolVar(&opts.StrictConfig, "strict-config", false, "Fail on unknown config keys (usually typos) instead of warning about them")
+	fs.BoolVar(&opts.Force, "force", false, "Continue even when rendered output contains template artifacts; with -show, publish outside the show's publish window; with -init, overwrite an existing config")
+	fs.BoolVar(&opts.VerbosePreview, "verbose-preview", false, "Print full descriptions in dry-run mode instead of trimmed previews")
+	fs.StringVar(&opts.OutputFile, "output", "", "Write full dry-run descriptions (or -filter-csv results) to this file")
+	fs.StringVar(&opts.CompareTemplates, "compare-templates", "", "Dry run rendering every show with two templates, e.g. old,new, and report each one's length, tracks shown and truncation")
+	fs.StringVar(&opts.CompareOutput, "compare-output", "", "With -compare-templates, write each show's two renders and their diff to <show>.txt in this directory")
+	fs.StringVar(&opts.DigestPath, "digest", "", "After a batch run, write one document listing every show with its published tracklist to this file")
+	fs.BoolVar(&opts.ShowVersion, "version", false, "Show version information")
+	fs.BoolVar(&opts.CheckUpdate, "check-update", false, "Check GitHub for a newer release and exit")
+	fs.BoolVar(&opts.Help, "help", false, "Show help information")
+	fs.BoolVar(&opts.ListShows, "list-shows", false, "List available shows and their aliases")
+	fs.BoolVar(&opts.Resolve, "resolve", false, "With -list-shows, resolve, parse and filter each enabled show's CUE file now, without formatting or contacting Mixcloud")
+	fs.BoolVar(&opts.ListTemplates, "list-templates", false, "List available templates")
+	fs.BoolVar(&opts.ShowStatus, "status", false, "Show last publish info for enabled shows and flag overdue ones")
+	fs.StringVar(&opts.ShowHistory, "history", "", "Print the recent publishes of a show by name/alias")
+	fs.IntVar(&opts.HistoryCount, "n", 10, "With -history, the number of entries to print (0 = all kept)")
+	fs.BoolVar(&opts.MigrateCredentials, "migrate-credentials", false, "Move the OAuth client secret and tokens from the config file into the OS keychain/credential manager")
+	fs.StringVar(&opts.SetToken, "set-token", "", "Check an access token obtained elsewhere against Mixcloud and save it like the browser authorization would; - reads it from stdin")
+	fs.BoolVar(&opts.FixConfig, "fix-config", false, "Rewrite smart quotes, non-breaking spaces and a BOM in the config file to plain ASCII (keeps a backup)")
+	fs.StringVar(&opts.WhichShow, "which-show", "", "Report which enabled shows pick up this CUE file name (looked up in the CUE directory)")
+	fs.BoolVar(&opts.TestFilter, "test-filter", false, "Report whether -artist/-title/-genre (or each -filter-csv row) would be excluded, and by which rule")
+	fs.StringVar(&opts.FilterArtist, "artist", "", "With -test-filter, the artist to check")
+	fs.StringVar(&opts.FilterTitle, "title", "", "With -test-filter, the title to check")
+	fs.StringVar(&opts.FilterGenre, "genre", "", "With -test-filter, the genre to check (optional)")
+	fs.StringVar(&opts.FilterCSV, "filter-csv", "", "With -test-filter, check every artist,title[,genre] row of this CSV and print it with a verdict column")
+	fs.BoolVar(&opts.PrintEnvVars, "print-env-vars", false, "List the NWRMIXCLOUD_ environment variables that override config values")
+	fs.BoolVar(&opts.PrintEffectiveConfig, "print-effective-config", false, "Print the config in force, with [config] overlay and environment overrides merged and secrets redacted")
+	fs.BoolVar(&opts.Doctor, "doctor", false, "Check config, OAuth token, Mixcloud connectivity and clock, CUE and log directories, templates and filters; exit 0 (pass), 1 (warnings) or 2 (failures)")
+	fs.BoolVar(&opts.JSON, "json", false, "With -doctor or -history, print JSON for monitoring and tooling")
+	fs.BoolVar(&opts.Lint, "lint", false, "Report unused templates, disabled shows, dead CUE patterns and other config cruft")
+	fs.BoolVar(&opts.TestTemplates, "test-templates", false, "Render the golden-file cases in paths.templates_test_dir and diff against expected.txt")
+	fs.BoolVar(&opts.UpdateGolden, "update-golden", false, "With -test-templates, rewrite expected.txt from the current output")
+	fs.BoolVar(&opts.ProgressJSON, "progress-json", false, "Write newline-delimited JSON progress events to stdout; human output moves to stderr")
+	fs.StringVar(&opts.ProgressFile, "progress-file", "", "Write progress events to this file or named pipe instead of stdout (implies -progress-json)")
+	fs.BoolVar(&opts.Quiet, "quiet", false, "Print only errors and the run's one-line outcome, like logging.console_verbosity = \"quiet\"; the log file keeps everything")
+	fs.BoolVar(&opts.Script, "script", false, "Keep stdout for the command's output (reports, -json, the description of a -show -dry-run); banners, progress and summaries go to stderr")
+	fs.BoolVar(&opts.InitConfig, "init", false, "Create a commented starter config file interactively")
+	fs.BoolVar(&opts.NoPrompt, "no-prompt", false, "With -init, take all answers from the -init-* flags instead of prompting")
+	fs.StringVar(&opts.InitStationName, "init-station", "", "With -init: station name")
+	fs.StringVar(&opts.InitUsername, "init-username", "", "With -init: Mixcloud username")
+	fs.StringVar(&opts.InitClientID, "init-client-id", "", "With -init: Mixcloud OAuth client ID")
+	fs.StringVar(&opts.InitClientSecret, "init-client-secret", "", "With -init: Mixcloud OAuth client secret")
+	fs.StringVar(&opts.InitCueDir, "init-cue-dir", "", "With -init: directory containing CUE files")
+	fs.StringVar(&opts.InitShowKey, "init-show-key", "", "With -init: key of the first show, e.g. sounds-like")
+	fs.StringVar(&opts.InitShowName, "init-show-name", "", "With -init: Mixcloud title pattern of the first show, e.g. \"Sounds Like - {date}\"")
+	fs.StringVar(&opts.InitShowPattern, "init-show-pattern", "", "With -init: CUE file pattern of the first show, e.g. \"MYR_SoundsLike_*.cue\"")
+	fs.StringVar(&opts.InitShowAliases, "init-show-aliases", "", "With -init: comma-separated aliases of the first show")
+	fs.StringVar(&opts.InitTemplate, "init-template", "", "With -init: starter template, \"detailed\" or \"minimal\" (default: classic)")
+
+	return fs
+}
+
+// subset is a flag set with only the named flags of all, still bound to the
+// same Options fields
+func subset(name string, all *flag.FlagSet, names []string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(all.Output())
+	for _, flagName := range names {
+		f := all.Lookup(flagName)
+		fs.Var(f.Value, f.Name, f.Usage)
+		fs.Lookup(flagName).DefValue = f.DefValue
+	}
+	return fs
+}
+
+// secretFlags are the flags whose values are credentials, with the kind their
+// [REDACTED:kind] marker shows in the log
+var secretFlags = map[string]string{
+	"set-token":          logger.SecretToken,
+	"init-client-secret": logger.SecretClientSecret,
+}
+
+// RegisterSecrets keeps the credentials given on the command line out of the
+// log, starting with the command line itself: the values of secret flags are
+// hidden wherever argv is logged, and anywhere else they turn up.
+func (o *Options) RegisterSecrets() {
+	for name, kind := range secretFlags {
+		logger.AddSecretFlag(name, kind)
+	}
+	if o.SetToken != "-" {
+		logger.AddSecret(logger.SecretToken, o.SetToken)
+	}
+	logger.AddSecret(logger.SecretClientSecret, o.InitClientSecret)
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+)
+
+// Usage prints the help for the command line opts was parsed from
+func Usage(w io.Writer, opts *Options) {
+	inv := &invocation{opts: opts, cmd: lookupCommand(opts.Command), program: opts.program}
+	inv.usage(w)
+}
+
+// usage prints the help for the command line: the command's own when it has
+// one (or "help <command>" asked for one), the full listing otherwise
+func (inv *invocation) usage(w io.Writer) {
+	cmd := inv.cmd
+	if inv.topic != nil {
+		cmd = inv.topic
+	}
+	if cmd != nil && cmd.name != "help" {
+		commandUsage(w, inv.program, cmd, cmd.flagSet(newFlagSet(inv.program, &Options{}, w)))
+		return
+	}
+	programUsage(w, inv.program, newFlagSet(inv.program, &Options{}, w))
+}
+
+// commandUsage prints the usage of one subcommand and the flags it takes
+func commandUsage(w io.Writer, program string, cmd *command, fs *flag.FlagSet) {
+	name := cmd.name
+	if cmd.args != "" {
+		name += " " + cmd.args
+	}
+	fmt.Fprintf(w, "Usage: %s %s [OPTIONS] [config.toml]\n\n", program, name)
+	fmt.Fprintf(w, "%s\n\n", cmd.summary)
+	fmt.Fprintf(w, "Options:\n")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
+
+// programUsage prints the full usage: commands, every flag and examples
+func programUsage(w io.Writer, program string, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Mixcloud Updater v%s\n\n", constants.Version)
+	fmt.Fprintf(w, "Automatically updates Mixcloud show descriptions with formatted tracklists from CUE files.\n")
+	fmt.Fprintf(w, "Uses a unified config-driven architecture for batch and single-show processing.\n\n")
+	fmt.Fprintf(w, "Usage:\n")
+	fmt.Fprintf(w, "  %s [config.toml]                    # Process all enabled shows\n", program)
+	fmt.Fprintf(w, "  %s [OPTIONS] [config.toml]          # Process with options\n", program)
+	fmt.Fprintf(w, "  %s COMMAND [OPTIONS] [config.toml]  # Run a command, see %s help COMMAND\n\n", program, program)
+	fmt.Fprintf(w, "Commands:\n%s\n", commandList())
+	fmt.Fprintf(w, "Options:\n")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fmt.Fprintf(w, "\nExamples:\n")
+	fmt.Fprintf(w, "  # Process all enabled shows from config\n")
+	fmt.Fprintf(w, "  %s config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Process specific show by alias\n")
+	fmt.Fprintf(w, "  %s -show nnw config.toml\n", program)
+	fmt.Fprintf(w, "  %s -show \"newer-new-wave\" config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Smoke-test the two highest-priority shows\n")
+	fmt.Fprintf(w, "  %s -limit 2 -dry-run config.toml\n", program)
+	fmt.Fprintf(w, "\n  # From cron: wait up to 10 minutes for an overlapping run instead of exiting\n")
+	fmt.Fprintf(w, "  %s -wait-lock 600 config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Stream JSON progress events for a wrapper app\n")
+	fmt.Fprintf(w, "  %s -progress-json config.toml > events.ndjson\n", program)
+	fmt.Fprintf(w, "\n  # Process every show in a show_group (all-or-nothing with group_atomic = true)\n")
+	fmt.Fprintf(w, "  %s -group festival-2025 config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Publish a show's archive oldest first, ten episodes per run\n")
+	fmt.Fprintf(w, "  %s -backfill nnw -backfill-dir /archive/nnw -backfill-limit 10 config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Override show date (format must match show's date_format)\n")
+	fmt.Fprintf(w, "  %s -show nnw -date \"6/28/2025\" config.toml\n", program)
+	fmt.Fprintf(w, "  %s -show nnw -date \"last friday\" config.toml  # Relative to today in the station timezone\n", program)
+	fmt.Fprintf(w, "  %s -show nnw -date \"6/14/2025,6/21/2025\" config.toml  # One run per date, needs {date} in cue_file_pattern\n", program)
+	fmt.Fprintf(w, "\n  # Preview without updating\n")
+	fmt.Fprintf(w, "  %s -dry-run config.toml\n", program)
+	fmt.Fprintf(w, "  %s -show sounds-like -dry-run config.toml\n", program)
+	fmt.Fprintf(w, "  %s preview -show sounds-like config.toml    # The same as a command\n", program)
+	fmt.Fprintf(w, "  %s -dry-run -output preview.txt config.toml  # Full descriptions to file\n", program)
+	fmt.Fprintf(w, "  %s -digest digest.md config.toml            # Also write every show's tracklist to one document\n", program)
+	fmt.Fprintf(w, "  %s -dry-run -verify config.toml             # Also check each show URL exists on Mixcloud\n", program)
+	fmt.Fprintf(w, "  %s -compare-templates classic,detailed -compare-output compare/ config.toml  # Both renders and a diff per show\n", program)
+	fmt.Fprintf(w, "  %s -script -show nnw -dry-run config.toml > desc.txt  # Just the description on stdout\n", program)
+	fmt.Fprintf(w, "\n  # Rename shows that set update_name (preview with -dry-run -verify first)\n")
+	fmt.Fprintf(w, "  %s -show sounds-like -confirm config.toml\n", program)
+	fmt.Fprintf(w, "\n  # During a Mixcloud outage: archive and queue every description, publish them later\n")
+	fmt.Fprintf(w, "  %s -offline config.toml\n", program)
+	fmt.Fprintf(w, "  %s -retry-failed config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Finish a run that was killed partway, skipping the shows it already published\n")
+	fmt.Fprintf(w, "  %s -resume config.toml\n", program)
+	fmt.Fprintf(w, "\n  # List available shows and templates\n")
+	fmt.Fprintf(w, "  %s -list-shows config.toml\n", program)
+	fmt.Fprintf(w, "  %s -list-templates config.toml\n", program)
+	fmt.Fprintf(w, "  %s list shows config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Check each enabled show's CUE file resolves and parses, without publishing\n")
+	fmt.Fprintf(w, "  %s -list-shows -resolve config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Find unused templates, dead CUE patterns and other config cruft\n")
+	fmt.Fprintf(w, "  %s -lint config.toml\n", program)
+	fmt.Fprintf(w, "  %s validate config.toml\n", program)
+	fmt.Fprintf(w, "  %s -lint -strict-config config.toml          # Unknown keys are errors, not warnings\n", program)
+	fmt.Fprintf(w, "\n  # Move the OAuth secrets out of the config file into the OS keychain\n")
+	fmt.Fprintf(w, "  %s -migrate-credentials config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Provision a host without a browser, with a token authorized elsewhere\n")
+	fmt.Fprintf(w, "  %s -set-token - config.toml < token.txt\n", program)
+	fmt.Fprintf(w, "  %s auth set-token - config.toml < token.txt\n", program)
+	fmt.Fprintf(w, "\n  # Check the whole pipeline, from config to Mixcloud connectivity\n")
+	fmt.Fprintf(w, "  %s -doctor config.toml\n", program)
+	fmt.Fprintf(w, "  %s -doctor -json config.toml > doctor.json   # For monitoring\n", program)
+	fmt.Fprintf(w, "\n  # Find which show picks up a CUE file\n")
+	fmt.Fprintf(w, "  %s -which-show MYR40705.cue config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Check which filtering rule, if any, excludes a track\n")
+	fmt.Fprintf(w, "  %s -test-filter -artist \"NWR Sweeper\" -title \"Top of Hour\" config.toml\n", program)
+	fmt.Fprintf(w, "  %s -test-filter -filter-csv plays.csv -output verdicts.csv config.toml\n", program)
+	fmt.Fprintf(w, "\n  # List the environment variables that override config values\n")
+	fmt.Fprintf(w, "  %s -print-env-vars config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Show the config in force once the shared overlay is merged in\n")
+	fmt.Fprintf(w, "  %s -print-effective-config config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Check templates against golden files (add -update-golden to accept changes)\n")
+	fmt.Fprintf(w, "  %s -test-templates config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Create a starter config interactively, or from flags in provisioning scripts\n")
+	fmt.Fprintf(w, "  %s -init config.toml\n", program)
+	fmt.Fprintf(w, "  %s -init -no-prompt -init-station \"NWR\" -init-username nwr -init-client-id ID -init-client-secret SECRET \\\n", program)
+	fmt.Fprintf(w, "      -init-cue-dir /data/cue -init-show-key jazz -init-show-name \"Jazz - {date}\" -init-show-pattern \"JAZZ_*.cue\" config.toml\n")
+	fmt.Fprintf(w, "\n  # Check when each show was last published\n")
+	fmt.Fprintf(w, "  %s -status config.toml\n", program)
+	fmt.Fprintf(w, "\n  # What was published for a show, newest first\n")
+	fmt.Fprintf(w, "  %s -history nnw -n 20 config.toml\n", program)
+	fmt.Fprintf(w, "  %s -history nnw -json config.toml > nnw-history.json\n", program)
+	fmt.Fprintf(w, "\n  # Check whether a newer release is available\n")
+	fmt.Fprintf(w, "  %s -check-update config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Use specific template override\n")
+	fmt.Fprintf(w, "  %s -show morning -template detailed config.toml\n", program)
+	fmt.Fprintf(w, "\n  # Automation with cron (process all shows)\n")
+	fmt.Fprintf(w, "  0 */2 * * * /path/to/mixcloud-updater /path/to/config.toml\n")
+}
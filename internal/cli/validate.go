@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/dateutil"
+)
+
+// Validate checks the options against each other and the config path. It
+// trims -show and -backfill and turns -compare-templates into a dry run.
+func Validate(opts *Options) error {
+	// Validate and check config file
+	if err := validateConfigFile(opts.ConfigPath); err != nil {
+		return fmt.Errorf("config file validation failed: %w", err)
+	}
+
+	if opts.ShowAlias != "" && opts.ShowGroup != "" {
+		return fmt.Errorf("-show and -group cannot be used together")
+	}
+
+	// Several dates republish one show's episodes, each finding its CUE file by date
+	if len(DateList(opts.DateOverride)) > 1 && opts.ShowAlias == "" {
+		return fmt.Errorf("-date with several dates requires -show")
+	}
+
+	// -limit only applies to batch processing of all enabled shows
+	if opts.ShowLimit > 0 && opts.ShowAlias != "" {
+		return fmt.Errorf("-limit cannot be used with -show")
+	}
+	if opts.ShowLimit > 0 && opts.ShowGroup != "" {
+		return fmt.Errorf("-limit cannot be used with -group")
+	}
+
+	// -backfill picks its own show, dates and limit
+	if opts.BackfillShow != "" {
+		switch {
+		case opts.ShowAlias != "":
+			return fmt.Errorf("-backfill cannot be used with -show")
+		case opts.ShowGroup != "":
+			return fmt.Errorf("-backfill cannot be used with -group")
+		case opts.ShowLimit > 0:
+			return fmt.Errorf("-backfill cannot be used with -limit (use -backfill-limit)")
+		case opts.DateOverride != "":
+			return fmt.Errorf("-backfill cannot be used with -date (episode dates come from the CUE files)")
+		case opts.TemplateName != "":
+			return fmt.Errorf("-backfill cannot be used with -template")
+		}
+		if err := validateShowAlias(opts.BackfillShow); err != nil {
+			return fmt.Errorf("backfill show validation failed: %w", err)
+		}
+		opts.BackfillShow = strings.TrimSpace(opts.BackfillShow)
+		if opts.BackfillSince != "" {
+			if _, err := dateutil.ParseFlexibleDate(opts.BackfillSince); err != nil {
+				return fmt.Errorf("invalid -backfill-since: %w", err)
+			}
+		}
+	} else if opts.BackfillDir != "" || opts.BackfillLimit > 0 || opts.BackfillSince != "" {
+		return fmt.Errorf("-backfill-dir, -backfill-limit and -backfill-since require -backfill")
+	}
+	if opts.BackfillLimit < 0 {
+		return fmt.Errorf("-backfill-limit must not be negative")
+	}
+	if opts.MaxConsecutiveFailures < -1 || opts.MaxTotalFailures < -1 {
+		return fmt.Errorf("-max-consecutive-failures and -max-total-failures must not be negative")
+	}
+
+	// -compare-templates is a dry run with a second render per show
+	if opts.CompareTemplates != "" {
+		if _, err := ParseCompareTemplates(opts.CompareTemplates); err != nil {
+			return err
+		}
+		switch {
+		case opts.TemplateName != "":
+			return fmt.Errorf("-compare-templates cannot be used with -template")
+		case opts.Offline:
+			return fmt.Errorf("-compare-templates cannot be used with -offline (comparisons are dry runs)")
+		case opts.RetryFailed:
+			return fmt.Errorf("-compare-templates cannot be used with -retry-failed (queued descriptions are already rendered)")
+		}
+		opts.DryRun = true
+	} else if opts.CompareOutput != "" {
+		return fmt.Errorf("-compare-output requires -compare-templates")
+	}
+
+	if opts.TestFilter {
+		if opts.FilterCSV == "" && opts.FilterArtist == "" && opts.FilterTitle == "" {
+			return fmt.Errorf("-test-filter needs -artist and/or -title, or -filter-csv")
+		}
+		if opts.FilterCSV != "" && (opts.FilterArtist != "" || opts.FilterTitle != "" || opts.FilterGenre != "") {
+			return fmt.Errorf("-filter-csv cannot be used with -artist, -title or -genre")
+		}
+	} else if opts.FilterArtist != "" || opts.FilterTitle != "" || opts.FilterGenre != "" || opts.FilterCSV != "" {
+		return fmt.Errorf("-artist, -title, -genre and -filter-csv require -test-filter")
+	}
+
+	if opts.JSON && !opts.Doctor && opts.ShowHistory == "" {
+		return fmt.Errorf("-json requires -doctor or -history")
+	}
+
+	if opts.Script && opts.ProgressJSON && opts.ProgressFile == "" {
+		return fmt.Errorf("-script cannot be used with -progress-json on stdout (add -progress-file)")
+	}
+
+	if opts.ShowHistory != "" {
+		if strings.HasPrefix(opts.ShowHistory, "-") {
+			return fmt.Errorf("-history needs a show name or alias, e.g. -history nnw")
+		}
+		if opts.HistoryCount < 0 {
+			return fmt.Errorf("-n must be 0 or more")
+		}
+	}
+
+	if opts.WaitLock < 0 {
+		return fmt.Errorf("-wait-lock must be 0 or more seconds")
+	}
+
+	if opts.Verify && !opts.DryRun {
+		return fmt.Errorf("-verify requires -dry-run (live runs always verify)")
+	}
+
+	// -offline queues what a live run would publish
+	if opts.Offline {
+		switch {
+		case opts.DryRun:
+			return fmt.Errorf("-offline cannot be used with -dry-run (dry runs never publish; -offline queues the descriptions)")
+		case opts.ShowGroup != "":
+			return fmt.Errorf("-offline cannot be used with -group")
+		case opts.BackfillShow != "":
+			return fmt.Errorf("-offline cannot be used with -backfill")
+		}
+	}
+
+	// -retry-failed publishes the queue as it was rendered
+	if opts.RetryFailed {
+		switch {
+		case opts.Offline:
+			return fmt.Errorf("-retry-failed cannot be used with -offline")
+		case opts.ShowAlias != "" || opts.ShowGroup != "" || opts.BackfillShow != "":
+			return fmt.Errorf("-retry-failed cannot be used with -show, -group or -backfill (it publishes every queued update)")
+		case opts.ShowLimit > 0 || opts.DateOverride != "" || opts.TemplateName != "":
+			return fmt.Errorf("-retry-failed cannot be used with -limit, -date or -template (queued descriptions are already rendered)")
+		}
+	}
+
+	// -resume and -no-resume pick up the checkpoint of a run of all enabled shows
+	if opts.Resume || opts.NoResume {
+		switch {
+		case opts.Resume && opts.NoResume:
+			return fmt.Errorf("-resume cannot be used with -no-resume")
+		case opts.ShowAlias != "" || opts.ShowGroup != "" || opts.BackfillShow != "" || opts.RetryFailed:
+			return fmt.Errorf("-resume and -no-resume cannot be used with -show, -group, -backfill or -retry-failed (only runs of all enabled shows are checkpointed)")
+		}
+	}
+
+	if opts.Resolve && !opts.ListShows {
+		return fmt.Errorf("-resolve can only be used with -list-shows")
+	}
+
+	// -digest combines the shows of a batch run
+	if opts.DigestPath != "" && opts.ShowAlias != "" && len(DateList(opts.DateOverride)) <= 1 {
+		return fmt.Errorf("-digest cannot be used with -show for a single date (a digest combines the shows of a batch run)")
+	}
+
+	if opts.TimeOffset != "" {
+		if opts.ShowAlias == "" {
+			return fmt.Errorf("-time-offset requires -show (offsets differ from show to show)")
+		}
+		if _, err := config.ParseTimeOffset(opts.TimeOffset); err != nil {
+			return fmt.Errorf("invalid -time-offset: %w", err)
+		}
+	}
+
+	// Validate show alias format if provided
+	if opts.ShowAlias != "" {
+		if err := validateShowAlias(opts.ShowAlias); err != nil {
+			return fmt.Errorf("show alias validation failed: %w", err)
+		}
+		opts.ShowAlias = strings.TrimSpace(opts.ShowAlias)
+	}
+
+	return nil
+}
+
+// validateShowAlias checks if the show alias format is valid
+func validateShowAlias(alias string) error {
+	// Check if show alias is not just whitespace
+	trimmed := strings.TrimSpace(alias)
+	if trimmed == "" {
+		return fmt.Errorf("show alias cannot be empty or just whitespace")
+	}
+
+	// Check reasonable length limits
+	if len(trimmed) < 1 {
+		return fmt.Errorf("show alias too short (minimum 1 character): %q", trimmed)
+	}
+
+	if len(trimmed) > 50 {
+		return fmt.Errorf("show alias too long (maximum 50 characters): %q", trimmed)
+	}
+
+	return nil
+}
+
+// validateConfigFile checks if the config file exists or can be created
+func validateConfigFile(filePath string) error {
+	// Clean the path
+	cleanPath := filepath.Clean(filePath)
+
+	// Check if file exists
+	info, err := os.Stat(cleanPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Config file doesn't exist - check if directory is writable for creating default
+			dir := filepath.Dir(cleanPath)
+			if dirInfo, dirErr := os.Stat(dir); dirErr != nil {
+				return fmt.Errorf("config file directory does not exist: %s", dir)
+			} else if !dirInfo.IsDir() {
+				return fmt.Errorf("config file directory path is not a directory: %s", dir)
+			}
+			// Directory exists, we can create config file later if needed
+			return nil
+		}
+		return fmt.Errorf("cannot access config file: %w", err)
+	}
+
+	// Config file exists - check if it's readable
+	if info.IsDir() {
+		return fmt.Errorf("config file path is a directory, not a file: %s", cleanPath)
+	}
+
+	file, err := os.Open(cleanPath)
+	if err != nil {
+		return fmt.Errorf("config file is not readable: %w", err)
+	}
+	file.Close()
+
+	return nil
+}
+
+// DateList splits a -date value into its comma-separated dates; a single date
+// gives one element and "" none
+func DateList(value string) []string {
+	var dates []string
+	for _, date := range strings.Split(value, ",") {
+		if date = strings.TrimSpace(date); date != "" {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// ParseCompareTemplates splits a -compare-templates value into its two
+// template names, e.g. "classic,detailed"
+func ParseCompareTemplates(value string) ([]string, error) {
+	names := strings.Split(value, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+	if len(names) != 2 || names[0] == "" || names[1] == "" {
+		return nil, fmt.Errorf("-compare-templates needs two template names, e.g. -compare-templates classic,detailed")
+	}
+	if names[0] == names[1] {
+		return nil, fmt.Errorf("-compare-templates needs two different templates, got %s twice", names[0])
+	}
+	return names, nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textnorm"
+)
+
+// AIDEV-NOTE: Configs edited in Notepad or Word arrive with smart quotes,
+// non-breaking spaces or Windows-1252 encoding, which TOML reports as byte
+// offsets. LoadConfig names the affected lines instead; -fix-config repairs them.
+
+// EncodingError reports a config that failed to parse and contains characters
+// substituted by a word processor or Windows editor
+type EncodingError struct {
+	Path   string
+	Issues []textnorm.Issue
+	Err    error // The underlying TOML error
+}
+
+func (e *EncodingError) Error() string {
+	return fmt.Sprintf("%s contains characters a word processor substituted for plain ASCII on %s; "+
+		"retype them or run with -fix-config (TOML error: %v)", e.Path, textnorm.Describe(e.Issues), e.Err)
+}
+
+// Unwrap lets errors.Is match ErrInvalidFormat
+func (e *EncodingError) Unwrap() error {
+	return ErrInvalidFormat
+}
+
+// Lines returns the affected line numbers
+func (e *EncodingError) Lines() []int {
+	return textnorm.Lines(e.Issues)
+}
+
+// FixResult describes a repair made by FixConfigFile
+type FixResult struct {
+	Lines       []int  // Lines that were rewritten
+	StrippedBOM bool   // A leading byte order mark was removed
+	BackupPath  string // Copy of the original file
+}
+
+// Changed reports whether the file was rewritten
+func (r FixResult) Changed() bool {
+	return len(r.Lines) > 0 || r.StrippedBOM
+}
+
+// FixConfigFile rewrites a BOM, smart quotes, non-breaking spaces and
+// Windows-1252 text in the config file at path to plain ASCII/UTF-8, after
+// copying the original to a timestamped backup. The file is left untouched
+// when there is nothing to fix or the repaired file still isn't valid TOML.
+func FixConfigFile(path string) (FixResult, error) {
+	var result FixResult
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result, fmt.Errorf("reading config file %s: %w", path, err)
+	}
+
+	result.StrippedBOM = textnorm.HasBOM(data)
+	result.Lines = textnorm.Lines(textnorm.Scan(data))
+	if !result.Changed() {
+		return result, nil
+	}
+
+	fixed := textnorm.Normalize(data)
+	var check Config
+	if err := toml.Unmarshal(fixed, &check); err != nil {
+		return FixResult{}, fmt.Errorf("%s is still invalid after replacing smart quotes, left unchanged: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return FixResult{}, fmt.Errorf("reading config file %s: %w", path, err)
+	}
+	result.BackupPath = fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(result.BackupPath, data, info.Mode().Perm()); err != nil {
+		return FixResult{}, fmt.Errorf("backing up config file: %w", err)
+	}
+	if err := os.WriteFile(path, fixed, info.Mode().Perm()); err != nil {
+		return FixResult{}, fmt.Errorf("writing fixed config file: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfigWordProcessorDamage(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		wantLines []int // nil when the config should load
+	}{
+		{"bom.toml", nil},
+		{"smart_quotes.toml", []int{2, 11}},
+		{"combined.toml", []int{2, 6, 11}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			cfg, err := LoadConfig(filepath.Join("testdata", tt.fixture))
+			if tt.wantLines == nil {
+				if err != nil {
+					t.Fatalf("LoadConfig() error = %v", err)
+				}
+				if cfg.Station.Name != "Now Wave Radio" {
+					t.Errorf("Station.Name = %q", cfg.Station.Name)
+				}
+				return
+			}
+
+			var encErr *EncodingError
+			if !errors.As(err, &encErr) {
+				t.Fatalf("LoadConfig() error = %v, want an EncodingError", err)
+			}
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Error("EncodingError should match ErrInvalidFormat")
+			}
+			if got := encErr.Lines(); !reflect.DeepEqual(got, tt.wantLines) {
+				t.Errorf("Lines() = %v, want %v", got, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestFixConfigFile(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		wantLines []int
+		wantBOM   bool
+	}{
+		{"bom.toml", nil, true},
+		{"smart_quotes.toml", []int{2, 11}, false},
+		{"combined.toml", []int{2, 6, 11}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			original, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(path, original, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := FixConfigFile(path)
+			if err != nil {
+				t.Fatalf("FixConfigFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Lines, tt.wantLines) || result.StrippedBOM != tt.wantBOM {
+				t.Errorf("result = %+v, want lines %v, BOM %v", result, tt.wantLines, tt.wantBOM)
+			}
+
+			backup, err := os.ReadFile(result.BackupPath)
+			if err != nil {
+				t.Fatalf("reading backup: %v", err)
+			}
+			if string(backup) != string(original) {
+				t.Error("backup differs from the original file")
+			}
+
+			cfg, err := LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig() after fix error = %v", err)
+			}
+			if cfg.Station.Name != "Now Wave Radio" {
+				t.Errorf("Station.Name = %q", cfg.Station.Name)
+			}
+			if tt.wantLines != nil && cfg.Shows["sounds-like"].ShowNamePattern != "Sounds Like 'Round Midnight - {date}" {
+				t.Errorf("ShowNamePattern = %q", cfg.Shows["sounds-like"].ShowNamePattern)
+			}
+		})
+	}
+}
+
+func TestFixConfigFileLeavesFileAlone(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"nothing to fix", "[station]\nname = \"Now Wave Radio\"\n", false},
+		{"still invalid after fixing", "[station]\nname = “Now Wave Radio\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := FixConfigFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FixConfigFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result.Changed() {
+				t.Errorf("result = %+v, want no change", result)
+			}
+
+			data, _ := os.ReadFile(path)
+			if string(data) != tt.content {
+				t.Error("config file was modified")
+			}
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 1 {
+				t.Errorf("got %d files, want no backup", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AIDEV-NOTE: Environment overrides are derived from the toml tags rather than
+// listed by hand, so every string, bool and int setting - including nested
+// sections like [logging] - can be overridden without code changes. Lists and
+// maps ([filtering] lists, [shows], [templates.config]) are left out; show
+// enablement gets its own NWRMIXCLOUD_SHOWS_<KEY>_ENABLED form.
+
+// EnvPrefix starts every environment variable that overrides the config
+const EnvPrefix = "NWRMIXCLOUD_"
+
+// ErrInvalidEnvironment is returned when an override can't be converted to its field's type
+var ErrInvalidEnvironment = errors.New("invalid environment variable override")
+
+// EnvVar describes one environment variable the config recognizes
+type EnvVar struct {
+	Name string // e.g. "NWRMIXCLOUD_LOGGING_LEVEL"
+	Key  string // Config key it overrides, e.g. "logging.level"
+	Type string // "string", "bool" or "int"
+
+	field reflect.Value // Settable field the value is written to
+}
+
+// EnvVars lists the recognized environment variables, sorted by name. Show
+// enablement variables are included for the shows in c.
+func (c *Config) EnvVars() []EnvVar {
+	var vars []EnvVar
+	collectEnvVars(reflect.ValueOf(c).Elem(), nil, &vars)
+
+	for showKey := range c.Shows {
+		vars = append(vars, EnvVar{
+			Name: envName([]string{"shows", showKey, "enabled"}),
+			Key:  "shows." + showKey + ".enabled",
+			Type: "bool",
+		})
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// collectEnvVars adds the string, bool and int fields of a struct, recursing
+// into nested structs. path holds the toml keys of the enclosing sections.
+func collectEnvVars(v reflect.Value, path []string, vars *[]EnvVar) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if tag == "" || tag == "-" || !field.IsExported() {
+			continue
+		}
+		fieldPath := append(append([]string(nil), path...), tag)
+
+		var typeName string
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			collectEnvVars(v.Field(i), fieldPath, vars)
+			continue
+		case reflect.String:
+			typeName = "string"
+		case reflect.Bool:
+			typeName = "bool"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			typeName = "int"
+		default:
+			continue // Lists and maps aren't overridable
+		}
+
+		*vars = append(*vars, EnvVar{
+			Name:  envName(fieldPath),
+			Key:   strings.Join(fieldPath, "."),
+			Type:  typeName,
+			field: v.Field(i),
+		})
+	}
+}
+
+// envName builds NWRMIXCLOUD_<PATH> in upper snake case, e.g.
+// ["shows", "sounds-like", "enabled"] → NWRMIXCLOUD_SHOWS_SOUNDS_LIKE_ENABLED
+func envName(path []string) string {
+	name := strings.ToUpper(strings.Join(path, "_"))
+	return EnvPrefix + strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name)
+}
+
+// ApplyEnvironmentOverrides overrides config values from NWRMIXCLOUD_ environment
+// variables (see EnvVars). Empty variables are ignored. Every value that doesn't
+// convert to its field's type is reported in the returned error; unrecognized
+// NWRMIXCLOUD_ variables are added to Warnings.
+func (c *Config) ApplyEnvironmentOverrides() error {
+	var problems []string
+	known := make(map[string]bool)
+
+	for _, envVar := range c.EnvVars() {
+		known[envVar.Name] = true
+		value := os.Getenv(envVar.Name)
+		if value == "" {
+			continue
+		}
+
+		if !envVar.field.IsValid() {
+			// Show enablement - shows are stored by value in the map
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s=%q: want true or false", envVar.Name, value))
+				continue
+			}
+			showKey := strings.TrimSuffix(strings.TrimPrefix(envVar.Key, "shows."), ".enabled")
+			showCfg := c.Shows[showKey]
+			showCfg.Enabled = enabled
+			c.Shows[showKey] = showCfg
+			continue
+		}
+
+		if err := setEnvField(envVar.field, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s=%q: %v", envVar.Name, value, err))
+		}
+	}
+
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, EnvPrefix) && !known[name] {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("unrecognized environment variable %s (-print-env-vars lists the supported ones)", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%w: %s", ErrInvalidEnvironment, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// setEnvField converts value to the field's type and stores it
+func setEnvField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("want true or false")
+		}
+		field.SetBool(b)
+	default:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("want a whole number")
+		}
+		field.SetInt(n)
+	}
+	return nil
+}
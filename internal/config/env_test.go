@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyEnvironmentOverrides(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		check func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "nested logger section",
+			env:  map[string]string{"NWRMIXCLOUD_LOGGING_LEVEL": "debug", "NWRMIXCLOUD_LOGGING_CONSOLE_OUTPUT": "false"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Logging.Level != "debug" || cfg.Logging.ConsoleOutput {
+					t.Errorf("Logging = %+v, want level debug without console output", cfg.Logging)
+				}
+			},
+		},
+		{
+			name: "int field",
+			env:  map[string]string{"NWRMIXCLOUD_PROCESSING_BATCH_SIZE": "12"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Processing.BatchSize != 12 {
+					t.Errorf("BatchSize = %d, want 12", cfg.Processing.BatchSize)
+				}
+			},
+		},
+		{
+			name: "existing variable names",
+			env: map[string]string{
+				"NWRMIXCLOUD_STATION_MIXCLOUD_USERNAME": "nwr",
+				"NWRMIXCLOUD_OAUTH_CLIENT_SECRET":       "secret",
+				"NWRMIXCLOUD_PATHS_CUE_FILE_DIRECTORY":  "/legacy",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Station.MixcloudUsername != "nwr" || cfg.OAuth.ClientSecret != "secret" || cfg.Paths.CueFileDirectory != "/legacy" {
+					t.Errorf("overrides not applied: station %+v, paths %+v", cfg.Station, cfg.Paths)
+				}
+			},
+		},
+		{
+			name: "show enablement",
+			env:  map[string]string{"NWRMIXCLOUD_SHOWS_SOUNDS_LIKE_ENABLED": "false", "NWRMIXCLOUD_SHOWS_MORNING_ENABLED": "1"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Shows["sounds-like"].Enabled || !cfg.Shows["morning"].Enabled {
+					t.Errorf("sounds-like enabled = %v, morning enabled = %v", cfg.Shows["sounds-like"].Enabled, cfg.Shows["morning"].Enabled)
+				}
+				if cfg.Shows["sounds-like"].ShowNamePattern != "Sounds Like" {
+					t.Errorf("show config lost its other fields: %+v", cfg.Shows["sounds-like"])
+				}
+			},
+		},
+		{
+			name: "empty values are ignored",
+			env:  map[string]string{"NWRMIXCLOUD_STATION_NAME": ""},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Station.Name != "Test FM" {
+					t.Errorf("Station.Name = %q, want it unchanged", cfg.Station.Name)
+				}
+			},
+		},
+		{
+			name: "unrecognized variable is a warning",
+			env:  map[string]string{"NWRMIXCLOUD_PROCESING_BATCH_SIZE": "3"},
+			check: func(t *testing.T, cfg *Config) {
+				if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "NWRMIXCLOUD_PROCESING_BATCH_SIZE") {
+					t.Errorf("Warnings = %v, want one naming the misspelled variable", cfg.Warnings)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			cfg := envTestConfig()
+			if err := cfg.ApplyEnvironmentOverrides(); err != nil {
+				t.Fatalf("ApplyEnvironmentOverrides() error = %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestApplyEnvironmentOverridesErrors(t *testing.T) {
+	t.Setenv("NWRMIXCLOUD_PROCESSING_BATCH_SIZE", "five")
+	t.Setenv("NWRMIXCLOUD_PROCESSING_AUTO_PROCESS", "yes please")
+	t.Setenv("NWRMIXCLOUD_SHOWS_MORNING_ENABLED", "maybe")
+
+	err := envTestConfig().ApplyEnvironmentOverrides()
+	if !errors.Is(err, ErrInvalidEnvironment) {
+		t.Fatalf("error = %v, want ErrInvalidEnvironment", err)
+	}
+	for _, want := range []string{
+		`NWRMIXCLOUD_PROCESSING_BATCH_SIZE="five": want a whole number`,
+		`NWRMIXCLOUD_PROCESSING_AUTO_PROCESS="yes please": want true or false`,
+		`NWRMIXCLOUD_SHOWS_MORNING_ENABLED="maybe": want true or false`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't mention %q", err, want)
+		}
+	}
+}
+
+func TestEnvVars(t *testing.T) {
+	vars := envTestConfig().EnvVars()
+
+	byName := make(map[string]EnvVar, len(vars))
+	for _, envVar := range vars {
+		byName[envVar.Name] = envVar
+	}
+
+	tests := []struct {
+		name, key, typeName string
+	}{
+		{"NWRMIXCLOUD_LOGGING_LEVEL", "logging.level", "string"},
+		{"NWRMIXCLOUD_PROCESSING_BATCH_SIZE", "processing.batch_size", "int"},
+		{"NWRMIXCLOUD_TEMPLATES_DEFAULT", "templates.default", "string"},
+		{"NWRMIXCLOUD_SHOWS_SOUNDS_LIKE_ENABLED", "shows.sounds-like.enabled", "bool"},
+	}
+	for _, tt := range tests {
+		envVar, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("%s not listed", tt.name)
+			continue
+		}
+		if envVar.Key != tt.key || envVar.Type != tt.typeName {
+			t.Errorf("%s = %s (%s), want %s (%s)", tt.name, envVar.Key, envVar.Type, tt.key, tt.typeName)
+		}
+	}
+
+	for _, unsupported := range []string{"NWRMIXCLOUD_FILTERING_EXCLUDED_ARTISTS", "NWRMIXCLOUD_WARNINGS"} {
+		if _, ok := byName[unsupported]; ok {
+			t.Errorf("%s listed, but lists can't be overridden", unsupported)
+		}
+	}
+}
+
+func envTestConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.Station.Name = "Test FM"
+	cfg.Shows = map[string]ShowConfig{
+		"sounds-like": {ShowNamePattern: "Sounds Like", Enabled: true},
+		"morning":     {ShowNamePattern: "Morning"},
+	}
+	return cfg
+}
@@ -0,0 +1,382 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nowwaveradio/mixcloud-updater/internal/httpclient"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textnorm"
+)
+
+// AIDEV-NOTE: Sites sharing most of their configuration point [config] at one
+// TOML fragment. It is merged over the local file as TOML tables, before the
+// config is decoded, so a key the local file leaves out is simply absent rather
+// than a zero value. The overlay wins in the shared sections (templates,
+// filtering, shows); everywhere else it only fills in keys the local file
+// leaves out, so station identity and credentials stay per site. Each good
+// overlay is cached next to the state file and used while its source is
+// unreachable; with no cache the site runs on its local file alone. The
+// overlay is never written back: SaveConfig writes the local values only.
+
+// OverlayConfig is the [config] section: a shared TOML fragment merged over this file
+type OverlayConfig struct {
+	OverlayURL     string   `toml:"overlay_url"`             // http(s) URL of the overlay
+	OverlayPath    string   `toml:"overlay_path"`            // Or a file, e.g. on a shared drive; relative to the config file
+	LockedSections []string `toml:"overlay_locked_sections"` // Top-level sections the overlay may not change
+}
+
+// Source returns the configured overlay URL or path, "" when there is none
+func (o OverlayConfig) Source() string {
+	if o.OverlayURL != "" {
+		return o.OverlayURL
+	}
+	return o.OverlayPath
+}
+
+// OverlayTimeout bounds an overlay fetch; an unreachable host falls back to the cache quickly
+const OverlayTimeout = 10 * time.Second
+
+// overlayMaxSize caps how much of an overlay is read
+const overlayMaxSize = 1 << 20
+
+// DefaultOverlayCacheFilename is the last good overlay, kept next to the state file
+const DefaultOverlayCacheFilename = "mixcloud-updater-overlay.toml"
+
+// overlaySections are the sections whose values an overlay replaces
+var overlaySections = map[string]bool{"templates": true, "filtering": true, "shows": true}
+
+// LockableSections are the sections overlay_locked_sections may name
+var LockableSections = []string{"station", "oauth", "filtering", "paths", "templates", "formatting", "shows", "processing", "network", "logging"}
+
+// overlayResult is a merged overlay
+type overlayResult struct {
+	config   Config   // Local file and overlay merged, as decoded
+	status   string   // Where the overlay came from
+	warnings []string // Fetch fallbacks and overlay keys that were ignored
+}
+
+// overlayFetches remembers each URL's response for the process, so the repeated
+// loads of one run fetch it once
+var overlayFetches sync.Map // URL → overlayFetch
+
+type overlayFetch struct {
+	data []byte
+	err  error
+}
+
+// validate checks the [config] section
+func (o OverlayConfig) validate() error {
+	if o.OverlayURL != "" && o.OverlayPath != "" {
+		return ConfigError{Field: "overlay_url", Message: "set overlay_url or overlay_path, not both"}
+	}
+	if o.OverlayURL != "" && !isOverlayURL(o.OverlayURL) {
+		return ConfigError{Field: "overlay_url", Message: fmt.Sprintf("%q must be an http:// or https:// URL", o.OverlayURL)}
+	}
+	for _, section := range o.LockedSections {
+		if !containsString(LockableSections, section) {
+			return ConfigError{Field: "overlay_locked_sections", Message: fmt.Sprintf("unknown section %q (valid: %s)",
+				section, strings.Join(LockableSections, ", "))}
+		}
+	}
+	return nil
+}
+
+// containsString reports whether list holds value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isOverlayURL reports whether source is fetched over HTTP rather than read from disk
+func isOverlayURL(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// applyOverlay merges the overlay configured in loaded, the decoded local file
+// data, over that file. It returns the merged TOML, or nil when no overlay
+// could be read and the local file stands alone.
+func applyOverlay(configPath string, data []byte, loaded *Config) ([]byte, overlayResult, error) {
+	settings := loaded.Overlay
+	if err := settings.validate(); err != nil {
+		return nil, overlayResult{}, err
+	}
+	source := settings.OverlayURL
+	if source == "" {
+		source = settings.OverlayPath
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(filepath.Dir(configPath), source)
+		}
+	}
+	cachePath := filepath.Join(filepath.Dir(state.ResolvePath(loaded.Processing.StateFile, configPath)), DefaultOverlayCacheFilename)
+
+	var result overlayResult
+	overlayData := loadOverlay(source, cachePath, loaded.Network.ProxyURL, &result)
+	if overlayData == nil {
+		return nil, result, nil
+	}
+
+	var overlayConfig Config
+	md, err := toml.Decode(string(overlayData), &overlayConfig)
+	if err != nil {
+		return nil, result, fmt.Errorf("%w: overlay %s - %v", ErrInvalidFormat, source, err)
+	}
+	for _, key := range unknownKeys(md, overlayData) {
+		result.warnings = append(result.warnings, fmt.Sprintf("unknown config key %s in overlay %s is ignored", key, source))
+	}
+	if md.IsDefined("config") {
+		result.warnings = append(result.warnings, fmt.Sprintf("[config] in overlay %s is ignored, only the local file sets it", source))
+	}
+
+	var localTables, overlayTables map[string]interface{}
+	if _, err := toml.Decode(string(data), &localTables); err != nil {
+		return nil, result, fmt.Errorf("%w: %s - %v", ErrInvalidFormat, configPath, err)
+	}
+	if _, err := toml.Decode(string(overlayData), &overlayTables); err != nil {
+		return nil, result, fmt.Errorf("%w: overlay %s - %v", ErrInvalidFormat, source, err)
+	}
+
+	var merged bytes.Buffer
+	if err := toml.NewEncoder(&merged).Encode(mergeOverlay(localTables, overlayTables, settings.LockedSections)); err != nil {
+		return nil, result, fmt.Errorf("merging overlay %s: %w", source, err)
+	}
+	if _, err := toml.Decode(merged.String(), &result.config); err != nil {
+		return nil, result, fmt.Errorf("merging overlay %s: %w", source, err)
+	}
+	return merged.Bytes(), result, nil
+}
+
+// loadOverlay reads the overlay from source, caching it at cachePath. When
+// source can't be read the cached copy is returned, or nil without one; either
+// way a warning is added to result.
+func loadOverlay(source, cachePath, proxyURL string, result *overlayResult) []byte {
+	data, err := readOverlay(source, proxyURL)
+	if err == nil {
+		result.status = "overlay " + source
+		if err := storeOverlayCache(cachePath, data); err != nil {
+			result.warnings = append(result.warnings, fmt.Sprintf("overlay cache not updated: %v", err))
+		}
+		return data
+	}
+
+	cached, cacheErr := os.ReadFile(cachePath)
+	switch {
+	case cacheErr == nil:
+		cachedAt := "at an unknown time"
+		if info, statErr := os.Stat(cachePath); statErr == nil {
+			cachedAt = info.ModTime().Format("2006-01-02 15:04")
+		}
+		result.status = fmt.Sprintf("cached copy of overlay %s from %s (%s)", source, cachedAt, cachePath)
+		result.warnings = append(result.warnings, fmt.Sprintf("overlay unavailable, using the copy cached %s: %v", cachedAt, err))
+		return cached
+	case errors.Is(cacheErr, os.ErrNotExist):
+		result.status = "local config only, overlay " + source + " unavailable"
+		result.warnings = append(result.warnings, fmt.Sprintf("overlay unavailable and never cached, using the local config only: %v", err))
+	default:
+		result.status = "local config only, overlay " + source + " unavailable"
+		result.warnings = append(result.warnings, fmt.Sprintf("overlay unavailable and its cache unreadable, using the local config only: %v",
+			errors.Join(err, cacheErr)))
+	}
+	return nil
+}
+
+// readOverlay reads the overlay file or fetches its URL, rejecting anything that isn't TOML
+func readOverlay(source, proxyURL string) ([]byte, error) {
+	var data []byte
+	var err error
+	if isOverlayURL(source) {
+		data, err = fetchOverlay(source, proxyURL)
+	} else if data, err = os.ReadFile(source); err != nil {
+		err = fmt.Errorf("reading overlay: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data = textnorm.StripBOM(data)
+	var tables map[string]interface{}
+	if _, err := toml.Decode(string(data), &tables); err != nil {
+		return nil, fmt.Errorf("%w: overlay %s - %v", ErrInvalidFormat, source, err)
+	}
+	return data, nil
+}
+
+// fetchOverlay fetches an overlay URL once per process
+func fetchOverlay(url, proxyURL string) ([]byte, error) {
+	if fetched, ok := overlayFetches.Load(url); ok {
+		return fetched.(overlayFetch).data, fetched.(overlayFetch).err
+	}
+	data, err := fetchOverlayURL(url, proxyURL)
+	overlayFetches.Store(url, overlayFetch{data: data, err: err})
+	return data, err
+}
+
+func fetchOverlayURL(url, proxyURL string) ([]byte, error) {
+	client, err := httpclient.New(proxyURL, OverlayTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("fetching overlay: %w", err)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating overlay request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching overlay: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching overlay: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, overlayMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading overlay: %w", err)
+	}
+	return data, nil
+}
+
+// storeOverlayCache writes the cache atomically (write to temp file, then rename)
+func storeOverlayCache(cachePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("creating overlay cache directory: %w", err)
+	}
+	tmpPath := cachePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing overlay cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("replacing overlay cache: %w", err)
+	}
+	return nil
+}
+
+// mergeOverlay merges the overlay's top-level tables into the local file's. The
+// overlay's [config] and the sections in locked are skipped.
+func mergeOverlay(local, overlay map[string]interface{}, locked []string) map[string]interface{} {
+	merged := make(map[string]interface{}, len(local)+len(overlay))
+	for section, value := range local {
+		merged[section] = value
+	}
+	for section, value := range overlay {
+		if section == "config" || containsString(locked, section) {
+			continue
+		}
+		existing, ok := merged[section]
+		switch {
+		case !ok:
+			merged[section] = value
+		case section == "shows":
+			merged[section] = mergeShowTables(existing, value)
+		default:
+			merged[section] = mergeValues(existing, value, overlaySections[section])
+		}
+	}
+	return merged
+}
+
+// mergeValues merges an overlay value into a local one. Tables are merged key
+// by key; for any other value, lists included, the overlay's replaces the local
+// one when overlayWins and only fills in a missing key otherwise.
+func mergeValues(local, overlay interface{}, overlayWins bool) interface{} {
+	localTable, localOK := local.(map[string]interface{})
+	overlayTable, overlayOK := overlay.(map[string]interface{})
+	if !localOK || !overlayOK {
+		if overlayWins {
+			return overlay
+		}
+		return local
+	}
+
+	merged := make(map[string]interface{}, len(localTable)+len(overlayTable))
+	for key, value := range localTable {
+		merged[key] = value
+	}
+	for key, value := range overlayTable {
+		if existing, ok := merged[key]; ok {
+			merged[key] = mergeValues(existing, value, overlayWins)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// mergeShowTables merges the overlay's [shows] into the local ones. A show both
+// define is merged key by key with the overlay's values winning, matched like
+// show keys are (case and spacing ignored) and kept under the local key.
+func mergeShowTables(local, overlay interface{}) interface{} {
+	localShows, localOK := local.(map[string]interface{})
+	overlayShows, overlayOK := overlay.(map[string]interface{})
+	if !localOK || !overlayOK {
+		return mergeValues(local, overlay, true)
+	}
+
+	merged := make(map[string]interface{}, len(localShows)+len(overlayShows))
+	localKeys := make(map[string]string, len(localShows))
+	for key, show := range localShows {
+		merged[key] = show
+		localKeys[CanonicalShowKey(key)] = key
+	}
+
+	// Sorted so overlay keys colliding with each other resolve the same way on every load
+	keys := make([]string, 0, len(overlayShows))
+	for key := range overlayShows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if localKey, ok := localKeys[CanonicalShowKey(key)]; ok {
+			merged[localKey] = mergeValues(merged[localKey], overlayShows[key], true)
+		} else {
+			merged[key] = overlayShows[key]
+		}
+	}
+	return merged
+}
+
+// OverlayStatus describes where the merged overlay came from, "" without one
+func (c *Config) OverlayStatus() string {
+	return c.overlayStatus
+}
+
+// redactedValue replaces secrets in EffectiveTOML
+const redactedValue = "[REDACTED]"
+
+// EffectiveTOML returns the config in force - local file, overlay, defaults and
+// environment overrides merged - as TOML, with the OAuth secrets and proxy
+// password redacted
+func (c *Config) EffectiveTOML() ([]byte, error) {
+	effective := *c
+	effective.Shows = c.showsAsWritten()
+	for _, secret := range []*string{&effective.OAuth.ClientSecret, &effective.OAuth.AccessToken, &effective.OAuth.RefreshToken} {
+		if *secret != "" {
+			*secret = redactedValue
+		}
+	}
+	if effective.Network.ProxyURL != "" {
+		effective.Network.ProxyURL = httpclient.Redact(effective.Network.ProxyURL)
+	}
+
+	data, err := toml.Marshal(&effective)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config to TOML: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/textnorm"
+)
+
+// AIDEV-NOTE: Show keys are matched case-insensitively, so [shows."Música Nueva"]
+// is stored under its canonical key "música nueva" and every map lookup, state
+// entry and history entry uses that. ShowConfig.DisplayKey keeps the key as
+// written for listings and for SaveConfig, which must not rewrite the user's
+// table names.
+
+// ErrDuplicateShowKey is returned when two show keys only differ in case,
+// accents' encoding or spacing
+var ErrDuplicateShowKey = errors.New("duplicate show key")
+
+// CanonicalShowKey returns the form show keys are stored and looked up in,
+// e.g. "Música Nueva" → "música nueva"
+func CanonicalShowKey(key string) string {
+	return textnorm.FoldKey(key)
+}
+
+// normalizeShowKeys re-keys c.Shows by canonical key, recording each key as
+// written in its DisplayKey
+func (c *Config) normalizeShowKeys() error {
+	if len(c.Shows) == 0 {
+		return nil
+	}
+
+	// Sorted so a collision is reported the same way on every load
+	keys := make([]string, 0, len(c.Shows))
+	for key := range c.Shows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	shows := make(map[string]ShowConfig, len(c.Shows))
+	for _, key := range keys {
+		canonical := CanonicalShowKey(key)
+		if existing, ok := shows[canonical]; ok {
+			return fmt.Errorf("%w: [shows.%q] and [shows.%q] are the same show once case and spacing are ignored", ErrDuplicateShowKey, existing.DisplayKey, key)
+		}
+		show := c.Shows[key]
+		show.DisplayKey = key
+		shows[canonical] = show
+	}
+	c.Shows = shows
+	return nil
+}
+
+// ShowDisplayKey returns a show's key as written in the config, or showKey
+// itself for shows that weren't loaded from a file
+func (c *Config) ShowDisplayKey(showKey string) string {
+	if show, ok := c.Shows[showKey]; ok && show.DisplayKey != "" {
+		return show.DisplayKey
+	}
+	return showKey
+}
+
+// showsAsWritten returns c.Shows keyed as written in the config
+func (c *Config) showsAsWritten() map[string]ShowConfig {
+	if c.Shows == nil {
+		return nil
+	}
+	shows := make(map[string]ShowConfig, len(c.Shows))
+	for key, show := range c.Shows {
+		shows[c.ShowDisplayKey(key)] = show
+	}
+	return shows
+}
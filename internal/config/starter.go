@@ -0,0 +1,325 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/errorutil"
+)
+
+// AIDEV-NOTE: The starter config is rendered from a text template rather than
+// toml.Marshal so every field can carry an explanatory comment. LoadConfig must
+// accept the output unchanged - starter_test.go guards that.
+
+// Built-in starter templates offered by -init
+const (
+	StarterTemplateNone     = ""
+	StarterTemplateDetailed = "detailed"
+	StarterTemplateMinimal  = "minimal"
+)
+
+// StarterTemplates are the template definitions -init can add to a new config
+var StarterTemplates = map[string]TemplateConfig{
+	StarterTemplateDetailed: {
+		Header: "Tracklist for {{.ShowTitle}} ({{.ShowDate}}):\n\n",
+		Track:  "{{.Index}}. {{.StartTime}} - {{.Artist}} - {{.Title}}\n",
+		Footer: "\n{{.TrackCount}} tracks on {{.StationName}}",
+	},
+	StarterTemplateMinimal: {
+		Track: "{{.StartTime}} {{.Artist}} - {{.Title}}\n",
+	},
+}
+
+// ErrConfigExists is returned when -init would overwrite an existing config
+var ErrConfigExists = errors.New("config file already exists")
+
+// StarterConfig holds the answers used to generate a commented starter config
+type StarterConfig struct {
+	StationName      string
+	MixcloudUsername string
+	ClientID         string
+	ClientSecret     string
+	CueFileDirectory string
+	Show             StarterShow
+	Template         string // StarterTemplateNone uses the built-in classic format
+}
+
+// StarterShow is the first show defined in a starter config
+type StarterShow struct {
+	Key         string   // e.g. "sounds-like"
+	NamePattern string   // e.g. "Sounds Like - {date}"
+	FilePattern string   // e.g. "MYR_SoundsLike_*.cue"
+	Aliases     []string // e.g. ["sl"]
+}
+
+var (
+	showKeyRegex          = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	mixcloudUsernameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// ValidateRequired rejects empty or whitespace-only answers
+func ValidateRequired(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("a value is required")
+	}
+	return nil
+}
+
+// ValidateMixcloudUsername checks a username as it appears in profile URLs
+func ValidateMixcloudUsername(username string) error {
+	if !mixcloudUsernameRegex.MatchString(username) {
+		return fmt.Errorf("use the name from your profile URL (letters, digits, '-' and '_'), e.g. \"yourstation\"")
+	}
+	return nil
+}
+
+// ValidateCueDirectory checks that the CUE file directory exists
+func ValidateCueDirectory(dir string) error {
+	if err := ValidateRequired(dir); err != nil {
+		return err
+	}
+	return errorutil.ValidateDirectory(dir, "checking CUE directory", false)
+}
+
+// ValidateShowKey checks a show key is usable as a TOML table name and CLI argument
+func ValidateShowKey(key string) error {
+	if !showKeyRegex.MatchString(key) {
+		return fmt.Errorf("use lowercase letters, digits, '-' and '_' (e.g. \"sounds-like\")")
+	}
+	return nil
+}
+
+// ValidateFilePattern checks a CUE file glob pattern
+func ValidateFilePattern(pattern string) error {
+	if err := ValidateRequired(pattern); err != nil {
+		return err
+	}
+	if strings.ContainsAny(pattern, `/\`) {
+		return fmt.Errorf("pattern is matched inside the CUE directory and can't contain a path")
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern: %w", err)
+	}
+	return nil
+}
+
+// ValidateStarterTemplate checks a starter template choice
+func ValidateStarterTemplate(name string) error {
+	if name == StarterTemplateNone {
+		return nil
+	}
+	if _, ok := StarterTemplates[name]; !ok {
+		return fmt.Errorf("choose %q, %q or leave empty for the classic format", StarterTemplateDetailed, StarterTemplateMinimal)
+	}
+	return nil
+}
+
+// ParseAliases splits a comma-separated alias list, dropping empty entries
+func ParseAliases(value string) []string {
+	var aliases []string
+	for _, alias := range strings.Split(value, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// Validate checks all answers, reporting the first problem found
+func (s *StarterConfig) Validate() error {
+	checks := []struct {
+		field    string
+		value    string
+		validate func(string) error
+	}{
+		{"station name", s.StationName, ValidateRequired},
+		{"Mixcloud username", s.MixcloudUsername, ValidateMixcloudUsername},
+		{"OAuth client ID", s.ClientID, ValidateRequired},
+		{"OAuth client secret", s.ClientSecret, ValidateRequired},
+		{"CUE file directory", s.CueFileDirectory, ValidateCueDirectory},
+		{"show key", s.Show.Key, ValidateShowKey},
+		{"show name pattern", s.Show.NamePattern, ValidateRequired},
+		{"CUE file pattern", s.Show.FilePattern, ValidateFilePattern},
+		{"template", s.Template, ValidateStarterTemplate},
+	}
+
+	for _, check := range checks {
+		if err := check.validate(check.value); err != nil {
+			return fmt.Errorf("%s: %w", check.field, err)
+		}
+	}
+	return nil
+}
+
+// RenderStarterConfig renders a fully commented config.toml from the answers
+func RenderStarterConfig(s *StarterConfig) ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		*StarterConfig
+		TemplateName string
+		TemplateDef  TemplateConfig
+	}{StarterConfig: s}
+	if s.Template != StarterTemplateNone {
+		data.TemplateName = s.Template
+		data.TemplateDef = StarterTemplates[s.Template]
+	}
+
+	var buf bytes.Buffer
+	if err := starterConfigTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering starter config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteStarterConfig renders the starter config to path. An existing file is
+// only replaced when overwrite is set.
+func WriteStarterConfig(path string, s *StarterConfig, overwrite bool) error {
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("%w: %s (use -force to overwrite)", ErrConfigExists, path)
+	}
+
+	content, err := RenderStarterConfig(s)
+	if err != nil {
+		return err
+	}
+
+	return errorutil.SafeWriteFile(path, content, "writing starter config", true)
+}
+
+// tomlString quotes a value as a TOML basic string
+func tomlString(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlStringArray formats values as a TOML array of basic strings
+func tomlStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = tomlString(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+var starterConfigTemplate = template.Must(template.New("starter").Funcs(template.FuncMap{
+	"toml":      tomlString,
+	"tomlArray": tomlStringArray,
+}).Parse(`# Mixcloud Updater Configuration
+# Generated by -init. Every setting is explained below; see config.toml.example
+# in the project for the full list of options.
+
+[station]
+# Your radio station name (available to templates as {{"{{"}}.StationName{{"}}"}})
+name = {{toml .StationName}}
+
+# The part after mixcloud.com/ in your profile URL
+mixcloud_username = {{toml .MixcloudUsername}}
+
+[oauth]
+# OAuth 2.0 credentials from https://www.mixcloud.com/developers/create/
+client_id = {{toml .ClientID}}
+client_secret = {{toml .ClientSecret}}
+
+# Managed automatically - filled in by the authorization flow on first run
+access_token = ""
+refresh_token = ""
+
+[filtering]
+# Tracks matching these are left out of tracklists (station IDs, ads, ...)
+# Exact artist/title matches (case-insensitive)
+excluded_artists = ["Station ID", "Commercial", "Sweeper", "Promo", "Jingle"]
+excluded_titles = ["Station Identification", "Commercial Break"]
+# Substring matches, e.g. every artist containing "Sponsor"
+# excluded_artists_contains = ["Sponsor"]
+
+# Regular expression matches
+excluded_artist_patterns = ["(?i)sweeper", "(?i)station.*id"]
+excluded_title_patterns = ["(?i)advertisement", "(?i)sponsored.*by"]
+
+[processing]
+# Directory containing the CUE files written by your automation system
+# Windows users: use forward slashes, e.g. "C:/Myriad/Data"
+cue_file_directory = {{toml .CueFileDirectory}}
+batch_size = 5  # Shows processed per batch in batch mode
+
+[logging]
+enabled = true                                   # Write a log file for every run
+directory = "logs"                               # Relative to the working directory, or absolute
+filename_pattern = "mixcloud-updater-YYYYMMDD.log" # One file per day
+level = "info"                                   # debug, info, warn or error
+max_files = 30                                   # Log files to keep (0 = no limit)
+max_size_mb = 10                                 # Rotate above this size (0 = no limit)
+console_output = true                            # Also print log lines to the console
+console_verbosity = "normal"                     # "quiet" or "silent" keep cron mail short
+
+[templates]
+# Template used by shows without their own "template" setting.
+# "classic" is the built-in format: 00:00 - "Title" by Artist
+# "numbered", "detailed" and "compact" are built in too
+default = {{if .TemplateName}}{{toml .TemplateName}}{{else}}"classic"{{end}}
+
+# Templates receive .ShowTitle, .ShowDate, .StationName and .TrackCount in the
+# header and footer, and .Index, .StartTime, .Artist, .Title and .Genre per track
+{{- if .TemplateName}}
+
+[templates.config.{{.TemplateName}}]
+{{- if .TemplateDef.Header}}
+header = {{toml .TemplateDef.Header}}
+{{- end}}
+track = {{toml .TemplateDef.Track}}
+{{- if .TemplateDef.Footer}}
+footer = {{toml .TemplateDef.Footer}}
+{{- end}}
+{{- end}}
+
+# Shows - each [shows.<key>] table is one show. Process a single show with
+# -show <key or alias>, or all enabled shows by running without -show.
+[shows.{{.Show.Key}}]
+# Mixcloud title; {date} is replaced with the show date formatted by date_format
+show_name_pattern = {{toml .Show.NamePattern}}
+
+# CUE files to use - the newest file matching the pattern is picked. A
+# {date:YYYYMMDD} in the pattern lets -date pick that day's file instead.
+cue_file_pattern = {{toml .Show.FilePattern}}
+
+# Short names accepted by -show
+aliases = {{tomlArray .Show.Aliases}}
+
+# Date format for {date}: M/D/YYYY gives 6/28/2025, MMMM D YYYY gives June 28 2025
+date_format = "M/D/YYYY"
+
+enabled = true  # Include in batch processing
+priority = 1    # Processing order in batch mode
+`))
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func validStarterConfig(t *testing.T) *StarterConfig {
+	t.Helper()
+	return &StarterConfig{
+		StationName:      `Now Wave "Radio"`,
+		MixcloudUsername: "nowwaveradio",
+		ClientID:         "client-id",
+		ClientSecret:     `se\cret`,
+		CueFileDirectory: t.TempDir(),
+		Show: StarterShow{
+			Key:         "sounds-like",
+			NamePattern: "Sounds Like - {date}",
+			FilePattern: "MYR_SoundsLike_*.cue",
+			Aliases:     []string{"sl", "soundslike"},
+		},
+	}
+}
+
+func TestStarterConfigLoads(t *testing.T) {
+	for _, templateName := range []string{StarterTemplateNone, StarterTemplateDetailed, StarterTemplateMinimal} {
+		t.Run("template "+templateName, func(t *testing.T) {
+			starter := validStarterConfig(t)
+			starter.Template = templateName
+			path := filepath.Join(t.TempDir(), "config.toml")
+
+			if err := WriteStarterConfig(path, starter, false); err != nil {
+				t.Fatalf("WriteStarterConfig() error = %v", err)
+			}
+
+			// Strict, so the starter config can't drift from the known keys
+			cfg, err := LoadConfigWithOptions(path, LoadOptions{StrictConfig: true})
+			if err != nil {
+				t.Fatalf("LoadConfig() on generated config error = %v", err)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("generated config fails validation: %v", err)
+			}
+
+			if cfg.Station.Name != starter.StationName || cfg.OAuth.ClientSecret != starter.ClientSecret {
+				t.Errorf("escaped values not preserved: name=%q secret=%q", cfg.Station.Name, cfg.OAuth.ClientSecret)
+			}
+			if cfg.Processing.CueFileDirectory != starter.CueFileDirectory {
+				t.Errorf("cue_file_directory = %q, want %q", cfg.Processing.CueFileDirectory, starter.CueFileDirectory)
+			}
+
+			show, ok := cfg.Shows["sounds-like"]
+			if !ok {
+				t.Fatal("generated config is missing the show")
+			}
+			if show.ShowNamePattern != "Sounds Like - {date}" || show.CueFilePattern != "MYR_SoundsLike_*.cue" || !show.Enabled {
+				t.Errorf("show not generated correctly: %+v", show)
+			}
+			if !reflect.DeepEqual(show.Aliases, starter.Show.Aliases) {
+				t.Errorf("aliases = %v, want %v", show.Aliases, starter.Show.Aliases)
+			}
+
+			if templateName == StarterTemplateNone {
+				if cfg.Templates.Default != "classic" || len(cfg.Templates.Config) != 0 {
+					t.Errorf("expected classic default and no templates, got %q %v", cfg.Templates.Default, cfg.Templates.Config)
+				}
+				return
+			}
+			if cfg.Templates.Default != templateName {
+				t.Errorf("templates.default = %q, want %q", cfg.Templates.Default, templateName)
+			}
+			if cfg.Templates.Config[templateName] != StarterTemplates[templateName] {
+				t.Errorf("template %q = %+v, want %+v", templateName, cfg.Templates.Config[templateName], StarterTemplates[templateName])
+			}
+		})
+	}
+}
+
+func TestStarterConfigIsCommented(t *testing.T) {
+	content, err := RenderStarterConfig(validStarterConfig(t))
+	if err != nil {
+		t.Fatalf("RenderStarterConfig() error = %v", err)
+	}
+
+	for _, section := range []string{"[station]", "[oauth]", "[processing]", "[shows.sounds-like]"} {
+		idx := strings.Index(string(content), section)
+		if idx == -1 {
+			t.Fatalf("missing section %s", section)
+		}
+		// Each section is followed by an explanatory comment
+		rest := strings.TrimLeft(string(content[idx+len(section):]), "\n")
+		if !strings.HasPrefix(rest, "#") {
+			t.Errorf("section %s has no comment", section)
+		}
+	}
+}
+
+func TestWriteStarterConfigRefusesOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("# existing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteStarterConfig(path, validStarterConfig(t), false)
+	if !errors.Is(err, ErrConfigExists) {
+		t.Fatalf("WriteStarterConfig() error = %v, want ErrConfigExists", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# existing\n" {
+		t.Error("existing config was modified")
+	}
+
+	if err := WriteStarterConfig(path, validStarterConfig(t), true); err != nil {
+		t.Fatalf("WriteStarterConfig() with overwrite error = %v", err)
+	}
+}
+
+func TestStarterConfigValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(*StarterConfig)
+		wantField string
+	}{
+		{"missing station name", func(s *StarterConfig) { s.StationName = " " }, "station name"},
+		{"username with slash", func(s *StarterConfig) { s.MixcloudUsername = "mixcloud.com/me" }, "Mixcloud username"},
+		{"missing client secret", func(s *StarterConfig) { s.ClientSecret = "" }, "OAuth client secret"},
+		{"missing CUE directory", func(s *StarterConfig) { s.CueFileDirectory = "/nonexistent/cue/dir" }, "CUE file directory"},
+		{"show key with spaces", func(s *StarterConfig) { s.Show.Key = "Sounds Like" }, "show key"},
+		{"pattern with path", func(s *StarterConfig) { s.Show.FilePattern = "data/*.cue" }, "CUE file pattern"},
+		{"bad glob", func(s *StarterConfig) { s.Show.FilePattern = "[*.cue" }, "CUE file pattern"},
+		{"unknown template", func(s *StarterConfig) { s.Template = "fancy" }, "template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starter := validStarterConfig(t)
+			tt.modify(starter)
+
+			err := starter.Validate()
+			if err == nil {
+				t.Fatal("Validate() expected error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantField+":") {
+				t.Errorf("Validate() error = %q, want field %q", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	got := ParseAliases(" sl, soundslike ,,")
+	if want := []string{"sl", "soundslike"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAliases() = %v, want %v", got, want)
+	}
+	if got := ParseAliases(""); got != nil {
+		t.Errorf("ParseAliases(\"\") = %v, want nil", got)
+	}
+}
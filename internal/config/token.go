@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/credstore"
+)
+
+// AIDEV-NOTE: ClearStoredToken edits the [oauth] token lines in place instead
+// of going through SaveConfig, which re-marshals the whole file and drops its
+// comments, key order and formatting. An unattended run reacting to a revoked
+// token must not rewrite a config someone maintains by hand.
+
+// tableHeaderPattern matches a [table] or [[array]] header line
+var tableHeaderPattern = regexp.MustCompile(`^\s*\[\[?\s*([^\]]*?)\s*\]`)
+
+// tokenLinePattern matches an access_token or refresh_token line with a quoted
+// value, keeping what follows the value (e.g. a comment)
+var tokenLinePattern = regexp.MustCompile(`^(\s*(?:access_token|refresh_token)\s*=\s*)("[^"]*"|'[^']*')(.*)$`)
+
+// ClearStoredToken forgets the OAuth access and refresh tokens, in memory and
+// where they are stored, so the next run asks for authorization again. The
+// token values in the config file's [oauth] section are blanked, leaving every
+// other line as it was, and with the keychain credential store its token
+// entries are removed too. A token set through the environment can't be
+// cleared and is reported as an error.
+func (c *Config) ClearStoredToken(configPath string) error {
+	c.OAuth.AccessToken, c.OAuth.RefreshToken = "", ""
+	if c.local != nil {
+		c.local.OAuth.AccessToken, c.local.OAuth.RefreshToken = "", ""
+	}
+
+	if err := clearTokenInFile(configPath); err != nil {
+		return err
+	}
+	if c.UsesKeychain() {
+		// Empty tokens remove their entries; the client secret stays
+		if err := credstore.Save(configPath, credstore.Secrets{ClientSecret: c.OAuth.ClientSecret}); err != nil {
+			return fmt.Errorf("clearing stored token: %w", err)
+		}
+	}
+
+	if name := envName([]string{"oauth", "access_token"}); os.Getenv(name) != "" {
+		return fmt.Errorf("the access token comes from %s, which the next run will send again; unset it to authorize", name)
+	}
+	return nil
+}
+
+// clearTokenInFile blanks the [oauth] token values of the config file at path
+func clearTokenInFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("clearing stored token: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("clearing stored token: %w", err)
+	}
+
+	cleared, changed := clearTokenLines(string(data))
+	if !changed {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(cleared), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("clearing stored token in %s: %w", path, err)
+	}
+	return nil
+}
+
+// clearTokenLines replaces the access_token and refresh_token values of the
+// [oauth] table in content with "", reporting whether any value changed
+func clearTokenLines(content string) (string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	table := ""
+	changed := false
+	for i, line := range lines {
+		body := strings.TrimRight(line, "\r\n")
+		ending := line[len(body):]
+
+		if match := tableHeaderPattern.FindStringSubmatch(body); match != nil {
+			table = match[1]
+			continue
+		}
+		if table != "oauth" {
+			continue
+		}
+		match := tokenLinePattern.FindStringSubmatch(body)
+		if match == nil || match[2] == `""` || match[2] == `''` {
+			continue
+		}
+		lines[i] = match[1] + `""` + match[3] + ending
+		changed = true
+	}
+	return strings.Join(lines, ""), changed
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestClearTokenLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "oauth tokens blanked",
+			content:     "[oauth]\nclient_id = \"id\"\naccess_token = \"abc\"\nrefresh_token = 'def'\n",
+			want:        "[oauth]\nclient_id = \"id\"\naccess_token = \"\"\nrefresh_token = \"\"\n",
+			wantChanged: true,
+		},
+		{
+			name:        "comment and CRLF kept",
+			content:     "[oauth]\r\n  access_token   = \"abc\"  # from the browser flow\r\n",
+			want:        "[oauth]\r\n  access_token   = \"\"  # from the browser flow\r\n",
+			wantChanged: true,
+		},
+		{
+			name:    "other tables untouched",
+			content: "[station]\naccess_token = \"abc\"\n\n[oauth]\nclient_id = \"id\"\n\n[shows.nnw]\nrefresh_token = \"def\"\n",
+			want:    "[station]\naccess_token = \"abc\"\n\n[oauth]\nclient_id = \"id\"\n\n[shows.nnw]\nrefresh_token = \"def\"\n",
+		},
+		{
+			name:    "already blank",
+			content: "[oauth]\naccess_token = \"\"\n",
+			want:    "[oauth]\naccess_token = \"\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := clearTokenLines(tt.content)
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("clearTokenLines() = %q, %v; want %q, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestClearStoredToken(t *testing.T) {
+	content := `# Station config - keep this comment
+[station]
+name = "Test Station"   # aligned by hand
+mixcloud_username = "testuser"
+
+[oauth]
+client_id = "id"
+client_secret = "secret"
+access_token = "revoked-token"
+`
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if err := cfg.ClearStoredToken(path); err != nil {
+		t.Fatalf("ClearStoredToken() error = %v", err)
+	}
+	if cfg.OAuth.AccessToken != "" {
+		t.Errorf("in-memory access token = %q, want it cleared", cfg.OAuth.AccessToken)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := clearTokenLines(content)
+	if string(data) != want || want == content {
+		t.Errorf("config file =\n%s\nwant only the access token blanked:\n%s", data, want)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("config file mode = %v, %v; want 0600 kept", info.Mode().Perm(), err)
+	}
+
+	t.Setenv("NWRMIXCLOUD_OAUTH_ACCESS_TOKEN", "from-env")
+	if err := cfg.ClearStoredToken(path); err == nil {
+		t.Error("ClearStoredToken() succeeded with the token set in the environment")
+	}
+}
+
+func TestClearStoredTokenKeychain(t *testing.T) {
+	keyring.MockInit()
+	path := createTempConfigFile(t, `
+[oauth]
+client_id = "id"
+credential_store = "keychain"
+`)
+	defer os.Remove(path)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	cfg.OAuth.ClientSecret, cfg.OAuth.AccessToken = "secret", "revoked-token"
+	if err := SaveConfig(cfg, path); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	if err := cfg.ClearStoredToken(path); err != nil {
+		t.Fatalf("ClearStoredToken() error = %v", err)
+	}
+	reloaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() after clearing error = %v", err)
+	}
+	if reloaded.OAuth.AccessToken != "" || reloaded.OAuth.ClientSecret != "secret" {
+		t.Errorf("reloaded OAuth = %+v, want the token gone and the client secret kept", reloaded.OAuth)
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// AIDEV-NOTE: BurntSushi's decoder silently ignores keys without a matching
+// field, so a typo like exluded_artists just does nothing. LoadConfig compares
+// the file's keys against what was decoded and reports the rest. The decoder
+// doesn't expose key positions, so lines are found by a light scan of the file.
+
+// ErrUnknownKeys is returned by strict loads of a config with keys no setting reads
+var ErrUnknownKeys = errors.New("unknown configuration keys")
+
+// deprecatedKeyPrefixes are accepted from configs written for earlier versions.
+// They are reported by their own deprecation warnings, not as unknown keys.
+var deprecatedKeyPrefixes = []string{
+	"templates.templates", // See mergeLegacyTemplates
+}
+
+// UnknownKey is a config key that no setting reads, usually a typo
+type UnknownKey struct {
+	Key  string // Dotted path, e.g. "filtering.exluded_artists"
+	Line int    // Line the key is set on, 0 when it couldn't be located
+}
+
+func (k UnknownKey) String() string {
+	if k.Line == 0 {
+		return k.Key
+	}
+	return fmt.Sprintf("%s (line %d)", k.Key, k.Line)
+}
+
+// unknownKeysError reports every unknown key in one ErrUnknownKeys error
+func unknownKeysError(path string, unknown []UnknownKey) error {
+	keys := make([]string, len(unknown))
+	for i, key := range unknown {
+		keys[i] = key.String()
+	}
+	return fmt.Errorf("%w in %s: %s", ErrUnknownKeys, path, strings.Join(keys, ", "))
+}
+
+// unknownKeys lists the keys in data that md didn't decode, in file order. An
+// unknown table is listed once rather than with each of its keys.
+func unknownKeys(md toml.MetaData, data []byte) []UnknownKey {
+	undecoded := md.Undecoded()
+	if len(undecoded) == 0 {
+		return nil
+	}
+
+	isUndecoded := make(map[string]bool, len(undecoded))
+	for _, key := range undecoded {
+		isUndecoded[strings.Join(key, ".")] = true
+	}
+
+	lines := keyLines(data)
+	var unknown []UnknownKey
+	for _, key := range undecoded {
+		if isDeprecatedKey(key) || isUndecoded[strings.Join(key[:len(key)-1], ".")] {
+			continue
+		}
+		dotted := strings.Join(key, ".")
+		unknown = append(unknown, UnknownKey{Key: dotted, Line: lines[dotted]})
+	}
+	return unknown
+}
+
+// isDeprecatedKey reports whether key is, or is inside, a deprecated key
+func isDeprecatedKey(key toml.Key) bool {
+	dotted := strings.Join(key, ".")
+	for _, prefix := range deprecatedKeyPrefixes {
+		if dotted == prefix || strings.HasPrefix(dotted, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// keyLines maps the dotted path of every table header and key assignment in a
+// TOML document to the line it first appears on. Multi-line strings are skipped;
+// keys inside inline tables aren't located.
+func keyLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	var table []string
+	inMultiline := ""
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if inMultiline != "" {
+			if strings.Count(line, inMultiline)%2 == 1 {
+				inMultiline = ""
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			header := strings.Trim(strings.SplitN(trimmed, "]", 2)[0], "[ ")
+			if strings.HasPrefix(trimmed, "[[") {
+				header = strings.Trim(strings.SplitN(trimmed, "]]", 2)[0], "[ ")
+			}
+			table = splitDottedKey(header)
+			recordKeyLine(lines, table, i+1)
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, "=")
+		if !found {
+			continue // Continuation of a multi-line array
+		}
+		path := append(append([]string(nil), table...), splitDottedKey(key)...)
+		recordKeyLine(lines, path, i+1)
+
+		for _, quote := range []string{`"""`, `'''`} {
+			if strings.Count(value, quote)%2 == 1 {
+				inMultiline = quote
+			}
+		}
+	}
+	return lines
+}
+
+// recordKeyLine records the line of a key path unless it was seen earlier
+func recordKeyLine(lines map[string]int, path []string, line int) {
+	dotted := strings.Join(path, ".")
+	if _, seen := lines[dotted]; !seen {
+		lines[dotted] = line
+	}
+}
+
+// splitDottedKey splits a TOML key like `shows."sounds-like".enabled` into its
+// parts, unquoted
+func splitDottedKey(key string) []string {
+	var parts []string
+	var current strings.Builder
+	quote := rune(0)
+	for _, r := range key {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(parts, strings.TrimSpace(current.String()))
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		tomlData string
+		want     []string
+	}{
+		{
+			name: "top-level section typo",
+			tomlData: `[station]
+name = "Test FM"
+
+[filterng]
+excluded_artists = ["Station ID"]
+`,
+			want: []string{"unknown config key filterng (line 4) is ignored"},
+		},
+		{
+			name: "key typo in a section",
+			tomlData: `[filtering]
+exluded_artists = ["Station ID"]
+`,
+			want: []string{"unknown config key filtering.exluded_artists (line 2) is ignored"},
+		},
+		{
+			name: "key typo in a show",
+			tomlData: `[shows.sounds-like]
+show_name_pattern = "Sounds Like"
+template = "detailed"
+enabeld = true
+`,
+			want: []string{"unknown config key shows.sounds-like.enabeld (line 4) is ignored"},
+		},
+		{
+			name: "key typo in logging",
+			tomlData: `[logging]
+enabled = true
+max_file = 10 # max_files
+`,
+			want: []string{"unknown config key logging.max_file (line 3) is ignored"},
+		},
+		{
+			name: "keys inside multi-line strings aren't keys",
+			tomlData: `[templates.config.detailed]
+header = """
+[Tracklist]
+heder = nope
+"""
+track = "{{.Title}}\n"
+trak = "{{.Artist}}\n"
+`,
+			want: []string{"unknown config key templates.config.detailed.trak (line 7) is ignored"},
+		},
+		{
+			name: "deprecated key is reported by its deprecation warning",
+			tomlData: `[templates.templates.legacy]
+track = "{{.Title}}\n"
+`,
+			want: []string{"[templates.templates] is deprecated, rename it to [templates.config]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(createTempConfigFile(t, tt.tomlData))
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Warnings, tt.want) {
+				t.Errorf("Warnings = %q, want %q", cfg.Warnings, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictConfig(t *testing.T) {
+	const typos = `[processing]
+batch_size = 3
+
+[shows.morning]
+show_name_pattern = "Morning"
+enabeld = true
+
+[logging]
+levle = "debug"
+`
+
+	tests := []struct {
+		name     string
+		tomlData string
+		opts     LoadOptions
+	}{
+		{"strict option", typos, LoadOptions{StrictConfig: true}},
+		{"processing.strict_config", strings.Replace(typos, "batch_size = 3", "strict_config = true", 1), LoadOptions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfigWithOptions(createTempConfigFile(t, tt.tomlData), tt.opts)
+			if !errors.Is(err, ErrUnknownKeys) {
+				t.Fatalf("error = %v, want ErrUnknownKeys", err)
+			}
+			for _, want := range []string{"shows.morning.enabeld (line 6)", "logging.levle (line 9)"} {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q doesn't mention %q", err, want)
+				}
+			}
+		})
+	}
+
+	// Known keys load cleanly in strict mode
+	cfg, err := LoadConfigWithOptions(createTempConfigFile(t, "[processing]\nstrict_config = true\nbatch_size = 3\n"), LoadOptions{})
+	if err != nil {
+		t.Fatalf("strict load of a valid config error = %v", err)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", cfg.Warnings)
+	}
+}
+
+func TestSplitDottedKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"shows.sounds-like", []string{"shows", "sounds-like"}},
+		{` shows . "late.night" .enabled `, []string{"shows", "late.night", "enabled"}},
+		{"'quoted key'", []string{"quoted key"}},
+	}
+	for _, tt := range tests {
+		if got := splitDottedKey(tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitDottedKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
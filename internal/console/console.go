@@ -0,0 +1,127 @@
+// Package console prints the CLI's user-facing text - banners, per-show
+// progress, previews and summaries - at the configured verbosity, so cron runs
+// can stay quiet while the log file keeps the full record.
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// AIDEV-NOTE: Everything people read on the terminal goes through this package
+// instead of fmt.Printf; only output that is the command's purpose (reports,
+// -json, -script descriptions) is written directly. Writers resolve os.Stdout
+// and os.Stderr on every write, like fmt.Printf, so -progress-json and -script
+// moving human output to stderr still applies. The interactive OAuth flow
+// prints here too; a URL the user must open goes through Errorf so a quiet run
+// still shows it.
+
+// Verbosity is how much the CLI prints (logging.console_verbosity)
+type Verbosity string
+
+// Values for logging.console_verbosity
+const (
+	Normal Verbosity = "normal" // Everything
+	Quiet  Verbosity = "quiet"  // Errors and the run's one-line outcome
+	Silent Verbosity = "silent" // Nothing; the exit code and log file tell what happened
+)
+
+var current atomic.Value // Verbosity, Normal until SetVerbosity
+
+// ParseVerbosity returns the verbosity named by s; "" is Normal
+func ParseVerbosity(s string) (Verbosity, error) {
+	switch v := Verbosity(s); v {
+	case "":
+		return Normal, nil
+	case Normal, Quiet, Silent:
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown console verbosity %q (want normal, quiet or silent)", s)
+}
+
+// SetVerbosity sets how much is printed from now on
+func SetVerbosity(v Verbosity) {
+	current.Store(v)
+}
+
+// Current returns the verbosity in effect
+func Current() Verbosity {
+	if v, ok := current.Load().(Verbosity); ok {
+		return v
+	}
+	return Normal
+}
+
+// stream writes to stdout or stderr while its verbosity check passes and
+// discards everything otherwise
+type stream struct {
+	show   func(Verbosity) bool
+	stderr bool
+}
+
+func (s stream) Write(p []byte) (int, error) {
+	if !s.show(Current()) {
+		return len(p), nil
+	}
+	if s.stderr {
+		return os.Stderr.Write(p)
+	}
+	return os.Stdout.Write(p)
+}
+
+var (
+	// Chrome receives progress, previews and summaries, printed at Normal only
+	Chrome io.Writer = stream{show: func(v Verbosity) bool { return v == Normal }}
+
+	// Content receives output that is the command's purpose, e.g. the dry-run
+	// description of an explicitly targeted show; it is printed at every verbosity
+	Content io.Writer = stream{show: func(Verbosity) bool { return true }}
+
+	// Failures receives per-show failures alongside the chrome on stdout,
+	// printed unless Silent
+	Failures io.Writer = stream{show: func(v Verbosity) bool { return v != Silent }}
+
+	warnings io.Writer = stream{show: func(v Verbosity) bool { return v == Normal }, stderr: true}
+	notes    io.Writer = stream{show: func(v Verbosity) bool { return v == Normal }, stderr: true}
+	errs     io.Writer = stream{show: func(v Verbosity) bool { return v != Silent }, stderr: true}
+	outcome  io.Writer = stream{show: func(v Verbosity) bool { return v == Quiet }}
+)
+
+// Printf prints chrome, see Chrome
+func Printf(format string, args ...interface{}) {
+	fmt.Fprintf(Chrome, format, args...)
+}
+
+// Println prints a line of chrome, see Chrome
+func Println(args ...interface{}) {
+	fmt.Fprintln(Chrome, args...)
+}
+
+// Failuref prints a per-show failure, see Failures
+func Failuref(format string, args ...interface{}) {
+	fmt.Fprintf(Failures, format, args...)
+}
+
+// Warnf prints a warning to stderr, at Normal only
+func Warnf(format string, args ...interface{}) {
+	fmt.Fprintf(warnings, format, args...)
+}
+
+// Notef prints chrome to stderr, at Normal only, for commands whose stdout
+// is their data, e.g. -test-filter's CSV
+func Notef(format string, args ...interface{}) {
+	fmt.Fprintf(notes, format, args...)
+}
+
+// Errorf prints an error to stderr, unless Silent
+func Errorf(format string, args ...interface{}) {
+	fmt.Fprintf(errs, format, args...)
+}
+
+// Outcome prints the run's one-line outcome. Only Quiet prints it: Normal
+// runs end with their full summary instead.
+func Outcome(line string) {
+	fmt.Fprintln(outcome, line)
+}
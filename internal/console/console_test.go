@@ -0,0 +1,106 @@
+package console
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// capture runs fn with os.Stdout and os.Stderr redirected and returns what it
+// printed to each
+func capture(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+	read := func(target **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := *target
+		*target = w
+		captured := make(chan string)
+		go func() {
+			data, _ := io.ReadAll(r)
+			captured <- string(data)
+		}()
+		return func() string {
+			*target = original
+			w.Close()
+			return <-captured
+		}
+	}
+	finishStdout, finishStderr := read(&os.Stdout), read(&os.Stderr)
+	fn()
+	return finishStdout(), finishStderr()
+}
+
+func TestVerbosity(t *testing.T) {
+	print := func() {
+		Printf("Processing %d shows\n", 3)
+		Println("✓ Done!")
+		Failuref("❌ Failed: %s\n", "nnw")
+		Warnf("Warning: %s\n", "stale lock")
+		Notef("%d of %d tracks excluded\n", 2, 9)
+		Errorf("Error: %s\n", "1 of 3 shows failed")
+		Content.Write([]byte("00:00 - \"Title\" by Artist\n"))
+		Outcome("batch: FAILURE - 1 of 3 shows failed")
+	}
+
+	tests := []struct {
+		verbosity  Verbosity
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			verbosity:  Normal,
+			wantStdout: "Processing 3 shows\n✓ Done!\n❌ Failed: nnw\n00:00 - \"Title\" by Artist\n",
+			wantStderr: "Warning: stale lock\n2 of 9 tracks excluded\nError: 1 of 3 shows failed\n",
+		},
+		{
+			verbosity:  Quiet,
+			wantStdout: "❌ Failed: nnw\n00:00 - \"Title\" by Artist\nbatch: FAILURE - 1 of 3 shows failed\n",
+			wantStderr: "Error: 1 of 3 shows failed\n",
+		},
+		{
+			verbosity:  Silent,
+			wantStdout: "00:00 - \"Title\" by Artist\n",
+			wantStderr: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.verbosity), func(t *testing.T) {
+			SetVerbosity(tt.verbosity)
+			t.Cleanup(func() { SetVerbosity(Normal) })
+
+			stdout, stderr := capture(t, print)
+			if stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.wantStdout)
+			}
+			if stderr != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestParseVerbosity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Verbosity
+		wantErr bool
+	}{
+		{"", Normal, false},
+		{"normal", Normal, false},
+		{"quiet", Quiet, false},
+		{"silent", Silent, false},
+		{"Quiet", "", true},
+		{"verbose", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVerbosity(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseVerbosity(%q) = %q, %v, want %q (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
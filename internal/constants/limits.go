@@ -7,6 +7,9 @@ const (
 	// MixcloudDescriptionLimit is the maximum character limit for show descriptions
 	MixcloudDescriptionLimit = 1000
 	
+	// MixcloudMaxTags is the most tags a Mixcloud show can have
+	MixcloudMaxTags = 5
+	
 	// MixcloudRateLimit defines the maximum requests per time window
 	MixcloudRateLimit = 60
 	MixcloudRateLimitWindow = time.Hour
@@ -17,14 +20,26 @@ const (
 	// DefaultTimeoutSeconds for HTTP requests
 	DefaultTimeoutSeconds = 30
 	
+	// DefaultExchangeTimeoutSeconds for the OAuth authorization code exchange
+	DefaultExchangeTimeoutSeconds = 30
+	
 	// DefaultRetryAttempts for failed requests
 	DefaultRetryAttempts = 3
 	
+	// DefaultRateLimitWarnRemaining is the API quota below which a run warns
+	DefaultRateLimitWarnRemaining = 10
+	
+	// MaxRetryAttempts caps processing.retry_attempts
+	MaxRetryAttempts = 10
+	
 	// DefaultRetryDelaySeconds for exponential backoff
 	DefaultRetryDelaySeconds = 1
 	
 	// MaxRetryDelaySeconds caps the exponential backoff
 	MaxRetryDelaySeconds = 30
+	
+	// RetryJitter varies each backoff wait by up to ± this fraction
+	RetryJitter = 0.25
 )
 
 // Processing and batch configuration
@@ -37,6 +52,27 @@ const (
 	
 	// DefaultProcessingTimeoutMinutes for individual show processing
 	DefaultProcessingTimeoutMinutes = 10
+	
+	// DefaultMaxBrokenTrackPercent of malformed CUE tracks a show tolerates
+	DefaultMaxBrokenTrackPercent = 20
+	
+	// DefaultHistoryEntries of publish history kept per show
+	DefaultHistoryEntries = 100
+	
+	// DefaultLockStaleMinutes after which another instance's lock file is taken over
+	DefaultLockStaleMinutes = 120
+	
+	// DefaultResumeWindowHours within which an interrupted batch run is offered for -resume
+	DefaultResumeWindowHours = 12
+	
+	// DefaultAnomalyThresholdPercent of its recent median a show's output can shrink to before it is flagged
+	DefaultAnomalyThresholdPercent = 60
+	
+	// DefaultAnomalyHistoryRuns of recent publishes the anomaly check takes the median of
+	DefaultAnomalyHistoryRuns = 5
+	
+	// DefaultMaxGenres listed in a template's .Genres
+	DefaultMaxGenres = 10
 )
 
 // File and logging configuration
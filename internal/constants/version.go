@@ -0,0 +1,4 @@
+package constants
+
+// Version is the release version, reported by -version and in provenance lines
+const Version = "1.0.0"
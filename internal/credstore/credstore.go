@@ -0,0 +1,104 @@
+// Package credstore keeps the OAuth client secret and tokens in the OS
+// credential store - the macOS Keychain, Windows Credential Manager or a Secret
+// Service (libsecret) keyring on Linux - instead of the plaintext config file.
+package credstore
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/zalando/go-keyring"
+)
+
+// AIDEV-NOTE: Entries are keyed by the config file's absolute path, so two
+// configs on one machine (e.g. two stations) keep separate credentials, and a
+// config reached through a relative path finds the same entries. Credential
+// stores are per user: a scheduled task must run as the user who migrated.
+
+// Values of oauth.credential_store
+const (
+	StoreFile     = "file"     // Secrets stay in the config file (default)
+	StoreKeychain = "keychain" // Secrets live in the OS credential store
+)
+
+// Secret names, used as the account of each entry
+const (
+	ClientSecret = "client_secret"
+	AccessToken  = "access_token"
+	RefreshToken = "refresh_token"
+)
+
+// servicePrefix starts the service name of every entry
+const servicePrefix = "mixcloud-updater:"
+
+// Secrets are the values kept in the credential store for one config
+type Secrets struct {
+	ClientSecret string
+	AccessToken  string
+	RefreshToken string
+}
+
+// ServiceName returns the service the entries of the config at configPath are
+// stored under
+func ServiceName(configPath string) string {
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configPath = abs
+	}
+	return servicePrefix + filepath.Clean(configPath)
+}
+
+// Available reports why the OS credential store can't be used, e.g. no Secret
+// Service running on Linux, or nil when it can
+func Available(configPath string) error {
+	_, err := keyring.Get(ServiceName(configPath), ClientSecret)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("OS credential store unavailable: %w", err)
+	}
+	return nil
+}
+
+// Load reads the secrets stored for the config at configPath. Secrets that
+// were never stored are "".
+func Load(configPath string) (Secrets, error) {
+	service := ServiceName(configPath)
+	var secrets Secrets
+	for name, value := range secrets.fields() {
+		stored, err := keyring.Get(service, name)
+		if errors.Is(err, keyring.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return Secrets{}, fmt.Errorf("reading %s from the OS credential store: %w", name, err)
+		}
+		*value = stored
+	}
+	return secrets, nil
+}
+
+// Save stores secrets for the config at configPath. An empty secret removes
+// its entry, so a cleared token doesn't linger in the store.
+func Save(configPath string, secrets Secrets) error {
+	service := ServiceName(configPath)
+	for name, value := range secrets.fields() {
+		if *value == "" {
+			if err := keyring.Delete(service, name); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+				return fmt.Errorf("removing %s from the OS credential store: %w", name, err)
+			}
+			continue
+		}
+		if err := keyring.Set(service, name, *value); err != nil {
+			return fmt.Errorf("writing %s to the OS credential store: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// fields maps each secret's name to its field in s
+func (s *Secrets) fields() map[string]*string {
+	return map[string]*string{
+		ClientSecret: &s.ClientSecret,
+		AccessToken:  &s.AccessToken,
+		RefreshToken: &s.RefreshToken,
+	}
+}
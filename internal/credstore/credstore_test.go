@@ -0,0 +1,65 @@
+package credstore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	keyring.MockInit()
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+
+	saved := Secrets{ClientSecret: "s3cret", AccessToken: "access", RefreshToken: "refresh"}
+	if err := Save(configPath, saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded != saved {
+		t.Errorf("Load() = %+v, want %+v", loaded, saved)
+	}
+
+	// Another config on the same machine has its own entries
+	other, err := Load(filepath.Join(t.TempDir(), "config.toml"))
+	if err != nil || other != (Secrets{}) {
+		t.Errorf("Load() of another config = %+v, %v, want nothing stored", other, err)
+	}
+
+	// Clearing a token removes its entry
+	saved.RefreshToken = ""
+	if err := Save(configPath, saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := keyring.Get(ServiceName(configPath), RefreshToken); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("refresh_token entry after clearing it: %v, want ErrNotFound", err)
+	}
+}
+
+func TestServiceNameResolvesRelativePaths(t *testing.T) {
+	t.Chdir(t.TempDir())
+	abs, err := filepath.Abs("config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ServiceName("./config.toml"), ServiceName(abs); got != want {
+		t.Errorf("ServiceName(./config.toml) = %q, want %q", got, want)
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	keyring.MockInit()
+	if err := Available("config.toml"); err != nil {
+		t.Errorf("Available() with a working store = %v", err)
+	}
+
+	keyring.MockInitWithError(errors.New("no Secret Service on the session bus"))
+	t.Cleanup(keyring.MockInit)
+	if err := Available("config.toml"); err == nil {
+		t.Error("Available() with a failing store succeeded")
+	}
+}
@@ -0,0 +1,85 @@
+package cue
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AIDEV-NOTE: When the playout logger crashes mid-show the CUE file just jumps from
+// one start time to a much later one. Gaps are measured between consecutive start
+// times, so each includes the earlier track's own length; thresholds need to allow
+// for the longest track a show plays.
+
+// Gap is a stretch between two consecutive track starts
+type Gap struct {
+	From   Track         // Track before the gap
+	To     Track         // Next track to start
+	Length time.Duration // Time between their start times
+}
+
+// GapReport summarizes the spacing of a CUE sheet's track start times
+type GapReport struct {
+	Largest time.Duration // Longest time between consecutive starts
+	Gaps    []Gap         // Gaps over the threshold, in start time order
+}
+
+// StartOffset parses the track's MM:SS start time into an offset from the start
+// of the show. ok is false when the start time is missing or malformed.
+func (t Track) StartOffset() (offset time.Duration, ok bool) {
+	return parseOffset(t.StartTime)
+}
+
+// PregapOffset parses the track's MM:SS INDEX 00 time like StartOffset
+func (t Track) PregapOffset() (offset time.Duration, ok bool) {
+	return parseOffset(t.PregapTime)
+}
+
+// parseOffset parses an MM:SS time into an offset from the start of the show
+func parseOffset(timeStr string) (offset time.Duration, ok bool) {
+	minutes, seconds, found := strings.Cut(timeStr, ":")
+	if !found || !isValidTimeFormat(timeStr) {
+		return 0, false
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, false
+	}
+	s, err := strconv.Atoi(seconds)
+	if err != nil || s >= 60 {
+		return 0, false
+	}
+	return time.Duration(m)*time.Minute + time.Duration(s)*time.Second, true
+}
+
+// FindGaps sorts tracks by start time and reports the largest gap between
+// consecutive starts and every gap longer than threshold. Tracks without a
+// usable start time are ignored.
+func FindGaps(tracks []Track, threshold time.Duration) GapReport {
+	type timedTrack struct {
+		track  Track
+		offset time.Duration
+	}
+	timed := make([]timedTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if offset, ok := track.StartOffset(); ok {
+			timed = append(timed, timedTrack{track, offset})
+		}
+	}
+	sort.SliceStable(timed, func(i, j int) bool {
+		return timed[i].offset < timed[j].offset
+	})
+
+	var report GapReport
+	for i := 1; i < len(timed); i++ {
+		length := timed[i].offset - timed[i-1].offset
+		if length > report.Largest {
+			report.Largest = length
+		}
+		if length > threshold {
+			report.Gaps = append(report.Gaps, Gap{From: timed[i-1].track, To: timed[i].track, Length: length})
+		}
+	}
+	return report
+}
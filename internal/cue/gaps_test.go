@@ -0,0 +1,106 @@
+package cue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartOffset(t *testing.T) {
+	tests := []struct {
+		startTime string
+		want      time.Duration
+		wantOK    bool
+	}{
+		{"00:00", 0, true},
+		{"04:48", 4*time.Minute + 48*time.Second, true},
+		{"125:07", 125*time.Minute + 7*time.Second, true},
+		{"", 0, false},
+		{"4:75", 0, false},
+		{"04:48:31", 0, false},
+		{"ab:cd", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.startTime, func(t *testing.T) {
+			got, ok := Track{StartTime: tt.startTime}.StartOffset()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("StartOffset() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFindGaps(t *testing.T) {
+	tracks := func(startTimes ...string) []Track {
+		result := make([]Track, len(startTimes))
+		for i, startTime := range startTimes {
+			result[i] = Track{Index: i + 1, StartTime: startTime}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		tracks      []Track
+		threshold   time.Duration
+		wantLargest time.Duration
+		wantGaps    [][2]int // From and To track indexes
+	}{
+		{
+			name:        "continuous show",
+			tracks:      tracks("00:00", "04:30", "09:10", "13:00"),
+			threshold:   15 * time.Minute,
+			wantLargest: 4*time.Minute + 40*time.Second,
+		},
+		{
+			name:        "logger crash",
+			tracks:      tracks("00:00", "04:30", "44:30", "48:00"),
+			threshold:   15 * time.Minute,
+			wantLargest: 40 * time.Minute,
+			wantGaps:    [][2]int{{2, 3}},
+		},
+		{
+			name:        "out of order tracks are sorted first",
+			tracks:      tracks("44:30", "00:00", "48:00", "04:30"),
+			threshold:   15 * time.Minute,
+			wantLargest: 40 * time.Minute,
+			wantGaps:    [][2]int{{4, 1}},
+		},
+		{
+			name:        "gap equal to the threshold is allowed",
+			tracks:      tracks("00:00", "15:00", "35:01"),
+			threshold:   15 * time.Minute,
+			wantLargest: 20*time.Minute + time.Second,
+			wantGaps:    [][2]int{{2, 3}},
+		},
+		{
+			name:        "tracks without start times are ignored",
+			tracks:      tracks("00:00", "", "20:00"),
+			threshold:   15 * time.Minute,
+			wantLargest: 20 * time.Minute,
+			wantGaps:    [][2]int{{1, 3}},
+		},
+		{
+			name:      "single track",
+			tracks:    tracks("00:00"),
+			threshold: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := FindGaps(tt.tracks, tt.threshold)
+			if report.Largest != tt.wantLargest {
+				t.Errorf("Largest = %v, want %v", report.Largest, tt.wantLargest)
+			}
+			if len(report.Gaps) != len(tt.wantGaps) {
+				t.Fatalf("Gaps = %v, want %d", report.Gaps, len(tt.wantGaps))
+			}
+			for i, gap := range report.Gaps {
+				if got := [2]int{gap.From.Index, gap.To.Index}; got != tt.wantGaps[i] {
+					t.Errorf("gap %d = track %d to %d, want %d to %d", i, got[0], got[1], tt.wantGaps[i][0], tt.wantGaps[i][1])
+				}
+			}
+		})
+	}
+}
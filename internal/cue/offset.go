@@ -0,0 +1,34 @@
+package cue
+
+import "time"
+
+// ShiftTracks adds offset to every track's start time, for uploads that don't
+// start where the CUE log does. Tracks that would start before 00:00 are clamped
+// to 00:00, or returned in dropped instead when dropNegative is set. Tracks
+// without a usable start time are kept unchanged. Pregap times move with their
+// track, clamped to 00:00.
+func ShiftTracks(tracks []Track, offset time.Duration, dropNegative bool) (shifted, dropped []Track) {
+	shifted = make([]Track, 0, len(tracks))
+	for _, track := range tracks {
+		start, ok := track.StartOffset()
+		if !ok || offset == 0 {
+			shifted = append(shifted, track)
+			continue
+		}
+
+		start += offset
+		if start < 0 {
+			if dropNegative {
+				dropped = append(dropped, track)
+				continue
+			}
+			start = 0
+		}
+		track.StartTime = formatOffset(start)
+		if pregap, ok := track.PregapOffset(); ok {
+			track.PregapTime = formatOffset(max(pregap+offset, 0))
+		}
+		shifted = append(shifted, track)
+	}
+	return shifted, dropped
+}
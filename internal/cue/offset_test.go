@@ -0,0 +1,68 @@
+package cue
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShiftTracks(t *testing.T) {
+	tracks := []Track{
+		{Index: 1, StartTime: "00:45"},
+		{Index: 2, StartTime: "01:30"},
+		{Index: 3, StartTime: ""},
+		{Index: 4, StartTime: "65:10"},
+	}
+	startTimes := func(tracks []Track) []string {
+		result := []string{}
+		for _, track := range tracks {
+			result = append(result, track.StartTime)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name         string
+		offset       time.Duration
+		dropNegative bool
+		want         []string
+		wantDropped  []string
+	}{
+		{"no offset", 0, false, []string{"00:45", "01:30", "", "65:10"}, []string{}},
+		{"positive", 90 * time.Second, false, []string{"02:15", "03:00", "", "66:40"}, []string{}},
+		{"negative clamps pre-roll", -90 * time.Second, false, []string{"00:00", "00:00", "", "63:40"}, []string{}},
+		{"negative drops pre-roll", -90 * time.Second, true, []string{"00:00", "", "63:40"}, []string{"00:45"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shifted, dropped := ShiftTracks(tracks, tt.offset, tt.dropNegative)
+			if got := startTimes(shifted); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ShiftTracks() start times = %v, want %v", got, tt.want)
+			}
+			if got := startTimes(dropped); !reflect.DeepEqual(got, tt.wantDropped) {
+				t.Errorf("ShiftTracks() dropped = %v, want %v", got, tt.wantDropped)
+			}
+		})
+	}
+	if tracks[0].StartTime != "00:45" {
+		t.Errorf("ShiftTracks() modified its input: %v", tracks[0])
+	}
+}
+
+func TestShiftTracksPregap(t *testing.T) {
+	tracks := []Track{
+		{Index: 1, StartTime: "00:50", PregapTime: "00:40"},
+		{Index: 2, StartTime: "04:01", PregapTime: "03:52"},
+		{Index: 3, StartTime: "08:15"},
+	}
+	shifted, _ := ShiftTracks(tracks, -45*time.Second, false)
+
+	var got []string
+	for _, track := range shifted {
+		got = append(got, track.PregapTime)
+	}
+	if want := []string{"00:00", "03:07", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ShiftTracks() pregap times = %v, want %v", got, want)
+	}
+}
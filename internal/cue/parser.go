@@ -5,6 +5,7 @@ package cue
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textnorm"
 )
 
 // AIDEV-TODO: Implement CUE file line-by-line parsing
@@ -23,11 +25,15 @@ import (
 
 // Track represents a single track from a CUE sheet
 type Track struct {
-	Index     int    `json:"index"`     // Track number (e.g., 1, 2, 3)
-	StartTime string `json:"start_time"` // Start time in MM:SS format
-	Artist    string `json:"artist"`    // Track artist/performer
-	Title     string `json:"title"`     // Track title
-	Genre     string `json:"genre"`     // Track genre (if available)
+	Index      int    `json:"index"`       // Track number (e.g., 1, 2, 3)
+	StartTime  string `json:"start_time"`  // Start time in MM:SS format: INDEX 01, or INDEX 00 without one
+	PregapTime string `json:"pregap_time"` // INDEX 00 (pregap) time in MM:SS format, "" if absent
+	Artist     string `json:"artist"`      // Track artist/performer
+	Title      string `json:"title"`       // Track title
+	Genre      string `json:"genre"`       // Track genre (if available)
+	ISRC       string `json:"isrc"`        // International Standard Recording Code, "" if absent
+	Label      string `json:"label"`       // Record label from the [enrichment] database, "" if unknown
+	Year       string `json:"year"`        // Release year from the [enrichment] database, "" if unknown
 }
 
 // String returns a formatted string representation of the track for debugging
@@ -49,10 +55,34 @@ type CueSheet struct {
 	Title     string   `json:"title"`      // Album/show title
 	Performer string   `json:"performer"`  // Album/show performer/artist
 	Genre     string   `json:"genre"`      // Album/show genre
+	Catalog   string   `json:"catalog"`    // Sheet-level CATALOG (UPC/EAN), "" if absent
 	Files     []string `json:"files"`      // Referenced audio files
 	Tracks    []Track  `json:"tracks"`     // List of tracks in the CUE sheet
 }
 
+// TrackWarning describes a malformed track that was skipped while parsing
+type TrackWarning struct {
+	Line   int    `json:"line"`   // Offending line (the TRACK line when data is missing)
+	Track  int    `json:"track"`  // Track number, 0 if it couldn't be read
+	Reason string `json:"reason"`
+}
+
+// String returns the warning in "line N, track N: reason" form
+func (w TrackWarning) String() string {
+	return fmt.Sprintf("line %d, track %d: %s", w.Line, w.Track, w.Reason)
+}
+
+// ParseOptions controls how ParseCueFileWithOptions treats malformed tracks
+type ParseOptions struct {
+	// Strict fails the whole file on the first malformed track instead of
+	// skipping it with a warning
+	Strict bool
+
+	// SkipIndex00Only drops tracks with an INDEX 00 but no INDEX 01 (e.g. an
+	// exporter's outro bed) instead of starting them at their pregap
+	SkipIndex00Only bool
+}
+
 // String returns a formatted string representation of the CueSheet for debugging
 func (c CueSheet) String() string {
 	var sb strings.Builder
@@ -92,6 +122,8 @@ const (
 	CmdPerformer  // PERFORMER (artist)
 	CmdTitle      // TITLE
 	CmdIndex      // INDEX timing
+	CmdISRC       // ISRC recording code (track level)
+	CmdCatalog    // CATALOG number (sheet level)
 )
 
 // ParsedLine represents a parsed line from a CUE file
@@ -146,6 +178,9 @@ var (
 	
 	// Matches TRACK command: TRACK 01 AUDIO
 	trackRegex = regexp.MustCompile(`^TRACK\s+(\d+)\s+(.+)$`)
+	
+	// Matches a track start time: MM:SS, minutes past 99 for long shows
+	timeRegex = regexp.MustCompile(`^\d{1,3}:\d{2}$`)
 )
 
 // parseLine parses a single line and returns the command type and arguments
@@ -204,6 +239,14 @@ func (p *lineParser) parseLine(line string) ParsedLine {
 			args = parseLineArgs(trimmed[6:]) // Fallback parsing
 		}
 		
+	case strings.HasPrefix(upper, "ISRC "):
+		command = CmdISRC
+		args = parseLineArgs(trimmed[5:]) // Skip "ISRC "
+		
+	case strings.HasPrefix(upper, "CATALOG "):
+		command = CmdCatalog
+		args = parseLineArgs(trimmed[8:]) // Skip "CATALOG "
+		
 	default:
 		command = CmdUnknown
 		args = parseLineArgs(trimmed)
@@ -270,7 +313,27 @@ func (p *lineParser) nextLine() (ParsedLine, bool) {
 		}
 	}
 	
-	return p.parseLine(line), true
+	return p.parseLine(normalizeLine(line)), true
+}
+
+// doubleQuoteReplacer straightens curly double quotes
+var doubleQuoteReplacer = strings.NewReplacer("\u201c", `"`, "\u201d", `"`)
+
+// normalizeLine repairs a line mangled by a Windows editor: Windows-1252 text is
+// decoded, UTF-8 that was mis-decoded as Windows-1252 ("Donâ€™t") is restored
+// and non-breaking spaces become spaces. Curly double quotes are
+// straightened only when they delimit the value (the line has no straight
+// quotes), so typographic quotes and apostrophes inside titles are kept.
+func normalizeLine(line string) string {
+	if !utf8.ValidString(line) {
+		line = string(textnorm.FromWindows1252([]byte(line)))
+	}
+	line = textnorm.RepairMojibake(line)
+	line = strings.ReplaceAll(line, "\u00a0", " ")
+	if !strings.Contains(line, `"`) {
+		line = doubleQuoteReplacer.Replace(line)
+	}
+	return line
 }
 
 // hasError returns true if the scanner encountered an error
@@ -278,29 +341,74 @@ func (p *lineParser) hasError() error {
 	return p.scanner.Err()
 }
 
+// lineError is a parse error tied to a line of the CUE file
+type lineError struct {
+	line int
+	msg  string
+}
+
+func (e *lineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+}
+
+func newLineError(line int, format string, args ...interface{}) error {
+	return &lineError{line: line, msg: fmt.Sprintf(format, args...)}
+}
+
 // trackParser maintains state while parsing track information
 type trackParser struct {
-	currentTrack    *Track
-	tracks          []Track
-	albumPerformer  string
-	albumTitle      string
-	albumGenre      string
-	files           []string
-	inTrackSection  bool // true after first TRACK command
+	currentTrack   *Track
+	tracks         []Track
+	albumPerformer string
+	albumTitle     string
+	albumGenre     string
+	catalog        string
+	files          []string
+	inTrackSection bool // true after first TRACK command
+	strict         bool // fail on the first malformed track instead of skipping it
+	skipIndex00    bool // drop tracks that only have an INDEX 00
+	index00Only    int  // tracks dropped for having only an INDEX 00
+	trackLine      int  // line of the current TRACK command
+	trackFailure   *TrackWarning
+	warnings       []TrackWarning
 }
 
 // newTrackParser creates a new track parser
-func newTrackParser() *trackParser {
+func newTrackParser(opts ParseOptions) *trackParser {
 	return &trackParser{
 		tracks:         []Track{},
 		files:          []string{},
 		inTrackSection: false,
+		strict:         opts.Strict,
+		skipIndex00:    opts.SkipIndex00Only,
 	}
 }
 
 // processLine processes a parsed line and updates track information
-// AIDEV-NOTE: Handles both album-level and track-level metadata
+// AIDEV-NOTE: Handles both album-level and track-level metadata. Outside strict
+// mode an error inside a track marks that track as malformed and the rest of
+// its lines are ignored; errors before the first TRACK still fail the file.
 func (tp *trackParser) processLine(line ParsedLine) error {
+	if line.Command != CmdTrack && tp.trackFailure != nil {
+		return nil
+	}
+
+	err := tp.dispatchLine(line)
+	if err == nil || tp.strict || tp.currentTrack == nil {
+		return err
+	}
+
+	reason := err.Error()
+	var lineErr *lineError
+	if errors.As(err, &lineErr) {
+		reason = lineErr.msg
+	}
+	tp.trackFailure = &TrackWarning{Line: line.LineNum, Track: tp.currentTrack.Index, Reason: reason}
+	return nil
+}
+
+// dispatchLine hands a parsed line to its command handler
+func (tp *trackParser) dispatchLine(line ParsedLine) error {
 	switch line.Command {
 	case CmdTrack:
 		return tp.handleTrackCommand(line)
@@ -314,6 +422,10 @@ func (tp *trackParser) processLine(line ParsedLine) error {
 		return tp.handleFileCommand(line)
 	case CmdRem:
 		return tp.handleRemCommand(line)
+	case CmdISRC:
+		tp.setISRC(strings.Join(line.Args, " "))
+	case CmdCatalog:
+		tp.catalog = strings.TrimSpace(strings.Join(line.Args, " "))
 	}
 	return nil
 }
@@ -321,36 +433,38 @@ func (tp *trackParser) processLine(line ParsedLine) error {
 // handleTrackCommand processes TRACK commands and starts a new track
 func (tp *trackParser) handleTrackCommand(line ParsedLine) error {
 	// Finalize current track if one exists
-	if tp.currentTrack != nil {
-		tp.finalizeCurrentTrack()
+	if err := tp.finalizeCurrentTrack(); err != nil {
+		return err
 	}
 
-	if len(line.Args) < 2 {
-		return fmt.Errorf("line %d: TRACK command requires track number and format", line.LineNum)
-	}
-
-	trackNum, err := strconv.Atoi(line.Args[0])
-	if err != nil {
-		return fmt.Errorf("line %d: invalid track number '%s'", line.LineNum, line.Args[0])
-	}
-
-	// Create new track
+	// Create new track; a malformed TRACK line still opens one so its lines
+	// are skipped along with it
 	tp.currentTrack = &Track{
-		Index: trackNum,
 		// Inherit album-level metadata as defaults
 		Artist: tp.albumPerformer,
 		Title:  "", // Title will be set by TITLE command
 		Genre:  "",
 	}
-
+	tp.trackLine = line.LineNum
 	tp.inTrackSection = true
+
+	if len(line.Args) < 2 {
+		return newLineError(line.LineNum, "TRACK command requires track number and format")
+	}
+
+	trackNum, err := strconv.Atoi(line.Args[0])
+	if err != nil {
+		return newLineError(line.LineNum, "invalid track number '%s'", line.Args[0])
+	}
+	tp.currentTrack.Index = trackNum
+
 	return nil
 }
 
 // handlePerformerCommand processes PERFORMER commands
 func (tp *trackParser) handlePerformerCommand(line ParsedLine) error {
 	if len(line.Args) == 0 {
-		return fmt.Errorf("line %d: PERFORMER command requires artist name", line.LineNum)
+		return newLineError(line.LineNum, "PERFORMER command requires artist name")
 	}
 
 	performer := strings.Join(line.Args, " ")
@@ -369,7 +483,7 @@ func (tp *trackParser) handlePerformerCommand(line ParsedLine) error {
 // handleTitleCommand processes TITLE commands
 func (tp *trackParser) handleTitleCommand(line ParsedLine) error {
 	if len(line.Args) == 0 {
-		return fmt.Errorf("line %d: TITLE command requires title text", line.LineNum)
+		return newLineError(line.LineNum, "TITLE command requires title text")
 	}
 
 	title := strings.Join(line.Args, " ")
@@ -386,39 +500,46 @@ func (tp *trackParser) handleTitleCommand(line ParsedLine) error {
 }
 
 // handleIndexCommand processes INDEX commands and converts time format
-// AIDEV-NOTE: Converts MM:SS:FF format to MM:SS by dropping frames
+// AIDEV-NOTE: Converts MM:SS:FF format to MM:SS by dropping frames. INDEX 01 is
+// the start time whatever order the indexes come in; INDEX 00 (the pregap of a
+// crossfaded track) is kept separately and only stands in for a missing INDEX 01,
+// see finalizeCurrentTrack. Other indexes are ignored.
 func (tp *trackParser) handleIndexCommand(line ParsedLine) error {
 	if tp.currentTrack == nil {
-		return fmt.Errorf("line %d: INDEX command found outside of track context", line.LineNum)
+		return newLineError(line.LineNum, "INDEX command found outside of track context")
 	}
 
 	if len(line.Args) < 4 {
-		return fmt.Errorf("line %d: INDEX command requires index number and time (MM:SS:FF)", line.LineNum)
+		return newLineError(line.LineNum, "INDEX command requires index number and time (MM:SS:FF)")
 	}
 
 	indexNum, err := strconv.Atoi(line.Args[0])
 	if err != nil {
-		return fmt.Errorf("line %d: invalid index number '%s'", line.LineNum, line.Args[0])
+		return newLineError(line.LineNum, "invalid index number '%s'", line.Args[0])
 	}
 
-	// Only process INDEX 01 (track start time)
-	if indexNum != 1 {
+	if indexNum != 0 && indexNum != 1 {
 		return nil
 	}
 
 	// Parse time components
 	minutes, err := strconv.Atoi(line.Args[1])
 	if err != nil {
-		return fmt.Errorf("line %d: invalid minutes '%s'", line.LineNum, line.Args[1])
+		return newLineError(line.LineNum, "invalid minutes '%s'", line.Args[1])
 	}
 
 	seconds, err := strconv.Atoi(line.Args[2])
 	if err != nil {
-		return fmt.Errorf("line %d: invalid seconds '%s'", line.LineNum, line.Args[2])
+		return newLineError(line.LineNum, "invalid seconds '%s'", line.Args[2])
 	}
 
 	// Convert to MM:SS format (dropping frames)
-	tp.currentTrack.StartTime = fmt.Sprintf("%02d:%02d", minutes, seconds)
+	timeStr := fmt.Sprintf("%02d:%02d", minutes, seconds)
+	if indexNum == 0 {
+		tp.currentTrack.PregapTime = timeStr
+	} else {
+		tp.currentTrack.StartTime = timeStr
+	}
 
 	return nil
 }
@@ -427,7 +548,7 @@ func (tp *trackParser) handleIndexCommand(line ParsedLine) error {
 // AIDEV-NOTE: CUE files can reference single or multiple audio files
 func (tp *trackParser) handleFileCommand(line ParsedLine) error {
 	if len(line.Args) < 2 {
-		return fmt.Errorf("line %d: FILE command requires filename and format", line.LineNum)
+		return newLineError(line.LineNum, "FILE command requires filename and format")
 	}
 
 	filename := line.Args[0]
@@ -466,11 +587,16 @@ func (tp *trackParser) handleRemCommand(line ParsedLine) error {
 			// Album-level genre
 			tp.albumGenre = value
 		}
+	case "ISRC":
+		// Older exporter versions write ISRC as a REM field rather than the ISRC command
+		tp.setISRC(value)
+	case "CATALOG":
+		tp.catalog = strings.TrimSpace(value)
 	case "COMMENT":
 		// Skip comments - they're just metadata
 		return nil
 	default:
-		// AIDEV-NOTE: Other REM types like DISCID, CATALOG, etc. are ignored
+		// AIDEV-NOTE: Other REM types like DISCID are ignored
 		// Could be expanded in the future if needed
 		return nil
 	}
@@ -478,33 +604,97 @@ func (tp *trackParser) handleRemCommand(line ParsedLine) error {
 	return nil
 }
 
-// finalizeCurrentTrack adds the current track to the tracks list
-func (tp *trackParser) finalizeCurrentTrack() {
-	if tp.currentTrack != nil {
-		// Only add tracks that have meaningful content
-		if !tp.currentTrack.IsEmpty() {
-			tp.tracks = append(tp.tracks, *tp.currentTrack)
+// setISRC records an ISRC on the current track, normalized to its 12-character
+// form (exporters sometimes write "US-ABC-12-34567"). ISRCs outside a track are ignored.
+func (tp *trackParser) setISRC(value string) {
+	if !tp.inTrackSection || tp.currentTrack == nil {
+		return
+	}
+	isrc := strings.ToUpper(strings.TrimSpace(value))
+	tp.currentTrack.ISRC = strings.ReplaceAll(isrc, "-", "")
+}
+
+// finalizeCurrentTrack adds the current track to the tracks list, or records
+// it as a warning if it is malformed (an error in strict mode)
+func (tp *trackParser) finalizeCurrentTrack() error {
+	track, failure := tp.currentTrack, tp.trackFailure
+	tp.currentTrack, tp.trackFailure = nil, nil
+	if track == nil {
+		return nil
+	}
+
+	// Only add tracks that have meaningful content
+	if failure == nil && track.IsEmpty() {
+		return nil
+	}
+	if failure == nil && track.StartTime == "" && track.PregapTime != "" {
+		if tp.skipIndex00 {
+			tp.index00Only++
+			return nil
+		}
+		track.StartTime = track.PregapTime
+	}
+	if failure == nil {
+		if reason := trackProblem(*track); reason != "" {
+			failure = &TrackWarning{Line: tp.trackLine, Track: track.Index, Reason: reason}
+		}
+	}
+
+	if failure != nil {
+		if tp.strict {
+			return errors.New(failure.String())
 		}
+		tp.warnings = append(tp.warnings, *failure)
+		return nil
 	}
+
+	tp.tracks = append(tp.tracks, *track)
+	return nil
+}
+
+// trackProblem reports why a complete track can't be used, or "" if it can
+func trackProblem(track Track) string {
+	switch {
+	case track.Index <= 0:
+		return fmt.Sprintf("invalid track number %d", track.Index)
+	case track.StartTime == "":
+		return "missing INDEX 01"
+	case !isValidTimeFormat(track.StartTime):
+		return fmt.Sprintf("invalid start time %s", track.StartTime)
+	}
+	return ""
 }
 
 // finish finalizes parsing and returns the complete CueSheet
-func (tp *trackParser) finish() *CueSheet {
+func (tp *trackParser) finish() (*CueSheet, error) {
 	// Finalize the last track
-	tp.finalizeCurrentTrack()
+	if err := tp.finalizeCurrentTrack(); err != nil {
+		return nil, err
+	}
 
 	return &CueSheet{
 		Title:     tp.albumTitle,
 		Performer: tp.albumPerformer,
 		Genre:     tp.albumGenre,
+		Catalog:   tp.catalog,
 		Files:     tp.files,
 		Tracks:    tp.tracks,
-	}
+	}, nil
 }
 
-// ParseCueFile parses a CUE file and returns a CueSheet with track information
-// AIDEV-NOTE: Main entry point for CUE file parsing - orchestrates the entire process
+// ParseCueFile parses a CUE file and returns a CueSheet with track information.
+// Any malformed track fails the whole file; see ParseCueFileWithOptions.
 func ParseCueFile(filename string) (*CueSheet, error) {
+	cueSheet, _, err := ParseCueFileWithOptions(filename, ParseOptions{Strict: true})
+	return cueSheet, err
+}
+
+// ParseCueFileWithOptions parses a CUE file and returns a CueSheet with track
+// information. Unless opts.Strict is set, malformed tracks are skipped and
+// returned as warnings; the file only fails if no usable track is left, in
+// which case the warnings are returned alongside the error.
+// AIDEV-NOTE: Main entry point for CUE file parsing - orchestrates the entire process
+func ParseCueFileWithOptions(filename string, opts ParseOptions) (*CueSheet, []TrackWarning, error) {
 	log := logger.Get()
 	
 	log.Info("Starting CUE file parsing", 
@@ -516,7 +706,7 @@ func ParseCueFile(filename string) (*CueSheet, error) {
 		log.Error("Failed to open CUE file", 
 			slog.String("filename", filename),
 			slog.String("error", err.Error()))
-		return nil, fmt.Errorf("failed to open CUE file '%s': %w", filename, err)
+		return nil, nil, fmt.Errorf("failed to open CUE file '%s': %w", filename, err)
 	}
 	defer func() {
 		if closeErr := parser.Close(); closeErr != nil {
@@ -527,7 +717,7 @@ func ParseCueFile(filename string) (*CueSheet, error) {
 	}()
 
 	// Initialize the track parser
-	trackParser := newTrackParser()
+	trackParser := newTrackParser(opts)
 	lineCount := 0
 
 	// Process the file line by line
@@ -545,7 +735,7 @@ func ParseCueFile(filename string) (*CueSheet, error) {
 				slog.Int("line_number", line.LineNum),
 				slog.String("line_content", line.Raw),
 				slog.String("error", err.Error()))
-			return nil, fmt.Errorf("parsing error in '%s': %w", filename, err)
+			return nil, nil, fmt.Errorf("parsing error in '%s': %w", filename, err)
 		}
 	}
 
@@ -558,15 +748,28 @@ func ParseCueFile(filename string) (*CueSheet, error) {
 		log.Error("Error reading CUE file", 
 			slog.String("filename", filename),
 			slog.String("error", err.Error()))
-		return nil, fmt.Errorf("error reading CUE file '%s': %w", filename, err)
+		return nil, nil, fmt.Errorf("error reading CUE file '%s': %w", filename, err)
 	}
 
 	// Finalize parsing and get the result
-	cueSheet := trackParser.finish()
+	cueSheet, err := trackParser.finish()
+	if err != nil {
+		log.Error("CUE file parsing error",
+			slog.String("filename", filename),
+			slog.String("error", err.Error()))
+		return nil, nil, fmt.Errorf("parsing error in '%s': %w", filename, err)
+	}
+	for _, warning := range trackParser.warnings {
+		log.Debug("Skipped malformed CUE track",
+			slog.String("filename", filename),
+			slog.String("warning", warning.String()))
+	}
 	
 	log.Info("CUE file parsing completed", 
 		slog.String("filename", filename),
 		slog.Int("track_count", len(cueSheet.Tracks)),
+		slog.Int("skipped_tracks", len(trackParser.warnings)),
+		slog.Int("index00_only_dropped", trackParser.index00Only),
 		slog.String("album_title", cueSheet.Title),
 		slog.String("album_performer", cueSheet.Performer))
 
@@ -575,13 +778,13 @@ func ParseCueFile(filename string) (*CueSheet, error) {
 		log.Error("CUE file validation failed", 
 			slog.String("filename", filename),
 			slog.String("error", err.Error()))
-		return nil, fmt.Errorf("validation failed for '%s': %w", filename, err)
+		return nil, trackParser.warnings, fmt.Errorf("validation failed for '%s': %w", filename, err)
 	}
 
 	log.Debug("CUE file validation successful", 
 		slog.String("filename", filename))
 
-	return cueSheet, nil
+	return cueSheet, trackParser.warnings, nil
 }
 
 // validateCueSheet performs basic validation on the parsed CueSheet
@@ -619,7 +822,5 @@ func validateCueSheet(cueSheet *CueSheet) error {
 
 // isValidTimeFormat checks if a time string is in MM:SS format
 func isValidTimeFormat(timeStr string) bool {
-	// Basic regex for MM:SS format
-	timeRegex := regexp.MustCompile(`^\d{1,3}:\d{2}$`)
 	return timeRegex.MatchString(timeStr)
 }
\ No newline at end of file
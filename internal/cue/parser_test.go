@@ -0,0 +1,234 @@
+package cue
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCueFileISRCAndCatalog(t *testing.T) {
+	// Older exporter versions write REM ISRC/REM CATALOG, newer ones the standard commands
+	tests := []struct {
+		name    string
+		fixture string
+	}{
+		{"REM fields", "isrc_rem.cue"},
+		{"standard commands", "isrc_standard.cue"},
+	}
+
+	wantISRCs := []string{"USABC1234567", "", "GBXYZ2500042"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet, err := ParseCueFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatalf("ParseCueFile() error = %v", err)
+			}
+
+			if sheet.Catalog != "0724384960650" {
+				t.Errorf("Catalog = %q, want 0724384960650", sheet.Catalog)
+			}
+			if len(sheet.Tracks) != len(wantISRCs) {
+				t.Fatalf("got %d tracks, want %d", len(sheet.Tracks), len(wantISRCs))
+			}
+			for i, want := range wantISRCs {
+				if got := sheet.Tracks[i].ISRC; got != want {
+					t.Errorf("track %d ISRC = %q, want %q", i+1, got, want)
+				}
+			}
+			if sheet.Tracks[0].StartTime != "00:25" || sheet.Tracks[0].Artist != "Laura Dre" {
+				t.Errorf("track 1 = %+v, other fields should still parse", sheet.Tracks[0])
+			}
+		})
+	}
+}
+
+func TestParseLineISRCCommands(t *testing.T) {
+	tests := []struct {
+		line    string
+		command CueCommand
+		args    []string
+	}{
+		{`    ISRC USABC1234567`, CmdISRC, []string{"USABC1234567"}},
+		{`isrc USABC1234567`, CmdISRC, []string{"USABC1234567"}},
+		{`CATALOG 0724384960650`, CmdCatalog, []string{"0724384960650"}},
+		{`REM ISRC "US-ABC-12-34567"`, CmdRem, []string{"ISRC", "US-ABC-12-34567"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			p := &lineParser{}
+			got := p.parseLine(tt.line)
+			if got.Command != tt.command {
+				t.Errorf("Command = %v, want %v", got.Command, tt.command)
+			}
+			if len(got.Args) != len(tt.args) {
+				t.Fatalf("Args = %q, want %q", got.Args, tt.args)
+			}
+			for i := range tt.args {
+				if got.Args[i] != tt.args[i] {
+					t.Errorf("Args = %q, want %q", got.Args, tt.args)
+				}
+			}
+		})
+	}
+}
+
+func TestISRCOutsideTrackIgnored(t *testing.T) {
+	tp := newTrackParser(ParseOptions{})
+	tp.setISRC("USABC1234567") // Before any TRACK
+	if err := tp.processLine(ParsedLine{Command: CmdTrack, Args: []string{"01", "AUDIO"}}); err != nil {
+		t.Fatal(err)
+	}
+	if tp.currentTrack.ISRC != "" {
+		t.Errorf("ISRC before TRACK leaked onto track: %q", tp.currentTrack.ISRC)
+	}
+
+	tp.setISRC(" us-abc-12-34567 ")
+	if tp.currentTrack.ISRC != "USABC1234567" {
+		t.Errorf("ISRC = %q, want normalized USABC1234567", tp.currentTrack.ISRC)
+	}
+}
+
+func TestParseCueFileMalformedTracks(t *testing.T) {
+	// A crashed logger can leave a track without its INDEX or with a garbled one
+	tests := []struct {
+		fixture     string
+		wantTracks  []int
+		wantWarning TrackWarning
+		wantStrict  string
+	}{
+		{
+			fixture:     "broken_middle.cue",
+			wantTracks:  []int{1, 3, 4, 5},
+			wantWarning: TrackWarning{Line: 11, Track: 2, Reason: "INDEX command requires index number and time (MM:SS:FF)"},
+			wantStrict:  "line 11:",
+		},
+		{
+			fixture:     "broken_end.cue",
+			wantTracks:  []int{1, 2},
+			wantWarning: TrackWarning{Line: 12, Track: 3, Reason: "missing INDEX 01"},
+			wantStrict:  "line 12, track 3: missing INDEX 01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.fixture)
+
+			sheet, warnings, err := ParseCueFileWithOptions(path, ParseOptions{})
+			if err != nil {
+				t.Fatalf("ParseCueFileWithOptions() error = %v", err)
+			}
+			if len(sheet.Tracks) != len(tt.wantTracks) {
+				t.Fatalf("got %d tracks, want %d", len(sheet.Tracks), len(tt.wantTracks))
+			}
+			for i, index := range tt.wantTracks {
+				if sheet.Tracks[i].Index != index {
+					t.Errorf("track %d index = %d, want %d", i, sheet.Tracks[i].Index, index)
+				}
+			}
+			if len(warnings) != 1 || warnings[0] != tt.wantWarning {
+				t.Errorf("warnings = %+v, want [%+v]", warnings, tt.wantWarning)
+			}
+
+			_, err = ParseCueFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantStrict) {
+				t.Errorf("strict error = %v, want it to contain %q", err, tt.wantStrict)
+			}
+		})
+	}
+}
+
+func TestParseCueFileNoUsableTracks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.cue")
+	content := "TRACK 01 AUDIO\n  TITLE \"A\"\n  PERFORMER \"B\"\nTRACK xx AUDIO\n  TITLE \"C\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, warnings, err := ParseCueFileWithOptions(path, ParseOptions{})
+	if err == nil {
+		t.Fatal("expected an error when every track is malformed")
+	}
+	if len(warnings) != 2 {
+		t.Errorf("got %d warnings, want 2 returned with the error", len(warnings))
+	}
+}
+
+func TestParseCueFileWindowsEditorDamage(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		wantTitle2 string
+	}{
+		{"bom.cue", "Don't Stop"},
+		{"smart_quotes.cue", "Don’t Stop"}, // Typographic apostrophes inside titles are kept
+		{"combined.cue", "Don’t Stop"},     // Windows-1252 byte decoded to the same character
+		{"mojibake.cue", "Don’t Stop"},     // UTF-8 saved back after being read as Windows-1252
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			sheet, err := ParseCueFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatalf("ParseCueFile() error = %v", err)
+			}
+			if sheet.Performer != "Now Wave Radio" {
+				t.Errorf("Performer = %q", sheet.Performer)
+			}
+			if len(sheet.Tracks) != 2 {
+				t.Fatalf("got %d tracks, want 2", len(sheet.Tracks))
+			}
+
+			first, second := sheet.Tracks[0], sheet.Tracks[1]
+			if first.Title != "When I Fall" || first.Artist != "Laura Dre" {
+				t.Errorf("track 1 = %q by %q, want curly quotes stripped", first.Title, first.Artist)
+			}
+			if second.Title != tt.wantTitle2 {
+				t.Errorf("track 2 title = %q, want %q", second.Title, tt.wantTitle2)
+			}
+			if second.StartTime != "04:10" {
+				t.Errorf("track 2 start = %q, want 04:10", second.StartTime)
+			}
+		})
+	}
+}
+
+func TestParseCueFilePregapIndexes(t *testing.T) {
+	type times struct{ start, pregap string }
+	tests := []struct {
+		fixture string
+		opts    ParseOptions
+		want    []times
+	}{
+		// INDEX 01 is the start whether INDEX 00 comes before or after it
+		{"pregap.cue", ParseOptions{Strict: true}, []times{{"00:00", ""}, {"04:01", "03:52"}, {"08:15", "08:09"}, {"12:30", ""}}},
+		{"pregap.cue", ParseOptions{Strict: true, SkipIndex00Only: true}, []times{{"00:00", ""}, {"04:01", "03:52"}, {"08:15", "08:09"}, {"12:30", ""}}},
+		// A track with only INDEX 00 starts at its pregap...
+		{"outro_bed.cue", ParseOptions{Strict: true}, []times{{"00:00", ""}, {"04:01", "03:52"}, {"58:40", "58:40"}}},
+		// ...or is dropped, without a warning
+		{"outro_bed.cue", ParseOptions{Strict: true, SkipIndex00Only: true}, []times{{"00:00", ""}, {"04:01", "03:52"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s skip=%v", tt.fixture, tt.opts.SkipIndex00Only), func(t *testing.T) {
+			sheet, warnings, err := ParseCueFileWithOptions(filepath.Join("testdata", tt.fixture), tt.opts)
+			if err != nil {
+				t.Fatalf("ParseCueFileWithOptions() error = %v", err)
+			}
+			if len(warnings) != 0 {
+				t.Errorf("warnings = %+v, want none", warnings)
+			}
+			var got []times
+			for _, track := range sheet.Tracks {
+				got = append(got, times{track.StartTime, track.PregapTime})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("(start, pregap) times = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package cue
+
+import (
+	"fmt"
+	"time"
+)
+
+// AIDEV-NOTE: Uploads over the account's length limit are split into parts on
+// Mixcloud. Each part's player starts at 00:00, so start times are rebased to
+// the part they fall in rather than left relative to the whole broadcast.
+
+// SplitTracks divides tracks into len(points)+1 parts at the given offsets,
+// which must be ascending. A track belongs to the part its start time falls in
+// and its StartTime is rebased to that part's start. Tracks without a usable
+// start time stay in the part of the track before them, unchanged.
+func SplitTracks(tracks []Track, points []time.Duration) [][]Track {
+	parts := make([][]Track, len(points)+1)
+	part := 0
+
+	for _, track := range tracks {
+		offset, ok := track.StartOffset()
+		if !ok {
+			parts[part] = append(parts[part], track)
+			continue
+		}
+
+		part = 0
+		for part < len(points) && offset >= points[part] {
+			part++
+		}
+		if part > 0 {
+			track.StartTime = formatOffset(offset - points[part-1])
+		}
+		parts[part] = append(parts[part], track)
+	}
+
+	return parts
+}
+
+// formatOffset formats an offset as a CUE-style MM:SS start time
+func formatOffset(offset time.Duration) string {
+	seconds := int(offset / time.Second)
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
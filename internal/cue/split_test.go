@@ -0,0 +1,68 @@
+package cue
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitTracks(t *testing.T) {
+	tracks := func(startTimes ...string) []Track {
+		result := make([]Track, len(startTimes))
+		for i, startTime := range startTimes {
+			result[i] = Track{Index: i + 1, StartTime: startTime}
+		}
+		return result
+	}
+	startTimes := func(parts [][]Track) [][]string {
+		result := make([][]string, len(parts))
+		for i, part := range parts {
+			result[i] = []string{}
+			for _, track := range part {
+				result[i] = append(result[i], track.StartTime)
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		tracks []Track
+		points []time.Duration
+		want   [][]string
+	}{
+		{
+			name:   "one split point",
+			tracks: tracks("00:00", "55:10", "60:00", "64:30"),
+			points: []time.Duration{time.Hour},
+			want:   [][]string{{"00:00", "55:10"}, {"00:00", "04:30"}},
+		},
+		{
+			name:   "several split points",
+			tracks: tracks("00:00", "61:00", "125:45"),
+			points: []time.Duration{time.Hour, 2 * time.Hour},
+			want:   [][]string{{"00:00"}, {"01:00"}, {"05:45"}},
+		},
+		{
+			name:   "empty part",
+			tracks: tracks("00:00", "130:00"),
+			points: []time.Duration{time.Hour, 2 * time.Hour},
+			want:   [][]string{{"00:00"}, {}, {"10:00"}},
+		},
+		{
+			name:   "track without start time follows the one before it",
+			tracks: tracks("00:00", "62:00", ""),
+			points: []time.Duration{time.Hour},
+			want:   [][]string{{"00:00"}, {"02:00", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitTracks(tt.tracks, tt.points)
+			if !reflect.DeepEqual(startTimes(got), tt.want) {
+				t.Errorf("SplitTracks() start times = %v, want %v", startTimes(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+PERFORMER "Now Wave Radio"
+TITLE "Sounds Like"
+FILE "SoundsLike.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "When I Fall"
+    PERFORMER "Laura Dre"
+    INDEX 01 00:25:40
+  TRACK 02 AUDIO
+    TITLE "Conditional Love"
+    PERFORMER "Airline Food"
+    INDEX 01 04:xx:00
+  TRACK 03 AUDIO
+    TITLE "Bloom"
+    PERFORMER "Kid Moxie"
+    INDEX 01 08:15:02
+  TRACK 04 AUDIO
+    TITLE "Kilter"
+    PERFORMER "Dear Boy"
+    INDEX 01 12:02:10
+  TRACK 05 AUDIO
+    TITLE "Not Yet"
+    PERFORMER "Miss Grit"
+    INDEX 01 16:40:00
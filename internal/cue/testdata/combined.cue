@@ -0,0 +1,11 @@
+﻿PERFORMER "Now Wave Radio"
+TITLE "Sounds Like"
+FILE "SoundsLike.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE “When I Fall”
+    PERFORMER “Laura Dre”
+    INDEX 01 00:25:40
+  TRACK 02 AUDIO
+    TITLE "Dont Stop"
+    PERFORMER "Airline Food"
+    INDEX 01 04:10:00
@@ -0,0 +1,19 @@
+REM GENRE "Radio"
+REM CATALOG 0724384960650
+PERFORMER "Now Wave Radio"
+TITLE "Sounds Like"
+FILE "SoundsLike.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "When I Fall"
+    PERFORMER "Laura Dre"
+    REM ISRC USABC1234567
+    INDEX 01 00:25:40
+  TRACK 02 AUDIO
+    TITLE "Station ID"
+    PERFORMER "Now Wave Radio"
+    INDEX 01 03:10:00
+  TRACK 03 AUDIO
+    TITLE "Conditional Love"
+    PERFORMER "Airline Food"
+    INDEX 01 08:15:02
+    REM ISRC gb-xyz-25-00042
@@ -0,0 +1,16 @@
+PERFORMER "Now Wave Radio"
+TITLE "Sounds Like"
+FILE "SoundsLike.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "When I Fall"
+    PERFORMER "Laura Dre"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Conditional Love"
+    PERFORMER "Airline Food"
+    INDEX 00 03:52:10
+    INDEX 01 04:01:00
+  TRACK 03 AUDIO
+    TITLE "Outro Bed"
+    PERFORMER "Now Wave Radio"
+    INDEX 00 58:40:00
@@ -0,0 +1,21 @@
+PERFORMER "Now Wave Radio"
+TITLE "Sounds Like"
+FILE "SoundsLike.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "When I Fall"
+    PERFORMER "Laura Dre"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Conditional Love"
+    PERFORMER "Airline Food"
+    INDEX 00 03:52:10
+    INDEX 01 04:01:00
+  TRACK 03 AUDIO
+    TITLE "Bloom"
+    PERFORMER "Kid Moxie"
+    INDEX 01 08:15:02
+    INDEX 00 08:09:40
+  TRACK 04 AUDIO
+    TITLE "Nightcall"
+    PERFORMER "Kavinsky"
+    INDEX 01 12:30:00
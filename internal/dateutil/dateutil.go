@@ -4,6 +4,8 @@
 package dateutil
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,11 +19,14 @@ import (
 //   - "DD" -> "02" (2-digit day with leading zero)
 //   - "M/D/YYYY" -> "1/2/2006"
 //   - "YYYYMMDD" -> "20060102"
+//   - "MMMM D YYYY" -> "January 2 2006"
 func FormatDateToGoLayout(userFormat string) string {
 	// AIDEV-NOTE: Order matters - replace longer patterns first to avoid partial matches
 	replacer := strings.NewReplacer(
 		"YYYY", "2006", // 4-digit year
 		"YY", "06",     // 2-digit year
+		"MMMM", "January", // Full month name
+		"MMM", "Jan",      // Abbreviated month name
 		"MM", "01",     // 2-digit month with leading zero
 		"M", "1",       // 1-2 digit month without leading zero
 		"DD", "02",     // 2-digit day with leading zero
@@ -38,31 +43,142 @@ func FormatDateWithPattern(t time.Time, userPattern string) string {
 	return t.Format(goLayout)
 }
 
+// flexibleFormats are the layouts ParseFlexibleDate tries, in order, with the
+// names ParseFlexibleDateFormat reports them by
+// AIDEV-NOTE: Common date formats users might input - ordered by likelihood.
+// Month-first comes before day-first, so "03/04/2025" is March 4.
+var flexibleFormats = []struct {
+	layout string
+	name   string
+}{
+	{"1/2/2006", "M/D/YYYY"},
+	{"01/02/2006", "MM/DD/YYYY"},
+	{"2006-01-02", "YYYY-MM-DD"},
+	{"2006/01/02", "YYYY/MM/DD"},
+	{"2/1/2006", "D/M/YYYY"},
+	{"02/01/2006", "DD/MM/YYYY"},
+	{"1-2-2006", "M-D-YYYY"},
+	{"01-02-2006", "MM-DD-YYYY"},
+	{"2006.01.02", "YYYY.MM.DD"},
+	{"20060102", "YYYYMMDD"},
+}
+
 // ParseFlexibleDate attempts to parse a date string using various common formats.
 // This handles different input formats that users might provide.
 func ParseFlexibleDate(dateStr string) (time.Time, error) {
-	// AIDEV-NOTE: Common date formats users might input - ordered by likelihood
-	formats := []string{
-		"1/2/2006",      // M/D/YYYY
-		"01/02/2006",    // MM/DD/YYYY
-		"2006-01-02",    // YYYY-MM-DD
-		"2006/01/02",    // YYYY/MM/DD
-		"2/1/2006",      // D/M/YYYY
-		"02/01/2006",    // DD/MM/YYYY
-		"1-2-2006",      // M-D-YYYY
-		"01-02-2006",    // MM-DD-YYYY
-		"2006.01.02",    // YYYY.MM.DD
-		"20060102",      // YYYYMMDD
+	date, _, err := ParseFlexibleDateFormat(dateStr)
+	return date, err
+}
+
+// ParseFlexibleDateFormat is ParseFlexibleDate, also returning the name of the
+// format that matched, e.g. "M/D/YYYY"
+func ParseFlexibleDateFormat(dateStr string) (time.Time, string, error) {
+	for _, format := range flexibleFormats {
+		if parsed, err := time.Parse(format.layout, dateStr); err == nil {
+			return parsed, format.name, nil
+		}
 	}
 	
-	for _, format := range formats {
-		if parsed, err := time.Parse(format, dateStr); err == nil {
-			return parsed, nil
+	return time.Time{}, "", &time.ParseError{
+		Layout:  "multiple common formats",
+		Value:   dateStr,
+		Message: ": use " + AbsoluteFormats,
+	}
+}
+
+// ParseDateInFormat parses dateStr in exactly one format, a pattern like
+// date_format's ("DD/MM/YYYY") or a Go layout ("02/01/2006")
+func ParseDateInFormat(dateStr, format string) (time.Time, error) {
+	date, err := time.Parse(FormatDateToGoLayout(format), strings.TrimSpace(dateStr))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a %s date", dateStr, format)
+	}
+	return date, nil
+}
+
+// ValidateDateInputFormat checks that format names a whole date: a year, a
+// month and a day that parse back to the date they were formatted from
+func ValidateDateInputFormat(format string) error {
+	sample := time.Date(2025, time.June, 28, 0, 0, 0, 0, time.UTC)
+	layout := FormatDateToGoLayout(format)
+	if parsed, err := time.Parse(layout, sample.Format(layout)); err != nil || !parsed.Equal(sample) {
+		return fmt.Errorf("%q needs a year, a month and a day, e.g. \"DD/MM/YYYY\"", format)
+	}
+	return nil
+}
+
+// AmbiguousDayMonth reports whether dateStr is a different date read day-first
+// than read month-first, as "03/04/2025" is, and returns the day-first reading
+func AmbiguousDayMonth(dateStr string) (dayFirst time.Time, ambiguous bool) {
+	pairs := [][2]string{{"1/2/2006", "2/1/2006"}, {"1-2-2006", "2-1-2006"}}
+	for _, pair := range pairs {
+		monthFirst, err := time.Parse(pair[0], dateStr)
+		if err != nil {
+			continue
 		}
+		dayFirst, err := time.Parse(pair[1], dateStr)
+		return dayFirst, err == nil && !dayFirst.Equal(monthFirst)
 	}
-	
-	return time.Time{}, &time.ParseError{
-		Layout: "multiple common formats",
-		Value:  dateStr,
+	return time.Time{}, false
+}
+
+// AbsoluteFormats lists the formats ParseFlexibleDate accepts, for error messages
+const AbsoluteFormats = "M/D/YYYY, YYYY-MM-DD, YYYY/MM/DD, D/M/YYYY, M-D-YYYY, YYYY.MM.DD or YYYYMMDD"
+
+// RelativeFormats lists the expressions ParseRelativeDate accepts, for error messages
+const RelativeFormats = `"today", "yesterday", "N days ago" or "last <weekday>"`
+
+// ParseDate parses a date given on the command line: a relative expression
+// resolved against now (see ParseRelativeDate) or any format ParseFlexibleDate
+// accepts
+func ParseDate(dateStr string, now time.Time) (time.Time, error) {
+	if date, ok := ParseRelativeDate(dateStr, now); ok {
+		return date, nil
+	}
+	date, err := ParseFlexibleDate(dateStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w, or a relative date: %s", err, RelativeFormats)
+	}
+	return date, nil
+}
+
+// ParseRelativeDate resolves "today", "yesterday", "N days ago" and
+// "last <weekday>" (e.g. "last friday" or "last fri") to midnight of that day in
+// now's location. "last <weekday>" is the most recent such day before today, so
+// "last friday" on a Friday is a week ago. ok is false when expr isn't a
+// relative expression.
+// AIDEV-NOTE: Days are counted on the calendar with time.Date rather than by
+// subtracting 24h multiples, so DST changes can't land on the wrong date.
+func ParseRelativeDate(expr string, now time.Time) (date time.Time, ok bool) {
+	words := strings.Fields(strings.ToLower(expr))
+	daysAgo := -1
+	switch {
+	case len(words) == 1 && words[0] == "today":
+		daysAgo = 0
+	case len(words) == 1 && words[0] == "yesterday":
+		daysAgo = 1
+	case len(words) == 3 && (words[1] == "days" || words[1] == "day") && words[2] == "ago":
+		if n, err := strconv.Atoi(words[0]); err == nil && n >= 0 {
+			daysAgo = n
+		}
+	case len(words) == 2 && words[0] == "last":
+		if weekday, found := parseWeekday(words[1]); found {
+			daysAgo = (int(now.Weekday())-int(weekday)+6)%7 + 1
+		}
+	}
+	if daysAgo < 0 {
+		return time.Time{}, false
+	}
+	return time.Date(now.Year(), now.Month(), now.Day()-daysAgo, 0, 0, 0, 0, now.Location()), true
+}
+
+// parseWeekday matches a lowercase weekday name or its three-letter abbreviation
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
 	}
+	return 0, false
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package dateutil
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -41,6 +42,16 @@ func TestFormatDateToGoLayout(t *testing.T) {
 			userFormat: "M/DD-YYYY",
 			expected:   "1/02-2006",
 		},
+		{
+			name:       "Full month name",
+			userFormat: "MMMM D YYYY",
+			expected:   "January 2 2006",
+		},
+		{
+			name:       "Abbreviated month name",
+			userFormat: "MMM DD, YYYY",
+			expected:   "Jan 02, 2006",
+		},
 		{
 			name:       "Empty string",
 			userFormat: "",
@@ -164,4 +175,188 @@ func TestParseFlexibleDate(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestParseRelativeDate(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	// 2025-06-28 is a Saturday
+	saturday := time.Date(2025, 6, 28, 9, 30, 0, 0, newYork)
+
+	tests := []struct {
+		name   string
+		expr   string
+		now    time.Time
+		want   string
+		wantOK bool
+	}{
+		{"today", "today", saturday, "2025-06-28", true},
+		{"yesterday", "Yesterday", saturday, "2025-06-27", true},
+		{"days ago", "3 days ago", saturday, "2025-06-25", true},
+		{"one day ago", "1 day ago", saturday, "2025-06-27", true},
+		{"zero days ago", "0 days ago", saturday, "2025-06-28", true},
+		{"last friday on saturday", "last friday", saturday, "2025-06-27", true},
+		{"last saturday on saturday is a week ago", "last saturday", saturday, "2025-06-21", true},
+		{"last sunday wraps back a week", "last sunday", saturday, "2025-06-22", true},
+		{"abbreviated weekday", "LAST  Fri", saturday, "2025-06-27", true},
+		{"last saturday from sunday", "last saturday", time.Date(2025, 6, 29, 1, 0, 0, 0, newYork), "2025-06-28", true},
+		{"last sunday from monday", "last sunday", time.Date(2025, 6, 30, 1, 0, 0, 0, newYork), "2025-06-29", true},
+		{"last friday across the year", "last friday", time.Date(2026, 1, 1, 8, 0, 0, 0, newYork), "2025-12-26", true},
+		{"yesterday across the year", "yesterday", time.Date(2026, 1, 1, 0, 5, 0, 0, newYork), "2025-12-31", true},
+		{"days ago across the year", "10 days ago", time.Date(2026, 1, 3, 12, 0, 0, 0, newYork), "2025-12-24", true},
+		// 2025-03-09 (spring forward) has 23 hours, 2025-11-02 (fall back) has 25
+		{"yesterday after spring forward", "yesterday", time.Date(2025, 3, 10, 0, 30, 0, 0, newYork), "2025-03-09", true},
+		{"today on spring forward", "today", time.Date(2025, 3, 9, 23, 30, 0, 0, newYork), "2025-03-09", true},
+		{"yesterday after fall back", "yesterday", time.Date(2025, 11, 3, 23, 30, 0, 0, newYork), "2025-11-02", true},
+		{"last sunday over fall back", "last sunday", time.Date(2025, 11, 3, 0, 30, 0, 0, newYork), "2025-11-02", true},
+		{"days ago over spring forward", "2 days ago", time.Date(2025, 3, 10, 0, 30, 0, 0, newYork), "2025-03-08", true},
+		{"absolute date", "6/27/2025", saturday, "", false},
+		{"misspelled weekday", "last fryday", saturday, "", false},
+		{"negative days", "-2 days ago", saturday, "", false},
+		{"next weekday", "next friday", saturday, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRelativeDate(tt.expr, tt.now)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRelativeDate(%q) ok = %v, want %v", tt.expr, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("ParseRelativeDate(%q) at %v = %s, want %s", tt.expr, tt.now, got.Format("2006-01-02"), tt.want)
+			}
+			if got.Hour() != 0 || got.Minute() != 0 || got.Location() != tt.now.Location() {
+				t.Errorf("ParseRelativeDate(%q) = %v, want midnight in %v", tt.expr, got, tt.now.Location())
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 6, 28, 9, 30, 0, 0, time.UTC)
+	if got, err := ParseDate("yesterday", now); err != nil || got.Format("2006-01-02") != "2025-06-27" {
+		t.Errorf("ParseDate(yesterday) = %v, %v, want 2025-06-27", got, err)
+	}
+	if got, err := ParseDate("6/20/2025", now); err != nil || got.Format("2006-01-02") != "2025-06-20" {
+		t.Errorf("ParseDate(6/20/2025) = %v, %v, want 2025-06-20", got, err)
+	}
+
+	_, err := ParseDate("last fryday", now)
+	if err == nil {
+		t.Fatal("ParseDate(last fryday) succeeded")
+	}
+	for _, want := range []string{"YYYY-MM-DD", `"yesterday"`, "last <weekday>"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseDate() error = %q, want it to list %s", err, want)
+		}
+	}
+}
+
+func TestParseFlexibleDateFormat(t *testing.T) {
+	tests := []struct {
+		dateStr string
+		want    time.Time
+		format  string
+	}{
+		// Month-first wins when both readings are valid
+		{"03/04/2025", time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), "M/D/YYYY"},
+		{"3/4/2025", time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), "M/D/YYYY"},
+		{"28/06/2025", time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC), "D/M/YYYY"},
+		{"2025-06-28", time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC), "YYYY-MM-DD"},
+		{"03-04-2025", time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), "M-D-YYYY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dateStr, func(t *testing.T) {
+			got, format, err := ParseFlexibleDateFormat(tt.dateStr)
+			if err != nil || !got.Equal(tt.want) || format != tt.format {
+				t.Errorf("ParseFlexibleDateFormat(%q) = %v, %q, %v, want %v, %q", tt.dateStr, got, format, err, tt.want, tt.format)
+			}
+		})
+	}
+}
+
+func TestParseDateInFormat(t *testing.T) {
+	tests := []struct {
+		dateStr string
+		format  string
+		want    time.Time // Zero for an error
+	}{
+		{"03/04/2025", "DD/MM/YYYY", time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{"03/04/2025", "MM/DD/YYYY", time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"03/04/2025", "02/01/2006", time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{"3/4/2025", "D/M/YYYY", time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{" 28/06/2025 ", "DD/MM/YYYY", time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC)},
+		{"06/28/2025", "DD/MM/YYYY", time.Time{}},
+		{"2025-06-28", "DD/MM/YYYY", time.Time{}},
+		{"3/4/2025", "DD/MM/YYYY", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dateStr+" as "+tt.format, func(t *testing.T) {
+			got, err := ParseDateInFormat(tt.dateStr, tt.format)
+			if tt.want.IsZero() {
+				if err == nil {
+					t.Errorf("ParseDateInFormat(%q, %q) = %v, want an error", tt.dateStr, tt.format, got)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("ParseDateInFormat(%q, %q) = %v, %v, want %v", tt.dateStr, tt.format, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmbiguousDayMonth(t *testing.T) {
+	tests := []struct {
+		dateStr   string
+		ambiguous bool
+		dayFirst  time.Time
+	}{
+		{"03/04/2025", true, time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{"3-4-2025", true, time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC)},
+		{"05/05/2025", false, time.Time{}},
+		{"06/28/2025", false, time.Time{}},
+		{"28/06/2025", false, time.Time{}},
+		{"2025-03-04", false, time.Time{}},
+		{"yesterday", false, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dateStr, func(t *testing.T) {
+			dayFirst, ambiguous := AmbiguousDayMonth(tt.dateStr)
+			if ambiguous != tt.ambiguous || (ambiguous && !dayFirst.Equal(tt.dayFirst)) {
+				t.Errorf("AmbiguousDayMonth(%q) = %v, %v, want %v, %v", tt.dateStr, dayFirst, ambiguous, tt.dayFirst, tt.ambiguous)
+			}
+		})
+	}
+}
+
+func TestValidateDateInputFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"DD/MM/YYYY", false},
+		{"D/M/YYYY", false},
+		{"YYYY-MM-DD", false},
+		{"02/01/2006", false},
+		{"MMMM D YYYY", false},
+		{"DD/MM", true},
+		{"MM/YYYY", true},
+		{"dd/mm/yyyy", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if err := ValidateDateInputFormat(tt.format); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDateInputFormat(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package desclen measures Mixcloud descriptions, either as raw characters or as
+// an estimate of the visible length once Mixcloud has rendered them.
+package desclen
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AIDEV-NOTE: The rendering rules below are observed behavior, not documented by
+// Mixcloud. Keep them all in Rendered (and its tests) so they can be adjusted in
+// one place when Mixcloud changes.
+
+// Model selects how description length is counted against the character limit
+type Model string
+
+const (
+	ModelRaw      Model = "raw"      // Bytes as sent, the default
+	ModelRendered Model = "rendered" // Rendered estimate, see Rendered
+)
+
+// ShortURLLength is what a URL counts as once Mixcloud shortens it
+const ShortURLLength = 23
+
+var (
+	urlRegex       = regexp.MustCompile(`https?://\S+`)
+	blankRunRegex  = regexp.MustCompile(`[ \t]+`)
+	lineSpaceRegex = regexp.MustCompile(` ?\n ?`)
+)
+
+// Valid reports whether m is a known model. The empty model means ModelRaw.
+func (m Model) Valid() bool {
+	return m == "" || m == ModelRaw || m == ModelRendered
+}
+
+// Length measures s under the model
+func (m Model) Length(s string) int {
+	if m == ModelRendered {
+		return Rendered(s)
+	}
+	return len(s)
+}
+
+// Rendered estimates how many characters s takes up once Mixcloud renders it:
+//   - each http(s) URL counts as ShortURLLength characters
+//   - runs of spaces and tabs collapse to one space, and spaces next to a line
+//     break disappear
+//   - control characters other than line breaks and zero-width characters are
+//     stripped
+//   - what's left is counted in characters, not bytes
+func Rendered(s string) int {
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1 // Includes zero-width spaces, joiners and the BOM
+		}
+		return r
+	}, s)
+	s = blankRunRegex.ReplaceAllString(s, " ")
+	s = lineSpaceRegex.ReplaceAllString(s, "\n")
+
+	urls := urlRegex.FindAllString(s, -1)
+	length := utf8.RuneCountInString(urlRegex.ReplaceAllString(s, ""))
+	return length + len(urls)*ShortURLLength
+}
@@ -0,0 +1,72 @@
+package desclen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRendered(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"plain text", "Tracklist", 9},
+		{"line breaks count", "a\nb\n", 4},
+		{"URL shortened", "https://example.bandcamp.com/album/a-very-long-album-name", ShortURLLength},
+		{"short URL still counts as shortened", "http://a.co", ShortURLLength},
+		{"URL in a line", "Buy: https://example.com/x here", len("Buy:  here") + ShortURLLength},
+		{"two URLs", "https://a.com/1 https://b.com/2", 1 + 2*ShortURLLength},
+		{"spaces collapse", "a    b\t\tc", 5},
+		{"spaces around line breaks dropped", "a   \n   b", 3},
+		{"blank lines kept", "a\n\nb", 4},
+		{"zero-width characters stripped", "a\u200bb\ufeff", 2},
+		{"control characters stripped", "a\r\nb\x00", 3},
+		{"counts characters not bytes", "Café – Björk", 12},
+		{"empty", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rendered(tt.text); got != tt.want {
+				t.Errorf("Rendered(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelLength(t *testing.T) {
+	text := "01 - Artist    https://example.com/" + strings.Repeat("x", 40)
+	tests := []struct {
+		model Model
+		want  int
+	}{
+		{"", len(text)},
+		{ModelRaw, len(text)},
+		{ModelRendered, len("01 - Artist ") + ShortURLLength},
+	}
+
+	for _, tt := range tests {
+		if got := tt.model.Length(text); got != tt.want {
+			t.Errorf("Model(%q).Length() = %d, want %d", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestModelValid(t *testing.T) {
+	tests := []struct {
+		model Model
+		want  bool
+	}{
+		{"", true},
+		{ModelRaw, true},
+		{ModelRendered, true},
+		{"visible", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.model.Valid(); got != tt.want {
+			t.Errorf("Model(%q).Valid() = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
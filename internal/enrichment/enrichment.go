@@ -0,0 +1,124 @@
+// Package enrichment adds record label and release year to tracks from the
+// station's music library database.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+// AIDEV-NOTE: Enrichment is a nice-to-have on top of the CUE file, so it never
+// fails a show. A lookup that errors (locked or corrupt database, broken query)
+// turns enrichment off for the rest of the run, and a show whose lookups run
+// past the time budget is published with the tracks looked up so far.
+
+// DefaultTimeout bounds the total lookup time of one show
+const DefaultTimeout = 2 * time.Second
+
+// Match is what the library knows about a track. Either field may be empty.
+type Match struct {
+	Label string
+	Year  string
+}
+
+// Source looks a track up by its normalized artist and title, see Normalize.
+// ok is false when the library has no such track.
+type Source interface {
+	Lookup(ctx context.Context, artist, title string) (match Match, ok bool, err error)
+	Close() error
+}
+
+// Normalize reduces an artist or title to the form lookups compare:
+// lowercased, trimmed and with whitespace collapsed
+func Normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Stats reports how a show's lookups went
+type Stats struct {
+	LookedUp int  // Tracks with an artist and title, looked up
+	Matched  int  // Tracks the library had a label or year for
+	TimedOut bool // The show's lookup time ran out before every track was looked up
+}
+
+// HitRate returns the share of looked-up tracks that matched, in percent
+func (s Stats) HitRate() float64 {
+	if s.LookedUp == 0 {
+		return 0
+	}
+	return float64(s.Matched) / float64(s.LookedUp) * 100
+}
+
+// Enricher looks up the tracks of each show of a run in a Source
+type Enricher struct {
+	source   Source
+	timeout  time.Duration
+	disabled error // The error that turned enrichment off, nil while it's on
+}
+
+// New returns an Enricher giving each show up to timeout of lookups
+// (DefaultTimeout when timeout <= 0)
+func New(source Source, timeout time.Duration) *Enricher {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Enricher{source: source, timeout: timeout}
+}
+
+// Enrich returns a copy of tracks with Label and Year set from the library.
+// The first lookup error disables the Enricher and is returned with the tracks
+// enriched so far; once disabled it returns tracks unchanged and no error.
+func (e *Enricher) Enrich(ctx context.Context, tracks []cue.Track) ([]cue.Track, Stats, error) {
+	var stats Stats
+	if e == nil || e.disabled != nil {
+		return tracks, stats, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	enriched := make([]cue.Track, len(tracks))
+	copy(enriched, tracks)
+	for i := range enriched {
+		artist, title := Normalize(enriched[i].Artist), Normalize(enriched[i].Title)
+		if artist == "" || title == "" {
+			continue
+		}
+		match, ok, err := e.source.Lookup(ctx, artist, title)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				stats.TimedOut = true
+				break
+			}
+			e.disabled = err
+			return enriched, stats, err
+		}
+		stats.LookedUp++
+		if ok && (match.Label != "" || match.Year != "") {
+			enriched[i].Label = strings.TrimSpace(match.Label)
+			enriched[i].Year = strings.TrimSpace(match.Year)
+			stats.Matched++
+		}
+	}
+	return enriched, stats, nil
+}
+
+// Disabled returns the error that turned enrichment off for the run, nil while it's on
+func (e *Enricher) Disabled() error {
+	if e == nil {
+		return nil
+	}
+	return e.disabled
+}
+
+// Close closes the Source
+func (e *Enricher) Close() error {
+	if e == nil {
+		return nil
+	}
+	return e.source.Close()
+}
@@ -0,0 +1,130 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+// fakeSource is a library keyed by "artist|title", both normalized
+type fakeSource struct {
+	tracks  map[string]Match
+	err     error         // Returned by every lookup when set
+	delay   time.Duration // Each lookup takes this long
+	lookups int
+}
+
+func (f *fakeSource) Lookup(ctx context.Context, artist, title string) (Match, bool, error) {
+	f.lookups++
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return Match{}, false, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return Match{}, false, f.err
+	}
+	match, ok := f.tracks[artist+"|"+title]
+	return match, ok, nil
+}
+
+func (f *fakeSource) Close() error { return nil }
+
+func testLibrary() *fakeSource {
+	return &fakeSource{tracks: map[string]Match{
+		"laura dre|moon pool":         {Label: "Italians Do It Better", Year: "2019"},
+		"pure obsessions|crystallize": {Year: "2021"},
+		"airline food|no label":       {},
+	}}
+}
+
+func TestEnrich(t *testing.T) {
+	tracks := []cue.Track{
+		{Artist: "Laura Dre", Title: "Moon Pool"},
+		{Artist: "  PURE  Obsessions ", Title: "Crystallize"},
+		{Artist: "Airline Food", Title: "No Label"},
+		{Artist: "Unknown", Title: "Not In The Library"},
+		{Artist: "", Title: "No Artist"},
+	}
+
+	enricher := New(testLibrary(), 0)
+	enriched, stats, err := enricher.Enrich(context.Background(), tracks)
+	if err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+
+	want := []Match{{"Italians Do It Better", "2019"}, {"", "2021"}, {}, {}, {}}
+	for i, track := range enriched {
+		if track.Label != want[i].Label || track.Year != want[i].Year {
+			t.Errorf("track %d = %q, %q; want %q, %q", i, track.Label, track.Year, want[i].Label, want[i].Year)
+		}
+	}
+	if tracks[0].Label != "" {
+		t.Error("Enrich changed the caller's tracks")
+	}
+	if stats.LookedUp != 4 || stats.Matched != 2 || stats.HitRate() != 50 || stats.TimedOut {
+		t.Errorf("stats = %+v (hit rate %.0f%%), want 4 looked up, 2 matched", stats, stats.HitRate())
+	}
+}
+
+func TestEnrichDatabaseErrorDisables(t *testing.T) {
+	library := testLibrary()
+	library.err = errors.New("database is locked")
+	enricher := New(library, 0)
+	tracks := []cue.Track{{Artist: "Laura Dre", Title: "Moon Pool"}}
+
+	if _, _, err := enricher.Enrich(context.Background(), tracks); err == nil {
+		t.Fatal("Enrich() succeeded on a database error")
+	}
+	if enricher.Disabled() == nil {
+		t.Fatal("enricher still enabled after a database error")
+	}
+
+	library.err = nil
+	enriched, _, err := enricher.Enrich(context.Background(), tracks)
+	if err != nil || enriched[0].Label != "" || library.lookups != 1 {
+		t.Errorf("disabled enricher looked tracks up: %v, %+v, %d lookups", err, enriched[0], library.lookups)
+	}
+}
+
+func TestEnrichTimeout(t *testing.T) {
+	library := testLibrary()
+	library.delay = 30 * time.Millisecond
+	enricher := New(library, 50*time.Millisecond)
+	tracks := []cue.Track{
+		{Artist: "Laura Dre", Title: "Moon Pool"},
+		{Artist: "Pure Obsessions", Title: "Crystallize"},
+		{Artist: "Airline Food", Title: "No Label"},
+	}
+
+	enriched, stats, err := enricher.Enrich(context.Background(), tracks)
+	if err != nil {
+		t.Fatalf("Enrich() error = %v, want the timeout to leave tracks unenriched", err)
+	}
+	if !stats.TimedOut || stats.LookedUp >= len(tracks) || enriched[len(tracks)-1].Label != "" {
+		t.Errorf("stats = %+v, want the time to run out before the last track", stats)
+	}
+	if enricher.Disabled() != nil {
+		t.Error("a timeout disabled the enricher")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Laura Dre", "laura dre"},
+		{"  The   Cure ", "the cure"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
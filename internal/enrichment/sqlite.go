@@ -0,0 +1,78 @@
+package enrichment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	_ "modernc.org/sqlite" // Pure Go, so builds stay cgo-free
+)
+
+// DefaultQuery looks a track up in the scheduling system's export. The query
+// gets the normalized artist and title as :artist and :title and returns the
+// label and year of the first match; compare against columns normalized the
+// same way, e.g. lower(trim(artist)).
+const DefaultQuery = `SELECT label, year FROM tracks WHERE lower(trim(artist)) = :artist AND lower(trim(title)) = :title LIMIT 1`
+
+// sqlSource looks tracks up with a prepared statement
+type sqlSource struct {
+	db   *sql.DB
+	stmt *sql.Stmt
+}
+
+// OpenSQLite opens the SQLite database at path read-only and prepares query
+// (DefaultQuery when empty)
+func OpenSQLite(path, query string) (Source, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("enrichment database: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("opening enrichment database %s: %w", path, err)
+	}
+	source, err := NewSQLSource(db, query)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enrichment database %s: %w", path, err)
+	}
+	return source, nil
+}
+
+// NewSQLSource prepares query (DefaultQuery when empty) on db and runs it once,
+// so a broken query or table fails here rather than on the first show. The
+// Source closes db with itself.
+func NewSQLSource(db *sql.DB, query string) (Source, error) {
+	if query == "" {
+		query = DefaultQuery
+	}
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("preparing query: %w", err)
+	}
+	source := &sqlSource{db: db, stmt: stmt}
+	if _, _, err := source.Lookup(context.Background(), "", ""); err != nil {
+		stmt.Close()
+		return nil, fmt.Errorf("checking query: %w", err)
+	}
+	return source, nil
+}
+
+func (s *sqlSource) Lookup(ctx context.Context, artist, title string) (Match, bool, error) {
+	var label, year sql.NullString
+	err := s.stmt.QueryRowContext(ctx, sql.Named("artist", artist), sql.Named("title", title)).Scan(&label, &year)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Match{}, false, nil
+	}
+	if err != nil {
+		return Match{}, false, err
+	}
+	return Match{Label: label.String, Year: year.String}, true, nil
+}
+
+func (s *sqlSource) Close() error {
+	s.stmt.Close()
+	return s.db.Close()
+}
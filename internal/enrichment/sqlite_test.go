@@ -0,0 +1,86 @@
+package enrichment
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// libraryFixture is a cut-down scheduling system export; year is an integer
+// column like the real one
+const libraryFixture = `
+CREATE TABLE tracks (artist TEXT, title TEXT, label TEXT, year INTEGER);
+INSERT INTO tracks VALUES ('Laura Dre', 'Moon Pool', 'Italians Do It Better', 2019);
+INSERT INTO tracks VALUES (' Pure Obsessions', 'Crystallize ', NULL, 2021);
+`
+
+// createLibrary fills db with libraryFixture
+func createLibrary(t *testing.T, db *sql.DB) {
+	t.Helper()
+	if _, err := db.Exec(libraryFixture); err != nil {
+		t.Fatalf("creating fixture library: %v", err)
+	}
+}
+
+func TestSQLSource(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1) // Every connection to :memory: is a database of its own
+	createLibrary(t, db)
+
+	source, err := NewSQLSource(db, "")
+	if err != nil {
+		t.Fatalf("NewSQLSource() error = %v", err)
+	}
+	defer source.Close()
+
+	tests := []struct {
+		artist, title string
+		want          Match
+		wantOK        bool
+	}{
+		{"laura dre", "moon pool", Match{Label: "Italians Do It Better", Year: "2019"}, true},
+		{"pure obsessions", "crystallize", Match{Year: "2021"}, true},
+		{"airline food", "moon pool", Match{}, false},
+	}
+	for _, tt := range tests {
+		match, ok, err := source.Lookup(context.Background(), tt.artist, tt.title)
+		if err != nil || ok != tt.wantOK || match != tt.want {
+			t.Errorf("Lookup(%q, %q) = %+v, %v, %v; want %+v, %v", tt.artist, tt.title, match, ok, err, tt.want, tt.wantOK)
+		}
+	}
+
+	if _, err := NewSQLSource(db, "SELEC label FROM tracks"); err == nil {
+		t.Error("NewSQLSource() accepted a broken query")
+	}
+}
+
+func TestOpenSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createLibrary(t, db)
+	db.Close()
+
+	custom := `SELECT label, year FROM tracks WHERE lower(trim(artist)) = :artist AND lower(trim(title)) = :title AND year > 2020`
+	source, err := OpenSQLite(path, custom)
+	if err != nil {
+		t.Fatalf("OpenSQLite() error = %v", err)
+	}
+	defer source.Close()
+	if _, ok, err := source.Lookup(context.Background(), "laura dre", "moon pool"); ok || err != nil {
+		t.Errorf("Lookup() = %v, %v; want the custom query's filter applied", ok, err)
+	}
+	if match, ok, err := source.Lookup(context.Background(), "pure obsessions", "crystallize"); !ok || err != nil || match.Year != "2021" {
+		t.Errorf("Lookup() = %+v, %v, %v; want the 2021 release", match, ok, err)
+	}
+
+	if _, err := OpenSQLite(filepath.Join(t.TempDir(), "missing.db"), ""); err == nil {
+		t.Error("OpenSQLite() opened a missing database")
+	}
+}
@@ -22,8 +22,11 @@ import (
 type Filter struct {
 	excludedArtists       []string         // Case-insensitive string matches for artists
 	excludedTitles        []string         // Case-insensitive string matches for titles
+	artistsContaining     []string         // Case-insensitive substrings of artists
+	titlesContaining      []string         // Case-insensitive substrings of titles
 	excludedArtistRegex   []*regexp.Regexp // Compiled regex patterns for artists
 	excludedTitleRegex    []*regexp.Regexp // Compiled regex patterns for titles
+	defaultArtistRegex    bool             // excludedArtistRegex holds defaultStationPatterns
 }
 
 // FilterStats holds statistics about filtering operations
@@ -67,23 +70,15 @@ func NewFilter(cfg *config.Config) (*Filter, error) {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
+	// Lowercase every string for case-insensitive matching
+	// AIDEV-NOTE: excluded_artists and excluded_titles only match whole values;
+	// substring matching is opt-in through the _contains lists, so "X" can't
+	// exclude "The xx"
 	filter := &Filter{
-		excludedArtists: make([]string, 0),
-		excludedTitles:  make([]string, 0),
-	}
-
-	// Process excluded artists (convert to lowercase for case-insensitive matching)
-	for _, artist := range cfg.Filtering.ExcludedArtists {
-		if strings.TrimSpace(artist) != "" {
-			filter.excludedArtists = append(filter.excludedArtists, strings.ToLower(strings.TrimSpace(artist)))
-		}
-	}
-
-	// Process excluded titles (convert to lowercase for case-insensitive matching)
-	for _, title := range cfg.Filtering.ExcludedTitles {
-		if strings.TrimSpace(title) != "" {
-			filter.excludedTitles = append(filter.excludedTitles, strings.ToLower(strings.TrimSpace(title)))
-		}
+		excludedArtists:   lowerEntries(cfg.Filtering.ExcludedArtists),
+		excludedTitles:    lowerEntries(cfg.Filtering.ExcludedTitles),
+		artistsContaining: lowerEntries(cfg.Filtering.ExcludedArtistsContains),
+		titlesContaining:  lowerEntries(cfg.Filtering.ExcludedTitlesContains),
 	}
 
 	// Compile artist regex patterns
@@ -121,11 +116,31 @@ func NewFilter(cfg *config.Config) (*Filter, error) {
 			}
 			filter.excludedArtistRegex = append(filter.excludedArtistRegex, compiled)
 		}
+		filter.defaultArtistRegex = true
 	}
 
 	return filter, nil
 }
 
+// lowerEntries returns the non-blank entries trimmed and in lowercase
+func lowerEntries(entries []string) []string {
+	lowered := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) != "" {
+			lowered = append(lowered, strings.ToLower(strings.TrimSpace(entry)))
+		}
+	}
+	return lowered
+}
+
+// WithExcludedArtists returns a copy of f that also excludes artists, matched
+// exactly like excluded_artists. f is left unchanged.
+func (f *Filter) WithExcludedArtists(artists []string) *Filter {
+	extended := *f
+	extended.excludedArtists = append(append([]string{}, f.excludedArtists...), lowerEntries(artists)...)
+	return &extended
+}
+
 // GetStats returns current filtering statistics
 func (f *Filter) GetStats() *FilterStats {
 	return &FilterStats{
@@ -165,14 +180,14 @@ func (f *Filter) isExcludedByStringContains(artist, title string) (bool, string,
 	titleLower := strings.ToLower(strings.TrimSpace(title))
 
 	// Check if artist contains any excluded artist strings
-	for _, excluded := range f.excludedArtists {
+	for _, excluded := range f.artistsContaining {
 		if strings.Contains(artistLower, excluded) {
 			return true, "excluded_artist_contains", excluded
 		}
 	}
 
 	// Check if title contains any excluded title strings
-	for _, excluded := range f.excludedTitles {
+	for _, excluded := range f.titlesContaining {
 		if strings.Contains(titleLower, excluded) {
 			return true, "excluded_title_contains", excluded
 		}
@@ -235,28 +250,39 @@ func matchesAnyRegexPattern(input string, patterns []*regexp.Regexp) (bool, stri
 	return false, ""
 }
 
+// stationGenres are genre substrings that mark station content rather than music
+var stationGenres = []string{
+	"sweepers", "sweeper", "bumpers", "bumper",
+	"station id", "commercial", "advertisement",
+	"promo", "ident", "jingle",
+}
+
 // isGenreExcluded checks if a track's genre should be filtered out
 // AIDEV-NOTE: Special handling for genre-based filtering (e.g., "Sweepers")
 func (f *Filter) isGenreExcluded(genre string) (bool, string) {
+	return stationGenre(genre)
+}
+
+// IsStationGenre reports whether genre marks station content, e.g. "Sweepers"
+// or "Station ID", which the filter excludes whatever the config says
+func IsStationGenre(genre string) bool {
+	excluded, _ := stationGenre(genre)
+	return excluded
+}
+
+// stationGenre returns the station genre substring genre contains, if any
+func stationGenre(genre string) (bool, string) {
 	if strings.TrimSpace(genre) == "" {
 		return false, ""
 	}
 
 	genreLower := strings.ToLower(strings.TrimSpace(genre))
-	
-	// Check for common station content genres
-	stationGenres := []string{
-		"sweepers", "sweeper", "bumpers", "bumper", 
-		"station id", "commercial", "advertisement", 
-		"promo", "ident", "jingle",
-	}
-	
 	for _, excluded := range stationGenres {
 		if strings.Contains(genreLower, excluded) {
 			return true, excluded
 		}
 	}
-	
+
 	return false, ""
 }
 
@@ -307,6 +333,21 @@ func (f *Filter) ShouldIncludeTrack(track *cue.Track) bool {
 	return true
 }
 
+// Apply returns the tracks that pass the filters, in order, and the number of
+// excluded tracks per FilterResult.Reason (e.g. "excluded_artist_regex": 3)
+func (f *Filter) Apply(tracks []cue.Track) ([]cue.Track, map[string]int) {
+	included := make([]cue.Track, 0, len(tracks))
+	excludedReasons := make(map[string]int)
+	for i := range tracks {
+		if f.ShouldIncludeTrack(&tracks[i]) {
+			included = append(included, tracks[i])
+			continue
+		}
+		excludedReasons[f.FilterTrack(&tracks[i]).Reason]++
+	}
+	return included, excludedReasons
+}
+
 // FilterTrack returns detailed information about why a track was filtered
 // AIDEV-NOTE: Alternative to ShouldIncludeTrack that provides detailed results
 func (f *Filter) FilterTrack(track *cue.Track) FilterResult {
@@ -370,4 +411,49 @@ func (f *Filter) FilterTrack(track *cue.Track) FilterResult {
 		Reason:        "",
 		MatchedValue:  "",
 	}
-}
\ No newline at end of file
+}
+
+// reasonRules describes the rule behind each FilterResult.Reason: the track
+// field it checks, how it compares and where the rule is configured
+var reasonRules = map[string]struct{ field, compare, source string }{
+	"excluded_artist":          {"artist", "equals", "filtering.excluded_artists"},
+	"excluded_title":           {"title", "equals", "filtering.excluded_titles"},
+	"excluded_artist_contains": {"artist", "contains", "filtering.excluded_artists_contains"},
+	"excluded_title_contains":  {"title", "contains", "filtering.excluded_titles_contains"},
+	"excluded_artist_regex":    {"artist", "matches", "filtering.excluded_artist_patterns"},
+	"excluded_title_regex":     {"title", "matches", "filtering.excluded_title_patterns"},
+	"excluded_genre":           {"genre", "contains", "built-in station genres"},
+}
+
+// ReasonField returns the track field a FilterResult.Reason refers to
+// ("artist", "title" or "genre"), or "" for reasons not tied to one field
+func ReasonField(reason string) string {
+	return reasonRules[reason].field
+}
+
+// Explain describes the rule that excluded a track, e.g.
+// `artist matches "(?i)sweeper" (filtering.excluded_artist_patterns)`.
+// Included tracks yield "".
+// AIDEV-NOTE: Exact and contains rules are stored lowercased, so MatchedValue is
+// the configured string in lowercase
+func (f *Filter) Explain(result FilterResult) string {
+	if result.ShouldInclude {
+		return ""
+	}
+	rule, ok := reasonRules[result.Reason]
+	if !ok {
+		switch result.Reason {
+		case "empty_track":
+			return "artist and title are both empty"
+		case "nil_track":
+			return "no track"
+		}
+		return result.Reason
+	}
+
+	source := rule.source
+	if result.Reason == "excluded_artist_regex" && f.defaultArtistRegex {
+		source = "built-in station patterns, used while excluded_artist_patterns is empty"
+	}
+	return fmt.Sprintf("%s %s %q (%s)", rule.field, rule.compare, result.MatchedValue, source)
+}
@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+func TestExactAndContainsLists(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Filtering.ExcludedArtists = []string{"X", "Station ID"}
+	cfg.Filtering.ExcludedTitles = []string{"Intro"}
+	cfg.Filtering.ExcludedArtistsContains = []string{"Sponsor"}
+	cfg.Filtering.ExcludedTitlesContains = []string{"(Radio Edit Bumper)"}
+	// A pattern that matches nothing keeps the default station patterns out of the way
+	cfg.Filtering.ExcludedArtistPatterns = []string{"^$"}
+
+	f, err := NewFilter(cfg)
+	if err != nil {
+		t.Fatalf("NewFilter() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		artist     string
+		title      string
+		wantReason string // "" when the track is kept
+	}{
+		{"exact artist", "X", "Marks the Spot", "excluded_artist"},
+		{"exact artist, other case", "station id", "Top of the Hour", "excluded_artist"},
+		{"exact list doesn't match inside names", "The xx", "Intro", "excluded_title"},
+		{"short exact entry keeps other artists", "Xiu Xiu", "I Luv the Valley OH!", ""},
+		{"longer exact entry keeps other artists", "Station ID Remix Crew", "Anthem", ""},
+		{"exact title only", "The xx", "Intro (Live)", ""},
+		{"artist contains", "Our Sponsor Of The Hour", "Message", "excluded_artist_contains"},
+		{"title contains", "Blondie", "Atomic (Radio Edit Bumper)", "excluded_title_contains"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			track := &cue.Track{Index: 1, Artist: tt.artist, Title: tt.title}
+			result := f.FilterTrack(track)
+			if result.Reason != tt.wantReason {
+				t.Errorf("FilterTrack(%q - %q) reason = %q, want %q", tt.artist, tt.title, result.Reason, tt.wantReason)
+			}
+			if include := f.ShouldIncludeTrack(track); include != (tt.wantReason == "") {
+				t.Errorf("ShouldIncludeTrack(%q - %q) = %v, disagrees with FilterTrack", tt.artist, tt.title, include)
+			}
+		})
+	}
+}
+
+func TestExplainContainsSource(t *testing.T) {
+	f := &Filter{}
+	got := f.Explain(FilterResult{Reason: "excluded_artist_contains", MatchedValue: "sponsor"})
+	if want := `artist contains "sponsor" (filtering.excluded_artists_contains)`; got != want {
+		t.Errorf("Explain() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+// AIDEV-NOTE: Compact mode is for cross-posting to platforms with short description
+// limits - one flowing line, no timestamps, truncated at entry boundaries
+
+// DefaultCompactSeparator joins entries in compact mode
+const DefaultCompactSeparator = " · "
+
+// CompactOptions configures the compact single-line output mode
+type CompactOptions struct {
+	Separator string // Between entries (default: DefaultCompactSeparator)
+	MaxLength int    // Character limit for this invocation (default: formatter's max length)
+}
+
+// compactOptionsFor returns the compact options of the show metadata
+// describes: its compact_separator, and compact_max_length or else its
+// description limit. Every compact path builds its options here.
+func (f *Formatter) compactOptionsFor(metadata template.Metadata) CompactOptions {
+	maxLength := metadata.Compact.MaxLength
+	if maxLength <= 0 {
+		maxLength = f.maxLengthFor(metadata)
+	}
+	return CompactOptions{Separator: metadata.Compact.Separator, MaxLength: maxLength}
+}
+
+// FormatCompact renders tracks as a single line: "Artist – Title · Artist – Title · ..."
+// If the line exceeds the limit it is cut at an entry boundary and "+N more" appended.
+// Lengths are counted in characters, not bytes, since the separators are multi-byte.
+func (f *Formatter) FormatCompact(tracks []cue.Track, trackFilter *filter.Filter, opts CompactOptions) string {
+	if len(tracks) == 0 {
+		return ""
+	}
+
+	separator := opts.Separator
+	if separator == "" {
+		separator = DefaultCompactSeparator
+	}
+	maxLength := opts.MaxLength
+	if maxLength <= 0 {
+		maxLength = f.maxLength
+	}
+
+	var entries []string
+	for _, track := range f.applyFilter(tracks, trackFilter) {
+		if entry := formatCompactEntry(&track); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return truncateCompact(entries, separator, maxLength)
+}
+
+// formatCompactProtected is FormatCompact between the protected lines, whose
+// space comes out of the limit first
+func (f *Formatter) formatCompactProtected(tracks []cue.Track, trackFilter *filter.Filter, opts CompactOptions) string {
+	protected := template.ProtectedFor(f.config, template.CompactTemplateName)
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = f.maxLength
+	}
+	if opts.MaxLength = protected.Reserve(opts.MaxLength); opts.MaxLength <= 0 {
+		return protected.Wrap("")
+	}
+	return protected.Wrap(f.FormatCompact(tracks, trackFilter, opts))
+}
+
+// formatCompactEntry formats a single track as "Artist – Title"
+func formatCompactEntry(track *cue.Track) string {
+	artist := strings.TrimSpace(track.Artist)
+	title := strings.TrimSpace(track.Title)
+
+	switch {
+	case artist != "" && title != "":
+		return artist + " – " + title
+	case title != "":
+		return title
+	default:
+		return artist
+	}
+}
+
+// truncateCompact joins as many whole entries as fit within maxLength characters,
+// appending "+N more" for the entries that were dropped
+func truncateCompact(entries []string, separator string, maxLength int) string {
+	full := strings.Join(entries, separator)
+	if utf8.RuneCountInString(full) <= maxLength {
+		return full
+	}
+
+	sepLength := utf8.RuneCountInString(separator)
+
+	// Walk back from the longest prefix until prefix + suffix fits
+	prefixLength := utf8.RuneCountInString(full)
+	for kept := len(entries) - 1; kept >= 0; kept-- {
+		// Drop the last kept entry (and its separator) from the running prefix length
+		prefixLength -= utf8.RuneCountInString(entries[kept])
+		if kept > 0 {
+			prefixLength -= sepLength
+		}
+
+		suffix := fmt.Sprintf("+%d more", len(entries)-kept)
+		length := utf8.RuneCountInString(suffix)
+		if kept > 0 {
+			length += prefixLength + sepLength
+		}
+		if length <= maxLength {
+			if kept == 0 {
+				return suffix
+			}
+			return strings.Join(entries[:kept], separator) + separator + suffix
+		}
+	}
+
+	// Not even the suffix fits
+	return ""
+}
@@ -0,0 +1,146 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+func compactTestTracks() []cue.Track {
+	return []cue.Track{
+		{StartTime: "00:00", Artist: "Laura Dre", Title: "When I Fall"},
+		{StartTime: "04:48", Artist: "Pure Obsessions", Title: "Dive Deep Into the Night"},
+		{StartTime: "08:15", Artist: "Airline Food", Title: "Conditional Love"},
+		{StartTime: "12:01", Artist: "Ultravox", Title: "Vienna"},
+	}
+}
+
+func TestFormatCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		tracks   []cue.Track
+		opts     CompactOptions
+		expected string
+	}{
+		{
+			name:     "empty tracks",
+			tracks:   nil,
+			expected: "",
+		},
+		{
+			name:     "all entries fit",
+			tracks:   compactTestTracks()[:2],
+			expected: "Laura Dre – When I Fall · Pure Obsessions – Dive Deep Into the Night",
+		},
+		{
+			name:     "custom separator",
+			tracks:   compactTestTracks()[:2],
+			opts:     CompactOptions{Separator: " / "},
+			expected: "Laura Dre – When I Fall / Pure Obsessions – Dive Deep Into the Night",
+		},
+		{
+			name:     "missing artist or title",
+			tracks:   []cue.Track{{Title: "Untitled Jam"}, {Artist: "Solo Artist"}},
+			expected: "Untitled Jam · Solo Artist",
+		},
+		{
+			name:     "truncates at entry boundary",
+			tracks:   compactTestTracks(),
+			opts:     CompactOptions{MaxLength: 80},
+			expected: "Laura Dre – When I Fall · Pure Obsessions – Dive Deep Into the Night · +2 more",
+		},
+		{
+			name:     "only the suffix fits",
+			tracks:   compactTestTracks(),
+			opts:     CompactOptions{MaxLength: 10},
+			expected: "+4 more",
+		},
+	}
+
+	f := NewFormatter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := f.FormatCompact(tt.tracks, nil, tt.opts)
+			if result != tt.expected {
+				t.Errorf("FormatCompact() = %q, want %q", result, tt.expected)
+			}
+			if tt.opts.MaxLength > 0 && utf8.RuneCountInString(result) > tt.opts.MaxLength {
+				t.Errorf("FormatCompact() length %d exceeds %d", utf8.RuneCountInString(result), tt.opts.MaxLength)
+			}
+		})
+	}
+}
+
+func TestFormatCompactMaxLengthPerInvocation(t *testing.T) {
+	f := NewFormatter()
+	f.SetMaxLength(60)
+
+	tracks := compactTestTracks()
+
+	// Formatter limit applies when no override is given
+	withDefault := f.FormatCompact(tracks, nil, CompactOptions{})
+	if utf8.RuneCountInString(withDefault) > 60 || !strings.HasSuffix(withDefault, "more") {
+		t.Errorf("expected truncation at formatter max length, got %q", withDefault)
+	}
+
+	// Per-invocation override leaves the formatter's own limit untouched
+	withOverride := f.FormatCompact(tracks, nil, CompactOptions{MaxLength: 300})
+	if strings.Contains(withOverride, "more") {
+		t.Errorf("expected all entries with a 300 character limit, got %q", withOverride)
+	}
+	if f.GetMaxLength() != 60 {
+		t.Errorf("GetMaxLength() = %d, want 60", f.GetMaxLength())
+	}
+}
+
+func TestCompactSelectedByShowConfig(t *testing.T) {
+	showCfg := &config.ShowConfig{
+		TemplateName:     "compact",
+		CompactSeparator: " | ",
+		CompactMaxLength: 50,
+	}
+
+	// No [templates.config] section - compact is still available as a built-in
+	f := NewFormatterWithConfig(&config.Config{})
+
+	name, err := f.SelectTemplateForShow(showCfg)
+	if err != nil || name != "compact" {
+		t.Fatalf("SelectTemplateForShow() = %q, %v; want compact", name, err)
+	}
+
+	result := f.FormatTracklistWithShowConfig(compactTestTracks(), nil, showCfg, template.Metadata{})
+	expected := "Laura Dre – When I Fall | +3 more"
+	if result != expected {
+		t.Errorf("FormatTracklistWithShowConfig() = %q, want %q", result, expected)
+	}
+}
+
+func TestCompactTemplatePathUsesShowOptions(t *testing.T) {
+	f := NewFormatterWithConfig(&config.Config{})
+	showCfg := &config.ShowConfig{CompactSeparator: " | ", CompactMaxLength: 50}
+	metadata := template.Metadata{Compact: template.CompactStyleFor(showCfg), MaxLength: 4000}
+
+	// -template compact on a show whose own template is something else
+	result := f.FormatTracklistWithTemplate(compactTestTracks(), nil, "compact", metadata)
+	expected := "Laura Dre – When I Fall | +3 more"
+	if result != expected {
+		t.Errorf("FormatTracklistWithTemplate() = %q, want %q", result, expected)
+	}
+}
+
+func TestUserDefinedCompactTemplateTakesPrecedence(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"compact": {Track: "{{.Title}};"},
+	}
+	f := NewFormatterWithConfig(cfg)
+
+	result := f.FormatTracklistWithTemplate(compactTestTracks()[:2], nil, "compact", template.Metadata{})
+	if result != "When I Fall;Dive Deep Into the Night;" {
+		t.Errorf("FormatTracklistWithTemplate() = %q, want user-defined compact output", result)
+	}
+}
@@ -11,7 +11,10 @@ import (
 	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
 	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
 	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/links"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
 	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textcut"
 )
 
 // AIDEV-NOTE: Mixcloud has a 1000 character limit for descriptions
@@ -42,7 +45,7 @@ type FormatOptions struct {
 func DefaultFormatOptions() FormatOptions {
 	return FormatOptions{
 		MaxLength:      constants.MixcloudDescriptionLimit,
-		TruncationText: "... and more",
+		TruncationText: messages.Default().AndMore,
 		LineFormat:     "", // Will use default auto format
 		IncludeNumbers: false,
 	}
@@ -63,8 +66,8 @@ func NewFormatterWithConfig(cfg *config.Config) *Formatter {
 		config:    cfg,
 	}
 	
-	// Initialize template formatter if templates are configured
-	if cfg != nil && len(cfg.Templates.Config) > 0 {
+	// Initialize template formatter with the built-in and configured templates
+	if cfg != nil {
 		templateFormatter := template.NewTemplateFormatter(cfg)
 		if err := templateFormatter.LoadTemplates(); err == nil {
 			formatter.templateFormatter = templateFormatter
@@ -87,11 +90,32 @@ func NewFormatterWithOptions(options FormatOptions) *Formatter {
 	}
 }
 
+// SetLinks sets the artist table behind the artistLink template function.
+// Built-in modes don't render links.
+func (f *Formatter) SetLinks(table *links.Table) {
+	if f.templateFormatter != nil {
+		f.templateFormatter.SetLinks(table)
+	}
+}
+
+// length measures text against maxLength using the configured length_model
+func (f *Formatter) length(text string) int {
+	return f.config.DescriptionLengthModel().Length(text)
+}
+
 // GetMaxLength returns the current character limit setting
 func (f *Formatter) GetMaxLength() int {
 	return f.maxLength
 }
 
+// maxLengthFor returns the character limit for one description: metadata's
+// MaxLength (a show's description_max_length) or the formatter's own limit.
+// AIDEV-NOTE: The formatter is shared by every show in a batch, so per-show
+// limits travel with each call's metadata rather than through SetMaxLength.
+func (f *Formatter) maxLengthFor(metadata template.Metadata) int {
+	return metadata.Limit(f.maxLength)
+}
+
 // SetMaxLength updates the character limit (useful for testing)
 func (f *Formatter) SetMaxLength(length int) {
 	if length > 0 {
@@ -107,6 +131,11 @@ func (f *Formatter) FormatTracklist(tracks []cue.Track, trackFilter *filter.Filt
 		return ""
 	}
 	
+	// Built-in compact mode as the configured default
+	if f.config != nil && f.config.Templates.Default == template.CompactTemplateName && !f.HasTemplate(template.CompactTemplateName) {
+		return f.formatCompactProtected(tracks, trackFilter, f.compactOptionsFor(template.Metadata{}))
+	}
+
 	// Check if template formatting is available and configured
 	if f.templateFormatter != nil && f.config != nil {
 		// Use template-based formatting with default template
@@ -116,7 +145,7 @@ func (f *Formatter) FormatTracklist(tracks []cue.Track, trackFilter *filter.Filt
 			filteredTracks := f.applyFilter(tracks, trackFilter)
 			
 			// Use template formatting
-			result, err := f.templateFormatter.FormatWithTemplate(defaultTemplate, filteredTracks, trackFilter, nil)
+			result, err := f.templateFormatter.FormatWithTemplate(defaultTemplate, filteredTracks, trackFilter, template.Metadata{})
 			if err == nil {
 				return result
 			}
@@ -129,16 +158,23 @@ func (f *Formatter) FormatTracklist(tracks []cue.Track, trackFilter *filter.Filt
 }
 
 // FormatTracklistWithTemplate formats tracks using a specific template
-func (f *Formatter) FormatTracklistWithTemplate(tracks []cue.Track, trackFilter *filter.Filter, templateName string, metadata map[string]interface{}) string {
+func (f *Formatter) FormatTracklistWithTemplate(tracks []cue.Track, trackFilter *filter.Filter, templateName string, metadata template.Metadata) string {
 	// Handle edge cases
 	if tracks == nil || len(tracks) == 0 {
 		return ""
 	}
+	tracks, trackFilter = f.collapseFor(tracks, trackFilter, metadata)
+	tracks = orderTracks(tracks, metadata)
 	
+	// Built-in compact mode unless the user defined their own "compact" template
+	if templateName == template.CompactTemplateName && !f.HasTemplate(templateName) {
+		return f.formatCompactProtected(tracks, trackFilter, f.compactOptionsFor(metadata))
+	}
+
 	// Check if template formatting is available
 	if f.templateFormatter == nil || !f.templateFormatter.HasTemplate(templateName) {
 		// Fall back to classic formatting
-		return f.formatClassic(tracks, trackFilter)
+		return f.formatClassicFor(tracks, trackFilter, metadata)
 	}
 	
 	// Apply filtering first
@@ -148,23 +184,32 @@ func (f *Formatter) FormatTracklistWithTemplate(tracks []cue.Track, trackFilter
 	result, err := f.templateFormatter.FormatWithTemplate(templateName, filteredTracks, trackFilter, metadata)
 	if err != nil {
 		// Fall back to classic formatting on error
-		return f.formatClassic(tracks, trackFilter)
+		return f.formatClassicFor(tracks, trackFilter, metadata)
 	}
 	
 	return result
 }
 
 // FormatTracklistWithShowConfig formats tracks using template selection based on show configuration
-func (f *Formatter) FormatTracklistWithShowConfig(tracks []cue.Track, trackFilter *filter.Filter, showCfg *config.ShowConfig, metadata map[string]interface{}) string {
+func (f *Formatter) FormatTracklistWithShowConfig(tracks []cue.Track, trackFilter *filter.Filter, showCfg *config.ShowConfig, metadata template.Metadata) string {
 	// Handle edge cases
 	if tracks == nil || len(tracks) == 0 {
 		return ""
 	}
+	tracks, trackFilter = f.collapseFor(tracks, trackFilter, metadata)
+	tracks = orderTracks(tracks, metadata)
 	
+	// Built-in compact mode, with the show's separator and length overrides
+	if templateName, err := f.SelectTemplateForShow(showCfg); err == nil &&
+		templateName == template.CompactTemplateName && !f.HasTemplate(templateName) {
+		metadata.Compact = template.CompactStyleFor(showCfg)
+		return f.formatCompactProtected(tracks, trackFilter, f.compactOptionsFor(metadata))
+	}
+
 	// Check if template formatting is available
 	if f.templateFormatter == nil {
 		// Fall back to classic formatting
-		return f.formatClassic(tracks, trackFilter)
+		return f.formatClassicFor(tracks, trackFilter, metadata)
 	}
 	
 	// Apply filtering first
@@ -174,72 +219,162 @@ func (f *Formatter) FormatTracklistWithShowConfig(tracks []cue.Track, trackFilte
 	result, err := f.templateFormatter.FormatWithShowConfig(filteredTracks, showCfg, metadata)
 	if err != nil {
 		// Fall back to classic formatting on error (including when "classic" is requested)
-		return f.formatClassic(tracks, trackFilter)
+		return f.formatClassicFor(tracks, trackFilter, metadata)
 	}
 	
 	return result
 }
 
-// applyFilter applies the filter to tracks and returns filtered results
-func (f *Formatter) applyFilter(tracks []cue.Track, trackFilter *filter.Filter) []cue.Track {
-	if trackFilter == nil {
-		// No filter, return all non-empty tracks
-		var result []cue.Track
-		for _, track := range tracks {
-			if !track.IsEmpty() {
-				result = append(result, track)
-			}
-		}
-		return result
+// FormatPlaceholder renders the template's header and footer around a placeholder
+// line. Built-in modes have no header or footer, so they get the placeholder alone.
+func (f *Formatter) FormatPlaceholder(templateName string, placeholder string, metadata template.Metadata) string {
+	if f.templateFormatter == nil || !f.templateFormatter.HasTemplate(templateName) {
+		return f.classicPlaceholder(placeholder, metadata)
 	}
-	
-	// Apply filter
+
+	result, err := f.templateFormatter.FormatPlaceholder(templateName, placeholder, metadata)
+	if err != nil {
+		return f.classicPlaceholder(placeholder, metadata)
+	}
+
+	return result
+}
+
+// classicPlaceholder is the placeholder line as built-in modes publish it:
+// between the protected lines, with the announcement and provenance footer
+func (f *Formatter) classicPlaceholder(placeholder string, metadata template.Metadata) string {
+	protected := template.ProtectedFor(f.config, template.ClassicTemplateName)
+	return protected.Wrap(withHeader(classicHeader(metadata), withFooter(placeholder, classicFooter(metadata))))
+}
+
+// orderTracks returns tracks newest first when metadata's TrackOrder is
+// config.TrackOrderReverse, otherwise unchanged. Every formatting mode then lists
+// them in that order and truncation drops from the end, i.e. the oldest tracks.
+func orderTracks(tracks []cue.Track, metadata template.Metadata) []cue.Track {
+	if !metadata.Reversed() {
+		return tracks
+	}
+	reversed := make([]cue.Track, len(tracks))
+	for i, track := range tracks {
+		reversed[len(tracks)-1-i] = track
+	}
+	return reversed
+}
+
+// collapseFor merges consecutive tracks by the same artist when the show
+// described by metadata sets collapse_same_artist. Tracks are filtered first, so
+// the returned ones need no filter; otherwise both are returned unchanged.
+// AIDEV-NOTE: Collapsing before ordering keeps a run's first start time and its
+// titles in play order with track_order = "reverse", and every mode then
+// truncates the collapsed entries, so the length limit sees what is published.
+func (f *Formatter) collapseFor(tracks []cue.Track, trackFilter *filter.Filter, metadata template.Metadata) ([]cue.Track, *filter.Filter) {
+	if !metadata.Collapse.Enabled {
+		return tracks, trackFilter
+	}
+	return template.CollapseTracks(f.applyFilter(tracks, trackFilter), metadata.Collapse), nil
+}
+
+// applyFilter applies the filter to tracks and returns filtered results. Without
+// a filter only empty tracks are dropped. tracks itself is returned when every
+// track is kept, so already filtered tracklists aren't copied again.
+func (f *Formatter) applyFilter(tracks []cue.Track, trackFilter *filter.Filter) []cue.Track {
 	var result []cue.Track
-	for _, track := range tracks {
-		if trackFilter.ShouldIncludeTrack(&track) && !track.IsEmpty() {
-			result = append(result, track)
+	for i := range tracks {
+		keep := (trackFilter == nil || trackFilter.ShouldIncludeTrack(&tracks[i])) && !tracks[i].IsEmpty()
+		switch {
+		case keep && result != nil:
+			result = append(result, tracks[i])
+		case !keep && result == nil:
+			// First dropped track: copy the ones kept before it
+			result = make([]cue.Track, i, len(tracks))
+			copy(result, tracks[:i])
 		}
 	}
+	if result == nil {
+		return tracks
+	}
 	return result
 }
 
 // formatClassic implements the original classic formatting logic
 func (f *Formatter) formatClassic(tracks []cue.Track, trackFilter *filter.Filter) string {
+	protected := template.ProtectedFor(f.config, template.ClassicTemplateName)
+	return protected.Wrap(f.formatClassicWithFooter(tracks, trackFilter, "", protected.Reserve(f.maxLength), messages.Default(), f.config.TrackStyleFor(nil)))
+}
+
+// formatClassicFor formats tracks classically for the show described by metadata:
+// within its length limit, between the protected lines, with the announcement
+// above the tracks and the provenance footer below them when they are enabled.
+func (f *Formatter) formatClassicFor(tracks []cue.Track, trackFilter *filter.Filter, metadata template.Metadata) string {
+	protected := template.ProtectedFor(f.config, template.ClassicTemplateName)
+	return protected.Wrap(f.formatClassicWithin(tracks, trackFilter, metadata, protected.Reserve(f.maxLengthFor(metadata))))
+}
+
+// formatClassicWithin is formatClassicFor without the protected lines, within maxLength
+// AIDEV-NOTE: The announcement's space comes out of the limit before the tracks
+// are truncated, like the footer's; one that leaves no room for tracks is dropped.
+func (f *Formatter) formatClassicWithin(tracks []cue.Track, trackFilter *filter.Filter, metadata template.Metadata, maxLength int) string {
+	header := classicHeader(metadata)
+	footer := classicFooter(metadata)
+	msgs := messagesFor(metadata)
+	style := f.trackStyleFor(metadata)
+	if header == "" {
+		return f.formatClassicWithFooter(tracks, trackFilter, footer, maxLength, msgs, style)
+	}
+
+	trackLength := maxLength - f.length(header) - 2 // -2 for the blank line after it
+	if trackLength <= f.length(msgs.AndMore) {
+		return f.formatClassicWithFooter(tracks, trackFilter, footer, maxLength, msgs, style)
+	}
+	return withHeader(header, f.formatClassicWithFooter(tracks, trackFilter, footer, trackLength, msgs, style))
+}
+
+// formatClassicWithFooter formats tracks classically within maxLength and appends
+// footer on its own line. The footer's space is reserved before truncating, so it
+// is never cut; a footer that leaves no room for tracks is dropped. msgs supplies
+// the fallbacks for missing fields and the truncation marker, style the track_style.
+func (f *Formatter) formatClassicWithFooter(tracks []cue.Track, trackFilter *filter.Filter, footer string, maxLength int, msgs messages.Catalog, style string) string {
+	var tracklist string
 	if trackFilter == nil {
 		// If no filter provided, format all tracks
-		return f.formatAllTracks(tracks)
+		tracklist = f.formatAllTracks(tracks, msgs, style)
+	} else {
+		tracklist = f.formatFilteredTracks(tracks, trackFilter, msgs, style)
+	}
+	if tracklist == "" || footer == "" {
+		return f.truncateToLength(tracklist, maxLength, msgs.AndMore)
 	}
 
+	trackLength := maxLength - f.length(footer) - 1 // -1 for the newline before it
+	if trackLength <= f.length(msgs.AndMore) {
+		return f.truncateToLength(tracklist, maxLength, msgs.AndMore)
+	}
+	return withFooter(f.truncateToLength(tracklist, trackLength, msgs.AndMore), footer)
+}
+
+// formatFilteredTracks formats the tracks the filter includes, one line each
+func (f *Formatter) formatFilteredTracks(tracks []cue.Track, trackFilter *filter.Filter, msgs messages.Catalog, style string) string {
 	// Apply filtering and build formatted lines
 	var lines []string
-	filteredCount := 0
 	
 	for _, track := range tracks {
 		// Apply filter to determine if track should be included
 		if trackFilter.ShouldIncludeTrack(&track) {
 			// Format the track into a line
-			line := f.formatTrackLine(&track)
+			line := f.formatTrackLine(&track, msgs, style)
 			if line != "" { // Only add non-empty lines
 				lines = append(lines, line)
-				filteredCount++
 			}
 		}
 	}
 	
 	// Join lines with newlines to create tracklist
-	tracklist := strings.Join(lines, "\n")
-	
-	// Apply truncation if necessary
-	if len(tracklist) > f.maxLength {
-		tracklist = f.truncateSmartly(tracklist)
-	}
-	
-	return tracklist
+	return strings.Join(lines, "\n")
 }
 
 // formatAllTracks formats all tracks without filtering (helper method)
 // AIDEV-NOTE: Used when no filter is provided
-func (f *Formatter) formatAllTracks(tracks []cue.Track) string {
+func (f *Formatter) formatAllTracks(tracks []cue.Track, msgs messages.Catalog, style string) string {
 	var lines []string
 	
 	for _, track := range tracks {
@@ -248,26 +383,70 @@ func (f *Formatter) formatAllTracks(tracks []cue.Track) string {
 			continue
 		}
 		
-		line := f.formatTrackLine(&track)
+		line := f.formatTrackLine(&track, msgs, style)
 		if line != "" {
 			lines = append(lines, line)
 		}
 	}
 	
 	// Join with newlines
-	tracklist := strings.Join(lines, "\n")
-	
-	// Apply truncation if necessary
-	if len(tracklist) > f.maxLength {
-		tracklist = f.truncateSmartly(tracklist)
+	return strings.Join(lines, "\n")
+}
+
+// trackStyleFor returns the track_style for the show described by metadata,
+// formatting.track_style when the metadata doesn't carry one
+func (f *Formatter) trackStyleFor(metadata template.Metadata) string {
+	if metadata.TrackStyle != "" {
+		return metadata.TrackStyle
 	}
-	
-	return tracklist
+	return f.config.TrackStyleFor(nil)
+}
+
+// messagesFor returns the strings for the show described by metadata: its
+// locale and strings overrides, English by default
+func messagesFor(metadata template.Metadata) messages.Catalog {
+	return metadata.Localized()
+}
+
+// classicFooter returns the line classic formatting appends for the show
+// described by metadata: the provenance line with include_provenance, else ""
+func classicFooter(metadata template.Metadata) string {
+	if !metadata.IncludeProvenance {
+		return ""
+	}
+	return metadata.Provenance()
+}
+
+// classicHeader returns the announcement classic formatting prepends for the show
+// described by metadata: the announcement with announcement_prepend, else ""
+func classicHeader(metadata template.Metadata) string {
+	if !metadata.AnnouncementPrepend {
+		return ""
+	}
+	return metadata.Announcement
+}
+
+// withHeader puts header above text, separated by a blank line. Empty text
+// stays empty so a show with no tracks is still recognised as one.
+func withHeader(header, text string) string {
+	if header == "" || text == "" {
+		return text
+	}
+	return header + "\n\n" + text
+}
+
+// withFooter appends footer to text on its own line
+func withFooter(text, footer string) string {
+	if footer == "" {
+		return text
+	}
+	return text + "\n" + footer
 }
 
 // formatTrackLine formats a single track into the specified string format
-// AIDEV-NOTE: Implements the format: MM:SS - "Track Title" by Artist Name
-func (f *Formatter) formatTrackLine(track *cue.Track) string {
+// AIDEV-NOTE: Implements the format: MM:SS - "Track Title" by Artist Name, or
+// MM:SS - Artist Name - "Track Title" with track_style = "artist-first"
+func (f *Formatter) formatTrackLine(track *cue.Track, msgs messages.Catalog, style string) string {
 	if track == nil || track.IsEmpty() {
 		return ""
 	}
@@ -284,22 +463,25 @@ func (f *Formatter) formatTrackLine(track *cue.Track) string {
 
 	// Handle missing title
 	if title == "" {
-		title = "(Unknown Title)"
+		title = msgs.UnknownTitle
 	}
 
 	// Handle missing artist
 	if artist == "" {
-		artist = "(Unknown Artist)"
+		artist = msgs.UnknownArtist
 	}
 
 	// Apply proper escaping for quotes in titles
 	// AIDEV-NOTE: Escape existing quotes to prevent formatting issues
 	escapedTitle := f.escapeQuotes(title)
 
-	// Format: MM:SS - "Track Title" by Artist Name
-	formatted := fmt.Sprintf(`%s - "%s" by %s`, startTime, escapedTitle, artist)
-
-	return formatted
+	// Format: MM:SS - "Track Title" by Artist Name, from the built-in classic
+	// template, or the artist-first line
+	return template.RenderTrackLine(template.FormattedTrack{
+		StartTime: startTime,
+		Title:     escapedTitle,
+		Artist:    artist,
+	}, style)
 }
 
 // escapeQuotes handles quote escaping in track titles
@@ -343,22 +525,25 @@ func (f *Formatter) GetFormattedTrackCount(tracks []cue.Track, trackFilter *filt
 // truncateSmartly truncates a tracklist at line boundaries while preserving formatting
 // AIDEV-NOTE: Implements smart truncation that cuts at complete track entries, not mid-line
 func (f *Formatter) truncateSmartly(tracklist string) string {
-	if len(tracklist) <= f.maxLength {
+	return f.truncateToLength(tracklist, f.maxLength, messages.Default().AndMore)
+}
+
+// truncateToLength is truncateSmartly with an explicit limit, for callers that
+// reserve part of maxLength for a footer, ending a cut tracklist with truncationText
+func (f *Formatter) truncateToLength(tracklist string, maxLength int, truncationText string) string {
+	if f.length(tracklist) <= maxLength {
 		return tracklist // No truncation needed
 	}
 
-	// Default truncation text
-	truncationText := "... and more"
-	
 	// Account for the truncation text in our length calculation
 	// We need room for the truncation text plus a newline
-	availableLength := f.maxLength - len(truncationText) - 1 // -1 for newline
+	availableLength := maxLength - f.length(truncationText) - 1 // -1 for newline
 	
 	// Handle edge case where truncation text itself is too long
 	if availableLength <= 0 {
 		// If even the truncation text won't fit, just return a simple truncated version
-		if f.maxLength <= len(truncationText) {
-			return truncationText[:f.maxLength]
+		if maxLength <= f.length(truncationText) {
+			return textcut.PrefixBytes(truncationText, maxLength)
 		}
 		return truncationText
 	}
@@ -370,7 +555,7 @@ func (f *Formatter) truncateSmartly(tracklist string) string {
 	}
 
 	// Handle case where even the first line is too long
-	if len(lines[0]) > availableLength {
+	if f.length(lines[0]) > availableLength {
 		// If the first track line itself exceeds the available length,
 		// we need to decide whether to show a partial track or just the truncation text
 		// For formatting integrity, we'll show just the truncation text
@@ -387,7 +572,7 @@ func (f *Formatter) truncateSmartly(tracklist string) string {
 		if i > 0 {
 			newLength += 1 // Add 1 for the newline character
 		}
-		newLength += len(line)
+		newLength += f.length(line)
 		
 		// Check if adding this line would exceed our available length
 		if newLength > availableLength {
@@ -421,6 +606,7 @@ func (f *Formatter) EstimateTracklistLength(tracks []cue.Track, trackFilter *fil
 
 	totalLength := 0
 	trackCount := 0
+	style := f.config.TrackStyleFor(nil)
 
 	for _, track := range tracks {
 		// Check if track would be included
@@ -438,17 +624,23 @@ func (f *Formatter) EstimateTracklistLength(tracks []cue.Track, trackFilter *fil
 		
 		title := track.Title
 		if title == "" {
-			title = "(Unknown Title)"
+			title = messages.Default().UnknownTitle
 		}
 		
 		artist := track.Artist
 		if artist == "" {
-			artist = "(Unknown Artist)"
+			artist = messages.Default().UnknownArtist
 		}
 
 		// Estimate: MM:SS - "Title" by Artist
 		// 5 (time) + 3 (" - ") + 1 (") + len(title) + 1 (") + 4 (" by ") + len(artist)
-		lineLength := 5 + 3 + 1 + len(title) + 1 + 4 + len(artist)
+		// or MM:SS - Artist - "Title", with 3 (" - ") between artist and title
+		lineLength := 5 + 3 + 1 + len(title) + 1 + len(artist)
+		if style == config.TrackStyleArtistFirst {
+			lineLength += 3
+		} else {
+			lineLength += 4
+		}
 		
 		if trackCount > 0 {
 			totalLength += 1 // newline character
@@ -489,6 +681,19 @@ func (f *Formatter) GetDefaultTemplateName() string {
 	return f.templateFormatter.GetDefaultTemplateName()
 }
 
+// CheckTrackSpace fails when the protected lines alone or a template's
+// min_track_space don't fit under limit. Built-in modes have no header or
+// footer, so only their protected lines are checked.
+func (f *Formatter) CheckTrackSpace(templateName string, limit int) error {
+	if err := template.CheckProtectedSpace(f.config, templateName, limit); err != nil {
+		return fmt.Errorf("template %s: %w", templateName, err)
+	}
+	if f.templateFormatter == nil {
+		return nil
+	}
+	return f.templateFormatter.CheckTrackSpace(templateName, limit)
+}
+
 // ValidateTemplate validates a template's syntax and execution
 func (f *Formatter) ValidateTemplate(templateName string) error {
 	if f.templateFormatter == nil {
@@ -500,6 +705,11 @@ func (f *Formatter) ValidateTemplate(templateName string) error {
 // SelectTemplateForShow determines which template to use for a given show configuration
 func (f *Formatter) SelectTemplateForShow(showCfg *config.ShowConfig) (string, error) {
 	if f.templateFormatter == nil {
+		// Built-in modes still apply when no templates are configured
+		selector := template.NewTemplateFormatter(f.config)
+		if name, err := selector.SelectTemplateForShow(showCfg); err == nil && name == template.CompactTemplateName {
+			return name, nil
+		}
 		return "classic", nil
 	}
 	return f.templateFormatter.SelectTemplateForShow(showCfg)
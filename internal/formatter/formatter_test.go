@@ -1,12 +1,16 @@
 package formatter
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
 	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
 	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
 )
 
 func TestNewFormatter(t *testing.T) {
@@ -42,7 +46,7 @@ func TestFormatTrackLine(t *testing.T) {
 		Title:     "Test Title",
 	}
 	
-	result := formatter.formatTrackLine(track)
+	result := formatter.formatTrackLine(track, messages.Default(), config.TrackStyleTitleFirst)
 	expected := `03:45 - "Test Title" by Test Artist`
 	if result != expected {
 		t.Errorf("Expected %q, got %q", expected, result)
@@ -60,7 +64,7 @@ func TestFormatTrackLineWithQuotes(t *testing.T) {
 		Title:     `Song "Title" With Quotes`,
 	}
 	
-	result := formatter.formatTrackLine(track)
+	result := formatter.formatTrackLine(track, messages.Default(), config.TrackStyleTitleFirst)
 	expected := `03:45 - "Song 'Title' With Quotes" by Test Artist`
 	if result != expected {
 		t.Errorf("Expected %q, got %q", expected, result)
@@ -116,7 +120,7 @@ func TestFormatTrackLineEdgeCases(t *testing.T) {
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatter.formatTrackLine(tt.track)
+			result := formatter.formatTrackLine(tt.track, messages.Default(), config.TrackStyleTitleFirst)
 			if result != tt.expected {
 				t.Errorf("Expected %q, got %q", tt.expected, result)
 			}
@@ -228,6 +232,27 @@ func TestTruncateSmartly(t *testing.T) {
 	}
 }
 
+func TestTruncateSmartlyMultibyte(t *testing.T) {
+	tracklist := strings.Join([]string{
+		"00:00 - \"مَرْحَبًا بِكُمْ\" by أُمّ كُلْثُوم",
+		"04:10 - \"夜に駆ける\" by YOASOBI",
+		"08:05 - \"Family 👩\u200d👩\u200d👧 Party 🎉👍🏽\" by 🇯🇵 DJ",
+	}, "\n")
+
+	// Every limit, including those below the length of the truncation text
+	for maxLength := 1; maxLength <= len(tracklist); maxLength++ {
+		formatter := NewFormatter()
+		formatter.SetMaxLength(maxLength)
+		result := formatter.truncateSmartly(tracklist)
+		if !utf8.ValidString(result) {
+			t.Errorf("maxLength %d: result %q is not valid UTF-8", maxLength, result)
+		}
+		if len(result) > maxLength {
+			t.Errorf("maxLength %d: result is %d bytes: %q", maxLength, len(result), result)
+		}
+	}
+}
+
 func TestGetFormattedTrackCount(t *testing.T) {
 	formatter := NewFormatter()
 	
@@ -353,21 +378,27 @@ func TestNewFormatterWithConfigNoTemplates(t *testing.T) {
 		t.Fatal("NewFormatterWithConfig returned nil")
 	}
 
-	if formatter.HasTemplateSupport() {
-		t.Error("Formatter should not have template support when no templates configured")
+	// The built-in templates need no configuration
+	if !formatter.HasTemplateSupport() {
+		t.Error("Formatter should have template support for the built-in templates")
 	}
 
 	templates := formatter.ListAvailableTemplates()
-	if len(templates) != 0 {
-		t.Errorf("Expected 0 templates, got %d", len(templates))
+	if len(templates) != 2 {
+		t.Errorf("Expected the 2 built-in templates, got %d", len(templates))
+	}
+	if !formatter.HasTemplate("numbered") || !formatter.HasTemplate("detailed") {
+		t.Errorf("built-in templates missing from %v", templates)
 	}
 }
 
 func TestFormatTracklistWithTemplate(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name: "Test Station",
 		},
@@ -451,10 +482,10 @@ func TestTemplateHelperMethods(t *testing.T) {
 
 	formatter := NewFormatterWithConfig(cfg)
 
-	// Test ListAvailableTemplates
+	// Test ListAvailableTemplates, the configured and built-in ones
 	templates := formatter.ListAvailableTemplates()
-	if len(templates) != 2 {
-		t.Errorf("Expected 2 templates, got %d", len(templates))
+	if len(templates) != 4 {
+		t.Errorf("Expected 4 templates, got %d", len(templates))
 	}
 
 	// Test HasTemplate
@@ -505,4 +536,673 @@ func TestTemplateHelperMethodsWithoutSupport(t *testing.T) {
 	if err == nil {
 		t.Error("Validation should fail when no template support")
 	}
-}
\ No newline at end of file
+}
+func TestClassicProvenanceSurvivesTruncation(t *testing.T) {
+	provenance := template.Metadata{
+		IncludeProvenance: true,
+		CueFile:           "MYR40628.cue",
+		GeneratedAt:       "2025-06-28 22:14",
+		ToolVersion:       "1.0.0",
+	}
+	wantLine := "Generated 2025-06-28 22:14 from MYR40628.cue by mixcloud-updater v1.0.0"
+
+	var tracks []cue.Track
+	for i := 0; i < 60; i++ {
+		tracks = append(tracks, cue.Track{StartTime: "00:00", Artist: "A Fairly Long Artist Name", Title: "A Fairly Long Song Title"})
+	}
+
+	tests := []struct {
+		name          string
+		maxLength     int
+		tracks        []cue.Track
+		wantTruncated bool
+	}{
+		{"fits", 1000, tracks[:2], false},
+		{"truncated at the Mixcloud limit", 1000, tracks, true},
+		{"aggressive limit", 150, tracks, true},
+		{"limit fits only the truncation text", len(wantLine) + 20, tracks, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.SetMaxLength(tt.maxLength)
+
+			result := formatter.FormatTracklistWithTemplate(tt.tracks, nil, "classic", provenance)
+			if !strings.HasSuffix(result, "\n"+wantLine) {
+				t.Errorf("result doesn't end with the provenance line:\n%s", result)
+			}
+			if len(result) > tt.maxLength {
+				t.Errorf("length %d exceeds max length %d", len(result), tt.maxLength)
+			}
+			if truncated := strings.Contains(result, "... and more"); truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestProtectedLinesSurviveTruncation(t *testing.T) {
+	header := "Licensed under CC BY-NC 4.0 - nowwave.radio/license"
+	footer := "Questions about this tracklist: music@nowwave.radio"
+	protected := len(header) + 1 + len(footer) + 1
+
+	var tracks []cue.Track
+	for i := 0; i < 60; i++ {
+		tracks = append(tracks, cue.Track{StartTime: "00:00", Artist: "A Fairly Long Artist Name", Title: "A Fairly Long Song Title"})
+	}
+	provenance := template.Metadata{IncludeProvenance: true, GeneratedAt: "2025-06-28 22:14", ToolVersion: "1.0.0"}
+
+	tests := []struct {
+		name          string
+		template      string
+		maxLength     int
+		metadata      template.Metadata
+		tracks        []cue.Track
+		wantTruncated bool
+	}{
+		{"classic fits", "classic", 1000, template.Metadata{}, tracks[:2], false},
+		{"classic truncated", "classic", 1000, template.Metadata{}, tracks, true},
+		{"classic squeezed to the truncation text", "classic", protected + 15, template.Metadata{}, tracks, true},
+		{"classic without room for tracks", "classic", protected, template.Metadata{}, tracks, false},
+		// The provenance footer is dropped before the protected lines are touched
+		{"classic provenance dropped", "classic", protected + 20, provenance, tracks, true},
+		{"compact truncated", "compact", 300, template.Metadata{}, tracks, true},
+		{"compact without room for tracks", "compact", protected, template.Metadata{}, tracks, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Formatting.ProtectedHeader = header
+			cfg.Formatting.ProtectedFooter = footer
+			formatter := NewFormatterWithConfig(cfg)
+			formatter.SetMaxLength(tt.maxLength)
+
+			result := formatter.FormatTracklistWithTemplate(tt.tracks, nil, tt.template, tt.metadata)
+			if !strings.HasPrefix(result, header+"\n") || !strings.HasSuffix(result, "\n"+footer) {
+				t.Errorf("protected lines missing or cut:\n%s", result)
+			}
+			if len(result) > tt.maxLength {
+				t.Errorf("length %d exceeds max length %d:\n%s", len(result), tt.maxLength, result)
+			}
+			truncated := strings.Contains(result, "... and more") || strings.Contains(result, " more")
+			if truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v:\n%s", truncated, tt.wantTruncated, result)
+			}
+		})
+	}
+
+	cfg := &config.Config{}
+	cfg.Formatting.ProtectedFooter = footer
+	formatter := NewFormatterWithConfig(cfg)
+	if got, want := formatter.FormatPlaceholder("classic", "Tracklist soon", template.Metadata{}), "Tracklist soon\n"+footer; got != want {
+		t.Errorf("FormatPlaceholder() = %q, want %q", got, want)
+	}
+	if err := formatter.CheckTrackSpace("classic", len(footer)); err == nil || !strings.Contains(err.Error(), "protected_header and protected_footer take") {
+		t.Errorf("CheckTrackSpace() under the protected lines' length = %v, want an error", err)
+	}
+	if err := formatter.CheckTrackSpace("classic", 1000); err != nil {
+		t.Errorf("CheckTrackSpace() = %v", err)
+	}
+}
+
+func TestClassicProvenanceOff(t *testing.T) {
+	formatter := NewFormatter()
+	tracks := []cue.Track{{StartTime: "00:00", Artist: "Artist One", Title: "Song One"}}
+
+	result := formatter.FormatTracklistWithTemplate(tracks, nil, "classic", template.Metadata{CueFile: "MYR40628.cue"})
+	if want := `00:00 - "Song One" by Artist One`; result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+}
+
+func TestTrackOrderReverse(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"numbered": {Track: "{{.Index}}/{{.OriginalIndex}} {{.Title}}\n"},
+	}
+	formatter := NewFormatterWithConfig(cfg)
+
+	tracks := []cue.Track{
+		{StartTime: "00:00", Artist: "Artist One", Title: "Song One"},
+		{StartTime: "03:30", Artist: "Artist Two", Title: "Song Two"},
+		{StartTime: "07:10", Artist: "Artist Three", Title: "Song Three"},
+	}
+	reverse := template.Metadata{TrackOrder: config.TrackOrderReverse}
+
+	tests := []struct {
+		name     string
+		template string
+		metadata template.Metadata
+		want     string
+	}{
+		{"classic chronological", "classic", template.Metadata{},
+			"00:00 - \"Song One\" by Artist One\n03:30 - \"Song Two\" by Artist Two\n07:10 - \"Song Three\" by Artist Three"},
+		{"classic reverse", "classic", reverse,
+			"07:10 - \"Song Three\" by Artist Three\n03:30 - \"Song Two\" by Artist Two\n00:00 - \"Song One\" by Artist One"},
+		{"template chronological", "numbered", template.Metadata{TrackOrder: config.TrackOrderChronological},
+			"1/1 Song One\n2/2 Song Two\n3/3 Song Three"},
+		{"template reverse", "numbered", reverse,
+			"1/3 Song Three\n2/2 Song Two\n3/1 Song One"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatter.FormatTracklistWithTemplate(tracks, nil, tt.template, tt.metadata)
+			if strings.TrimSpace(result) != tt.want {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+
+	if tracks[0].Title != "Song One" {
+		t.Error("reverse order should not reorder the caller's tracks")
+	}
+}
+
+func TestTrackOrderReverseTruncatesOldest(t *testing.T) {
+	var tracks []cue.Track
+	for i := 1; i <= 40; i++ {
+		tracks = append(tracks, cue.Track{StartTime: fmt.Sprintf("%02d:00", i), Artist: "Some Artist", Title: fmt.Sprintf("Song %d", i)})
+	}
+
+	formatter := NewFormatter()
+	formatter.SetMaxLength(300)
+	result := formatter.FormatTracklistWithTemplate(tracks, nil, "classic", template.Metadata{TrackOrder: config.TrackOrderReverse})
+
+	if !strings.HasPrefix(result, `40:00 - "Song 40"`) {
+		t.Errorf("reverse tracklist should start with the newest track:\n%s", result)
+	}
+	if !strings.HasSuffix(result, "... and more") {
+		t.Errorf("tracklist should be truncated:\n%s", result)
+	}
+	if strings.Contains(result, `"Song 1"`) {
+		t.Errorf("truncation should drop the oldest tracks:\n%s", result)
+	}
+}
+
+func TestFormatTrackLineStyles(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name  string
+		style string
+		track cue.Track
+		want  string
+	}{
+		{"title-first", config.TrackStyleTitleFirst,
+			cue.Track{StartTime: "00:25", Artist: "Laura Dre", Title: `When "I" Fall`},
+			`00:25 - "When 'I' Fall" by Laura Dre`},
+		{"title-first missing title", config.TrackStyleTitleFirst,
+			cue.Track{StartTime: "00:25", Artist: "Laura Dre"},
+			`00:25 - "(Unknown Title)" by Laura Dre`},
+		{"title-first missing artist", config.TrackStyleTitleFirst,
+			cue.Track{StartTime: "00:25", Title: "When I Fall"},
+			`00:25 - "When I Fall" by (Unknown Artist)`},
+		{"artist-first", config.TrackStyleArtistFirst,
+			cue.Track{StartTime: "00:25", Artist: "Laura Dre", Title: `When "I" Fall`},
+			`00:25 - Laura Dre - "When 'I' Fall"`},
+		{"artist-first missing title", config.TrackStyleArtistFirst,
+			cue.Track{StartTime: "00:25", Artist: "Laura Dre"},
+			`00:25 - Laura Dre - "(Unknown Title)"`},
+		{"artist-first missing artist", config.TrackStyleArtistFirst,
+			cue.Track{StartTime: "00:25", Title: "When I Fall"},
+			`00:25 - (Unknown Artist) - "When I Fall"`},
+		{"artist-first missing start time", config.TrackStyleArtistFirst,
+			cue.Track{Artist: "Laura Dre", Title: "When I Fall"},
+			`00:00 - Laura Dre - "When I Fall"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatter.formatTrackLine(&tt.track, messages.Default(), tt.style); got != tt.want {
+				t.Errorf("formatTrackLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackStyle(t *testing.T) {
+	tracks := []cue.Track{
+		{StartTime: "00:00", Artist: "Artist One", Title: "Song One"},
+		{StartTime: "03:30", Title: "Song Two"},
+	}
+	titleFirst := "00:00 - \"Song One\" by Artist One\n03:30 - \"Song Two\" by (Unknown Artist)"
+	artistFirst := "00:00 - Artist One - \"Song One\"\n03:30 - (Unknown Artist) - \"Song Two\""
+
+	tests := []struct {
+		name   string
+		global string
+		show   string
+		want   string
+	}{
+		{"default", "", "", titleFirst},
+		{"global artist-first", config.TrackStyleArtistFirst, "", artistFirst},
+		{"show overrides global", config.TrackStyleArtistFirst, config.TrackStyleTitleFirst, titleFirst},
+		{"show artist-first", "", config.TrackStyleArtistFirst, artistFirst},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Formatting.TrackStyle = tt.global
+			formatter := NewFormatterWithConfig(cfg)
+			showCfg := &config.ShowConfig{TemplateName: "classic", TrackStyle: tt.show}
+			metadata := template.Metadata{TrackStyle: cfg.TrackStyleFor(showCfg)}
+
+			got := formatter.FormatTracklistWithShowConfig(tracks, nil, showCfg, metadata)
+			if got != tt.want {
+				t.Errorf("result = %q, want %q", got, tt.want)
+			}
+			if estimated := formatter.EstimateTracklistLength(tracks, nil); tt.show == "" && estimated != len(got) {
+				t.Errorf("EstimateTracklistLength() = %d, want %d", estimated, len(got))
+			}
+		})
+	}
+}
+
+func TestPerCallMaxLength(t *testing.T) {
+	var tracks []cue.Track
+	for i := 0; i < 40; i++ {
+		tracks = append(tracks, cue.Track{StartTime: "00:00", Artist: "Some Artist", Title: "Some Fairly Long Title"})
+	}
+	showLimit := template.Metadata{MaxLength: 250}
+
+	tests := []struct {
+		name     string
+		template string
+		metadata template.Metadata
+		want     int
+	}{
+		{"classic default", "classic", template.Metadata{}, 1000},
+		{"classic show limit", "classic", showLimit, 250},
+		{"compact show limit", "compact", showLimit, 250},
+	}
+
+	formatter := NewFormatter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatter.FormatTracklistWithTemplate(tracks, nil, tt.template, tt.metadata)
+			if length := utf8.RuneCountInString(result); length > tt.want || length < tt.want-60 {
+				t.Errorf("length = %d, want close to the %d limit", length, tt.want)
+			}
+		})
+	}
+
+	if got := formatter.GetMaxLength(); got != 1000 {
+		t.Errorf("a per-call limit changed the formatter's own limit to %d", got)
+	}
+}
+
+func TestClassicAnnouncement(t *testing.T) {
+	announcement := "Pledge drive all week: nowwave.radio/give"
+	var tracks []cue.Track
+	for i := 0; i < 60; i++ {
+		tracks = append(tracks, cue.Track{StartTime: "00:00", Artist: "A Fairly Long Artist Name", Title: "A Fairly Long Song Title"})
+	}
+
+	tests := []struct {
+		name          string
+		metadata      template.Metadata
+		tracks        []cue.Track
+		wantPrefix    bool
+		wantTruncated bool
+	}{
+		{"fits", template.Metadata{Announcement: announcement, AnnouncementPrepend: true}, tracks[:2], true, false},
+		{"truncated", template.Metadata{Announcement: announcement, AnnouncementPrepend: true}, tracks, true, true},
+		{"prepend off", template.Metadata{Announcement: announcement}, tracks[:2], false, false},
+		{"empty announcement", template.Metadata{Announcement: "", AnnouncementPrepend: true}, tracks[:2], false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewFormatter()
+			formatter.SetMaxLength(500)
+
+			result := formatter.FormatTracklistWithTemplate(tt.tracks, nil, "classic", tt.metadata)
+			if prefixed := strings.HasPrefix(result, announcement+"\n\n00:00 - "); prefixed != tt.wantPrefix {
+				t.Errorf("announcement prepended = %v, want %v:\n%s", prefixed, tt.wantPrefix, result)
+			}
+			if !tt.wantPrefix && !strings.HasPrefix(result, "00:00 - ") {
+				t.Errorf("result should start with the first track:\n%s", result)
+			}
+			if len(result) > 500 {
+				t.Errorf("length %d exceeds max length 500", len(result))
+			}
+			if truncated := strings.Contains(result, "... and more"); truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestClassicAnnouncementPlaceholder(t *testing.T) {
+	formatter := NewFormatter()
+	metadata := template.Metadata{Announcement: "Pledge drive all week", AnnouncementPrepend: true}
+
+	result := formatter.FormatPlaceholder("classic", "Tracklist coming soon", metadata)
+	if want := "Pledge drive all week\n\nTracklist coming soon"; result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+}
+
+// largeSheet returns n tracks a minute apart, every tenth a station ID
+func largeSheet(n int) []cue.Track {
+	tracks := make([]cue.Track, n)
+	for i := range tracks {
+		tracks[i] = cue.Track{
+			Index:     i + 1,
+			StartTime: fmt.Sprintf("%02d:00", i),
+			Artist:    fmt.Sprintf("Artist %d", i+1),
+			Title:     fmt.Sprintf("Song %d", i+1),
+		}
+		if i%10 == 9 {
+			tracks[i].Artist = "Station ID"
+		}
+	}
+	return tracks
+}
+
+// largeSheetFormatter returns a formatter with an hourly template and a filter
+// dropping the station IDs of largeSheet
+func largeSheetFormatter(tb testing.TB) (*Formatter, *filter.Filter) {
+	tb.Helper()
+	cfg := &config.Config{}
+	cfg.Filtering.ExcludedArtists = []string{"Station ID"}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"hourly": {
+			Header:     "{{.ShowTitle}} - {{.TrackCount}} tracks\n",
+			HourHeader: "\n{{.Label}}\n",
+			Track:      "{{.StartTime}} {{.Artist}} - {{.Title}}\n",
+			Footer:     "\nThanks for listening",
+		},
+	}
+	trackFilter, err := filter.NewFilter(cfg)
+	if err != nil {
+		tb.Fatalf("NewFilter failed: %v", err)
+	}
+	return NewFormatterWithConfig(cfg), trackFilter
+}
+
+func TestLargeSheetTruncationCount(t *testing.T) {
+	formatter, trackFilter := largeSheetFormatter(t)
+	tracks := largeSheet(1000)
+	metadata := template.Metadata{ShowTitle: "Overnight"}
+
+	result := formatter.FormatTracklistWithTemplate(tracks, trackFilter, "hourly", metadata)
+	if len(result) > formatter.GetMaxLength() {
+		t.Fatalf("length %d exceeds the limit of %d", len(result), formatter.GetMaxLength())
+	}
+	if !strings.HasPrefix(result, "Overnight - 900 tracks\n") || !strings.HasSuffix(result, "\nThanks for listening") {
+		t.Fatalf("result lost its header or footer:\n%s", result)
+	}
+
+	listed := strings.Count(result, " - Song ")
+	var omitted int
+	marker := strings.LastIndex(result, "\n... and ")
+	if marker < 0 {
+		t.Fatalf("no truncation message in:\n%s", result)
+	}
+	if _, err := fmt.Sscanf(result[marker:], "\n... and %d more tracks", &omitted); err != nil {
+		t.Fatalf("unreadable truncation message: %v", err)
+	}
+	if listed == 0 || listed+omitted != 900 {
+		t.Errorf("listed %d and reported %d more, want them to add up to the 900 tracks kept by the filter", listed, omitted)
+	}
+}
+
+// TestClassicGolden locks classic output, now rendered through the built-in
+// classic template's track line, to the format it has always had
+func TestClassicGolden(t *testing.T) {
+	es, err := messages.ForLocale("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracks := []cue.Track{
+		{StartTime: "00:25", Artist: "Laura Dre", Title: "When I Fall"},
+		{StartTime: " 03:10 ", Artist: "  Airline Food ", Title: ` "Conditional" Love `},
+		{StartTime: "", Artist: "", Title: "Untitled Edit"},
+		{StartTime: "12:00", Artist: "Sad Lovers & Giants", Title: ""},
+		{},
+	}
+	long := make([]cue.Track, 30)
+	for i := range long {
+		long[i] = cue.Track{StartTime: fmt.Sprintf("%02d:00", i), Artist: "Artist", Title: fmt.Sprintf("Song %d", i+1)}
+	}
+
+	tests := []struct {
+		name     string
+		tracks   []cue.Track
+		metadata template.Metadata
+		want     string
+	}{
+		{
+			name:   "fields trimmed, quotes replaced, missing fields filled in",
+			tracks: tracks,
+			want: `00:25 - "When I Fall" by Laura Dre
+03:10 - "'Conditional' Love" by Airline Food
+00:00 - "Untitled Edit" by (Unknown Artist)
+12:00 - "(Unknown Title)" by Sad Lovers & Giants`,
+		},
+		{
+			name:     "localized fallbacks",
+			tracks:   tracks,
+			metadata: template.Metadata{Messages: es},
+			want: `00:25 - "When I Fall" by Laura Dre
+03:10 - "'Conditional' Love" by Airline Food
+00:00 - "Untitled Edit" by (Artista desconocido)
+12:00 - "(Título desconocido)" by Sad Lovers & Giants`,
+		},
+		{
+			name:   "truncated",
+			tracks: long,
+			want: `00:00 - "Song 1" by Artist
+01:00 - "Song 2" by Artist
+02:00 - "Song 3" by Artist
+03:00 - "Song 4" by Artist
+04:00 - "Song 5" by Artist
+05:00 - "Song 6" by Artist
+... and more`,
+		},
+		{
+			name:   "announcement and provenance",
+			tracks: long,
+			metadata: template.Metadata{
+				Announcement:        "Pledge drive",
+				AnnouncementPrepend: true,
+				IncludeProvenance:   true,
+				CueFile:             "MYR40628.cue",
+				GeneratedAt:         "2025-06-28 22:14",
+				ToolVersion:         "1.0.0",
+			},
+			want: `Pledge drive
+
+00:00 - "Song 1" by Artist
+01:00 - "Song 2" by Artist
+02:00 - "Song 3" by Artist
+... and more
+Generated 2025-06-28 22:14 from MYR40628.cue by mixcloud-updater v1.0.0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Both with and without the built-in templates loaded
+			for _, formatter := range []*Formatter{NewFormatter(), NewFormatterWithConfig(&config.Config{})} {
+				formatter.SetMaxLength(200)
+				if got := formatter.FormatTracklistWithTemplate(tt.tracks, nil, "classic", tt.metadata); got != tt.want {
+					t.Errorf("classic output = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuiltinTemplatesWithoutConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Station.Name = "Now Wave Radio"
+	formatter := NewFormatterWithConfig(cfg)
+	tracks := []cue.Track{
+		{StartTime: "00:25", Artist: "Laura Dre", Title: "When I Fall", Genre: "Synthpop"},
+		{StartTime: "08:15", Artist: "Airline Food", Title: "Conditional Love"},
+	}
+	metadata := template.Metadata{ShowTitle: "Mind Your Rhythm", ShowDate: "June 28, 2025"}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{
+			template: "numbered",
+			want: `1. 00:25 - "When I Fall" by Laura Dre
+2. 08:15 - "Conditional Love" by Airline Food
+
+2 tracks
+`,
+		},
+		{
+			template: "detailed",
+			want: `Mind Your Rhythm - June 28, 2025
+
+00:25 - "When I Fall" by Laura Dre (Synthpop)
+08:15 - "Conditional Love" by Airline Food
+
+Now Wave Radio
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if got := formatter.FormatTracklistWithTemplate(tracks, nil, tt.template, metadata); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+
+			showCfg := &config.ShowConfig{TemplateName: tt.template}
+			if got := formatter.FormatTracklistWithShowConfig(tracks, nil, showCfg, metadata); got != tt.want {
+				t.Errorf("show with template = %q: output = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserTemplateOverridesBuiltin(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"classic":  {Track: "{{.Artist}} / {{.Title}}\n"},
+		"numbered": {Track: "#{{.Index}} {{.Title}}\n"},
+	}
+	formatter := NewFormatterWithConfig(cfg)
+	tracks := []cue.Track{{StartTime: "00:25", Artist: "Laura Dre", Title: "When I Fall"}}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"classic", "Laura Dre / When I Fall\n"},
+		{"numbered", "#1 When I Fall\n"},
+	}
+
+	for _, tt := range tests {
+		showCfg := &config.ShowConfig{TemplateName: tt.template}
+		if got := formatter.FormatTracklistWithShowConfig(tracks, nil, showCfg, template.Metadata{}); got != tt.want {
+			t.Errorf("%s output = %q, want the user-defined template's %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkFormatLargeSheet formats a 1000-track sheet of which only the first
+// tracks fit the limit, the case of overnight automation logs. Like the
+// processor, it filters once up front and formats without a filter.
+func BenchmarkFormatLargeSheet(b *testing.B) {
+	formatter, trackFilter := largeSheetFormatter(b)
+	tracks, _ := trackFilter.Apply(largeSheet(1000))
+	metadata := template.Metadata{ShowTitle: "Overnight"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		formatter.FormatTracklistWithTemplate(tracks, nil, "hourly", metadata)
+	}
+}
+
+func TestCollapseSameArtist(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Filtering.ExcludedArtists = []string{"Station ID"}
+	trackFilter, err := filter.NewFilter(cfg)
+	if err != nil {
+		t.Fatalf("NewFilter() error = %v", err)
+	}
+	formatter := NewFormatterWithConfig(cfg)
+
+	// The station ID between two album hour tracks is filtered out first, so
+	// they merge
+	tracks := []cue.Track{
+		{StartTime: "21:00", Artist: "Pure Obsessions", Title: "Intro"},
+		{StartTime: "21:05", Artist: "Laura Dre", Title: "One"},
+		{StartTime: "21:09", Artist: "Station ID", Title: "Now Wave Radio"},
+		{StartTime: "21:10", Artist: "laura  dre", Title: "Two"},
+		{StartTime: "21:14", Artist: "Laura Dre feat. Kid Moxie", Title: "Three"},
+		{StartTime: "21:18", Artist: "Airline Food", Title: "Outro"},
+	}
+	collapse := template.ArtistCollapse{Enabled: true}
+
+	tests := []struct {
+		name     string
+		template string
+		metadata template.Metadata
+		want     string
+	}{
+		{"off", "classic", template.Metadata{},
+			"21:00 - \"Intro\" by Pure Obsessions\n21:05 - \"One\" by Laura Dre\n21:10 - \"Two\" by laura  dre\n" +
+				"21:14 - \"Three\" by Laura Dre feat. Kid Moxie\n21:18 - \"Outro\" by Airline Food"},
+		{"classic", "classic", template.Metadata{Collapse: collapse, TrackStyle: config.TrackStyleArtistFirst},
+			"21:00 - Pure Obsessions - \"Intro\"\n21:05 - Laura Dre - \"One / Two / Three\"\n21:18 - Airline Food - \"Outro\""},
+		{"classic reverse keeps the first start time", "classic",
+			template.Metadata{Collapse: collapse, TrackOrder: config.TrackOrderReverse},
+			"21:18 - \"Outro\" by Airline Food\n21:05 - \"One / Two / Three\" by Laura Dre\n21:00 - \"Intro\" by Pure Obsessions"},
+		{"separator and cap", "classic",
+			template.Metadata{Collapse: template.ArtistCollapse{Enabled: true, Separator: " | ", MaxTitles: 2}},
+			"21:00 - \"Intro\" by Pure Obsessions\n21:05 - \"One | Two | +1 more\" by Laura Dre\n21:18 - \"Outro\" by Airline Food"},
+		{"compact", "compact", template.Metadata{Collapse: collapse},
+			"Pure Obsessions – Intro · Laura Dre – One / Two / Three · Airline Food – Outro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatter.FormatTracklistWithTemplate(tracks, trackFilter, tt.template, tt.metadata)
+			if strings.TrimSpace(result) != tt.want {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+
+	if tracks[3].Title != "Two" {
+		t.Error("collapsing changed the caller's tracks")
+	}
+}
+
+func TestCollapseSameArtistBeforeTruncation(t *testing.T) {
+	// 40 tracks from four albums don't fit in 300 characters one per line, but
+	// the four collapsed entries do
+	var tracks []cue.Track
+	for i := 0; i < 40; i++ {
+		tracks = append(tracks, cue.Track{
+			StartTime: fmt.Sprintf("%02d:00", i),
+			Artist:    fmt.Sprintf("Artist %d", i/10+1),
+			Title:     fmt.Sprintf("Song %d", i+1),
+		})
+	}
+	metadata := template.Metadata{MaxLength: 300, Collapse: template.ArtistCollapse{Enabled: true, MaxTitles: 3}}
+
+	formatter := NewFormatter()
+	result := formatter.FormatTracklistWithTemplate(tracks, nil, "classic", metadata)
+	if strings.Contains(result, "... and more") {
+		t.Errorf("collapsed tracklist was truncated as if uncollapsed:\n%s", result)
+	}
+	want := `30:00 - "Song 31 / Song 32 / Song 33 / +7 more" by Artist 4`
+	if lines := strings.Split(result, "\n"); len(lines) != 4 || lines[3] != want {
+		t.Errorf("result =\n%s\nwant 4 entries ending with %q", result, want)
+	}
+}
@@ -0,0 +1,103 @@
+// Package httpclient builds the transport behind every outbound request - Mixcloud
+// API calls, OAuth token exchanges, announcement fetches and update checks - so
+// they all go through the same proxy.
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AIDEV-NOTE: A client built as &http.Client{} with its own Transport loses
+// http.DefaultTransport's ProxyFromEnvironment, which is how HTTPS_PROXY got
+// ignored on some paths. Build transports with Transport and clients with New
+// instead. An explicit network.proxy_url replaces the environment entirely,
+// NO_PROXY included. Credentials in the URL are sent as Proxy-Authorization,
+// also on the CONNECT request that tunnels TLS to Mixcloud.
+
+// ParseProxyURL parses network.proxy_url: an http, https or socks5 URL with a
+// host, optionally with user:pass credentials
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(proxyURL))
+	if err != nil {
+		// url.Error repeats the URL, password included
+		return nil, fmt.Errorf("proxy URL is not a valid URL")
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("proxy URL %q must start with http://, https:// or socks5://", Redact(proxyURL))
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", Redact(proxyURL))
+	}
+	return parsed, nil
+}
+
+// ProxyFunc returns the proxy selector for a transport: every request goes
+// through proxyURL when it is set, otherwise HTTPS_PROXY, HTTP_PROXY and
+// NO_PROXY from the environment apply
+func ProxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if strings.TrimSpace(proxyURL) == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	parsed, err := ParseProxyURL(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return http.ProxyURL(parsed), nil
+}
+
+// Transport returns a copy of http.DefaultTransport that uses ProxyFunc(proxyURL)
+func Transport(proxyURL string) (*http.Transport, error) {
+	proxy, err := ProxyFunc(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	return transport, nil
+}
+
+// New returns a client that gives up after timeout, with Transport(proxyURL)
+func New(proxyURL string, timeout time.Duration) (*http.Client, error) {
+	transport, err := Transport(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// Describe names the proxy requests to target go through, for logs and -doctor:
+// the configured or environment proxy with its password hidden, or "" when
+// requests connect directly
+func Describe(proxyURL, target string) (string, error) {
+	proxy, err := ProxyFunc(proxyURL)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid target URL: %w", err)
+	}
+	selected, err := proxy(req)
+	if err != nil {
+		return "", fmt.Errorf("invalid proxy environment variable: %w", err)
+	}
+	if selected == nil {
+		return "", nil
+	}
+	return selected.Redacted(), nil
+}
+
+// Redact hides the password in a proxy URL, e.g. for error messages
+func Redact(proxyURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(proxyURL))
+	if err != nil {
+		return "(invalid URL)"
+	}
+	return parsed.Redacted()
+}
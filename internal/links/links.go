@@ -0,0 +1,135 @@
+// Package links loads artist lookup tables (e.g. artist → Bandcamp page) used by
+// the artistLink template function.
+package links
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// AIDEV-NOTE: Hosts maintain the table by hand (usually a spreadsheet export), so
+// keys are normalized: case, a leading "The " and "feat." suffixes don't matter.
+
+// Table maps normalized artist names to URLs. A nil Table has no links.
+type Table struct {
+	urls map[string]string
+}
+
+// featuringRegex matches a featuring suffix, with or without parentheses
+var featuringRegex = regexp.MustCompile(`(?i)\s+[(\[]?(feat\.?|ft\.?|featuring)\s.*$`)
+
+// NormalizeArtist reduces an artist name to its lookup key: lowercased, without a
+// leading "The " or a featuring suffix, and with whitespace collapsed
+func NormalizeArtist(artist string) string {
+	name := strings.ToLower(strings.Join(strings.Fields(artist), " "))
+	name = featuringRegex.ReplaceAllString(name, "")
+	if trimmed := strings.TrimPrefix(name, "the "); trimmed != "" {
+		name = trimmed
+	}
+	return strings.TrimSpace(name)
+}
+
+// Load reads a links file. ".csv" files hold artist,url rows (an "artist,url"
+// header row is skipped); ".toml" files hold "Artist" = "URL" pairs. Later
+// entries win when two names normalize to the same artist.
+func Load(path string) (*Table, error) {
+	var entries [][2]string
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = readCSV(path)
+	case ".toml":
+		entries, err = readTOML(path)
+	default:
+		return nil, fmt.Errorf("links file %s: unsupported format (use .csv or .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("links file %s: %w", path, err)
+	}
+
+	table := &Table{urls: make(map[string]string, len(entries))}
+	for _, entry := range entries {
+		artist, url := NormalizeArtist(entry[0]), strings.TrimSpace(entry[1])
+		if artist == "" || url == "" {
+			continue
+		}
+		table.urls[artist] = url
+	}
+	return table, nil
+}
+
+func readCSV(path string) ([][2]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Spreadsheet exports often carry extra columns
+	reader.TrimLeadingSpace = true
+
+	var entries [][2]string
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("row %d: want artist,url", row)
+		}
+		if row == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "artist") {
+			continue
+		}
+		entries = append(entries, [2]string{record[0], record[1]})
+	}
+}
+
+func readTOML(path string) ([][2]string, error) {
+	var raw map[string]string
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		return nil, err
+	}
+	artists := make([]string, 0, len(raw))
+	for artist := range raw {
+		artists = append(artists, artist)
+	}
+	sort.Strings(artists) // Deterministic when two keys normalize alike
+
+	entries := make([][2]string, 0, len(raw))
+	for _, artist := range artists {
+		entries = append(entries, [2]string{artist, raw[artist]})
+	}
+	return entries, nil
+}
+
+// Lookup returns the URL for artist, or "" if the table has none
+func (t *Table) Lookup(artist string) string {
+	if t == nil {
+		return ""
+	}
+	return t.urls[NormalizeArtist(artist)]
+}
+
+// Len returns the number of artists in the table
+func (t *Table) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.urls)
+}
@@ -0,0 +1,96 @@
+package links
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeArtist(t *testing.T) {
+	tests := []struct {
+		artist   string
+		expected string
+	}{
+		{"Laura Dre", "laura dre"},
+		{"  LAURA   dre ", "laura dre"},
+		{"The Chameleons", "chameleons"},
+		{"Chameleons", "chameleons"},
+		{"Laura Dre feat. Airline Food", "laura dre"},
+		{"Laura Dre ft. Airline Food", "laura dre"},
+		{"Laura Dre (Featuring Airline Food)", "laura dre"},
+		{"Laura Dre [feat Airline Food]", "laura dre"},
+		{"The The", "the"},
+		{"Feather", "feather"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.artist, func(t *testing.T) {
+			if got := NormalizeArtist(tt.artist); got != tt.expected {
+				t.Errorf("NormalizeArtist(%q) = %q, want %q", tt.artist, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	for _, fixture := range []string{"links.csv", "links.toml"} {
+		t.Run(fixture, func(t *testing.T) {
+			table, err := Load(filepath.Join("testdata", fixture))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if table.Len() != 3 {
+				t.Errorf("Len() = %d, want 3", table.Len())
+			}
+
+			lookups := map[string]string{
+				"laura dre":                    "https://lauradre.bandcamp.com",
+				"Chameleons":                   "https://thechameleons.bandcamp.com",
+				"Airline Food feat. Laura Dre": "https://airlinefood.bandcamp.com",
+				"Kid Moxie":                    "",
+				"Unknown Artist":               "",
+			}
+			for artist, want := range lookups {
+				if got := table.Lookup(artist); got != want {
+					t.Errorf("Lookup(%q) = %q, want %q", artist, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing file", filepath.Join(dir, "missing.csv")},
+		{"unsupported extension", write("links.json", "{}")},
+		{"row without url", write("short.csv", "Laura Dre\n")},
+		{"invalid toml", write("bad.toml", "Laura Dre = ")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.path); err == nil {
+				t.Error("Load() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestNilTable(t *testing.T) {
+	var table *Table
+	if table.Lookup("Laura Dre") != "" || table.Len() != 0 {
+		t.Error("nil Table should have no links")
+	}
+}
@@ -0,0 +1,330 @@
+// Package lint reports configuration cruft: unused templates, long-disabled
+// shows, CUE patterns that match nothing and similar. Findings are advisory and
+// never stop the updater from running.
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+// Severity ranks a finding
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning" // Likely a mistake or dead config
+	SeverityInfo    Severity = "info"    // Worth a look, may be intentional
+)
+
+// Severities lists severities in report order
+var Severities = []Severity{SeverityWarning, SeverityInfo}
+
+// DisabledThreshold is how long a disabled show can go unpublished before it
+// is reported as a removal candidate
+const DisabledThreshold = 90 * 24 * time.Hour
+
+// Finding is a single lint result tied to a config section
+type Finding struct {
+	Severity Severity
+	Section  string // e.g. "shows.sounds-like" or "templates.config.detailed"
+	Message  string
+}
+
+// Check lints cfg. runState supplies last-publish times for disabled shows and
+// may be nil. Findings are sorted by severity, then section.
+func Check(cfg *config.Config, runState *state.State, now time.Time) []Finding {
+	var findings []Finding
+	findings = append(findings, checkUnusedTemplates(cfg)...)
+	findings = append(findings, checkDisabledShows(cfg, runState, now)...)
+	findings = append(findings, checkCueSources(cfg)...)
+	findings = append(findings, checkPatternOverlaps(cfg)...)
+	findings = append(findings, checkAliasShadowing(cfg)...)
+	findings = append(findings, checkTemplateFields(cfg)...)
+	findings = append(findings, checkOutputModes(cfg)...)
+
+	rank := make(map[Severity]int, len(Severities))
+	for i, severity := range Severities {
+		rank[severity] = i
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return rank[findings[i].Severity] < rank[findings[j].Severity]
+		}
+		return findings[i].Section < findings[j].Section
+	})
+	return findings
+}
+
+func showSection(showKey string) string {
+	return "shows." + showKey
+}
+
+func templateSection(name string) string {
+	return "templates.config." + name
+}
+
+// sortedShowKeys returns show keys in a stable order for reporting
+func sortedShowKeys(cfg *config.Config) []string {
+	keys := make([]string, 0, len(cfg.Shows))
+	for key := range cfg.Shows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkUnusedTemplates flags templates that are neither the default nor referenced by a show
+func checkUnusedTemplates(cfg *config.Config) []Finding {
+	used := map[string]bool{cfg.Templates.Default: true}
+	for _, showCfg := range cfg.Shows {
+		used[showCfg.TemplateName] = true
+	}
+
+	var findings []Finding
+	for name := range cfg.Templates.Config {
+		if !used[name] {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Section:  templateSection(name),
+				Message:  "template is not the default and no show references it",
+			})
+		}
+	}
+	return findings
+}
+
+// checkDisabledShows lists disabled shows. With run history, shows that haven't
+// published within DisabledThreshold are escalated to warnings.
+func checkDisabledShows(cfg *config.Config, runState *state.State, now time.Time) []Finding {
+	haveHistory := runState != nil && len(runState.ShowKeys()) > 0
+
+	var findings []Finding
+	for _, showKey := range sortedShowKeys(cfg) {
+		if cfg.Shows[showKey].Enabled {
+			continue
+		}
+
+		finding := Finding{
+			Severity: SeverityInfo,
+			Section:  showSection(showKey),
+			Message:  "show is disabled",
+		}
+		if haveHistory {
+			showState, ok := runState.Show(showKey)
+			switch {
+			case !ok || showState.LastPublished.IsZero():
+				finding.Severity = SeverityWarning
+				finding.Message = "show is disabled and has never been published - remove it?"
+			case now.Sub(showState.LastPublished) > DisabledThreshold:
+				finding.Severity = SeverityWarning
+				finding.Message = fmt.Sprintf("show is disabled and was last published %s (%s) - remove it?",
+					state.FormatRelative(showState.LastPublished, now), showState.LastPublished.Format("2006-01-02"))
+			default:
+				finding.Message = fmt.Sprintf("show is disabled, last published %s",
+					state.FormatRelative(showState.LastPublished, now))
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// checkCueSources flags cue_file_pattern/cue_file_mapping entries that currently resolve to nothing
+func checkCueSources(cfg *config.Config) []Finding {
+	cueResolver := shows.NewCueResolver(cfg.CueDirectory())
+
+	var findings []Finding
+	for _, showKey := range sortedShowKeys(cfg) {
+		showCfg := cfg.Shows[showKey]
+		if showCfg.CueFilePattern == "" && showCfg.CueFileMapping == "" {
+			continue // Reported by config validation
+		}
+		if _, err := cueResolver.ResolveCueFile(&showCfg); err != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Section:  showSection(showKey),
+				Message:  fmt.Sprintf("CUE source matches nothing on disk: %v", err),
+			})
+		}
+	}
+	return findings
+}
+
+// checkPatternOverlaps flags enabled shows whose CUE sources pick up the same
+// files on disk, which publishes one tracklist to several shows. Each set of
+// overlapping shows is reported once, under the first show key.
+func checkPatternOverlaps(cfg *config.Config) []Finding {
+	resolver, err := shows.NewResolver(cfg)
+	if err != nil {
+		return nil // Alias conflicts are reported by config validation
+	}
+	cueDir := cfg.CueDirectory()
+	cueResolver := shows.NewCueResolver(cueDir)
+
+	// Every file some enabled show would consider. With a relative CUE directory
+	// globs return relative paths that already include it, and MatchShowsForFile
+	// would join it again.
+	candidates := make(map[string]bool)
+	addCandidate := func(path string) {
+		if rel, err := filepath.Rel(cueDir, path); err == nil && !filepath.IsAbs(path) {
+			path = rel
+		}
+		candidates[path] = true
+	}
+	for _, showKey := range resolver.ListEnabledShows(false) {
+		showCfg := cfg.Shows[showKey]
+		if showCfg.CueFileMapping != "" {
+			if path, err := cueResolver.ResolveCueFile(&showCfg); err == nil {
+				addCandidate(path)
+			}
+			continue
+		}
+		if showCfg.CueFilePattern != "" {
+			files, _ := cueResolver.FindCueFilesByPattern(shows.ExpandCuePattern(showCfg.CueFilePattern, time.Time{}))
+			for _, file := range files {
+				addCandidate(file)
+			}
+		}
+	}
+
+	type overlap struct {
+		showKeys []string
+		files    []string
+	}
+	overlaps := make(map[string]*overlap)
+	for file := range candidates {
+		matched := resolver.MatchShowsForFile(file)
+		if len(matched) < 2 {
+			continue
+		}
+		sort.Strings(matched)
+		id := strings.Join(matched, "\x00")
+		if overlaps[id] == nil {
+			overlaps[id] = &overlap{showKeys: matched}
+		}
+		overlaps[id].files = append(overlaps[id].files, file)
+	}
+
+	var findings []Finding
+	for _, o := range overlaps {
+		sort.Strings(o.files)
+		others := make([]string, 0, len(o.showKeys)-1)
+		for _, showKey := range o.showKeys[1:] {
+			others = append(others, fmt.Sprintf("%q", showKey))
+		}
+		label := "show"
+		if len(others) > 1 {
+			label = "shows"
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Section:  showSection(o.showKeys[0]),
+			Message: fmt.Sprintf("CUE source overlaps %s %s: %d file(s) such as %s are picked up by each, publishing the same tracklist",
+				label, strings.Join(others, ", "), len(o.files), filepath.Base(o.files[0])),
+		})
+	}
+	return findings
+}
+
+// checkAliasShadowing flags aliases that equal another show's key. The resolver
+// matches keys and aliases case-insensitively, so the alias hides that show.
+func checkAliasShadowing(cfg *config.Config) []Finding {
+	keys := make(map[string]string, len(cfg.Shows))
+	for showKey := range cfg.Shows {
+		keys[strings.ToLower(showKey)] = showKey
+	}
+
+	var findings []Finding
+	for _, showKey := range sortedShowKeys(cfg) {
+		for _, alias := range cfg.Shows[showKey].Aliases {
+			other, ok := keys[strings.ToLower(alias)]
+			if !ok || other == showKey {
+				continue
+			}
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Section:  showSection(showKey),
+				Message:  fmt.Sprintf("alias %q shadows the key of show %q", alias, other),
+			})
+		}
+	}
+	return findings
+}
+
+// checkTemplateFields flags template bodies referencing fields the template data doesn't have
+func checkTemplateFields(cfg *config.Config) []Finding {
+	var findings []Finding
+	check := func(section string, templateConfig config.TemplateConfig) {
+		unknown, err := template.UnknownFields(templateConfig)
+		if err != nil {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Section:  section,
+				Message:  fmt.Sprintf("template does not parse: %v", err),
+			})
+			return
+		}
+		for _, field := range unknown {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Section:  section,
+				Message:  fmt.Sprintf("unknown field %s", field),
+			})
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Templates.Config))
+	for name := range cfg.Templates.Config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		check(templateSection(name), cfg.Templates.Config[name])
+	}
+
+	for _, showKey := range sortedShowKeys(cfg) {
+		customTemplate := cfg.Shows[showKey].CustomTemplate
+		// Inline templates with their own {{define}} blocks aren't split into parts
+		if customTemplate == "" || strings.Contains(customTemplate, "{{define") {
+			continue
+		}
+		check(showSection(showKey), config.TemplateConfig{Track: customTemplate})
+	}
+	return findings
+}
+
+// checkOutputModes flags enabled shows publishing with an html or markdown
+// output_mode template: Mixcloud displays descriptions as plain text, so the
+// escapes would show up literally
+func checkOutputModes(cfg *config.Config) []Finding {
+	var findings []Finding
+	for _, showKey := range sortedShowKeys(cfg) {
+		showCfg := cfg.Shows[showKey]
+		if !showCfg.Enabled || showCfg.CustomTemplate != "" {
+			continue
+		}
+		name := showCfg.TemplateName
+		if name == "" {
+			name = cfg.Templates.Default
+		}
+		mode := cfg.Templates.Config[name].OutputMode
+		if mode == "" || mode == config.OutputModePlain {
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityWarning,
+			Section:  showSection(showKey),
+			Message: fmt.Sprintf("template %q has output_mode = %q, but Mixcloud shows descriptions as plain text (escapes like &amp; appear as typed)",
+				name, mode),
+		})
+	}
+	return findings
+}
@@ -0,0 +1,190 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+func lintConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "MYR_Show_20250101.cue"), []byte("TITLE \"x\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Processing.CueFileDirectory = dir
+	cfg.Templates.Default = "detailed"
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"detailed": {Track: "{{.Index}}. {{.Artist}} - {{.Title}}\n"},
+		"used":     {Track: "{{.Artist}}\n"},
+	}
+	cfg.Shows = map[string]config.ShowConfig{
+		"main": {CueFilePattern: "MYR_Show_*.cue", TemplateName: "used", Aliases: []string{"m"}, Enabled: true},
+	}
+	return cfg
+}
+
+// findingsFor returns the messages reported for a section
+func findingsFor(findings []Finding, section string) []Finding {
+	var matched []Finding
+	for _, f := range findings {
+		if f.Section == section {
+			matched = append(matched, f)
+		}
+	}
+	return matched
+}
+
+func TestCheckCleanConfig(t *testing.T) {
+	if findings := Check(lintConfig(t), nil, time.Now()); len(findings) != 0 {
+		t.Errorf("Check() on clean config = %+v, want none", findings)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*config.Config)
+		section  string
+		severity Severity
+		contains string
+	}{
+		{
+			name: "unused template",
+			modify: func(c *config.Config) {
+				c.Templates.Config["old"] = config.TemplateConfig{Track: "{{.Title}}"}
+			},
+			section:  "templates.config.old",
+			severity: SeverityWarning,
+			contains: "no show references it",
+		},
+		{
+			name: "pattern matches nothing",
+			modify: func(c *config.Config) {
+				c.Shows["gone"] = config.ShowConfig{CueFilePattern: "GONE_*.cue", Enabled: true}
+			},
+			section:  "shows.gone",
+			severity: SeverityWarning,
+			contains: "matches nothing",
+		},
+		{
+			name: "alias shadows another show's key",
+			modify: func(c *config.Config) {
+				c.Shows["other"] = config.ShowConfig{CueFilePattern: "MYR_Show_*.cue", Aliases: []string{"MAIN"}, Enabled: true}
+			},
+			section:  "shows.other",
+			severity: SeverityWarning,
+			contains: `alias "MAIN" shadows the key of show "main"`,
+		},
+		{
+			name: "overlapping CUE patterns",
+			modify: func(c *config.Config) {
+				c.Shows["all-myriad"] = config.ShowConfig{CueFilePattern: "MYR_*.cue", TemplateName: "used", Enabled: true}
+			},
+			section:  "shows.all-myriad",
+			severity: SeverityWarning,
+			contains: `overlaps show "main": 1 file(s) such as MYR_Show_20250101.cue`,
+		},
+		{
+			name: "unknown template field",
+			modify: func(c *config.Config) {
+				c.Templates.Config["used"] = config.TemplateConfig{Track: "{{.Artsit}}\n"}
+			},
+			section:  "templates.config.used",
+			severity: SeverityWarning,
+			contains: "unknown field track: .Artsit",
+		},
+		{
+			name: "unknown field in custom template",
+			modify: func(c *config.Config) {
+				show := c.Shows["main"]
+				show.TemplateName = ""
+				show.CustomTemplate = "{{.Released}}"
+				c.Shows["main"] = show
+				delete(c.Templates.Config, "used")
+			},
+			section:  "shows.main",
+			severity: SeverityWarning,
+			contains: "unknown field track: .Released",
+		},
+		{
+			name: "html template published to Mixcloud",
+			modify: func(c *config.Config) {
+				c.Templates.Config["used"] = config.TemplateConfig{Track: "<li>{{.Artist}}</li>\n", OutputMode: config.OutputModeHTML}
+			},
+			section:  "shows.main",
+			severity: SeverityWarning,
+			contains: `template "used" has output_mode = "html"`,
+		},
+		{
+			name: "disabled show without history",
+			modify: func(c *config.Config) {
+				c.Shows["paused"] = config.ShowConfig{CueFilePattern: "MYR_Show_*.cue"}
+			},
+			section:  "shows.paused",
+			severity: SeverityInfo,
+			contains: "show is disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := lintConfig(t)
+			tt.modify(cfg)
+
+			findings := findingsFor(Check(cfg, nil, time.Now()), tt.section)
+			if len(findings) != 1 {
+				t.Fatalf("findings for %s = %+v, want exactly one", tt.section, findings)
+			}
+			if findings[0].Severity != tt.severity || !strings.Contains(findings[0].Message, tt.contains) {
+				t.Errorf("finding = %+v, want %s containing %q", findings[0], tt.severity, tt.contains)
+			}
+		})
+	}
+}
+
+func TestCheckDisabledShowsWithHistory(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	runState, err := state.Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runState.RecordPublish("recent", state.ShowState{LastPublished: now.Add(-10 * 24 * time.Hour)})
+	runState.RecordPublish("ancient", state.ShowState{LastPublished: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)})
+
+	cfg := lintConfig(t)
+	for _, key := range []string{"recent", "ancient", "never"} {
+		cfg.Shows[key] = config.ShowConfig{CueFilePattern: "MYR_Show_*.cue"}
+	}
+
+	findings := Check(cfg, runState, now)
+	want := map[string]Severity{
+		"shows.recent":  SeverityInfo,
+		"shows.ancient": SeverityWarning,
+		"shows.never":   SeverityWarning,
+	}
+	for section, severity := range want {
+		got := findingsFor(findings, section)
+		if len(got) != 1 || got[0].Severity != severity {
+			t.Errorf("findings for %s = %+v, want one %s", section, got, severity)
+		}
+	}
+	if got := findingsFor(findings, "shows.ancient"); len(got) == 1 && !strings.Contains(got[0].Message, "2022-03-01") {
+		t.Errorf("ancient show message %q should include the last publish date", got[0].Message)
+	}
+
+	// Warnings sort ahead of info
+	for i := 1; i < len(findings); i++ {
+		if findings[i-1].Severity == SeverityInfo && findings[i].Severity == SeverityWarning {
+			t.Errorf("findings not grouped by severity: %+v", findings)
+			break
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// AIDEV-NOTE: A full log partition must not become a publishing outage. The
+// log file sits behind a fileGuard that swallows write errors, so slog and the
+// console writer in front of it never see them, and after a few failures in a
+// row turns file output off for the rest of the run with a single notice on
+// stderr. Rotations that can't open their new file are counted the same way,
+// then given up so they aren't retried on every line; logging continues in
+// the current file.
+
+// maxFileWriteErrors is how many consecutive failed writes disable file
+// output, and failed rotations disable rotation
+const maxFileWriteErrors = 3
+
+// fileGuard writes to the log file until it keeps failing
+type fileGuard struct {
+	mu       sync.Mutex
+	w        io.Writer
+	failures int  // Consecutive failed writes
+	disabled bool // Set once failures reaches maxFileWriteErrors
+	notice   io.Writer
+
+	rotateFailures int  // Consecutive failed rotations
+	noRotate       bool // Set once rotateFailures reaches maxFileWriteErrors
+}
+
+// newFileGuard guards writes to w, reporting degradation on stderr
+func newFileGuard(w io.Writer) *fileGuard {
+	return &fileGuard{w: w, notice: os.Stderr}
+}
+
+// Write writes p to the file. It never fails: errors are counted instead.
+func (g *fileGuard) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.disabled {
+		return len(p), nil
+	}
+	if _, err := g.w.Write(p); err != nil {
+		g.failLocked(err)
+	} else {
+		g.failures = 0
+	}
+	return len(p), nil
+}
+
+// setWriter switches to a newly opened file after rotation
+func (g *fileGuard) setWriter(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.w = w
+	g.failures = 0
+	g.rotateFailures = 0
+}
+
+// rotationFailed counts a rotation that couldn't open its new file
+func (g *fileGuard) rotationFailed(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rotateFailures++
+	if g.rotateFailures < maxFileWriteErrors || g.noRotate {
+		return
+	}
+	g.noRotate = true
+	fmt.Fprintf(g.notice, "Log rotation disabled for the rest of this run after %d failed attempts, still logging to the current file: %v\n", g.rotateFailures, err)
+}
+
+// failLocked counts a failed write, disabling file output at the limit
+func (g *fileGuard) failLocked(err error) {
+	g.failures++
+	if g.failures < maxFileWriteErrors {
+		return
+	}
+	g.disabled = true
+	fmt.Fprintf(g.notice, "Log file output disabled for the rest of this run after %d failed writes: %v\n", g.failures, err)
+}
+
+// canRotate reports whether rotation should still be attempted
+func (g *fileGuard) canRotate() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return !g.disabled && !g.noRotate
+}
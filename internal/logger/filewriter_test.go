@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// failingWriter accepts ok writes, then fails every write with ENOSPC
+type failingWriter struct {
+	ok     int
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > w.ok {
+		return 0, &os.PathError{Op: "write", Path: "updater.log", Err: syscall.ENOSPC}
+	}
+	return len(p), nil
+}
+
+func TestFileWriteFailuresDisableFileOutput(t *testing.T) {
+	// Console output is captured to check it outlives the log file
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	logger, err := NewLogger(Config{Enabled: true, Directory: t.TempDir(), FilenamePattern: "updater.log", Level: "info", ConsoleOutput: true})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+
+	// The disk fills up after two more lines
+	disk := &failingWriter{ok: 2}
+	var notice bytes.Buffer
+	logger.fileGuard.w, logger.fileGuard.notice = disk, &notice
+
+	for i := 1; i <= 10; i++ {
+		logger.Info("Processing show", "n", i)
+		if _, err := logger.Write([]byte(fmt.Sprintf("line %d\n", i))); err != nil {
+			t.Errorf("Write() error = %v, want none", err)
+		}
+	}
+	os.Stdout = stdout
+	w.Close()
+	console, _ := io.ReadAll(r)
+
+	if got := strings.Count(notice.String(), "Log file output disabled"); got != 1 {
+		t.Errorf("degradation notice printed %d times, want once:\n%s", got, notice.String())
+	}
+	if !strings.Contains(notice.String(), "no space left on device") {
+		t.Errorf("notice = %q, want the write error", notice.String())
+	}
+	if want := 2 + maxFileWriteErrors; disk.writes != want {
+		t.Errorf("file writes = %d, want %d (none once disabled)", disk.writes, want)
+	}
+	if !strings.Contains(string(console), "n=10") || !strings.Contains(string(console), "line 10") {
+		t.Errorf("console output stopped with the log file:\n%s", console)
+	}
+}
+
+func TestFileWriteFailureRecovers(t *testing.T) {
+	guard := newFileGuard(&failingWriter{ok: 0})
+	var notice bytes.Buffer
+	guard.notice = &notice
+
+	// Failures short of the limit, then a good write, then short again
+	for i := 0; i < maxFileWriteErrors-1; i++ {
+		guard.Write([]byte("lost\n"))
+	}
+	guard.setWriter(io.Discard)
+	guard.Write([]byte("kept\n"))
+	guard.setWriter(&failingWriter{ok: 0})
+	for i := 0; i < maxFileWriteErrors-1; i++ {
+		guard.Write([]byte("lost\n"))
+	}
+
+	if guard.disabled || notice.Len() > 0 {
+		t.Errorf("guard disabled after non-consecutive failures, notice %q", notice.String())
+	}
+}
+
+func TestFailedRotationStopsRetrying(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	logger, err := NewLogger(Config{Enabled: true, Directory: dir, FilenamePattern: "updater.log", Level: "info", MaxSizeMB: 1})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer logger.Close()
+	var notice bytes.Buffer
+	logger.fileGuard.notice = &notice
+
+	// Over the size limit with nowhere to open the next file
+	logger.fileSize = 2 * 1024 * 1024
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := logger.Write([]byte("line\n")); err != nil {
+			t.Errorf("Write() error = %v, want none", err)
+		}
+	}
+
+	if got := strings.Count(notice.String(), "Log rotation disabled"); got != 1 {
+		t.Errorf("rotation notice printed %d times, want once:\n%s", got, notice.String())
+	}
+	if !strings.Contains(notice.String(), "no such file or directory") {
+		t.Errorf("notice = %q, want the rotation error", notice.String())
+	}
+	if err := logger.checkRotation(); err != nil {
+		t.Errorf("checkRotation() after disabling = %v, want no further attempts", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
 	"github.com/nowwaveradio/mixcloud-updater/internal/dateutil"
 )
 
@@ -26,6 +27,23 @@ type Config struct {
 	MaxFiles        int    `toml:"max_files"`
 	MaxSizeMB       int    `toml:"max_size_mb"`
 	ConsoleOutput   bool   `toml:"console_output"`
+	// ConsoleVerbosity is "normal", "quiet" or "silent"; quiet and silent keep
+	// log lines off the console even with ConsoleOutput, see console.Verbosity
+	ConsoleVerbosity string `toml:"console_verbosity"`
+	// Redact maps a rule name to a regular expression; matches are logged as
+	// [REDACTED:name]. Configured OAuth secrets are always redacted.
+	Redact map[string]string `toml:"redact"`
+}
+
+// logsToConsole reports whether log lines are also written to stdout
+func (c Config) logsToConsole() bool {
+	return c.ConsoleOutput && !c.consoleQuiet()
+}
+
+// consoleQuiet reports whether console_verbosity keeps the console free of log lines
+func (c Config) consoleQuiet() bool {
+	v, err := console.ParseVerbosity(c.ConsoleVerbosity)
+	return err == nil && v != console.Normal
 }
 
 // Logger wraps slog.Logger with file management capabilities
@@ -33,23 +51,38 @@ type Logger struct {
 	*slog.Logger
 	config      Config
 	file        *os.File
+	fileGuard   *fileGuard // Stops writing to file after repeated failures
 	fileName    string
 	fileSize    int64
 	mu          sync.Mutex
 	multiWriter io.Writer
+	redactRules []redactRule
 }
 
 var (
 	// Global logger instance
 	globalLogger *Logger
 	once         sync.Once
+
+	// Records logged before Initialize, replayed into the real handler
+	preInit = newPreInitBuffer(preInitBufferSize)
 )
 
-// Initialize creates and configures the global logger instance
+// Initialize creates and configures the global logger instance and flushes
+// anything logged before it into the new handler
 func Initialize(config Config) error {
 	var initErr error
 	once.Do(func() {
-		globalLogger, initErr = NewLogger(config)
+		var logger *Logger
+		logger, initErr = newLogger(config, preInit)
+		if initErr != nil {
+			// Still flush early records somewhere visible
+			logger = &Logger{Logger: slog.New(newRedactHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+				Level: slog.LevelInfo,
+			}), nil))}
+			preInit.bind(func() slog.Handler { return logger.Handler() })
+		}
+		globalLogger = logger
 	})
 	return initErr
 }
@@ -57,24 +90,34 @@ func Initialize(config Config) error {
 // Get returns the global logger instance
 func Get() *Logger {
 	if globalLogger == nil {
-		// Fallback to console-only logger if not initialized
-		consoleLogger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
-		}))
-		globalLogger = &Logger{Logger: consoleLogger}
+		// AIDEV-NOTE: Before Initialize, records are buffered in memory rather than
+		// printed so they end up in the log file once it is opened
+		globalLogger = &Logger{Logger: slog.New(newRedactHandler(&bufferHandler{buf: preInit}, nil))}
 	}
 	return globalLogger
 }
 
 // NewLogger creates a new logger with the given configuration
 func NewLogger(config Config) (*Logger, error) {
+	return newLogger(config, nil)
+}
+
+// newLogger creates a logger and, when early is set, replays the buffered
+// pre-initialization records before the "Logger initialized" entry
+func newLogger(config Config, early *preInitBuffer) (*Logger, error) {
 	// AIDEV-NOTE: Validate filename pattern for cross-platform compatibility
 	if err := ValidateFilenamePattern(config.FilenamePattern); err != nil {
 		return nil, fmt.Errorf("invalid filename pattern: %w", err)
 	}
 
+	redactRules, err := compileRedactRules(config.Redact)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction rule: %w", err)
+	}
+
 	logger := &Logger{
-		config: config,
+		config:      config,
+		redactRules: redactRules,
 	}
 
 	// Parse log level
@@ -83,7 +126,7 @@ func NewLogger(config Config) (*Logger, error) {
 	// Set up writers based on configuration
 	writers := []io.Writer{}
 
-	if config.ConsoleOutput {
+	if config.logsToConsole() {
 		writers = append(writers, os.Stdout)
 	}
 
@@ -100,11 +143,14 @@ func NewLogger(config Config) (*Logger, error) {
 			return nil, fmt.Errorf("failed to open log file: %w", err)
 		}
 		logger.file = logFile
-		writers = append(writers, logFile)
+		logger.fileGuard = newFileGuard(logFile)
+		writers = append(writers, logger.fileGuard)
 	}
 
 	// Create multi-writer
-	if len(writers) == 0 {
+	if len(writers) == 0 && config.consoleQuiet() {
+		writers = append(writers, io.Discard)
+	} else if len(writers) == 0 {
 		// Fallback to stdout if no writers configured
 		writers = append(writers, os.Stdout)
 	}
@@ -127,13 +173,18 @@ func NewLogger(config Config) (*Logger, error) {
 		},
 	})
 
-	logger.Logger = slog.New(handler)
+	logger.Logger = slog.New(newRedactHandler(handler, logger.redactRules))
+
+	if early != nil {
+		// Resolve the handler on each call so later rotation is picked up
+		early.bind(func() slog.Handler { return logger.Handler() })
+	}
 	
 	// Log initialization
 	logger.Info("Logger initialized",
 		slog.String("log_file", logger.fileName),
 		slog.String("level", config.Level),
-		slog.Bool("console", config.ConsoleOutput))
+		slog.Bool("console", config.logsToConsole()))
 
 	return logger, nil
 }
@@ -165,6 +216,11 @@ func (l *Logger) openLogFile() (*os.File, error) {
 	return file, nil
 }
 
+// Directory returns the directory log files are written to for config
+func Directory(config Config) string {
+	return expandLogDirectory(config.Directory)
+}
+
 // expandLogDirectory expands the log directory path with platform-specific defaults
 func expandLogDirectory(dir string) string {
 	if dir == "" {
@@ -231,7 +287,7 @@ func (l *Logger) checkRotation() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	
-	if l.file == nil || !l.config.Enabled {
+	if l.file == nil || !l.config.Enabled || !l.fileGuard.canRotate() {
 		return nil
 	}
 	
@@ -250,27 +306,28 @@ func (l *Logger) checkRotation() error {
 	return nil
 }
 
-// rotate performs log file rotation
+// rotate performs log file rotation. When the new file can't be opened the
+// current one is kept and the failure counts towards giving up rotation.
 func (l *Logger) rotate() error {
-	// Close current file
-	if l.file != nil {
-		l.file.Close()
-	}
-	
-	// Open new file
+	// Open new file before giving up the current one
 	file, err := l.openLogFile()
 	if err != nil {
+		l.fileGuard.rotationFailed(err)
 		return err
 	}
 	
+	if l.file != nil {
+		l.file.Close()
+	}
 	l.file = file
+	l.fileGuard.setWriter(file)
 	
 	// Update multi-writer
 	writers := []io.Writer{}
-	if l.config.ConsoleOutput {
+	if l.config.logsToConsole() {
 		writers = append(writers, os.Stdout)
 	}
-	writers = append(writers, l.file)
+	writers = append(writers, l.fileGuard)
 	l.multiWriter = io.MultiWriter(writers...)
 	
 	// Recreate handler with new writer - use same options as original
@@ -289,7 +346,7 @@ func (l *Logger) rotate() error {
 			return a
 		},
 	})
-	l.Logger = slog.New(handler)
+	l.Logger = slog.New(newRedactHandler(handler, l.redactRules))
 	
 	// Clean old files if needed
 	if l.config.MaxFiles > 0 {
@@ -349,11 +406,9 @@ func (l *Logger) cleanOldFiles() {
 
 // Write implements io.Writer interface with rotation check
 func (l *Logger) Write(p []byte) (n int, err error) {
-	// Check rotation before writing
-	if err := l.checkRotation(); err != nil {
-		// Log rotation error but continue writing
-		fmt.Fprintf(os.Stderr, "Log rotation error: %v\n", err)
-	}
+	// Check rotation before writing; a failed rotation keeps the current file
+	// and is reported by the file guard if it persists
+	l.checkRotation()
 	
 	n, err = l.multiWriter.Write(p)
 	l.fileSize += int64(n)
@@ -370,20 +425,3 @@ func (l *Logger) Close() error {
 	}
 	return nil
 }
-
-// LogExecutionSummary logs a formatted execution summary for audit purposes
-func (l *Logger) LogExecutionSummary(startTime time.Time, configFile string, mode string, results []string, exitCode int) {
-	duration := time.Since(startTime)
-	
-	l.Info("=== EXECUTION SUMMARY ===")
-	l.Info("Execution details",
-		slog.Time("start_time", startTime),
-		slog.String("config_file", configFile),
-		slog.String("mode", mode),
-		slog.Duration("total_duration", duration),
-		slog.Int("exit_code", exitCode))
-	
-	for _, result := range results {
-		l.Info(result)
-	}
-}
\ No newline at end of file
@@ -526,63 +526,6 @@ func TestLoggerErrorHandling(t *testing.T) {
 	})
 }
 
-// TestExecutionSummaryLogging tests the execution summary functionality
-func TestExecutionSummaryLogging(t *testing.T) {
-	tempDir := t.TempDir()
-
-	config := Config{
-		Enabled:         true,
-		Directory:       tempDir,
-		FilenamePattern: "summary-test.log",
-		Level:           "info",
-		ConsoleOutput:   false,
-	}
-
-	logger, err := NewLogger(config)
-	if err != nil {
-		t.Fatalf("Failed to create logger: %v", err)
-	}
-	defer logger.Close()
-
-	// Test execution summary logging
-	startTime := time.Now().Add(-5 * time.Second) // Simulate 5 seconds ago
-	configFile := "/path/to/config.toml"
-	mode := "Test Mode"
-	results := []string{
-		"Show 1: SUCCESS",
-		"Show 2: FAILED - Network error",
-		"Show 3: SUCCESS",
-	}
-	exitCode := 0
-
-	logger.LogExecutionSummary(startTime, configFile, mode, results, exitCode)
-
-	// Verify the log file contains the summary
-	logFile := filepath.Join(tempDir, "summary-test.log")
-	content, err := os.ReadFile(logFile)
-	if err != nil {
-		t.Fatalf("Failed to read log file: %v", err)
-	}
-
-	contentStr := string(content)
-	
-	// Check that key elements are present in the log
-	expectedElements := []string{
-		"EXECUTION SUMMARY",
-		configFile,
-		mode,
-		"Show 1: SUCCESS",
-		"Show 2: FAILED",
-		"Show 3: SUCCESS",
-	}
-
-	for _, element := range expectedElements {
-		if !strings.Contains(contentStr, element) {
-			t.Errorf("Expected log content to contain %q, but it was not found", element)
-		}
-	}
-}
-
 // TestLoggerWithWriter tests the io.Writer interface implementation
 func TestLoggerWithWriter(t *testing.T) {
 	tempDir := t.TempDir()
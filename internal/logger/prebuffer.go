@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AIDEV-NOTE: Anything logged before Initialize (bad config path, TOML errors, env
+// overrides) used to go to the console only, which cron swallows. The pre-init
+// logger buffers those records and Initialize replays them into the real handler.
+
+// preInitBufferSize bounds how many records are kept before Initialize
+const preInitBufferSize = 200
+
+// envOverridePrefix identifies environment variables that override config values
+const envOverridePrefix = "NWRMIXCLOUD_"
+
+// bufferedRecord is a record captured before Initialize together with the
+// WithAttrs/WithGroup calls of the logger that produced it
+type bufferedRecord struct {
+	record slog.Record
+	ops    []handlerOp
+}
+
+// handlerOp re-applies a WithAttrs or WithGroup call to the real handler
+type handlerOp func(slog.Handler) slog.Handler
+
+// preInitBuffer is a bounded ring of records logged before Initialize.
+// Once bound to the real handler, records are forwarded instead of buffered.
+type preInitBuffer struct {
+	mu      sync.Mutex
+	records []bufferedRecord
+	start   int // Index of the oldest record once the ring has wrapped
+	dropped int
+	target  func() slog.Handler
+}
+
+// newPreInitBuffer creates a buffer seeded with the process's startup context
+func newPreInitBuffer(size int) *preInitBuffer {
+	b := &preInitBuffer{records: make([]bufferedRecord, 0, size)}
+	b.recordStartupContext()
+	return b
+}
+
+// recordStartupContext adds argv, working directory and the names (never the
+// values) of set environment overrides as the first records
+func (b *preInitBuffer) recordStartupContext() {
+	logger := slog.New(&bufferHandler{buf: b})
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		workDir = "(unknown: " + err.Error() + ")"
+	}
+
+	var envOverrides []string
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, envOverridePrefix) && value != "" {
+			envOverrides = append(envOverrides, name)
+		}
+	}
+	sort.Strings(envOverrides)
+
+	logger.Info("Process starting",
+		slog.Any("argv", startupArgs{}),
+		slog.String("working_directory", workDir),
+		slog.String("env_overrides", strings.Join(envOverrides, ",")))
+}
+
+// startupArgs logs os.Args through RedactArgs when the record is written, so
+// secret flags registered after the record was buffered are still hidden
+type startupArgs struct{}
+
+func (startupArgs) LogValue() slog.Value {
+	return slog.StringValue(strings.Join(RedactArgs(os.Args), " "))
+}
+
+// add stores a record, evicting the oldest when the ring is full
+func (b *preInitBuffer) add(rec bufferedRecord) {
+	if len(b.records) < cap(b.records) {
+		b.records = append(b.records, rec)
+		return
+	}
+	if cap(b.records) == 0 {
+		b.dropped++
+		return
+	}
+	b.records[b.start] = rec
+	b.start = (b.start + 1) % len(b.records)
+	b.dropped++
+}
+
+// bind forwards all buffered records, oldest first, to the handler returned by
+// target and routes future records straight to it
+func (b *preInitBuffer) bind(target func() slog.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.target = target
+	handler := target()
+	ctx := context.Background()
+
+	if b.dropped > 0 {
+		warn := slog.NewRecord(b.oldestTime(), slog.LevelWarn, "Early log records dropped before logger initialization", 0)
+		warn.AddAttrs(slog.Int("dropped", b.dropped))
+		handler.Handle(ctx, warn)
+	}
+
+	for i := 0; i < len(b.records); i++ {
+		rec := b.records[(b.start+i)%len(b.records)]
+		h := handler
+		for _, op := range rec.ops {
+			h = op(h)
+		}
+		if h.Enabled(ctx, rec.record.Level) {
+			h.Handle(ctx, rec.record)
+		}
+	}
+
+	b.records = nil
+	b.start = 0
+	b.dropped = 0
+}
+
+// oldestTime returns the timestamp of the oldest buffered record
+func (b *preInitBuffer) oldestTime() time.Time {
+	if len(b.records) == 0 {
+		return time.Now()
+	}
+	return b.records[b.start].record.Time
+}
+
+// bufferHandler is the slog.Handler behind the pre-init logger
+type bufferHandler struct {
+	buf *preInitBuffer
+	ops []handlerOp
+}
+
+func (h *bufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	h.buf.mu.Lock()
+	target := h.buf.target
+	h.buf.mu.Unlock()
+
+	if target != nil {
+		return h.resolve(target()).Enabled(ctx, level)
+	}
+	return true // Level filtering happens when the buffer is replayed
+}
+
+func (h *bufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.buf.mu.Lock()
+	target := h.buf.target
+	if target == nil {
+		h.buf.add(bufferedRecord{record: r.Clone(), ops: h.ops})
+		h.buf.mu.Unlock()
+		return nil
+	}
+	h.buf.mu.Unlock()
+
+	return h.resolve(target()).Handle(ctx, r)
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+// with returns a copy of the handler with one more op recorded
+func (h *bufferHandler) with(op handlerOp) slog.Handler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &bufferHandler{buf: h.buf, ops: append(ops, op)}
+}
+
+// resolve applies the recorded ops to the real handler
+func (h *bufferHandler) resolve(target slog.Handler) slog.Handler {
+	for _, op := range h.ops {
+		target = op(target)
+	}
+	return target
+}
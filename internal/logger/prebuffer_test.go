@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// resetGlobalLogger restores the package to its pre-Initialize state
+func resetGlobalLogger(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		if globalLogger != nil {
+			globalLogger.Close()
+		}
+		globalLogger = nil
+		once = sync.Once{}
+		preInit = newPreInitBuffer(preInitBufferSize)
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestPreInitRecordsFlushedToLogFile(t *testing.T) {
+	// Set before the reset so the startup context record picks it up
+	t.Setenv("NWRMIXCLOUD_OAUTH_ACCESS_TOKEN", "secret-token-value")
+	resetGlobalLogger(t)
+
+	Get().With(slog.String("phase", "startup")).Warn("Config file unreadable",
+		slog.String("path", "/etc/mixcloud/config.toml"))
+
+	tempDir := t.TempDir()
+	err := Initialize(Config{
+		Enabled:         true,
+		Directory:       tempDir,
+		FilenamePattern: "prebuffer.log",
+		Level:           "info",
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	Get().Info("After initialize")
+
+	data, err := os.ReadFile(Get().fileName)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	content := string(data)
+
+	wantInOrder := []string{
+		"Process starting",
+		"Config file unreadable",
+		"Logger initialized",
+		"After initialize",
+	}
+	last := -1
+	for _, want := range wantInOrder {
+		idx := strings.Index(content, want)
+		if idx == -1 {
+			t.Fatalf("log file missing %q:\n%s", want, content)
+		}
+		if idx < last {
+			t.Errorf("%q logged out of order:\n%s", want, content)
+		}
+		last = idx
+	}
+
+	for _, want := range []string{"phase=startup", "path=/etc/mixcloud/config.toml", "working_directory=", "env_overrides=NWRMIXCLOUD_OAUTH_ACCESS_TOKEN"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("log file missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "secret-token-value") {
+		t.Error("env override values must not be logged")
+	}
+}
+
+func TestPreInitBufferIsBounded(t *testing.T) {
+	buf := &preInitBuffer{records: make([]bufferedRecord, 0, 3)}
+	log := slog.New(&bufferHandler{buf: buf})
+	for i := 1; i <= 5; i++ {
+		log.Info(fmt.Sprintf("message %d", i))
+	}
+
+	var out bytes.Buffer
+	buf.bind(func() slog.Handler { return slog.NewTextHandler(&out, nil) })
+
+	content := out.String()
+	for _, dropped := range []string{"message 1", "message 2"} {
+		if strings.Contains(content, dropped) {
+			t.Errorf("expected %q to be evicted:\n%s", dropped, content)
+		}
+	}
+	for _, kept := range []string{"message 3", "message 4", "message 5", "dropped=2"} {
+		if !strings.Contains(content, kept) {
+			t.Errorf("expected %q in flushed output:\n%s", kept, content)
+		}
+	}
+
+	// Records after bind go straight to the target handler
+	log.Info("message 6")
+	if !strings.Contains(out.String(), "message 6") {
+		t.Error("records after bind should be forwarded")
+	}
+}
@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AIDEV-NOTE: Every handler the package builds - file/console, the fallback
+// after a failed Initialize and the pre-init buffer - is wrapped in a
+// redactHandler, so a secret can't reach a log through a debug dump of the
+// config or an error quoting a request URL. Secrets are registered at runtime
+// with AddSecret as they become known (config load, token refresh); patterns
+// come from [logging.redact]. Records buffered before Initialize are redacted
+// again on replay, once the config's secrets are registered.
+
+// Kinds of registered secrets, shown in the [REDACTED:kind] marker
+const (
+	SecretClientSecret  = "oauth_client_secret"
+	SecretToken         = "oauth_token"
+	SecretProxyPassword = "proxy_password"
+)
+
+// minSecretLength keeps short or placeholder values from redacting ordinary
+// words, e.g. a client_secret of "x" in a test config
+const minSecretLength = 8
+
+// secretValue is a registered secret and the kind its marker names
+type secretValue struct {
+	value string
+	kind  string
+}
+
+var (
+	secretsMu   sync.RWMutex
+	secrets     []secretValue     // Longest first, so a secret containing another is replaced whole
+	secretFlags map[string]string // Command-line flags holding a secret, by name, see RedactArgs
+)
+
+// AddSecret makes every logger replace value with [REDACTED:kind] wherever it
+// appears in a message or attribute, including its URL-encoded form. Values
+// shorter than 8 characters are ignored.
+func AddSecret(kind, value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, variant := range []string{value, url.QueryEscape(value)} {
+		known := false
+		for _, secret := range secrets {
+			if secret.value == variant {
+				known = true
+				break
+			}
+		}
+		if !known {
+			secrets = append(secrets, secretValue{value: variant, kind: kind})
+		}
+	}
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i].value) > len(secrets[j].value) })
+}
+
+// AddSecretFlag makes RedactArgs hide the value of the command-line flag name
+// (without dashes) behind [REDACTED:kind], however short the value is
+func AddSecretFlag(name, kind string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if secretFlags == nil {
+		secretFlags = make(map[string]string)
+	}
+	secretFlags[name] = kind
+}
+
+// RedactArgs returns a copy of args with the values of flags registered with
+// AddSecretFlag redacted, in the -name value, -name=value and --name forms. A
+// bare name is a subcommand taking the secret as its argument, e.g. "auth
+// set-token <token>". The stdin placeholder "-" is kept.
+func RedactArgs(args []string) []string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+
+	redacted := append([]string(nil), args...)
+	for i := 0; i < len(redacted); i++ {
+		name := strings.TrimLeft(redacted[i], "-")
+		name, value, hasValue := strings.Cut(name, "=")
+		kind, ok := secretFlags[name]
+		switch {
+		case !ok:
+		case hasValue && value != "-":
+			redacted[i] = redacted[i][:len(redacted[i])-len(value)] + redactMarker(kind)
+		case !hasValue && i+1 < len(redacted) && redacted[i+1] != "-":
+			i++
+			redacted[i] = redactMarker(kind)
+		}
+	}
+	return redacted
+}
+
+// redactMarker is what a redacted value of kind is replaced with
+func redactMarker(kind string) string {
+	return "[REDACTED:" + kind + "]"
+}
+
+// redactRule is a compiled [logging.redact] pattern
+type redactRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// ValidateRedactRules reports the first [logging.redact] pattern that doesn't compile
+func ValidateRedactRules(patterns map[string]string) error {
+	_, err := compileRedactRules(patterns)
+	return err
+}
+
+// compileRedactRules compiles [logging.redact] patterns, keyed by the name
+// their [REDACTED:name] marker shows, in name order
+func compileRedactRules(patterns map[string]string) ([]redactRule, error) {
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make([]redactRule, 0, len(names))
+	for _, name := range names {
+		pattern, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("logging.redact.%s: %w", name, err)
+		}
+		rules = append(rules, redactRule{name: name, pattern: pattern})
+	}
+	return rules, nil
+}
+
+// redactHandler scrubs registered secrets and rule matches from records before
+// passing them on
+type redactHandler struct {
+	next  slog.Handler
+	rules []redactRule
+}
+
+// newRedactHandler wraps next so nothing it writes contains a secret
+func newRedactHandler(next slog.Handler, rules []redactRule) slog.Handler {
+	return &redactHandler{next: next, rules: rules}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, h.redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	return &redactHandler{next: h.next.WithAttrs(redacted), rules: h.rules}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name), rules: h.rules}
+}
+
+// redactAttr redacts an attribute's value, recursing into groups. Errors,
+// structs and other values are checked as the text the handler would print and
+// replaced by the redacted text when it held a secret.
+func (h *redactHandler) redactAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(h.redact(a.Value.String()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = h.redactAttr(member)
+		}
+		a.Value = slog.GroupValue(redacted...)
+	case slog.KindAny:
+		text := fmt.Sprintf("%+v", a.Value.Any())
+		if redacted := h.redact(text); redacted != text {
+			a.Value = slog.StringValue(redacted)
+		}
+	}
+	return a
+}
+
+// redact replaces registered secrets, then rule matches, in s
+func (h *redactHandler) redact(s string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		if strings.Contains(s, secret.value) {
+			s = strings.ReplaceAll(s, secret.value, redactMarker(secret.kind))
+		}
+	}
+	secretsMu.RUnlock()
+
+	for _, rule := range h.rules {
+		s = rule.pattern.ReplaceAllLiteralString(s, redactMarker(rule.name))
+	}
+	return s
+}
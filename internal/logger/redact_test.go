@@ -0,0 +1,257 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+// resetSecrets forgets the registered secrets before and after a test
+func resetSecrets(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		secretsMu.Lock()
+		secrets = nil
+		secretFlags = nil
+		secretsMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+// readLog returns the contents of l's log file
+func readLog(t *testing.T, l *Logger) string {
+	t.Helper()
+	data, err := os.ReadFile(l.fileName)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	return string(data)
+}
+
+type tokenHolder struct {
+	Name  string
+	Token string
+}
+
+func TestRedactSecretsFromLogFile(t *testing.T) {
+	resetSecrets(t)
+	const (
+		clientSecret = "cs-9f8e7d6c5b4a"
+		accessToken  = "at/abc+def=123456"
+	)
+	AddSecret(SecretClientSecret, clientSecret)
+	AddSecret(SecretToken, accessToken)
+	AddSecret(SecretToken, "short") // Below minSecretLength, never redacted
+
+	l, err := NewLogger(Config{
+		Enabled:         true,
+		Directory:       t.TempDir(),
+		FilenamePattern: "redact.log",
+		Level:           "debug",
+	})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	defer l.Close()
+
+	baseErr := fmt.Errorf("token %s rejected", accessToken)
+	wrapped := fmt.Errorf("refreshing: %w", fmt.Errorf("request failed: %w", baseErr))
+
+	l.Info("Using client secret " + clientSecret)
+	l.Debug("Token", slog.String("access_token", accessToken))
+	l.Error("Upload failed", slog.String("error", wrapped.Error()))
+	l.Error("Upload failed", slog.Any("err", wrapped))
+	l.Debug("Config", slog.Any("oauth", tokenHolder{Name: "main", Token: accessToken}))
+	l.With(slog.String("secret", clientSecret)).WithGroup("request").Info("Request",
+		slog.Group("query", slog.String("url", "https://api.mixcloud.com/me/?access_token="+url.QueryEscape(accessToken))))
+	l.Info("A short word", slog.String("value", "short"))
+
+	content := readLog(t, l)
+	for _, secret := range []string{clientSecret, accessToken, url.QueryEscape(accessToken)} {
+		if strings.Contains(content, secret) {
+			t.Errorf("log file contains secret %q:\n%s", secret, content)
+		}
+	}
+	if got := strings.Count(content, "[REDACTED:oauth_token]"); got < 5 {
+		t.Errorf("found %d [REDACTED:oauth_token] markers, want at least 5:\n%s", got, content)
+	}
+	for _, want := range []string{"[REDACTED:oauth_client_secret]", "refreshing: request failed", "Name:main", "value=short"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("log file missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestRedactRules(t *testing.T) {
+	resetSecrets(t)
+
+	tests := []struct {
+		name     string
+		patterns map[string]string
+		message  string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "listener name from a dedication",
+			patterns: map[string]string{"listener": `(?i)for [A-Z][a-z]+ [A-Z][a-z]+`},
+			message:  "REM COMMENT Dedicated for Jane Doe",
+			want:     "Dedicated [REDACTED:listener]",
+		},
+		{
+			name:     "email address",
+			patterns: map[string]string{"email": `[\w.+-]+@[\w-]+\.[\w.]+`},
+			message:  "Contact host@station.example about show",
+			want:     "Contact [REDACTED:email] about show",
+		},
+		{
+			name:     "no rules",
+			patterns: nil,
+			message:  "nothing to hide",
+			want:     "nothing to hide",
+		},
+		{
+			name:     "invalid pattern",
+			patterns: map[string]string{"broken": `(unclosed`},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRedactRules(tt.patterns); (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRedactRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			l, err := NewLogger(Config{
+				Enabled:         true,
+				Directory:       t.TempDir(),
+				FilenamePattern: "rules.log",
+				Level:           "info",
+				Redact:          tt.patterns,
+			})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "logging.redact.broken") {
+					l.Close()
+					t.Fatalf("NewLogger() error = %v, want one naming logging.redact.broken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLogger() error = %v", err)
+			}
+			defer l.Close()
+
+			l.Info("Cue", slog.String("line", tt.message))
+			if content := readLog(t, l); !strings.Contains(content, tt.want) {
+				t.Errorf("log file missing %q:\n%s", tt.want, content)
+			}
+		})
+	}
+}
+
+func TestPreInitRecordsRedacted(t *testing.T) {
+	resetSecrets(t)
+	resetGlobalLogger(t)
+	const refreshToken = "rt-0123456789abcdef"
+
+	// Logged before the secret is known; redacted when replayed
+	Get().Warn("Loaded token", slog.String("refresh_token", refreshToken))
+	AddSecret(SecretToken, refreshToken)
+	Get().Info("Refreshing with " + refreshToken)
+
+	if err := Initialize(Config{
+		Enabled:         true,
+		Directory:       t.TempDir(),
+		FilenamePattern: "preinit.log",
+		Level:           "info",
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	content := readLog(t, Get())
+	if strings.Contains(content, refreshToken) {
+		t.Errorf("log file contains the refresh token:\n%s", content)
+	}
+	if got := strings.Count(content, "[REDACTED:oauth_token]"); got != 2 {
+		t.Errorf("found %d [REDACTED:oauth_token] markers, want 2:\n%s", got, content)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	resetSecrets(t)
+	AddSecretFlag("set-token", SecretToken)
+	AddSecretFlag("init-client-secret", SecretClientSecret)
+
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{"flag and value", "-init -init-client-secret abc config.toml", "-init -init-client-secret [REDACTED:oauth_client_secret] config.toml"},
+		{"double dash with equals", "--init-client-secret=abc config.toml", "--init-client-secret=[REDACTED:oauth_client_secret] config.toml"},
+		{"subcommand argument", "auth set-token tok123 config.toml", "auth set-token [REDACTED:oauth_token] config.toml"},
+		{"stdin placeholder kept", "-set-token - config.toml", "-set-token - config.toml"},
+		{"flag without value", "-set-token", "-set-token"},
+		{"other flags untouched", "-show nnw -dry-run config.toml", "-show nnw -dry-run config.toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(RedactArgs(strings.Fields(tt.args)), " ")
+			if got != tt.want {
+				t.Errorf("RedactArgs(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+
+	// Every secret flag, in both the -flag value and -flag=value forms
+	for name, kind := range map[string]string{"set-token": SecretToken, "init-client-secret": SecretClientSecret} {
+		for _, args := range [][]string{{"-" + name, "s3cret"}, {"-" + name + "=s3cret"}, {"--" + name, "s3cret"}, {"--" + name + "=s3cret"}} {
+			got := strings.Join(RedactArgs(args), " ")
+			if strings.Contains(got, "s3cret") || !strings.Contains(got, redactMarker(kind)) {
+				t.Errorf("RedactArgs(%q) = %q, want the value replaced by %s", args, got, redactMarker(kind))
+			}
+		}
+	}
+}
+
+// TestCommandLineSecretsRedacted checks a secret passed on the command line
+// reaches neither the buffered startup record nor a later one quoting argv,
+// even when it is too short for AddSecret
+func TestCommandLineSecretsRedacted(t *testing.T) {
+	resetSecrets(t)
+	const clientSecret, shortToken = "SUPERSECRET123", "tok42"
+	savedArgs := os.Args
+	defer func() { os.Args = savedArgs }()
+	os.Args = []string{"mixcloud-updater", "-init", "-no-prompt", "-init-client-secret", clientSecret, "-set-token=" + shortToken, "config.toml"}
+	// The startup record is buffered before the secrets are known
+	resetGlobalLogger(t)
+
+	AddSecretFlag("init-client-secret", SecretClientSecret)
+	AddSecretFlag("set-token", SecretToken)
+	AddSecret(SecretClientSecret, clientSecret)
+	if err := Initialize(Config{
+		Enabled:         true,
+		Directory:       t.TempDir(),
+		FilenamePattern: "argv.log",
+		Level:           "info",
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	Get().Info("Mixcloud Updater started", slog.String("command", strings.Join(RedactArgs(os.Args), " ")))
+	Get().Info("Writing config", slog.String("client_secret", clientSecret))
+
+	content := readLog(t, Get())
+	for _, secret := range []string{clientSecret, shortToken} {
+		if strings.Contains(content, secret) {
+			t.Errorf("log file contains %q:\n%s", secret, content)
+		}
+	}
+	if got := strings.Count(content, "-init-client-secret [REDACTED:oauth_client_secret]"); got != 2 {
+		t.Errorf("found the redacted client secret flag %d times, want 2 (startup and started records):\n%s", got, content)
+	}
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AIDEV-NOTE: ExecutionSummary is the one record of how a run ended. main fills
+// it in (processor.BatchResult.Summarize adds the per-show outcomes) and every
+// consumer reads from it: the audit log's text block and JSON record below and
+// processing.status_file. Add new outcome details here rather than formatting
+// them at the call site, so the consumers can't drift apart.
+
+// RunStatus is the outcome of a whole run
+type RunStatus string
+
+// Values for ExecutionSummary.Status
+const (
+	RunSuccess          RunStatus = "success"
+	RunFailure          RunStatus = "failure"
+	RunNoShowsProcessed RunStatus = "no_shows_processed" // Nothing to process, e.g. every show disabled
+	RunOffline          RunStatus = "offline"            // Shows queued for -retry-failed
+	RunLocked           RunStatus = "locked"             // Another instance held the run lock
+)
+
+// ShowStatus is the outcome of a single show. The values match the statuses of
+// the processor's show_finished progress events.
+type ShowStatus string
+
+// Values for ShowOutcome.Status
+const (
+	ShowSuccess      ShowStatus = "success"
+	ShowFailed       ShowStatus = "failed"
+	ShowSkipped      ShowStatus = "skipped"
+	ShowQueued       ShowStatus = "queued"        // Offline run: queued for -retry-failed
+	ShowNotAttempted ShowStatus = "not_attempted" // Left out by -limit, the run deadline or a failure threshold
+)
+
+// ShowOutcome is how a single show of the run ended
+type ShowOutcome struct {
+	ShowKey         string     `json:"show_key"`
+	Status          ShowStatus `json:"status"`
+	URL             string     `json:"url,omitempty"`
+	FailureCategory string     `json:"failure_category,omitempty"` // Set with ShowFailed
+	Error           string     `json:"error,omitempty"`
+	DurationMS      int64      `json:"duration_ms,omitempty"`
+	CarriedOver     bool       `json:"carried_over,omitempty"` // Published by the interrupted run this run resumed
+	Anomaly         string     `json:"anomaly,omitempty"`      // Verdict of the comparison with the show's recent publishes, e.g. "shrunk"
+	AnomalyDetail   string     `json:"anomaly_detail,omitempty"`
+}
+
+// RunTotals counts the shows of a run by outcome
+type RunTotals struct {
+	Total        int `json:"total"`
+	Processed    int `json:"processed"`
+	Successful   int `json:"successful"`
+	Failed       int `json:"failed"`
+	Skipped      int `json:"skipped"`
+	Placeholders int `json:"placeholders"`
+	Queued       int `json:"queued"`
+	CarriedOver  int `json:"carried_over,omitempty"`
+	NotAttempted int `json:"not_attempted"`
+}
+
+// ExecutionSummary describes a finished run for the audit log and status file
+type ExecutionSummary struct {
+	Mode          string        `json:"mode"`             // Run mode, e.g. "single" or "batch"; "command" for other commands
+	Target        string        `json:"target,omitempty"` // Show alias, group, backfill show or command name
+	DryRun        bool          `json:"dry_run,omitempty"`
+	Offline       bool          `json:"offline,omitempty"`
+	ConfigFile    string        `json:"config_file"`
+	StartTime     time.Time     `json:"start_time"`
+	EndTime       time.Time     `json:"end_time"`
+	Status        RunStatus     `json:"status"`
+	Detail        string        `json:"detail,omitempty"` // e.g. why no show was processed or the doctor status
+	Error         string        `json:"error,omitempty"`  // Why the run stopped early or failed
+	Shows         []ShowOutcome `json:"shows,omitempty"`
+	Totals        *RunTotals    `json:"totals,omitempty"` // nil when no processing run finished
+	ReauthPauseMS int64         `json:"reauth_pause_ms,omitempty"`
+	ExitCode      int           `json:"exit_code"`
+}
+
+// Duration returns how long the run took
+func (s ExecutionSummary) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// FailedShowKeys returns the keys of the shows that failed, in processing order
+func (s ExecutionSummary) FailedShowKeys() []string {
+	var keys []string
+	for _, show := range s.Shows {
+		if show.Status == ShowFailed {
+			keys = append(keys, show.ShowKey)
+		}
+	}
+	return keys
+}
+
+// Headline describes the run in one line, e.g. "single nnw: SUCCESS" or
+// "batch: FAILURE - 3 of 12 shows failed"
+func (s ExecutionSummary) Headline() string {
+	line := s.Mode
+	if s.Target != "" {
+		line += " " + s.Target
+	}
+	line += ": " + strings.ToUpper(string(s.Status))
+	if s.Error != "" {
+		return line + " - " + s.Error
+	}
+	if s.Detail != "" {
+		return line + " - " + s.Detail
+	}
+	return line
+}
+
+// MarshalJSON adds the run's duration_ms to the summary's fields
+func (s ExecutionSummary) MarshalJSON() ([]byte, error) {
+	type plain ExecutionSummary // Without this method
+	return json.Marshal(struct {
+		plain
+		DurationMS int64 `json:"duration_ms"`
+	}{plain(s), s.Duration().Milliseconds()})
+}
+
+// LogExecutionSummary logs the summary for audit purposes: a text block for
+// people reading the log, then the whole summary as one JSON record
+func (l *Logger) LogExecutionSummary(summary ExecutionSummary) {
+	l.Info("=== EXECUTION SUMMARY ===")
+	l.Info("Execution details",
+		slog.Time("start_time", summary.StartTime),
+		slog.Time("end_time", summary.EndTime),
+		slog.String("config_file", summary.ConfigFile),
+		slog.String("mode", summary.Mode),
+		slog.String("target", summary.Target),
+		slog.String("status", string(summary.Status)),
+		slog.Duration("total_duration", summary.Duration()),
+		slog.Int("exit_code", summary.ExitCode))
+
+	l.Info(summary.Headline())
+	for _, show := range summary.Shows {
+		line := fmt.Sprintf("%s: %s", show.ShowKey, strings.ToUpper(string(show.Status)))
+		if show.Error != "" {
+			line += " - " + show.Error
+		}
+		l.Info(line)
+	}
+	if summary.ReauthPauseMS > 0 {
+		pause := time.Duration(summary.ReauthPauseMS) * time.Millisecond
+		l.Info(fmt.Sprintf("Paused %s for re-authentication", pause.Round(time.Second)))
+	}
+
+	record, err := json.Marshal(summary)
+	if err != nil {
+		l.Warn("Failed to encode execution summary", slog.String("error", err.Error()))
+	} else {
+		l.Info("Execution summary record", slog.String("summary", string(record)))
+	}
+
+	if summary.Status == RunNoShowsProcessed {
+		l.LogEmptyRunSummary(summary.Detail, summary.ExitCode)
+	}
+}
+
+// LogEmptyRunSummary logs a labeled block for a run that had no show to process,
+// so it stands out from successful runs in the audit log
+func (l *Logger) LogEmptyRunSummary(reason string, exitCode int) {
+	l.Warn("=== NO SHOWS PROCESSED ===")
+	l.Warn("Run finished without processing any show",
+		slog.String("reason", reason),
+		slog.Int("exit_code", exitCode))
+}
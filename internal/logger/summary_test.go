@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogExecutionSummary(t *testing.T) {
+	start := time.Date(2025, 6, 28, 22, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+
+	tests := []struct {
+		name         string
+		summary      ExecutionSummary
+		wantHeadline string
+		wantLines    []string
+	}{
+		{
+			name: "single show success",
+			summary: ExecutionSummary{
+				Mode: "single", Target: "nnw", Status: RunSuccess,
+				Shows:  []ShowOutcome{{ShowKey: "nnw", Status: ShowSuccess, URL: "https://www.mixcloud.com/test/nnw/", DurationMS: 1200}},
+				Totals: &RunTotals{Total: 1, Processed: 1, Successful: 1},
+			},
+			wantHeadline: "single nnw: SUCCESS",
+			wantLines:    []string{"nnw: SUCCESS"},
+		},
+		{
+			name: "single show failure",
+			summary: ExecutionSummary{
+				Mode: "single", Target: "nnw", Status: RunFailure, ExitCode: 1,
+				Error:  "processing show nnw: show not found",
+				Shows:  []ShowOutcome{{ShowKey: "nnw", Status: ShowFailed, FailureCategory: "not_found", Error: "show not found"}},
+				Totals: &RunTotals{Total: 1, Processed: 1, Failed: 1},
+			},
+			wantHeadline: "single nnw: FAILURE - processing show nnw: show not found",
+			wantLines:    []string{"nnw: FAILED - show not found"},
+		},
+		{
+			name: "mixed batch",
+			summary: ExecutionSummary{
+				Mode: "batch", Status: RunFailure, ExitCode: 1,
+				Error: "1 of 3 shows failed",
+				Shows: []ShowOutcome{
+					{ShowKey: "nnw", Status: ShowSuccess},
+					{ShowKey: "sl", Status: ShowFailed, FailureCategory: "network", Error: "connection refused"},
+					{ShowKey: "vault", Status: ShowNotAttempted},
+				},
+				Totals:        &RunTotals{Total: 3, Processed: 2, Successful: 1, Failed: 1, NotAttempted: 1},
+				ReauthPauseMS: 42000,
+			},
+			wantHeadline: "batch: FAILURE - 1 of 3 shows failed",
+			wantLines:    []string{"nnw: SUCCESS", "sl: FAILED - connection refused", "vault: NOT_ATTEMPTED", "Paused 42s for re-authentication"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			logger, err := NewLogger(Config{Enabled: true, Directory: dir, FilenamePattern: "summary.log", Level: "info"})
+			if err != nil {
+				t.Fatalf("NewLogger() error = %v", err)
+			}
+			summary := tt.summary
+			summary.ConfigFile = "/path/to/config.toml"
+			summary.StartTime, summary.EndTime = start, end
+			logger.LogExecutionSummary(summary)
+			logger.Close()
+
+			if got := summary.Headline(); got != tt.wantHeadline {
+				t.Errorf("Headline() = %q, want %q", got, tt.wantHeadline)
+			}
+			content, err := os.ReadFile(filepath.Join(dir, "summary.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			log := string(content)
+			for _, want := range append([]string{"EXECUTION SUMMARY", "config_file=/path/to/config.toml", "total_duration=1m30s", tt.wantHeadline}, tt.wantLines...) {
+				if !strings.Contains(log, want) {
+					t.Errorf("log doesn't contain %q:\n%s", want, log)
+				}
+			}
+
+			// The JSON record decodes back to the summary
+			_, quoted, found := strings.Cut(log, "summary=")
+			if !found {
+				t.Fatalf("log has no summary record:\n%s", log)
+			}
+			record, err := strconv.QuotedPrefix(quoted)
+			if err == nil {
+				record, err = strconv.Unquote(record)
+			}
+			if err != nil {
+				t.Fatalf("summary record isn't a quoted string: %v", err)
+			}
+			var decoded ExecutionSummary
+			var duration struct {
+				MS int64 `json:"duration_ms"`
+			}
+			if err := json.Unmarshal([]byte(record), &decoded); err != nil {
+				t.Fatalf("decoding summary record: %v", err)
+			}
+			if err := json.Unmarshal([]byte(record), &duration); err != nil || duration.MS != 90000 {
+				t.Errorf("duration_ms = %d (%v), want 90000", duration.MS, err)
+			}
+			decoded.StartTime, decoded.EndTime = decoded.StartTime.UTC(), decoded.EndTime.UTC()
+			if !reflect.DeepEqual(decoded, summary) {
+				t.Errorf("summary record = %+v, want %+v", decoded, summary)
+			}
+		})
+	}
+}
+
+func TestExecutionSummaryNoShowsProcessed(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewLogger(Config{Enabled: true, Directory: dir, FilenamePattern: "summary.log", Level: "info"})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	logger.LogExecutionSummary(ExecutionSummary{Mode: "batch", Status: RunNoShowsProcessed, Detail: "every show is disabled", ExitCode: 3})
+	logger.Close()
+
+	content, err := os.ReadFile(filepath.Join(dir, "summary.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"batch: NO_SHOWS_PROCESSED - every show is disabled", "NO SHOWS PROCESSED", `reason="every show is disabled"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("log doesn't contain %q:\n%s", want, content)
+		}
+	}
+}
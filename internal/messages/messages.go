@@ -0,0 +1,120 @@
+// Package messages holds the boilerplate the formatters write into descriptions
+// - fallbacks for missing track fields and the markers ending a cut tracklist -
+// in each bundled locale.
+package messages
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AIDEV-NOTE: Formatters measure these strings with the description length
+// model rather than assuming the English lengths: translations and per-show
+// overrides differ in length, and accented letters take two bytes under the raw
+// model.
+
+// Catalog is the set of strings for one locale. Empty fields fall back to
+// English, so a show's [strings] table only names what it overrides.
+type Catalog struct {
+	UnknownTitle  string `toml:"unknown_title"`  // Stands in for a track without a title
+	UnknownArtist string `toml:"unknown_artist"` // Stands in for a track without an artist
+	AndMore       string `toml:"and_more"`       // Ends a classic tracklist cut to fit
+	MoreTracks    string `toml:"more_tracks"`    // Ends a template tracklist cut to fit; {count} is the number dropped
+}
+
+// DefaultLocale is used for shows without a locale
+const DefaultLocale = "en"
+
+// CountPlaceholder is replaced by the number of dropped tracks in MoreTracks
+const CountPlaceholder = "{count}"
+
+var bundled = map[string]Catalog{
+	"en": {
+		UnknownTitle:  "(Unknown Title)",
+		UnknownArtist: "(Unknown Artist)",
+		AndMore:       "... and more",
+		MoreTracks:    "... and {count} more tracks",
+	},
+	"es": {
+		UnknownTitle:  "(Título desconocido)",
+		UnknownArtist: "(Artista desconocido)",
+		AndMore:       "... y más",
+		MoreTracks:    "... y {count} temas más",
+	},
+}
+
+// Default returns the English catalog
+func Default() Catalog {
+	return bundled[DefaultLocale]
+}
+
+// Locales returns the bundled locales, sorted
+func Locales() []string {
+	locales := make([]string, 0, len(bundled))
+	for locale := range bundled {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// ForLocale returns the catalog for locale, e.g. "es". Only the language part of
+// a tag like "es-MX" or "es_MX" is used; "" means DefaultLocale.
+func ForLocale(locale string) (Catalog, error) {
+	language := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	if language == "" {
+		language = DefaultLocale
+	}
+	catalog, ok := bundled[language]
+	if !ok {
+		return Default(), fmt.Errorf("unknown locale %q (available: %s)", locale, strings.Join(Locales(), ", "))
+	}
+	return catalog, nil
+}
+
+// With returns c with every non-empty field of overrides replacing its own, and
+// fields still empty taken from the English catalog
+func (c Catalog) With(overrides Catalog) Catalog {
+	result := Default()
+	for _, source := range []Catalog{c, overrides} {
+		if source.UnknownTitle != "" {
+			result.UnknownTitle = source.UnknownTitle
+		}
+		if source.UnknownArtist != "" {
+			result.UnknownArtist = source.UnknownArtist
+		}
+		if source.AndMore != "" {
+			result.AndMore = source.AndMore
+		}
+		if source.MoreTracks != "" {
+			result.MoreTracks = source.MoreTracks
+		}
+	}
+	return result
+}
+
+// MoreTracksText returns MoreTracks for count dropped tracks
+func (c Catalog) MoreTracksText(count int) string {
+	return strings.ReplaceAll(Default().With(c).MoreTracks, CountPlaceholder, strconv.Itoa(count))
+}
+
+// EndsTruncated reports whether description ends with the AndMore or MoreTracks
+// marker of c, i.e. a formatter dropped tracks to fit the limit
+func (c Catalog) EndsTruncated(description string) bool {
+	c = Default().With(c)
+	description = strings.TrimRight(description, " \t\r\n")
+	if strings.HasSuffix(description, c.AndMore) {
+		return true
+	}
+	parts := strings.Split(c.MoreTracks, CountPlaceholder)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile(strings.Join(parts, `\d+`) + `$`).MatchString(description)
+}
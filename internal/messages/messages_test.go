@@ -0,0 +1,88 @@
+package messages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForLocale(t *testing.T) {
+	tests := []struct {
+		locale     string
+		wantArtist string
+		wantErr    bool
+	}{
+		{"", "(Unknown Artist)", false},
+		{"en", "(Unknown Artist)", false},
+		{"es", "(Artista desconocido)", false},
+		{"es-MX", "(Artista desconocido)", false},
+		{"ES_es", "(Artista desconocido)", false},
+		{"fr", "(Unknown Artist)", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			catalog, err := ForLocale(tt.locale)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ForLocale(%q) error = %v, wantErr %v", tt.locale, err, tt.wantErr)
+			}
+			if catalog.UnknownArtist != tt.wantArtist {
+				t.Errorf("UnknownArtist = %q, want %q", catalog.UnknownArtist, tt.wantArtist)
+			}
+		})
+	}
+}
+
+func TestBundledCatalogsAreComplete(t *testing.T) {
+	for _, locale := range Locales() {
+		catalog := bundled[locale]
+		if catalog != Default().With(catalog) {
+			t.Errorf("%s catalog leaves strings to English: %+v", locale, catalog)
+		}
+		if !strings.Contains(catalog.MoreTracks, CountPlaceholder) {
+			t.Errorf("%s more_tracks %q has no %s", locale, catalog.MoreTracks, CountPlaceholder)
+		}
+	}
+}
+
+func TestWith(t *testing.T) {
+	spanish, _ := ForLocale("es")
+	got := spanish.With(Catalog{UnknownArtist: "(Sin artista)"})
+	want := Catalog{
+		UnknownTitle:  "(Título desconocido)",
+		UnknownArtist: "(Sin artista)",
+		AndMore:       "... y más",
+		MoreTracks:    "... y {count} temas más",
+	}
+	if got != want {
+		t.Errorf("With() = %+v, want %+v", got, want)
+	}
+
+	if got := (Catalog{}).MoreTracksText(3); got != "... and 3 more tracks" {
+		t.Errorf("zero Catalog MoreTracksText(3) = %q, want the English text", got)
+	}
+}
+
+func TestEndsTruncated(t *testing.T) {
+	spanish, _ := ForLocale("es")
+	custom := spanish.With(Catalog{MoreTracks: "(+{count})"})
+	tests := []struct {
+		name        string
+		catalog     Catalog
+		description string
+		want        bool
+	}{
+		{"english classic", Catalog{}, "00:00 - \"Song\" by Artist\n... and more", true},
+		{"english template", Catalog{}, "1. Song\n... and 12 more tracks\n", true},
+		{"english complete", Catalog{}, "00:00 - \"Give Me More\" by Artist", false},
+		{"spanish classic", spanish, "00:00 - \"Canción\" by Grupo\n... y más", true},
+		{"spanish template", spanish, "1. Canción\n... y 4 temas más\n", true},
+		{"spanish show, english marker", spanish, "1. Song\n... and 4 more tracks\n", false},
+		{"custom marker", custom, "1. Canción\n(+4)\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.catalog.EndsTruncated(tt.description); got != tt.want {
+				t.Errorf("EndsTruncated(%q) = %v, want %v", tt.description, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package mixcloud
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// AIDEV-NOTE: GetShow runs for every show on every run. Mixcloud sends ETag and
+// Last-Modified validators, so responses are cached (in the state file) and
+// revalidated with If-None-Match/If-Modified-Since; a 304 reuses the cached body.
+
+// ShowCache stores GetShow responses by cloudcast key. *state.State implements it.
+type ShowCache interface {
+	CachedShow(cloudcastKey string) (state.CachedShow, bool)
+	StoreCachedShow(cloudcastKey string, cached state.CachedShow)
+}
+
+// SetShowCache enables conditional GetShow requests backed by cache; nil disables them
+func (c *Client) SetShowCache(cache ShowCache) {
+	c.showCache = cache
+}
+
+// SetBaseURL points the client at another API root, e.g. a local test server
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+}
+
+// apiBaseURL returns the API root, overridable so tests can point at a local server
+func (c *Client) apiBaseURL() string {
+	if c.baseURL != "" {
+		return c.baseURL
+	}
+	return MixcloudAPIBaseURL
+}
+
+// addCacheValidators makes req conditional on the cached response for cloudcastKey,
+// returning the cached entry if there is one
+func (c *Client) addCacheValidators(req *http.Request, cloudcastKey string) (state.CachedShow, bool) {
+	if c.showCache == nil {
+		return state.CachedShow{}, false
+	}
+	cached, ok := c.showCache.CachedShow(cloudcastKey)
+	if !ok || len(cached.Body) == 0 || (cached.ETag == "" && cached.LastModified == "") {
+		return state.CachedShow{}, false
+	}
+
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+	return cached, true
+}
+
+// cacheResponse stores a 200 response that carries validators
+func (c *Client) cacheResponse(cloudcastKey string, resp *http.Response, body []byte) {
+	if c.showCache == nil {
+		return
+	}
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return // Nothing to revalidate with
+	}
+
+	c.showCache.StoreCachedShow(cloudcastKey, state.CachedShow{
+		ETag:         etag,
+		LastModified: lastModified,
+		FetchedAt:    time.Now(),
+		Body:         body,
+	})
+}
@@ -0,0 +1,159 @@
+package mixcloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+const cacheTestShowURL = "https://www.mixcloud.com/nowwaveradio/the-newer-new-wave-show-june-28-2025/"
+
+// cloudcastServer serves a cloudcast with the given ETag, answering 304 when
+// the request's If-None-Match matches, and records the validators it received
+type cloudcastServer struct {
+	mu          sync.Mutex
+	etag        string
+	name        string
+	ifNoneMatch []string // If-None-Match of every request, "" when absent
+	notModified int
+}
+
+func (s *cloudcastServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ifNoneMatch = append(s.ifNoneMatch, r.Header.Get("If-None-Match"))
+
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"key":"/nowwaveradio/the-newer-new-wave-show-june-28-2025/","name":%q,"description":"tracks"}`, s.name)
+}
+
+func newCacheTestClient(t *testing.T, server *cloudcastServer, cache ShowCache) *Client {
+	t.Helper()
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	client := &Client{baseURL: httpServer.URL}
+	if cache != nil {
+		client.SetShowCache(cache)
+	}
+	return client
+}
+
+func TestGetShowConditionalRequest(t *testing.T) {
+	server := &cloudcastServer{etag: `"v1"`, name: "First Name"}
+	runState, err := state.Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newCacheTestClient(t, server, runState)
+
+	first, err := client.GetShow(cacheTestShowURL)
+	if err != nil {
+		t.Fatalf("first GetShow() error = %v", err)
+	}
+	second, err := client.GetShow(cacheTestShowURL)
+	if err != nil {
+		t.Fatalf("second GetShow() error = %v", err)
+	}
+
+	if server.ifNoneMatch[0] != "" || server.ifNoneMatch[1] != `"v1"` {
+		t.Errorf("If-None-Match headers = %q, want none then \"v1\"", server.ifNoneMatch)
+	}
+	if server.notModified != 1 {
+		t.Errorf("server answered %d requests with 304, want 1", server.notModified)
+	}
+	if second.Name != first.Name || second.Description != "tracks" || second.URL != cacheTestShowURL {
+		t.Errorf("cached show = %+v, want copy of %+v", second, first)
+	}
+}
+
+func TestGetShowValidatorMismatch(t *testing.T) {
+	server := &cloudcastServer{etag: `"v2"`, name: "Renamed Show"}
+	runState, err := state.Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const cloudcastKey = "nowwaveradio/the-newer-new-wave-show-june-28-2025/"
+	runState.StoreCachedShow(cloudcastKey, state.CachedShow{
+		ETag: `"v1"`,
+		Body: []byte(`{"key":"/nowwaveradio/the-newer-new-wave-show-june-28-2025/","name":"Old Name"}`),
+	})
+	client := newCacheTestClient(t, server, runState)
+
+	show, err := client.GetShow(cacheTestShowURL)
+	if err != nil {
+		t.Fatalf("GetShow() error = %v", err)
+	}
+	if server.ifNoneMatch[0] != `"v1"` {
+		t.Errorf("If-None-Match = %q, want the stale \"v1\"", server.ifNoneMatch[0])
+	}
+	if show.Name != "Renamed Show" {
+		t.Errorf("Name = %q, want the fresh response", show.Name)
+	}
+	if cached, _ := runState.CachedShow(cloudcastKey); cached.ETag != `"v2"` {
+		t.Errorf("cached ETag = %q, want it replaced by \"v2\"", cached.ETag)
+	}
+}
+
+func TestGetShowCacheFileAbsent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	runState, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Load() of missing state file error = %v", err)
+	}
+	server := &cloudcastServer{etag: `"v1"`, name: "Show"}
+	client := newCacheTestClient(t, server, runState)
+
+	if _, err := client.GetShow(cacheTestShowURL); err != nil {
+		t.Fatalf("GetShow() error = %v", err)
+	}
+	if server.ifNoneMatch[0] != "" {
+		t.Errorf("first request should be unconditional, got If-None-Match %q", server.ifNoneMatch[0])
+	}
+	if err := runState.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("state file not written: %v", err)
+	}
+
+	// A fresh run reading the new file revalidates
+	reloaded, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = newCacheTestClient(t, server, reloaded)
+	if _, err := client.GetShow(cacheTestShowURL); err != nil {
+		t.Fatalf("GetShow() after reload error = %v", err)
+	}
+	if server.notModified != 1 {
+		t.Errorf("reloaded cache should produce a 304, got %d", server.notModified)
+	}
+}
+
+func TestGetShowWithoutCache(t *testing.T) {
+	server := &cloudcastServer{etag: `"v1"`, name: "Show"}
+	client := newCacheTestClient(t, server, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetShow(cacheTestShowURL); err != nil {
+			t.Fatalf("GetShow() error = %v", err)
+		}
+	}
+	if server.ifNoneMatch[0] != "" || server.ifNoneMatch[1] != "" {
+		t.Errorf("requests without a cache must be unconditional, got %q", server.ifNoneMatch)
+	}
+}
@@ -4,20 +4,20 @@
 package mixcloud
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
-	"math/rand"
-	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -28,7 +28,9 @@ import (
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
 	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/httpclient"
 	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textcut"
 )
 
 // AIDEV-NOTE: Compile regex patterns and transformers once at package level for better performance
@@ -51,11 +53,27 @@ var (
 		runes.Remove(runes.In(unicode.Mn)),    // Remove combining marks (accents)
 		norm.NFC,                              // Recompose remaining characters
 	)
+
+	// windowsPunctuationReplacer maps the typographic punctuation Windows playout
+	// software and word processors produce to the ASCII the slug replacer handles
+	// AIDEV-NOTE: These aren't combining marks, so normalizeUnicode would drop them
+	// and close the gap they stood for: "Rock—Roll" must slug as rock-roll
+	windowsPunctuationReplacer = strings.NewReplacer(
+		"\u2018", "'",  // Left single quote ‘
+		"\u2019", "'",  // Right single quote ’
+		"\u201a", "'",  // Single low quote ‚
+		"\u201c", `"`,  // Left double quote “
+		"\u201d", `"`,  // Right double quote ”
+		"\u201e", `"`,  // Double low quote „
+		"\u2013", "-",  // En dash –
+		"\u2014", "-",  // Em dash —
+		"\u2026", "",   // Ellipsis …
+	)
 )
 
 // AIDEV-TODO: Add GetShow method for fetching show information
 // AIDEV-TODO: Add UpdateShowDescription method with multipart form handling  
-// AIDEV-NOTE: Mixcloud API has rate limiting - implement exponential backoff
+// AIDEV-NOTE: Mixcloud API has rate limiting - retries and backoff are defined in retry.go
 
 // API endpoint constants for Mixcloud API
 const (
@@ -64,8 +82,6 @@ const (
 	UploadEndpoint         = "/upload/"                          // POST /upload/
 	APITimeoutSeconds      = 30                                  // 30 second timeout for API requests
 	MaxDescriptionLength   = constants.MixcloudDescriptionLimit   // Maximum description length
-	RateLimitMaxRetries    = 5                                   // Maximum retries for rate limiting
-	RateLimitBaseDelay     = 1 * time.Second                    // Base delay for exponential backoff
 )
 
 // Custom error types for OAuth and API failures
@@ -80,6 +96,8 @@ var (
 	ErrShowNotFound        = errors.New("show not found on Mixcloud")
 	ErrDescriptionTooLong  = errors.New("description exceeds maximum length")
 	ErrAPIRequestFailed    = errors.New("Mixcloud API request failed")
+	ErrServerError         = errors.New("Mixcloud server error")
+	ErrTimeout             = errors.New("Mixcloud request timed out") // Alongside ErrNetworkFailure; a write may still have been applied
 )
 
 // OAuthError represents an OAuth-specific error with additional context
@@ -110,14 +128,13 @@ type Client struct {
 	config       *config.Config    // Original config for token updates
 	configPath   string            // Path to config file for saving updates
 	tokenSource  oauth2.TokenSource // TokenSource for monitoring token changes
-}
-
-// Show represents a Mixcloud show/cloudcast
-type Show struct {
-	Key         string `json:"key"`         // Cloudcast key (username/slug format)
-	Name        string `json:"name"`        // Show title
-	Description string `json:"description"` // Current description text
-	URL         string `json:"url"`         // Full URL to the show
+	baseURL      string            // API root, MixcloudAPIBaseURL unless overridden in tests
+	showCache    ShowCache         // Optional GetShow response cache, see SetShowCache
+	timeout      time.Duration     // Per-request timeout, see newHTTPClient
+	transport    *http.Transport   // Connection pool shared by every client built here, see sharedTransport
+	plainHTTP    *http.Client      // Persistent client without the OAuth transport, see plainClient
+	connOnce     sync.Once         // Builds transport and plainHTTP on first use
+	rateLimit    rateLimitStats    // Quota from the latest responses, see Stats
 }
 
 // tokenRefreshTransport wraps an OAuth2 transport to intercept token refresh events
@@ -178,48 +195,37 @@ func (t *tokenRefreshTransport) RoundTrip(req *http.Request) (*http.Response, er
 	return resp, err
 }
 
-// executeWithRetry executes the request with exponential backoff retry logic
-// AIDEV-NOTE: Implements retry for transient network failures and rate limiting
+// executeWithRetry executes the request, retrying transient network failures and
+// 429 responses with RetryPolicy
+// AIDEV-NOTE: Once attempts run out the last 429 response is returned as is
 func (t *tokenRefreshTransport) executeWithRetry(req *http.Request) (*http.Response, error) {
-	maxRetries := 3
-	baseDelay := 1 * time.Second
+	policy := RetryPolicy(t.client.config)
+	policy.Retryable = func(err error) bool {
+		return errors.Is(err, ErrRateLimited) || t.shouldRetryError(err)
+	}
+	policy.OnRetry = func(attempt int, delay time.Duration, err error) {
+		log.Printf("[MIXCLOUD] Request failed (attempt %d/%d), retrying in %v: %v",
+			attempt, policy.Attempts(), delay, err)
+	}
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		resp, err := t.base.RoundTrip(req)
-		
-		// Check if we should retry this error
-		if err != nil && attempt < maxRetries {
-			if t.shouldRetryError(err) {
-				delay := time.Duration(1<<uint(attempt)) * baseDelay // Exponential backoff
-				log.Printf("[MIXCLOUD] Request failed (attempt %d/%d), retrying in %v: %v", 
-					attempt+1, maxRetries+1, delay, err)
-				time.Sleep(delay)
-				continue
-			}
+	var resp *http.Response
+	err := policy.Do(req.Context(), func() error {
+		if resp != nil {
+			resp.Body.Close() // Important: close the previous 429 body before retrying
 		}
-
-		// Check HTTP response status for retry conditions
-		if resp != nil && attempt < maxRetries {
-			if resp.StatusCode == 429 { // Rate limited
-				delay := time.Duration(1<<uint(attempt)) * baseDelay
-				log.Printf("[MIXCLOUD] Rate limited (attempt %d/%d), retrying in %v", 
-					attempt+1, maxRetries+1, delay)
-				resp.Body.Close() // Important: close the body before retrying
-				time.Sleep(delay)
-				continue
-			}
+		var err error
+		resp, err = t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			return newTooManyRequestsError(resp)
 		}
+		return err
+	})
 
-		// Return the result (success or final failure)
-		return resp, err
-	}
-
-	// This should never be reached due to the loop logic
-	return nil, &OAuthError{
-		Type:      "RetryExhausted",
-		Message:   "maximum retry attempts exceeded",
-		Retryable: false,
+	var tooMany *tooManyRequestsError
+	if errors.As(err, &tooMany) {
+		return tooMany.resp, nil
 	}
+	return resp, err
 }
 
 // shouldRetryError determines if an error is worth retrying
@@ -338,7 +344,16 @@ func NewClient(cfg *config.Config, configPath string) (*Client, error) {
 		username:     cfg.Station.MixcloudUsername,
 		config:       cfg,
 		configPath:   configPath,
+		timeout:      requestTimeout(cfg),
+		rateLimit:    rateLimitStats{warnBelow: cfg.Processing.RateLimitWarnRemaining},
+	}
+
+	// Every request, token refresh included, goes through the configured proxy
+	transport, err := httpclient.Transport(cfg.Network.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("network.proxy_url: %w", err)
 	}
+	client.transport = transport
 
 	// Set up httpClient with OAuth transport for automatic token refresh
 	if token != nil {
@@ -347,18 +362,69 @@ func NewClient(cfg *config.Config, configPath string) (*Client, error) {
 		if err != nil {
 			// Continue with degraded functionality - log warning and use basic client
 			log.Printf("[MIXCLOUD] Warning: Failed to create OAuth HTTP client, continuing with basic client: %v", err)
-			client.httpClient = &http.Client{}
+			client.httpClient = client.newHTTPClient(nil)
 		}
 	} else {
 		// No token available - use default HTTP client
 		// AIDEV-NOTE: API calls will fail until token is set via SaveToken()
 		log.Printf("[MIXCLOUD] No OAuth token available - client will require manual authentication")
-		client.httpClient = &http.Client{}
+		client.httpClient = client.newHTTPClient(nil)
 	}
 
 	return client, nil
 }
 
+// requestTimeout returns the configured per-request timeout, APITimeoutSeconds if unset
+func requestTimeout(cfg *config.Config) time.Duration {
+	if cfg.Processing.HTTPTimeoutSeconds > 0 {
+		return time.Duration(cfg.Processing.HTTPTimeoutSeconds) * time.Second
+	}
+	return APITimeoutSeconds * time.Second
+}
+
+// newHTTPClient returns an HTTP client that gives up on a request after the
+// client's timeout. A nil transport uses the shared connection pool and records
+// the rate-limit headers of its responses.
+// AIDEV-NOTE: Every client this package builds must come from here - a request
+// without a timeout can hang on a stalled TLS connection for hours.
+func (c *Client) newHTTPClient(transport http.RoundTripper) *http.Client {
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = APITimeoutSeconds * time.Second
+	}
+	if transport == nil {
+		transport = &rateLimitTransport{base: c.sharedTransport(), stats: &c.rateLimit}
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// AIDEV-NOTE: GetShow (public) and UpdateShow (token in the access_token query
+// parameter) don't need the OAuth transport, but they must not build a client per
+// call either: each fresh client paid a new TCP+TLS handshake, seconds per show on
+// a slow uplink. Both use plainClient, and every client - OAuth and token refresh
+// included - dials through sharedTransport, so keep-alive connections are reused
+// across a whole batch.
+
+// sharedTransport returns the connection pool shared by the client's requests
+func (c *Client) sharedTransport() *http.Transport {
+	c.connOnce.Do(c.initConnections)
+	return c.transport
+}
+
+// plainClient returns the persistent client for requests without the OAuth transport
+func (c *Client) plainClient() *http.Client {
+	c.connOnce.Do(c.initConnections)
+	return c.plainHTTP
+}
+
+// initConnections builds the shared transport, unless one was set, and the plain client
+func (c *Client) initConnections() {
+	if c.transport == nil {
+		c.transport, _ = httpclient.Transport("") // Environment proxy only, can't fail
+	}
+	c.plainHTTP = c.newHTTPClient(&rateLimitTransport{base: c.transport, stats: &c.rateLimit})
+}
+
 // LoadToken reads the current OAuth token from the stored configuration
 // AIDEV-NOTE: Tokens are already loaded in NewClient, this provides access to current token
 func (c *Client) LoadToken() *oauth2.Token {
@@ -397,7 +463,7 @@ func (c *Client) SaveToken(token *oauth2.Token) error {
 	if err != nil {
 		// Continue with degraded functionality (no automatic refresh)
 		log.Printf("[MIXCLOUD] Warning: Failed to recreate HTTP client, continuing with basic client: %v", err)
-		c.httpClient = &http.Client{}
+		c.httpClient = c.newHTTPClient(nil)
 	}
 
 	// Save the updated config to file if config path is available
@@ -418,6 +484,22 @@ func (c *Client) SaveToken(token *oauth2.Token) error {
 	return nil
 }
 
+// ClearToken forgets the access token once Mixcloud has rejected it for good:
+// later updates fail fast instead of sending it again, and the config file or
+// credential store no longer holds it (see config.ClearStoredToken), so the next
+// run asks for authorization
+func (c *Client) ClearToken() error {
+	c.token = nil
+	if c.config == nil {
+		return nil
+	}
+	if c.configPath == "" {
+		c.config.OAuth.AccessToken, c.config.OAuth.RefreshToken = "", ""
+		return nil
+	}
+	return c.config.ClearStoredToken(c.configPath)
+}
+
 // saveTokenToFile persists a token to the config file without recreating the HTTP client
 // AIDEV-NOTE: Used internally by token refresh transport to avoid recursion
 func (c *Client) saveTokenToFile(token *oauth2.Token) error {
@@ -473,12 +555,15 @@ func (c *Client) recreateHTTPClient(token *oauth2.Token) error {
 		}
 	}
 
+	// Token refreshes go through a client with a timeout too
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, c.newHTTPClient(nil))
+
 	// Create TokenSource for automatic token refresh
-	tokenSource := c.oauth2Config.TokenSource(oauth2.NoContext, token)
+	tokenSource := c.oauth2Config.TokenSource(ctx, token)
 	c.tokenSource = tokenSource
 	
 	// Create OAuth HTTP client with custom transport for token refresh monitoring
-	oauthClient := oauth2.NewClient(oauth2.NoContext, tokenSource)
+	oauthClient := oauth2.NewClient(ctx, tokenSource)
 	
 	// Wrap the OAuth transport with our custom transport for token persistence
 	customTransport := &tokenRefreshTransport{
@@ -488,9 +573,7 @@ func (c *Client) recreateHTTPClient(token *oauth2.Token) error {
 	}
 	
 	// Create HTTP client with custom transport
-	c.httpClient = &http.Client{
-		Transport: customTransport,
-	}
+	c.httpClient = c.newHTTPClient(customTransport)
 
 	return nil
 }
@@ -643,7 +726,10 @@ func GenerateShowURL(username, showName string) string {
 	// Convert to lowercase for URL compatibility
 	slug := strings.ToLower(showName)
 
-	// Normalize Unicode characters first (accents, non-Latin scripts)
+	// Map Windows punctuation before normalizeUnicode drops it
+	slug = windowsPunctuationReplacer.Replace(slug)
+
+	// Normalize Unicode characters (accents, non-Latin scripts)
 	// AIDEV-NOTE: Handle accented characters like café→cafe, señor→senor
 	slug = normalizeUnicode(slug)
 
@@ -751,13 +837,22 @@ func extractCloudcastKey(showURL string) (string, error) {
 	return fmt.Sprintf("%s/%s/", username, slug), nil
 }
 
-// executeAPIRequestWithRetry performs an HTTP request with exponential backoff retry logic for rate limiting
-// AIDEV-NOTE: Implements sophisticated retry logic with jitter and Retry-After header support
+// executeAPIRequestWithRetry performs an HTTP request, retrying network errors and
+// 429 responses with RetryPolicy
+// AIDEV-NOTE: Honors Retry-After; once attempts run out the last 429 response is
+// returned so the caller can handle the status
 func (c *Client) executeAPIRequestWithRetry(req *http.Request) (*http.Response, error) {
-	maxRetries := RateLimitMaxRetries
-	baseDelay := RateLimitBaseDelay
+	policy := RetryPolicy(c.config)
+	policy.OnRetry = func(attempt int, delay time.Duration, err error) {
+		log.Printf("[MIXCLOUD] Request failed (attempt %d/%d), retrying in %v: %v",
+			attempt, policy.Attempts(), delay, err)
+	}
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
+	var resp *http.Response
+	err := policy.Do(req.Context(), func() error {
+		if resp != nil {
+			resp.Body.Close()
+		}
 		// Clone the request for each attempt since the body may be consumed
 		reqClone := req.Clone(req.Context())
 		if req.Body != nil {
@@ -771,87 +866,43 @@ func (c *Client) executeAPIRequestWithRetry(req *http.Request) (*http.Response,
 			reqClone.Body = req.Body
 		}
 
-		// Make the HTTP request
-		resp, err := c.httpClient.Do(reqClone)
+		var err error
+		resp, err = c.httpClient.Do(reqClone)
 		if err != nil {
-			// Network errors should be retried
-			if attempt < maxRetries {
-				delay := c.calculateRetryDelay(attempt, baseDelay, 0)
-				log.Printf("[MIXCLOUD] Network error (attempt %d/%d), retrying in %v: %v", 
-					attempt+1, maxRetries+1, delay, err)
-				time.Sleep(delay)
-				continue
-			}
-			return nil, fmt.Errorf("%w: HTTP request failed after %d retries: %v", ErrNetworkFailure, maxRetries+1, err)
+			resp = nil
+			return err
 		}
-
-		// Check if this is a rate limiting response
 		if resp.StatusCode == http.StatusTooManyRequests {
-			if attempt < maxRetries {
-				// Parse Retry-After header if present
-				retryAfterSeconds := c.parseRetryAfterHeader(resp.Header.Get("Retry-After"))
-				delay := c.calculateRetryDelay(attempt, baseDelay, retryAfterSeconds)
-				
-				log.Printf("[MIXCLOUD] Rate limited (attempt %d/%d), retrying in %v", 
-					attempt+1, maxRetries+1, delay)
-				
-				// Close the response body before retrying
-				resp.Body.Close()
-				time.Sleep(delay)
-				continue
-			} else {
-				// Max retries exceeded for rate limiting
-				return resp, nil // Return the response so caller can handle the 429 status
-			}
+			return newTooManyRequestsError(resp)
 		}
+		return nil
+	})
 
-		// For other status codes (success or non-retryable errors), return immediately
-		return resp, nil
-	}
-
-	// This should never be reached due to the loop logic
-	return nil, fmt.Errorf("%w: maximum retry attempts exceeded", ErrRateLimited)
-}
-
-// calculateRetryDelay calculates the delay for the next retry attempt
-// AIDEV-NOTE: Implements exponential backoff with jitter and Retry-After header support
-func (c *Client) calculateRetryDelay(attempt int, baseDelay time.Duration, retryAfterSeconds int) time.Duration {
-	// If server provided Retry-After header, respect it
-	if retryAfterSeconds > 0 {
-		serverDelay := time.Duration(retryAfterSeconds) * time.Second
-		// Add small jitter to prevent thundering herd
-		jitter := time.Duration(rand.Intn(1000)) * time.Millisecond
-		return serverDelay + jitter
-	}
-
-	// Calculate exponential backoff: baseDelay * 2^attempt
-	exponentialDelay := baseDelay * time.Duration(1<<uint(attempt))
-	
-	// Cap the delay at 30 seconds to avoid excessive waiting
-	const maxDelay = 30 * time.Second
-	if exponentialDelay > maxDelay {
-		exponentialDelay = maxDelay
+	var tooMany *tooManyRequestsError
+	if errors.As(err, &tooMany) {
+		return tooMany.resp, nil
 	}
-
-	// Add jitter to prevent thundering herd (±25% of the delay)
-	jitterRange := int(exponentialDelay.Milliseconds() / 4) // 25% of delay
-	if jitterRange > 0 {
-		jitter := time.Duration(rand.Intn(jitterRange*2)-jitterRange) * time.Millisecond
-		exponentialDelay += jitter
+	if err != nil {
+		return nil, fmt.Errorf("%w: HTTP request failed: %v", ErrNetworkFailure, err)
 	}
+	return resp, nil
+}
 
-	// Ensure minimum delay of 100ms
-	const minDelay = 100 * time.Millisecond
-	if exponentialDelay < minDelay {
-		exponentialDelay = minDelay
+// requestError wraps an HTTP request that got no response in ErrNetworkFailure,
+// and also in ErrTimeout when the response didn't arrive in time
+// AIDEV-NOTE: A timed-out POST may have reached Mixcloud and been applied; only the
+// answer was lost. Callers check ErrTimeout before blindly sending the write again.
+func requestError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w: HTTP request failed: %w", ErrNetworkFailure, ErrTimeout, err)
 	}
-
-	return exponentialDelay
+	return fmt.Errorf("%w: HTTP request failed: %w", ErrNetworkFailure, err)
 }
 
 // parseRetryAfterHeader parses the Retry-After header value
 // AIDEV-NOTE: Supports both delay-seconds and HTTP-date formats
-func (c *Client) parseRetryAfterHeader(retryAfter string) int {
+func parseRetryAfterHeader(retryAfter string) int {
 	if retryAfter == "" {
 		return 0
 	}
@@ -883,8 +934,13 @@ func (c *Client) parseRetryAfterHeader(retryAfter string) int {
 }
 
 // GetShow fetches show information from the Mixcloud API
-// AIDEV-NOTE: Implements GET /cloudcast/<key>/ endpoint with proper error handling
 func (c *Client) GetShow(showURL string) (*Show, error) {
+	return c.GetShowContext(context.Background(), showURL)
+}
+
+// GetShowContext is GetShow with a context that can cancel the request
+// AIDEV-NOTE: Implements GET /cloudcast/<key>/ endpoint with proper error handling
+func (c *Client) GetShowContext(ctx context.Context, showURL string) (*Show, error) {
 	log := logger.Get()
 	startTime := time.Now()
 	
@@ -905,15 +961,15 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 
 	// Construct the API endpoint URL
 	endpoint := fmt.Sprintf(CloudcastEndpoint, cloudcastKey)
-	apiURL := MixcloudAPIBaseURL + endpoint
+	apiURL := c.apiBaseURL() + endpoint
 
 	log.Info("Making Mixcloud API request", 
 		slog.String("api_url", apiURL),
 		slog.String("method", "GET"),
 		slog.Bool("authenticated", false))
 
-	// Create HTTP request with timeout
-	req, err := http.NewRequest("GET", apiURL, nil)
+	// Create HTTP request, cancelled with ctx
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		log.Error("Failed to create HTTP request", 
 			slog.String("error", err.Error()))
@@ -923,16 +979,16 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 	// Set appropriate headers
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "mixcloud-updater/1.0")
+	cached, haveCached := c.addCacheValidators(req, cloudcastKey)
 
-	// Make the API request - try unauthenticated first for public shows
-	basicClient := &http.Client{}
-	resp, err := basicClient.Do(req)
+	// Make the API request - unauthenticated, shows are public
+	resp, err := c.plainClient().Do(req)
 	if err != nil {
 		log.Error("Mixcloud API request failed", 
 			slog.String("api_url", apiURL),
 			slog.String("error", err.Error()),
 			slog.Duration("duration", time.Since(startTime)))
-		return nil, fmt.Errorf("%w: HTTP request failed: %v", ErrNetworkFailure, err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
@@ -942,9 +998,19 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 		slog.Duration("duration", time.Since(startTime)))
 
 	// Handle different HTTP status codes
+	var body []byte
 	switch resp.StatusCode {
 	case http.StatusOK:
 		log.Debug("API request successful")
+	case http.StatusNotModified:
+		if !haveCached {
+			log.Error("Unexpected 304 Not Modified without a cached response")
+			return nil, fmt.Errorf("%w: unexpected 304 Not Modified for uncached show", ErrAPIRequestFailed)
+		}
+		log.Info("Show unchanged, using cached response",
+			slog.String("cloudcast_key", cloudcastKey),
+			slog.Time("fetched_at", cached.FetchedAt))
+		body = cached.Body
 	case http.StatusNotFound:
 		log.Error("Show not found on Mixcloud", 
 			slog.String("show_url", showURL),
@@ -958,10 +1024,10 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 		log.Error("API rate limit exceeded", 
 			slog.Int("status_code", resp.StatusCode))
 		return nil, fmt.Errorf("%w: API rate limit exceeded after retries", ErrRateLimited)
-	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
 		log.Error("Mixcloud server error", 
 			slog.Int("status_code", resp.StatusCode))
-		return nil, fmt.Errorf("%w: server error (status %d)", ErrAPIRequestFailed, resp.StatusCode)
+		return nil, fmt.Errorf("%w: %w (status %d)", ErrAPIRequestFailed, ErrServerError, resp.StatusCode)
 	default:
 		log.Error("Unexpected API response status", 
 			slog.Int("status_code", resp.StatusCode))
@@ -969,11 +1035,13 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 	}
 
 	// Read the response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Error("Failed to read API response body", 
-			slog.String("error", err.Error()))
-		return nil, fmt.Errorf("%w: failed to read response body: %v", ErrAPIRequestFailed, err)
+	if body == nil {
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			log.Error("Failed to read API response body", 
+				slog.String("error", err.Error()))
+			return nil, fmt.Errorf("%w: failed to read response body: %v", ErrAPIRequestFailed, err)
+		}
 	}
 
 	log.Debug("API response body read", 
@@ -982,13 +1050,9 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 	// Parse JSON response into Show struct
 	var show Show
 	if err := json.Unmarshal(body, &show); err != nil {
-		previewLen := len(body)
-		if previewLen > 200 {
-			previewLen = 200
-		}
 		log.Error("Failed to parse API response JSON", 
 			slog.String("error", err.Error()),
-			slog.String("response_preview", string(body[:previewLen])))
+			slog.String("response_preview", textcut.PrefixBytes(string(body), 200)))
 		return nil, fmt.Errorf("%w: failed to parse JSON response: %v", ErrAPIRequestFailed, err)
 	}
 
@@ -999,6 +1063,9 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 			slog.String("show_name", show.Name))
 		return nil, fmt.Errorf("%w: incomplete show data received from API", ErrAPIRequestFailed)
 	}
+	if resp.StatusCode == http.StatusOK {
+		c.cacheResponse(cloudcastKey, resp, body)
+	}
 
 	// Set the URL field to the original input URL for consistency
 	show.URL = showURL
@@ -1006,70 +1073,102 @@ func (c *Client) GetShow(showURL string) (*Show, error) {
 	log.Info("Successfully fetched show from Mixcloud API", 
 		slog.String("show_name", show.Name),
 		slog.String("show_key", show.Key),
+		slog.Time("created_time", show.CreatedTime),
+		slog.Int("audio_length", show.AudioLength),
+		slog.Int("tag_count", len(show.Tags)),
 		slog.Duration("total_duration", time.Since(startTime)))
 
 	return &show, nil
 }
 
+// editedShowURL returns the show URL from an edit response such as
+// {"result": {"success": true, "key": "/station/new-slug/"}}, or "" without a key
+func editedShowURL(body []byte) string {
+	var response struct {
+		Result struct {
+			Key string `json:"key"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	key := strings.Trim(response.Result.Key, "/")
+	if key == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://www.mixcloud.com/%s/", key)
+}
+
 // UpdateShowDescription updates the description of a Mixcloud show
-// AIDEV-NOTE: Implements POST /upload/ endpoint with multipart form data
 func (c *Client) UpdateShowDescription(showURL, description string) error {
+	return c.UpdateShow(showURL, map[string]string{"description": description})
+}
+
+// UpdateShow sends an edit request with the given form fields, e.g.
+// "description", "name" or "unlisted". Fields left out keep their current value.
+func (c *Client) UpdateShow(showURL string, fields map[string]string) error {
+	return c.UpdateShowContext(context.Background(), showURL, fields)
+}
+
+// UpdateShowContext is UpdateShow with a context that can cancel the request
+func (c *Client) UpdateShowContext(ctx context.Context, showURL string, fields map[string]string) error {
+	_, err := c.EditShowContext(ctx, showURL, fields)
+	return err
+}
+
+// EditShowContext is UpdateShowContext that also returns the show's URL as
+// reported by the edit response, which differs from showURL when a new "name"
+// changed the slug. It is "" when the response doesn't include the show key.
+// AIDEV-NOTE: Implements POST /upload/ endpoint with multipart form data
+func (c *Client) EditShowContext(ctx context.Context, showURL string, fields map[string]string) (string, error) {
 	// Extract cloudcast key from the URL
 	cloudcastKey, err := extractCloudcastKey(showURL)
 	if err != nil {
-		return fmt.Errorf("failed to parse show URL: %w", err)
+		return "", fmt.Errorf("failed to parse show URL: %w", err)
 	}
 
-	// Validate description length
-	if len(description) > MaxDescriptionLength {
-		return fmt.Errorf("%w: description length %d exceeds maximum %d characters", 
-			ErrDescriptionTooLong, len(description), MaxDescriptionLength)
+	// Validate description length, measured per length_model
+	if description, ok := fields["description"]; ok {
+		if length := c.config.DescriptionLengthModel().Length(description); length > MaxDescriptionLength {
+			return "", fmt.Errorf("%w: description length %d exceeds maximum %d characters",
+				ErrDescriptionTooLong, length, MaxDescriptionLength)
+		}
 	}
 
 	// Check if client has authentication tokens for API requests
 	// AIDEV-NOTE: Updates require authentication, unlike GetShow which works publicly
 	if c.token == nil || c.token.AccessToken == "" {
-		return fmt.Errorf("%w: access token is required for updating show descriptions", ErrAuthenticationFailed)
+		return "", fmt.Errorf("%w: access token is required for updating show descriptions", ErrAuthenticationFailed)
 	}
 
-	// Create multipart form data
-	var formBuf bytes.Buffer
-	writer := multipart.NewWriter(&formBuf)
-
-	// Add description field (cloudcast key is already in URL path, no form field needed)
-	if err := writer.WriteField("description", description); err != nil {
-		return fmt.Errorf("%w: failed to write description field: %v", ErrAPIRequestFailed, err)
-	}
-
-	// Close the multipart writer to finalize the form data
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("%w: failed to close multipart writer: %v", ErrAPIRequestFailed, err)
+	// Create multipart form data (cloudcast key is already in URL path, no form field needed)
+	formBuf, contentType, err := buildUpdateForm(fields)
+	if err != nil {
+		return "", err
 	}
 
 	// Construct the API endpoint URL for editing existing uploads
 	// According to Mixcloud API docs: /upload/[YOUR_SHOW_KEY]/edit/?access_token=...
 	// Clean cloudcastKey to avoid double slashes
 	cleanKey := strings.Trim(cloudcastKey, "/")
-	apiURL := fmt.Sprintf("%s/upload/%s/edit/?access_token=%s", MixcloudAPIBaseURL, cleanKey, c.token.AccessToken)
+	apiURL := fmt.Sprintf("%s/upload/%s/edit/?access_token=%s", c.apiBaseURL(), cleanKey, c.token.AccessToken)
 
 	// Create HTTP request with multipart form data
-	req, err := http.NewRequest("POST", apiURL, &formBuf)
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, formBuf)
 	if err != nil {
-		return fmt.Errorf("%w: failed to create request", ErrAPIRequestFailed)
+		return "", fmt.Errorf("%w: failed to create request", ErrAPIRequestFailed)
 	}
 
 	// Set appropriate headers
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "mixcloud-updater/1.0")
 
-	// Make the API request with basic HTTP client (token is in query param, not OAuth header)
-	// AIDEV-NOTE: Use basic client since we're passing access_token as query parameter
-	log.Printf("[MIXCLOUD] Updating description for show: %s", showURL)
-	basicClient := &http.Client{}
-	resp, err := basicClient.Do(req)
+	// Make the API request with the plain client (token is in query param, not OAuth header)
+	log.Printf("[MIXCLOUD] Updating show %s (fields: %d)", showURL, len(fields))
+	resp, err := c.plainClient().Do(req)
 	if err != nil {
-		return fmt.Errorf("%w: request failed: %v", ErrAPIRequestFailed, err)
+		return "", requestError(err)
 	}
 	defer resp.Body.Close()
 
@@ -1083,24 +1182,27 @@ func (c *Client) UpdateShowDescription(showURL, description string) error {
 	// Handle different HTTP status codes
 	switch resp.StatusCode {
 	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
-		// Success - verify the response contains success indication
 		log.Printf("[MIXCLOUD] Successfully updated show description")
-		return nil
+		return editedShowURL(body), nil
 	case http.StatusBadRequest:
-		return fmt.Errorf("%w: bad request - invalid cloudcast key or description format: %s", 
+		// A revoked or expired token is a 400 with an OAuthException, not a 401
+		if strings.Contains(string(body), "OAuthException") {
+			return "", fmt.Errorf("%w: access token rejected: %s", ErrAuthenticationFailed, string(body))
+		}
+		return "", fmt.Errorf("%w: bad request - invalid cloudcast key or description format: %s", 
 			ErrAPIRequestFailed, string(body))
 	case http.StatusUnauthorized:
-		return fmt.Errorf("%w: API authentication failed", ErrAuthenticationFailed)
+		return "", fmt.Errorf("%w: API authentication failed", ErrAuthenticationFailed)
 	case http.StatusForbidden:
-		return fmt.Errorf("%w: insufficient permissions to update this show", ErrAuthenticationFailed)
+		return "", fmt.Errorf("%w: insufficient permissions to update this show", ErrAuthenticationFailed)
 	case http.StatusNotFound:
-		return fmt.Errorf("%w: show not found: %s", ErrShowNotFound, showURL)
+		return "", fmt.Errorf("%w: show not found: %s", ErrShowNotFound, showURL)
 	case http.StatusTooManyRequests:
 		// This should be rare since executeAPIRequestWithRetry handles rate limiting
-		return fmt.Errorf("%w: API rate limit exceeded after retries", ErrRateLimited)
-	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
-		return fmt.Errorf("%w: server error (status %d): %s", ErrAPIRequestFailed, resp.StatusCode, string(body))
+		return "", fmt.Errorf("%w: API rate limit exceeded after retries", ErrRateLimited)
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return "", fmt.Errorf("%w: %w (status %d): %s", ErrAPIRequestFailed, ErrServerError, resp.StatusCode, string(body))
 	default:
-		return fmt.Errorf("%w: unexpected status code %d: %s", ErrAPIRequestFailed, resp.StatusCode, string(body))
+		return "", fmt.Errorf("%w: unexpected status code %d: %s", ErrAPIRequestFailed, resp.StatusCode, string(body))
 	}
 }
\ No newline at end of file
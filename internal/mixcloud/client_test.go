@@ -0,0 +1,36 @@
+package mixcloud
+
+import "testing"
+
+func TestGenerateShowURL(t *testing.T) {
+	// Titles as the playout exports them. When a title's slug is checked against
+	// the URL Mixcloud gave the upload, add it here with that slug.
+	tests := []struct {
+		name     string
+		showName string
+		wantSlug string
+	}{
+		{"plain", "The Newer New Wave Show", "the-newer-new-wave-show"},
+		{"spaced hyphen and date", "Myriad - 6/28/2025", "myriad-6282025"},
+		{"accents", "Música Nueva", "musica-nueva"},
+		{"straight apostrophe", "Don't Stop", "dont-stop"},
+		{"curly apostrophe", "Don’t Stop", "dont-stop"},
+		{"left single quote", "‘Round Midnight", "round-midnight"},
+		{"curly double quotes", "“Live” at Ronnie’s", "live-at-ronnies"},
+		{"low double quote", "„Kraut“ Hour", "kraut-hour"},
+		{"spaced en dash", "Myriad – 6/28/2025", "myriad-6282025"},
+		{"spaced em dash", "Late Night — Deep Cuts", "late-night-deep-cuts"},
+		{"unspaced em dash", "Rock—Roll Hour", "rock-roll-hour"},
+		{"ellipsis", "Wait… What", "wait-what"},
+		{"trailing ellipsis", "And Then…", "and-then"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := "https://www.mixcloud.com/station/" + tt.wantSlug + "/"
+			if got := GenerateShowURL("station", tt.showName); got != want {
+				t.Errorf("GenerateShowURL(%q) = %q, want %q", tt.showName, got, want)
+			}
+		})
+	}
+}
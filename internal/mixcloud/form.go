@@ -0,0 +1,39 @@
+package mixcloud
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"sort"
+)
+
+// buildUpdateForm encodes the fields of an edit request as multipart form data.
+// Fields are written in name order so requests are reproducible.
+func buildUpdateForm(fields map[string]string) (*bytes.Buffer, string, error) {
+	if len(fields) == 0 {
+		return nil, "", fmt.Errorf("%w: no fields to update", ErrAPIRequestFailed)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if name == "" {
+			return nil, "", fmt.Errorf("%w: empty form field name", ErrAPIRequestFailed)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var formBuf bytes.Buffer
+	writer := multipart.NewWriter(&formBuf)
+	for _, name := range names {
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			return nil, "", fmt.Errorf("%w: failed to write %s field: %v", ErrAPIRequestFailed, name, err)
+		}
+	}
+
+	// Close the multipart writer to finalize the form data
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("%w: failed to close multipart writer: %v", ErrAPIRequestFailed, err)
+	}
+	return &formBuf, writer.FormDataContentType(), nil
+}
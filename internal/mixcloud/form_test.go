@@ -0,0 +1,90 @@
+package mixcloud
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestBuildUpdateForm(t *testing.T) {
+	fields := map[string]string{
+		"unlisted":    "1",
+		"description": "Line one\nLine two – ünïcode",
+		"name":        "The Show - June 28, 2025",
+	}
+
+	body, contentType, err := buildUpdateForm(fields)
+	if err != nil {
+		t.Fatalf("buildUpdateForm() error = %v", err)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("parsing content type %q: %v", contentType, err)
+	}
+	reader := multipart.NewReader(body, params["boundary"])
+
+	var names []string
+	got := make(map[string]string)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading part: %v", err)
+		}
+		value, _ := io.ReadAll(part)
+		names = append(names, part.FormName())
+		got[part.FormName()] = string(value)
+	}
+
+	if want := []string{"description", "name", "unlisted"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("field order = %v, want %v", names, want)
+	}
+	if !reflect.DeepEqual(got, fields) {
+		t.Errorf("fields = %v, want %v", got, fields)
+	}
+}
+
+func TestBuildUpdateFormErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]string
+	}{
+		{"no fields", nil},
+		{"empty field name", map[string]string{"": "x", "description": "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := buildUpdateForm(tt.fields)
+			if !errors.Is(err, ErrAPIRequestFailed) {
+				t.Errorf("buildUpdateForm() error = %v, want ErrAPIRequestFailed", err)
+			}
+		})
+	}
+}
+
+func TestEditedShowURL(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"renamed", `{"result": {"success": true, "key": "/station/new-name/"}}`, "https://www.mixcloud.com/station/new-name/"},
+		{"no key", `{"result": {"success": true}}`, ""},
+		{"not JSON", `OK`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editedShowURL([]byte(tt.body)); got != tt.want {
+				t.Errorf("editedShowURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
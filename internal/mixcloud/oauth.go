@@ -2,16 +2,24 @@ package mixcloud
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"log/slog"
+	"net"
 	"net/http"
 	"os/exec"
 	"runtime"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/httpclient"
 	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
 )
 
@@ -22,9 +30,35 @@ const (
 	// OAuth callback configuration
 	DefaultCallbackPort = 8080
 	CallbackPath        = "/oauth/callback"
+	StatusPath          = "/status"
 	CallbackTimeout     = 5 * time.Minute
+	// MaxExchangeAttempts is how many authorizations a flow accepts before a
+	// failing code exchange ends it
+	MaxExchangeAttempts = 3
 )
 
+// AIDEV-NOTE: The callback answers at once with a status page and exchanges the
+// code in the background, so a slow token endpoint shows progress instead of a
+// browser spinner. The page polls StatusPath. Authorization codes are single
+// use, so after a failed exchange the flow keeps listening and the user retries
+// by authorizing again; the 5-minute wait restarts with each attempt.
+
+// Phases of the flow reported by the status endpoint
+const (
+	phaseWaiting    = "waiting"    // No authorization code received yet
+	phaseExchanging = "exchanging" // Exchanging the code for a token
+	phaseFailed     = "failed"     // The last exchange failed
+	phaseDone       = "done"       // Token received
+)
+
+// flowStatus is what the status endpoint reports to the browser
+type flowStatus struct {
+	Phase   string `json:"phase"`
+	Attempt int    `json:"attempt"`
+	Error   string `json:"error,omitempty"`
+	AuthURL string `json:"auth_url,omitempty"` // Where to authorize again after a failed exchange, "" once attempts run out
+}
+
 // OAuthFlow handles the complete OAuth authorization flow for Mixcloud
 type OAuthFlow struct {
 	config     *oauth2.Config
@@ -33,6 +67,13 @@ type OAuthFlow struct {
 	server      *http.Server
 	resultChan  chan *oauth2.Token
 	errorChan   chan error
+	exchangeFailed chan error // A failed code exchange the user can retry
+	proxyURL    string // network.proxy_url for the code exchange, "" for the environment proxy
+	exchangeTimeout time.Duration // Limit for the code exchange, oauth.exchange_timeout_seconds
+	openURL     func(url string) error // Opens the authorization URL, the default browser outside tests
+
+	mu     sync.Mutex
+	status flowStatus
 }
 
 // NewOAuthFlow creates a new OAuth flow handler
@@ -54,13 +95,18 @@ func NewOAuthFlow(clientID, clientSecret string, callbackPort int) *OAuthFlow {
 		Scopes:      []string{}, // Mixcloud doesn't require specific scopes
 	}
 
-	return &OAuthFlow{
+	flow := &OAuthFlow{
 		config:      oauth2Config,
 		callbackPort: callbackPort,
 		redirectURI:  redirectURI,
 		resultChan:  make(chan *oauth2.Token, 1),
 		errorChan:   make(chan error, 1),
+		exchangeFailed: make(chan error, 1),
+		exchangeTimeout: constants.DefaultExchangeTimeoutSeconds * time.Second,
+		status:      flowStatus{Phase: phaseWaiting, Attempt: 1},
 	}
+	flow.openURL = flow.openBrowser
+	return flow
 }
 
 // Authorize initiates the OAuth flow and returns the access token
@@ -82,63 +128,92 @@ func (o *OAuthFlow) Authorize(ctx context.Context) (*oauth2.Token, error) {
 	// Generate the authorization URL using Mixcloud's simple OAuth flow
 	// Mixcloud doesn't support Google-style OAuth parameters like access_type=offline
 	authURL := o.config.AuthCodeURL("state")
+	o.mu.Lock()
+	o.status.AuthURL = authURL
+	o.mu.Unlock()
 	
 	log.Info("Generated OAuth authorization URL", 
 		slog.String("url", authURL))
 
 	// Open the browser to the authorization URL
-	fmt.Printf("Opening browser for Mixcloud authorization...\n")
-	fmt.Printf("If the browser doesn't open automatically, visit this URL:\n")
-	fmt.Printf("%s\n\n", authURL)
+	console.Printf("Opening browser for Mixcloud authorization...\n")
+	console.Printf("If the browser doesn't open automatically, visit this URL:\n")
+	console.Printf("%s\n\n", authURL)
 
-	if err := o.openBrowser(authURL); err != nil {
+	if err := o.openURL(authURL); err != nil {
 		log.Warn("Failed to open browser automatically", 
 			slog.String("error", err.Error()),
 			slog.String("os", runtime.GOOS))
-		fmt.Printf("Please manually open the URL above in your browser.\n\n")
+		console.Errorf("Could not open a browser; open this URL to authorize:\n%s\n\n", authURL)
 	} else {
 		log.Debug("Browser opened successfully")
 	}
 
 	// Wait for the OAuth callback or timeout
-	fmt.Printf("Waiting for authorization (timeout: %v)...\n", CallbackTimeout)
+	console.Printf("Waiting for authorization (timeout: %v)...\n", CallbackTimeout)
 	
 	log.Info("Waiting for OAuth callback", 
 		slog.Duration("timeout", CallbackTimeout))
 
-	timeoutCtx, cancel := context.WithTimeout(ctx, CallbackTimeout)
-	defer cancel()
-
-	select {
-	case token := <-o.resultChan:
-		o.shutdown()
-		log.Info("OAuth authorization successful", 
-			slog.Bool("has_access_token", token.AccessToken != ""),
-			slog.Time("expires", token.Expiry))
-		fmt.Printf("✓ Authorization successful!\n")
-		return token, nil
-
-	case err := <-o.errorChan:
-		o.shutdown()
-		log.Error("OAuth flow failed", slog.String("error", err.Error()))
-		return nil, fmt.Errorf("OAuth flow failed: %w", err)
-
-	case <-timeoutCtx.Done():
-		o.shutdown()
-		log.Error("OAuth flow timed out", slog.Duration("timeout", CallbackTimeout))
-		return nil, fmt.Errorf("OAuth flow timed out after %v", CallbackTimeout)
-
-	case <-ctx.Done():
-		o.shutdown()
-		log.Error("OAuth flow cancelled", slog.String("error", ctx.Err().Error()))
-		return nil, fmt.Errorf("OAuth flow cancelled: %w", ctx.Err())
+	timeout := time.NewTimer(CallbackTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case token := <-o.resultChan:
+			o.shutdown()
+			log.Info("OAuth authorization successful", 
+				slog.Bool("has_access_token", token.AccessToken != ""),
+				slog.Time("expires", token.Expiry))
+			console.Printf("✓ Authorization successful!\n")
+			return token, nil
+
+		case err := <-o.errorChan:
+			o.shutdown()
+			log.Error("OAuth flow failed", slog.String("error", err.Error()))
+			return nil, fmt.Errorf("OAuth flow failed: %w", err)
+
+		case err := <-o.exchangeFailed:
+			status := o.currentStatus()
+			log.Warn("OAuth token exchange failed",
+				slog.String("error", err.Error()),
+				slog.Int("attempt", status.Attempt-1),
+				slog.Int("max_attempts", MaxExchangeAttempts))
+			console.Errorf("✗ %v\n", err)
+			if status.AuthURL == "" {
+				o.shutdown()
+				return nil, fmt.Errorf("OAuth flow failed after %d attempts: %w", MaxExchangeAttempts, err)
+			}
+			console.Errorf("Authorize again to retry (attempt %d of %d) from the browser page or this URL:\n", status.Attempt, MaxExchangeAttempts)
+			console.Errorf("%s\n\n", status.AuthURL)
+			console.Printf("Waiting for authorization (timeout: %v)...\n", CallbackTimeout)
+			timeout.Reset(CallbackTimeout)
+
+		case <-timeout.C:
+			o.shutdown()
+			log.Error("OAuth flow timed out", slog.Duration("timeout", CallbackTimeout))
+			return nil, fmt.Errorf("OAuth flow timed out after %v", CallbackTimeout)
+
+		case <-ctx.Done():
+			o.shutdown()
+			log.Error("OAuth flow cancelled", slog.String("error", ctx.Err().Error()))
+			return nil, fmt.Errorf("OAuth flow cancelled: %w", ctx.Err())
+		}
 	}
 }
 
+// currentStatus returns a copy of the flow's status
+func (o *OAuthFlow) currentStatus() flowStatus {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.status
+}
+
 // startCallbackServer starts the local HTTP server to handle OAuth callbacks
 func (o *OAuthFlow) startCallbackServer() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc(CallbackPath, o.handleCallback)
+	mux.HandleFunc(StatusPath, o.handleStatus)
 	mux.HandleFunc("/", o.handleRoot)
 
 	o.server = &http.Server{
@@ -181,30 +256,74 @@ func (o *OAuthFlow) handleCallback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Exchange the code for an access token
-	token, err := o.config.Exchange(context.Background(), code)
-	if err != nil {
-		err = fmt.Errorf("failed to exchange code for token: %w", err)
-		o.errorChan <- err
-		o.writeErrorResponse(w, err)
-		return
+	// Only one exchange at a time; a repeated callback just shows its progress
+	o.mu.Lock()
+	busy := o.status.Phase == phaseExchanging || o.status.Phase == phaseDone
+	if !busy {
+		o.status.Phase, o.status.Error = phaseExchanging, ""
+	}
+	o.mu.Unlock()
+	if !busy {
+		exchangeClient, err := httpclient.New(o.proxyURL, o.exchangeTimeout)
+		if err != nil {
+			err = fmt.Errorf("network.proxy_url: %w", err)
+			o.errorChan <- err
+			o.writeErrorResponse(w, err)
+			return
+		}
+		logger.Get().Info("Authorization code received, exchanging for token",
+			slog.Duration("timeout", o.exchangeTimeout))
+		console.Printf("Authorization code received, exchanging for token (timeout: %v)...\n", o.exchangeTimeout)
+		go o.exchange(exchangeClient, code)
 	}
 
-	// Validate the token
-	if token.AccessToken == "" {
-		err := fmt.Errorf("received empty access token")
-		o.errorChan <- err
-		o.writeErrorResponse(w, err)
-		return
+	o.writeProgressResponse(w)
+}
+
+// exchange trades an authorization code for a token, giving up after
+// exchangeTimeout, and reports the outcome to Authorize and the status page
+func (o *OAuthFlow) exchange(client *http.Client, code string) {
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), oauth2.HTTPClient, client), o.exchangeTimeout)
+	defer cancel()
+
+	token, err := o.config.Exchange(ctx, code)
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		err = fmt.Errorf("%w: no token after %v: %w", ErrTimeout, o.exchangeTimeout, err)
+	case err != nil:
+		err = fmt.Errorf("failed to exchange code for token: %w", err)
+	case token.AccessToken == "":
+		err = fmt.Errorf("received empty access token")
 	}
 
-	// Send success response to browser
-	o.writeSuccessResponse(w)
+	o.mu.Lock()
+	if err != nil {
+		o.status.Phase, o.status.Error = phaseFailed, err.Error()
+		if o.status.Attempt < MaxExchangeAttempts {
+			o.status.Attempt++
+		} else {
+			o.status.AuthURL = ""
+		}
+	} else {
+		o.status.Phase = phaseDone
+	}
+	o.mu.Unlock()
 
-	// Send the token through the result channel
+	if err != nil {
+		o.exchangeFailed <- err
+		return
+	}
 	o.resultChan <- token
 }
 
+// handleStatus reports the flow's progress as JSON for the status page
+func (o *OAuthFlow) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(o.currentStatus())
+}
+
 // handleRoot provides a simple landing page for the OAuth server
 func (o *OAuthFlow) handleRoot(w http.ResponseWriter, r *http.Request) {
 	html := `
@@ -256,9 +375,71 @@ func (o *OAuthFlow) writeSuccessResponse(w http.ResponseWriter) {
 	w.Write([]byte(html))
 }
 
+// writeProgressResponse sends the page shown while the code is exchanged. It
+// polls the status endpoint and turns into the success page, or the error with
+// a link to authorize again.
+func (o *OAuthFlow) writeProgressResponse(w http.ResponseWriter) {
+	html := `
+<!DOCTYPE html>
+<html>
+<head>
+    <title>Mixcloud Authorization</title>
+    <style>
+        body { font-family: Arial, sans-serif; text-align: center; margin-top: 50px; }
+        .container { max-width: 500px; margin: 0 auto; }
+        .success { color: #28a745; }
+        .error { color: #dc3545; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1 id="title">Authorization code received</h1>
+        <p id="message">Exchanging it for an access token...</p>
+        <p><strong id="error"></strong></p>
+        <p><a id="retry" href="" hidden>Authorize again</a></p>
+    </div>
+    <script>
+        function poll() {
+            fetch("` + StatusPath + `", {cache: "no-store"}).then(function (r) { return r.json(); }).then(function (s) {
+                var title = document.getElementById("title"), message = document.getElementById("message");
+                if (s.phase === "done") {
+                    title.textContent = "✓ Authorization Successful!";
+                    title.className = "success";
+                    message.textContent = "You can now close this browser window and return to the terminal.";
+                    return;
+                }
+                if (s.phase === "failed") {
+                    title.textContent = "✗ Token Exchange Failed";
+                    title.className = "error";
+                    document.getElementById("error").textContent = s.error;
+                    var retry = document.getElementById("retry");
+                    if (s.auth_url) {
+                        message.textContent = "Authorization codes can only be used once, so retrying starts a new authorization (attempt " + s.attempt + ").";
+                        retry.href = s.auth_url;
+                        retry.hidden = false;
+                    } else {
+                        message.textContent = "No attempts left. Close this window and check the terminal.";
+                        retry.hidden = true;
+                    }
+                    return;
+                }
+                setTimeout(poll, 1000);
+            }).catch(function () {
+                document.getElementById("message").textContent = "Lost contact with the updater. Check the terminal.";
+            });
+        }
+        poll();
+    </script>
+</body>
+</html>`
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(html))
+}
+
 // writeErrorResponse sends an error page to the browser
 func (o *OAuthFlow) writeErrorResponse(w http.ResponseWriter, err error) {
-	html := fmt.Sprintf(`
+	page := fmt.Sprintf(`
 <!DOCTYPE html>
 <html>
 <head>
@@ -277,10 +458,10 @@ func (o *OAuthFlow) writeErrorResponse(w http.ResponseWriter, err error) {
         <p>Please close this browser window and try again.</p>
     </div>
 </body>
-</html>`, err.Error())
+</html>`, html.EscapeString(err.Error()))
 	w.Header().Set("Content-Type", "text/html")
 	w.WriteHeader(http.StatusBadRequest)
-	w.Write([]byte(html))
+	w.Write([]byte(page))
 }
 
 // openBrowser attempts to open the given URL in the default browser
@@ -315,19 +496,30 @@ func (o *OAuthFlow) shutdown() {
 
 // AuthorizeAndSave performs the complete OAuth flow and saves tokens to config
 func AuthorizeAndSave(cfg *config.Config, configPath string) error {
+	_, err := AuthorizeToken(cfg, configPath)
+	return err
+}
+
+// AuthorizeToken is AuthorizeAndSave returning the new token, so a running
+// client can pick it up via SaveToken
+func AuthorizeToken(cfg *config.Config, configPath string) (*oauth2.Token, error) {
 	// Validate OAuth configuration
 	if cfg.OAuth.ClientID == "" || cfg.OAuth.ClientSecret == "" {
-		return fmt.Errorf("OAuth client_id and client_secret must be configured")
+		return nil, fmt.Errorf("OAuth client_id and client_secret must be configured")
 	}
 
 	// Create OAuth flow
 	flow := NewOAuthFlow(cfg.OAuth.ClientID, cfg.OAuth.ClientSecret, DefaultCallbackPort)
+	flow.proxyURL = cfg.Network.ProxyURL
+	if cfg.OAuth.ExchangeTimeoutSeconds > 0 {
+		flow.exchangeTimeout = time.Duration(cfg.OAuth.ExchangeTimeoutSeconds) * time.Second
+	}
 
 	// Perform authorization
 	ctx := context.Background()
 	token, err := flow.Authorize(ctx)
 	if err != nil {
-		return fmt.Errorf("authorization failed: %w", err)
+		return nil, fmt.Errorf("authorization failed: %w", err)
 	}
 
 	// Update config with new tokens
@@ -336,9 +528,9 @@ func AuthorizeAndSave(cfg *config.Config, configPath string) error {
 
 	// Save updated config to file
 	if err := config.SaveConfig(cfg, configPath); err != nil {
-		return fmt.Errorf("failed to save tokens to config file: %w", err)
+		return nil, fmt.Errorf("failed to save tokens to config file: %w", err)
 	}
 
-	fmt.Printf("✓ OAuth tokens saved to config file: %s\n", configPath)
-	return nil
+	console.Printf("✓ OAuth tokens saved to config file: %s\n", configPath)
+	return token, nil
 }
\ No newline at end of file
@@ -0,0 +1,172 @@
+package mixcloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newStubTokenEndpoint answers code "slow" after delay, "good" with a token and
+// anything else with invalid_grant
+func newStubTokenEndpoint(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.FormValue("code") {
+		case "slow":
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+			}
+			fallthrough
+		case "good":
+			fmt.Fprint(w, `{"access_token": "new-access-token", "token_type": "bearer"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "invalid_grant"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestFlow returns a flow exchanging codes at tokenURL that never opens a browser
+func newTestFlow(tokenURL string, port int) *OAuthFlow {
+	flow := NewOAuthFlow("client-id", "client-secret", port)
+	flow.config.Endpoint.TokenURL = tokenURL
+	flow.config.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	flow.openURL = func(string) error { return nil }
+	return flow
+}
+
+func TestOAuthExchangeTimeout(t *testing.T) {
+	endpoint := newStubTokenEndpoint(t, 2*time.Second)
+	flow := newTestFlow(endpoint.URL, 0)
+	flow.exchangeTimeout = 50 * time.Millisecond
+	flow.status.AuthURL = "https://www.mixcloud.com/oauth/authorize?client_id=client-id"
+
+	// The browser gets the progress page straight away, before the exchange ends
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	flow.handleCallback(recorder, httptest.NewRequest(http.MethodGet, CallbackPath+"?code=slow", nil))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("callback answered after %v, want it before the exchange finishes", elapsed)
+	}
+	if body := recorder.Body.String(); recorder.Code != http.StatusOK || !strings.Contains(body, "Authorization code received") {
+		t.Errorf("callback response %d, want the progress page:\n%s", recorder.Code, body)
+	}
+
+	select {
+	case err := <-flow.exchangeFailed:
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("exchange error = %v, want ErrTimeout", err)
+		}
+	case <-flow.resultChan:
+		t.Fatal("exchange succeeded, want it cut off at the 50ms timeout")
+	case <-time.After(time.Second):
+		t.Fatal("exchange still running after 1s, want it cut off at the 50ms timeout")
+	}
+
+	recorder = httptest.NewRecorder()
+	flow.handleStatus(recorder, httptest.NewRequest(http.MethodGet, StatusPath, nil))
+	var status flowStatus
+	if err := json.NewDecoder(recorder.Body).Decode(&status); err != nil {
+		t.Fatalf("decoding status: %v", err)
+	}
+	if status.Phase != phaseFailed || status.Attempt != 2 || status.AuthURL == "" || !strings.Contains(status.Error, "timed out") {
+		t.Errorf("status = %+v, want a failed exchange with a retry link for attempt 2", status)
+	}
+}
+
+func TestOAuthExchangeAttemptsRunOut(t *testing.T) {
+	endpoint := newStubTokenEndpoint(t, 0)
+	flow := newTestFlow(endpoint.URL, 0)
+	flow.status.AuthURL = "https://www.mixcloud.com/oauth/authorize?client_id=client-id"
+	flow.status.Attempt = MaxExchangeAttempts
+
+	flow.handleCallback(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, CallbackPath+"?code=bad", nil))
+	<-flow.exchangeFailed
+	if status := flow.currentStatus(); status.Phase != phaseFailed || status.AuthURL != "" {
+		t.Errorf("status = %+v, want a failure without a retry link", status)
+	}
+}
+
+func TestAuthorizeRetriesFailedExchange(t *testing.T) {
+	endpoint := newStubTokenEndpoint(t, 0)
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	flow := newTestFlow(endpoint.URL, port)
+
+	type outcome struct {
+		token *oauth2.Token
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		token, err := flow.Authorize(context.Background())
+		done <- outcome{token, err}
+	}()
+
+	// A spare keep-alive connection would hold up the server's shutdown
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	base := fmt.Sprintf("http://localhost:%d", port)
+	callback := func(code string) {
+		t.Helper()
+		resp, err := client.Get(base + CallbackPath + "?code=" + code)
+		if err != nil {
+			t.Fatalf("callback: %v", err)
+		}
+		resp.Body.Close()
+	}
+	waitForPhase := func(phase string) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if flow.currentStatus().Phase == phase {
+				return
+			}
+		}
+		t.Fatalf("flow never reached phase %q, status %+v", phase, flow.currentStatus())
+	}
+
+	// The callback server starts in the background
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		resp, err := client.Get(base + StatusPath)
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("callback server never started: %v", err)
+		}
+	}
+
+	// A used code fails the exchange; authorizing again brings a fresh one
+	callback("used-code")
+	waitForPhase(phaseFailed)
+	callback("good")
+
+	select {
+	case result := <-done:
+		if result.err != nil {
+			t.Fatalf("Authorize() error = %v", result.err)
+		}
+		if result.token.AccessToken != "new-access-token" {
+			t.Errorf("Authorize() token = %q, want new-access-token", result.token.AccessToken)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Authorize() didn't return after the retried exchange succeeded")
+	}
+}
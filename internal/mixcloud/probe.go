@@ -0,0 +1,139 @@
+package mixcloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AIDEV-NOTE: Ping and Me back the -doctor checks. Both are single attempts
+// without retries - a diagnosis should report a flaky network, not hide it.
+
+// MeEndpoint returns the account the access token belongs to
+const MeEndpoint = "/me/"
+
+// Probe is the outcome of a reachability check against the API
+type Probe struct {
+	StatusCode int           // Any status counts as reachable
+	Latency    time.Duration // Time to the response headers
+	ServerTime time.Time     // From the Date header, zero when missing
+	LocalTime  time.Time     // Local clock halfway through the request
+}
+
+// ClockSkew returns how far the local clock is ahead of the server's (negative
+// when behind), and false when the response had no Date header. The Date header
+// has one-second resolution.
+func (p *Probe) ClockSkew() (time.Duration, bool) {
+	if p.ServerTime.IsZero() {
+		return 0, false
+	}
+	return p.LocalTime.Sub(p.ServerTime), true
+}
+
+// User is the Mixcloud account an access token belongs to
+type User struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// Ping sends an unauthenticated request to the API root and reports latency and
+// the server's clock
+func (c *Client) Ping(ctx context.Context) (*Probe, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.apiBaseURL()+"/", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create request", ErrAPIRequestFailed)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "mixcloud-updater/1.0")
+
+	start := time.Now()
+	resp, err := c.plainClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: HTTP request failed: %w", ErrNetworkFailure, err)
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	probe := &Probe{
+		StatusCode: resp.StatusCode,
+		Latency:    latency,
+		LocalTime:  start.Add(latency / 2),
+	}
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		probe.ServerTime = date
+	}
+	return probe, nil
+}
+
+// Me returns the account the client's access token belongs to. A rejected token
+// is reported as ErrAuthenticationFailed.
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	if c.token == nil {
+		return nil, fmt.Errorf("%w: no access token", ErrAuthenticationFailed)
+	}
+	return c.UserForToken(ctx, c.token.AccessToken)
+}
+
+// UserForToken returns the account accessToken belongs to like Me, without
+// using or changing the client's token - to check a token before SaveToken
+// stores it
+func (c *Client) UserForToken(ctx context.Context, accessToken string) (*User, error) {
+	if accessToken == "" {
+		return nil, fmt.Errorf("%w: no access token", ErrAuthenticationFailed)
+	}
+
+	apiURL := fmt.Sprintf("%s%s?access_token=%s", c.apiBaseURL(), MeEndpoint, url.QueryEscape(accessToken))
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create request", ErrAPIRequestFailed)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "mixcloud-updater/1.0")
+
+	// Token in the query parameter, as for edits
+	resp, err := c.plainClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: HTTP request failed: %w", ErrNetworkFailure, redactToken(err, url.QueryEscape(accessToken)))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read response body: %v", ErrAPIRequestFailed, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w: access token rejected (status %d)", ErrAuthenticationFailed, resp.StatusCode)
+	case resp.StatusCode == http.StatusBadRequest && strings.Contains(string(body), "OAuthException"):
+		return nil, fmt.Errorf("%w: access token rejected: %s", ErrAuthenticationFailed, string(body))
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: %w (status %d)", ErrAPIRequestFailed, ErrServerError, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: unexpected status code %d", ErrAPIRequestFailed, resp.StatusCode)
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse JSON response: %v", ErrAPIRequestFailed, err)
+	}
+	if user.Username == "" {
+		return nil, fmt.Errorf("%w: response has no username", ErrAPIRequestFailed)
+	}
+	return &user, nil
+}
+
+// redactToken keeps the access token out of request errors, which quote the URL
+func redactToken(err error, token string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = strings.ReplaceAll(urlErr.URL, token, "REDACTED")
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package mixcloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestPing(t *testing.T) {
+	serverTime := time.Now().Add(-10 * time.Minute).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL}
+	probe, err := client.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if probe.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", probe.StatusCode)
+	}
+	if !probe.ServerTime.Equal(serverTime) {
+		t.Errorf("ServerTime = %v, want %v", probe.ServerTime, serverTime)
+	}
+	skew, ok := probe.ClockSkew()
+	if !ok || skew < 10*time.Minute || skew > 10*time.Minute+2*time.Second {
+		t.Errorf("ClockSkew() = %v, %v, want about 10m ahead", skew, ok)
+	}
+}
+
+func TestProbesUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := &Client{baseURL: server.URL, token: &oauth2.Token{AccessToken: "secret-token"}}
+	if _, err := client.Ping(context.Background()); !errors.Is(err, ErrNetworkFailure) {
+		t.Errorf("Ping() error = %v, want ErrNetworkFailure", err)
+	}
+	_, err := client.Me(context.Background())
+	if !errors.Is(err, ErrNetworkFailure) {
+		t.Fatalf("Me() error = %v, want ErrNetworkFailure", err)
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Me() error %q exposes the access token", err)
+	}
+}
+
+func TestMe(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr error
+	}{
+		{"account", http.StatusOK, `{"username":"nowwaveradio","name":"Now Wave Radio"}`, "nowwaveradio", nil},
+		{"rejected token", http.StatusUnauthorized, `{}`, "", ErrAuthenticationFailed},
+		{"OAuth exception", http.StatusBadRequest, `{"error":{"type":"OAuthException","message":"Invalid access token"}}`, "", ErrAuthenticationFailed},
+		{"server error", http.StatusBadGateway, ``, "", ErrServerError},
+		{"no username", http.StatusOK, `{"name":"Now Wave Radio"}`, "", ErrAPIRequestFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != MeEndpoint || r.URL.Query().Get("access_token") != "token" {
+					t.Errorf("request = %s, want %s with the access token", r.URL, MeEndpoint)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := &Client{baseURL: server.URL, token: &oauth2.Token{AccessToken: "token"}}
+			user, err := client.Me(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Me() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Me() error = %v", err)
+			}
+			if user.Username != tt.want {
+				t.Errorf("Username = %q, want %q", user.Username, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserForToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "candidate" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"username":"nowwaveradio"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseURL: server.URL, token: &oauth2.Token{AccessToken: "current"}}
+	user, err := client.UserForToken(context.Background(), "candidate")
+	if err != nil {
+		t.Fatalf("UserForToken() error = %v", err)
+	}
+	if user.Username != "nowwaveradio" {
+		t.Errorf("Username = %q, want nowwaveradio", user.Username)
+	}
+	if client.token.AccessToken != "current" {
+		t.Errorf("client token = %q, want it unchanged", client.token.AccessToken)
+	}
+
+	if _, err := client.UserForToken(context.Background(), ""); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Errorf("UserForToken(\"\") error = %v, want ErrAuthenticationFailed", err)
+	}
+}
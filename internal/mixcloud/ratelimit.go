@@ -0,0 +1,120 @@
+package mixcloud
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// AIDEV-NOTE: Mixcloud sends X-RateLimit-* headers on some responses but not
+// all, so Stats keeps the last values seen rather than resetting them. The
+// recording transport sits directly on the shared connection pool, below the
+// OAuth transport, so every request is seen exactly once.
+
+// Stats is the API quota as of the most recent response that reported it
+type Stats struct {
+	Remaining  int           // X-RateLimit-Remaining, valid when QuotaKnown
+	Limit      int           // X-RateLimit-Limit, 0 when not sent
+	QuotaKnown bool          // False until a response carried X-RateLimit-Remaining
+	RetryAfter time.Duration // From the most recent Retry-After header, 0 when none was sent
+	Responses  int           // Responses seen, with or without rate-limit headers
+	UpdatedAt  time.Time     // When the quota was last reported
+}
+
+// RemainingString describes the remaining quota for summaries, e.g. "37 of 60"
+func (s Stats) RemainingString() string {
+	if !s.QuotaKnown {
+		return "unknown"
+	}
+	if s.Limit > 0 {
+		return fmt.Sprintf("%d of %d", s.Remaining, s.Limit)
+	}
+	return strconv.Itoa(s.Remaining)
+}
+
+// rateLimitStats is the Client's mutex-guarded Stats
+type rateLimitStats struct {
+	mu        sync.Mutex
+	stats     Stats
+	warnBelow int                              // Warn when the remaining quota drops below this, 0 = never
+	warned    bool                             // Warned since the quota was last at or above warnBelow
+	lowQuota  func(stats Stats, warnBelow int) // Called on each drop below warnBelow; tests substitute it, nil logs a warning
+}
+
+// record updates the stats from a response's headers and reports whether the
+// remaining quota just dropped below the warning threshold
+func (r *rateLimitStats) record(header http.Header, now time.Time) (Stats, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats.Responses++
+	r.stats.RetryAfter = time.Duration(parseRetryAfterHeader(header.Get("Retry-After"))) * time.Second
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining < 0 {
+		return r.stats, false
+	}
+	r.stats.Remaining = remaining
+	r.stats.QuotaKnown = true
+	r.stats.UpdatedAt = now
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil && limit > 0 {
+		r.stats.Limit = limit
+	}
+
+	// Warn once per crossing, not on every request below the threshold
+	crossed := false
+	if remaining < r.warnBelow {
+		crossed = !r.warned
+		r.warned = true
+	} else {
+		r.warned = false
+	}
+	return r.stats, crossed
+}
+
+func (r *rateLimitStats) snapshot() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// rateLimitTransport records the rate-limit headers of every response
+type rateLimitTransport struct {
+	base  http.RoundTripper
+	stats *rateLimitStats
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if stats, crossed := t.stats.record(resp.Header, time.Now()); crossed {
+		lowQuota := t.stats.lowQuota
+		if lowQuota == nil {
+			lowQuota = warnLowQuota
+		}
+		lowQuota(stats, t.stats.warnBelow)
+	}
+	return resp, nil
+}
+
+// warnLowQuota logs that the remaining quota dropped below warnBelow
+func warnLowQuota(stats Stats, warnBelow int) {
+	logger.Get().Warn("Mixcloud API quota running low",
+		slog.Int("remaining", stats.Remaining),
+		slog.Int("limit", stats.Limit),
+		slog.Int("warn_below", warnBelow))
+}
+
+// Stats returns the API quota reported by the most recent responses
+func (c *Client) Stats() Stats {
+	return c.rateLimit.snapshot()
+}
@@ -0,0 +1,191 @@
+package mixcloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// quotaServer serves a cloudcast and sends the next of its headers with each
+// response, none once they run out
+type quotaServer struct {
+	mu      sync.Mutex
+	headers []map[string]string
+}
+
+func (s *quotaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if len(s.headers) > 0 {
+		for name, value := range s.headers[0] {
+			w.Header().Set(name, value)
+		}
+		s.headers = s.headers[1:]
+	}
+	s.mu.Unlock()
+	(&cloudcastServer{name: "Show"}).ServeHTTP(w, r)
+}
+
+func newQuotaTestClient(t *testing.T, headers ...map[string]string) *Client {
+	t.Helper()
+	server := httptest.NewServer(&quotaServer{headers: headers})
+	t.Cleanup(server.Close)
+	return &Client{baseURL: server.URL, rateLimit: rateLimitStats{warnBelow: 10}}
+}
+
+func quotaHeaders(remaining, limit int) map[string]string {
+	return map[string]string{
+		"X-RateLimit-Remaining": strconv.Itoa(remaining),
+		"X-RateLimit-Limit":     strconv.Itoa(limit),
+	}
+}
+
+func TestStatsRecordsRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    []map[string]string
+		requests   int
+		wantQuota  string
+		wantRetry  time.Duration
+		wantKnown  bool
+		wantRemain int
+	}{
+		{
+			name:      "no requests",
+			wantQuota: "unknown",
+		},
+		{
+			name:      "headers absent",
+			headers:   []map[string]string{{}, {}},
+			requests:  2,
+			wantQuota: "unknown",
+		},
+		{
+			name:       "latest values win",
+			headers:    []map[string]string{quotaHeaders(40, 60), quotaHeaders(37, 60)},
+			requests:   2,
+			wantQuota:  "37 of 60",
+			wantKnown:  true,
+			wantRemain: 37,
+		},
+		{
+			name:       "absent headers keep the last known quota",
+			headers:    []map[string]string{quotaHeaders(37, 60), {}},
+			requests:   2,
+			wantQuota:  "37 of 60",
+			wantKnown:  true,
+			wantRemain: 37,
+		},
+		{
+			name:       "remaining without limit",
+			headers:    []map[string]string{{"X-RateLimit-Remaining": "12"}},
+			requests:   1,
+			wantQuota:  "12",
+			wantKnown:  true,
+			wantRemain: 12,
+		},
+		{
+			name:       "zero remaining is not unknown",
+			headers:    []map[string]string{quotaHeaders(0, 60)},
+			requests:   1,
+			wantQuota:  "0 of 60",
+			wantKnown:  true,
+			wantRemain: 0,
+		},
+		{
+			name:      "unparseable remaining",
+			headers:   []map[string]string{{"X-RateLimit-Remaining": "lots"}},
+			requests:  1,
+			wantQuota: "unknown",
+		},
+		{
+			name:      "retry-after",
+			headers:   []map[string]string{{"Retry-After": "20"}},
+			requests:  1,
+			wantQuota: "unknown",
+			wantRetry: 20 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newQuotaTestClient(t, tt.headers...)
+			client.rateLimit.lowQuota = func(Stats, int) {}
+			for i := 0; i < tt.requests; i++ {
+				if _, err := client.GetShow(cacheTestShowURL); err != nil {
+					t.Fatalf("GetShow() error = %v", err)
+				}
+			}
+
+			stats := client.Stats()
+			if got := stats.RemainingString(); got != tt.wantQuota {
+				t.Errorf("RemainingString() = %q, want %q", got, tt.wantQuota)
+			}
+			if stats.QuotaKnown != tt.wantKnown || stats.Remaining != tt.wantRemain {
+				t.Errorf("QuotaKnown, Remaining = %v, %d, want %v, %d", stats.QuotaKnown, stats.Remaining, tt.wantKnown, tt.wantRemain)
+			}
+			if stats.RetryAfter != tt.wantRetry {
+				t.Errorf("RetryAfter = %v, want %v", stats.RetryAfter, tt.wantRetry)
+			}
+			if stats.Responses != tt.requests {
+				t.Errorf("Responses = %d, want %d", stats.Responses, tt.requests)
+			}
+		})
+	}
+}
+
+func TestLowQuotaWarning(t *testing.T) {
+	// Warns when the quota drops below 10, once per drop
+	client := newQuotaTestClient(t,
+		quotaHeaders(12, 60), quotaHeaders(9, 60), quotaHeaders(8, 60), map[string]string{},
+		quotaHeaders(11, 60), quotaHeaders(5, 60))
+	var warned []int
+	client.rateLimit.lowQuota = func(stats Stats, warnBelow int) {
+		if warnBelow != 10 {
+			t.Errorf("warnBelow = %d, want 10", warnBelow)
+		}
+		warned = append(warned, stats.Remaining)
+	}
+
+	for i := 0; i < 6; i++ {
+		if _, err := client.GetShow(cacheTestShowURL); err != nil {
+			t.Fatalf("GetShow() error = %v", err)
+		}
+	}
+	if want := []int{9, 5}; !reflect.DeepEqual(warned, want) {
+		t.Errorf("warned at %v remaining, want %v", warned, want)
+	}
+
+	t.Run("no threshold never warns", func(t *testing.T) {
+		client := newQuotaTestClient(t, quotaHeaders(1, 60))
+		client.rateLimit.warnBelow = 0
+		client.rateLimit.lowQuota = func(Stats, int) { t.Error("warned without a threshold") }
+		if _, err := client.GetShow(cacheTestShowURL); err != nil {
+			t.Fatalf("GetShow() error = %v", err)
+		}
+	})
+}
+
+func TestOAuthRequestsRecordedOnce(t *testing.T) {
+	client := newQuotaTestClient(t, quotaHeaders(30, 60))
+	client.oauth2Config = &oauth2.Config{ClientID: "id", ClientSecret: "secret"}
+	if err := client.recreateHTTPClient(&oauth2.Token{AccessToken: "token"}); err != nil {
+		t.Fatalf("recreateHTTPClient() error = %v", err)
+	}
+
+	resp, err := client.httpClient.Get(client.baseURL + "/nowwaveradio/show/")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	stats := client.Stats()
+	if stats.Responses != 1 || stats.RemainingString() != "30 of 60" {
+		t.Errorf("Stats() = %+v, want one response with 30 of 60 remaining", stats)
+	}
+}
@@ -0,0 +1,66 @@
+package mixcloud
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/retry"
+)
+
+// AIDEV-NOTE: The single definition of how Mixcloud calls are retried. The processor
+// retries whole GetShow/UpdateShow calls with it; the OAuth transport and
+// executeAPIRequestWithRetry retry single requests. GetShow and UpdateShow go through
+// the plain client, so the loops never nest and a call under a 429 storm is attempted
+// exactly processing.retry_attempts times.
+
+// RetryPolicy returns the Mixcloud retry policy: processing.retry_attempts attempts
+// (default constants.DefaultRetryAttempts), waiting 1s, 2s, 4s... capped at 30s,
+// each ±25%. Callers set Retryable and OnRetry.
+func RetryPolicy(cfg *config.Config) retry.Policy {
+	attempts := constants.DefaultRetryAttempts
+	if cfg != nil && cfg.Processing.RetryAttempts > 0 {
+		attempts = cfg.Processing.RetryAttempts
+	}
+	return retry.Policy{
+		MaxAttempts: attempts,
+		BaseDelay:   constants.DefaultRetryDelaySeconds * time.Second,
+		MaxDelay:    constants.MaxRetryDelaySeconds * time.Second,
+		Jitter:      constants.RetryJitter,
+		RetryAfter:  retryAfter,
+	}
+}
+
+// tooManyRequestsError carries a 429 response between attempts of a request retry
+type tooManyRequestsError struct {
+	resp       *http.Response
+	retryAfter time.Duration // From the Retry-After header, 0 when absent
+}
+
+func (e *tooManyRequestsError) Error() string {
+	return "HTTP 429 Too Many Requests"
+}
+
+func (e *tooManyRequestsError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// newTooManyRequestsError wraps a 429 response. The body stays open in case no
+// attempts are left and the response goes back to the caller.
+func newTooManyRequestsError(resp *http.Response) *tooManyRequestsError {
+	return &tooManyRequestsError{
+		resp:       resp,
+		retryAfter: time.Duration(parseRetryAfterHeader(resp.Header.Get("Retry-After"))) * time.Second,
+	}
+}
+
+// retryAfter returns the wait a 429 response asked for
+func retryAfter(err error) time.Duration {
+	var tooMany *tooManyRequestsError
+	if errors.As(err, &tooMany) {
+		return tooMany.retryAfter
+	}
+	return 0
+}
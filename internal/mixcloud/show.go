@@ -0,0 +1,116 @@
+package mixcloud
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Show represents a Mixcloud show/cloudcast
+type Show struct {
+	Key         string            `json:"key"`          // Cloudcast key (username/slug format)
+	Name        string            `json:"name"`         // Show title
+	Description string            `json:"description"`  // Current description text
+	URL         string            `json:"url"`          // Full URL to the show
+	CreatedTime time.Time         `json:"created_time"` // When the show was published (zero if unknown)
+	AudioLength int               `json:"audio_length"` // Length in seconds
+	PlayCount   int               `json:"play_count"`
+	Tags        []string          `json:"tags"`     // Tag names, e.g. "New Wave"
+	PictureURLs map[string]string `json:"pictures"` // Artwork URLs keyed by size, e.g. "large"
+}
+
+// AIDEV-NOTE: The API response is decoded through apiShow so fields that are
+// missing, null or shaped differently across API versions never fail the whole
+// show - GetShow only requires key and name.
+
+// apiShow mirrors the cloudcast JSON returned by the Mixcloud API
+type apiShow struct {
+	Key         string                 `json:"key"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	URL         string                 `json:"url"`
+	CreatedTime string                 `json:"created_time"`
+	AudioLength float64                `json:"audio_length"`
+	PlayCount   float64                `json:"play_count"`
+	Tags        []json.RawMessage      `json:"tags"`
+	Pictures    map[string]interface{} `json:"pictures"`
+}
+
+// apiTag is a tag object as nested in a cloudcast response
+type apiTag struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// createdTimeLayouts are the timestamp formats seen in created_time
+var createdTimeLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+}
+
+// UnmarshalJSON decodes a cloudcast API response, tolerating missing fields
+func (s *Show) UnmarshalJSON(data []byte) error {
+	var raw apiShow
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = Show{
+		Key:         raw.Key,
+		Name:        raw.Name,
+		Description: raw.Description,
+		URL:         raw.URL,
+		CreatedTime: parseCreatedTime(raw.CreatedTime),
+		AudioLength: int(raw.AudioLength),
+		PlayCount:   int(raw.PlayCount),
+		Tags:        parseTags(raw.Tags),
+		PictureURLs: parsePictures(raw.Pictures),
+	}
+	return nil
+}
+
+// parseCreatedTime returns the zero time for missing or unrecognised timestamps
+func parseCreatedTime(value string) time.Time {
+	for _, layout := range createdTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseTags accepts tag objects ({"key": ..., "name": ...}) as returned by the
+// API, or plain strings as written when a Show is marshalled back to JSON
+func parseTags(rawTags []json.RawMessage) []string {
+	var tags []string
+	for _, rawTag := range rawTags {
+		var tag apiTag
+		if err := json.Unmarshal(rawTag, &tag); err == nil {
+			if tag.Name != "" {
+				tags = append(tags, tag.Name)
+			}
+			continue
+		}
+
+		var name string
+		if err := json.Unmarshal(rawTag, &name); err == nil && name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// parsePictures keeps only string-valued picture URLs
+func parsePictures(pictures map[string]interface{}) map[string]string {
+	if len(pictures) == 0 {
+		return nil
+	}
+
+	urls := make(map[string]string, len(pictures))
+	for size, value := range pictures {
+		if url, ok := value.(string); ok && url != "" {
+			urls[size] = url
+		}
+	}
+	return urls
+}
@@ -0,0 +1,107 @@
+package mixcloud
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShowUnmarshalAPIFixture(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "cloudcast.json"))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	var show Show
+	if err := json.Unmarshal(data, &show); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if show.Key != "/nowwaveradio/the-newer-new-wave-show-june-28-2025/" {
+		t.Errorf("Key = %q", show.Key)
+	}
+	if show.Name != "The Newer New Wave Show - June 28 2025" {
+		t.Errorf("Name = %q", show.Name)
+	}
+	if show.Description == "" {
+		t.Error("Description should be populated")
+	}
+	if want := time.Date(2025, 6, 28, 21, 14, 5, 0, time.UTC); !show.CreatedTime.Equal(want) {
+		t.Errorf("CreatedTime = %v, want %v", show.CreatedTime, want)
+	}
+	if show.AudioLength != 7203 {
+		t.Errorf("AudioLength = %d, want 7203", show.AudioLength)
+	}
+	if show.PlayCount != 128 {
+		t.Errorf("PlayCount = %d, want 128", show.PlayCount)
+	}
+	if want := []string{"New Wave", "Synthpop"}; !reflect.DeepEqual(show.Tags, want) {
+		t.Errorf("Tags = %v, want %v", show.Tags, want)
+	}
+	if len(show.PictureURLs) != 5 || show.PictureURLs["large"] == "" {
+		t.Errorf("PictureURLs = %v, want 5 sizes including large", show.PictureURLs)
+	}
+}
+
+func TestShowUnmarshalToleratesMissingFields(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Show
+	}{
+		{
+			name: "minimal response",
+			json: `{"key": "/user/show/", "name": "Show"}`,
+			want: Show{Key: "/user/show/", Name: "Show"},
+		},
+		{
+			name: "null and unparseable values",
+			json: `{"key": "/user/show/", "name": "Show", "created_time": "last tuesday", "audio_length": null, "tags": null, "pictures": {"large": null}}`,
+			want: Show{Key: "/user/show/", Name: "Show", PictureURLs: map[string]string{}},
+		},
+		{
+			name: "tags as plain strings",
+			json: `{"key": "/user/show/", "name": "Show", "tags": ["Jazz", {"name": "Soul"}, 42]}`,
+			want: Show{Key: "/user/show/", Name: "Show", Tags: []string{"Jazz", "Soul"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var show Show
+			if err := json.Unmarshal([]byte(tt.json), &show); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(show, tt.want) {
+				t.Errorf("Show = %+v, want %+v", show, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowJSONRoundTrip(t *testing.T) {
+	original := Show{
+		Key:         "/user/show/",
+		Name:        "Show",
+		CreatedTime: time.Date(2025, 6, 28, 21, 14, 5, 0, time.UTC),
+		AudioLength: 3600,
+		Tags:        []string{"Jazz"},
+		PictureURLs: map[string]string{"large": "https://example.com/large.jpg"},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded Show
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("round trip = %+v, want %+v", decoded, original)
+	}
+}
@@ -0,0 +1,99 @@
+package mixcloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// newStalledServer answers nothing until the test ends, like a hung TLS connection
+func newStalledServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+	return server
+}
+
+func TestRequestsTimeOut(t *testing.T) {
+	server := newStalledServer(t)
+	client := &Client{
+		baseURL: server.URL,
+		timeout: 50 * time.Millisecond,
+		token:   &oauth2.Token{AccessToken: "token"},
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetShow", func() error {
+			_, err := client.GetShow(cacheTestShowURL)
+			return err
+		}},
+		{"UpdateShow", func() error {
+			return client.UpdateShow(cacheTestShowURL, map[string]string{"description": "tracks"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := tt.call()
+			if !errors.Is(err, ErrNetworkFailure) || !errors.Is(err, ErrTimeout) {
+				t.Fatalf("error = %v, want ErrNetworkFailure and ErrTimeout", err)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("request took %v, want it cut off at the 50ms timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestGetShowContextCancelled(t *testing.T) {
+	server := newStalledServer(t)
+	client := &Client{baseURL: server.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetShowContext(ctx, cacheTestShowURL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int
+		want    time.Duration
+	}{
+		{"unset uses APITimeoutSeconds", 0, APITimeoutSeconds * time.Second},
+		{"configured", 5, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Processing.HTTPTimeoutSeconds = tt.seconds
+			if got := requestTimeout(cfg); got != tt.want {
+				t.Errorf("requestTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
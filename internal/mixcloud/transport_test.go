@@ -0,0 +1,100 @@
+package mixcloud
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// countingTransport returns a transport that counts the connections it dials
+func countingTransport(dials *int32) *http.Transport {
+	dialer := &net.Dialer{}
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(dials, 1)
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+func TestRequestsReuseConnections(t *testing.T) {
+	cloudcast := &cloudcastServer{name: "Show"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"result":{"success":true}}`))
+			return
+		}
+		cloudcast.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	var dials int32
+	client := &Client{
+		baseURL:   server.URL,
+		token:     &oauth2.Token{AccessToken: "token"},
+		transport: countingTransport(&dials),
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetShow(cacheTestShowURL); err != nil {
+			t.Fatalf("GetShow() error = %v", err)
+		}
+		if err := client.UpdateShow(cacheTestShowURL, map[string]string{"description": "tracks"}); err != nil {
+			t.Fatalf("UpdateShow() error = %v", err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&dials); got != 1 {
+		t.Errorf("dialed %d connections for 6 requests, want 1 reused connection", got)
+	}
+}
+
+func TestRequestsUseConfiguredProxy(t *testing.T) {
+	cloudcast := &cloudcastServer{name: "Show"}
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := proxyCredentials(r); !ok || user != "radio" || pass != "s3cret" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		proxied = append(proxied, r.URL.String()) // A proxy sees the absolute URL
+		cloudcast.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	cfg := &config.Config{}
+	cfg.OAuth.ClientID = "id"
+	cfg.OAuth.ClientSecret = "secret"
+	cfg.Station.MixcloudUsername = "testuser"
+	cfg.Network.ProxyURL = strings.Replace(proxy.URL, "http://", "http://radio:s3cret@", 1)
+	client, err := NewClient(cfg, "")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.SetBaseURL("http://api.mixcloud.invalid") // Only the proxy can reach it
+
+	if _, err := client.GetShow(cacheTestShowURL); err != nil {
+		t.Fatalf("GetShow() error = %v", err)
+	}
+	if len(proxied) != 1 || !strings.HasPrefix(proxied[0], "http://api.mixcloud.invalid/") {
+		t.Errorf("proxied requests = %v, want the GetShow request", proxied)
+	}
+}
+
+// proxyCredentials decodes a request's Proxy-Authorization header
+func proxyCredentials(r *http.Request) (user, pass string, ok bool) {
+	auth := r.Header.Get("Proxy-Authorization")
+	if auth == "" {
+		return "", "", false
+	}
+	req := &http.Request{Header: http.Header{"Authorization": {auth}}}
+	return req.BasicAuth()
+}
@@ -0,0 +1,101 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: A systemic problem - an unmounted CUE share, a revoked token -
+// fails every show of a batch the same way, each after its own retries. The
+// failure thresholds stop the run once failures look systemic instead of
+// grinding through the rest: shows not started are reported as not attempted,
+// never as failures, and the error that tripped the threshold is quoted in the
+// summary. The run's exit code still comes from the failures that did happen,
+// so an abort caused by auth failures exits with the auth code.
+
+// abortPolicy decides when a batch run stops starting shows. A zero threshold
+// never trips.
+type abortPolicy struct {
+	maxConsecutive int // Failures in a row; any success resets the count
+	maxTotal       int // Failures in the whole run
+
+	consecutive int
+	total       int
+	trigger     *FailureAbort // Set once a threshold is reached
+}
+
+// FailureAbort describes the failure that stopped a run at a failure threshold
+type FailureAbort struct {
+	Reason  string // Which threshold, e.g. "max_consecutive_failures (3)"
+	ShowKey string // The show whose failure reached it
+	Error   error
+}
+
+func (a *FailureAbort) String() string {
+	return fmt.Sprintf("stopped at %s, last failure: %s: %v", a.Reason, a.ShowKey, a.Error)
+}
+
+// newAbortPolicy returns the run's thresholds: processing.max_consecutive_failures
+// and max_total_failures unless the command line replaced them
+func (sp *ShowProcessor) newAbortPolicy() *abortPolicy {
+	policy := &abortPolicy{
+		maxConsecutive: sp.config.Processing.MaxConsecutiveFailures,
+		maxTotal:       sp.config.Processing.MaxTotalFailures,
+	}
+	if sp.options.MaxConsecutiveFailures != nil {
+		policy.maxConsecutive = *sp.options.MaxConsecutiveFailures
+	}
+	if sp.options.MaxTotalFailures != nil {
+		policy.maxTotal = *sp.options.MaxTotalFailures
+	}
+	return policy
+}
+
+// record counts a finished show and reports whether the run must stop. Skipped
+// and queued shows neither count nor reset the run of failures.
+func (p *abortPolicy) record(result ProcessingResult) bool {
+	if p.trigger != nil {
+		return true
+	}
+	switch {
+	case result.Error != nil:
+		p.consecutive++
+		p.total++
+	case result.Success:
+		p.consecutive = 0
+		return false
+	default:
+		return false
+	}
+
+	switch {
+	case p.maxConsecutive > 0 && p.consecutive >= p.maxConsecutive:
+		p.trigger = &FailureAbort{Reason: fmt.Sprintf("max_consecutive_failures (%d)", p.maxConsecutive)}
+	case p.maxTotal > 0 && p.total >= p.maxTotal:
+		p.trigger = &FailureAbort{Reason: fmt.Sprintf("max_total_failures (%d)", p.maxTotal)}
+	default:
+		return false
+	}
+	p.trigger.ShowKey = result.ShowKey
+	p.trigger.Error = result.Error
+	return true
+}
+
+// stopAtFailureThreshold records showKeys as not attempted because the run
+// reached a failure threshold
+func (sp *ShowProcessor) stopAtFailureThreshold(batchResult *BatchResult, abort *FailureAbort, showKeys []string) {
+	batchResult.FailureAbort = abort
+	batchResult.ThresholdSkipped = append(batchResult.ThresholdSkipped, showKeys...)
+	batchResult.NotAttemptedShows += len(showKeys)
+
+	sp.logger.Error("Failure threshold reached, not attempting remaining shows",
+		slog.String("reason", abort.Reason),
+		slog.String("show_key", abort.ShowKey),
+		slog.String("error", abort.Error.Error()),
+		slog.Int("not_attempted", len(showKeys)),
+		slog.String("not_attempted_shows", strings.Join(showKeys, ", ")))
+	console.Failuref("🛑 %s reached: not attempting %d remaining shows\n\n", abort.Reason, len(showKeys))
+}
@@ -0,0 +1,141 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAbortPolicy(t *testing.T) {
+	// Each outcome is F (failed), S (succeeded) or K (skipped)
+	tests := []struct {
+		name           string
+		maxConsecutive int
+		maxTotal       int
+		outcomes       string
+		wantStopAt     int // Index of the outcome that trips the policy, -1 for none
+		wantReason     string
+	}{
+		{"no thresholds", 0, 0, "FFFFFF", -1, ""},
+		{"consecutive", 3, 0, "FFF", 2, "max_consecutive_failures (3)"},
+		{"success resets the run", 3, 0, "FFSFFSFF", -1, ""},
+		{"skips don't reset the run", 3, 0, "FKFKF", 4, "max_consecutive_failures (3)"},
+		{"total", 0, 3, "FSFSSF", 5, "max_total_failures (3)"},
+		{"consecutive before total", 2, 5, "FSFF", 3, "max_consecutive_failures (2)"},
+		{"total before consecutive", 3, 3, "FSFFS", 3, "max_total_failures (3)"},
+		{"threshold of one", 1, 0, "SSF", 2, "max_consecutive_failures (1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &abortPolicy{maxConsecutive: tt.maxConsecutive, maxTotal: tt.maxTotal}
+			stopAt := -1
+			for i, outcome := range tt.outcomes {
+				result := ProcessingResult{ShowKey: fmt.Sprintf("show%d", i)}
+				switch outcome {
+				case 'F':
+					result.Error = fmt.Errorf("failure %d", i)
+				case 'S':
+					result.Success = true
+				case 'K':
+					result.Skipped = true
+				}
+				if policy.record(result) {
+					stopAt = i
+					break
+				}
+			}
+
+			if stopAt != tt.wantStopAt {
+				t.Fatalf("stopped at outcome %d, want %d", stopAt, tt.wantStopAt)
+			}
+			if stopAt < 0 {
+				if policy.trigger != nil {
+					t.Errorf("trigger = %v, want none", policy.trigger)
+				}
+				return
+			}
+			want := fmt.Sprintf("stopped at %s, last failure: show%d: failure %d", tt.wantReason, stopAt, stopAt)
+			if got := policy.trigger.String(); got != want {
+				t.Errorf("trigger = %q, want %q", got, want)
+			}
+			if !policy.record(ProcessingResult{Success: true}) {
+				t.Error("a tripped policy let the run go on")
+			}
+		})
+	}
+}
+
+const failureThresholdConfig = `
+[shows.alpha]
+cue_file_mapping = "MISSING.cue"
+show_name_pattern = "Alpha"
+priority = 4
+enabled = true
+
+[shows.beta]
+cue_file_mapping = "MISSING.cue"
+show_name_pattern = "Beta"
+priority = 3
+enabled = true
+
+[shows.gamma]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Gamma"
+priority = 2
+enabled = true
+
+[shows.delta]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Delta"
+priority = 1
+enabled = true
+`
+
+func TestBatchRunStopsAtFailureThreshold(t *testing.T) {
+	sp := newTestProcessor(t, failureThresholdConfig)
+	sp.config.Processing.MaxConsecutiveFailures = 2
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	err := sp.ProcessAllShows(false)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Abort == nil {
+		t.Fatalf("ProcessAllShows() error = %v, want a BatchError stopped at the threshold", err)
+	}
+	if !strings.Contains(err.Error(), "stopped at max_consecutive_failures (2)") {
+		t.Errorf("error = %q, want the threshold named", err)
+	}
+	if batchErr.Abort.ShowKey != "beta" || !strings.Contains(batchErr.Abort.Error.Error(), "CUE file") {
+		t.Errorf("Abort = %v, want beta's missing CUE file quoted", batchErr.Abort)
+	}
+
+	run := sp.LastRun()
+	if run.FailedShows != 2 || strings.Join(run.ThresholdSkipped, ",") != "gamma,delta" || run.NotAttemptedShows != 2 {
+		t.Errorf("failed %d, not attempted %v (%d); want 2 failed and gamma, delta not attempted",
+			run.FailedShows, run.ThresholdSkipped, run.NotAttemptedShows)
+	}
+	if len(fake.updates) != 0 {
+		t.Errorf("%d shows updated after the threshold was reached", len(fake.updates))
+	}
+}
+
+func TestFailureThresholdOverriddenFromCommandLine(t *testing.T) {
+	sp := newTestProcessor(t, failureThresholdConfig)
+	sp.config.Processing.MaxConsecutiveFailures = 2
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	never := 0
+	sp.SetOptions(Options{MaxConsecutiveFailures: &never})
+
+	err := sp.ProcessAllShows(false)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Abort != nil {
+		t.Fatalf("ProcessAllShows() error = %v, want failures without a threshold stop", err)
+	}
+	if run := sp.LastRun(); run.FailedShows != 2 || run.SuccessfulShows != 2 || len(run.ThresholdSkipped) != 0 {
+		t.Errorf("failed %d, successful %d, not attempted %v; want every show attempted",
+			run.FailedShows, run.SuccessfulShows, run.ThresholdSkipped)
+	}
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"log/slog"
+	"path/filepath"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/announcement"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: The announcement is loaded once per run, before the first show is
+// rendered, and travels to the formatter in each show's metadata. Failing to
+// load it only logs a warning: the run carries on with the cached copy, or
+// without an announcement when there is none. Offline runs don't fetch URLs.
+
+// announcementLoader returns the loader for station.announcement_source, caching
+// URL responses next to the state file
+func (sp *ShowProcessor) announcementLoader() announcement.Loader {
+	return announcement.Loader{
+		Client:    sp.httpClient,
+		CachePath: filepath.Join(filepath.Dir(sp.state.Path()), announcement.DefaultCacheFilename),
+	}
+}
+
+// loadAnnouncement sets the run's announcement from station.announcement_source
+func (sp *ShowProcessor) loadAnnouncement() {
+	sp.announcement = ""
+	source := sp.config.AnnouncementSourcePath(sp.configPath)
+	if source == "" {
+		return
+	}
+
+	loader := sp.announcementLoader()
+	var loaded announcement.Result
+	var err error
+	if sp.offline() && announcement.IsURL(source) {
+		loaded, err = loader.Cached()
+	} else {
+		loaded, err = loader.Load(sp.runContext(), source)
+	}
+	switch {
+	case err == nil:
+	case loaded.Text == "":
+		sp.logger.Warn("Station announcement unavailable, continuing without it",
+			slog.String("source", source),
+			slog.String("error", err.Error()))
+		console.Printf("⚠️  Station announcement unavailable, continuing without it: %v\n\n", err)
+	case loaded.Cached:
+		sp.logger.Warn("Station announcement unavailable, using the cached copy",
+			slog.String("source", source),
+			slog.String("error", err.Error()))
+		console.Printf("⚠️  Station announcement unavailable, using the cached copy: %v\n\n", err)
+	default:
+		// Fetched fine, only the cache couldn't be written
+		sp.logger.Warn("Failed to cache station announcement",
+			slog.String("source", source),
+			slog.String("error", err.Error()))
+	}
+
+	sp.announcement = loaded.Text
+	sp.logger.Debug("Station announcement loaded",
+		slog.String("source", source),
+		slog.Bool("cached", loaded.Cached),
+		slog.Int("length", len(loaded.Text)))
+}
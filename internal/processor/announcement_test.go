@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/announcement"
+)
+
+const announcementTestConfig = `
+[shows.night-one]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Night One"
+enabled = true
+`
+
+func TestAnnouncementPrependedToClassicDescription(t *testing.T) {
+	sp := newTestProcessor(t, announcementTestConfig)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(sp.configPath), "announcement.txt"), []byte("Pledge drive all week\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sp.config.Station.AnnouncementSource = "announcement.txt"
+	sp.config.Station.AnnouncementAutoPrepend = true
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	if err := sp.ProcessShow("night-one", "", "", false); err != nil {
+		t.Fatalf("ProcessShow() error = %v", err)
+	}
+	if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], nightOneURL+"=Pledge drive all week\n\n") {
+		t.Errorf("updates = %v, want the announcement above the tracks", fake.updates)
+	}
+}
+
+func TestAnnouncementMissingDoesNotFailRun(t *testing.T) {
+	sp := newTestProcessor(t, announcementTestConfig)
+	sp.config.Station.AnnouncementSource = "missing.txt"
+	sp.config.Station.AnnouncementAutoPrepend = true
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	if err := sp.ProcessShow("night-one", "", "", false); err != nil {
+		t.Fatalf("ProcessShow() error = %v", err)
+	}
+	if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], nightOneURL+"=00:25 - ") {
+		t.Errorf("updates = %v, want the tracks alone", fake.updates)
+	}
+}
+
+func TestOfflineAnnouncementUsesCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("Fresh announcement"))
+	}))
+	defer server.Close()
+
+	sp := newTestProcessor(t, announcementTestConfig)
+	sp.config.Station.AnnouncementSource = server.URL
+	sp.httpClient = server.Client()
+	cachePath := filepath.Join(filepath.Dir(sp.state.Path()), announcement.DefaultCacheFilename)
+	if err := os.WriteFile(cachePath, []byte("Cached announcement"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sp.SetOptions(Options{Offline: true})
+	sp.loadAnnouncement()
+	if sp.announcement != "Cached announcement" || requests != 0 {
+		t.Errorf("offline announcement = %q after %d requests, want the cached copy without fetching", sp.announcement, requests)
+	}
+
+	sp.SetOptions(Options{})
+	sp.loadAnnouncement()
+	if sp.announcement != "Fresh announcement" || requests != 1 {
+		t.Errorf("announcement = %q after %d requests, want it fetched", sp.announcement, requests)
+	}
+}
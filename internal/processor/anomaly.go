@@ -0,0 +1,171 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// AIDEV-NOTE: A CUE export that stopped halfway or a filter rule that matches
+// too much still yields a valid description, just a much shorter one than the
+// show usually gets. The only reference is the show's own publish history, so
+// this check compares against the median of its recent publishes, which one
+// odd episode can't skew. Shows without history and placeholder publishes
+// aren't checked; migrated entries carry a track count but no description
+// length, so each median only counts the entries that recorded its value.
+
+// Values for AnomalyCheck.Verdict
+const (
+	AnomalyNormal = "normal"
+	AnomalyShrunk = "shrunk"
+)
+
+// AnomalyCheck compares a show's output with its recent publishes
+// (anomaly_threshold_percent)
+type AnomalyCheck struct {
+	Runs             int     // History entries compared with
+	Tracks           int     // Tracks published after filtering
+	MedianTracks     float64 // Median of the compared entries' tracks
+	Length           int     // Formatted description length
+	MedianLength     float64 // 0 when no entry recorded a description length
+	ThresholdPercent int
+	Verdict          string // AnomalyNormal or AnomalyShrunk
+}
+
+// String describes the check, e.g. "9 tracks vs median 35 (26%), 1180 chars vs
+// median 4102 (29%) over 5 publishes, threshold 60% (shrunk)"
+func (c AnomalyCheck) String() string {
+	var parts []string
+	if c.MedianTracks > 0 {
+		parts = append(parts, fmt.Sprintf("%d tracks vs median %g (%.0f%%)",
+			c.Tracks, c.MedianTracks, float64(c.Tracks)/c.MedianTracks*100))
+	}
+	if c.MedianLength > 0 {
+		parts = append(parts, fmt.Sprintf("%d chars vs median %g (%.0f%%)",
+			c.Length, c.MedianLength, float64(c.Length)/c.MedianLength*100))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "nothing recorded to compare with")
+	}
+	return fmt.Sprintf("%s over %d publishes, threshold %d%% (%s)",
+		strings.Join(parts, ", "), c.Runs, c.ThresholdPercent, c.Verdict)
+}
+
+// median returns the median of values, 0 for none
+func median(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[middle])
+	}
+	return float64(sorted[middle-1]+sorted[middle]) / 2
+}
+
+// belowThreshold reports whether value is under thresholdPercent of reference.
+// A reference of 0 has nothing to fall below.
+func belowThreshold(value int, reference float64, thresholdPercent int) bool {
+	return reference > 0 && float64(value)*100 < reference*float64(thresholdPercent)
+}
+
+// compareWithHistory checks tracks and length against the medians of entries,
+// a show's recent publishes. Either value under thresholdPercent of its median
+// makes the output AnomalyShrunk; a median of 0 (no entries) can't be undercut.
+func compareWithHistory(tracks, length int, entries []state.HistoryEntry, thresholdPercent int) AnomalyCheck {
+	check := AnomalyCheck{
+		Runs:             len(entries),
+		Tracks:           tracks,
+		Length:           length,
+		ThresholdPercent: thresholdPercent,
+		Verdict:          AnomalyNormal,
+	}
+
+	var trackCounts, lengths []int
+	for _, entry := range entries {
+		trackCounts = append(trackCounts, entry.Tracks)
+		if entry.DescriptionLength > 0 {
+			lengths = append(lengths, entry.DescriptionLength)
+		}
+	}
+	check.MedianTracks = median(trackCounts)
+	check.MedianLength = median(lengths)
+	if belowThreshold(tracks, check.MedianTracks, thresholdPercent) || belowThreshold(length, check.MedianLength, thresholdPercent) {
+		check.Verdict = AnomalyShrunk
+	}
+	return check
+}
+
+// loadHistory returns the publish history, loading the history file on first use
+func (sp *ShowProcessor) loadHistory() (*state.History, error) {
+	if sp.history != nil {
+		return sp.history, nil
+	}
+	history, err := state.LoadHistory(state.ResolveHistoryPath(sp.config.Processing.HistoryFile, sp.configPath), sp.state)
+	if err != nil {
+		return history, err
+	}
+	sp.history = history
+	return history, nil
+}
+
+// checkAnomaly compares the formatted show with its recent publishes. A show
+// that shrank is logged and pointed out, and with anomaly_action = "fail"
+// returned as an error so it isn't published.
+func (sp *ShowProcessor) checkAnomaly(result *ProcessingResult) error {
+	if result.Placeholder {
+		return nil
+	}
+	history, err := sp.loadHistory()
+	if err != nil {
+		sp.logger.Warn("Failed to load history file, not comparing with earlier publishes",
+			slog.String("show_key", result.ShowKey),
+			slog.String("path", history.Path()),
+			slog.String("error", err.Error()))
+		return nil
+	}
+
+	processing := sp.config.Processing
+	runs := processing.AnomalyHistoryRuns
+	if runs <= 0 {
+		runs = constants.DefaultAnomalyHistoryRuns
+	}
+	threshold := processing.AnomalyThresholdPercent
+	if threshold <= 0 {
+		threshold = constants.DefaultAnomalyThresholdPercent
+	}
+	entries := history.Entries(result.ShowKey, runs)
+	if len(entries) == 0 {
+		// A new show has nothing to compare with
+		return nil
+	}
+	check := compareWithHistory(result.FilteredTracks, result.FormattedLength, entries, threshold)
+	result.Anomaly = &check
+
+	if check.Verdict != AnomalyShrunk {
+		sp.logger.Debug("Output compared with earlier publishes",
+			slog.String("show_key", result.ShowKey),
+			slog.String("anomaly_check", check.String()))
+		return nil
+	}
+
+	sp.logger.Warn("Output is much smaller than the show's recent publishes, the CUE file may be incomplete",
+		slog.String("show_key", result.ShowKey),
+		slog.String("cue_file", result.CueFile),
+		slog.Int("anomaly_threshold_percent", threshold),
+		slog.String("anomaly_check", check.String()))
+	console.Warnf("⚠️  %s: output much smaller than recent publishes: %s\n", result.ShowKey, check)
+	if processing.AnomalyCheckAction() == config.GapActionFail {
+		return &SourceError{Err: fmt.Errorf("output shrank below anomaly_threshold_percent (%d%%) of recent publishes: %s",
+			threshold, check)}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// historyOf returns entries with the given track counts and description lengths
+func historyOf(tracks []int, lengths []int) []state.HistoryEntry {
+	entries := make([]state.HistoryEntry, len(tracks))
+	for i := range tracks {
+		entries[i] = state.HistoryEntry{Tracks: tracks[i], DescriptionLength: lengths[i]}
+	}
+	return entries
+}
+
+func TestCompareWithHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		tracks      int
+		length      int
+		entries     []state.HistoryEntry
+		threshold   int
+		wantVerdict string
+		wantTracks  float64
+		wantLength  float64
+	}{
+		{
+			name:        "no history",
+			tracks:      3,
+			length:      100,
+			threshold:   60,
+			wantVerdict: AnomalyNormal,
+		},
+		{
+			name:        "same as usual",
+			tracks:      20,
+			length:      2000,
+			entries:     historyOf([]int{20, 20, 20}, []int{2000, 2000, 2000}),
+			threshold:   60,
+			wantVerdict: AnomalyNormal,
+			wantTracks:  20,
+			wantLength:  2000,
+		},
+		{
+			name:        "tracks exactly at the threshold",
+			tracks:      12,
+			length:      2000,
+			entries:     historyOf([]int{20, 20, 20}, []int{2000, 2000, 2000}),
+			threshold:   60,
+			wantVerdict: AnomalyNormal,
+			wantTracks:  20,
+			wantLength:  2000,
+		},
+		{
+			name:        "tracks one below the threshold",
+			tracks:      11,
+			length:      2000,
+			entries:     historyOf([]int{20, 20, 20}, []int{2000, 2000, 2000}),
+			threshold:   60,
+			wantVerdict: AnomalyShrunk,
+			wantTracks:  20,
+			wantLength:  2000,
+		},
+		{
+			name:        "length exactly at the threshold",
+			tracks:      20,
+			length:      1200,
+			entries:     historyOf([]int{20, 20, 20}, []int{2000, 2000, 2000}),
+			threshold:   60,
+			wantVerdict: AnomalyNormal,
+			wantTracks:  20,
+			wantLength:  2000,
+		},
+		{
+			name:        "length one below the threshold",
+			tracks:      20,
+			length:      1199,
+			entries:     historyOf([]int{20, 20, 20}, []int{2000, 2000, 2000}),
+			threshold:   60,
+			wantVerdict: AnomalyShrunk,
+			wantTracks:  20,
+			wantLength:  2000,
+		},
+		{
+			name:        "median ignores one odd publish",
+			tracks:      11,
+			length:      1100,
+			entries:     historyOf([]int{12, 60, 12}, []int{1200, 6000, 1200}),
+			threshold:   60,
+			wantVerdict: AnomalyNormal,
+			wantTracks:  12,
+			wantLength:  1200,
+		},
+		{
+			name:        "even count averages the middle pair",
+			tracks:      8,
+			length:      1000,
+			entries:     historyOf([]int{10, 14, 16, 30}, []int{1000, 1000, 1000, 1000}),
+			threshold:   60,
+			wantVerdict: AnomalyShrunk, // 8 < 60% of 15
+			wantTracks:  15,
+			wantLength:  1000,
+		},
+		{
+			name:        "migrated entries have no description length",
+			tracks:      20,
+			length:      100,
+			entries:     historyOf([]int{20, 20}, []int{0, 0}),
+			threshold:   60,
+			wantVerdict: AnomalyNormal,
+			wantTracks:  20,
+		},
+		{
+			name:        "lower threshold tolerates more shrinkage",
+			tracks:      11,
+			length:      1100,
+			entries:     historyOf([]int{20, 20, 20}, []int{2000, 2000, 2000}),
+			threshold:   50,
+			wantVerdict: AnomalyNormal,
+			wantTracks:  20,
+			wantLength:  2000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := compareWithHistory(tt.tracks, tt.length, tt.entries, tt.threshold)
+			if check.Verdict != tt.wantVerdict {
+				t.Errorf("Verdict = %q, want %q (%s)", check.Verdict, tt.wantVerdict, check)
+			}
+			if check.MedianTracks != tt.wantTracks || check.MedianLength != tt.wantLength {
+				t.Errorf("medians = %g tracks, %g chars; want %g, %g",
+					check.MedianTracks, check.MedianLength, tt.wantTracks, tt.wantLength)
+			}
+			if check.Runs != len(tt.entries) {
+				t.Errorf("Runs = %d, want %d", check.Runs, len(tt.entries))
+			}
+		})
+	}
+}
+
+const anomalyTestConfig = `
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault"
+enabled = true
+
+[shows.fresh]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Fresh"
+enabled = true
+`
+
+// seedHistory records publishes of 10 tracks for showKey in sp's history file
+func seedHistory(t *testing.T, sp *ShowProcessor, showKey string, publishes int) {
+	t.Helper()
+	history, err := state.LoadHistory(state.DefaultHistoryPath(sp.configPath), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < publishes; i++ {
+		history.Append(showKey, state.HistoryEntry{
+			PublishedAt:       time.Date(2025, 6, 7+7*i, 22, 0, 0, 0, time.UTC),
+			Tracks:            10,
+			DescriptionLength: 400,
+		}, 0)
+	}
+	if err := history.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnomalyCheckWarns(t *testing.T) {
+	sp := newTestProcessor(t, anomalyTestConfig)
+	seedHistory(t, sp, "vault", 3)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	if err := sp.ProcessAllShows(false); err != nil {
+		t.Fatalf("ProcessAllShows() error = %v", err)
+	}
+	run := sp.LastRun()
+	if run.SuccessfulShows != 2 || len(fake.updates) != 2 {
+		t.Fatalf("successful %d, updates %v; want both shows published", run.SuccessfulShows, fake.updates)
+	}
+
+	var summary logger.ExecutionSummary
+	run.Summarize(&summary)
+	for _, outcome := range summary.Shows {
+		want := "" // fresh has no history to compare with
+		if outcome.ShowKey == "vault" {
+			want = AnomalyShrunk // 3 tracks against a median of 10
+		}
+		if outcome.Anomaly != want || (want != "") != (outcome.AnomalyDetail != "") {
+			t.Errorf("%s anomaly = %q (%s), want %q", outcome.ShowKey, outcome.Anomaly, outcome.AnomalyDetail, want)
+		}
+	}
+}
+
+func TestAnomalyCheckFailHoldsUpdate(t *testing.T) {
+	sp := newTestProcessor(t, anomalyTestConfig)
+	sp.config.Processing.AnomalyAction = "fail"
+	seedHistory(t, sp, "vault", 3)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	if err := sp.ProcessAllShows(false); err == nil {
+		t.Fatal("ProcessAllShows() succeeded with the shrunk show held")
+	}
+	if len(fake.updates) != 1 || !strings.Contains(fake.updates[0], "fresh") {
+		t.Errorf("updates = %v, want only fresh", fake.updates)
+	}
+	for _, result := range sp.LastRun().Results {
+		if result.ShowKey != "vault" {
+			continue
+		}
+		var sourceErr *SourceError
+		if !errors.As(result.Error, &sourceErr) || result.Anomaly == nil || result.Anomaly.Verdict != AnomalyShrunk {
+			t.Errorf("vault error = %v, anomaly = %v; want a source error for a shrunk show", result.Error, result.Anomaly)
+		}
+	}
+}
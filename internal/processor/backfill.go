@@ -0,0 +1,187 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+)
+
+// AIDEV-NOTE: Backfill publishes a show's archive of past episodes through the
+// normal pipeline. Each episode is processed as the show with its CUE file
+// mapped directly and its air date as the -date override, so names, URLs and
+// update fields come out exactly as they would have on the night.
+
+// RunModeBackfill is reported by run_started for -backfill runs
+const RunModeBackfill = "backfill"
+
+// BackfillOptions limits which archived episodes a backfill processes
+type BackfillOptions struct {
+	Dir   string    // Searched instead of the show's CUE directory
+	Since time.Time // Episodes dated before this are left out (zero = all)
+	Limit int       // Most episodes processed, oldest first (0 = all)
+}
+
+// BackfillEpisode is the outcome of one archived episode
+type BackfillEpisode struct {
+	shows.Episode
+	Result ProcessingResult
+}
+
+// ProcessBackfill processes every archived episode of a show, oldest first.
+// A failed episode doesn't stop the backfill; a per-episode report is printed at the end.
+func (sp *ShowProcessor) ProcessBackfill(nameOrAlias string, opts BackfillOptions, dryRun bool) error {
+	startTime := time.Now()
+
+	showCfg := sp.resolver.FindShowConfig(nameOrAlias)
+	if showCfg == nil {
+		return sp.resolver.NotFoundError(nameOrAlias)
+	}
+	showKey := sp.resolver.FindShowKey(nameOrAlias)
+
+	episodes, err := sp.cueResolver.FindEpisodes(showCfg, opts.Dir)
+	if err != nil {
+		return fmt.Errorf("finding episodes of %s: %w", showKey, err)
+	}
+	found := len(episodes)
+	episodes, tooOld, overLimit := selectEpisodes(episodes, opts)
+
+	console.Printf("Backfilling %s: %d episodes\n", showKey, len(episodes))
+	console.Printf("============================\n")
+	console.Printf("Found %d CUE files", found)
+	if tooOld > 0 {
+		console.Printf(", %d before %s", tooOld, opts.Since.Format("2006-01-02"))
+	}
+	if overLimit > 0 {
+		console.Printf(", %d left for a later run (-backfill-limit %d)", overLimit, opts.Limit)
+	}
+	console.Printf("\n\n")
+
+	sp.logger.Info("Starting backfill",
+		slog.String("show_key", showKey),
+		slog.Int("found", found),
+		slog.Int("episodes", len(episodes)),
+		slog.Int("before_since", tooOld),
+		slog.Int("over_limit", overLimit),
+		slog.Bool("dry_run", dryRun))
+	if !dryRun && len(episodes) > 1 && sp.config.Processing.MinUpdateIntervalSeconds <= 0 {
+		sp.logger.Warn("Backfill without rate pacing, Mixcloud may throttle the updates")
+		console.Printf("⚠️  min_update_interval_seconds is 0 - set it to pace %d updates and avoid Mixcloud throttling\n\n", len(episodes))
+	}
+
+	defer sp.startRun()()
+	sp.loadAnnouncement()
+	sp.emitRunStarted(RunModeBackfill, showKey, len(episodes), dryRun)
+	batchResult := &BatchResult{
+		TotalShows: len(episodes),
+		Results:    make([]ProcessingResult, 0, len(episodes)),
+	}
+
+	abort := sp.newAbortPolicy()
+	report := make([]BackfillEpisode, 0, len(episodes))
+	for i, episode := range episodes {
+		if sp.deadlineExceeded() || abort.trigger != nil {
+			remaining := make([]string, 0, len(episodes)-i)
+			for _, left := range episodes[i:] {
+				remaining = append(remaining, showKey+"@"+left.Date.Format("2006-01-02"))
+			}
+			if abort.trigger != nil {
+				sp.stopAtFailureThreshold(batchResult, abort.trigger, remaining)
+			} else {
+				sp.stopAtDeadline(batchResult, remaining)
+			}
+			break
+		}
+
+		if !episode.DateFromName {
+			sp.logger.Warn("No date in CUE file name, using its modification time",
+				slog.String("file", episode.CueFile),
+				slog.String("date", episode.Date.Format("2006-01-02")))
+		}
+
+		// The episode's own CUE file, and its air date in place of today's
+		episodeCfg := *showCfg
+		episodeCfg.CueFileMapping = episode.CueFile
+		episodeCfg.CueFilePattern = ""
+
+		startEpisode := time.Now()
+		result := sp.processingleShow(showKey, &episodeCfg, "", episode.Date.Format("01/02/2006"), dryRun)
+		result.Duration = time.Since(startEpisode)
+
+		batchResult.add(result)
+		sp.emitShowFinished(result)
+		sp.printBatchLine(result)
+		report = append(report, BackfillEpisode{Episode: episode, Result: result})
+		abort.record(result)
+	}
+
+	batchResult.TotalDuration = time.Since(startTime)
+	printBackfillReport(showKey, report)
+	return sp.finishBatch(batchResult)
+}
+
+// selectEpisodes applies Since and Limit to episodes sorted oldest first,
+// returning the episodes kept and how many each guard left out
+func selectEpisodes(episodes []shows.Episode, opts BackfillOptions) (kept []shows.Episode, tooOld, overLimit int) {
+	kept = episodes
+	if !opts.Since.IsZero() {
+		kept = kept[:0:0]
+		for _, episode := range episodes {
+			if episode.Date.Before(opts.Since) {
+				tooOld++
+				continue
+			}
+			kept = append(kept, episode)
+		}
+	}
+	if opts.Limit > 0 && len(kept) > opts.Limit {
+		overLimit = len(kept) - opts.Limit
+		kept = kept[:opts.Limit]
+	}
+	return kept, tooOld, overLimit
+}
+
+// printBackfillReport prints one line per episode: date, status and URL
+func printBackfillReport(showKey string, report []BackfillEpisode) {
+	console.Printf("\nBackfill Report: %s\n", showKey)
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	tw := tabwriter.NewWriter(console.Chrome, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "DATE\tSTATUS\tURL\n")
+	for _, episode := range report {
+		url := episode.Result.ShowURL
+		if url == "" {
+			url = "(" + filepath.Base(episode.CueFile) + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", episode.Date.Format("2006-01-02"), backfillStatus(episode.Result), url)
+	}
+	tw.Flush()
+
+	for _, episode := range report {
+		if episode.Result.Error != nil {
+			console.Printf("• %s: %v\n", episode.Date.Format("2006-01-02"), episode.Result.Error)
+		}
+	}
+}
+
+// backfillStatus is the report's one-word outcome of an episode
+func backfillStatus(result ProcessingResult) string {
+	switch {
+	case result.Error != nil:
+		return "failed [" + string(result.FailureCategory) + "]"
+	case result.DryRun && result.Success:
+		return "dry-run"
+	case result.Success && result.Placeholder:
+		return "updated (placeholder)"
+	case result.Success:
+		return "updated"
+	case result.Skipped:
+		return "skipped: " + strings.TrimSpace(result.SkipReason)
+	default:
+		return "skipped"
+	}
+}
@@ -0,0 +1,139 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+)
+
+const backfillTestConfig = `
+[shows.myr]
+cue_file_pattern = "MYR_*.cue"
+show_name_pattern = "Myriad - {date}"
+date_format = "Jan 2, 2006"
+enabled = true
+`
+
+// newBackfillTestProcessor writes dated CUE files for the myr show into the CUE directory
+func newBackfillTestProcessor(t *testing.T, dates ...string) (*ShowProcessor, *fakeMixcloud) {
+	t.Helper()
+	return newMyrTestProcessor(t, backfillTestConfig, dates...)
+}
+
+// newMyrTestProcessor is newBackfillTestProcessor with the myr show configured by tomlBody
+func newMyrTestProcessor(t *testing.T, tomlBody string, dates ...string) (*ShowProcessor, *fakeMixcloud) {
+	t.Helper()
+	sp := newTestProcessor(t, tomlBody)
+	for _, date := range dates {
+		path := filepath.Join(sp.config.Processing.CueFileDirectory, "MYR_"+date+".cue")
+		if err := os.WriteFile(path, []byte(testCueContent), 0644); err != nil {
+			t.Fatalf("writing CUE file: %v", err)
+		}
+	}
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	return sp, fake
+}
+
+func myrURL(date string) string {
+	return mixcloud.GenerateShowURL("testuser", "Myriad - "+date)
+}
+
+// updatedURLs returns the URLs of the fake's updates, in order
+func updatedURLs(fake *fakeMixcloud) []string {
+	urls := make([]string, len(fake.updates))
+	for i, update := range fake.updates {
+		urls[i] = strings.SplitN(update, "=", 2)[0]
+	}
+	return urls
+}
+
+func TestProcessBackfill(t *testing.T) {
+	dates := []string{"20250712", "20250628", "20250705"}
+
+	tests := []struct {
+		name     string
+		opts     BackfillOptions
+		wantURLs []string
+	}{
+		{
+			name:     "all episodes oldest first",
+			wantURLs: []string{myrURL("Jun 28, 2025"), myrURL("Jul 5, 2025"), myrURL("Jul 12, 2025")},
+		},
+		{
+			name:     "since",
+			opts:     BackfillOptions{Since: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+			wantURLs: []string{myrURL("Jul 5, 2025"), myrURL("Jul 12, 2025")},
+		},
+		{
+			name:     "limit takes the oldest",
+			opts:     BackfillOptions{Limit: 2},
+			wantURLs: []string{myrURL("Jun 28, 2025"), myrURL("Jul 5, 2025")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, fake := newBackfillTestProcessor(t, dates...)
+			if err := sp.ProcessBackfill("myr", tt.opts, false); err != nil {
+				t.Fatalf("ProcessBackfill() error = %v", err)
+			}
+			got := updatedURLs(fake)
+			if strings.Join(got, "\n") != strings.Join(tt.wantURLs, "\n") {
+				t.Errorf("updated URLs = %v, want %v", got, tt.wantURLs)
+			}
+		})
+	}
+}
+
+// TestProcessBackfillShowDate checks {{.ShowDate}} is each episode's air date, not today
+func TestProcessBackfillShowDate(t *testing.T) {
+	sp, fake := newMyrTestProcessor(t, backfillTestConfig+`template = "dated"
+
+[templates.config.dated]
+header = "Aired {{.ShowDate}}\n"
+track = "{{.Artist}} - {{.Title}}\n"
+`, "20250628", "20250705")
+	if err := sp.ProcessBackfill("myr", BackfillOptions{}, false); err != nil {
+		t.Fatalf("ProcessBackfill() error = %v", err)
+	}
+
+	want := []string{"Aired June 28, 2025", "Aired July 5, 2025"}
+	if len(fake.updates) != len(want) {
+		t.Fatalf("updates = %v, want %d", fake.updates, len(want))
+	}
+	for i, update := range fake.updates {
+		if description := strings.SplitN(update, "=", 2)[1]; !strings.HasPrefix(description, want[i]+"\n") {
+			t.Errorf("episode %d description = %q, want it to start with %q", i+1, description, want[i])
+		}
+	}
+}
+
+func TestProcessBackfillContinuesPastFailure(t *testing.T) {
+	sp, fake := newBackfillTestProcessor(t, "20250628", "20250705", "20250712")
+	fake.missing[myrURL("Jul 5, 2025")] = true
+
+	err := sp.ProcessBackfill("myr", BackfillOptions{}, false)
+	if err == nil {
+		t.Fatal("ProcessBackfill() error = nil, want the failed episode reported")
+	}
+	want := []string{myrURL("Jun 28, 2025"), myrURL("Jul 12, 2025")}
+	if got := updatedURLs(fake); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("updated URLs = %v, want %v", got, want)
+	}
+}
+
+func TestProcessBackfillUnknownShow(t *testing.T) {
+	sp, _ := newBackfillTestProcessor(t)
+
+	var notFound *shows.NotFoundError
+	if err := sp.ProcessBackfill("nope", BackfillOptions{}, false); !errors.As(err, &notFound) {
+		t.Errorf("ProcessBackfill() error = %v, want NotFoundError", err)
+	}
+}
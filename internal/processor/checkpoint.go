@@ -0,0 +1,147 @@
+package processor
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// AIDEV-NOTE: Only live all-shows runs keep a checkpoint. Without -resume an
+// interrupted run is pointed out and every show is processed as before, so a
+// cron job that never passes the flag behaves exactly as it did. A checkpoint
+// that can't be written costs only the ability to resume, so save errors are
+// logged and the run goes on.
+
+// resumeWindow returns how long an interrupted run can be resumed
+func (sp *ShowProcessor) resumeWindow() time.Duration {
+	hours := sp.config.Processing.ResumeWindowHours
+	if hours <= 0 {
+		hours = constants.DefaultResumeWindowHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// checkpointPath returns the checkpoint file of this config
+func (sp *ShowProcessor) checkpointPath() string {
+	return state.ResolveCheckpointPath(sp.config.Processing.CheckpointFile, sp.configPath)
+}
+
+// interruptedRun returns the checkpoint of a run that was interrupted within
+// the resume window, nil when there is none
+func (sp *ShowProcessor) interruptedRun() *state.Checkpoint {
+	checkpoint, err := state.LoadCheckpoint(sp.checkpointPath())
+	if err != nil {
+		sp.logger.Warn("Ignoring unreadable checkpoint file",
+			slog.String("error", err.Error()))
+		return nil
+	}
+	if checkpoint == nil || !checkpoint.Interrupted(time.Now(), sp.resumeWindow()) {
+		return nil
+	}
+	return checkpoint
+}
+
+// resumeRun returns the shows an interrupted run published, which -resume
+// carries over instead of processing again. A live resumed run goes on
+// recording into the interrupted run's checkpoint. Without -resume an
+// interrupted run is only pointed out.
+func (sp *ShowProcessor) resumeRun(dryRun bool) map[string]state.CheckpointEntry {
+	if sp.options.NoResume {
+		return nil
+	}
+
+	checkpoint := sp.interruptedRun()
+	if checkpoint == nil {
+		if sp.options.Resume {
+			console.Printf("No interrupted run to resume, processing every show\n\n")
+		}
+		return nil
+	}
+
+	published := checkpoint.Published()
+	if !sp.options.Resume {
+		if len(published) > 0 {
+			console.Warnf("⚠️  Run %s was interrupted after publishing %d of %d shows. "+
+				"Use -resume to skip them or -no-resume to start over.\n\n",
+				checkpoint.RunID, len(published), len(checkpoint.Shows))
+		}
+		return nil
+	}
+
+	sp.logger.Info("Resuming interrupted run",
+		slog.String("run_id", checkpoint.RunID),
+		slog.String("checkpoint_file", checkpoint.Path()),
+		slog.Int("carried_over", len(published)))
+	console.Printf("Resuming run %s: %d shows already published\n", checkpoint.RunID, len(published))
+	if !dryRun {
+		sp.checkpoint = checkpoint
+	}
+	return published
+}
+
+// startCheckpoint starts recording a live run that isn't resuming one
+func (sp *ShowProcessor) startCheckpoint(startedAt time.Time, showKeys []string, dryRun bool) {
+	if dryRun || sp.checkpoint != nil {
+		return
+	}
+	sp.checkpoint = state.NewCheckpoint(sp.checkpointPath(), startedAt, showKeys)
+	if err := sp.checkpoint.Save(); err != nil {
+		sp.logger.Warn("Failed to write checkpoint, this run can't be resumed",
+			slog.String("error", err.Error()))
+	}
+}
+
+// recordCheckpoint adds a finished show to the run's checkpoint
+func (sp *ShowProcessor) recordCheckpoint(result ProcessingResult) {
+	if sp.checkpoint == nil {
+		return
+	}
+	entry := state.CheckpointEntry{
+		ShowKey:    result.ShowKey,
+		Status:     resultStatus(result),
+		ShowURL:    result.ShowURL,
+		FinishedAt: time.Now(),
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	if err := sp.checkpoint.Record(entry); err != nil {
+		sp.logger.Warn("Failed to update checkpoint",
+			slog.String("show_key", result.ShowKey),
+			slog.String("error", err.Error()))
+	}
+}
+
+// finishCheckpoint marks the run's checkpoint complete, so it is no longer
+// offered for -resume
+func (sp *ShowProcessor) finishCheckpoint() {
+	if sp.checkpoint == nil {
+		return
+	}
+	if err := sp.checkpoint.Finish(); err != nil {
+		sp.logger.Warn("Failed to complete checkpoint",
+			slog.String("error", err.Error()))
+	}
+	sp.checkpoint = nil
+}
+
+// carryOver adds the shows an interrupted run published as carried-over
+// successes and returns the shows left to process
+func (br *BatchResult) carryOver(showKeys []string, published map[string]state.CheckpointEntry) []string {
+	if len(published) == 0 {
+		return showKeys
+	}
+	remaining := make([]string, 0, len(showKeys))
+	for _, showKey := range showKeys {
+		entry, ok := published[showKey]
+		if !ok {
+			remaining = append(remaining, showKey)
+			continue
+		}
+		br.add(ProcessingResult{ShowKey: showKey, ShowURL: entry.ShowURL, Success: true, CarriedOver: true})
+	}
+	return remaining
+}
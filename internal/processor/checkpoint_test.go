@@ -0,0 +1,180 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+const checkpointTestConfig = `
+[shows.alpha]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Alpha"
+priority = 3
+enabled = true
+
+[shows.beta]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Beta"
+priority = 2
+enabled = true
+
+[shows.gamma]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Gamma"
+priority = 1
+enabled = true
+`
+
+// errProcessKilled is what crashingMixcloud panics with
+type errProcessKilled struct{}
+
+// crashingMixcloud stands in for the process dying: the update after the
+// first `after` panics before reaching Mixcloud
+type crashingMixcloud struct {
+	*fakeMixcloud
+	after int
+}
+
+func (c *crashingMixcloud) UpdateShowContext(ctx context.Context, showURL string, fields map[string]string) error {
+	if len(c.updates) == c.after {
+		panic(errProcessKilled{})
+	}
+	return c.fakeMixcloud.UpdateShowContext(ctx, showURL, fields)
+}
+
+// interruptRun runs every show of sp, killing the run after two shows are published
+func interruptRun(t *testing.T, sp *ShowProcessor) {
+	t.Helper()
+	sp.mixcloud = &crashingMixcloud{fakeMixcloud: newFakeMixcloud(), after: 2}
+	defer func() {
+		if _, ok := recover().(errProcessKilled); !ok {
+			t.Fatal("run wasn't interrupted")
+		}
+	}()
+	sp.ProcessAllShows(false)
+}
+
+// rerun processes every show of sp's config again with options, as the next
+// invocation would
+func rerun(t *testing.T, sp *ShowProcessor, options Options) (*BatchResult, *fakeMixcloud) {
+	t.Helper()
+	next, err := NewShowProcessor(sp.config, sp.configPath)
+	if err != nil {
+		t.Fatalf("NewShowProcessor() error = %v", err)
+	}
+	next.SetOptions(options)
+	fake := newFakeMixcloud()
+	next.mixcloud = fake
+	if err := next.ProcessAllShows(false); err != nil {
+		t.Fatalf("ProcessAllShows() error = %v", err)
+	}
+	return next.LastRun(), fake
+}
+
+func TestInterruptedRunLeavesCheckpoint(t *testing.T) {
+	sp := newTestProcessor(t, checkpointTestConfig)
+	interruptRun(t, sp)
+
+	checkpoint, err := state.LoadCheckpoint(sp.checkpointPath())
+	if err != nil || checkpoint == nil {
+		t.Fatalf("LoadCheckpoint() = %v, %v, want the interrupted run", checkpoint, err)
+	}
+	if checkpoint.Complete {
+		t.Error("interrupted run's checkpoint is complete")
+	}
+	if got := strings.Join(checkpoint.Shows, ","); got != "alpha,beta,gamma" {
+		t.Errorf("checkpoint shows = %s, want alpha,beta,gamma", got)
+	}
+	published := checkpoint.Published()
+	if len(published) != 2 || published["alpha"].ShowURL == "" || published["beta"].ShowURL == "" {
+		t.Errorf("Published() = %+v, want alpha and beta with their URLs", published)
+	}
+}
+
+func TestResumeInterruptedRun(t *testing.T) {
+	sp := newTestProcessor(t, checkpointTestConfig)
+	interruptRun(t, sp)
+	interrupted, _ := state.LoadCheckpoint(sp.checkpointPath())
+
+	run, fake := rerun(t, sp, Options{Resume: true})
+
+	if len(fake.updates) != 1 || !strings.Contains(fake.updates[0], "gamma") {
+		t.Errorf("updates = %v, want only gamma", fake.updates)
+	}
+	if run.TotalShows != 3 || run.SuccessfulShows != 3 || run.CarriedOverShows != 2 || run.ProcessedShows != 1 {
+		t.Errorf("run = total %d, successful %d, carried over %d, processed %d; want 3, 3, 2, 1",
+			run.TotalShows, run.SuccessfulShows, run.CarriedOverShows, run.ProcessedShows)
+	}
+
+	var summary logger.ExecutionSummary
+	run.Summarize(&summary)
+	if summary.Totals.CarriedOver != 2 {
+		t.Errorf("summary carried over = %d, want 2", summary.Totals.CarriedOver)
+	}
+	for _, show := range summary.Shows {
+		if show.Status != logger.ShowSuccess || show.CarriedOver != (show.ShowKey != "gamma") {
+			t.Errorf("summary outcome %+v, want success carried over for alpha and beta only", show)
+		}
+	}
+
+	resumed, _ := state.LoadCheckpoint(sp.checkpointPath())
+	if !resumed.Complete || resumed.RunID != interrupted.RunID || len(resumed.Published()) != 3 {
+		t.Errorf("checkpoint after resuming = %+v, want run %s complete with 3 shows", resumed, interrupted.RunID)
+	}
+}
+
+func TestInterruptedRunNotResumed(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		stale   bool
+	}{
+		{"without -resume", Options{}, false},
+		{"with -no-resume", Options{NoResume: true}, false},
+		{"outside the resume window", Options{Resume: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, checkpointTestConfig)
+			interruptRun(t, sp)
+			if tt.stale {
+				sp.config.Processing.ResumeWindowHours = 1
+				checkpoint, _ := state.LoadCheckpoint(sp.checkpointPath())
+				checkpoint.StartedAt = time.Now().Add(-2 * time.Hour)
+				if err := checkpoint.Save(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			run, fake := rerun(t, sp, tt.options)
+
+			if len(fake.updates) != 3 {
+				t.Errorf("updates = %v, want every show", fake.updates)
+			}
+			if run.CarriedOverShows != 0 || run.ProcessedShows != 3 {
+				t.Errorf("carried over %d, processed %d; want 0, 3", run.CarriedOverShows, run.ProcessedShows)
+			}
+			checkpoint, _ := state.LoadCheckpoint(sp.checkpointPath())
+			if !checkpoint.Complete || len(checkpoint.Entries) != 3 {
+				t.Errorf("checkpoint = %+v, want a new complete run of 3 shows", checkpoint)
+			}
+		})
+	}
+}
+
+func TestDryRunKeepsNoCheckpoint(t *testing.T) {
+	sp := newTestProcessor(t, checkpointTestConfig)
+	sp.mixcloud = newFakeMixcloud()
+	if err := sp.ProcessAllShows(true); err != nil {
+		t.Fatalf("ProcessAllShows() error = %v", err)
+	}
+	if checkpoint, err := state.LoadCheckpoint(sp.checkpointPath()); checkpoint != nil || err != nil {
+		t.Errorf("dry run left checkpoint %+v, %v", checkpoint, err)
+	}
+}
@@ -0,0 +1,171 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/desclen"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+	"github.com/nowwaveradio/mixcloud-updater/internal/templatetest"
+)
+
+// AIDEV-NOTE: -compare-templates renders each show once per compared template
+// from the tracks and metadata prepareShow already built, so the CUE file is
+// resolved, parsed and filtered once however many templates are compared. Split
+// shows are compared as one unsplit description.
+
+// TemplateRender is a show rendered with one of the -compare-templates templates
+type TemplateRender struct {
+	Template        string
+	Description     string
+	FormattedLength int
+	RenderedLength  int    // Estimated length once Mixcloud renders it, see desclen.Rendered
+	TracksShown     int    // Tracks that fit before the description was truncated
+	Truncated       bool   // The formatter dropped tracks to fit the limit
+	Problem         string // Why the description couldn't be published as is, e.g. over the limit
+}
+
+// Summary describes the render in one line, e.g.
+// `812 chars (~790 rendered), 42 of 42 tracks, truncated: no`
+func (r TemplateRender) Summary(totalTracks int) string {
+	truncated := "no"
+	if r.Truncated {
+		truncated = "yes"
+	}
+	summary := fmt.Sprintf("%d chars (~%d rendered), %d of %d tracks, truncated: %s",
+		r.FormattedLength, r.RenderedLength, r.TracksShown, totalTracks, truncated)
+	if r.Problem != "" {
+		summary += ", " + r.Problem
+	}
+	return summary
+}
+
+// compareTemplates renders a dry-run show with every -compare-templates
+// template into result.Comparison
+func (sp *ShowProcessor) compareTemplates(result *ProcessingResult, showCfg *config.ShowConfig, tracks []cue.Track, metadata template.Metadata) {
+	showTemplate, err := sp.formatter.SelectTemplateForShow(showCfg)
+	if err != nil {
+		showTemplate = "classic"
+	}
+
+	result.Comparison = make([]TemplateRender, 0, len(sp.options.CompareTemplates))
+	for _, name := range sp.options.CompareTemplates {
+		// The show's own template keeps its per-show options, e.g. compact_separator
+		override := name
+		if name == showTemplate {
+			override = ""
+		}
+		scratch := ProcessingResult{ShowKey: result.ShowKey}
+		description := sp.formatDescription(&scratch, showCfg, override, tracks, result.Placeholder, metadata)
+
+		render := TemplateRender{
+			Template:        name,
+			Description:     description,
+			FormattedLength: len(description),
+			RenderedLength:  desclen.Rendered(description),
+			Truncated:       isDescriptionTruncated(description, result.Messages),
+		}
+		switch {
+		case result.Placeholder:
+		case render.Truncated:
+			render.TracksShown = sp.tracksFitting(showCfg, override, tracks, metadata, result)
+		default:
+			render.TracksShown = len(tracks)
+		}
+		lengthModel := sp.config.DescriptionLengthModel()
+		if length := lengthModel.Length(description); length > showCfg.DescriptionLimit() {
+			render.Problem = fmt.Sprintf("over the %d character limit", showCfg.DescriptionLimit())
+		} else if description == "" {
+			render.Problem = "empty"
+		}
+
+		sp.logger.Info("Template compared",
+			slog.String("show_key", result.ShowKey),
+			slog.String("template", name),
+			slog.Int("length", render.FormattedLength),
+			slog.Int("tracks_shown", render.TracksShown),
+			slog.Bool("truncated", render.Truncated))
+		result.Comparison = append(result.Comparison, render)
+	}
+}
+
+// tracksFitting finds how many tracks a truncated template fits: the most
+// tracks, in display order, that render without truncation
+func (sp *ShowProcessor) tracksFitting(showCfg *config.ShowConfig, override string, tracks []cue.Track, metadata template.Metadata, result *ProcessingResult) int {
+	// Truncation drops tracks from the bottom of the list, which in reverse
+	// order are the oldest
+	leading := func(n int) []cue.Track {
+		if result.TrackOrder == config.TrackOrderReverse {
+			return tracks[len(tracks)-n:]
+		}
+		return tracks[:n]
+	}
+	return sort.Search(len(tracks), func(n int) bool {
+		scratch := ProcessingResult{ShowKey: result.ShowKey}
+		description := sp.formatDescription(&scratch, showCfg, override, leading(n+1), false, metadata)
+		return isDescriptionTruncated(description, result.Messages)
+	})
+}
+
+// printComparison prints each compared render of a dry-run show with its metrics
+func (sp *ShowProcessor) printComparison(result ProcessingResult) {
+	for _, render := range result.Comparison {
+		console.Printf("\nTemplate %s: %s\n", render.Template, render.Summary(result.FilteredTracks))
+		console.Printf("%s\n", previewDivider)
+		console.Printf("%s\n", previewText(render.Description, sp.options.VerbosePreview))
+		console.Printf("%s\n", previewDivider)
+	}
+}
+
+// writeComparison writes a show's compared renders and the diff between the
+// first two to <show key>.txt in the -compare-output directory
+func (sp *ShowProcessor) writeComparison(result ProcessingResult) {
+	if sp.options.CompareOutput == "" || len(result.Comparison) == 0 {
+		return
+	}
+	path := filepath.Join(sp.options.CompareOutput, result.ShowKey+".txt")
+	file, err := os.Create(path)
+	if err == nil {
+		err = writeRenders(file, result)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		sp.logger.Warn("Failed to write template comparison",
+			slog.String("show_key", result.ShowKey),
+			slog.String("path", path),
+			slog.String("error", err.Error()))
+	}
+}
+
+// writeRenders writes each render under a header with its metrics, then a
+// unified diff from the first template's render to the second's
+func writeRenders(w io.Writer, result ProcessingResult) error {
+	if _, err := fmt.Fprintf(w, "=== %s: %s ===\nURL: %s\n\n", result.ShowKey, result.ShowName, result.ShowURL); err != nil {
+		return err
+	}
+	for _, render := range result.Comparison {
+		if _, err := fmt.Fprintf(w, "--- Template %s: %s ---\n%s\n\n",
+			render.Template, render.Summary(result.FilteredTracks), render.Description); err != nil {
+			return err
+		}
+	}
+	if len(result.Comparison) < 2 {
+		return nil
+	}
+	before, after := result.Comparison[0], result.Comparison[1]
+	diff := templatetest.UnifiedDiff(before.Template, after.Template, before.Description, after.Description)
+	if diff == "" {
+		diff = "(identical)\n"
+	}
+	_, err := fmt.Fprintf(w, "--- Diff %s -> %s ---\n%s", before.Template, after.Template, diff)
+	return err
+}
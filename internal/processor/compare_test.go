@@ -0,0 +1,91 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const compareTestConfig = `
+[templates.config.lista]
+header = "{{.ShowTitle}}\n"
+track = "{{.Index}}. {{.Artist}} - {{.Title}}\n"
+
+[shows.noche]
+cue_file_mapping = "NOCHE.cue"
+show_name_pattern = "La Noche"
+enabled = true
+description_max_length = 250
+template = "classic"
+`
+
+func TestCompareTemplates(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxLength     int
+		wantTruncated bool
+	}{
+		{"both fit", 4000, false},
+		{"both truncated", 250, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, compareTestConfig)
+			writeLocaleCue(t, sp.config.Processing.CueFileDirectory, 20)
+			sp.mixcloud = newFakeMixcloud()
+			outDir := t.TempDir()
+			sp.SetOptions(Options{CompareTemplates: []string{"classic", "lista"}, CompareOutput: outDir})
+
+			showCfg := sp.config.Shows["noche"]
+			showCfg.DescriptionMaxLength = tt.maxLength
+			result := sp.processingleShow("noche", &showCfg, "", "", true)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+			if len(result.Comparison) != 2 {
+				t.Fatalf("got %d compared renders, want 2", len(result.Comparison))
+			}
+			if result.Comparison[0].Description != result.Description {
+				t.Error("the show's own template should render exactly its dry-run description")
+			}
+
+			// Each template's last shown track is in its render, the next one isn't
+			lastLine := map[string]string{"classic": "\"Canción %d\"", "lista": "Canción %d\n"}
+			for _, render := range result.Comparison {
+				if render.Truncated != tt.wantTruncated {
+					t.Errorf("%s: Truncated = %v, want %v", render.Template, render.Truncated, tt.wantTruncated)
+				}
+				if !tt.wantTruncated {
+					if render.TracksShown != 20 {
+						t.Errorf("%s: TracksShown = %d, want all 20", render.Template, render.TracksShown)
+					}
+					continue
+				}
+				if render.TracksShown < 1 || render.TracksShown >= 20 {
+					t.Fatalf("%s: TracksShown = %d, want some but not all of 20", render.Template, render.TracksShown)
+				}
+				if !strings.Contains(render.Description, fmt.Sprintf(lastLine[render.Template], render.TracksShown)) ||
+					strings.Contains(render.Description, fmt.Sprintf(lastLine[render.Template], render.TracksShown+1)) {
+					t.Errorf("%s: TracksShown = %d doesn't match the render:\n%s", render.Template, render.TracksShown, render.Description)
+				}
+				if render.FormattedLength > tt.maxLength || render.Problem != "" {
+					t.Errorf("%s: %d chars, problem %q, want it under the %d limit", render.Template, render.FormattedLength, render.Problem, tt.maxLength)
+				}
+			}
+
+			data, err := os.ReadFile(filepath.Join(outDir, "noche.txt"))
+			if err != nil {
+				t.Fatalf("reading -compare-output file: %v", err)
+			}
+			for _, want := range []string{"=== noche: La Noche ===", "--- Template classic: ", "--- Template lista: ",
+				"--- Diff classic -> lista ---", "--- classic\n+++ lista\n", "+La Noche\n"} {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("-compare-output file missing %q:\n%s", want, data)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -58,8 +59,10 @@ func TestGenerateShowName(t *testing.T) {
 	sp := &ShowProcessor{
 		config: &config.Config{
 			Station: struct {
-				Name             string `toml:"name"`
-				MixcloudUsername string `toml:"mixcloud_username"`
+				Name                    string `toml:"name"`
+				MixcloudUsername        string `toml:"mixcloud_username"`
+				AnnouncementSource      string `toml:"announcement_source"`
+				AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 			}{
 				Name: "Test Station",
 			},
@@ -147,8 +150,10 @@ func TestDateHandlingFallbackLogic(t *testing.T) {
 	sp := &ShowProcessor{
 		config: &config.Config{
 			Station: struct {
-				Name             string `toml:"name"`
-				MixcloudUsername string `toml:"mixcloud_username"`
+				Name                    string `toml:"name"`
+				MixcloudUsername        string `toml:"mixcloud_username"`
+				AnnouncementSource      string `toml:"announcement_source"`
+				AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 			}{
 				Name: "Test Station",
 			},
@@ -185,4 +190,257 @@ func TestDateHandlingFallbackLogic(t *testing.T) {
 			t.Errorf("Expected current date fallback %q, got %q", expectedResult, result)
 		}
 	})
-}
\ No newline at end of file
+}
+func TestGenerateURLSource(t *testing.T) {
+	sp := &ShowProcessor{
+		config: &config.Config{
+			Station: struct {
+				Name                    string `toml:"name"`
+				MixcloudUsername        string `toml:"mixcloud_username"`
+				AnnouncementSource      string `toml:"announcement_source"`
+				AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
+			}{
+				Name: "Test Station",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		showCfg      *config.ShowConfig
+		dateOverride string
+		expected     string
+		expectError  bool
+	}{
+		{
+			name: "Falls back to show name without url_pattern",
+			showCfg: &config.ShowConfig{
+				ShowNamePattern: "The Newer New Wave Show",
+			},
+			dateOverride: "6/28/2025",
+			expected:     "The Newer New Wave Show",
+		},
+		{
+			name: "url_pattern with inline date format",
+			showCfg: &config.ShowConfig{
+				ShowNamePattern: "The Newer New Wave Show",
+				URLPattern:      "The Newer New Wave Show - {date:MMMM D YYYY}",
+				DateFormat:      "M/D/YYYY",
+			},
+			dateOverride: "6/28/2025",
+			expected:     "The Newer New Wave Show - June 28 2025",
+		},
+		{
+			name: "Inline format without date_format still parses override",
+			showCfg: &config.ShowConfig{
+				ShowNamePattern: "Show",
+				URLPattern:      "Show {date:YYYY-MM-DD}",
+			},
+			dateOverride: "6/28/2025",
+			expected:     "Show 2025-06-28",
+		},
+		{
+			name: "url_pattern mixes {date} and {station}",
+			showCfg: &config.ShowConfig{
+				ShowNamePattern: "Show",
+				URLPattern:      "{station} Show {date}",
+				DateFormat:      "MM/DD/YYYY",
+			},
+			dateOverride: "6/28/2025",
+			expected:     "Test Station Show 06/28/2025",
+		},
+		{
+			name: "Unparseable override for inline format",
+			showCfg: &config.ShowConfig{
+				ShowNamePattern: "Show",
+				URLPattern:      "Show {date:MMMM D YYYY}",
+			},
+			dateOverride: "next saturday",
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			showName, err := sp.generateShowName(tt.showCfg, "test.cue", tt.dateOverride)
+			if err != nil {
+				t.Fatalf("generateShowName() unexpected error: %v", err)
+			}
+
+			result, err := sp.generateURLSource(tt.showCfg, showName, tt.dateOverride)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("generateURLSource() expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateURLSource() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("generateURLSource() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveDateOverride(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	sp := newTestProcessor(t, `
+[shows.weekly]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Weekly {date}"
+date_format = "YYYY-MM-DD"
+enabled = true
+`)
+	sp.mixcloud = newFakeMixcloud()
+	// Saturday 03:00 UTC is still Friday evening at the station
+	sp.now = func() time.Time { return time.Date(2025, 6, 28, 3, 0, 0, 0, time.UTC) }
+	sp.location = losAngeles
+
+	tests := []struct {
+		dateOverride string
+		want         string
+	}{
+		{"today", "06/27/2025"},
+		{"yesterday", "06/26/2025"},
+		{"2 days ago", "06/25/2025"},
+		{"last friday", "06/20/2025"},
+		{"last saturday", "06/21/2025"},
+		{"6/28/2025", "6/28/2025"},
+		{"last fryday", "last fryday"},
+	}
+	showCfg := sp.config.Shows["weekly"]
+	for _, tt := range tests {
+		t.Run(tt.dateOverride, func(t *testing.T) {
+			if got, err := sp.resolveDateOverride(&showCfg, tt.dateOverride); err != nil || got != tt.want {
+				t.Errorf("resolveDateOverride(%q) = %q, %v, want %q", tt.dateOverride, got, err, tt.want)
+			}
+		})
+	}
+
+	yesterday, _ := sp.resolveDateOverride(&showCfg, "yesterday")
+	result := sp.processingleShow("weekly", &showCfg, "", yesterday, true)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	if result.ShowName != "Weekly 2025-06-26" || result.ShowDate != "2025-06-26" {
+		t.Errorf("show name %q, date %q, want Weekly 2025-06-26 on 2025-06-26", result.ShowName, result.ShowDate)
+	}
+
+	result = sp.processingleShow("weekly", &showCfg, "", "last fryday", true)
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "last <weekday>") {
+		t.Errorf("processingleShow() with an invalid date error = %v, want one listing the relative forms", result.Error)
+	}
+}
+
+func TestResolveDateOverrideInputFormat(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.uk]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "UK Show {date}"
+date_format = "YYYY-MM-DD"
+date_input_format = "DD/MM/YYYY"
+enabled = true
+
+[shows.us]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "US Show {date}"
+date_format = "YYYY-MM-DD"
+enabled = true
+`)
+	sp.mixcloud = newFakeMixcloud()
+	sp.now = func() time.Time { return time.Date(2025, 6, 28, 12, 0, 0, 0, time.UTC) }
+	sp.location = time.UTC
+
+	tests := []struct {
+		name         string
+		showKey      string
+		dateOverride string
+		wantErr      bool
+		wantDate     string // The show date it publishes under
+	}{
+		// 03/04/2025 is 3 April to a day-first show and 4 March to the flexible parser
+		{"strict ambiguous", "uk", "03/04/2025", false, "2025-04-03"},
+		{"strict day past 12", "uk", "28/06/2025", false, "2025-06-28"},
+		{"strict rejects month-first", "uk", "06/28/2025", true, ""},
+		{"strict rejects ISO", "uk", "2025-06-28", true, ""},
+		{"strict rejects no leading zeros", "uk", "3/4/2025", true, ""},
+		{"strict still takes relative dates", "uk", "yesterday", false, "2025-06-27"},
+		{"flexible ambiguous is month-first", "us", "03/04/2025", false, "2025-03-04"},
+		{"flexible day past 12 is day-first", "us", "28/06/2025", false, "2025-06-28"},
+		{"flexible ISO", "us", "2025-06-28", false, "2025-06-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			showCfg := sp.config.Shows[tt.showKey]
+			dateOverride, err := sp.resolveDateOverride(&showCfg, tt.dateOverride)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "DD/MM/YYYY") {
+					t.Errorf("resolveDateOverride(%q) error = %v, want one naming the date_input_format", tt.dateOverride, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveDateOverride(%q) error = %v", tt.dateOverride, err)
+			}
+
+			result := sp.processingleShow(tt.showKey, &showCfg, "", dateOverride, true)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+			if result.ShowDate != tt.wantDate || !strings.HasSuffix(result.ShowName, " "+tt.wantDate) {
+				t.Errorf("show %q on %s, want %s", result.ShowName, result.ShowDate, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestShowDatePlaceholder(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	sp := newTestProcessor(t, `
+[templates.config.dated]
+header = "{{.ShowTitle}} aired {{.ShowDate}}\n"
+track = "{{.Artist}} - {{.Title}}\n"
+
+[shows.weekly]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Weekly"
+template = "dated"
+enabled = true
+`)
+	sp.mixcloud = newFakeMixcloud()
+	// Saturday 03:00 UTC is still Friday evening at the station
+	sp.now = func() time.Time { return time.Date(2025, 6, 28, 3, 0, 0, 0, time.UTC) }
+	sp.location = losAngeles
+
+	tests := []struct {
+		name         string
+		dateOverride string
+		want         string
+	}{
+		{"today in the station timezone", "", "Weekly aired June 27, 2025"},
+		{"past date", "3/14/2025", "Weekly aired March 14, 2025"},
+		{"zero-padded date", "06/26/2025", "Weekly aired June 26, 2025"},
+		{"ISO date", "2025-01-31", "Weekly aired January 31, 2025"},
+	}
+	showCfg := sp.config.Shows["weekly"]
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sp.processingleShow("weekly", &showCfg, "", tt.dateOverride, true)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+			if !strings.HasPrefix(result.Description, tt.want+"\n") {
+				t.Errorf("description = %q, want it to start with %q", result.Description, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/dateutil"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+)
+
+// AIDEV-NOTE: -date with several dates republishes chosen past episodes of one
+// show. Each date runs the normal single-show pipeline with that date as the
+// override; the {date} placeholder in cue_file_pattern is what lets each one
+// find its own CUE file instead of the newest.
+
+// RunModeDates is reported by run_started for -show runs with several -date values
+const RunModeDates = "dates"
+
+// DateResult is the outcome of one date of a multi-date run
+type DateResult struct {
+	Date   time.Time
+	Result ProcessingResult
+}
+
+// ProcessShowDates processes a show once for each date, in the order given.
+// A date that fails, e.g. because no CUE file matches it, doesn't stop the
+// others; a per-date report is printed at the end.
+func (sp *ShowProcessor) ProcessShowDates(nameOrAlias string, templateOverride string, dates []string, dryRun bool) error {
+	startTime := time.Now()
+
+	showCfg := sp.resolver.FindShowConfig(nameOrAlias)
+	if showCfg == nil {
+		return sp.resolver.NotFoundError(nameOrAlias)
+	}
+	showKey := sp.resolver.FindShowKey(nameOrAlias)
+
+	if !shows.HasDatePlaceholder(showCfg) {
+		return fmt.Errorf("-date with several dates needs a {date} placeholder in the cue_file_pattern of %s, e.g. \"NWR_{date:YYYYMMDD}*.cue\", otherwise every date would get the newest CUE file", showKey)
+	}
+	if !showCfg.Enabled {
+		return &ShowDisabledError{ShowKey: showKey}
+	}
+
+	// Every date is checked before anything is published
+	parsed := make([]time.Time, len(dates))
+	for i, date := range dates {
+		var err error
+		if parsed[i], _, err = sp.parseDateInput(showCfg, date); err != nil {
+			return fmt.Errorf("invalid date '%s': %w", date, err)
+		}
+		if dayFirst, ambiguous := dateutil.AmbiguousDayMonth(date); ambiguous && showCfg.DateInputFormat == "" {
+			console.Printf("⚠️  %s read as %s; day-first it would be %s, set date_input_format on the show to pick one\n",
+				date, parsed[i].Format("2006-01-02"), dayFirst.Format("2006-01-02"))
+			sp.logger.Warn("Ambiguous date override",
+				slog.String("date_override", date),
+				slog.String("date", parsed[i].Format("2006-01-02")),
+				slog.String("day_first", dayFirst.Format("2006-01-02")))
+		}
+	}
+
+	console.Printf("Processing show: %s for %d dates\n", showKey, len(dates))
+	console.Printf("============================\n\n")
+
+	sp.logger.Info("Processing show for several dates",
+		slog.String("show_key", showKey),
+		slog.Int("dates", len(dates)),
+		slog.Bool("dry_run", dryRun))
+
+	defer sp.startRun()()
+	sp.loadAnnouncement()
+	sp.emitRunStarted(RunModeDates, showKey, len(dates), dryRun)
+	batchResult := &BatchResult{
+		TotalShows: len(dates),
+		Results:    make([]ProcessingResult, 0, len(dates)),
+	}
+
+	abort := sp.newAbortPolicy()
+	report := make([]DateResult, 0, len(dates))
+	for i, date := range parsed {
+		if sp.deadlineExceeded() || abort.trigger != nil {
+			remaining := make([]string, 0, len(parsed)-i)
+			for _, left := range parsed[i:] {
+				remaining = append(remaining, showKey+"@"+left.Format("2006-01-02"))
+			}
+			if abort.trigger != nil {
+				sp.stopAtFailureThreshold(batchResult, abort.trigger, remaining)
+			} else {
+				sp.stopAtDeadline(batchResult, remaining)
+			}
+			break
+		}
+
+		startDate := time.Now()
+		result := sp.processingleShow(showKey, showCfg, templateOverride, date.Format("01/02/2006"), dryRun)
+		result.Duration = time.Since(startDate)
+
+		batchResult.add(result)
+		sp.emitShowFinished(result)
+		sp.printBatchLine(result)
+		report = append(report, DateResult{Date: date, Result: result})
+		abort.record(result)
+	}
+
+	batchResult.TotalDuration = time.Since(startTime)
+	printDatesReport(showKey, report)
+	return sp.finishBatch(batchResult)
+}
+
+// printDatesReport prints one line per date: date, status and URL
+func printDatesReport(showKey string, report []DateResult) {
+	console.Printf("\nDates Report: %s\n", showKey)
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	tw := tabwriter.NewWriter(console.Chrome, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "DATE\tSTATUS\tURL\n")
+	for _, date := range report {
+		url := date.Result.ShowURL
+		if url == "" && date.Result.CueFile != "" {
+			url = "(" + filepath.Base(date.Result.CueFile) + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", date.Date.Format("2006-01-02"), backfillStatus(date.Result), url)
+	}
+	tw.Flush()
+
+	for _, date := range report {
+		if date.Result.Error != nil {
+			console.Printf("• %s: %v\n", date.Date.Format("2006-01-02"), date.Result.Error)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const datesTestConfig = `
+[shows.myr]
+cue_file_pattern = "MYR_{date:YYYYMMDD}.cue"
+show_name_pattern = "Myriad - {date}"
+date_format = "Jan 2, 2006"
+enabled = true
+`
+
+func TestProcessShowDates(t *testing.T) {
+	sp, fake := newMyrTestProcessor(t, datesTestConfig, "20250628", "20250712")
+
+	// 5 July has no CUE file; the dates around it are still published
+	err := sp.ProcessShowDates("myr", "", []string{"6/28/2025", "2025-07-05", "7/12/2025"}, false)
+	if err == nil {
+		t.Fatal("ProcessShowDates() error = nil, want the date without a CUE file reported")
+	}
+	want := []string{myrURL("Jun 28, 2025"), myrURL("Jul 12, 2025")}
+	if got := updatedURLs(fake); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("updated URLs = %v, want %v", got, want)
+	}
+
+	run := sp.LastRun()
+	if run.TotalShows != 3 || run.SuccessfulShows != 2 || run.FailedShows != 1 {
+		t.Errorf("run = %d total, %d successful, %d failed, want 3, 2, 1", run.TotalShows, run.SuccessfulShows, run.FailedShows)
+	}
+	if failed := run.Results[1]; !strings.Contains(failed.Error.Error(), "MYR_20250705.cue") {
+		t.Errorf("5 July error = %v, want the missing CUE file named", failed.Error)
+	}
+}
+
+func TestProcessShowDatesRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		dated     bool
+		dates     []string
+		wantError string
+	}{
+		{
+			name:      "no date placeholder",
+			dates:     []string{"6/28/2025", "7/12/2025"},
+			wantError: "{date} placeholder",
+		},
+		{
+			name:      "bad date",
+			dated:     true,
+			dates:     []string{"6/28/2025", "someday"},
+			wantError: "invalid date 'someday'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlBody := backfillTestConfig
+			if tt.dated {
+				tomlBody = datesTestConfig
+			}
+			sp, fake := newMyrTestProcessor(t, tomlBody, "20250628", "20250712")
+			err := sp.ProcessShowDates("myr", "", tt.dates, false)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("ProcessShowDates() error = %v, want one containing %q", err, tt.wantError)
+			}
+			if len(fake.updates) > 0 {
+				t.Errorf("updates = %v, want none", fake.updates)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: run_deadline_minutes keeps a stalled cron run from overlapping the
+// next one. The show in flight when it passes fails with the cancelled request;
+// shows not yet started are reported as not attempted, never as failures.
+
+// ErrRunDeadlineExceeded is returned when run_deadline_minutes stopped a run early
+var ErrRunDeadlineExceeded = errors.New("run deadline exceeded")
+
+// startRun sets up the context the run's Mixcloud requests use, cancelled after
+// run_deadline_minutes. Callers defer the returned cancel func.
+func (sp *ShowProcessor) startRun() context.CancelFunc {
+	if sp.runDeadline <= 0 {
+		sp.runCtx = context.Background()
+		return func() {}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sp.runDeadline)
+	sp.runCtx = ctx
+	return cancel
+}
+
+// runContext returns the current run's context
+func (sp *ShowProcessor) runContext() context.Context {
+	if sp.runCtx == nil {
+		return context.Background()
+	}
+	return sp.runCtx
+}
+
+// deadlineExceeded reports whether the current run is past run_deadline_minutes
+func (sp *ShowProcessor) deadlineExceeded() bool {
+	return errors.Is(sp.runContext().Err(), context.DeadlineExceeded)
+}
+
+// stopAtDeadline records showKeys as not attempted because the run deadline passed
+func (sp *ShowProcessor) stopAtDeadline(batchResult *BatchResult, showKeys []string) {
+	batchResult.DeadlineSkipped = append(batchResult.DeadlineSkipped, showKeys...)
+	batchResult.NotAttemptedShows += len(showKeys)
+
+	sp.logger.Warn("Run deadline exceeded, not attempting remaining shows",
+		slog.Duration("run_deadline", sp.runDeadline),
+		slog.Int("not_attempted", len(showKeys)),
+		slog.String("not_attempted_shows", strings.Join(showKeys, ", ")))
+	console.Printf("⏱️  Run deadline (%s) exceeded: not attempting %d remaining shows\n\n", sp.runDeadline, len(showKeys))
+}
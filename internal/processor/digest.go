@@ -0,0 +1,168 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: The digest is written from the results the run already holds, so
+// it shows exactly what was published without rendering any show again. It's
+// written after the batch summary, whatever the outcome: a digest with failures
+// listed is what the reader needs most. A digest that can't be written doesn't
+// fail a run whose shows were published.
+
+// DefaultDigestTemplate renders the -digest document when processing.digest_template is not set
+const DefaultDigestTemplate = `# {{.Station}} digest, {{.GeneratedAt.Format "January 2, 2006"}}{{if .DryRun}} (dry run){{end}}
+
+{{len .Published}} shows updated{{if .Failed}}, {{len .Failed}} failed{{end}}{{if .Skipped}}, {{len .Skipped}} skipped{{end}}{{if .Queued}}, {{len .Queued}} queued offline{{end}}.
+{{range .Published}}
+## {{.ShowName}}{{if .ShowDate}} ({{.ShowDate}}){{end}}
+
+{{.ShowURL}}
+{{if .CarriedOver}}
+Published by the interrupted run this run resumed.
+{{else}}
+` + "```" + `
+{{.Description}}
+` + "```" + `
+{{end}}{{end}}{{if .Failed}}
+## Failed
+
+{{range .Failed}}- **{{.ShowKey}}**{{if .FailureCategory}} [{{.FailureCategory}}]{{end}}: {{.Error}}
+{{end}}{{end}}{{if .Queued}}
+## Queued offline
+
+{{range .Queued}}- **{{.ShowKey}}**: {{.ShowURL}}
+{{end}}{{end}}{{if .Skipped}}
+## Skipped
+
+{{range .Skipped}}- **{{.ShowKey}}**{{if .Reason}}: {{.Reason}}{{end}}
+{{end}}{{end}}{{if .NotAttempted}}
+## Not attempted
+
+{{range .NotAttempted}}- {{.}}
+{{end}}{{end}}`
+
+// Digest is the data a digest template is rendered with
+type Digest struct {
+	Station      string
+	GeneratedAt  time.Time
+	DryRun       bool
+	Results      []ProcessingResult // Every show's result, in processing order
+	Published    []DigestShow       // Shows updated, or in a dry run shows that would be
+	Failed       []DigestShow
+	Queued       []DigestShow // Offline run: queued for -retry-failed
+	Skipped      []DigestShow
+	NotAttempted []string // Show keys left out by -limit, the run deadline or a failure threshold
+}
+
+// DigestShow is one show of a digest
+type DigestShow struct {
+	ShowKey         string
+	ShowName        string
+	ShowDate        string // YYYY-MM-DD
+	ShowURL         string
+	Tracks          int
+	Description     string // As formatted for Mixcloud; split shows' parts separated by a blank line
+	Placeholder     bool
+	CarriedOver     bool   // Published by the interrupted run this run resumed, no description
+	Reason          string // Skipped: why
+	FailureCategory string // Failed
+	Error           string // Failed
+}
+
+// digestShow converts a show's result for a digest
+func digestShow(result ProcessingResult) DigestShow {
+	show := DigestShow{
+		ShowKey:     result.ShowKey,
+		ShowName:    result.ShowName,
+		ShowDate:    result.ShowDate,
+		ShowURL:     result.ShowURL,
+		Tracks:      result.FilteredTracks,
+		Description: result.Description,
+		Placeholder: result.Placeholder,
+		CarriedOver: result.CarriedOver,
+		Reason:      result.SkipReason,
+	}
+	if show.ShowName == "" {
+		show.ShowName = result.ShowKey
+	}
+	if result.Error != nil {
+		show.FailureCategory = string(result.FailureCategory)
+		show.Error = result.Error.Error()
+	}
+	return show
+}
+
+// Digest collects the run's shows by outcome for a digest of station
+func (br *BatchResult) Digest(station string, generatedAt time.Time) Digest {
+	digest := Digest{
+		Station:     station,
+		GeneratedAt: generatedAt,
+		Results:     br.Results,
+	}
+	for _, result := range br.Results {
+		digest.DryRun = digest.DryRun || result.DryRun
+		show := digestShow(result)
+		switch resultStatus(result) {
+		case StatusSuccess:
+			digest.Published = append(digest.Published, show)
+		case StatusFailed:
+			digest.Failed = append(digest.Failed, show)
+		case StatusQueued:
+			digest.Queued = append(digest.Queued, show)
+		default:
+			digest.Skipped = append(digest.Skipped, show)
+		}
+	}
+	digest.NotAttempted = append(append(append(digest.NotAttempted, br.NotAttempted...), br.DeadlineSkipped...), br.ThresholdSkipped...)
+	return digest
+}
+
+// RenderDigest renders digest with tmplText, DefaultDigestTemplate when empty
+func RenderDigest(tmplText string, digest Digest) ([]byte, error) {
+	if tmplText == "" {
+		tmplText = DefaultDigestTemplate
+	}
+	tmpl, err := template.New("digest").Parse(tmplText)
+	if err != nil {
+		return nil, fmt.Errorf("parsing digest template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, digest); err != nil {
+		return nil, fmt.Errorf("rendering digest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeDigest writes the run's digest to Options.Digest (-digest). Failures are
+// logged and printed, the run's outcome stands.
+func (sp *ShowProcessor) writeDigest(batchResult *BatchResult) {
+	path := sp.options.Digest
+	if path == "" {
+		return
+	}
+	data, err := RenderDigest(sp.config.Processing.DigestTemplate, batchResult.Digest(sp.config.Station.Name, time.Now()))
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		sp.logger.Error("Failed to write digest",
+			slog.String("path", path),
+			slog.String("error", err.Error()))
+		console.Errorf("❌ Digest not written: %v\n", err)
+		return
+	}
+	sp.logger.Info("Digest written", slog.String("path", path))
+	console.Printf("Digest written to %s\n", path)
+}
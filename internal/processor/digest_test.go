@@ -0,0 +1,139 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchResultDigest(t *testing.T) {
+	br := &BatchResult{
+		Results: []ProcessingResult{
+			{ShowKey: "nnw", ShowName: "NNW - 6/28/2025", ShowURL: "https://www.mixcloud.com/station/nnw/", Description: "tracklist", Success: true},
+			{ShowKey: "jazz", Error: errors.New("no CUE file"), FailureCategory: FailureSource},
+			{ShowKey: "myr", Skipped: true, SkipReason: "outside its publish window"},
+			{ShowKey: "late", Queued: true},
+			{ShowKey: "early", Success: true, CarriedOver: true},
+		},
+		NotAttempted:    []string{"extra"},
+		DeadlineSkipped: []string{"overtime"},
+	}
+
+	digest := br.Digest("Test FM", time.Date(2025, 6, 28, 22, 0, 0, 0, time.UTC))
+
+	keys := func(shows []DigestShow) string {
+		var names []string
+		for _, show := range shows {
+			names = append(names, show.ShowKey)
+		}
+		return strings.Join(names, ",")
+	}
+	tests := []struct {
+		section string
+		got     string
+		want    string
+	}{
+		{"published", keys(digest.Published), "nnw,early"},
+		{"failed", keys(digest.Failed), "jazz"},
+		{"skipped", keys(digest.Skipped), "myr"},
+		{"queued", keys(digest.Queued), "late"},
+		{"not attempted", strings.Join(digest.NotAttempted, ","), "extra,overtime"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.section, tt.got, tt.want)
+		}
+	}
+	if digest.Failed[0].Error != "no CUE file" || digest.Failed[0].FailureCategory != string(FailureSource) {
+		t.Errorf("failed show = %+v, want its error and category", digest.Failed[0])
+	}
+	if digest.Published[0].Description != "tracklist" || len(digest.Results) != 5 {
+		t.Errorf("digest = %+v, want the published description and every result", digest)
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	br := &BatchResult{
+		Results: []ProcessingResult{
+			{ShowKey: "nnw", ShowName: "NNW - 6/28/2025", ShowDate: "2025-06-28", ShowURL: "https://www.mixcloud.com/station/nnw/",
+				Description: "00:25 - \"When I Fall\" by Laura Dre", Success: true},
+			{ShowKey: "jazz", Error: errors.New("no CUE file"), FailureCategory: FailureSource},
+		},
+	}
+	digest := br.Digest("Test FM", time.Date(2025, 6, 28, 22, 0, 0, 0, time.UTC))
+
+	t.Run("default template", func(t *testing.T) {
+		data, err := RenderDigest("", digest)
+		if err != nil {
+			t.Fatalf("RenderDigest() error = %v", err)
+		}
+		for _, want := range []string{
+			"# Test FM digest, June 28, 2025",
+			"1 shows updated, 1 failed.",
+			"## NNW - 6/28/2025 (2025-06-28)",
+			"https://www.mixcloud.com/station/nnw/",
+			"```\n00:25 - \"When I Fall\" by Laura Dre\n```",
+			"## Failed",
+			"- **jazz** [config/source]: no CUE file",
+		} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("digest missing %q:\n%s", want, data)
+			}
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		data, err := RenderDigest("{{range .Results}}{{.ShowKey}};{{end}}", digest)
+		if err != nil || string(data) != "nnw;jazz;" {
+			t.Errorf("RenderDigest() = %q, %v, want nnw;jazz;", data, err)
+		}
+	})
+
+	t.Run("broken template", func(t *testing.T) {
+		if _, err := RenderDigest("{{range .Results}", digest); err == nil {
+			t.Error("RenderDigest() with a broken template succeeded")
+		}
+	})
+}
+
+func TestBatchRunWritesDigest(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.jazz]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Jazz Hour"
+enabled = true
+
+[shows.missing]
+cue_file_mapping = "MISSING.cue"
+show_name_pattern = "Missing"
+enabled = true
+`)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	path := filepath.Join(t.TempDir(), "digests", "week.md")
+	sp.SetOptions(Options{Digest: path})
+
+	if err := sp.ProcessAllShows(false); err == nil {
+		t.Fatal("ProcessAllShows() succeeded with a missing CUE file")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("digest not written: %v", err)
+	}
+	var published string
+	for _, result := range sp.LastRun().Results {
+		if result.ShowKey == "jazz" {
+			published = result.Description
+		}
+	}
+	if published == "" || !strings.Contains(string(data), published) {
+		t.Errorf("digest doesn't hold jazz's published description %q:\n%s", published, data)
+	}
+	if !strings.Contains(string(data), "- **missing**") {
+		t.Errorf("digest doesn't list the failed show:\n%s", data)
+	}
+}
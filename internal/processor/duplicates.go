@@ -0,0 +1,167 @@
+package processor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: A copy-pasted cue_file_mapping publishes the same tracklist to two
+// Mixcloud shows, and nothing looks wrong until a listener notices. Batch runs
+// render every show before updating any so this cross-check can still stop both
+// (processing.fail_on_duplicate_source); by default it only warns, since a few
+// stations deliberately publish one CUE file under two shows.
+
+// Why shows were reported as sharing a source
+const (
+	DuplicateCueFile     = "cue_file"    // The shows resolved the same CUE file
+	DuplicateDescription = "description" // The shows rendered identical descriptions
+)
+
+// DuplicateSource is a set of shows in one run that resolved the same CUE file
+// or rendered the same description
+type DuplicateSource struct {
+	Reason string   `json:"reason"`           // DuplicateCueFile or DuplicateDescription
+	Shows  []string `json:"shows"`            // Show keys, in processing order
+	Detail string   `json:"detail,omitempty"` // The CUE file, or the start of the description's SHA-256
+}
+
+func (d DuplicateSource) String() string {
+	if d.Reason == DuplicateCueFile {
+		return fmt.Sprintf("%s resolved the same CUE file %s", strings.Join(d.Shows, ", "), d.Detail)
+	}
+	return fmt.Sprintf("%s rendered identical descriptions (sha256 %s)", strings.Join(d.Shows, ", "), d.Detail)
+}
+
+// DuplicateSourceError fails a show that shares its source with another show
+// in the run, with processing.fail_on_duplicate_source
+type DuplicateSourceError struct {
+	Duplicate DuplicateSource
+}
+
+func (e *DuplicateSourceError) Error() string {
+	return "duplicate source: " + e.Duplicate.String()
+}
+
+// findDuplicateSources groups the rendered shows of a run by CUE file and by
+// description. A description match between shows that already share a CUE file
+// isn't reported again.
+func findDuplicateSources(results []ProcessingResult) []DuplicateSource {
+	byCueFile := make(map[string][]string)
+	byDescription := make(map[string][]string)
+	var cueFiles, hashes []string
+	for _, result := range results {
+		if result.Error != nil || result.Skipped {
+			continue
+		}
+		if result.CueFile != "" {
+			cueFile := cuePathKey(result.CueFile)
+			if _, seen := byCueFile[cueFile]; !seen {
+				cueFiles = append(cueFiles, cueFile)
+			}
+			byCueFile[cueFile] = append(byCueFile[cueFile], result.ShowKey)
+		}
+		// Placeholders are the same text by design
+		if text := publishedText(result); text != "" && !result.Placeholder {
+			sum := sha256.Sum256([]byte(text))
+			hash := hex.EncodeToString(sum[:])
+			if _, seen := byDescription[hash]; !seen {
+				hashes = append(hashes, hash)
+			}
+			byDescription[hash] = append(byDescription[hash], result.ShowKey)
+		}
+	}
+
+	var duplicates []DuplicateSource
+	for _, cueFile := range cueFiles {
+		if shows := byCueFile[cueFile]; len(shows) > 1 {
+			duplicates = append(duplicates, DuplicateSource{Reason: DuplicateCueFile, Shows: shows, Detail: cueFile})
+		}
+	}
+	for _, hash := range hashes {
+		shows := byDescription[hash]
+		if len(shows) < 2 || slices.ContainsFunc(duplicates, func(d DuplicateSource) bool { return slices.Equal(d.Shows, shows) }) {
+			continue
+		}
+		duplicates = append(duplicates, DuplicateSource{Reason: DuplicateDescription, Shows: shows, Detail: hash[:12]})
+	}
+	return duplicates
+}
+
+// cuePathKey normalizes a resolved CUE path so two spellings of one file compare equal
+func cuePathKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Clean(path)
+}
+
+// publishedText is everything a show would publish: its description, or the
+// descriptions of all its parts
+func publishedText(result ProcessingResult) string {
+	if len(result.Parts) == 0 {
+		return result.Description
+	}
+	descriptions := make([]string, len(result.Parts))
+	for i, part := range result.Parts {
+		descriptions[i] = part.Description
+	}
+	return strings.Join(descriptions, "\x00")
+}
+
+// checkDuplicateSources reports the shows of a rendered batch that share a
+// source and, with processing.fail_on_duplicate_source, fails each of them
+// before anything is published
+func (sp *ShowProcessor) checkDuplicateSources(results []ProcessingResult) []DuplicateSource {
+	duplicates := findDuplicateSources(results)
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	fail := sp.config.Processing.FailOnDuplicateSource
+	for _, duplicate := range duplicates {
+		sp.logger.Warn("Shows share a source",
+			slog.String("reason", duplicate.Reason),
+			slog.String("shows", strings.Join(duplicate.Shows, ", ")),
+			slog.String("detail", duplicate.Detail),
+			slog.Bool("failing", fail))
+		console.Printf("⚠️  Duplicate source: %s\n", duplicate)
+		if !fail {
+			continue
+		}
+		for i := range results {
+			if slices.Contains(duplicate.Shows, results[i].ShowKey) {
+				sp.failDuplicate(&results[i], duplicate)
+			}
+		}
+	}
+	if !fail {
+		console.Printf("   Check their cue_file_mapping/cue_file_pattern; fail_on_duplicate_source = true stops them\n")
+	}
+	console.Printf("\n")
+	return duplicates
+}
+
+// failDuplicate fails a rendered show for sharing its source, taking an
+// offline run's description back out of the queue
+func (sp *ShowProcessor) failDuplicate(result *ProcessingResult, duplicate DuplicateSource) {
+	if result.Error != nil {
+		return // Already failed for its other duplicate
+	}
+	if result.Queued {
+		sp.dropPending(result)
+		result.Queued = false
+	}
+	result.Success = false
+	result.Error = &SourceError{Err: &DuplicateSourceError{Duplicate: duplicate}}
+	result.FailureCategory = CategorizeFailure(result.Error)
+}
@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const duplicateTestConfig = `
+[shows.alpha]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Alpha"
+priority = 1
+enabled = true
+
+[shows.beta]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Beta"
+priority = 2
+enabled = true
+
+[shows.gamma]
+cue_file_mapping = "COPY.cue"
+show_name_pattern = "Gamma"
+priority = 3
+enabled = true
+
+[shows.delta]
+cue_file_mapping = "OTHER.cue"
+show_name_pattern = "Delta"
+priority = 4
+enabled = true
+`
+
+const otherCueContent = `FILE "OTHER.wav" WAV
+  TRACK 01 AUDIO
+    TITLE "Something Else"
+    PERFORMER "Another Artist"
+    INDEX 01 00:00:00
+`
+
+// newDuplicateTestProcessor sets up alpha and beta on the same CUE file, gamma
+// on a copy of it and delta on a different one
+func newDuplicateTestProcessor(t *testing.T) (*ShowProcessor, *fakeMixcloud) {
+	t.Helper()
+	sp := newTestProcessor(t, duplicateTestConfig)
+	dir := sp.config.Processing.CueFileDirectory
+	for name, content := range map[string]string{"COPY.cue": testCueContent, "OTHER.cue": otherCueContent} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	return sp, fake
+}
+
+func TestFindDuplicateSources(t *testing.T) {
+	dir := t.TempDir()
+	cueFile := filepath.Join(dir, "TEST.cue")
+	results := []ProcessingResult{
+		{ShowKey: "alpha", CueFile: cueFile, Description: "same"},
+		{ShowKey: "beta", CueFile: filepath.Join(dir, ".", "TEST.cue"), Description: "same"},
+		{ShowKey: "gamma", CueFile: filepath.Join(dir, "COPY.cue"), Description: "same"},
+		{ShowKey: "delta", CueFile: filepath.Join(dir, "OTHER.cue"), Description: "other"},
+		{ShowKey: "failed", CueFile: cueFile, Description: "same", Error: errors.New("boom")},
+		{ShowKey: "skipped", CueFile: cueFile, Skipped: true},
+		{ShowKey: "empty-1", CueFile: filepath.Join(dir, "E1.cue"), Description: "placeholder", Placeholder: true},
+		{ShowKey: "empty-2", CueFile: filepath.Join(dir, "E2.cue"), Description: "placeholder", Placeholder: true},
+		{ShowKey: "split", CueFile: filepath.Join(dir, "SPLIT.cue"), Parts: []PartResult{{Description: "same"}, {Description: "more"}}},
+	}
+
+	got := findDuplicateSources(results)
+	if len(got) != 2 {
+		t.Fatalf("findDuplicateSources() = %v, want 2 duplicates", got)
+	}
+	if got[0].Reason != DuplicateCueFile || strings.Join(got[0].Shows, ",") != "alpha,beta" || got[0].Detail != cueFile {
+		t.Errorf("duplicates[0] = %+v, want alpha and beta on %s", got[0], cueFile)
+	}
+	if got[1].Reason != DuplicateDescription || strings.Join(got[1].Shows, ",") != "alpha,beta,gamma" {
+		t.Errorf("duplicates[1] = %+v, want alpha, beta and gamma by description", got[1])
+	}
+
+	// Shows sharing both a CUE file and a description are reported once
+	got = findDuplicateSources(results[:2])
+	if len(got) != 1 || got[0].Reason != DuplicateCueFile {
+		t.Errorf("findDuplicateSources() = %v, want only the CUE file match", got)
+	}
+}
+
+func TestProcessAllShowsDuplicateSources(t *testing.T) {
+	tests := []struct {
+		name        string
+		fail        bool
+		wantFailed  []string
+		wantUpdates int
+	}{
+		{"warns by default", false, nil, 4},
+		{"fails with fail_on_duplicate_source", true, []string{"alpha", "beta", "gamma"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, fake := newDuplicateTestProcessor(t)
+			sp.config.Processing.FailOnDuplicateSource = tt.fail
+			events := recordProgress(sp)
+
+			err := sp.ProcessAllShows(false)
+			if (err != nil) != (len(tt.wantFailed) > 0) {
+				t.Fatalf("ProcessAllShows() error = %v, want failures %v", err, tt.wantFailed)
+			}
+			if len(fake.updates) != tt.wantUpdates {
+				t.Errorf("sent %d updates %v, want %d", len(fake.updates), fake.updates, tt.wantUpdates)
+			}
+
+			run := sp.LastRun()
+			failed := run.FailedShowKeys()
+			sort.Strings(failed)
+			if strings.Join(failed, ",") != strings.Join(tt.wantFailed, ",") {
+				t.Errorf("failed shows = %v, want %v", failed, tt.wantFailed)
+			}
+			for _, result := range run.Results {
+				if result.Error != nil && result.FailureCategory != FailureSource {
+					t.Errorf("%s failure category = %s, want %s", result.ShowKey, result.FailureCategory, FailureSource)
+				}
+			}
+			if len(run.Duplicates) != 2 {
+				t.Errorf("BatchResult.Duplicates = %v, want the CUE file and description matches", run.Duplicates)
+			}
+
+			finished := (*events)[len(*events)-1]
+			if finished.Event != EventRunFinished || len(finished.Duplicates) != 2 {
+				t.Errorf("last event = %+v, want run_finished with 2 duplicates", finished)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// AIDEV-NOTE: A CUE file paired with the wrong upload rarely fails any other
+// check, but its tracklist spans a different length than the audio, e.g. 180
+// minutes of tracks on a 60-minute upload. Only the live show knows its audio
+// length, so the span is measured while preparing the show and compared where
+// the show is looked up: before live updates and in -dry-run -verify. Split
+// shows are separate uploads per part and aren't checked.
+
+// Values for LengthCheck.Verdict
+const (
+	LengthMatch    = "match"
+	LengthMismatch = "mismatch"
+	LengthUnknown  = "unknown" // Mixcloud reported no audio length or the tracklist has no span
+)
+
+// LengthCheck compares a show's upload length with its tracklist
+// (duration_tolerance_percent)
+type LengthCheck struct {
+	TrackSpan   time.Duration // First to last track start, after time_offset
+	Tail        time.Duration // duration_tail_minutes, allowed for the last track
+	AudioLength time.Duration // The upload's audio length on Mixcloud, 0 until looked up
+	Deviation   float64       // Percent AudioLength is off TrackSpan + Tail
+	Verdict     string        // LengthMatch, LengthMismatch or LengthUnknown, "" until looked up
+}
+
+// String describes the check, e.g.
+// "upload 1h0m0s, tracklist 2h56m0s + 4m0s, 66.7% off (mismatch)"
+func (c LengthCheck) String() string {
+	if c.Verdict == "" {
+		return fmt.Sprintf("tracklist %s + %s, upload not looked up", c.TrackSpan, c.Tail)
+	}
+	if c.Verdict == LengthUnknown {
+		return fmt.Sprintf("upload %s, tracklist %s + %s (unknown)", c.AudioLength, c.TrackSpan, c.Tail)
+	}
+	return fmt.Sprintf("upload %s, tracklist %s + %s, %.1f%% off (%s)",
+		c.AudioLength, c.TrackSpan, c.Tail, c.Deviation, c.Verdict)
+}
+
+// trackSpan returns the time from the first to the last track start. Tracks
+// without a usable start time are ignored.
+func trackSpan(tracks []cue.Track) time.Duration {
+	var first, last time.Duration
+	found := false
+	for _, track := range tracks {
+		offset, ok := track.StartOffset()
+		if !ok {
+			continue
+		}
+		if !found || offset < first {
+			first = offset
+		}
+		if !found || offset > last {
+			last = offset
+		}
+		found = true
+	}
+	return last - first
+}
+
+// compareLength sets the check's audio length, deviation and verdict against
+// tolerancePercent
+func (c *LengthCheck) compareLength(audioLength time.Duration, tolerancePercent int) {
+	c.AudioLength = audioLength
+	expected := c.TrackSpan + c.Tail
+	if audioLength <= 0 || expected <= 0 {
+		c.Deviation = 0
+		c.Verdict = LengthUnknown
+		return
+	}
+	c.Deviation = math.Abs(float64(audioLength-expected)) / float64(expected) * 100
+	c.Verdict = LengthMatch
+	if c.Deviation > float64(tolerancePercent) {
+		c.Verdict = LengthMismatch
+	}
+}
+
+// measureTrackSpan starts the show's length check with the span of tracks, the
+// CUE sheet's tracks after time_offset
+func (sp *ShowProcessor) measureTrackSpan(result *ProcessingResult, showCfg *config.ShowConfig, tracks []cue.Track) {
+	if showCfg.DurationTolerancePercent <= 0 || len(showCfg.SplitAt) > 0 {
+		return
+	}
+	result.LengthCheck = &LengthCheck{
+		TrackSpan: trackSpan(tracks),
+		Tail:      time.Duration(showCfg.DurationTailMinutes) * time.Minute,
+	}
+}
+
+// checkAudioLength compares the looked-up show's audio length with the
+// tracklist span. A mismatch is logged, and with duration_mismatch_action =
+// "fail" returned as an error.
+func (sp *ShowProcessor) checkAudioLength(result *ProcessingResult, showCfg *config.ShowConfig, existing *mixcloud.Show) error {
+	check := result.LengthCheck
+	if check == nil || existing == nil {
+		return nil
+	}
+	check.compareLength(time.Duration(existing.AudioLength)*time.Second, showCfg.DurationTolerancePercent)
+
+	switch check.Verdict {
+	case LengthMatch:
+		sp.logger.Debug("Upload length matches the tracklist",
+			slog.String("show_key", result.ShowKey),
+			slog.String("length_check", check.String()))
+		return nil
+	case LengthUnknown:
+		sp.logger.Warn("Upload length not compared with the tracklist",
+			slog.String("show_key", result.ShowKey),
+			slog.String("url", result.ShowURL),
+			slog.String("length_check", check.String()))
+		return nil
+	}
+
+	sp.logger.Warn("Upload length doesn't match the tracklist, the CUE file may belong to another upload",
+		slog.String("show_key", result.ShowKey),
+		slog.String("url", result.ShowURL),
+		slog.String("cue_file", result.CueFile),
+		slog.Int("duration_tolerance_percent", showCfg.DurationTolerancePercent),
+		slog.String("length_check", check.String()))
+	if showCfg.LengthMismatchAction() == config.GapActionFail {
+		// A wrong CUE file is a source problem, even though Mixcloud told us
+		return &SourceError{Err: fmt.Errorf("upload length doesn't match the tracklist, over duration_tolerance_percent (%d%%): %s",
+			showCfg.DurationTolerancePercent, check)}
+	}
+	return nil
+}
+
+// historyLength returns the history record of a show's length check, nil when
+// the show wasn't checked
+func historyLength(check *LengthCheck) *state.LengthRecord {
+	if check == nil || check.Verdict == "" {
+		return nil
+	}
+	return &state.LengthRecord{
+		TrackSpanSeconds:   int(check.TrackSpan.Seconds()),
+		TailSeconds:        int(check.Tail.Seconds()),
+		AudioLengthSeconds: int(check.AudioLength.Seconds()),
+		DeviationPercent:   math.Round(check.Deviation*10) / 10,
+		Verdict:            check.Verdict,
+	}
+}
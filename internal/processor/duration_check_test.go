@@ -0,0 +1,119 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+func TestCompareLength(t *testing.T) {
+	tests := []struct {
+		name          string
+		span          time.Duration
+		tail          time.Duration
+		audio         time.Duration
+		wantVerdict   string
+		wantDeviation float64
+	}{
+		{"exact match", 56 * time.Minute, 4 * time.Minute, time.Hour, LengthMatch, 0},
+		{"just inside, longer upload", time.Hour, 0, 68*time.Minute + 59*time.Second, LengthMatch, 14.97},
+		{"on the tolerance", time.Hour, 0, 69 * time.Minute, LengthMatch, 15},
+		{"just inside, shorter upload", time.Hour, 0, 51 * time.Minute, LengthMatch, 15},
+		{"just outside", time.Hour, 0, 69*time.Minute + time.Second, LengthMismatch, 15.03},
+		{"far outside", 176 * time.Minute, 4 * time.Minute, time.Hour, LengthMismatch, 66.67},
+		{"no audio length", time.Hour, 0, 0, LengthUnknown, 0},
+		{"single track, no tail", 0, 0, time.Hour, LengthUnknown, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := LengthCheck{TrackSpan: tt.span, Tail: tt.tail}
+			check.compareLength(tt.audio, 15)
+			if check.Verdict != tt.wantVerdict {
+				t.Errorf("verdict = %q, want %q (%s)", check.Verdict, tt.wantVerdict, check)
+			}
+			if diff := check.Deviation - tt.wantDeviation; diff > 0.01 || diff < -0.01 {
+				t.Errorf("deviation = %.2f%%, want %.2f%%", check.Deviation, tt.wantDeviation)
+			}
+		})
+	}
+}
+
+func TestTrackSpan(t *testing.T) {
+	tracks := []cue.Track{
+		{Index: 1, StartTime: "04:48"},
+		{Index: 2, StartTime: "00:25"},
+		{Index: 3, StartTime: ""},
+		{Index: 4, StartTime: "175:30"},
+	}
+	if got, want := trackSpan(tracks), 175*time.Minute+5*time.Second; got != want {
+		t.Errorf("trackSpan() = %s, want %s", got, want)
+	}
+	if got := trackSpan(nil); got != 0 {
+		t.Errorf("trackSpan(nil) = %s, want 0", got)
+	}
+}
+
+// TEST.cue's tracks start at 00:25, 04:48 and 08:15: a 7m50s span, 9m50s with the tail
+const lengthCheckTestConfig = `
+[shows.night-one]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Night One"
+enabled = true
+duration_tolerance_percent = 15
+duration_tail_minutes = 2
+`
+
+func TestAudioLengthCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		audioLength int
+		wantVerdict string
+		wantErr     bool
+	}{
+		{"matching upload", "fail", 590, LengthMatch, false},
+		{"mismatch warns", "", 3600, LengthMismatch, false},
+		{"mismatch fails", "fail", 3600, LengthMismatch, true},
+		{"no audio length", "fail", 0, LengthUnknown, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toml := lengthCheckTestConfig
+			if tt.action != "" {
+				toml += "duration_mismatch_action = \"" + tt.action + "\"\n"
+			}
+			sp := newTestProcessor(t, toml)
+			fake := newFakeMixcloud()
+			fake.audioLength = tt.audioLength
+			sp.mixcloud = fake
+			showCfg := sp.config.Shows["night-one"]
+
+			result := sp.processingleShow("night-one", &showCfg, "", "", false)
+			if result.LengthCheck == nil || result.LengthCheck.Verdict != tt.wantVerdict {
+				t.Fatalf("length check = %+v, want verdict %q", result.LengthCheck, tt.wantVerdict)
+			}
+			if result.LengthCheck.TrackSpan != 7*time.Minute+50*time.Second {
+				t.Errorf("track span = %s, want 7m50s", result.LengthCheck.TrackSpan)
+			}
+			if !tt.wantErr {
+				if result.Error != nil || len(fake.updates) != 1 {
+					t.Errorf("error = %v, updates = %d, want the show published", result.Error, len(fake.updates))
+				}
+				return
+			}
+			if result.Error == nil || !strings.Contains(result.Error.Error(), "duration_tolerance_percent (15%)") {
+				t.Fatalf("error = %v, want the length mismatch", result.Error)
+			}
+			if result.FailureCategory != FailureSource {
+				t.Errorf("failure category = %s, want %s", result.FailureCategory, FailureSource)
+			}
+			if len(fake.updates) != 0 {
+				t.Errorf("updates = %v, want none", fake.updates)
+			}
+		})
+	}
+}
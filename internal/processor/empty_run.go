@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: A run with nothing to process used to return nil and exit 0 like
+// a successful publish, so automation couldn't tell "every show disabled for
+// maintenance" from "every show published". Zero-work runs now return an error
+// wrapping ErrNoShowsProcessed and flag run_finished; the CLI exits with
+// processing.empty_run_exit_code for them instead of treating them as failures.
+
+// ErrNoShowsProcessed is wrapped by every error reporting a run that had no show to process
+var ErrNoShowsProcessed = errors.New("no shows processed")
+
+// ErrNoEnabledShows is returned by ProcessAllShows when no show is enabled
+var ErrNoEnabledShows = fmt.Errorf("%w: no enabled shows in configuration", ErrNoShowsProcessed)
+
+// ShowDisabledError is returned by ProcessShow for a show with enabled = false
+type ShowDisabledError struct {
+	ShowKey string
+}
+
+func (e *ShowDisabledError) Error() string {
+	return fmt.Sprintf("%v: show %s is disabled in configuration", ErrNoShowsProcessed, e.ShowKey)
+}
+
+func (e *ShowDisabledError) Unwrap() error {
+	return ErrNoShowsProcessed
+}
+
+// IsEmptyRun reports whether err means the run had nothing to process rather than failed
+func IsEmptyRun(err error) bool {
+	return errors.Is(err, ErrNoShowsProcessed)
+}
+
+// finishEmptyRun reports a run that had nothing to process, and why, and returns err
+func (sp *ShowProcessor) finishEmptyRun(batchResult *BatchResult, err error, reason, hint string) error {
+	batchResult.EmptyRunReason = reason
+	sp.logger.Warn("No shows processed", slog.String("reason", reason))
+
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("NO SHOWS PROCESSED\n")
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("%s\n", reason)
+	console.Printf("%s\n", hint)
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	return err
+}
@@ -0,0 +1,124 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+// excludeEverything is a filter config that drops every track in the test CUE file
+const excludeEverything = `
+[filtering]
+excluded_artist_patterns = [".*"]
+
+[templates.config.framed]
+header = "{{.ShowTitle}}\n"
+track = "{{.Artist}} - {{.Title}}\n"
+footer = "Thanks for listening"
+`
+
+func TestEmptyTracklistOutcomes(t *testing.T) {
+	tests := []struct {
+		name            string
+		onEmpty         string
+		wantError       bool
+		wantSkipped     bool
+		wantPlaceholder bool
+	}{
+		{"default fails", "", true, false, false},
+		{"explicit fail", "fail", true, false, false},
+		{"skip", "skip", false, true, false},
+		{"publish placeholder", "publish_placeholder", false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, excludeEverything+`
+[shows.special]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Holiday Special"
+template = "framed"
+on_empty_tracklist = "`+tt.onEmpty+`"
+enabled = true
+`)
+
+			showCfg := sp.config.Shows["special"]
+			result := sp.processingleShow("special", &showCfg, "", "", true)
+
+			if result.FilteredTracks != 0 {
+				t.Fatalf("FilteredTracks = %d, want 0", result.FilteredTracks)
+			}
+			if (result.Error != nil) != tt.wantError {
+				t.Errorf("Error = %v, wantError %v", result.Error, tt.wantError)
+			}
+			if result.Skipped != tt.wantSkipped {
+				t.Errorf("Skipped = %v, want %v", result.Skipped, tt.wantSkipped)
+			}
+			if tt.wantSkipped && result.SkipReason == "" {
+				t.Error("skipped show should have a SkipReason")
+			}
+			if result.Placeholder != tt.wantPlaceholder {
+				t.Errorf("Placeholder = %v, want %v", result.Placeholder, tt.wantPlaceholder)
+			}
+			if result.Success != tt.wantPlaceholder {
+				t.Errorf("Success = %v, want %v", result.Success, tt.wantPlaceholder)
+			}
+
+			if tt.wantPlaceholder {
+				expected := "Holiday Special\nFull tracklist unavailable for this episode\nThanks for listening"
+				if result.FormattedLength != len(expected) {
+					t.Errorf("FormattedLength = %d, want %d (header + placeholder + footer)", result.FormattedLength, len(expected))
+				}
+			}
+		})
+	}
+}
+
+func TestFormatPlaceholder(t *testing.T) {
+	sp := newTestProcessor(t, excludeEverything)
+	metadata := template.Metadata{ShowTitle: "Holiday Special"}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"template header and footer", "framed", "Holiday Special\nNo music this week\nThanks for listening"},
+		{"classic has no header or footer", "classic", "No music this week"},
+		{"compact has no header or footer", "compact", "No music this week"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sp.formatter.FormatPlaceholder(tt.template, "No music this week", metadata)
+			if got != tt.expected {
+				t.Errorf("FormatPlaceholder() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBatchResultCountsOutcomes(t *testing.T) {
+	br := &BatchResult{}
+	br.add(ProcessingResult{ShowKey: "published", Success: true})
+	br.add(ProcessingResult{ShowKey: "placeholder", Success: true, Placeholder: true})
+	br.add(ProcessingResult{ShowKey: "skipped", Skipped: true, SkipReason: "all 3 tracks excluded by filters"})
+	br.add(ProcessingResult{ShowKey: "failed", Error: errors.New("no tracks remaining after filtering")})
+
+	if br.ProcessedShows != 4 || len(br.Results) != 4 {
+		t.Errorf("ProcessedShows = %d, Results = %d; want 4", br.ProcessedShows, len(br.Results))
+	}
+	if br.SuccessfulShows != 2 {
+		t.Errorf("SuccessfulShows = %d, want 2", br.SuccessfulShows)
+	}
+	if br.PlaceholderShows != 1 {
+		t.Errorf("PlaceholderShows = %d, want 1", br.PlaceholderShows)
+	}
+	if br.SkippedShows != 1 {
+		t.Errorf("SkippedShows = %d, want 1", br.SkippedShows)
+	}
+	if br.FailedShows != 1 {
+		t.Errorf("FailedShows = %d, want 1", br.FailedShows)
+	}
+}
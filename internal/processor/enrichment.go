@@ -0,0 +1,62 @@
+package processor
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/enrichment"
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// openEnricher opens the [enrichment] database, nil when none is configured.
+// A database that can't be opened is a warning: shows publish without labels
+// and years rather than not at all.
+func openEnricher(cfg *config.Config, configPath string) *enrichment.Enricher {
+	path := cfg.EnrichmentDatabasePath(configPath)
+	if path == "" {
+		return nil
+	}
+	source, err := enrichment.OpenSQLite(path, cfg.Enrichment.Query)
+	if err != nil {
+		logger.Get().Warn("Track enrichment unavailable, publishing without labels and years",
+			slog.String("path", path),
+			slog.String("error", err.Error()))
+		console.Printf("⚠️  Track enrichment unavailable: %v\n", err)
+		return nil
+	}
+	logger.Get().Info("Track enrichment database opened", slog.String("path", path))
+	return enrichment.New(source, time.Duration(cfg.Enrichment.TimeoutMS)*time.Millisecond)
+}
+
+// enrichTracks sets the label and year of a show's filtered tracks from the
+// enrichment database and records the hit rate on result
+func (sp *ShowProcessor) enrichTracks(showKey string, tracks []cue.Track, result *ProcessingResult) []cue.Track {
+	if sp.enricher == nil || sp.enricher.Disabled() != nil || len(tracks) == 0 {
+		return tracks
+	}
+
+	enriched, stats, err := sp.enricher.Enrich(sp.runContext(), tracks)
+	result.EnrichmentLookups = stats.LookedUp
+	result.EnrichedTracks = stats.Matched
+	if err != nil {
+		sp.logger.Warn("Track enrichment failed, disabled for the rest of the run",
+			slog.String("show_key", showKey),
+			slog.String("error", err.Error()))
+		console.Printf("⚠️  Track enrichment failed, disabled for this run: %v\n", err)
+	}
+	if stats.TimedOut {
+		sp.logger.Warn("Track enrichment ran out of time, remaining tracks not looked up",
+			slog.String("show_key", showKey),
+			slog.Int("looked_up", stats.LookedUp),
+			slog.Int("tracks", len(tracks)))
+	}
+	sp.logger.Info("Track enrichment completed",
+		slog.String("show_key", showKey),
+		slog.Int("looked_up", stats.LookedUp),
+		slog.Int("matched", stats.Matched),
+		slog.Float64("hit_rate_percent", stats.HitRate()))
+	return enriched
+}
@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/enrichment"
+)
+
+// fakeLibrary is an enrichment source keyed by normalized "artist|title"
+type fakeLibrary struct {
+	tracks map[string]enrichment.Match
+	err    error
+}
+
+func (f *fakeLibrary) Lookup(ctx context.Context, artist, title string) (enrichment.Match, bool, error) {
+	if f.err != nil {
+		return enrichment.Match{}, false, f.err
+	}
+	match, ok := f.tracks[artist+"|"+title]
+	return match, ok, nil
+}
+
+func (f *fakeLibrary) Close() error { return nil }
+
+const enrichmentTestConfig = `
+[templates.config.labels]
+track = "{{.Title}}{{if .Label}} ({{.Label}}, {{.Year}}){{end}}\n"
+
+[shows.night]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Night"
+template = "labels"
+enabled = true
+`
+
+func TestEnrichedTracklist(t *testing.T) {
+	tests := []struct {
+		name        string
+		library     *fakeLibrary
+		wantLine    string
+		wantLookups int
+		wantMatched int
+	}{
+		{
+			name: "label and year from the library",
+			library: &fakeLibrary{tracks: map[string]enrichment.Match{
+				"laura dre|when i fall": {Label: "Italians Do It Better", Year: "2019"},
+			}},
+			wantLine:    "When I Fall (Italians Do It Better, 2019)\nDive Deep Into the Night\n",
+			wantLookups: 3,
+			wantMatched: 1,
+		},
+		{
+			name:     "database error publishes without",
+			library:  &fakeLibrary{err: errors.New("database disk image is malformed")},
+			wantLine: "When I Fall\nDive Deep Into the Night\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, enrichmentTestConfig)
+			sp.mixcloud = newFakeMixcloud()
+			sp.enricher = enrichment.New(tt.library, 0)
+
+			showCfg := sp.config.Shows["night"]
+			result := sp.processingleShow("night", &showCfg, "", "", true)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+			if !strings.Contains(result.Description, tt.wantLine) {
+				t.Errorf("description = %q, want it to contain %q", result.Description, tt.wantLine)
+			}
+			if result.EnrichmentLookups != tt.wantLookups || result.EnrichedTracks != tt.wantMatched {
+				t.Errorf("enrichment = %d of %d tracks, want %d of %d",
+					result.EnrichedTracks, result.EnrichmentLookups, tt.wantMatched, tt.wantLookups)
+			}
+			if tt.library.err != nil && sp.enricher.Disabled() == nil {
+				t.Error("enrichment still on after a database error")
+			}
+		})
+	}
+}
+
+func TestUnavailableEnrichmentDatabase(t *testing.T) {
+	sp := newTestProcessor(t, enrichmentTestConfig+`
+[enrichment]
+sqlite_path = "missing-library.db"
+`)
+	if sp.enricher != nil {
+		t.Fatal("enricher set up without a usable database")
+	}
+	showCfg := sp.config.Shows["night"]
+	if result := sp.processingleShow("night", &showCfg, "", "", true); result.Error != nil {
+		t.Errorf("processingleShow() error = %v, want the show published without enrichment", result.Error)
+	}
+}
@@ -0,0 +1,136 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// FailureCategory groups show failures by who needs to act on them
+// AIDEV-NOTE: Each category has a different remediation - fix config/CUE, re-auth,
+// or wait out Mixcloud - so operators shouldn't have to read raw error strings
+type FailureCategory string
+
+const (
+	FailureNone      FailureCategory = ""
+	FailureSource    FailureCategory = "config/source" // Config, CUE file or template problems
+	FailureNotFound  FailureCategory = "not-found"     // Show URL doesn't exist on Mixcloud
+	FailureAuth      FailureCategory = "auth"          // 401/403 or expired/invalid tokens
+	FailureRateLimit FailureCategory = "rate-limit"    // 429 after retries
+	FailureServer    FailureCategory = "server"        // Mixcloud 5xx
+	FailureNetwork   FailureCategory = "network"       // Connection-level failures
+	FailureUnknown   FailureCategory = "unknown"
+)
+
+// failureCategoryOrder is the display order for grouped failure output
+var failureCategoryOrder = []FailureCategory{
+	FailureAuth,
+	FailureSource,
+	FailureNotFound,
+	FailureRateLimit,
+	FailureServer,
+	FailureNetwork,
+	FailureUnknown,
+}
+
+// SourceError marks a failure that happened before any Mixcloud API call,
+// i.e. a problem with the show's config, CUE file or template output
+type SourceError struct {
+	Err error
+}
+
+func (e *SourceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// CategorizeFailure maps an error returned by show processing to a FailureCategory
+func CategorizeFailure(err error) FailureCategory {
+	if err == nil {
+		return FailureNone
+	}
+
+	// Auth first - an OAuth failure wrapped in a network error still needs re-auth
+	if errors.Is(err, mixcloud.ErrAuthenticationFailed) ||
+		errors.Is(err, mixcloud.ErrTokenExpired) ||
+		errors.Is(err, mixcloud.ErrInvalidRefreshToken) {
+		return FailureAuth
+	}
+
+	var oauthErr *mixcloud.OAuthError
+	if errors.As(err, &oauthErr) {
+		switch oauthErr.Type {
+		case "InvalidRefreshToken", "AuthenticationFailed", "TokenExpired":
+			return FailureAuth
+		case "NetworkFailure":
+			return FailureNetwork
+		}
+	}
+
+	switch {
+	case errors.Is(err, mixcloud.ErrRateLimited):
+		return FailureRateLimit
+	case errors.Is(err, mixcloud.ErrShowNotFound):
+		return FailureNotFound
+	case errors.Is(err, mixcloud.ErrServerError):
+		return FailureServer
+	case errors.Is(err, mixcloud.ErrNetworkFailure):
+		return FailureNetwork
+	case errors.Is(err, mixcloud.ErrInvalidShowURL),
+		errors.Is(err, mixcloud.ErrDescriptionTooLong):
+		return FailureSource
+	}
+
+	var sourceErr *SourceError
+	if errors.As(err, &sourceErr) {
+		return FailureSource
+	}
+
+	return FailureUnknown
+}
+
+// BatchError is returned by ProcessAllShows when one or more shows failed
+type BatchError struct {
+	Failed     int
+	Total      int
+	Categories map[FailureCategory]int // Failed show count per category
+	Abort      *FailureAbort           // Set when a failure threshold stopped the run
+}
+
+func (e *BatchError) Error() string {
+	if e.Abort != nil {
+		return fmt.Sprintf("%d of %d shows failed, stopped at %s", e.Failed, e.Total, e.Abort.Reason)
+	}
+	return fmt.Sprintf("%d of %d shows failed", e.Failed, e.Total)
+}
+
+// OnlyCategory reports whether every failure in the batch falls in category
+func (e *BatchError) OnlyCategory(category FailureCategory) bool {
+	return e.Failed > 0 && e.Categories[category] == e.Failed
+}
+
+// categorySummary renders the per-category counts, e.g. "auth=2, server=1"
+func (e *BatchError) categorySummary() string {
+	parts := make([]string, 0, len(e.Categories))
+	for category, count := range e.Categories {
+		parts = append(parts, fmt.Sprintf("%s=%d", category, count))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// IsAuthFailure reports whether err means re-authenticating would fix it.
+// For batch errors this is only true when every failed show was an auth failure.
+func IsAuthFailure(err error) bool {
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.OnlyCategory(FailureAuth)
+	}
+	return CategorizeFailure(err) == FailureAuth
+}
@@ -0,0 +1,152 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+func TestCategorizeFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected FailureCategory
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: FailureNone,
+		},
+		{
+			name:     "CUE file problem",
+			err:      &SourceError{Err: fmt.Errorf("resolving CUE file: %w", errors.New("no files match pattern"))},
+			expected: FailureSource,
+		},
+		{
+			name:     "render check failure",
+			err:      &SourceError{Err: fmt.Errorf("render sanity check failed: %w", &RenderArtifactError{Source: "show name"})},
+			expected: FailureSource,
+		},
+		{
+			name:     "show not found during verification",
+			err:      fmt.Errorf("verifying show exists: %w", fmt.Errorf("%w: show URL https://www.mixcloud.com/u/x/", mixcloud.ErrShowNotFound)),
+			expected: FailureNotFound,
+		},
+		{
+			name:     "401 during update",
+			err:      fmt.Errorf("updating show description: %w", fmt.Errorf("%w: API authentication failed", mixcloud.ErrAuthenticationFailed)),
+			expected: FailureAuth,
+		},
+		{
+			name:     "expired token",
+			err:      fmt.Errorf("updating show description: %w", mixcloud.ErrTokenExpired),
+			expected: FailureAuth,
+		},
+		{
+			name: "OAuth refresh failure",
+			err: fmt.Errorf("verifying show exists: %w", &mixcloud.OAuthError{
+				Type:    "InvalidRefreshToken",
+				Message: "refresh token is invalid or expired",
+				Cause:   errors.New("oauth2: invalid_grant"),
+			}),
+			expected: FailureAuth,
+		},
+		{
+			name:     "rate limit after retries",
+			err:      fmt.Errorf("max retries (3) exceeded: %w", fmt.Errorf("%w: API rate limit exceeded after retries", mixcloud.ErrRateLimited)),
+			expected: FailureRateLimit,
+		},
+		{
+			name:     "5xx during update",
+			err:      fmt.Errorf("updating show description: %w", fmt.Errorf("%w: %w (status 503): busy", mixcloud.ErrAPIRequestFailed, mixcloud.ErrServerError)),
+			expected: FailureServer,
+		},
+		{
+			name:     "4xx bad request is not a server error",
+			err:      fmt.Errorf("updating show description: %w", fmt.Errorf("%w: bad request", mixcloud.ErrAPIRequestFailed)),
+			expected: FailureUnknown,
+		},
+		{
+			name:     "network failure",
+			err:      fmt.Errorf("verifying show exists: %w", fmt.Errorf("%w: HTTP request failed: dial tcp: timeout", mixcloud.ErrNetworkFailure)),
+			expected: FailureNetwork,
+		},
+		{
+			name:     "description too long is our problem",
+			err:      fmt.Errorf("updating show description: %w", fmt.Errorf("%w: length 1200", mixcloud.ErrDescriptionTooLong)),
+			expected: FailureSource,
+		},
+		{
+			name:     "unrecognised error",
+			err:      errors.New("something odd"),
+			expected: FailureUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategorizeFailure(tt.err); got != tt.expected {
+				t.Errorf("CategorizeFailure(%v) = %q, want %q", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsAuthFailure(t *testing.T) {
+	authErr := fmt.Errorf("updating show description: %w", mixcloud.ErrAuthenticationFailed)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "single auth failure",
+			err:      authErr,
+			expected: true,
+		},
+		{
+			name:     "single server failure",
+			err:      fmt.Errorf("%w: %w", mixcloud.ErrAPIRequestFailed, mixcloud.ErrServerError),
+			expected: false,
+		},
+		{
+			name:     "batch of only auth failures",
+			err:      &BatchError{Failed: 3, Total: 4, Categories: map[FailureCategory]int{FailureAuth: 3}},
+			expected: true,
+		},
+		{
+			name:     "batch with mixed failures",
+			err:      &BatchError{Failed: 3, Total: 4, Categories: map[FailureCategory]int{FailureAuth: 2, FailureServer: 1}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthFailure(tt.err); got != tt.expected {
+				t.Errorf("IsAuthFailure() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSourceFailuresAreCategorized(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "MISSING.cue"
+show_name_pattern = "The Vault"
+enabled = true
+`)
+
+	showCfg := sp.config.Shows["vault"]
+	result := sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error == nil {
+		t.Fatal("processingleShow() expected error for missing CUE file")
+	}
+	if result.FailureCategory != FailureSource {
+		t.Errorf("FailureCategory = %q, want %q", result.FailureCategory, FailureSource)
+	}
+}
@@ -0,0 +1,210 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: Atomic groups (show_group + group_atomic) exist for multi-show
+// events where a half-published series confuses listeners. Every member is
+// rendered and verified before the first update; if an update still fails, the
+// members already updated are restored from the description fetched during
+// verification.
+
+// Phases in which an atomic group can fail
+const (
+	GroupPhasePreCheck = "pre-check"
+	GroupPhaseUpdate   = "update"
+)
+
+// GroupError reports an atomic show group that was aborted because one member failed
+type GroupError struct {
+	Group  string
+	Member string // Show key of the member whose failure aborted the group
+	Phase  string // GroupPhasePreCheck or GroupPhaseUpdate
+	Err    error  // The member's own failure
+}
+
+func (e *GroupError) Error() string {
+	if e.Phase == GroupPhasePreCheck {
+		return fmt.Sprintf("group %s aborted, no shows were updated: %s failed pre-checks: %v", e.Group, e.Member, e.Err)
+	}
+	return fmt.Sprintf("group %s aborted, updated shows were rolled back: updating %s failed: %v", e.Group, e.Member, e.Err)
+}
+
+// Unwrap exposes the member's failure so the group is categorized (and exit
+// codes chosen) by what actually went wrong
+func (e *GroupError) Unwrap() error {
+	return e.Err
+}
+
+// ProcessGroup processes the enabled members of a show_group in priority order.
+// Members of an atomic group are all published or none are.
+func (sp *ShowProcessor) ProcessGroup(group string, dryRun bool) error {
+	startTime := time.Now()
+
+	members := sp.resolver.ListGroupShows(group, true)
+	if len(members) == 0 {
+		return fmt.Errorf("no enabled shows in group: %s", group)
+	}
+	atomic := sp.resolver.IsGroupAtomic(group)
+
+	mode := "independent"
+	if atomic {
+		mode = "atomic"
+	}
+	console.Printf("Processing group %s: %d shows (%s)\n", group, len(members), mode)
+	console.Printf("============================\n\n")
+
+	sp.logger.Info("Starting group processing",
+		slog.String("group", group),
+		slog.Int("total_shows", len(members)),
+		slog.Bool("atomic", atomic),
+		slog.Bool("dry_run", dryRun))
+
+	defer sp.startRun()()
+	sp.loadAnnouncement()
+	sp.emitRunStarted(RunModeGroup, group, len(members), dryRun)
+	batchResult := &BatchResult{
+		TotalShows: len(members),
+		Results:    make([]ProcessingResult, 0, len(members)),
+	}
+
+	if atomic {
+		var results []ProcessingResult
+		results, batchResult.GroupFailure = sp.processAtomicGroup(group, members, dryRun)
+		for _, result := range results {
+			batchResult.add(result)
+			sp.emitShowFinished(result)
+			sp.printBatchLine(result)
+		}
+		if batchResult.GroupFailure != nil {
+			sp.logger.Error("Show group aborted",
+				slog.String("group", group),
+				slog.String("member", batchResult.GroupFailure.Member),
+				slog.String("phase", batchResult.GroupFailure.Phase),
+				slog.String("error", batchResult.GroupFailure.Err.Error()))
+		}
+	} else {
+		abort := sp.newAbortPolicy()
+		for i, showKey := range members {
+			if sp.deadlineExceeded() {
+				sp.stopAtDeadline(batchResult, members[i:])
+				break
+			}
+			if abort.trigger != nil {
+				sp.stopAtFailureThreshold(batchResult, abort.trigger, members[i:])
+				break
+			}
+			showCfg := sp.config.Shows[showKey]
+			startShow := time.Now()
+			result := sp.processingleShow(showKey, &showCfg, "", "", dryRun)
+			result.Duration = time.Since(startShow)
+			batchResult.add(result)
+			sp.emitShowFinished(result)
+			sp.printBatchLine(result)
+			abort.record(result)
+		}
+	}
+
+	batchResult.TotalDuration = time.Since(startTime)
+	return sp.finishBatch(batchResult)
+}
+
+// processAtomicGroup prepares every member, then updates them in order. It
+// returns the per-member results and, if the group was aborted, why.
+func (sp *ShowProcessor) processAtomicGroup(group string, members []string, dryRun bool) ([]ProcessingResult, *GroupError) {
+	results := make([]ProcessingResult, 0, len(members))
+	for _, showKey := range members {
+		showCfg := sp.config.Shows[showKey]
+		startShow := time.Now()
+		result := sp.prepareShow(showKey, &showCfg, "", "", dryRun)
+		result.Duration = time.Since(startShow)
+		results = append(results, result)
+	}
+
+	// Every member must pass before anything is published
+	for _, result := range results {
+		if result.Error != nil {
+			groupErr := &GroupError{Group: group, Member: result.ShowKey, Phase: GroupPhasePreCheck, Err: result.Error}
+			abortMembers(results, groupErr)
+			return results, groupErr
+		}
+	}
+
+	if dryRun {
+		return results, nil
+	}
+
+	for i := range results {
+		if !results[i].readyToPublish() {
+			continue // Skipped by on_empty_tracklist
+		}
+
+		sp.publishShow(&results[i])
+		if results[i].Error == nil {
+			continue
+		}
+
+		groupErr := &GroupError{Group: group, Member: results[i].ShowKey, Phase: GroupPhaseUpdate, Err: results[i].Error}
+		sp.rollbackMembers(results[:i], groupErr)
+		abortMembers(results[i+1:], groupErr)
+		return results, groupErr
+	}
+
+	for i := range results {
+		if results[i].Success {
+			sp.recordPublish(&results[i])
+		}
+	}
+	return results, nil
+}
+
+// abortMembers marks members that weren't at fault as failed by the group
+func abortMembers(results []ProcessingResult, groupErr *GroupError) {
+	for i := range results {
+		if results[i].Error != nil || results[i].Skipped {
+			continue
+		}
+		results[i].Success = false
+		results[i].Error = groupErr
+		results[i].FailureCategory = CategorizeFailure(groupErr)
+	}
+}
+
+// rollbackMembers restores the previous description of every member already
+// updated, newest first, recording the outcome on each result
+func (sp *ShowProcessor) rollbackMembers(results []ProcessingResult, groupErr *GroupError) {
+	for i := len(results) - 1; i >= 0; i-- {
+		result := &results[i]
+		if !result.Success {
+			continue
+		}
+
+		result.Success = false
+		result.Error = groupErr
+		result.FailureCategory = CategorizeFailure(groupErr)
+
+		// AIDEV-NOTE: Not the run context - a restore must still go out after the
+		// run deadline, and each request is bounded by http_timeout_seconds anyway
+		if err := sp.updateShowWithRetry(context.Background(), result.ShowURL, result.formFields(result.PreviousDescription)); err != nil {
+			result.RestoreError = err
+			sp.logger.Error("Failed to restore previous description",
+				slog.String("show_key", result.ShowKey),
+				slog.String("url", result.ShowURL),
+				slog.String("error", err.Error()))
+			// The new description is still live, so the publish did happen
+			sp.recordPublish(result)
+			continue
+		}
+
+		result.Restored = true
+		sp.logger.Info("Restored previous description",
+			slog.String("show_key", result.ShowKey),
+			slog.String("url", result.ShowURL))
+	}
+}
@@ -0,0 +1,240 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// fakeMixcloud is an in-memory stand-in for the Mixcloud client. Shows are keyed
+// by URL; every existing show starts with the description "old <url>" and the
+// name "Name <url>".
+type fakeMixcloud struct {
+	missing     map[string]bool     // URLs GetShow reports as not found
+	gets        []string            // URLs passed to GetShow, in order
+	updateErrs  map[string]error    // URLs whose next update fails
+	restoreErrs map[string]error    // URLs whose update back to the old description fails
+	updates     []string            // "url=description" for every successful update, in order
+	sent        []map[string]string // Form fields of every successful update, in order
+	cache       mixcloud.ShowCache  // Set by SetShowCache
+	updateDelay time.Duration       // Added to every successful update
+	tokens      []*oauth2.Token     // Tokens passed to SaveToken
+	audioLength int                 // Audio length in seconds GetShow reports for every show
+}
+
+func (f *fakeMixcloud) SaveToken(token *oauth2.Token) error {
+	f.tokens = append(f.tokens, token)
+	return nil
+}
+
+func (f *fakeMixcloud) SetShowCache(cache mixcloud.ShowCache) {
+	f.cache = cache
+}
+
+func newFakeMixcloud() *fakeMixcloud {
+	return &fakeMixcloud{
+		missing:     make(map[string]bool),
+		updateErrs:  make(map[string]error),
+		restoreErrs: make(map[string]error),
+	}
+}
+
+func (f *fakeMixcloud) GetShowContext(ctx context.Context, showURL string) (*mixcloud.Show, error) {
+	f.gets = append(f.gets, showURL)
+	if f.missing[showURL] {
+		return nil, fmt.Errorf("%w: show URL %s", mixcloud.ErrShowNotFound, showURL)
+	}
+	return &mixcloud.Show{URL: showURL, Name: "Name " + showURL, Description: "old " + showURL, AudioLength: f.audioLength}, nil
+}
+
+func (f *fakeMixcloud) UpdateShowContext(ctx context.Context, showURL string, fields map[string]string) error {
+	description := fields["description"]
+	if description == "old "+showURL {
+		if err := f.restoreErrs[showURL]; err != nil {
+			return err
+		}
+	} else if err := f.updateErrs[showURL]; err != nil {
+		return err
+	}
+	f.updates = append(f.updates, showURL+"="+description)
+	f.sent = append(f.sent, fields)
+	time.Sleep(f.updateDelay)
+	return nil
+}
+
+const groupTestConfig = `
+[shows.fest-fri]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Fest Friday"
+show_group = "fest"
+group_atomic = true
+priority = 3
+enabled = true
+
+[shows.fest-sat]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Fest Saturday"
+show_group = "fest"
+group_atomic = true
+priority = 2
+enabled = true
+
+[shows.fest-sun]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Fest Sunday"
+show_group = "fest"
+group_atomic = true
+priority = 1
+enabled = true
+`
+
+func festURL(day string) string {
+	return mixcloud.GenerateShowURL("testuser", "Fest "+day)
+}
+
+func newGroupTestProcessor(t *testing.T) (*ShowProcessor, *fakeMixcloud) {
+	t.Helper()
+	sp := newTestProcessor(t, groupTestConfig)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	return sp, fake
+}
+
+// groupResults runs the fest group and returns its results keyed by show
+func groupResults(t *testing.T, sp *ShowProcessor, dryRun bool) (map[string]ProcessingResult, *GroupError) {
+	t.Helper()
+	members := sp.resolver.ListGroupShows("fest", true)
+	results, groupErr := sp.processAtomicGroup("fest", members, dryRun)
+
+	byKey := make(map[string]ProcessingResult, len(results))
+	for _, result := range results {
+		byKey[result.ShowKey] = result
+	}
+	return byKey, groupErr
+}
+
+func TestAtomicGroupPublishesAll(t *testing.T) {
+	sp, fake := newGroupTestProcessor(t)
+
+	results, groupErr := groupResults(t, sp, false)
+	if groupErr != nil {
+		t.Fatalf("group error = %v", groupErr)
+	}
+	for key, result := range results {
+		if !result.Success {
+			t.Errorf("%s not published: %v", key, result.Error)
+		}
+		if _, ok := sp.state.Show(key); !ok {
+			t.Errorf("%s publish not recorded in state", key)
+		}
+	}
+	if len(fake.updates) != 3 {
+		t.Errorf("updates = %v, want 3", fake.updates)
+	}
+}
+
+func TestAtomicGroupPreCheckFailureUpdatesNothing(t *testing.T) {
+	sp, fake := newGroupTestProcessor(t)
+	fake.missing[festURL("Saturday")] = true
+
+	results, groupErr := groupResults(t, sp, false)
+	if groupErr == nil {
+		t.Fatal("expected group error")
+	}
+	if groupErr.Member != "fest-sat" || groupErr.Phase != GroupPhasePreCheck {
+		t.Errorf("group error = %+v, want pre-check failure of fest-sat", groupErr)
+	}
+	if len(fake.updates) != 0 {
+		t.Errorf("updates = %v, want none", fake.updates)
+	}
+
+	if !errors.Is(results["fest-sat"].Error, mixcloud.ErrShowNotFound) {
+		t.Errorf("fest-sat error = %v, want its own not-found error", results["fest-sat"].Error)
+	}
+	for _, key := range []string{"fest-fri", "fest-sun"} {
+		var memberErr *GroupError
+		if !errors.As(results[key].Error, &memberErr) || results[key].Success {
+			t.Errorf("%s = %+v, want failed by the group", key, results[key])
+		}
+		if results[key].FailureCategory != FailureNotFound {
+			t.Errorf("%s category = %s, want the offending member's %s", key, results[key].FailureCategory, FailureNotFound)
+		}
+	}
+}
+
+func TestAtomicGroupDryRunPreCheckFailure(t *testing.T) {
+	sp := newTestProcessor(t, strings.Replace(groupTestConfig, `"Fest Sunday"`, `"Fest {{.Day}}"`, 1))
+	sp.mixcloud = newFakeMixcloud()
+
+	results, groupErr := groupResults(t, sp, true)
+	if groupErr == nil || groupErr.Member != "fest-sun" {
+		t.Fatalf("group error = %v, want failure of fest-sun", groupErr)
+	}
+	if results["fest-fri"].Success {
+		t.Error("fest-fri dry run should be marked failed by the group")
+	}
+}
+
+func TestAtomicGroupUpdateFailureRollsBack(t *testing.T) {
+	tests := []struct {
+		name         string
+		restoreFails bool
+	}{
+		{"restore succeeds", false},
+		{"restore fails", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, fake := newGroupTestProcessor(t)
+			fake.updateErrs[festURL("Saturday")] = errors.New("bad request")
+			if tt.restoreFails {
+				fake.restoreErrs[festURL("Friday")] = errors.New("forbidden")
+			}
+
+			results, groupErr := groupResults(t, sp, false)
+			if groupErr == nil || groupErr.Member != "fest-sat" || groupErr.Phase != GroupPhaseUpdate {
+				t.Fatalf("group error = %v, want update failure of fest-sat", groupErr)
+			}
+
+			fri := results["fest-fri"]
+			if fri.Success || fri.Error == nil {
+				t.Errorf("fest-fri = %+v, want failed by the group", fri)
+			}
+			if fri.Restored == tt.restoreFails || (fri.RestoreError != nil) != tt.restoreFails {
+				t.Errorf("fest-fri restored = %v, restore error = %v", fri.Restored, fri.RestoreError)
+			}
+			// State reflects what is live: only an unrestored member counts as published
+			if _, recorded := sp.state.Show("fest-fri"); recorded != tt.restoreFails {
+				t.Errorf("fest-fri recorded in state = %v, want %v", recorded, tt.restoreFails)
+			}
+
+			wantUpdates := []string{festURL("Friday") + "=" + fri.Description}
+			if !tt.restoreFails {
+				wantUpdates = append(wantUpdates, festURL("Friday")+"=old "+festURL("Friday"))
+			}
+			if strings.Join(fake.updates, "\n") != strings.Join(wantUpdates, "\n") {
+				t.Errorf("updates = %v, want %v", fake.updates, wantUpdates)
+			}
+
+			if sun := results["fest-sun"]; sun.Success || sun.Error == nil {
+				t.Errorf("fest-sun = %+v, want not updated", sun)
+			}
+		})
+	}
+}
+
+func TestProcessGroupUnknownGroup(t *testing.T) {
+	sp, _ := newGroupTestProcessor(t)
+	err := sp.ProcessGroup("missing-group", false)
+	if err == nil || !strings.Contains(err.Error(), "no enabled shows in group") {
+		t.Errorf("ProcessGroup() error = %v, want no enabled shows", err)
+	}
+}
@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// AIDEV-NOTE: -list-shows -resolve runs the first half of a dry run for every
+// enabled show - resolve, validate, sidecar, parse, filter - and stops before
+// anything is rendered or sent. A show's problem is kept in its ShowStatus
+// instead of ending the listing, so one command answers "will the next run
+// work?" for the whole station.
+
+// ShowStatus is what resolving an enabled show's CUE file found right now
+type ShowStatus struct {
+	ShowKey        string
+	CueFile        string // Resolved path, "" when nothing matched
+	Modified       time.Time
+	Size           int64
+	Stale          bool   // Older than the show's expected_interval_days
+	Title          string // Sheet-level TITLE
+	Performer      string // Sheet-level PERFORMER
+	AudioFiles     int    // FILE entries in the sheet
+	ParsedTracks   int
+	FilteredTracks int    // After the filters, a sidecar's excluded_artists included
+	TrackWarnings  int    // Malformed tracks skipped while parsing
+	Sidecar        string // Episode sidecar applied, "" for none
+	Error          error  // Where resolution stopped: no match, unreadable or unparseable file
+}
+
+// ListShowStatuses resolves, parses and filters the CUE file each enabled show
+// would publish now, in processing order. Nothing is formatted or sent.
+func (sp *ShowProcessor) ListShowStatuses() []ShowStatus {
+	enabledShows := sp.resolver.ListEnabledShows(true)
+	statuses := make([]ShowStatus, 0, len(enabledShows))
+	for _, showKey := range enabledShows {
+		showCfg := sp.config.Shows[showKey]
+		statuses = append(statuses, sp.showStatus(showKey, &showCfg))
+	}
+	return statuses
+}
+
+// showStatus resolves one show's CUE file for ListShowStatuses
+func (sp *ShowProcessor) showStatus(showKey string, showCfg *config.ShowConfig) ShowStatus {
+	status := ShowStatus{ShowKey: showKey}
+
+	cueFile, err := sp.cueResolver.ResolveCueFile(showCfg)
+	if err != nil {
+		status.Error = fmt.Errorf("resolving CUE file: %w", err)
+		return status
+	}
+	status.CueFile = cueFile
+	if err := sp.cueResolver.ValidateCueFile(cueFile); err != nil {
+		status.Error = fmt.Errorf("validating CUE file: %w", err)
+		return status
+	}
+	if info, err := os.Stat(cueFile); err == nil {
+		status.Modified = info.ModTime()
+		status.Size = info.Size()
+		status.Stale = state.IsStale(status.Modified, showCfg.ExpectedIntervalDays, sp.now())
+	}
+
+	result := ProcessingResult{ShowKey: showKey, CueFile: cueFile}
+	_, _, trackFilter, err := sp.applySidecar(&result, showCfg, "", "")
+	if err != nil {
+		status.Error = err
+		return status
+	}
+	status.Sidecar = result.Sidecar
+
+	cueSheet, warnings, err := cue.ParseCueFileWithOptions(cueFile, cue.ParseOptions{
+		Strict:          sp.options.StrictCue || sp.config.Processing.StrictCueParsing,
+		SkipIndex00Only: sp.config.Processing.SkipIndex00OnlyTracks,
+	})
+	status.TrackWarnings = len(warnings)
+	if err != nil {
+		status.Error = fmt.Errorf("parsing CUE file: %w", err)
+		return status
+	}
+	status.Title = cueSheet.Title
+	status.Performer = cueSheet.Performer
+	status.AudioFiles = len(cueSheet.Files)
+	status.ParsedTracks = len(cueSheet.Tracks)
+	if err := sp.checkTrackWarnings(status.ParsedTracks, status.TrackWarnings); err != nil {
+		status.Error = fmt.Errorf("parsing CUE file: %w", err)
+		return status
+	}
+
+	filtered, _ := trackFilter.Apply(cueSheet.Tracks)
+	status.FilteredTracks = len(filtered)
+	return status
+}
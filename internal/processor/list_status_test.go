@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListShowStatuses(t *testing.T) {
+	sp := newTestProcessor(t, `
+[filtering]
+excluded_artists = ["Airline Food"]
+
+[shows.sheet]
+cue_file_mapping = "SHEET.cue"
+show_name_pattern = "Sheet"
+enabled = true
+priority = 1
+
+[shows.missing]
+cue_file_mapping = "MISSING.cue"
+show_name_pattern = "Missing"
+enabled = true
+
+[shows.broken]
+cue_file_mapping = "BROKEN.cue"
+show_name_pattern = "Broken"
+enabled = true
+
+[shows.stale]
+cue_file_pattern = "TEST*.cue"
+show_name_pattern = "Stale"
+expected_interval_days = 7
+enabled = true
+
+[shows.off]
+cue_file_mapping = "MISSING.cue"
+show_name_pattern = "Off"
+enabled = false
+`)
+	dir := filepath.Dir(sp.configPath)
+	sheet := strings.Replace(strings.Replace(testCueContent, `PERFORMER ""`, `PERFORMER "Now Wave Radio"`, 1), `TITLE ""`, `TITLE "Sounds Like"`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "SHEET.cue"), []byte(sheet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := "TRACK 01 AUDIO\n  TITLE \"A\"\n  PERFORMER \"B\"\nTRACK xx AUDIO\n  TITLE \"C\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "BROKEN.cue"), []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+	monthAgo := time.Now().AddDate(0, 0, -30)
+	if err := os.Chtimes(filepath.Join(dir, "TEST.cue"), monthAgo, monthAgo); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := sp.ListShowStatuses()
+	byKey := make(map[string]ShowStatus, len(statuses))
+	for _, status := range statuses {
+		byKey[status.ShowKey] = status
+	}
+	if len(statuses) != 4 || statuses[0].ShowKey != "sheet" {
+		t.Fatalf("statuses = %+v, want the 4 enabled shows in priority order", statuses)
+	}
+	if _, listed := byKey["off"]; listed {
+		t.Error("disabled show was resolved")
+	}
+
+	tests := []struct {
+		showKey   string
+		wantErr   string
+		title     string
+		performer string
+		parsed    int
+		filtered  int
+		stale     bool
+	}{
+		{showKey: "sheet", title: "Sounds Like", performer: "Now Wave Radio", parsed: 3, filtered: 2},
+		{showKey: "missing", wantErr: "CUE file"},
+		{showKey: "broken", wantErr: "parsing CUE file"},
+		{showKey: "stale", parsed: 3, filtered: 2, stale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.showKey, func(t *testing.T) {
+			status := byKey[tt.showKey]
+			if tt.wantErr != "" {
+				if status.Error == nil || !strings.Contains(status.Error.Error(), tt.wantErr) {
+					t.Errorf("Error = %v, want one containing %q", status.Error, tt.wantErr)
+				}
+				return
+			}
+			if status.Error != nil {
+				t.Fatalf("Error = %v", status.Error)
+			}
+			if status.Title != tt.title || status.Performer != tt.performer {
+				t.Errorf("sheet = %q by %q, want %q by %q", status.Title, status.Performer, tt.title, tt.performer)
+			}
+			if status.ParsedTracks != tt.parsed || status.FilteredTracks != tt.filtered {
+				t.Errorf("tracks = %d parsed, %d filtered; want %d, %d", status.ParsedTracks, status.FilteredTracks, tt.parsed, tt.filtered)
+			}
+			if status.Stale != tt.stale {
+				t.Errorf("Stale = %v, want %v", status.Stale, tt.stale)
+			}
+			if status.AudioFiles != 1 || status.Size == 0 || status.Modified.IsZero() {
+				t.Errorf("status = %+v, want one audio file and the file's size and age", status)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const localeTestConfig = `
+[templates.config.lista]
+header = "{{.ShowTitle}}\n"
+track = "{{.Index}}. {{.Artist}} - {{.Title}}\n"
+
+[shows.noche]
+cue_file_mapping = "NOCHE.cue"
+show_name_pattern = "La Noche"
+enabled = true
+locale = "es"
+description_max_length = 250
+template = "classic"
+
+[shows.noche-lista]
+cue_file_mapping = "NOCHE.cue"
+show_name_pattern = "La Noche Lista"
+enabled = true
+locale = "es"
+description_max_length = 250
+template = "lista"
+
+[shows.noche-propia]
+cue_file_mapping = "NOCHE.cue"
+show_name_pattern = "La Noche Propia"
+enabled = true
+locale = "es"
+description_max_length = 250
+template = "classic"
+strings.and_more = "... ¡y muchas más!"
+`
+
+// writeLocaleCue writes NOCHE.cue with tracks tracks, the first without a performer
+func writeLocaleCue(t *testing.T, dir string, tracks int) {
+	t.Helper()
+	var cue strings.Builder
+	cue.WriteString("FILE \"NOCHE.wav\" WAV\n")
+	for i := 1; i <= tracks; i++ {
+		fmt.Fprintf(&cue, "  TRACK %02d AUDIO\n    TITLE \"Canción %d\"\n", i, i)
+		if i > 1 {
+			fmt.Fprintf(&cue, "    PERFORMER \"Grupo %d\"\n", i)
+		}
+		fmt.Fprintf(&cue, "    INDEX 01 %02d:00:00\n", i*4)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "NOCHE.cue"), []byte(cue.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpanishShowDescriptions(t *testing.T) {
+	tests := []struct {
+		show      string
+		wantStart string
+		wantEnd   *regexp.Regexp
+	}{
+		{"noche", `04:00 - "Canción 1" by (Artista desconocido)` + "\n", regexp.MustCompile(`\n\.\.\. y más$`)},
+		{"noche-lista", "La Noche Lista\n1.  - Canción 1\n", regexp.MustCompile(`\n\.\.\. y \d+ temas más\n$`)},
+		{"noche-propia", `04:00 - "Canción 1" by (Artista desconocido)` + "\n", regexp.MustCompile(`\n\.\.\. ¡y muchas más!$`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.show, func(t *testing.T) {
+			sp := newTestProcessor(t, localeTestConfig)
+			writeLocaleCue(t, sp.config.Processing.CueFileDirectory, 20)
+			sp.mixcloud = newFakeMixcloud()
+
+			showCfg := sp.config.Shows[tt.show]
+			result := sp.processingleShow(tt.show, &showCfg, "", "", true)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+			if !strings.HasPrefix(result.Description, tt.wantStart) {
+				t.Errorf("description = %q, want it to start %q", result.Description, tt.wantStart)
+			}
+			if !tt.wantEnd.MatchString(result.Description) {
+				t.Errorf("description = %q, want it to end with %s", result.Description, tt.wantEnd)
+			}
+			if len(result.Description) > showCfg.DescriptionMaxLength {
+				t.Errorf("description is %d bytes, over the show's limit of %d", len(result.Description), showCfg.DescriptionMaxLength)
+			}
+			if summary := dryRunSummary(result); !strings.Contains(summary, "truncated: yes") {
+				t.Errorf("dryRunSummary() = %q, want the localized marker recognized", summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/desclen"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// AIDEV-NOTE: During a Mixcloud outage a batch used to fail every show after
+// the full retry policy, and the rendered tracklists were lost with the
+// failures. Offline runs (-offline, or offline_fallback once the run's first
+// Mixcloud call fails with a network error) stop before the API: each show is
+// rendered and checked as usual, then archived to offline_dir, copied to
+// -output and queued in the pending-updates file. ProcessPending (-retry-failed)
+// later publishes the queued text as it was rendered. Offline shows never reach
+// sp.mixcloud; the tests enforce this with a client that panics on use.
+
+// OfflineReasonFlag is the offline reason recorded for runs started with -offline
+const OfflineReasonFlag = "-offline"
+
+// ErrNoPendingUpdates is returned by ProcessPending when nothing is queued
+var ErrNoPendingUpdates = fmt.Errorf("%w: no pending updates queued", ErrNoShowsProcessed)
+
+// OfflineRunError is returned by runs that finished offline, so callers can
+// tell queued descriptions from published ones
+type OfflineRunError struct {
+	Queued int // Descriptions queued for -retry-failed
+}
+
+func (e *OfflineRunError) Error() string {
+	return fmt.Sprintf("completed offline (%d descriptions queued)", e.Queued)
+}
+
+// IsOfflineRun reports whether err means the run finished offline rather than failed
+func IsOfflineRun(err error) bool {
+	var offlineErr *OfflineRunError
+	return errors.As(err, &offlineErr)
+}
+
+// offline reports whether shows are queued instead of published
+func (sp *ShowProcessor) offline() bool {
+	return sp.options.Offline || sp.wentOffline != ""
+}
+
+// offlineRunReason returns why the run is offline, for the pending queue and logs
+func (sp *ShowProcessor) offlineRunReason() string {
+	if sp.wentOffline != "" {
+		return sp.wentOffline
+	}
+	return OfflineReasonFlag
+}
+
+// fallBackOffline switches the rest of the run offline when offline_fallback is
+// set and result failed on the run's first Mixcloud call with a network error.
+// It reports whether it did, in which case the show should be processed again.
+func (sp *ShowProcessor) fallBackOffline(result ProcessingResult) bool {
+	if !sp.config.Processing.OfflineFallback || sp.offline() || result.DryRun {
+		return false
+	}
+	if result.FailureCategory != FailureNetwork || sp.apiShows != 1 {
+		return false
+	}
+
+	sp.wentOffline = result.Error.Error()
+	sp.logger.Warn("Mixcloud unreachable, finishing the run offline",
+		slog.String("show_key", result.ShowKey),
+		slog.String("error", result.Error.Error()))
+	console.Printf("⚠️  Mixcloud unreachable (%v)\n", result.Error)
+	console.Printf("   offline_fallback: queueing descriptions for -retry-failed instead\n\n")
+	return true
+}
+
+// queueOffline archives a prepared show's descriptions and queues them for
+// -retry-failed instead of publishing. A show that can't be queued fails, since
+// its description would otherwise be lost.
+func (sp *ShowProcessor) queueOffline(result *ProcessingResult) {
+	sp.writePreviewOutput(*result)
+
+	archivePath, err := sp.archiveDescription(*result)
+	if err != nil {
+		// The queue still holds the description
+		sp.logger.Warn("Failed to archive offline description",
+			slog.String("show_key", result.ShowKey),
+			slog.String("error", err.Error()))
+	}
+	result.ArchivePath = archivePath
+
+	pending, err := sp.loadPending()
+	if err != nil {
+		result.Error = err
+		return
+	}
+	pending.Queue(pendingUpdate(*result, sp.offlineRunReason(), time.Now()))
+	if err := pending.Save(); err != nil {
+		result.Error = fmt.Errorf("queueing offline update: %w", err)
+		return
+	}
+
+	sp.logger.Info("Show queued offline",
+		slog.String("show_key", result.ShowKey),
+		slog.String("url", result.ShowURL),
+		slog.String("archive", archivePath),
+		slog.String("pending_file", pending.Path()),
+		slog.String("reason", sp.offlineRunReason()))
+	sp.emitStep(result.ShowKey, StepQueue, pending.Path(), map[string]int{"queued": pending.Len()})
+	result.Queued = true
+}
+
+// archiveDescription writes a show's descriptions to <offline_dir>/<show>_<date>.txt,
+// replacing an earlier archive of the same episode, and returns the file's path
+func (sp *ShowProcessor) archiveDescription(result ProcessingResult) (string, error) {
+	dir := sp.config.OfflineDirPath(sp.configPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating offline directory %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s.txt", result.ShowKey, result.ShowDate))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("creating offline archive: %w", err)
+	}
+	writeErr := writeDescriptions(file, result)
+	if err := errors.Join(writeErr, file.Close()); err != nil {
+		return "", fmt.Errorf("writing offline archive %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// queuedSummary describes a queued show, e.g. "812 chars, 14 tracks, archived to offline/nnw_2025-06-28.txt"
+func queuedSummary(result ProcessingResult) string {
+	summary := fmt.Sprintf("%d chars, %d tracks", result.FormattedLength, result.FilteredTracks)
+	if len(result.Parts) > 0 {
+		summary = fmt.Sprintf("%d parts (%s), %d tracks", len(result.Parts), partsLengthSummary(result), result.FilteredTracks)
+	}
+	if result.ArchivePath != "" {
+		return summary + ", archived to " + result.ArchivePath
+	}
+	return summary + ", not archived (see log)"
+}
+
+// loadPending loads the pending-updates file on first use. A file that can't be
+// read is not overwritten.
+func (sp *ShowProcessor) loadPending() (*state.Pending, error) {
+	if sp.pending != nil {
+		return sp.pending, nil
+	}
+	pending, err := state.LoadPending(state.ResolvePendingPath(sp.config.Processing.PendingFile, sp.configPath))
+	if err != nil {
+		return nil, err
+	}
+	sp.pending = pending
+	return pending, nil
+}
+
+// dropPending removes a queued offline update for a show that was just
+// published normally, so -retry-failed doesn't overwrite it with the older text
+func (sp *ShowProcessor) dropPending(result *ProcessingResult) {
+	pending, err := sp.loadPending()
+	if err != nil || !pending.Remove(result.ShowKey, result.generatedURL) {
+		return
+	}
+	if err := pending.Save(); err != nil {
+		sp.logger.Warn("Failed to save pending updates file",
+			slog.String("show_key", result.ShowKey),
+			slog.String("path", pending.Path()),
+			slog.String("error", err.Error()))
+	}
+}
+
+// pendingUpdate records a prepared show for the pending-updates queue
+func pendingUpdate(result ProcessingResult, reason string, queuedAt time.Time) state.PendingUpdate {
+	update := state.PendingUpdate{
+		ShowKey:        result.ShowKey,
+		ShowName:       result.ShowName,
+		ShowDate:       result.ShowDate,
+		ShowURL:        result.ShowURL,
+		GeneratedURL:   result.generatedURL,
+		CueFile:        result.CueFile,
+		Template:       result.Template,
+		Description:    result.Description,
+		UpdateFields:   result.UpdateFields,
+		NewName:        result.NewName,
+		PreserveName:   result.PreserveName,
+		ParsedTracks:   result.ParsedTracks,
+		Tracks:         result.FilteredTracks,
+		ExcludedTracks: result.ExcludedTracks,
+		Placeholder:    result.Placeholder,
+		QueuedAt:       queuedAt,
+		Reason:         reason,
+	}
+	for _, part := range result.Parts {
+		update.Parts = append(update.Parts, state.PendingPart{
+			Number:      part.Number,
+			ShowURL:     part.ShowURL,
+			Tracks:      part.Tracks,
+			Description: part.Description,
+			Placeholder: part.Placeholder,
+		})
+	}
+	return update
+}
+
+// pendingResult rebuilds the prepared show a queued update was made from
+func pendingResult(update state.PendingUpdate, dryRun bool) ProcessingResult {
+	result := ProcessingResult{
+		ShowKey:         update.ShowKey,
+		ShowName:        update.ShowName,
+		ShowDate:        update.ShowDate,
+		CueFile:         update.CueFile,
+		Template:        update.Template,
+		Description:     update.Description,
+		FormattedLength: len(update.Description),
+		RenderedLength:  desclen.Rendered(update.Description),
+		UpdateFields:    update.UpdateFields,
+		NewName:         update.NewName,
+		PreserveName:    update.PreserveName,
+		ShowURL:         update.ShowURL,
+		generatedURL:    update.GeneratedURL,
+		ParsedTracks:    update.ParsedTracks,
+		FilteredTracks:  update.Tracks,
+		ExcludedTracks:  update.ExcludedTracks,
+		Placeholder:     update.Placeholder,
+		DryRun:          dryRun,
+	}
+	if result.UpdateFields == nil {
+		result.UpdateFields = make(map[string]string)
+	}
+	if len(update.Parts) > 0 {
+		result.FormattedLength = 0
+		result.RenderedLength = 0
+	}
+	for _, part := range update.Parts {
+		result.Parts = append(result.Parts, PartResult{
+			Number:          part.Number,
+			ShowURL:         part.ShowURL,
+			Tracks:          part.Tracks,
+			Description:     part.Description,
+			FormattedLength: len(part.Description),
+			RenderedLength:  desclen.Rendered(part.Description),
+			Placeholder:     part.Placeholder,
+		})
+		result.FormattedLength += len(part.Description)
+		result.RenderedLength += desclen.Rendered(part.Description)
+	}
+	return result
+}
+
+// ProcessPending publishes the updates queued by offline runs (-retry-failed),
+// oldest first. Published updates leave the queue; failed ones stay for the
+// next retry. Dry runs list the queue without contacting Mixcloud.
+func (sp *ShowProcessor) ProcessPending(dryRun bool) error {
+	startTime := time.Now()
+
+	pending, err := sp.loadPending()
+	if err != nil {
+		return fmt.Errorf("loading pending updates: %w", err)
+	}
+	updates := append([]state.PendingUpdate(nil), pending.Updates...)
+
+	batchResult := &BatchResult{
+		TotalShows: len(updates),
+		Results:    make([]ProcessingResult, 0, len(updates)),
+	}
+	sp.emitRunStarted(RunModeRetry, pending.Path(), len(updates), dryRun)
+	if len(updates) == 0 {
+		err := sp.finishEmptyRun(batchResult, ErrNoPendingUpdates,
+			fmt.Sprintf("No pending updates queued in %s.", pending.Path()),
+			"Offline runs (-offline or offline_fallback) queue descriptions here.")
+		batchResult.TotalDuration = time.Since(startTime)
+		sp.emitRunFinished(batchResult)
+		return err
+	}
+
+	console.Printf("Publishing %d pending updates\n", len(updates))
+	console.Printf("============================\n\n")
+	defer sp.startRun()()
+
+	for i, update := range updates {
+		if sp.deadlineExceeded() {
+			remaining := make([]string, 0, len(updates)-i)
+			for _, left := range updates[i:] {
+				remaining = append(remaining, left.ShowKey)
+			}
+			sp.stopAtDeadline(batchResult, remaining)
+			break
+		}
+
+		startShow := time.Now()
+		sp.emit(ProgressEvent{Event: EventShowStarted, ShowKey: update.ShowKey})
+		var result ProcessingResult
+		if dryRun {
+			result = pendingResult(update, true)
+			result.Success = true
+		} else {
+			result = sp.publishPending(update)
+			result = sp.retryAfterReauth(result, func() ProcessingResult {
+				return sp.publishPending(update)
+			})
+		}
+		result.Duration = time.Since(startShow)
+
+		if result.Success && !dryRun {
+			sp.recordPublish(&result)
+		}
+		batchResult.add(result)
+		sp.emitShowFinished(result)
+		sp.printBatchLine(result)
+	}
+
+	if pending.Len() > 0 {
+		console.Printf("%d updates still queued in %s\n", pending.Len(), pending.Path())
+	}
+	batchResult.TotalDuration = time.Since(startTime)
+	return sp.finishBatch(batchResult)
+}
+
+// publishPending verifies and publishes one queued update
+func (sp *ShowProcessor) publishPending(update state.PendingUpdate) ProcessingResult {
+	result := pendingResult(update, false)
+	sp.logger.Info("Publishing pending update",
+		slog.String("show_key", result.ShowKey),
+		slog.String("url", result.ShowURL),
+		slog.Time("queued_at", update.QueuedAt))
+
+	// A show renamed since the update was queued lives at its new URL
+	if len(result.Parts) == 0 && result.generatedURL != "" {
+		result.ShowURL = result.generatedURL
+		sp.followRename(&result)
+	}
+
+	showCfg, ok := sp.config.Shows[update.ShowKey]
+	if !ok {
+		// Removed from the config since; publish what was queued
+		showCfg = config.ShowConfig{PreserveName: update.PreserveName}
+	}
+	sp.verifyForPublish(&result, &showCfg)
+	if result.readyToPublish() {
+		sp.publishShow(&result)
+	}
+	if result.Error != nil && result.FailureCategory == FailureNone {
+		result.FailureCategory = CategorizeFailure(result.Error)
+	}
+	return result
+}
@@ -0,0 +1,246 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+)
+
+// panicMixcloud fails the test run outright if an offline show touches the API
+type panicMixcloud struct{}
+
+func (panicMixcloud) GetShowContext(ctx context.Context, showURL string) (*mixcloud.Show, error) {
+	panic("offline run called GetShow for " + showURL)
+}
+
+func (panicMixcloud) UpdateShowContext(ctx context.Context, showURL string, fields map[string]string) error {
+	panic("offline run called UpdateShow for " + showURL)
+}
+
+// unreachableMixcloud fails every call with a network error, like a Mixcloud outage
+type unreachableMixcloud struct {
+	gets []string
+}
+
+func (u *unreachableMixcloud) GetShowContext(ctx context.Context, showURL string) (*mixcloud.Show, error) {
+	u.gets = append(u.gets, showURL)
+	return nil, fmt.Errorf("%w: HTTP request failed: dial tcp: connection refused", mixcloud.ErrNetworkFailure)
+}
+
+func (u *unreachableMixcloud) UpdateShowContext(ctx context.Context, showURL string, fields map[string]string) error {
+	return fmt.Errorf("%w: HTTP request failed: dial tcp: connection refused", mixcloud.ErrNetworkFailure)
+}
+
+const offlineTestConfig = `
+[shows.night-one]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Night One"
+priority = 2
+enabled = true
+
+[shows.night-two]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Night Two"
+priority = 1
+enabled = true
+`
+
+const (
+	nightOneURL = "https://www.mixcloud.com/testuser/night-one/"
+	nightTwoURL = "https://www.mixcloud.com/testuser/night-two/"
+)
+
+func loadTestPending(t *testing.T, sp *ShowProcessor) *state.Pending {
+	t.Helper()
+	pending, err := state.LoadPending(state.ResolvePendingPath(sp.config.Processing.PendingFile, sp.configPath))
+	if err != nil {
+		t.Fatalf("LoadPending() error = %v", err)
+	}
+	return pending
+}
+
+func pendingURLs(pending *state.Pending) []string {
+	urls := make([]string, 0, pending.Len())
+	for _, update := range pending.Updates {
+		urls = append(urls, update.ShowURL)
+	}
+	return urls
+}
+
+func TestOfflineRunMakesNoAPICalls(t *testing.T) {
+	sp := newTestProcessor(t, offlineTestConfig)
+	sp.mixcloud = panicMixcloud{}
+	var output strings.Builder
+	sp.SetOptions(Options{Offline: true, PreviewOutput: &output})
+
+	err := sp.ProcessAllShows(false)
+	var offlineErr *OfflineRunError
+	if !errors.As(err, &offlineErr) || offlineErr.Queued != 2 {
+		t.Fatalf("ProcessAllShows() error = %v, want 2 descriptions queued", err)
+	}
+	if err.Error() != "completed offline (2 descriptions queued)" {
+		t.Errorf("error = %q", err)
+	}
+
+	pending := loadTestPending(t, sp)
+	if got := strings.Join(pendingURLs(pending), " "); got != nightOneURL+" "+nightTwoURL {
+		t.Errorf("queued URLs = %s", got)
+	}
+	for _, update := range pending.Updates {
+		if update.Reason != OfflineReasonFlag || !strings.Contains(update.Description, "When I Fall") {
+			t.Errorf("queued update = %+v", update)
+		}
+	}
+
+	archives, _ := filepath.Glob(filepath.Join(sp.config.OfflineDirPath(sp.configPath), "*.txt"))
+	if len(archives) != 2 {
+		t.Fatalf("archived %v, want a file per show", archives)
+	}
+	archived, err := os.ReadFile(archives[0])
+	if err != nil || !strings.Contains(string(archived), "=== night-one: Night One ===") {
+		t.Errorf("archive %s = %q (%v)", archives[0], archived, err)
+	}
+	if !strings.Contains(output.String(), "=== night-two: Night Two ===") {
+		t.Errorf("-output = %q, want both shows", output.String())
+	}
+}
+
+func TestOfflineSingleShow(t *testing.T) {
+	sp := newTestProcessor(t, offlineTestConfig)
+	sp.mixcloud = panicMixcloud{}
+	sp.SetOptions(Options{Offline: true})
+
+	err := sp.ProcessShow("night-two", "", "", false)
+	if !IsOfflineRun(err) {
+		t.Fatalf("ProcessShow() error = %v, want an offline run", err)
+	}
+	if got := pendingURLs(loadTestPending(t, sp)); len(got) != 1 || got[0] != nightTwoURL {
+		t.Errorf("queued URLs = %v", got)
+	}
+}
+
+func TestOfflineFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		fallback    bool
+		wantOffline bool
+		wantGets    int // Every show verified when the run doesn't fall back
+	}{
+		{"fallback enabled", true, true, 1},
+		{"fallback disabled", false, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, offlineTestConfig)
+			sp.config.Processing.OfflineFallback = tt.fallback
+			sp.config.Processing.RetryAttempts = 1
+			sp.retryPolicy = mixcloud.RetryPolicy(sp.config)
+			sp.retryPolicy.Sleep = func(context.Context, time.Duration) error { return nil }
+			client := &unreachableMixcloud{}
+			sp.mixcloud = client
+
+			err := sp.ProcessAllShows(false)
+			if IsOfflineRun(err) != tt.wantOffline {
+				t.Fatalf("ProcessAllShows() error = %v, want offline %v", err, tt.wantOffline)
+			}
+			if len(client.gets) != tt.wantGets {
+				t.Errorf("GetShow calls = %v, want %d", client.gets, tt.wantGets)
+			}
+			if queued := loadTestPending(t, sp).Len(); tt.wantOffline != (queued == 2) {
+				t.Errorf("queued %d updates", queued)
+			}
+		})
+	}
+}
+
+func TestOfflineFallbackOnlyForFirstAPICall(t *testing.T) {
+	sp := newTestProcessor(t, offlineTestConfig)
+	sp.config.Processing.OfflineFallback = true
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	// night-one reached Mixcloud fine; a later network failure is just a failed show
+	showCfg := sp.config.Shows["night-one"]
+	if result := sp.processingleShow("night-one", &showCfg, "", "", false); !result.Success {
+		t.Fatalf("night-one error = %v", result.Error)
+	}
+	sp.mixcloud = &unreachableMixcloud{}
+	sp.retryPolicy.Sleep = func(context.Context, time.Duration) error { return nil }
+	showCfg = sp.config.Shows["night-two"]
+	result := sp.processingleShow("night-two", &showCfg, "", "", false)
+	if result.FailureCategory != FailureNetwork {
+		t.Fatalf("night-two category = %q, want network", result.FailureCategory)
+	}
+	if sp.fallBackOffline(result) {
+		t.Error("fallBackOffline() = true after an earlier show reached Mixcloud")
+	}
+}
+
+func TestProcessPending(t *testing.T) {
+	sp := newTestProcessor(t, offlineTestConfig)
+	sp.mixcloud = panicMixcloud{}
+	sp.SetOptions(Options{Offline: true})
+	if err := sp.ProcessAllShows(false); !IsOfflineRun(err) {
+		t.Fatalf("offline ProcessAllShows() error = %v", err)
+	}
+
+	// Mixcloud is back, but night-two's upload hasn't finished
+	fake := newFakeMixcloud()
+	fake.missing[nightTwoURL] = true
+	sp.mixcloud = fake
+	sp.SetOptions(Options{})
+
+	var batchErr *BatchError
+	if err := sp.ProcessPending(false); !errors.As(err, &batchErr) || batchErr.Failed != 1 {
+		t.Fatalf("ProcessPending() error = %v, want 1 failed show", err)
+	}
+	if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], nightOneURL+"=") ||
+		!strings.Contains(fake.updates[0], "When I Fall") {
+		t.Errorf("updates = %v, want night-one's queued description", fake.updates)
+	}
+	if got := pendingURLs(loadTestPending(t, sp)); len(got) != 1 || got[0] != nightTwoURL {
+		t.Errorf("still queued %v, want night-two", got)
+	}
+	if published, ok := sp.state.Show("night-one"); !ok || published.ShowURL != nightOneURL {
+		t.Errorf("state for night-one = %+v, %v", published, ok)
+	}
+
+	delete(fake.missing, nightTwoURL)
+	if err := sp.ProcessPending(false); err != nil {
+		t.Fatalf("second ProcessPending() error = %v", err)
+	}
+	if loadTestPending(t, sp).Len() != 0 {
+		t.Error("queue not empty after every update was published")
+	}
+	if err := sp.ProcessPending(false); !errors.Is(err, ErrNoPendingUpdates) || !IsEmptyRun(err) {
+		t.Errorf("ProcessPending() on an empty queue error = %v, want ErrNoPendingUpdates", err)
+	}
+}
+
+func TestPublishRemovesPendingUpdate(t *testing.T) {
+	sp := newTestProcessor(t, offlineTestConfig)
+	sp.mixcloud = panicMixcloud{}
+	sp.SetOptions(Options{Offline: true})
+	if err := sp.ProcessShow("night-one", "", "", false); !IsOfflineRun(err) {
+		t.Fatalf("offline ProcessShow() error = %v", err)
+	}
+
+	// A normal run publishes the episode first; -retry-failed mustn't overwrite it later
+	sp.mixcloud = newFakeMixcloud()
+	sp.SetOptions(Options{})
+	if err := sp.ProcessShow("night-one", "", "", false); err != nil {
+		t.Fatalf("ProcessShow() error = %v", err)
+	}
+	if queued := loadTestPending(t, sp).Len(); queued != 0 {
+		t.Errorf("%d updates still queued after the show was published", queued)
+	}
+}
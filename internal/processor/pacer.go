@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"sync"
+	"time"
+)
+
+// AIDEV-NOTE: Mixcloud throttles bursts of edits from one account, so description
+// updates are spaced proactively instead of only backing off after a 429. Only
+// UpdateShow calls are paced - GET verification and dry runs are not.
+
+// updatePacer enforces a minimum interval between consecutive update calls.
+// It is safe for concurrent use so it can be shared across workers.
+type updatePacer struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastCall time.Time
+	waited   time.Duration // Total time spent waiting
+
+	// Swappable for tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// newUpdatePacer creates a pacer; a zero interval disables pacing
+func newUpdatePacer(interval time.Duration) *updatePacer {
+	return &updatePacer{
+		interval: interval,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// Wait blocks until the interval has passed since the previous call, then
+// records this call. It returns how long it waited.
+func (p *updatePacer) Wait() time.Duration {
+	if p == nil || p.interval <= 0 {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var wait time.Duration
+	if !p.lastCall.IsZero() {
+		wait = p.interval - p.now().Sub(p.lastCall)
+	}
+	if wait > 0 {
+		p.sleep(wait)
+		p.waited += wait
+	} else {
+		wait = 0
+	}
+
+	p.lastCall = p.now()
+	return wait
+}
+
+// Waited returns the total time spent pacing so far
+func (p *updatePacer) Waited() time.Duration {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.waited
+}
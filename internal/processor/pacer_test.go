@@ -0,0 +1,62 @@
+package processor
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when the pacer sleeps or the test says so
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Sleep(d time.Duration)   { c.now = c.now.Add(d) }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestUpdatePacer(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 6, 30, 9, 0, 0, 0, time.UTC)}
+	pacer := newUpdatePacer(10 * time.Second)
+	pacer.now = clock.Now
+	pacer.sleep = clock.Sleep
+
+	steps := []struct {
+		name     string
+		elapsed  time.Duration // Time passing before the call
+		wantWait time.Duration
+	}{
+		{"first call never waits", 0, 0},
+		{"immediate second call waits full interval", 0, 10 * time.Second},
+		{"partial interval elapsed", 4 * time.Second, 6 * time.Second},
+		{"interval already elapsed", 15 * time.Second, 0},
+	}
+
+	var total time.Duration
+	for _, step := range steps {
+		clock.Advance(step.elapsed)
+		if got := pacer.Wait(); got != step.wantWait {
+			t.Errorf("%s: Wait() = %v, want %v", step.name, got, step.wantWait)
+		}
+		total += step.wantWait
+	}
+
+	if pacer.Waited() != total {
+		t.Errorf("Waited() = %v, want %v", pacer.Waited(), total)
+	}
+}
+
+func TestUpdatePacerDisabled(t *testing.T) {
+	pacer := newUpdatePacer(0)
+	pacer.sleep = func(time.Duration) { t.Fatal("disabled pacer should never sleep") }
+
+	for i := 0; i < 3; i++ {
+		if got := pacer.Wait(); got != 0 {
+			t.Errorf("Wait() = %v, want 0", got)
+		}
+	}
+
+	var nilPacer *updatePacer
+	if nilPacer.Wait() != 0 || nilPacer.Waited() != 0 {
+		t.Error("nil pacer should not wait")
+	}
+}
@@ -0,0 +1,244 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/desclen"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+// AIDEV-NOTE: Broadcasts over the account's upload limit go up as separate
+// Mixcloud shows ("... (Part 1)", "... (Part 2)") whose slugs share the show's
+// slug plus part_url_suffix_pattern. Each part is rendered, verified and updated
+// on its own, and a part that fails doesn't stop the others from being updated.
+// The show fails if any part does, so the next run retries the whole show.
+
+// PartResult is the outcome of one part of a show split with split_at
+type PartResult struct {
+	Number              int    // 1-based position in the show
+	ShowURL             string // The part's Mixcloud URL
+	Tracks              int    // Filtered tracks that start in this part
+	Description         string
+	FormattedLength     int
+	RenderedLength      int    // Estimated length once Mixcloud renders it, see desclen.Rendered
+	Placeholder         bool   // No tracks start in this part, so the placeholder line is published
+	Name                string // Current title re-sent with the update (preserve_name)
+	Verified            bool   // The part's URL resolved on Mixcloud
+	LiveShowName        string // The part's current name on Mixcloud, once verified
+	PreviousDescription string // The part's description on Mixcloud before the update
+	Success             bool
+	Error               error
+}
+
+// PartsError reports that some parts of a split show failed. Parts not counted
+// in Failed were updated.
+type PartsError struct {
+	Failed int
+	Total  int
+	Err    error // The first part's failure
+}
+
+func (e *PartsError) Error() string {
+	return fmt.Sprintf("%d of %d parts failed: %v", e.Failed, e.Total, e.Err)
+}
+
+func (e *PartsError) Unwrap() error {
+	return e.Err
+}
+
+// prepareParts splits a show's filtered tracks at its split_at offsets and
+// renders and checks a description per part. result.ShowURL becomes part 1's
+// URL and result.Description all parts' descriptions, for the state file.
+func (sp *ShowProcessor) prepareParts(result *ProcessingResult, showCfg *config.ShowConfig, templateOverride string, tracks []cue.Track, metadata template.Metadata) error {
+	offsets, err := showCfg.SplitOffsets()
+	if err != nil {
+		return fmt.Errorf("split_at: %w", err)
+	}
+	segments := cue.SplitTracks(tracks, offsets)
+
+	result.Parts = make([]PartResult, 0, len(segments))
+	descriptions := make([]string, 0, len(segments))
+	for i, partTracks := range segments {
+		part := PartResult{
+			Number:      i + 1,
+			ShowURL:     partURL(result.ShowURL, showCfg.PartURLSuffix(i+1)),
+			Tracks:      len(partTracks),
+			Placeholder: len(partTracks) == 0,
+		}
+
+		partMetadata := metadata
+		partMetadata.PartNumber = part.Number
+		partMetadata.PartCount = len(segments)
+		partMetadata.IncludedCount = part.Tracks
+
+		part.Description = sp.formatDescription(result, showCfg, templateOverride, partTracks, part.Placeholder, partMetadata)
+		part.FormattedLength = len(part.Description)
+		part.RenderedLength = desclen.Rendered(part.Description)
+
+		sp.logger.Info("Part tracklist formatted",
+			slog.String("show_key", result.ShowKey),
+			slog.Int("part", part.Number),
+			slog.Int("tracks", part.Tracks),
+			slog.String("template", result.Template),
+			slog.Int("length", part.FormattedLength),
+			slog.Int("rendered_length", part.RenderedLength))
+
+		if err := sp.checkDescription(result.ShowKey, part.Description, showCfg.DescriptionLimit()); err != nil {
+			return fmt.Errorf("part %d: %w", part.Number, err)
+		}
+
+		result.Parts = append(result.Parts, part)
+		result.FormattedLength += part.FormattedLength
+		result.RenderedLength += part.RenderedLength
+		descriptions = append(descriptions, part.Description)
+	}
+
+	result.ShowURL = result.Parts[0].ShowURL
+	result.Description = strings.Join(descriptions, "\n\n")
+	return nil
+}
+
+// partURL appends a part's slug suffix to the show's URL, e.g.
+// https://www.mixcloud.com/user/show/ + "-part-2" → https://www.mixcloud.com/user/show-part-2/
+func partURL(showURL, suffix string) string {
+	return strings.TrimSuffix(showURL, "/") + suffix + "/"
+}
+
+// verifyParts looks every part up on Mixcloud, recording a part that doesn't
+// resolve in its Error and carrying on with the rest. Dry-run lookups are paced
+// like verifyDryRun's.
+func (sp *ShowProcessor) verifyParts(result *ProcessingResult, showCfg *config.ShowConfig) {
+	for i := range result.Parts {
+		part := &result.Parts[i]
+		if result.DryRun {
+			if waited := sp.pacer.Wait(); waited > 0 {
+				sp.logger.Debug("Rate-pacing dry-run verification",
+					slog.String("url", part.ShowURL),
+					slog.Duration("waited", waited))
+			}
+		}
+
+		existing, err := sp.verifyShowWithRetry(sp.runContext(), part.ShowURL)
+		if err != nil {
+			sp.logger.Error("Part verification failed",
+				slog.String("show_key", result.ShowKey),
+				slog.Int("part", part.Number),
+				slog.String("url", part.ShowURL),
+				slog.String("error", err.Error()))
+			part.Error = fmt.Errorf("verifying part %d exists: %w", part.Number, err)
+			continue
+		}
+
+		part.Verified = true
+		if existing != nil {
+			part.LiveShowName = existing.Name
+			part.PreviousDescription = existing.Description
+		}
+		if showCfg.PreserveName {
+			if part.LiveShowName == "" {
+				sp.logger.Warn("preserve_name is set but Mixcloud returned no show name, sending update without it",
+					slog.String("show_key", result.ShowKey),
+					slog.String("url", part.ShowURL))
+			}
+			part.Name = part.LiveShowName
+		}
+		sp.emitStep(result.ShowKey, StepVerify, part.ShowURL, nil)
+	}
+
+	if !sp.options.NoCache {
+		sp.saveState(result.ShowKey) // Keep the response cache for the next run
+	}
+}
+
+// publishParts updates every part that resolved, then fails the show with a
+// PartsError if any part failed
+func (sp *ShowProcessor) publishParts(result *ProcessingResult) {
+	for i := range result.Parts {
+		part := &result.Parts[i]
+		if part.Error != nil {
+			continue // Didn't resolve
+		}
+
+		sp.logger.Info("Updating part description",
+			slog.String("show_key", result.ShowKey),
+			slog.Int("part", part.Number),
+			slog.String("url", part.ShowURL))
+
+		fields := result.partFields(*part)
+		if _, err := sp.editShowWithRetry(sp.runContext(), part.ShowURL, fields); err != nil {
+			sp.logger.Error("Part description update failed",
+				slog.String("show_key", result.ShowKey),
+				slog.Int("part", part.Number),
+				slog.String("url", part.ShowURL),
+				slog.String("error", err.Error()))
+			part.Error = fmt.Errorf("updating part %d description: %w", part.Number, err)
+			continue
+		}
+
+		sp.logger.Info("Part description updated successfully",
+			slog.String("show_key", result.ShowKey),
+			slog.Int("part", part.Number),
+			slog.String("url", part.ShowURL))
+		sp.emitStep(result.ShowKey, StepUpdate, part.ShowURL, map[string]int{"fields": len(fields)})
+		part.Success = true
+	}
+
+	if err := result.partsError(); err != nil {
+		result.Error = err
+		result.FailureCategory = CategorizeFailure(err)
+		return
+	}
+	result.Success = true
+}
+
+// partFields returns the complete edit request for one part
+func (r *ProcessingResult) partFields(part PartResult) map[string]string {
+	fields := r.formFields(part.Description)
+	if part.Name != "" {
+		fields["name"] = part.Name
+	}
+	return fields
+}
+
+// failedParts counts the parts with an error
+func (r *ProcessingResult) failedParts() int {
+	failed := 0
+	for _, part := range r.Parts {
+		if part.Error != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// partsError returns a PartsError for the failed parts, nil when none failed
+func (r *ProcessingResult) partsError() error {
+	for _, part := range r.Parts {
+		if part.Error != nil {
+			return &PartsError{Failed: r.failedParts(), Total: len(r.Parts), Err: part.Error}
+		}
+	}
+	return nil
+}
+
+// partLines describes each part on one line, e.g.
+// "✅ Part 1 of 2: https://www.mixcloud.com/user/show-part-1/ (14 tracks, 812 chars)"
+func partLines(result ProcessingResult) []string {
+	lines := make([]string, 0, len(result.Parts))
+	for _, part := range result.Parts {
+		line := fmt.Sprintf("Part %d of %d: %s (%d tracks, %d chars)",
+			part.Number, len(result.Parts), part.ShowURL, part.Tracks, part.FormattedLength)
+		switch {
+		case part.Error != nil:
+			line = "❌ " + line + " - " + part.Error.Error()
+		case part.Success:
+			line = "✅ " + line
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// The test CUE file's tracks start at 00:25, 04:48 and 08:15
+const partsTestConfig = `
+[shows.split]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Split Show"
+split_at = "00:05:00"
+enabled = true
+`
+
+func TestSplitShowUpdatesEachPart(t *testing.T) {
+	sp := newTestProcessor(t, partsTestConfig)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	showCfg := sp.config.Shows["split"]
+	result := sp.processingleShow("split", &showCfg, "", "6/28/2025", false)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+
+	part1 := "https://www.mixcloud.com/testuser/split-show-part-1/"
+	part2 := "https://www.mixcloud.com/testuser/split-show-part-2/"
+	if len(fake.updates) != 2 || !strings.HasPrefix(fake.updates[0], part1+"=") || !strings.HasPrefix(fake.updates[1], part2+"=") {
+		t.Fatalf("updates = %q, want one each to %s and %s", fake.updates, part1, part2)
+	}
+
+	tests := []struct {
+		part        int
+		wantTracks  int
+		contains    string
+		notContains string
+	}{
+		{1, 2, `04:48 - "Dive Deep Into the Night"`, "Conditional Love"},
+		{2, 1, `03:15 - "Conditional Love"`, "When I Fall"}, // Rebased to the start of part 2
+	}
+	for _, tt := range tests {
+		part := result.Parts[tt.part-1]
+		if !part.Success || part.Tracks != tt.wantTracks {
+			t.Errorf("part %d = success %v, %d tracks, want success with %d tracks", tt.part, part.Success, part.Tracks, tt.wantTracks)
+		}
+		description := fake.sent[tt.part-1]["description"]
+		if !strings.Contains(description, tt.contains) || strings.Contains(description, tt.notContains) {
+			t.Errorf("part %d description = %q, want %q without %q", tt.part, description, tt.contains, tt.notContains)
+		}
+	}
+
+	if showState, _ := sp.state.Show("split"); showState.ShowURL != part1 {
+		t.Errorf("published URL = %q, want part 1's %q", showState.ShowURL, part1)
+	}
+}
+
+func TestSplitShowPartFailure(t *testing.T) {
+	part1 := "https://www.mixcloud.com/testuser/split-show-part-1/"
+	part2 := "https://www.mixcloud.com/testuser/split-show-part-2/"
+
+	tests := []struct {
+		name         string
+		setup        func(fake *fakeMixcloud)
+		wantUpdated  string
+		wantErr      error
+		wantCategory FailureCategory
+	}{
+		{
+			name:         "part not found",
+			setup:        func(fake *fakeMixcloud) { fake.missing[part1] = true },
+			wantUpdated:  part2,
+			wantErr:      mixcloud.ErrShowNotFound,
+			wantCategory: FailureNotFound,
+		},
+		{
+			name:         "part update rejected",
+			setup:        func(fake *fakeMixcloud) { fake.updateErrs[part2] = mixcloud.ErrDescriptionTooLong },
+			wantUpdated:  part1,
+			wantErr:      mixcloud.ErrDescriptionTooLong,
+			wantCategory: FailureSource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, partsTestConfig)
+			fake := newFakeMixcloud()
+			tt.setup(fake)
+			sp.mixcloud = fake
+
+			showCfg := sp.config.Shows["split"]
+			result := sp.processingleShow("split", &showCfg, "", "6/28/2025", false)
+
+			var partsErr *PartsError
+			if !errors.As(result.Error, &partsErr) || partsErr.Failed != 1 || partsErr.Total != 2 {
+				t.Fatalf("error = %v, want a PartsError for 1 of 2 parts", result.Error)
+			}
+			if !errors.Is(result.Error, tt.wantErr) {
+				t.Errorf("error = %v, want it to wrap %v", result.Error, tt.wantErr)
+			}
+			if result.FailureCategory != tt.wantCategory {
+				t.Errorf("FailureCategory = %q, want %q", result.FailureCategory, tt.wantCategory)
+			}
+			if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], tt.wantUpdated+"=") {
+				t.Errorf("updates = %q, want only %s", fake.updates, tt.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestSplitShowDryRun(t *testing.T) {
+	sp := newTestProcessor(t, strings.Replace(partsTestConfig, `"00:05:00"`, `["00:05:00", "00:06:00"]`, 1))
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	sp.SetOptions(Options{Verify: true})
+
+	showCfg := sp.config.Shows["split"]
+	result := sp.processingleShow("split", &showCfg, "", "6/28/2025", true)
+	if result.Error != nil {
+		t.Fatalf("dry run error = %v", result.Error)
+	}
+	if len(fake.updates) != 0 {
+		t.Errorf("dry run updated %v", fake.updates)
+	}
+	if len(fake.gets) != 3 {
+		t.Errorf("verified %v, want all 3 parts", fake.gets)
+	}
+
+	// No track starts between 05:00 and 06:00
+	if part := result.Parts[1]; !part.Placeholder || !strings.Contains(part.Description, "Full tracklist unavailable") {
+		t.Errorf("part 2 = %+v, want the placeholder", part)
+	}
+	summary := dryRunSummary(result)
+	for _, want := range []string{"3 parts (", "3 tracks", "verified: all part URLs resolve"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("dryRunSummary() = %q, want it to contain %q", summary, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AIDEV-NOTE: Calendar placeholders are derived from the same show date as
+// {date} (the -date override, otherwise today in the local timezone). Any
+// future day offsets must use AddDate, not 24h multiples, so DST changes can't
+// move a show onto the wrong day.
+
+// namedPlaceholderRegex matches identifier-style placeholders like {week} or {year}
+var namedPlaceholderRegex = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// calendarPlaceholders render a value from the show date
+var calendarPlaceholders = map[string]func(time.Time) string{
+	"week": func(t time.Time) string { // ISO 8601 week number, 1-53
+		_, week := t.ISOWeek()
+		return strconv.Itoa(week)
+	},
+	"week_year": func(t time.Time) string { // Year the ISO week belongs to
+		year, _ := t.ISOWeek()
+		return strconv.Itoa(year)
+	},
+	"month_name": func(t time.Time) string { return t.Month().String() },
+	"day_name":   func(t time.Time) string { return t.Weekday().String() },
+	"year":       func(t time.Time) string { return strconv.Itoa(t.Year()) },
+}
+
+// SupportedPlaceholders lists the placeholders show name, URL and update field
+// patterns accept, for error messages and docs
+var SupportedPlaceholders = []string{
+	"{date}", "{date:FORMAT}", "{station}",
+	"{week}", "{week_year}", "{month_name}", "{day_name}", "{year}",
+}
+
+// UnknownPlaceholderError reports a placeholder the pattern expander doesn't support
+type UnknownPlaceholderError struct {
+	Placeholder string // e.g. "{weeks}"
+	Pattern     string
+}
+
+func (e *UnknownPlaceholderError) Error() string {
+	return fmt.Sprintf("unknown placeholder %s in %q (supported: %s)",
+		e.Placeholder, e.Pattern, strings.Join(SupportedPlaceholders, ", "))
+}
+
+// expandCalendarPlaceholders replaces calendar placeholders in text with values
+// from the show date. showDate is only called when one is present. {date} and
+// {station} are left for the caller; any other {name} is an error, unless
+// keepUnknown is set (-force) and it is left for the render check to warn about.
+func expandCalendarPlaceholders(text, pattern string, showDate func() (time.Time, error), keepUnknown bool) (string, error) {
+	var expandErr error
+	result := namedPlaceholderRegex.ReplaceAllStringFunc(text, func(match string) string {
+		if expandErr != nil {
+			return match
+		}
+		name := namedPlaceholderRegex.FindStringSubmatch(match)[1]
+		if name == "date" || name == "station" {
+			return match
+		}
+
+		render, ok := calendarPlaceholders[name]
+		if !ok {
+			if keepUnknown {
+				return match
+			}
+			expandErr = &UnknownPlaceholderError{Placeholder: match, Pattern: pattern}
+			return match
+		}
+		date, err := showDate()
+		if err != nil {
+			expandErr = fmt.Errorf("resolving date for %s: %w", match, err)
+			return match
+		}
+		return render(date)
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return result, nil
+}
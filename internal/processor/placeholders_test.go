@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+func TestCalendarPlaceholders(t *testing.T) {
+	const pattern = "{week}|{week_year}|{month_name}|{day_name}|{year}"
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		expected string
+	}{
+		{"mid-year week", time.Date(2025, 7, 3, 12, 0, 0, 0, time.UTC), "27|2025|July|Thursday|2025"},
+		{"Dec 29 in week 1 of next year", time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), "1|2026|December|Monday|2025"},
+		{"Dec 30 in week 1 of next year", time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), "1|2025|December|Monday|2024"},
+		{"Dec 31 in week 1 of next year", time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), "1|2020|December|Tuesday|2019"},
+		{"Dec 31 in week 53", time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), "53|2020|December|Thursday|2020"},
+		{"Jan 1 in week 53 of previous year", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "53|2020|January|Friday|2021"},
+		{"Jan 1 in week 52 of previous year", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), "52|2022|January|Sunday|2023"},
+		{"Dec 28 always in last week", time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC), "53|2026|December|Monday|2026"},
+		{"Jan 4 always in week 1", time.Date(2027, 1, 4, 0, 0, 0, 0, time.UTC), "1|2027|January|Monday|2027"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := expandCalendarPlaceholders(pattern, pattern, func() (time.Time, error) { return tt.date, nil }, false)
+			if err != nil {
+				t.Fatalf("expandCalendarPlaceholders() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expandCalendarPlaceholders() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandShowPatternCalendarPlaceholders(t *testing.T) {
+	sp := &ShowProcessor{config: &config.Config{}}
+	sp.config.Station.Name = "Test Station"
+
+	tests := []struct {
+		name         string
+		pattern      string
+		dateFormat   string
+		dateOverride string
+		expected     string
+		force        bool
+		unknown      string // Placeholder expected in an UnknownPlaceholderError
+		expectError  bool
+	}{
+		{
+			name:         "week with date_format",
+			pattern:      "Week {week} Mix ({week_year})",
+			dateFormat:   "M/D/YYYY",
+			dateOverride: "12/29/2025",
+			expected:     "Week 1 Mix (2026)",
+		},
+		{
+			name:         "override without date_format is parsed for calendar placeholders",
+			pattern:      "{station} {day_name} {month_name} {year} - {date}",
+			dateOverride: "6/28/2025",
+			expected:     "Test Station Saturday June 2025 - 6/28/2025",
+		},
+		{
+			name:     "literal text without placeholders",
+			pattern:  "Week Mix",
+			expected: "Week Mix",
+		},
+		{
+			name:         "unknown placeholder",
+			pattern:      "Week {weeks} Mix",
+			dateOverride: "6/28/2025",
+			unknown:      "{weeks}",
+			expectError:  true,
+		},
+		{
+			name:         "unknown placeholder kept with -force",
+			pattern:      "Week {weeks} Mix",
+			dateOverride: "6/28/2025",
+			force:        true,
+			expected:     "Week {weeks} Mix",
+		},
+		{
+			name:         "unparseable override",
+			pattern:      "Week {week}",
+			dateOverride: "someday",
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp.SetOptions(Options{Force: tt.force})
+			showCfg := &config.ShowConfig{ShowNamePattern: tt.pattern, DateFormat: tt.dateFormat}
+			result, err := sp.expandShowPattern(tt.pattern, showCfg, tt.dateOverride)
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("expandShowPattern() = %q, want error", result)
+				}
+				var unknownErr *UnknownPlaceholderError
+				if tt.unknown != "" && (!errors.As(err, &unknownErr) || unknownErr.Placeholder != tt.unknown) {
+					t.Errorf("expandShowPattern() error = %v, want unknown placeholder %s", err, tt.unknown)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandShowPattern() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expandShowPattern() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// AIDEV-NOTE: Dry-run output is trimmed so a 90-track show (or a batch of 30)
+// doesn't scroll the summary off screen. -verbose-preview restores full output and
+// -output always receives the untrimmed descriptions.
+
+// Lines of a dry-run description shown before and after the omitted middle
+const (
+	previewHeadLines = 15
+	previewTailLines = 5
+)
+
+const previewDivider = "─────────────────────────────────────────"
+
+// compactMarkerRegex matches the "+N more" compact mode appends when it drops entries
+var compactMarkerRegex = regexp.MustCompile(`\+\d+ more\s*$`)
+
+// isDescriptionTruncated reports whether the formatter dropped tracks to fit the
+// limit: the description ends with a truncation marker of msgs, the show's
+// locale strings, or compact mode's
+func isDescriptionTruncated(description string, msgs messages.Catalog) bool {
+	return msgs.EndsTruncated(description) || compactMarkerRegex.MatchString(description)
+}
+
+// previewText returns the description as shown in dry-run mode: the first and
+// last lines with a marker in place of the omitted middle
+func previewText(description string, verbose bool) string {
+	lines := strings.Split(description, "\n")
+	if verbose || len(lines) <= previewHeadLines+previewTailLines {
+		return description
+	}
+
+	omitted := len(lines) - previewHeadLines - previewTailLines
+	preview := make([]string, 0, previewHeadLines+previewTailLines+1)
+	preview = append(preview, lines[:previewHeadLines]...)
+	preview = append(preview, fmt.Sprintf("(... %d lines omitted, use -verbose-preview to see all)", omitted))
+	preview = append(preview, lines[len(lines)-previewTailLines:]...)
+	return strings.Join(preview, "\n")
+}
+
+// verifyDryRun looks a dry-run show up on Mixcloud (-dry-run -verify), recording
+// that its URL resolves and the live name and description, and returns the live
+// show. Lookups are paced like updates since a verified batch is a burst of GETs.
+func (sp *ShowProcessor) verifyDryRun(result *ProcessingResult) (*mixcloud.Show, error) {
+	if waited := sp.pacer.Wait(); waited > 0 {
+		sp.logger.Debug("Rate-pacing dry-run verification",
+			slog.String("url", result.ShowURL),
+			slog.Duration("waited", waited))
+	}
+
+	existing, err := sp.verifyShowWithRetry(sp.runContext(), result.ShowURL)
+	if err != nil {
+		return nil, err
+	}
+	result.Verified = true
+	if existing != nil {
+		result.LiveShowName = existing.Name
+		result.PreviousDescription = existing.Description
+	}
+	if !sp.options.NoCache {
+		sp.saveState(result.ShowKey)
+	}
+	sp.emitStep(result.ShowKey, StepVerify, result.ShowURL, nil)
+	return existing, nil
+}
+
+// verifiedSummary describes a verified dry-run show's live state, e.g.
+// `URL resolves, live name "Show - June 28, 2025", current description 812 chars`
+func verifiedSummary(result ProcessingResult) string {
+	return fmt.Sprintf("URL resolves, live name %q, current description %d chars",
+		result.LiveShowName, len(result.PreviousDescription))
+}
+
+// printDryRunPreview prints the would-be update for a show to w: console.Content
+// when the show was targeted with -show, since showing it is the point of the
+// command, console.Chrome in batch runs
+func (sp *ShowProcessor) printDryRunPreview(w io.Writer, result ProcessingResult) {
+	fmt.Fprintf(w, "DRY RUN - Would update %s:\n", result.ShowName)
+	if result.URLSource != "" && result.URLSource != result.ShowName {
+		fmt.Fprintf(w, "URL source: %s\n", result.URLSource)
+	}
+	if result.Account != "" {
+		fmt.Fprintf(w, "Account: %s (mixcloud_username; updates use the station's access token)\n", result.Account)
+	}
+	if result.Sidecar != "" {
+		fmt.Fprintf(w, "Sidecar: %s\n", sidecarSummary(result))
+	}
+	if len(result.Parts) > 0 {
+		sp.printPartsPreview(w, result)
+		return
+	}
+	fmt.Fprintf(w, "URL: %s\n", result.ShowURL)
+	if result.Verified {
+		fmt.Fprintf(w, "Verified: %s\n", verifiedSummary(result))
+	}
+	if check := result.LengthCheck; check != nil && check.Verdict != "" {
+		fmt.Fprintf(w, "Upload length: %s\n", check)
+	}
+	if check := result.Anomaly; check != nil && check.Verdict == AnomalyShrunk {
+		fmt.Fprintf(w, "⚠️  Smaller than recent publishes: %s\n", check)
+	}
+	if result.NewName != "" {
+		fmt.Fprintf(w, "Rename: %s (the URL changes; live runs need -confirm)\n", renameSummary(result))
+	}
+	if result.Sanitized.Changed() {
+		fmt.Fprintf(w, "Sanitized: %s\n", result.Sanitized)
+	}
+	fmt.Fprintf(w, "Fields:\n")
+	for _, line := range dryRunFieldLines(result, result.formFields(result.Description)) {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprintf(w, "%s\n", previewDivider)
+	fmt.Fprintf(w, "%s\n", previewText(result.Description, sp.options.VerbosePreview))
+	fmt.Fprintf(w, "%s\n", previewDivider)
+}
+
+// printPartsPreview prints the would-be update of each part of a split show to w
+func (sp *ShowProcessor) printPartsPreview(w io.Writer, result ProcessingResult) {
+	if result.Sanitized.Changed() {
+		fmt.Fprintf(w, "Sanitized: %s\n", result.Sanitized)
+	}
+	for _, part := range result.Parts {
+		fmt.Fprintf(w, "\nPart %d of %d: %s\n", part.Number, len(result.Parts), part.ShowURL)
+		if part.Verified {
+			fmt.Fprintf(w, "Verified: URL resolves, live name %q, current description %d chars\n",
+				part.LiveShowName, len(part.PreviousDescription))
+		}
+		fmt.Fprintf(w, "Fields:\n")
+		for _, line := range dryRunFieldLines(result, result.partFields(part)) {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+		fmt.Fprintf(w, "%s\n", previewDivider)
+		fmt.Fprintf(w, "%s\n", previewText(part.Description, sp.options.VerbosePreview))
+		fmt.Fprintf(w, "%s\n", previewDivider)
+	}
+}
+
+// dryRunSummary returns the one-line summary printed per show in batch dry runs
+func dryRunSummary(result ProcessingResult) string {
+	truncated := "no"
+	if isDescriptionTruncated(result.Description, result.Messages) {
+		truncated = "yes"
+	}
+	summary := fmt.Sprintf("%d chars (~%d rendered), %d tracks, truncated: %s, template: %s",
+		result.FormattedLength, result.RenderedLength, result.FilteredTracks, truncated, result.Template)
+	if len(result.Parts) > 0 {
+		summary = fmt.Sprintf("%d parts (%s), %d tracks, truncated: %s, template: %s",
+			len(result.Parts), partsLengthSummary(result), result.FilteredTracks, truncated, result.Template)
+	}
+	if result.Placeholder {
+		summary += " (placeholder)"
+	}
+	if result.TrackOrder == config.TrackOrderReverse {
+		summary += ", newest first"
+	}
+	if result.Sanitized.Changed() {
+		summary += ", sanitized: " + result.Sanitized.String()
+	}
+	if result.TrackGaps > 0 {
+		summary += fmt.Sprintf(", track gaps: %d (largest %s)", result.TrackGaps, result.LargestTrackGap)
+	}
+	if result.Sidecar != "" {
+		summary += ", sidecar: " + sidecarSummary(result)
+	}
+	if result.Account != "" {
+		summary += ", account: " + result.Account
+	}
+	if check := result.LengthCheck; check != nil && check.Verdict != "" {
+		summary += ", upload length: " + check.String()
+	}
+	if check := result.Anomaly; check != nil && check.Verdict == AnomalyShrunk {
+		summary += ", smaller than recent publishes: " + check.String()
+	}
+	if result.Verified {
+		summary += ", verified: " + verifiedSummary(result)
+	}
+	if len(result.Parts) > 0 && result.Parts[0].Verified {
+		summary += ", verified: all part URLs resolve"
+	}
+	if result.NewName != "" {
+		summary += ", rename: " + renameSummary(result)
+	}
+	return summary
+}
+
+// partsLengthSummary lists each part's length, e.g. "812 + 640 chars"
+func partsLengthSummary(result ProcessingResult) string {
+	lengths := make([]string, 0, len(result.Parts))
+	for _, part := range result.Parts {
+		lengths = append(lengths, fmt.Sprintf("%d", part.FormattedLength))
+	}
+	return strings.Join(lengths, " + ") + " chars"
+}
+
+// writePreviewOutput appends the full description to the -output file and the
+// bare description to DescriptionOutput, if set
+func (sp *ShowProcessor) writePreviewOutput(result ProcessingResult) {
+	if sp.options.PreviewOutput != nil {
+		if err := writeDescriptions(sp.options.PreviewOutput, result); err != nil {
+			sp.logger.Warn("Failed to write dry-run output",
+				slog.String("show_key", result.ShowKey),
+				slog.String("error", err.Error()))
+		}
+	}
+	if sp.options.DescriptionOutput != nil {
+		if err := writeBareDescriptions(sp.options.DescriptionOutput, result); err != nil {
+			sp.logger.Warn("Failed to write dry-run description",
+				slog.String("show_key", result.ShowKey),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// writeBareDescriptions writes a show's description exactly as it would be
+// published, followed by a newline. Split shows get each part's description,
+// separated by a blank line.
+func writeBareDescriptions(w io.Writer, result ProcessingResult) error {
+	descriptions := []string{result.Description}
+	if len(result.Parts) > 0 {
+		descriptions = descriptions[:0]
+		for _, part := range result.Parts {
+			descriptions = append(descriptions, part.Description)
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s\n", strings.Join(descriptions, "\n\n"))
+	return err
+}
+
+// writeDescriptions writes a show's full description under a header naming the
+// show and its URL, as -output and the offline archive record it. Split shows
+// get a section per part.
+func writeDescriptions(w io.Writer, result ProcessingResult) error {
+	if len(result.Parts) == 0 {
+		_, err := fmt.Fprintf(w, "=== %s: %s ===\nURL: %s\n\n%s\n\n",
+			result.ShowKey, result.ShowName, result.ShowURL, result.Description)
+		return err
+	}
+	for _, part := range result.Parts {
+		if _, err := fmt.Fprintf(w, "=== %s: %s (part %d of %d) ===\nURL: %s\n\n%s\n\n",
+			result.ShowKey, result.ShowName, part.Number, len(result.Parts), part.ShowURL, part.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+func numberedLines(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestPreviewText(t *testing.T) {
+	tests := []struct {
+		name        string
+		lines       int
+		verbose     bool
+		wantOmitted string
+	}{
+		{"short description unchanged", 20, false, ""},
+		{"long description trimmed", 82, false, "(... 62 lines omitted, use -verbose-preview to see all)"},
+		{"verbose shows everything", 82, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description := numberedLines(tt.lines)
+			got := previewText(description, tt.verbose)
+
+			if tt.wantOmitted == "" {
+				if got != description {
+					t.Errorf("previewText() modified description:\n%s", got)
+				}
+				return
+			}
+
+			lines := strings.Split(got, "\n")
+			if len(lines) != previewHeadLines+previewTailLines+1 {
+				t.Fatalf("previewText() returned %d lines, want %d", len(lines), previewHeadLines+previewTailLines+1)
+			}
+			if lines[previewHeadLines] != tt.wantOmitted {
+				t.Errorf("marker = %q, want %q", lines[previewHeadLines], tt.wantOmitted)
+			}
+			if lines[0] != "line 1" || lines[previewHeadLines-1] != "line 15" {
+				t.Errorf("head lines wrong: %q ... %q", lines[0], lines[previewHeadLines-1])
+			}
+			if lines[len(lines)-1] != fmt.Sprintf("line %d", tt.lines) {
+				t.Errorf("last line = %q, want line %d", lines[len(lines)-1], tt.lines)
+			}
+		})
+	}
+}
+
+func TestIsDescriptionTruncated(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		expected    bool
+	}{
+		{"complete classic", "00:00 - \"Song\" by Artist", false},
+		{"classic truncated", "00:00 - \"Song\" by Artist\n... and more", true},
+		{"template truncated", "1. Song\n... and 12 more tracks\n", true},
+		{"compact truncated", "Artist – Song · +4 more", true},
+		{"title mentioning more", "00:00 - \"Give Me More\" by Artist", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDescriptionTruncated(tt.description, messages.Catalog{}); got != tt.expected {
+				t.Errorf("isDescriptionTruncated() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDryRunWritesFullDescriptionToOutput(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.jazz]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Jazz Hour"
+enabled = true
+`)
+	var output bytes.Buffer
+	sp.SetOptions(Options{PreviewOutput: &output})
+
+	showCfg := sp.config.Shows["jazz"]
+	result := sp.processingleShow("jazz", &showCfg, "", "", true)
+	if !result.Success {
+		t.Fatalf("processingleShow() failed: %v", result.Error)
+	}
+
+	if !strings.Contains(output.String(), "=== jazz: Jazz Hour ===") {
+		t.Errorf("output missing show header:\n%s", output.String())
+	}
+	if !strings.Contains(output.String(), result.Description) {
+		t.Errorf("output missing full description:\n%s", output.String())
+	}
+
+	summary := dryRunSummary(result)
+	want := fmt.Sprintf("%d chars (~%d rendered), 3 tracks, truncated: no, template: classic",
+		result.FormattedLength, result.RenderedLength)
+	if summary != want {
+		t.Errorf("dryRunSummary() = %q, want %q", summary, want)
+	}
+}
+
+// captureStdout runs fn with os.Stdout redirected and returns what it printed
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	captured := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-captured
+}
+
+func TestDryRunDescriptionOutputIsBare(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.jazz]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Jazz Hour"
+enabled = true
+`)
+	var descriptions, preview bytes.Buffer
+	sp.SetOptions(Options{DescriptionOutput: &descriptions, PreviewOutput: &preview})
+
+	var err error
+	chrome := captureStdout(t, func() {
+		err = sp.ProcessShow("jazz", "", "", true)
+	})
+	if err != nil {
+		t.Fatalf("ProcessShow() error = %v", err)
+	}
+
+	description := strings.TrimSuffix(descriptions.String(), "\n")
+	if !strings.HasPrefix(description, `00:25 - "When I Fall" by Laura Dre`) || strings.Contains(description, "===") {
+		t.Errorf("description output = %q, want the description alone", descriptions.String())
+	}
+	if !strings.Contains(preview.String(), description) {
+		t.Errorf("description output doesn't match the -output description:\n%s", preview.String())
+	}
+	if !strings.Contains(chrome, "DRY RUN - Would update Jazz Hour") {
+		t.Errorf("human output = %q, want the dry-run preview", chrome)
+	}
+	if strings.Contains(descriptions.String(), "DRY RUN") {
+		t.Error("human output leaked into the description output")
+	}
+}
+
+func TestWriteBareDescriptionsParts(t *testing.T) {
+	result := ProcessingResult{Parts: []PartResult{{Number: 1, Description: "part one"}, {Number: 2, Description: "part two"}}}
+	var out bytes.Buffer
+	if err := writeBareDescriptions(&out, result); err != nil {
+		t.Fatal(err)
+	}
+	if want := "part one\n\npart two\n"; out.String() != want {
+		t.Errorf("writeBareDescriptions() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestDryRunVerify(t *testing.T) {
+	showURL := mixcloud.GenerateShowURL("testuser", "Jazz Hour")
+
+	tests := []struct {
+		name        string
+		verify      bool
+		missing     bool
+		wantGets    int
+		wantSuccess bool
+		wantSummary string
+	}{
+		{"without -verify", false, false, 0, true, ""},
+		{"URL resolves", true, false, 1, true,
+			fmt.Sprintf(`, verified: URL resolves, live name "Name %s", current description %d chars`, showURL, len("old "+showURL))},
+		{"URL not found", true, true, 1, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, `
+[shows.jazz]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Jazz Hour"
+enabled = true
+`)
+			fake := newFakeMixcloud()
+			fake.missing[showURL] = tt.missing
+			sp.mixcloud = fake
+			sp.SetOptions(Options{Verify: tt.verify})
+
+			showCfg := sp.config.Shows["jazz"]
+			result := sp.processingleShow("jazz", &showCfg, "", "", true)
+
+			if len(fake.gets) != tt.wantGets {
+				t.Errorf("GetShow calls = %d, want %d", len(fake.gets), tt.wantGets)
+			}
+			if len(fake.updates) != 0 {
+				t.Errorf("dry run sent updates: %v", fake.updates)
+			}
+			if result.Success != tt.wantSuccess {
+				t.Fatalf("Success = %v, want %v (error: %v)", result.Success, tt.wantSuccess, result.Error)
+			}
+			if !tt.wantSuccess {
+				if result.FailureCategory != FailureNotFound {
+					t.Errorf("FailureCategory = %q, want %q", result.FailureCategory, FailureNotFound)
+				}
+				return
+			}
+
+			if result.Verified != tt.verify {
+				t.Errorf("Verified = %v, want %v", result.Verified, tt.verify)
+			}
+			if tt.verify && result.LiveShowName != "Name "+showURL {
+				t.Errorf("LiveShowName = %q, want %q", result.LiveShowName, "Name "+showURL)
+			}
+			if !tt.verify && result.LiveShowName != "" {
+				t.Errorf("LiveShowName = %q without -verify", result.LiveShowName)
+			}
+			summary := dryRunSummary(result)
+			if tt.wantSummary != "" && !strings.HasSuffix(summary, tt.wantSummary) {
+				t.Errorf("dryRunSummary() = %q, want suffix %q", summary, tt.wantSummary)
+			}
+			if tt.wantSummary == "" && strings.Contains(summary, "verified") {
+				t.Errorf("dryRunSummary() = %q, want no verification", summary)
+			}
+		})
+	}
+}
+
+func TestDryRunGuestAccount(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.guest]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Guest Mix"
+mixcloud_username = "dj-guest"
+enabled = true
+`)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	sp.SetOptions(Options{Verify: true})
+
+	showCfg := sp.config.Shows["guest"]
+	var result ProcessingResult
+	output := captureStdout(t, func() {
+		result = sp.processingleShow("guest", &showCfg, "", "", true)
+		sp.printDryRunPreview(os.Stdout, result)
+	})
+	if !result.Success {
+		t.Fatalf("processingleShow() failed: %v", result.Error)
+	}
+
+	want := "https://www.mixcloud.com/dj-guest/guest-mix/"
+	if result.ShowURL != want || len(fake.gets) != 1 || fake.gets[0] != want {
+		t.Errorf("ShowURL = %q, looked up %v; want %s", result.ShowURL, fake.gets, want)
+	}
+	if !strings.Contains(output, "Account: dj-guest") {
+		t.Errorf("preview doesn't name the account:\n%s", output)
+	}
+	if summary := dryRunSummary(result); !strings.Contains(summary, ", account: dj-guest") {
+		t.Errorf("dryRunSummary() = %q, want the account", summary)
+	}
+}
+
+func TestConsoleVerbosity(t *testing.T) {
+	const showsConfig = `
+[shows.jazz]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Jazz Hour"
+enabled = true
+priority = 1
+
+[shows.broken]
+cue_file_mapping = "MISSING.cue"
+show_name_pattern = "Broken"
+enabled = true
+priority = 2
+`
+	single := func(sp *ShowProcessor) { sp.ProcessShow("jazz", "", "", true) }
+	batch := func(sp *ShowProcessor) { sp.ProcessAllShows(true) }
+
+	tests := []struct {
+		name      string
+		verbosity console.Verbosity
+		run       func(sp *ShowProcessor)
+		want      []string
+		wantNot   []string
+	}{
+		{"normal single show", console.Normal, single,
+			[]string{"Processing show: jazz", "DRY RUN - Would update Jazz Hour", "✅ Success: jazz"}, nil},
+		{"silent single show keeps the description", console.Silent, single,
+			[]string{"DRY RUN - Would update Jazz Hour", `00:25 - "When I Fall" by Laura Dre`}, []string{"Processing show", "Dry run complete"}},
+		{"normal batch", console.Normal, batch,
+			[]string{"Processing 2 enabled shows", "DRY RUN - Would update Jazz Hour", "❌ Failed: broken", "Batch Processing Summary"}, nil},
+		{"quiet batch shows failures only", console.Quiet, batch,
+			[]string{"❌ Failed: broken"}, []string{"Processing 2 enabled shows", "DRY RUN", "✅", "Batch Processing Summary"}},
+		{"silent batch prints nothing", console.Silent, batch,
+			nil, []string{"Failed", "DRY RUN", "Summary"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, showsConfig)
+			sp.SetOptions(Options{VerbosePreview: true})
+			console.SetVerbosity(tt.verbosity)
+			t.Cleanup(func() { console.SetVerbosity(console.Normal) })
+
+			out := captureStdout(t, func() { tt.run(sp) })
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output doesn't contain %q:\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.wantNot {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("output contains %q:\n%s", unwanted, out)
+				}
+			}
+			if len(tt.want) == 0 && out != "" {
+				t.Errorf("output = %q, want nothing", out)
+			}
+		})
+	}
+}
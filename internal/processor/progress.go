@@ -0,0 +1,219 @@
+package processor
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AIDEV-NOTE: Progress events are for GUI wrappers and automation that need to
+// follow a run live. Library consumers register a ProgressObserver; the CLI's
+// -progress-json mode plugs in a JSONProgressWriter. Events never replace the
+// console output or logs, they run alongside them.
+
+// Progress event types
+const (
+	EventRunStarted   = "run_started"
+	EventShowStarted  = "show_started"
+	EventShowStep     = "show_step"
+	EventShowFinished = "show_finished"
+	EventRunFinished  = "run_finished"
+)
+
+// Steps reported by show_step events, in processing order
+const (
+	StepResolve = "resolve" // CUE file found
+	StepParse   = "parse"   // CUE file parsed
+	StepFilter  = "filter"  // Tracks filtered
+	StepFormat  = "format"  // Description rendered
+	StepVerify  = "verify"  // Show found on Mixcloud
+	StepUpdate  = "update"  // Description published
+	StepQueue   = "queue"   // Offline run: description archived and queued for -retry-failed
+)
+
+// Run modes reported by run_started
+const (
+	RunModeSingle = "single"
+	RunModeBatch  = "batch"
+	RunModeGroup  = "group"
+	RunModeRetry  = "retry" // -retry-failed: publishing the pending-updates queue
+)
+
+// Show statuses reported by show_finished
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+	StatusQueued  = "queued" // Offline run: queued for -retry-failed
+)
+
+// ProgressEvent is a single progress notification. Only the fields relevant
+// to the event type are set.
+type ProgressEvent struct {
+	Event      string          `json:"event"`
+	Time       time.Time       `json:"time"`
+	Mode       string          `json:"mode,omitempty"`        // run_started
+	Target     string          `json:"target,omitempty"`      // run_started: show alias or group name
+	DryRun     bool            `json:"dry_run,omitempty"`     // run_started
+	TotalShows int             `json:"total_shows,omitempty"` // run_started
+	ShowKey    string          `json:"show_key,omitempty"`
+	Step       string          `json:"step,omitempty"`   // show_step
+	Detail     string          `json:"detail,omitempty"` // show_step: e.g. the CUE file or show URL; run_finished: see NoShowsProcessed
+	Counts     map[string]int  `json:"counts,omitempty"` // show_step: e.g. {"tracks": 12, "excluded": 2}
+	Status     string          `json:"status,omitempty"` // show_finished
+	Category   FailureCategory `json:"failure_category,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DurationMS int64           `json:"duration_ms,omitempty"` // show_finished, run_finished
+	Anomaly    string          `json:"anomaly,omitempty"`     // show_finished: AnomalyCheck verdict, with the check in AnomalyDetail
+	Totals     *ProgressTotals `json:"totals,omitempty"`      // run_finished
+
+	// show_finished: the comparison with the show's recent publishes
+	AnomalyDetail string `json:"anomaly_detail,omitempty"`
+
+	// run_finished: the run had nothing to process, e.g. every show disabled;
+	// Detail says why
+	NoShowsProcessed bool `json:"no_shows_processed,omitempty"`
+
+	// run_finished: shows of a batch run that share a CUE file or description
+	Duplicates []DuplicateSource `json:"duplicates,omitempty"`
+}
+
+// ProgressTotals summarizes a run in run_finished
+type ProgressTotals struct {
+	Total        int `json:"total"`
+	Processed    int `json:"processed"`
+	Successful   int `json:"successful"`
+	Failed       int `json:"failed"`
+	Skipped      int `json:"skipped"`
+	Placeholders int `json:"placeholders"`
+	Queued       int `json:"queued"`
+	NotAttempted int `json:"not_attempted"`
+}
+
+// ProgressObserver receives progress events as a run happens. Calls are made
+// synchronously from the processing goroutine, so observers should be quick.
+type ProgressObserver interface {
+	OnProgress(event ProgressEvent)
+}
+
+// ProgressFunc adapts a function to ProgressObserver
+type ProgressFunc func(event ProgressEvent)
+
+// OnProgress calls f(event)
+func (f ProgressFunc) OnProgress(event ProgressEvent) {
+	f(event)
+}
+
+// SetProgressObserver registers the observer for progress events; nil disables them
+func (sp *ShowProcessor) SetProgressObserver(observer ProgressObserver) {
+	sp.progress = observer
+}
+
+// emit timestamps event and hands it to the observer, if any
+func (sp *ShowProcessor) emit(event ProgressEvent) {
+	if sp.progress == nil {
+		return
+	}
+	event.Time = time.Now()
+	sp.progress.OnProgress(event)
+}
+
+func (sp *ShowProcessor) emitRunStarted(mode, target string, totalShows int, dryRun bool) {
+	sp.emit(ProgressEvent{Event: EventRunStarted, Mode: mode, Target: target, TotalShows: totalShows, DryRun: dryRun})
+}
+
+func (sp *ShowProcessor) emitStep(showKey, step, detail string, counts map[string]int) {
+	sp.emit(ProgressEvent{Event: EventShowStep, ShowKey: showKey, Step: step, Detail: detail, Counts: counts})
+}
+
+func (sp *ShowProcessor) emitShowFinished(result ProcessingResult) {
+	event := ProgressEvent{
+		Event:      EventShowFinished,
+		ShowKey:    result.ShowKey,
+		Status:     resultStatus(result),
+		DurationMS: result.Duration.Milliseconds(),
+	}
+	if result.Error != nil {
+		event.Category = result.FailureCategory
+		event.Error = result.Error.Error()
+	}
+	if result.Anomaly != nil {
+		event.Anomaly = result.Anomaly.Verdict
+		event.AnomalyDetail = result.Anomaly.String()
+	}
+	sp.emit(event)
+}
+
+func (sp *ShowProcessor) emitRunFinished(batchResult *BatchResult) {
+	sp.lastRun = batchResult // Every run ends here, see LastRun
+	event := ProgressEvent{
+		Event:      EventRunFinished,
+		DurationMS: batchResult.TotalDuration.Milliseconds(),
+		Totals: &ProgressTotals{
+			Total:        batchResult.TotalShows,
+			Processed:    batchResult.ProcessedShows,
+			Successful:   batchResult.SuccessfulShows,
+			Failed:       batchResult.FailedShows,
+			Skipped:      batchResult.SkippedShows,
+			Placeholders: batchResult.PlaceholderShows,
+			Queued:       batchResult.QueuedShows,
+			NotAttempted: batchResult.NotAttemptedShows,
+		},
+	}
+	if batchResult.GroupFailure != nil {
+		event.Error = batchResult.GroupFailure.Error()
+	} else if batchResult.FailureAbort != nil {
+		event.Error = batchResult.FailureAbort.String()
+	}
+	event.Duplicates = batchResult.Duplicates
+	if batchResult.EmptyRunReason != "" {
+		event.NoShowsProcessed = true
+		event.Detail = batchResult.EmptyRunReason
+	}
+	sp.emit(event)
+}
+
+// resultStatus maps a finished show to its show_finished status
+func resultStatus(result ProcessingResult) string {
+	switch {
+	case result.Error != nil:
+		return StatusFailed
+	case result.Success:
+		return StatusSuccess
+	case result.Queued:
+		return StatusQueued
+	default:
+		return StatusSkipped
+	}
+}
+
+// JSONProgressWriter writes each event as one line of JSON (NDJSON)
+type JSONProgressWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewJSONProgressWriter returns an observer writing newline-delimited JSON to w
+func NewJSONProgressWriter(w io.Writer) *JSONProgressWriter {
+	return &JSONProgressWriter{encoder: json.NewEncoder(w)}
+}
+
+// OnProgress writes the event. After the first write error (e.g. the reader of a
+// pipe went away) further events are dropped; see Err.
+func (w *JSONProgressWriter) OnProgress(event ProgressEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return
+	}
+	w.err = w.encoder.Encode(event)
+}
+
+// Err returns the first error writing events, if any
+func (w *JSONProgressWriter) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
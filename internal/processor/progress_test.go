@@ -0,0 +1,209 @@
+package processor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordProgress registers an observer collecting every event sp emits
+func recordProgress(sp *ShowProcessor) *[]ProgressEvent {
+	var events []ProgressEvent
+	sp.SetProgressObserver(ProgressFunc(func(event ProgressEvent) {
+		events = append(events, event)
+	}))
+	return &events
+}
+
+// eventSequence renders events as "event" or "event:step" for comparison
+func eventSequence(events []ProgressEvent) []string {
+	sequence := make([]string, 0, len(events))
+	for _, event := range events {
+		if event.Step != "" {
+			sequence = append(sequence, event.Event+":"+event.Step)
+		} else {
+			sequence = append(sequence, event.Event)
+		}
+	}
+	return sequence
+}
+
+func TestProgressEventsSingleShow(t *testing.T) {
+	sp, _ := newGroupTestProcessor(t)
+	events := recordProgress(sp)
+
+	if err := sp.ProcessShow("fest-fri", "", "", false); err != nil {
+		t.Fatalf("ProcessShow() error = %v", err)
+	}
+
+	want := []string{
+		EventRunStarted,
+		EventShowStarted,
+		EventShowStep + ":" + StepResolve,
+		EventShowStep + ":" + StepParse,
+		EventShowStep + ":" + StepFilter,
+		EventShowStep + ":" + StepFormat,
+		EventShowStep + ":" + StepVerify,
+		EventShowStep + ":" + StepUpdate,
+		EventShowFinished,
+		EventRunFinished,
+	}
+	if got := eventSequence(*events); !reflect.DeepEqual(got, want) {
+		t.Fatalf("event sequence = %v, want %v", got, want)
+	}
+
+	started := (*events)[0]
+	if started.Mode != RunModeSingle || started.Target != "fest-fri" || started.TotalShows != 1 {
+		t.Errorf("run_started = %+v", started)
+	}
+	for _, event := range (*events)[1:9] {
+		if event.ShowKey != "fest-fri" {
+			t.Errorf("%s show_key = %q, want fest-fri", event.Event, event.ShowKey)
+		}
+		if event.Time.IsZero() {
+			t.Errorf("%s has no timestamp", event.Event)
+		}
+	}
+	if parse := (*events)[3]; parse.Counts["tracks"] != 3 {
+		t.Errorf("parse counts = %v, want 3 tracks", parse.Counts)
+	}
+	if finished := (*events)[8]; finished.Status != StatusSuccess || finished.Error != "" {
+		t.Errorf("show_finished = %+v", finished)
+	}
+	totals := (*events)[9].Totals
+	if totals == nil || totals.Total != 1 || totals.Successful != 1 || totals.Failed != 0 {
+		t.Errorf("run_finished totals = %+v", totals)
+	}
+}
+
+func TestProgressEventsBatchFailure(t *testing.T) {
+	sp, fake := newGroupTestProcessor(t)
+	fake.missing[festURL("Saturday")] = true
+	events := recordProgress(sp)
+
+	if err := sp.ProcessAllShows(false); err == nil {
+		t.Fatal("ProcessAllShows() should fail when a show is missing")
+	}
+
+	var finished []ProgressEvent
+	for _, event := range *events {
+		if event.Event == EventShowFinished {
+			finished = append(finished, event)
+		}
+	}
+	if len(finished) != 3 {
+		t.Fatalf("got %d show_finished events, want 3", len(finished))
+	}
+	statuses := map[string]string{}
+	for _, event := range finished {
+		statuses[event.ShowKey] = event.Status
+	}
+	wantStatuses := map[string]string{"fest-fri": StatusSuccess, "fest-sat": StatusFailed, "fest-sun": StatusSuccess}
+	if !reflect.DeepEqual(statuses, wantStatuses) {
+		t.Errorf("statuses = %v, want %v", statuses, wantStatuses)
+	}
+	if finished[1].Category != FailureNotFound || finished[1].Error == "" {
+		t.Errorf("failed show_finished = %+v, want not_found category and error", finished[1])
+	}
+
+	first, last := (*events)[0], (*events)[len(*events)-1]
+	if first.Event != EventRunStarted || first.Mode != RunModeBatch || first.TotalShows != 3 {
+		t.Errorf("first event = %+v, want batch run_started", first)
+	}
+	if last.Event != EventRunFinished || last.Totals == nil || last.Totals.Successful != 2 || last.Totals.Failed != 1 {
+		t.Errorf("last event = %+v, want run_finished with 2 successful and 1 failed", last)
+	}
+
+	run := sp.LastRun()
+	if run == nil || run.SuccessfulShows != 2 {
+		t.Fatalf("LastRun() = %+v, want the batch with 2 successful shows", run)
+	}
+	if failed := run.FailedShowKeys(); !reflect.DeepEqual(failed, []string{"fest-sat"}) {
+		t.Errorf("FailedShowKeys() = %v, want [fest-sat]", failed)
+	}
+}
+
+func TestProgressEventsEmptyRun(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.paused]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Paused"
+enabled = false
+`)
+
+	tests := []struct {
+		name       string
+		run        func() error
+		wantDetail string
+	}{
+		{"disabled show", func() error { return sp.ProcessShow("paused", "", "", false) }, "Show 'paused' is disabled in configuration"},
+		{"no enabled shows", func() error { return sp.ProcessAllShows(false) }, "No enabled shows found in configuration."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := recordProgress(sp)
+			if err := tt.run(); !IsEmptyRun(err) {
+				t.Fatalf("error = %v, want an empty run", err)
+			}
+
+			want := []string{EventRunStarted, EventRunFinished}
+			if got := eventSequence(*events); !reflect.DeepEqual(got, want) {
+				t.Fatalf("event sequence = %v, want %v", got, want)
+			}
+			finished := (*events)[1]
+			if !finished.NoShowsProcessed || finished.Detail != tt.wantDetail {
+				t.Errorf("run_finished = %+v, want no_shows_processed with %q", finished, tt.wantDetail)
+			}
+		})
+	}
+
+	t.Run("runs that process shows don't set it", func(t *testing.T) {
+		sp, _ := newGroupTestProcessor(t)
+		events := recordProgress(sp)
+		if err := sp.ProcessAllShows(false); err != nil {
+			t.Fatalf("ProcessAllShows() error = %v", err)
+		}
+		if last := (*events)[len(*events)-1]; last.NoShowsProcessed {
+			t.Errorf("run_finished = %+v, want no_shows_processed unset", last)
+		}
+	})
+}
+
+func TestJSONProgressWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewJSONProgressWriter(&buf)
+	writer.OnProgress(ProgressEvent{Event: EventShowStep, ShowKey: "a", Step: StepParse, Counts: map[string]int{"tracks": 4}})
+	writer.OnProgress(ProgressEvent{Event: EventRunFinished, Totals: &ProgressTotals{Total: 1, Successful: 1}})
+	if err := writer.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0]["event"] != EventShowStep || lines[0]["step"] != StepParse {
+		t.Errorf("first line = %v", lines[0])
+	}
+	if _, ok := lines[0]["totals"]; ok {
+		t.Errorf("unset fields should be omitted: %v", lines[0])
+	}
+	if totals, ok := lines[1]["totals"].(map[string]interface{}); !ok || totals["successful"] != float64(1) {
+		t.Errorf("second line totals = %v", lines[1]["totals"])
+	}
+	if strings.Contains(buf.String(), "\n\n") {
+		t.Error("events should be separated by single newlines")
+	}
+}
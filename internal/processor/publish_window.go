@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+)
+
+// outsidePublishWindow reports whether the show has a publish window that is
+// closed right now, with the skip reason naming when it opens next
+func (sp *ShowProcessor) outsidePublishWindow(showKey string, showCfg *config.ShowConfig) (string, bool) {
+	window, err := shows.ParsePublishWindow(showCfg)
+	if err != nil || window == nil {
+		return "", false // Invalid windows are rejected by ValidateShows
+	}
+
+	now := sp.now()
+	open, next := window.Contains(now, sp.location)
+	if open {
+		return "", false
+	}
+
+	reason := fmt.Sprintf("outside publish window (next window opens %s)", next.Format("Mon 15:04"))
+	sp.logger.Info("Show outside its publish window",
+		slog.String("show_key", showKey),
+		slog.String("window", window.String()),
+		slog.Time("now", now.In(sp.location)),
+		slog.Time("next_open", next))
+	return reason, true
+}
@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"testing"
+	"time"
+)
+
+const publishWindowTestConfig = `
+[shows.friday-night]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Friday Night"
+publish_after = "FRI 20:00"
+publish_before = "SAT 02:00"
+priority = 1
+enabled = true
+
+[shows.anytime]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Anytime"
+priority = 2
+enabled = true
+`
+
+func newPublishWindowTestProcessor(t *testing.T, now time.Time) (*ShowProcessor, *fakeMixcloud) {
+	t.Helper()
+	sp := newTestProcessor(t, publishWindowTestConfig)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	sp.now = func() time.Time { return now }
+	sp.location = time.UTC
+	return sp, fake
+}
+
+func TestProcessAllShowsPublishWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantSkip   bool
+		wantReason string
+	}{
+		// 27 June 2025 is a Friday
+		{"during the show", time.Date(2025, 6, 27, 19, 0, 0, 0, time.UTC), true, "outside publish window (next window opens Fri 20:00)"},
+		{"after the show", time.Date(2025, 6, 27, 21, 0, 0, 0, time.UTC), false, ""},
+		{"after midnight", time.Date(2025, 6, 28, 1, 0, 0, 0, time.UTC), false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, fake := newPublishWindowTestProcessor(t, tt.now)
+
+			// A skipped show is not a failure
+			if err := sp.ProcessAllShows(false); err != nil {
+				t.Fatalf("ProcessAllShows() error = %v", err)
+			}
+
+			wantUpdates := 2
+			if tt.wantSkip {
+				wantUpdates = 1
+			}
+			if len(fake.updates) != wantUpdates {
+				t.Errorf("updates = %d, want %d", len(fake.updates), wantUpdates)
+			}
+			if _, published := sp.state.Show("friday-night"); published == tt.wantSkip {
+				t.Errorf("friday-night published = %v, want %v", published, !tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestOutsidePublishWindowReason(t *testing.T) {
+	sp, _ := newPublishWindowTestProcessor(t, time.Date(2025, 6, 28, 3, 0, 0, 0, time.UTC))
+
+	showCfg := sp.config.Shows["friday-night"]
+	reason, outside := sp.outsidePublishWindow("friday-night", &showCfg)
+	if !outside {
+		t.Fatal("outsidePublishWindow() = false on Saturday 03:00")
+	}
+	if want := "outside publish window (next window opens Fri 20:00)"; reason != want {
+		t.Errorf("reason = %q, want %q", reason, want)
+	}
+
+	anytime := sp.config.Shows["anytime"]
+	if _, outside := sp.outsidePublishWindow("anytime", &anytime); outside {
+		t.Error("show without a window reported outside it")
+	}
+}
+
+func TestProcessShowOutsideWindowNeedsForce(t *testing.T) {
+	sp, fake := newPublishWindowTestProcessor(t, time.Date(2025, 6, 27, 19, 0, 0, 0, time.UTC))
+
+	if err := sp.ProcessShow("friday-night", "", "", false); err != nil {
+		t.Fatalf("ProcessShow() error = %v", err)
+	}
+	if len(fake.updates) != 0 {
+		t.Fatalf("updates = %v, want none outside the window", fake.updates)
+	}
+
+	sp.SetOptions(Options{Force: true})
+	if err := sp.ProcessShow("friday-night", "", "", false); err != nil {
+		t.Fatalf("ProcessShow() with Force error = %v", err)
+	}
+	if len(fake.updates) != 1 {
+		t.Errorf("updates = %v, want 1 with -force", fake.updates)
+	}
+}
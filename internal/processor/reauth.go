@@ -0,0 +1,88 @@
+package processor
+
+import (
+	"log/slog"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+)
+
+// AIDEV-NOTE: auto_reauth = "prompt" only works with someone at the keyboard -
+// the OAuth flow waits for a browser callback. The CLI only sets a Reauthorizer
+// when a terminal is attached, so cron runs keep failing fast with FailureAuth.
+
+// Reauthorizer runs the interactive OAuth flow and returns the new token
+type Reauthorizer func() (*oauth2.Token, error)
+
+// tokenSaver is implemented by clients that can switch to a new OAuth token
+type tokenSaver interface {
+	SaveToken(token *oauth2.Token) error
+}
+
+// SetReauthorizer enables re-authentication on the run's first auth failure:
+// the run pauses, reauth is called and the failed show is retried once
+func (sp *ShowProcessor) SetReauthorizer(reauth Reauthorizer) {
+	sp.reauth = reauth
+}
+
+// ReauthPause returns how long the run was paused re-authenticating
+func (sp *ShowProcessor) ReauthPause() time.Duration {
+	return sp.reauthPause
+}
+
+// LastRun returns the outcome of the latest run, e.g. for processing.status_file,
+// or nil when no run has finished
+func (sp *ShowProcessor) LastRun() *BatchResult {
+	return sp.lastRun
+}
+
+// retryAfterReauth re-authenticates and calls retry when result is the run's
+// first auth failure and a Reauthorizer is set. Otherwise result is returned as
+// is, after checking whether the token was revoked (see checkRejectedToken).
+func (sp *ShowProcessor) retryAfterReauth(result ProcessingResult, retry func() ProcessingResult) ProcessingResult {
+	if sp.reauth == nil {
+		sp.checkRejectedToken(result)
+		return result
+	}
+	if sp.reauthTried || result.FailureCategory != FailureAuth {
+		return result
+	}
+	sp.reauthTried = true
+
+	sp.logger.Warn("Authentication failed mid-run, pausing to re-authenticate",
+		slog.String("show_key", result.ShowKey),
+		slog.String("error", result.Error.Error()))
+	console.Printf("\n🔑 %s failed authentication - pausing the run to re-authorize with Mixcloud\n", result.ShowKey)
+
+	start := time.Now()
+	token, err := sp.reauth()
+	pause := time.Since(start)
+	sp.reauthPause += pause
+	if err != nil {
+		sp.logger.Error("Re-authentication failed",
+			slog.Duration("paused", pause),
+			slog.String("error", err.Error()))
+		console.Printf("❌ Re-authentication failed: %v\n\n", err)
+		result.ReauthPause = pause
+		return result
+	}
+
+	if saver, ok := sp.mixcloud.(tokenSaver); ok {
+		if err := saver.SaveToken(token); err != nil {
+			// The client still uses the new token; only persisting it failed
+			sp.logger.Warn("Failed to save re-authorized token",
+				slog.String("error", err.Error()))
+		}
+	}
+
+	sp.logger.Info("Re-authenticated, retrying show",
+		slog.String("show_key", result.ShowKey),
+		slog.Duration("paused", pause))
+	console.Printf("✓ Re-authorized after %.1fs, retrying %s\n\n", pause.Seconds(), result.ShowKey)
+
+	retried := retry()
+	retried.ReauthPause = pause
+	return retried
+}
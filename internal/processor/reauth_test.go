@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+func TestProcessAllShowsAutoReauth(t *testing.T) {
+	authErr := fmt.Errorf("%w: API authentication failed", mixcloud.ErrAuthenticationFailed)
+
+	tests := []struct {
+		name          string
+		reauth        bool
+		failing       []string // Days whose updates fail until re-authentication
+		reauthFails   bool
+		wantCalls     int
+		wantFailed    int
+		wantTokenSent bool
+	}{
+		{"no reauthorizer fails fast", false, []string{"Friday"}, false, 0, 1, false},
+		{"retries the show after re-authenticating", true, []string{"Friday"}, false, 1, 0, true},
+		{"failed re-authentication keeps the failure", true, []string{"Friday"}, true, 1, 1, false},
+		{"only the first auth failure re-authenticates", true, []string{"Friday", "Sunday"}, true, 1, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, fake := newGroupTestProcessor(t)
+			for _, day := range tt.failing {
+				fake.updateErrs[festURL(day)] = authErr
+			}
+
+			calls := 0
+			if tt.reauth {
+				sp.SetReauthorizer(func() (*oauth2.Token, error) {
+					calls++
+					time.Sleep(10 * time.Millisecond)
+					if tt.reauthFails {
+						return nil, errors.New("authorization timed out")
+					}
+					fake.updateErrs = make(map[string]error)
+					return &oauth2.Token{AccessToken: "fresh"}, nil
+				})
+			}
+
+			err := sp.ProcessAllShows(false)
+			var batchErr *BatchError
+			if tt.wantFailed == 0 && err != nil {
+				t.Fatalf("ProcessAllShows() error = %v", err)
+			}
+			if tt.wantFailed > 0 {
+				if !errors.As(err, &batchErr) || batchErr.Failed != tt.wantFailed {
+					t.Fatalf("ProcessAllShows() error = %v, want %d failed", err, tt.wantFailed)
+				}
+				if !IsAuthFailure(err) {
+					t.Errorf("IsAuthFailure(%v) = false, want true", err)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("reauthorizer called %d times, want %d", calls, tt.wantCalls)
+			}
+			if got := len(fake.tokens) > 0; got != tt.wantTokenSent {
+				t.Errorf("token saved = %v, want %v", got, tt.wantTokenSent)
+			}
+			if tt.wantCalls > 0 && sp.ReauthPause() < 10*time.Millisecond {
+				t.Errorf("ReauthPause() = %v, want the time spent re-authenticating", sp.ReauthPause())
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// AIDEV-NOTE: Mixcloud derives a show's URL slug from its name, so update_name
+// moves the show. The new URL is taken from the edit response (or predicted from
+// the new name and looked up) and recorded in the state file under the URL the
+// config generates, so later runs update the renamed show instead of hitting a 404.
+
+// ErrRenameNotConfirmed is returned for live runs of update_name shows without -confirm
+var ErrRenameNotConfirmed = errors.New("update_name renames the show and changes its URL; rerun with -confirm")
+
+// showEditor is implemented by clients that report the show URL after an edit,
+// which changes when the edit renames the show
+type showEditor interface {
+	EditShowContext(ctx context.Context, showURL string, fields map[string]string) (string, error)
+}
+
+// prepareRename expands a show's name_template and adds the new name to the
+// update fields. Live runs must be confirmed since the show's URL changes.
+func (sp *ShowProcessor) prepareRename(result *ProcessingResult, showCfg *config.ShowConfig, dateOverride string) error {
+	newName, err := sp.expandShowPattern(showCfg.NameTemplate, showCfg, dateOverride)
+	if err != nil {
+		return fmt.Errorf("expanding name_template: %w", err)
+	}
+	if err := sp.enforceRenderCheck(result.ShowKey, "name template", newName); err != nil {
+		return err
+	}
+	if !result.DryRun && !sp.options.Confirm {
+		return ErrRenameNotConfirmed
+	}
+	result.NewName = newName
+	result.UpdateFields["name"] = newName
+	return nil
+}
+
+// followRename points result at the show's current URL when an earlier run
+// renamed it
+func (sp *ShowProcessor) followRename(result *ProcessingResult) {
+	result.generatedURL = result.ShowURL
+	if sp.state == nil {
+		return
+	}
+	if renamed, ok := sp.state.RenamedURL(result.ShowURL); ok {
+		sp.logger.Debug("Show was renamed by an earlier run",
+			slog.String("show_key", result.ShowKey),
+			slog.String("generated_url", result.ShowURL),
+			slog.String("url", renamed))
+		result.ShowURL = renamed
+	}
+}
+
+// checkRename records the show's current name and drops the rename when the
+// show already has the new name
+func (sp *ShowProcessor) checkRename(result *ProcessingResult, currentName string) {
+	result.PreviousName = currentName
+	if currentName != result.NewName {
+		return
+	}
+	sp.logger.Debug("Show already has the name_template name, not renaming",
+		slog.String("show_key", result.ShowKey),
+		slog.String("name", currentName))
+	delete(result.UpdateFields, "name")
+	result.NewName = ""
+}
+
+// completeRename finds where a renamed show now lives and records it. editedURL
+// is the URL from the edit response; without one the URL is predicted from the
+// new name and looked up. A rename that can't be located is logged and the old
+// URL kept - the update itself succeeded.
+func (sp *ShowProcessor) completeRename(result *ProcessingResult, editedURL string) {
+	newURL := editedURL
+	if newURL == "" {
+		showCfg := sp.config.Shows[result.ShowKey]
+		predicted := mixcloud.GenerateShowURL(sp.config.MixcloudUsernameFor(&showCfg), result.NewName)
+		if _, err := sp.verifyShowWithRetry(sp.runContext(), predicted); err != nil {
+			sp.logger.Warn("Show was renamed but its new URL could not be confirmed, the next run may not find it",
+				slog.String("show_key", result.ShowKey),
+				slog.String("name", result.NewName),
+				slog.String("predicted_url", predicted),
+				slog.String("error", err.Error()))
+			return
+		}
+		newURL = predicted
+	}
+	if newURL == result.ShowURL {
+		return
+	}
+
+	sp.logger.Info("Show renamed",
+		slog.String("show_key", result.ShowKey),
+		slog.String("previous_name", result.PreviousName),
+		slog.String("name", result.NewName),
+		slog.String("previous_url", result.ShowURL),
+		slog.String("url", newURL))
+	if sp.state != nil {
+		sp.state.RecordRename(result.generatedURL, newURL)
+	}
+	result.ShowURL = newURL
+}
+
+// renameSummary describes a rename, e.g. `"Show - 6/28/2025" → "Show: Summer Special"`.
+// Without a verified current name it says where the old name comes from.
+func renameSummary(result ProcessingResult) string {
+	previous := fmt.Sprintf("%q", result.PreviousName)
+	if result.DryRun && !result.Verified {
+		previous = "(current Mixcloud title; add -verify to fetch it)"
+	}
+	return fmt.Sprintf("%s → %q", previous, result.NewName)
+}
@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+const renameTestConfig = `
+[shows.rename]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Rename Show"
+update_name = true
+name_template = "Rename Show {date:YYYY}"
+enabled = true
+`
+
+// editingMixcloud reports editedURL from every edit, like the client does when
+// the edit response includes the show key
+type editingMixcloud struct {
+	*fakeMixcloud
+	editedURL string
+}
+
+func (f *editingMixcloud) EditShowContext(ctx context.Context, showURL string, fields map[string]string) (string, error) {
+	if err := f.UpdateShowContext(ctx, showURL, fields); err != nil {
+		return "", err
+	}
+	return f.editedURL, nil
+}
+
+func TestRenameRequiresConfirm(t *testing.T) {
+	sp := newTestProcessor(t, renameTestConfig)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	showCfg := sp.config.Shows["rename"]
+	result := sp.processingleShow("rename", &showCfg, "", "6/28/2025", false)
+	if !errors.Is(result.Error, ErrRenameNotConfirmed) {
+		t.Fatalf("error = %v, want ErrRenameNotConfirmed", result.Error)
+	}
+	if len(fake.gets) != 0 || len(fake.updates) != 0 {
+		t.Errorf("unconfirmed rename reached Mixcloud: gets %v, updates %v", fake.gets, fake.updates)
+	}
+}
+
+func TestRenameRecordsNewURL(t *testing.T) {
+	oldURL := mixcloud.GenerateShowURL("testuser", "Rename Show")
+	newURL := mixcloud.GenerateShowURL("testuser", "Rename Show 2025")
+
+	tests := []struct {
+		name      string
+		editedURL string // "" uses a client that doesn't report the URL
+	}{
+		{"URL from edit response", newURL},
+		{"URL predicted from new name", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, renameTestConfig)
+			sp.SetOptions(Options{Confirm: true})
+			fake := newFakeMixcloud()
+			sp.mixcloud = fake
+			if tt.editedURL != "" {
+				sp.mixcloud = &editingMixcloud{fakeMixcloud: fake, editedURL: tt.editedURL}
+			}
+
+			showCfg := sp.config.Shows["rename"]
+			result := sp.processingleShow("rename", &showCfg, "", "6/28/2025", false)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+			if got := fake.sent[0]["name"]; got != "Rename Show 2025" {
+				t.Errorf("sent name = %q, want %q", got, "Rename Show 2025")
+			}
+			if result.PreviousName != "Name "+oldURL {
+				t.Errorf("PreviousName = %q, want %q", result.PreviousName, "Name "+oldURL)
+			}
+			if result.ShowURL != newURL {
+				t.Errorf("ShowURL = %q, want %q", result.ShowURL, newURL)
+			}
+			if got, _ := sp.state.RenamedURL(oldURL); got != newURL {
+				t.Errorf("recorded rename = %q, want %q", got, newURL)
+			}
+			if showState, _ := sp.state.Show("rename"); showState.ShowURL != newURL {
+				t.Errorf("published URL = %q, want %q", showState.ShowURL, newURL)
+			}
+
+			// The next run finds the show where it now lives
+			fake.gets = nil
+			result = sp.processingleShow("rename", &showCfg, "", "6/28/2025", false)
+			if result.Error != nil {
+				t.Fatalf("second run error = %v", result.Error)
+			}
+			if len(fake.gets) == 0 || fake.gets[0] != newURL {
+				t.Errorf("second run looked up %v, want %s first", fake.gets, newURL)
+			}
+		})
+	}
+}
+
+func TestRenameDryRun(t *testing.T) {
+	sp := newTestProcessor(t, renameTestConfig)
+	sp.mixcloud = newFakeMixcloud()
+
+	showCfg := sp.config.Shows["rename"]
+	result := sp.processingleShow("rename", &showCfg, "", "6/28/2025", true)
+	if result.Error != nil {
+		t.Fatalf("dry run without -confirm error = %v", result.Error)
+	}
+	want := `rename: (current Mixcloud title; add -verify to fetch it) → "Rename Show 2025"`
+	if summary := dryRunSummary(result); !strings.Contains(summary, want) {
+		t.Errorf("dryRunSummary() = %q, want it to contain %q", summary, want)
+	}
+
+	sp.SetOptions(Options{Verify: true})
+	result = sp.processingleShow("rename", &showCfg, "", "6/28/2025", true)
+	if result.Error != nil {
+		t.Fatalf("verified dry run error = %v", result.Error)
+	}
+	oldURL := mixcloud.GenerateShowURL("testuser", "Rename Show")
+	want = `rename: "Name ` + oldURL + `" → "Rename Show 2025"`
+	if summary := dryRunSummary(result); !strings.Contains(summary, want) {
+		t.Errorf("dryRunSummary() = %q, want it to contain %q", summary, want)
+	}
+}
@@ -0,0 +1,141 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AIDEV-NOTE: Post-render sanity check - catches typo'd placeholders ({datee}) and
+// template leftovers ({{.ShowTitl}}, "<no value>") before they get published.
+// A description carries track titles, where braces are plain text ("Remix
+// {Edit}"), so there only placeholders the expander knows are reported.
+var (
+	// templateActionRegex matches unrendered Go template actions like {{.ShowTitle}}
+	templateActionRegex = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+	// placeholderRegex matches single-brace placeholders like {date} or {station}
+	placeholderRegex = regexp.MustCompile(`\{[^{}\n]{1,40}\}`)
+)
+
+// descriptionSource is the source name of a rendered tracklist description
+const descriptionSource = "description"
+
+// noValueMarker is what text/template emits for missing map keys and nil fields
+const noValueMarker = "<no value>"
+
+// renderArtifactContext is the number of characters shown either side of an artifact
+const renderArtifactContext = 20
+
+// RenderArtifactError reports leftover template or placeholder syntax in rendered output
+type RenderArtifactError struct {
+	Source string   // What was checked ("show name" or "description")
+	Issues []string // Human-readable description of each artifact found
+}
+
+func (e *RenderArtifactError) Error() string {
+	return fmt.Sprintf("rendered %s contains template artifacts: %s", e.Source, strings.Join(e.Issues, "; "))
+}
+
+// checkRenderArtifacts scans rendered text for unreplaced placeholders, unrendered
+// template actions and text/template's "<no value>" marker.
+// Returns nil when the text is clean.
+func checkRenderArtifacts(source, text string) error {
+	var issues []string
+
+	// Template actions first so their braces aren't reported again as placeholders
+	actionSpans := templateActionRegex.FindAllStringIndex(text, -1)
+	for _, span := range actionSpans {
+		issues = append(issues, fmt.Sprintf("unrendered template action %q in %q",
+			text[span[0]:span[1]], artifactSnippet(text, span[0], span[1])))
+	}
+
+	for _, span := range placeholderRegex.FindAllStringIndex(text, -1) {
+		if insideSpans(span, actionSpans) {
+			continue
+		}
+		placeholder := text[span[0]:span[1]]
+		kind := "unknown placeholder"
+		if source == descriptionSource {
+			if !isKnownPlaceholder(placeholder) {
+				continue
+			}
+			kind = "unexpanded placeholder"
+		}
+		issues = append(issues, fmt.Sprintf("%s %q in %q",
+			kind, placeholder, artifactSnippet(text, span[0], span[1])))
+	}
+
+	offset := 0
+	for {
+		idx := strings.Index(text[offset:], noValueMarker)
+		if idx < 0 {
+			break
+		}
+		start := offset + idx
+		end := start + len(noValueMarker)
+		issues = append(issues, fmt.Sprintf("missing template value %q in %q",
+			noValueMarker, artifactSnippet(text, start, end)))
+		offset = end
+	}
+
+	if len(issues) == 0 {
+		return nil
+	}
+	return &RenderArtifactError{Source: source, Issues: issues}
+}
+
+// isKnownPlaceholder reports whether placeholder, e.g. "{date:Jan 2}", is one
+// the pattern expander replaces (see SupportedPlaceholders)
+func isKnownPlaceholder(placeholder string) bool {
+	name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "{"), "}")
+	if name == "date" || name == "station" || strings.HasPrefix(name, "date:") {
+		return true
+	}
+	_, ok := calendarPlaceholders[name]
+	return ok
+}
+
+// artifactSnippet returns the artifact with a little surrounding context for error messages
+func artifactSnippet(text string, start, end int) string {
+	from := start - renderArtifactContext
+	if from < 0 {
+		from = 0
+	}
+	to := end + renderArtifactContext
+	if to > len(text) {
+		to = len(text)
+	}
+
+	// Keep the snippet on valid UTF-8 boundaries
+	for from > 0 && !isRuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !isRuneStart(text[to]) {
+		to++
+	}
+
+	snippet := strings.ReplaceAll(text[from:to], "\n", " ")
+	if from > 0 {
+		snippet = "..." + snippet
+	}
+	if to < len(text) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// isRuneStart reports whether b begins a UTF-8 encoded rune
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// insideSpans reports whether span lies entirely within one of the given spans
+func insideSpans(span []int, spans [][]int) bool {
+	for _, s := range spans {
+		if span[0] >= s[0] && span[1] <= s[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+const testCueContent = `PERFORMER ""
+TITLE ""
+FILE "TEST.cue" WAV
+  TRACK 01 AUDIO
+    TITLE "When I Fall"
+    PERFORMER "Laura Dre"
+    INDEX 01 00:25:40
+  TRACK 02 AUDIO
+    TITLE "Dive Deep Into the Night"
+    PERFORMER "Pure Obsessions"
+    INDEX 01 04:48:31
+  TRACK 03 AUDIO
+    TITLE "Conditional Love"
+    PERFORMER "Airline Food"
+    INDEX 01 08:15:02
+`
+
+// newTestProcessor writes the given TOML (with the CUE directory substituted for
+// {dir}) and a test CUE file to a temp dir and returns a processor built from it
+func newTestProcessor(t *testing.T, tomlBody string) *ShowProcessor {
+	t.Helper()
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "TEST.cue"), []byte(testCueContent), 0644); err != nil {
+		t.Fatalf("writing CUE fixture: %v", err)
+	}
+
+	header := `
+[station]
+name = "Test Station"
+mixcloud_username = "testuser"
+
+[oauth]
+client_id = "test-client-id"
+client_secret = "test-client-secret"
+access_token = "test-access-token"
+
+[processing]
+cue_file_directory = "{dir}"
+
+[logging]
+enabled = false
+`
+	content := strings.ReplaceAll(header+tomlBody, "{dir}", filepath.ToSlash(dir))
+	configPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	sp, err := NewShowProcessor(cfg, configPath)
+	if err != nil {
+		t.Fatalf("NewShowProcessor() error = %v", err)
+	}
+	return sp
+}
+
+func TestCheckRenderArtifacts(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		text       string
+		wantIssues []string
+	}{
+		{
+			name:   "clean text",
+			source: "show name",
+			text:   "The Vault - 6/28/2025",
+		},
+		{
+			name:       "typo'd placeholder",
+			source:     "show name",
+			text:       "The Vault - {datee}",
+			wantIssues: []string{`unknown placeholder "{datee}"`},
+		},
+		{
+			name:       "unrendered template action",
+			source:     descriptionSource,
+			text:       "Tracklist for {{.ShowTitl}}:\n00:25 - Song",
+			wantIssues: []string{`unrendered template action "{{.ShowTitl}}"`},
+		},
+		{
+			name:       "missing template value",
+			source:     descriptionSource,
+			text:       "Notes: <no value>\n00:25 - Song",
+			wantIssues: []string{`missing template value "<no value>"`},
+		},
+		{
+			name:   "multiple artifacts",
+			source: "show name",
+			text:   "{{.X}} and {station_nam} and <no value>",
+			wantIssues: []string{
+				`unrendered template action "{{.X}}"`,
+				`unknown placeholder "{station_nam}"`,
+				`missing template value "<no value>"`,
+			},
+		},
+		{
+			name:   "braces in track titles",
+			source: descriptionSource,
+			text:   "00:25 - \"{Interlude}\" by Laura Dre\n04:48 - \"Remix {Edit}\" by Pure Obsessions",
+		},
+		{
+			name:       "unexpanded placeholder in a description",
+			source:     descriptionSource,
+			text:       "Aired {date:Jan 2} in week {week}\n00:25 - \"{Interlude}\" by Laura Dre",
+			wantIssues: []string{`unexpanded placeholder "{date:Jan 2}"`, `unexpanded placeholder "{week}"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRenderArtifacts(tt.source, tt.text)
+			if len(tt.wantIssues) == 0 {
+				if err != nil {
+					t.Errorf("checkRenderArtifacts() unexpected error = %v", err)
+				}
+				return
+			}
+
+			var artifactErr *RenderArtifactError
+			if !errors.As(err, &artifactErr) {
+				t.Fatalf("checkRenderArtifacts() error = %v, want *RenderArtifactError", err)
+			}
+			if len(artifactErr.Issues) != len(tt.wantIssues) {
+				t.Fatalf("got %d issues %v, want %d", len(artifactErr.Issues), artifactErr.Issues, len(tt.wantIssues))
+			}
+			for i, want := range tt.wantIssues {
+				if !strings.Contains(artifactErr.Issues[i], want) {
+					t.Errorf("issue %d = %q, want it to contain %q", i, artifactErr.Issues[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestArtifactSnippetQuotesContext(t *testing.T) {
+	text := "A long description line before the problem {{.ShowTitl}} and some trailing text after it"
+	err := checkRenderArtifacts("description", text)
+	if err == nil {
+		t.Fatal("checkRenderArtifacts() expected error")
+	}
+	if !strings.Contains(err.Error(), "... before the problem {{.ShowTitl}} and some trailing t...") {
+		t.Errorf("error does not quote surrounding context: %v", err)
+	}
+}
+
+func TestRenderCheckShowNamePath(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault - {datee}"
+enabled = true
+`)
+
+	showCfg := sp.config.Shows["vault"]
+	result := sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error == nil {
+		t.Fatal("processingleShow() expected render check failure for typo'd placeholder")
+	}
+	if !strings.Contains(result.Error.Error(), "{datee}") {
+		t.Errorf("error should quote the offending placeholder, got: %v", result.Error)
+	}
+	if result.Success {
+		t.Error("show should not be marked successful")
+	}
+
+	// With -force the show proceeds
+	sp.SetOptions(Options{Force: true})
+	result = sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error != nil {
+		t.Errorf("processingleShow() with Force unexpected error = %v", result.Error)
+	}
+}
+
+func TestRenderCheckTemplatePath(t *testing.T) {
+	sp := newTestProcessor(t, `
+[templates]
+default = "notes"
+
+[templates.config.notes]
+header = "Notes: {{.Custom.show_notes}}\n"
+track = "{{.StartTime}} {{.Artist}} - {{.Title}}\n"
+
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault"
+enabled = true
+`)
+
+	showCfg := sp.config.Shows["vault"]
+	result := sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error == nil {
+		t.Fatal("processingleShow() expected render check failure for <no value>")
+	}
+	if !strings.Contains(result.Error.Error(), "<no value>") {
+		t.Errorf("error should quote the <no value> marker, got: %v", result.Error)
+	}
+
+	sp.SetOptions(Options{Force: true})
+	result = sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error != nil {
+		t.Errorf("processingleShow() with Force unexpected error = %v", result.Error)
+	}
+}
+
+func TestRenderCheckBracesInTrackTitles(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "BRACES.cue"
+show_name_pattern = "The Vault"
+enabled = true
+`)
+	cue := strings.ReplaceAll(strings.ReplaceAll(testCueContent, "When I Fall", "{Interlude}"), "Conditional Love", "Conditional Love (Remix {Edit})")
+	if err := os.WriteFile(filepath.Join(sp.config.Processing.CueFileDirectory, "BRACES.cue"), []byte(cue), 0644); err != nil {
+		t.Fatalf("writing CUE fixture: %v", err)
+	}
+
+	showCfg := sp.config.Shows["vault"]
+	result := sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() unexpected error = %v", result.Error)
+	}
+	if !strings.Contains(result.Description, "{Interlude}") || !strings.Contains(result.Description, "Remix {Edit}") {
+		t.Errorf("description should keep the braced titles, got:\n%s", result.Description)
+	}
+}
@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+const retryTestConfig = `
+[shows.storm]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Storm Show"
+enabled = true
+`
+
+// rateLimitServer answers 429 to the methods in limited and a minimal show otherwise,
+// counting requests per method
+type rateLimitServer struct {
+	mu       sync.Mutex
+	requests map[string]int
+	limited  map[string]bool
+}
+
+func (s *rateLimitServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.Method]++
+	s.mu.Unlock()
+
+	if s.limited[r.Method] {
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"key": "/testuser/storm-show/", "name": "Storm Show", "description": "old"}`))
+}
+
+// TestRateLimitStormAttempts checks the end-to-end attempt count documented in
+// the README: a call answered with 429 every time is attempted retry_attempts times.
+func TestRateLimitStormAttempts(t *testing.T) {
+	tests := []struct {
+		name          string
+		limited       string // Method answered with 429
+		retryAttempts int    // 0 = default
+		wantAttempts  int
+	}{
+		{"verify with default attempts", http.MethodGet, 0, constants.DefaultRetryAttempts},
+		{"update with default attempts", http.MethodPost, 0, constants.DefaultRetryAttempts},
+		{"verify with retry_attempts = 5", http.MethodGet, 5, 5},
+		{"update with retry_attempts = 1", http.MethodPost, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &rateLimitServer{
+				requests: make(map[string]int),
+				limited:  map[string]bool{tt.limited: true},
+			}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			sp := newTestProcessor(t, retryTestConfig)
+			sp.options.NoCache = true
+			sp.configureShowCache()
+			sp.mixcloud.(*mixcloud.Client).SetBaseURL(httpServer.URL)
+			if tt.retryAttempts > 0 {
+				sp.config.Processing.RetryAttempts = tt.retryAttempts
+				sp.retryPolicy = mixcloud.RetryPolicy(sp.config)
+			}
+			var waits []time.Duration
+			sp.retryPolicy.Sleep = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			showCfg := sp.config.Shows["storm"]
+			result := sp.processingleShow("storm", &showCfg, "", "", false)
+			if !errors.Is(result.Error, mixcloud.ErrRateLimited) {
+				t.Fatalf("processingleShow() error = %v, want ErrRateLimited", result.Error)
+			}
+
+			if got := server.requests[tt.limited]; got != tt.wantAttempts {
+				t.Errorf("%s requests = %d, want %d", tt.limited, got, tt.wantAttempts)
+			}
+			if len(waits) != tt.wantAttempts-1 {
+				t.Fatalf("waits = %v, want %d", waits, tt.wantAttempts-1)
+			}
+			// 1s, 2s, 4s... each within the ±25% jitter
+			base := time.Second
+			for i, wait := range waits {
+				if wait < base*3/4 || wait > base*5/4 {
+					t.Errorf("wait %d = %v, want %v ±25%%", i+1, wait, base)
+				}
+				base *= 2
+			}
+		})
+	}
+}
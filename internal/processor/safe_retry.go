@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// AIDEV-NOTE: With processing.safe_retries (the default) every retry of a show
+// update starts with a GetShow. A write that timed out may have been applied with
+// only the response lost, and re-sending it after a concurrent edit on Mixcloud
+// would overwrite that edit. When the live show already carries what the failed
+// attempt sent, the update counts as done. A 429 is a clean refusal, so retries
+// after one skip the read-back. The read-back uses a single GetShow, never retried:
+// when it fails the write is sent again as without safe_retries.
+
+// updateAlreadyApplied reports whether the update a failed attempt sent, fields,
+// is already live on showURL. lastErr is the failed attempt's error.
+func (sp *ShowProcessor) updateAlreadyApplied(ctx context.Context, showURL string, fields map[string]string, lastErr error) bool {
+	if !sp.config.SafeRetriesEnabled() || errors.Is(lastErr, mixcloud.ErrRateLimited) {
+		return false
+	}
+	if _, ok := fields["description"]; !ok {
+		return false
+	}
+
+	show, err := sp.mixcloud.GetShowContext(ctx, showURL)
+	if err != nil {
+		sp.logger.Debug("Read-back before retrying show update failed",
+			slog.String("url", showURL),
+			slog.String("error", err.Error()))
+		return false
+	}
+	if !showMatchesFields(show, fields) {
+		return false
+	}
+
+	sp.logger.Warn("Show update already applied, not sending it again",
+		slog.String("url", showURL),
+		slog.Bool("timed_out", errors.Is(lastErr, mixcloud.ErrTimeout)),
+		slog.String("error", lastErr.Error()))
+	return true
+}
+
+// showMatchesFields reports whether show's description, and name when fields
+// sets one, equal the update fields. Other fields aren't returned by GetShow;
+// one request sends every field, so the description stands in for them.
+func showMatchesFields(show *mixcloud.Show, fields map[string]string) bool {
+	if show == nil || !sameText(show.Description, fields["description"]) {
+		return false
+	}
+	if name, ok := fields["name"]; ok && !sameText(show.Name, name) {
+		return false
+	}
+	return true
+}
+
+// sameText compares two texts ignoring line endings and surrounding whitespace,
+// which Mixcloud doesn't keep as sent
+func sameText(a, b string) bool {
+	normalize := func(s string) string {
+		return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// lostResponseServer applies every edit but holds the response to the first one
+// past the client's timeout, like a response lost on a flaky uplink
+type lostResponseServer struct {
+	mu          sync.Mutex
+	description string
+	posts       int
+	release     chan struct{}
+}
+
+func (s *lostResponseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.posts++
+		first := s.posts == 1
+		s.description = r.FormValue("description")
+		s.mu.Unlock()
+		if first {
+			select {
+			case <-s.release:
+			case <-time.After(10 * time.Second):
+			}
+		}
+		w.Write([]byte(`{"result": {"success": true}}`))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"key":         "/testuser/storm-show/",
+		"name":        "Storm Show",
+		"description": s.description,
+	})
+}
+
+func TestSafeRetriesAfterLostResponse(t *testing.T) {
+	off := false
+	tests := []struct {
+		name        string
+		safeRetries *bool
+		wantPosts   int
+	}{
+		{"safe_retries default", nil, 1},
+		{"safe_retries = false", &off, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &lostResponseServer{description: "old", release: make(chan struct{})}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+			defer close(server.release)
+
+			sp := newTestProcessor(t, retryTestConfig)
+			sp.options.NoCache = true
+			sp.config.Processing.SafeRetries = tt.safeRetries
+			sp.config.Processing.HTTPTimeoutSeconds = 1
+			client, err := mixcloud.NewClient(sp.config, sp.configPath)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			client.SetBaseURL(httpServer.URL)
+			sp.mixcloud = client
+			sp.retryPolicy.Sleep = func(context.Context, time.Duration) error { return nil }
+
+			showCfg := sp.config.Shows["storm"]
+			result := sp.processingleShow("storm", &showCfg, "", "", false)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if server.posts != tt.wantPosts {
+				t.Errorf("POST requests = %d, want %d", server.posts, tt.wantPosts)
+			}
+			if server.description != result.Description {
+				t.Errorf("live description = %q, want %q", server.description, result.Description)
+			}
+		})
+	}
+}
+
+func TestSafeRetriesSkipRateLimits(t *testing.T) {
+	sp := newTestProcessor(t, retryTestConfig)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	fields := map[string]string{"description": "tracks"}
+	if sp.updateAlreadyApplied(context.Background(), nightOneURL, fields, mixcloud.ErrRateLimited) {
+		t.Error("updateAlreadyApplied() after a 429 = true, want false")
+	}
+	if len(fake.gets) != 0 {
+		t.Errorf("GetShow calls = %v, want none after a 429", fake.gets)
+	}
+}
+
+func TestShowMatchesFields(t *testing.T) {
+	show := &mixcloud.Show{Name: "Storm Show", Description: "Line one\nLine two"}
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   bool
+	}{
+		{"same description", map[string]string{"description": "Line one\nLine two"}, true},
+		{"line endings and trailing space", map[string]string{"description": "Line one\r\nLine two \n"}, true},
+		{"different description", map[string]string{"description": "Line one"}, false},
+		{"name differs", map[string]string{"description": "Line one\nLine two", "name": "Storm Show (Rerun)"}, false},
+		{"name matches", map[string]string{"description": "Line one\nLine two", "name": "Storm Show"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := showMatchesFields(show, tt.fields); got != tt.want {
+				t.Errorf("showMatchesFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+const showFlagsTestConfig = `
+[templates.config.master]
+header = "{{.Show.Key}} with {{.Show.Vars.host}}\n"
+track = "{{.Title}}{{if hasFlag \"genres\"}} [genre]{{end}}\n"
+
+[shows.night-one]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Night One"
+enabled = true
+template = "master"
+flags = ["genres"]
+template_vars = { host = "DJ Example" }
+`
+
+func TestShowInfoReachesTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+	}{
+		{"show template", ""},
+		{"template override", "master"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, showFlagsTestConfig)
+			fake := newFakeMixcloud()
+			sp.mixcloud = fake
+
+			if err := sp.ProcessShow("night-one", tt.override, "", false); err != nil {
+				t.Fatalf("ProcessShow() error = %v", err)
+			}
+			want := nightOneURL + "=night-one with DJ Example\nWhen I Fall [genre]\n"
+			if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], want) {
+				t.Errorf("updates = %q, want them to start %q", fake.updates, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+const showKeysTestConfig = `
+[shows."Música Nueva"]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Música Nueva"
+enabled = true
+
+[shows."LATE NIGHT"]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Late Night"
+enabled = true
+
+[shows."Drive Time"]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Drive Time"
+enabled = true
+`
+
+func TestProcessShowWithUnicodeKey(t *testing.T) {
+	showURL := mixcloud.GenerateShowURL("testuser", "Música Nueva")
+
+	for _, input := range []string{"Música Nueva", "música nueva", "MÚSICA  NUEVA"} {
+		t.Run(input, func(t *testing.T) {
+			sp := newTestProcessor(t, showKeysTestConfig)
+			fake := newFakeMixcloud()
+			sp.mixcloud = fake
+			events := recordProgress(sp)
+
+			if err := sp.ProcessShow(input, "", "", false); err != nil {
+				t.Fatalf("ProcessShow(%q) error = %v", input, err)
+			}
+			if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], showURL+"=") {
+				t.Errorf("updates = %v, want one update of %s", fake.updates, showURL)
+			}
+			if keys := startedShows(*events); !reflect.DeepEqual(keys, []string{"música nueva"}) {
+				t.Errorf("shows started = %q, want the canonical key", keys)
+			}
+		})
+	}
+}
+
+func TestProcessAllShowsWithUnicodeKeys(t *testing.T) {
+	sp := newTestProcessor(t, showKeysTestConfig)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+	events := recordProgress(sp)
+
+	if err := sp.ProcessAllShows(false); err != nil {
+		t.Fatalf("ProcessAllShows() error = %v", err)
+	}
+	if len(fake.updates) != 3 {
+		t.Fatalf("got %d updates, want one per show: %v", len(fake.updates), fake.updates)
+	}
+	keys := startedShows(*events)
+	sort.Strings(keys)
+	if want := []string{"drive time", "late night", "música nueva"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("shows started = %q, want %q", keys, want)
+	}
+	for _, key := range keys {
+		if _, ok := sp.state.Show(key); !ok {
+			t.Errorf("no publish recorded under %q", key)
+		}
+	}
+}
+
+// startedShows lists the show keys of the show_started events, in order
+func startedShows(events []ProgressEvent) []string {
+	var keys []string
+	for _, event := range events {
+		if event.Event == EventShowStarted {
+			keys = append(keys, event.ShowKey)
+		}
+	}
+	return keys
+}
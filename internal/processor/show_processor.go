@@ -4,21 +4,44 @@
 package processor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/nowwaveradio/mixcloud-updater/internal/analysis"
+	"github.com/nowwaveradio/mixcloud-updater/internal/announcement"
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
 	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/enrichment"
 	"github.com/nowwaveradio/mixcloud-updater/internal/dateutil"
+	"github.com/nowwaveradio/mixcloud-updater/internal/desclen"
 	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
 	"github.com/nowwaveradio/mixcloud-updater/internal/formatter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/httpclient"
+	"github.com/nowwaveradio/mixcloud-updater/internal/links"
 	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
 	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+	"github.com/nowwaveradio/mixcloud-updater/internal/retry"
 	"github.com/nowwaveradio/mixcloud-updater/internal/shows"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textnorm"
 )
 
+// datePlaceholderRegex matches {date:FORMAT} placeholders, e.g. {date:MMMM D YYYY}
+var datePlaceholderRegex = regexp.MustCompile(`\{date:([^{}]+)\}`)
+
 // ShowProcessor orchestrates the complete workflow for processing shows
 type ShowProcessor struct {
 	config       *config.Config
@@ -27,36 +50,169 @@ type ShowProcessor struct {
 	cueResolver  *shows.CueResolver
 	filter       *filter.Filter
 	formatter    *formatter.Formatter
-	mixcloud     *mixcloud.Client
+	mixcloud     mixcloudAPI
 	logger       *slog.Logger
+	options      Options
+	state        *state.State
+	history      *state.History // Loaded on the first publish, see recordHistory
+	pending      *state.Pending // Loaded on first use, see loadPending
+	pacer        *updatePacer
+	progress     ProgressObserver // Optional, see SetProgressObserver
+	runDeadline  time.Duration    // run_deadline_minutes, 0 = none
+	runCtx       context.Context  // Cancelled at runDeadline, see startRun
+	reauth       Reauthorizer     // Optional, see SetReauthorizer
+	reauthTried  bool             // Only the first auth failure of a run re-authenticates
+	reauthPause  time.Duration    // Time spent waiting on re-authentication
+	tokenCheck   TokenCheck       // The /me/ probe after the run's first auth failure
+	apiShows     int              // Shows that reached the Mixcloud API this run, see fallBackOffline
+	wentOffline  string           // Why the run went offline after it started (offline_fallback), "" when it didn't
+	announcement string           // station.announcement_source text for this run, see loadAnnouncement
+	httpClient   *http.Client     // For announcement URLs, nil = default; tests substitute an httptest client
+	location     *time.Location   // processing.timezone, publish windows are evaluated in it
+	now          func() time.Time // Clock for publish windows; tests substitute a fixed time
+	retryPolicy  retry.Policy     // mixcloud.RetryPolicy; tests substitute Sleep
+	lastRun      *BatchResult     // Outcome of the latest run, see LastRun
+	checkpoint   *state.Checkpoint // Progress of a live batch run, see startCheckpoint
+	enricher     *enrichment.Enricher // [enrichment] database, nil when not configured
+}
+
+// mixcloudAPI is the part of the Mixcloud client the processor uses; tests substitute a fake
+type mixcloudAPI interface {
+	GetShowContext(ctx context.Context, showURL string) (*mixcloud.Show, error)
+	UpdateShowContext(ctx context.Context, showURL string, fields map[string]string) error
+}
+
+// showCacheSetter is implemented by clients that can reuse cached GetShow responses
+type showCacheSetter interface {
+	SetShowCache(cache mixcloud.ShowCache)
+}
+
+// statsReporter is implemented by clients that track the API quota from
+// rate-limit response headers
+type statsReporter interface {
+	Stats() mixcloud.Stats
+}
+
+// Options holds run-wide processing switches set from the command line
+type Options struct {
+	// Force downgrades render sanity-check failures to loud warnings
+	Force bool
+	// VerbosePreview prints full dry-run descriptions instead of trimmed previews
+	VerbosePreview bool
+	// PreviewOutput receives the full description of every dry-run show (-output)
+	PreviewOutput io.Writer
+	// DescriptionOutput receives each dry-run description alone, with no header,
+	// so -script -dry-run -show can be piped into other tools
+	DescriptionOutput io.Writer
+	// NoCache disables conditional GetShow requests against the state file cache (-no-cache)
+	NoCache bool
+	// Limit caps ProcessAllShows to the first N enabled shows by priority (<= 0 means no limit)
+	Limit int
+	// StrictCue fails a show on its first malformed CUE track (-strict-cue), like
+	// processing.strict_cue_parsing
+	StrictCue bool
+	// Verify makes dry runs look each show up on Mixcloud, read-only (-verify)
+	Verify bool
+	// Confirm allows live runs to rename update_name shows (-confirm)
+	Confirm bool
+	// Offline queues every show for -retry-failed instead of contacting Mixcloud (-offline)
+	Offline bool
+	// TimeOffset replaces the show's time_offset, e.g. "-00:01:30" (-time-offset)
+	TimeOffset string
+	// CompareTemplates renders every dry-run show with each of these templates
+	// too (-compare-templates)
+	CompareTemplates []string
+	// CompareOutput is the directory each show's compared renders and their
+	// diff are written to (-compare-output)
+	CompareOutput string
+	// Resume makes ProcessAllShows skip the shows an interrupted run published (-resume)
+	Resume bool
+	// NoResume ignores an interrupted run's checkpoint without mentioning it (-no-resume)
+	NoResume bool
+	// Digest is the file a batch run writes its combined digest to (-digest)
+	Digest string
+	// MaxConsecutiveFailures and MaxTotalFailures replace processing.max_consecutive_failures
+	// and max_total_failures when set (-max-consecutive-failures, -max-total-failures)
+	MaxConsecutiveFailures *int
+	MaxTotalFailures       *int
 }
 
 // ProcessingResult contains the results of processing a single show
 type ProcessingResult struct {
-	ShowKey         string
-	ShowName        string
-	CueFile         string
-	ParsedTracks    int
-	FilteredTracks  int
-	ExcludedTracks  int
-	FormattedLength int
-	ShowURL         string
-	Template        string
-	DryRun          bool
-	Success         bool
-	Error           error
-	Duration        time.Duration
+	ShowKey             string
+	ShowName            string
+	URLSource           string // String the URL slug was generated from (url_pattern or show name)
+	Account             string // The show's mixcloud_username its URL is generated under, "" for the station's account
+	CueFile             string
+	Sidecar             string   // Override file next to the CUE file applied to this episode, "" when none
+	SidecarOverrides    []string // Keys the sidecar set, e.g. ["show_name", "template"]
+	ShowDate            string // Date the show aired, YYYY-MM-DD: the -date override or today in the station timezone
+	ParsedTracks        int
+	TrackWarnings       int // Malformed CUE tracks skipped while parsing
+	TrackGaps           int           // Gaps between track starts over max_track_gap_minutes
+	LargestTrackGap     time.Duration // Longest time between consecutive track starts, set with max_track_gap_minutes
+	LengthCheck         *LengthCheck  // Upload length against the tracklist span, set with duration_tolerance_percent
+	Anomaly             *AnomalyCheck // Output against the show's recent publishes, nil for new shows and placeholders
+	FilteredTracks      int
+	ExcludedTracks      int
+	EnrichmentLookups   int // Filtered tracks looked up in the [enrichment] database
+	EnrichedTracks      int // Looked-up tracks the database had a label or year for
+	FormattedLength     int
+	RenderedLength      int               // Estimated length once Mixcloud renders it, see desclen.Rendered
+	Sanitized           textnorm.SanitizeReport // Characters changed by textnorm.SanitizeDescription
+	ReauthPause         time.Duration     // Time paused re-authenticating before this show was retried
+	Description         string            // Formatted description that was (or would be) published
+	UpdateFields        map[string]string // Form fields sent alongside the description
+	PreserveName        bool              // The current title is re-sent with the update (preserve_name)
+	NewName             string            // Name the show is renamed to (update_name), "" when not renaming
+	PreviousName        string            // Name on Mixcloud before the rename, set once the show is looked up
+	ShowURL             string            // Where the show is updated; after a rename, where it now lives; part 1's URL for split shows
+	Parts               []PartResult      // Per-part outcome of a split_at show, nil for shows that aren't split
+	Comparison          []TemplateRender  // Dry run with Options.CompareTemplates: the show rendered with each template
+	generatedURL        string            // URL generated from the config, the key of recorded renames
+	Verified            bool   // Dry run with Options.Verify: the show URL resolved on Mixcloud
+	LiveShowName        string // Dry run with Options.Verify: the show's current name on Mixcloud
+	Template            string
+	TrackOrder          string // config.TrackOrderChronological or config.TrackOrderReverse
+	Messages            messages.Catalog // The show's locale strings, whose truncation markers the description may end with
+	DryRun              bool
+	Success             bool
+	Skipped             bool   // Nothing to publish and on_empty_tracklist = "skip"
+	SkipReason          string // Why the show was skipped
+	Placeholder         bool   // Published the placeholder because no tracks survived filtering
+	Queued              bool   // Offline run: archived and queued for -retry-failed instead of published
+	CarriedOver         bool   // Published by the interrupted run this run resumed (-resume)
+	ArchivePath         string // Offline run: where the description was archived, "" if archiving failed
+	PreviousDescription string // Description on Mixcloud before the update, kept to roll back atomic groups
+	Restored            bool   // Rolled back to PreviousDescription after its atomic group failed
+	RestoreError        error  // Set when the rollback itself failed
+	Error               error
+	FailureCategory     FailureCategory // Set when Error is non-nil
+	Duration            time.Duration
 }
 
 // BatchResult contains the results of batch processing multiple shows
 type BatchResult struct {
-	TotalShows      int
-	ProcessedShows  int
-	SuccessfulShows int
-	FailedShows     int
-	SkippedShows    int
-	Results         []ProcessingResult
-	TotalDuration   time.Duration
+	TotalShows        int
+	ProcessedShows    int
+	SuccessfulShows   int
+	FailedShows       int
+	SkippedShows      int
+	PlaceholderShows  int      // Successful shows published with the empty-tracklist placeholder
+	QueuedShows       int      // Shows queued for -retry-failed by an offline run
+	CarriedOverShows  int      // Successful shows published by the interrupted run this run resumed
+	NotAttemptedShows int      // Enabled shows left out by -limit, the run deadline or a failure threshold
+	NotAttempted      []string // Keys of the shows left out by -limit
+	DeadlineSkipped   []string // Keys of the shows not started before run_deadline_minutes
+	ThresholdSkipped  []string // Keys of the shows not started after a failure threshold was reached
+	FailureAbort      *FailureAbort // The failure that reached a threshold, nil when none was
+	Results           []ProcessingResult
+	TotalDuration     time.Duration
+	PacingDuration    time.Duration // Time spent waiting for min_update_interval_seconds
+	ReauthDuration    time.Duration // Time paused for auto_reauth
+	GroupFailure      *GroupError   // Set when an atomic show group was aborted
+	EmptyRunReason    string        // Why the run had nothing to process, "" when it did
+	Duplicates        []DuplicateSource // Shows of a batch run that share a CUE file or description
 }
 
 // NewShowProcessor creates a new ShowProcessor with all dependencies initialized
@@ -76,15 +232,13 @@ func NewShowProcessor(cfg *config.Config, configPath string) (*ShowProcessor, er
 		return nil, fmt.Errorf("show validation failed: %w", err)
 	}
 
-	// Initialize CUE resolver with processing directory
-	baseDir := cfg.Processing.CueFileDirectory
-	if baseDir == "" {
-		baseDir = cfg.Paths.CueFileDirectory // Fallback to legacy path config
-	}
-	if baseDir == "" {
-		baseDir = "." // Final fallback
+	location, err := cfg.StationLocation()
+	if err != nil {
+		return nil, err
 	}
-	cueResolver := shows.NewCueResolver(baseDir)
+
+	// Initialize CUE resolver with processing directory
+	cueResolver := shows.NewCueResolver(cfg.CueDirectory())
 
 	// Initialize content filter
 	trackFilter, err := filter.NewFilter(cfg)
@@ -94,6 +248,23 @@ func NewShowProcessor(cfg *config.Config, configPath string) (*ShowProcessor, er
 
 	// Initialize formatter with template support
 	trackFormatter := formatter.NewFormatterWithConfig(cfg)
+	if err := checkTrackSpace(cfg, trackFormatter); err != nil {
+		return nil, fmt.Errorf("template validation failed: %w", err)
+	}
+	for _, name := range template.OverriddenBuiltins(cfg) {
+		logger.Get().Info("Configured template replaces the built-in one",
+			slog.String("template", name))
+	}
+	if linksPath := cfg.LinksFilePath(configPath); linksPath != "" {
+		linkTable, err := links.Load(linksPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading links file: %w", err)
+		}
+		trackFormatter.SetLinks(linkTable)
+		logger.Get().Info("Artist links loaded",
+			slog.String("path", linksPath),
+			slog.Int("artists", linkTable.Len()))
+	}
 
 	// Initialize Mixcloud client
 	mixcloudClient, err := mixcloud.NewClient(cfg, configPath)
@@ -101,10 +272,24 @@ func NewShowProcessor(cfg *config.Config, configPath string) (*ShowProcessor, er
 		return nil, fmt.Errorf("initializing Mixcloud client: %w", err)
 	}
 
+	// Announcement URLs go through the same proxy as the Mixcloud API
+	announcementClient, err := httpclient.New(cfg.Network.ProxyURL, announcement.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("network.proxy_url: %w", err)
+	}
+
 	// Use the global file logger
 	log := logger.Get()
 
-	return &ShowProcessor{
+	// Load run history - a broken state file shouldn't block publishing
+	runState, err := state.Load(state.ResolvePath(cfg.Processing.StateFile, configPath))
+	if err != nil {
+		log.Warn("Failed to load state file, starting with empty state",
+			slog.String("path", runState.Path()),
+			slog.String("error", err.Error()))
+	}
+
+	sp := &ShowProcessor{
 		config:      cfg,
 		configPath:  configPath,
 		resolver:    resolver,
@@ -113,34 +298,109 @@ func NewShowProcessor(cfg *config.Config, configPath string) (*ShowProcessor, er
 		formatter:   trackFormatter,
 		mixcloud:    mixcloudClient,
 		logger:      log.Logger, // Use the underlying slog.Logger
-	}, nil
+		state:       runState,
+		pacer:       newUpdatePacer(time.Duration(cfg.Processing.MinUpdateIntervalSeconds) * time.Second),
+		runDeadline: time.Duration(cfg.Processing.RunDeadlineMinutes) * time.Minute,
+		location:    location,
+		now:         time.Now,
+		retryPolicy: mixcloud.RetryPolicy(cfg),
+		httpClient:  announcementClient,
+		enricher:    openEnricher(cfg, configPath),
+	}
+	sp.configureShowCache()
+	return sp, nil
+}
+
+// SetOptions updates the run-wide processing options
+func (sp *ShowProcessor) SetOptions(opts Options) {
+	sp.options = opts
+	sp.configureShowCache()
+}
+
+// configureShowCache points the Mixcloud client's GetShow cache at the state
+// file, unless NoCache is set
+func (sp *ShowProcessor) configureShowCache() {
+	setter, ok := sp.mixcloud.(showCacheSetter)
+	if !ok {
+		return
+	}
+	if sp.options.NoCache || sp.state == nil {
+		setter.SetShowCache(nil)
+		return
+	}
+	setter.SetShowCache(sp.state)
 }
 
 // ProcessShow processes a single show by name or alias
 func (sp *ShowProcessor) ProcessShow(nameOrAlias string, templateOverride string, dateOverride string, dryRun bool) error {
 	startTime := time.Now()
 	
-	fmt.Printf("Processing show: %s\n", nameOrAlias)
-	fmt.Printf("================\n\n")
+	console.Printf("Processing show: %s\n", nameOrAlias)
+	console.Printf("================\n\n")
+
+	defer sp.startRun()()
+	sp.loadAnnouncement()
+	sp.emitRunStarted(RunModeSingle, nameOrAlias, 1, dryRun)
+	batchResult := &BatchResult{TotalShows: 1}
+	defer func() {
+		batchResult.TotalDuration = time.Since(startTime)
+		sp.emitRunFinished(batchResult)
+	}()
 
 	// Find show configuration
 	showCfg := sp.resolver.FindShowConfig(nameOrAlias)
 	if showCfg == nil {
-		return fmt.Errorf("show not found: %s", nameOrAlias)
+		return sp.resolver.NotFoundError(nameOrAlias)
 	}
 
 	showKey := sp.resolver.FindShowKey(nameOrAlias)
+
+	// -date is read the show's way before anything is resolved or published
+	dateOverride, err := sp.resolveDateOverride(showCfg, dateOverride)
+	if err != nil {
+		return fmt.Errorf("invalid -date for %s: %w", showKey, err)
+	}
 	
 	// Check if show is enabled
 	if !showCfg.Enabled {
-		fmt.Printf("⚠️  Show '%s' is disabled in configuration\n", showKey)
-		fmt.Printf("Set enabled = true in config to process this show\n")
-		return nil
+		return sp.finishEmptyRun(batchResult, &ShowDisabledError{ShowKey: showKey},
+			fmt.Sprintf("Show '%s' is disabled in configuration", showKey),
+			"Set enabled = true in config to process this show")
+	}
+
+	// Outside its publish window the show waits for -force
+	if reason, outside := sp.outsidePublishWindow(showKey, showCfg); outside {
+		if !sp.options.Force {
+			console.Printf("⚠️  Show '%s' is %s\n", showKey, reason)
+			console.Printf("Use -force to publish it anyway\n")
+			result := ProcessingResult{ShowKey: showKey, DryRun: dryRun, Skipped: true, SkipReason: reason}
+			batchResult.add(result)
+			sp.emitShowFinished(result)
+			return nil
+		}
+		console.Printf("⚠️  Show '%s' is %s, publishing anyway (-force)\n\n", showKey, reason)
 	}
 
 	// Process the show
 	result := sp.processingleShow(showKey, showCfg, templateOverride, dateOverride, dryRun)
+	if sp.fallBackOffline(result) {
+		result = sp.processingleShow(showKey, showCfg, templateOverride, dateOverride, dryRun)
+	}
 	result.Duration = time.Since(startTime)
+	batchResult.add(result)
+	sp.emitShowFinished(result)
+
+	if result.DryRun && result.Success {
+		sp.printDryRunPreview(console.Content, result)
+		sp.printComparison(result)
+	}
+
+	if result.Error != nil {
+		sp.logger.Error("Show processing failed",
+			slog.String("show_key", showKey),
+			slog.String("failure_category", string(result.FailureCategory)),
+			slog.String("error", result.Error.Error()))
+	}
 
 	// Print results
 	sp.printSingleResult(result)
@@ -148,6 +408,9 @@ func (sp *ShowProcessor) ProcessShow(nameOrAlias string, templateOverride string
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.Queued {
+		return &OfflineRunError{Queued: 1}
+	}
 
 	return nil
 }
@@ -159,62 +422,222 @@ func (sp *ShowProcessor) ProcessAllShows(dryRun bool) error {
 	enabledShows := sp.resolver.ListEnabledShows(true) // sorted by priority
 	
 	if len(enabledShows) == 0 {
-		fmt.Printf("No enabled shows found in configuration.\n")
-		fmt.Printf("Add show configurations with enabled = true to process shows.\n")
-		return nil
+		sp.emitRunStarted(RunModeBatch, "", 0, dryRun)
+		batchResult := &BatchResult{}
+		err := sp.finishEmptyRun(batchResult, ErrNoEnabledShows,
+			"No enabled shows found in configuration.",
+			"Add show configurations with enabled = true to process shows.")
+		batchResult.TotalDuration = time.Since(startTime)
+		sp.emitRunFinished(batchResult)
+		return err
 	}
 
-	fmt.Printf("Processing %d enabled shows\n", len(enabledShows))
-	fmt.Printf("============================\n\n")
-
 	batchResult := &BatchResult{
 		TotalShows:    len(enabledShows),
 		Results:       make([]ProcessingResult, 0, len(enabledShows)),
 		TotalDuration: 0,
 	}
 
+	enabledShows = batchResult.carryOver(enabledShows, sp.resumeRun(dryRun))
+	enabledShows = batchResult.applyLimit(enabledShows, sp.options.Limit)
+	if batchResult.NotAttemptedShows > 0 {
+		sp.logger.Warn("Show limit active, not attempting remaining shows",
+			slog.Int("limit", sp.options.Limit),
+			slog.Int("not_attempted", batchResult.NotAttemptedShows),
+			slog.String("not_attempted_shows", strings.Join(batchResult.NotAttempted, ", ")))
+		console.Printf("⚠️  -limit %d: processing %d of %d enabled shows\n",
+			sp.options.Limit, len(enabledShows), batchResult.TotalShows)
+	}
+
+	console.Printf("Processing %d enabled shows\n", len(enabledShows))
+	console.Printf("============================\n\n")
+	defer sp.startRun()()
+	sp.loadAnnouncement()
+	sp.emitRunStarted(RunModeBatch, "", len(enabledShows), dryRun)
+	sp.startCheckpoint(startTime, enabledShows, dryRun)
+
+	// Render every show before updating any, so shows that picked up the same
+	// CUE file or description are caught before either is published
+	// A show that rendered counts as a success towards the failure thresholds
+	// until its update fails
+	abort := sp.newAbortPolicy()
+	results := make([]ProcessingResult, 0, len(enabledShows))
+	var abortedShows []string
+	for i, showKey := range enabledShows {
+		if sp.deadlineExceeded() {
+			sp.stopAtDeadline(batchResult, enabledShows[i:])
+			break
+		}
+		if abort.trigger != nil {
+			abortedShows = append(abortedShows, enabledShows[i:]...)
+			break
+		}
+		showCfg := sp.config.Shows[showKey]
+		startShow := time.Now()
+		result := sp.prepareBatchShow(showKey, &showCfg, dryRun)
+		result.Duration = time.Since(startShow)
+		results = append(results, result)
+		rendered := result
+		rendered.Success = result.Success || result.readyToPublish()
+		abort.record(rendered)
+	}
+	batchResult.Duplicates = sp.checkDuplicateSources(results)
+
 	// Process shows according to batch size
 	batchSize := sp.config.Processing.BatchSize
 
 	sp.logger.Info("Starting batch processing",
-		slog.Int("total_shows", len(enabledShows)),
+		slog.Int("total_shows", len(results)),
 		slog.Int("batch_size", batchSize))
 	if batchSize <= 0 {
 		batchSize = 5 // Default batch size
 	}
 
-	for i := 0; i < len(enabledShows); i += batchSize {
+	var lateShows []string
+	for i := 0; i < len(results); i += batchSize {
 		end := i + batchSize
-		if end > len(enabledShows) {
-			end = len(enabledShows)
-		}
-
-		batch := enabledShows[i:end]
-		fmt.Printf("Processing batch %d/%d (%d shows)\n", 
-			(i/batchSize)+1, (len(enabledShows)+batchSize-1)/batchSize, len(batch))
-		fmt.Printf("──────────────────────────────────────\n")
-
-		for _, showKey := range batch {
-			showCfg := sp.config.Shows[showKey]
-			result := sp.processingleShow(showKey, &showCfg, "", "", dryRun)
-			
-			batchResult.Results = append(batchResult.Results, result)
-			batchResult.ProcessedShows++
-
-			if result.Error != nil {
-				batchResult.FailedShows++
-				fmt.Printf("❌ Failed: %s - %v\n\n", showKey, result.Error)
-			} else if result.Success {
-				batchResult.SuccessfulShows++
-				fmt.Printf("✅ Success: %s\n\n", showKey)
-			} else {
-				batchResult.SkippedShows++
-				fmt.Printf("⏭️  Skipped: %s\n\n", showKey)
+		if end > len(results) {
+			end = len(results)
+		}
+
+		batch := results[i:end]
+		console.Printf("Processing batch %d/%d (%d shows)\n", 
+			(i/batchSize)+1, (len(results)+batchSize-1)/batchSize, len(batch))
+		console.Printf("──────────────────────────────────────\n")
+
+		for j := range batch {
+			result := &batch[j]
+			if result.readyToPublish() {
+				if sp.deadlineExceeded() {
+					lateShows = append(lateShows, result.ShowKey)
+					continue
+				}
+				if abort.trigger != nil {
+					abortedShows = append(abortedShows, result.ShowKey)
+					continue
+				}
+				startPublish := time.Now()
+				sp.publishPrepared(result, dryRun)
+				result.Duration += time.Since(startPublish)
+				abort.record(*result)
 			}
+
+			batchResult.add(*result)
+			sp.recordCheckpoint(*result)
+			sp.emitShowFinished(*result)
+			sp.printBatchLine(*result)
 		}
 	}
+	if len(lateShows) > 0 {
+		sp.stopAtDeadline(batchResult, lateShows)
+	}
+	if len(abortedShows) > 0 {
+		sp.stopAtFailureThreshold(batchResult, abort.trigger, abortedShows)
+	}
+	sp.finishCheckpoint()
 
 	batchResult.TotalDuration = time.Since(startTime)
+	return sp.finishBatch(batchResult)
+}
+
+// prepareBatchShow renders and checks a show of a batch run, skipping it outside
+// its publish window. Like processingleShow it re-authenticates or finishes the
+// run offline when the show's Mixcloud lookup calls for it.
+func (sp *ShowProcessor) prepareBatchShow(showKey string, showCfg *config.ShowConfig, dryRun bool) ProcessingResult {
+	if reason, outside := sp.outsidePublishWindow(showKey, showCfg); outside {
+		return ProcessingResult{ShowKey: showKey, DryRun: dryRun, Skipped: true, SkipReason: reason}
+	}
+	prepare := func() ProcessingResult {
+		return sp.prepareShow(showKey, showCfg, "", "", dryRun)
+	}
+	result := sp.retryAfterReauth(prepare(), prepare)
+	if sp.fallBackOffline(result) {
+		result = sp.retryAfterReauth(prepare(), prepare)
+	}
+	return result
+}
+
+// publishPrepared updates a show rendered by prepareBatchShow and records the
+// publish. An update failing authentication or reaching an unreachable Mixcloud
+// is retried from the start like processingleShow.
+func (sp *ShowProcessor) publishPrepared(result *ProcessingResult, dryRun bool) {
+	// The run went offline after this show was rendered
+	if sp.offline() {
+		sp.queueOffline(result)
+		return
+	}
+
+	sp.publishShow(result)
+	if result.Success {
+		sp.recordPublish(result)
+		return
+	}
+
+	showKey := result.ShowKey
+	showCfg := sp.config.Shows[showKey]
+	duration := result.Duration
+	retried := sp.retryAfterReauth(*result, func() ProcessingResult {
+		return sp.processShowOnce(showKey, &showCfg, "", "", dryRun)
+	})
+	if sp.fallBackOffline(retried) {
+		retried = sp.processingleShow(showKey, &showCfg, "", "", dryRun)
+	}
+	*result = retried
+	result.Duration = duration
+}
+
+// printBatchLine logs a failed show and prints the one-line outcome of a show in a batch
+func (sp *ShowProcessor) printBatchLine(result ProcessingResult) {
+	showKey := result.ShowKey
+	if result.Error != nil {
+		sp.logger.Error("Show processing failed",
+			slog.String("show_key", showKey),
+			slog.String("failure_category", string(result.FailureCategory)),
+			slog.String("error", result.Error.Error()))
+		console.Failuref("❌ Failed: %s [%s] - %v\n", showKey, result.FailureCategory, result.Error)
+		for _, line := range partLines(result) {
+			console.Failuref("   %s\n", line)
+		}
+		if result.Restored {
+			console.Failuref("   ↩️  Restored previous description\n")
+		} else if result.RestoreError != nil {
+			console.Failuref("   ⚠️  Restore FAILED, new description is still live: %v\n", result.RestoreError)
+		}
+		console.Printf("\n")
+	} else if result.DryRun && result.Success {
+		// Full previews only on request - 30 descriptions in a row are unreadable
+		if sp.options.VerbosePreview {
+			sp.printDryRunPreview(console.Chrome, result)
+		}
+		if sp.options.VerbosePreview {
+			sp.printComparison(result)
+		}
+		console.Printf("✅ Dry run: %s - %s\n", showKey, dryRunSummary(result))
+		for _, render := range result.Comparison {
+			console.Printf("   %s: %s\n", render.Template, render.Summary(result.FilteredTracks))
+		}
+		console.Printf("\n")
+	} else if result.Queued {
+		console.Printf("📥 Queued offline: %s - %s\n\n", showKey, queuedSummary(result))
+	} else if result.Success && result.Placeholder {
+		console.Printf("✅ Success: %s (placeholder - no tracks after filtering)\n\n", showKey)
+	} else if result.Success && len(result.Parts) > 0 {
+		console.Printf("✅ Success: %s (%d parts)\n\n", showKey, len(result.Parts))
+	} else if result.Success && result.NewName != "" {
+		console.Printf("✅ Success: %s (renamed %s, now at %s)\n\n", showKey, renameSummary(result), result.ShowURL)
+	} else if result.Success {
+		console.Printf("✅ Success: %s\n\n", showKey)
+	} else if result.Skipped {
+		console.Printf("⏭️  Skipped: %s - %s\n\n", showKey, result.SkipReason)
+	} else {
+		console.Printf("⏭️  Skipped: %s\n\n", showKey)
+	}
+}
+
+// finishBatch logs and prints the batch summary, returning a BatchError if any show failed
+func (sp *ShowProcessor) finishBatch(batchResult *BatchResult) error {
+	batchResult.PacingDuration = sp.pacer.Waited()
+	sp.emitRunFinished(batchResult)
 
 	// Log batch completion
 	sp.logger.Info("Batch processing completed",
@@ -222,14 +645,37 @@ func (sp *ShowProcessor) ProcessAllShows(dryRun bool) error {
 		slog.Int("successful", batchResult.SuccessfulShows),
 		slog.Int("failed", batchResult.FailedShows),
 		slog.Int("skipped", batchResult.SkippedShows),
+		slog.Int("placeholders", batchResult.PlaceholderShows),
+		slog.Int("queued", batchResult.QueuedShows),
+		slog.Int("carried_over", batchResult.CarriedOverShows),
+		slog.Int("not_attempted", batchResult.NotAttemptedShows),
+		slog.Duration("rate_pacing", batchResult.PacingDuration),
+		slog.Duration("reauth_pause", batchResult.ReauthDuration),
+		slog.String("api_quota_remaining", sp.apiQuotaRemaining()),
 		slog.Duration("total_duration", batchResult.TotalDuration))
 
 	// Print batch summary
 	sp.printBatchSummary(batchResult)
+	sp.writeDigest(batchResult)
 
 	// Return error if any shows failed (but continue processing)
 	if batchResult.FailedShows > 0 {
-		return fmt.Errorf("%d of %d shows failed", batchResult.FailedShows, batchResult.TotalShows)
+		batchErr := &BatchError{
+			Failed:     batchResult.FailedShows,
+			Total:      batchResult.TotalShows,
+			Categories: batchResult.failureCategories(),
+			Abort:      batchResult.FailureAbort,
+		}
+		sp.logger.Error("Batch processing had failures",
+			slog.Int("failed", batchErr.Failed),
+			slog.String("failure_categories", batchErr.categorySummary()))
+		return batchErr
+	}
+	if len(batchResult.DeadlineSkipped) > 0 {
+		return fmt.Errorf("%w: %d shows not attempted", ErrRunDeadlineExceeded, len(batchResult.DeadlineSkipped))
+	}
+	if batchResult.QueuedShows > 0 {
+		return &OfflineRunError{Queued: batchResult.QueuedShows}
 	}
 
 	return nil
@@ -237,20 +683,66 @@ func (sp *ShowProcessor) ProcessAllShows(dryRun bool) error {
 
 // processingleShow handles the core processing logic for a single show
 func (sp *ShowProcessor) processingleShow(showKey string, showCfg *config.ShowConfig, templateOverride string, dateOverride string, dryRun bool) ProcessingResult {
-	result := ProcessingResult{
+	result := sp.processShowOnce(showKey, showCfg, templateOverride, dateOverride, dryRun)
+	return sp.retryAfterReauth(result, func() ProcessingResult {
+		return sp.processShowOnce(showKey, showCfg, templateOverride, dateOverride, dryRun)
+	})
+}
+
+// processShowOnce prepares and publishes a show without re-authenticating
+func (sp *ShowProcessor) processShowOnce(showKey string, showCfg *config.ShowConfig, templateOverride string, dateOverride string, dryRun bool) ProcessingResult {
+	result := sp.prepareShow(showKey, showCfg, templateOverride, dateOverride, dryRun)
+	if !result.readyToPublish() {
+		return result
+	}
+
+	sp.publishShow(&result)
+	if result.Success {
+		sp.recordPublish(&result)
+	}
+	return result
+}
+
+// readyToPublish reports whether a prepared show passed every pre-check and
+// only the Mixcloud update remains
+func (r *ProcessingResult) readyToPublish() bool {
+	return !r.DryRun && r.Error == nil && !r.Skipped && !r.Success && !r.Queued
+}
+
+// prepareShow resolves, renders and checks a show and, outside dry runs, verifies
+// it exists on Mixcloud - everything short of updating it
+func (sp *ShowProcessor) prepareShow(showKey string, showCfg *config.ShowConfig, templateOverride string, dateOverride string, dryRun bool) (result ProcessingResult) {
+	result = ProcessingResult{
 		ShowKey:  showKey,
 		DryRun:   dryRun,
 		Template: templateOverride,
 	}
 
+	// Anything failing before the Mixcloud API is reached is a config/source problem
+	reachedAPI := false
+	defer func() {
+		if result.Error == nil {
+			return
+		}
+		if !reachedAPI {
+			result.Error = &SourceError{Err: result.Error}
+		}
+		result.FailureCategory = CategorizeFailure(result.Error)
+	}()
+
 	// Log processing start
 	sp.logger.Info("Processing show",
 		slog.String("show_key", showKey),
 		slog.Bool("dry_run", dryRun),
 		slog.String("template_override", templateOverride))
+	sp.emit(ProgressEvent{Event: EventShowStarted, ShowKey: showKey})
 
-	// Resolve CUE file
-	cueFile, err := sp.cueResolver.ResolveCueFile(showCfg)
+	// Resolve CUE file, the -date episode's when cue_file_pattern has {date}
+	var cueDate time.Time
+	if dateOverride != "" {
+		cueDate, _ = sp.parseFlexibleDate(dateOverride)
+	}
+	cueFile, err := sp.cueResolver.ResolveCueFileForDate(showCfg, cueDate)
 	if err != nil {
 		sp.logger.Error("Failed to resolve CUE file",
 			slog.String("show_key", showKey),
@@ -260,6 +752,7 @@ func (sp *ShowProcessor) processingleShow(showKey string, showCfg *config.ShowCo
 	}
 	result.CueFile = cueFile
 	sp.logger.Debug("CUE file resolved", slog.String("file", cueFile))
+	sp.emitStep(showKey, StepResolve, cueFile, nil)
 
 	// Validate CUE file
 	if err := sp.cueResolver.ValidateCueFile(cueFile); err != nil {
@@ -271,8 +764,21 @@ func (sp *ShowProcessor) processingleShow(showKey string, showCfg *config.ShowCo
 		return result
 	}
 
+	// Apply the episode's sidecar overrides, see sidecar.go
+	showCfg, dateOverride, trackFilter, err := sp.applySidecar(&result, showCfg, templateOverride, dateOverride)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
 	// Parse CUE file
-	cueSheet, err := cue.ParseCueFile(cueFile)
+	strict := sp.options.StrictCue || sp.config.Processing.StrictCueParsing
+	cueSheet, warnings, err := cue.ParseCueFileWithOptions(cueFile, cue.ParseOptions{
+		Strict:          strict,
+		SkipIndex00Only: sp.config.Processing.SkipIndex00OnlyTracks,
+	})
+	sp.logTrackWarnings(showKey, cueFile, warnings)
+	result.TrackWarnings = len(warnings)
 	if err != nil {
 		sp.logger.Error("CUE file parsing failed",
 			slog.String("show_key", showKey),
@@ -282,6 +788,36 @@ func (sp *ShowProcessor) processingleShow(showKey string, showCfg *config.ShowCo
 		return result
 	}
 	result.ParsedTracks = len(cueSheet.Tracks)
+	cueSheet.Tracks, err = sp.applyTimeOffset(showKey, showCfg, cueSheet.Tracks)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	gapErr := sp.checkTrackGaps(&result, showCfg, cueFile, cueSheet.Tracks)
+	sp.measureTrackSpan(&result, showCfg, cueSheet.Tracks)
+	parseCounts := map[string]int{"tracks": result.ParsedTracks, "malformed": result.TrackWarnings}
+	if showCfg.MaxTrackGapMinutes > 0 {
+		parseCounts["gaps"] = result.TrackGaps
+		parseCounts["largest_gap_seconds"] = int(result.LargestTrackGap.Seconds())
+	}
+	sp.emitStep(showKey, StepParse, "", parseCounts)
+
+	if err := sp.checkTrackWarnings(result.ParsedTracks, result.TrackWarnings); err != nil {
+		sp.logger.Error("Too many malformed CUE tracks",
+			slog.String("show_key", showKey),
+			slog.String("file", cueFile),
+			slog.String("error", err.Error()))
+		result.Error = fmt.Errorf("parsing CUE file: %w", err)
+		return result
+	}
+	if gapErr != nil {
+		sp.logger.Error("Gaps between CUE tracks",
+			slog.String("show_key", showKey),
+			slog.String("file", cueFile),
+			slog.String("error", gapErr.Error()))
+		result.Error = fmt.Errorf("checking CUE track gaps: %w", gapErr)
+		return result
+	}
 
 	sp.logger.Info("CUE file parsed successfully",
 		slog.String("show_key", showKey),
@@ -296,25 +832,38 @@ func (sp *ShowProcessor) processingleShow(showKey string, showCfg *config.ShowCo
 	}
 
 	// Filter tracks
-	var filteredTracks []cue.Track
-	for _, track := range cueSheet.Tracks {
-		if sp.filter.ShouldIncludeTrack(&track) && !track.IsEmpty() {
-			filteredTracks = append(filteredTracks, track)
-		}
-	}
+	filteredTracks, excludedReasons := trackFilter.Apply(cueSheet.Tracks)
 	result.FilteredTracks = len(filteredTracks)
 	result.ExcludedTracks = result.ParsedTracks - result.FilteredTracks
+	sp.emitStep(showKey, StepFilter, "", map[string]int{"tracks": result.FilteredTracks, "excluded": result.ExcludedTracks})
 
 	sp.logger.Info("Track filtering completed",
 		slog.String("show_key", showKey),
 		slog.Int("included", result.FilteredTracks),
 		slog.Int("excluded", result.ExcludedTracks))
+	filteredTracks = sp.enrichTracks(showKey, filteredTracks, &result)
 
 	if result.FilteredTracks == 0 {
-		sp.logger.Warn("No tracks remaining after filtering",
-			slog.String("show_key", showKey))
-		result.Error = fmt.Errorf("no tracks remaining after filtering")
-		return result
+		// AIDEV-NOTE: An all-station-content episode is a legitimate "nothing to
+		// publish", so shows can opt out of treating it as a failure
+		switch showCfg.EmptyTracklistAction() {
+		case config.OnEmptyTracklistSkip:
+			sp.logger.Info("No tracks remaining after filtering, skipping show",
+				slog.String("show_key", showKey),
+				slog.Int("excluded", result.ExcludedTracks))
+			result.Skipped = true
+			result.SkipReason = fmt.Sprintf("all %d tracks excluded by filters", result.ParsedTracks)
+			return result
+		case config.OnEmptyTracklistPublishPlaceholder:
+			sp.logger.Warn("No tracks remaining after filtering, publishing placeholder",
+				slog.String("show_key", showKey))
+			result.Placeholder = true
+		default:
+			sp.logger.Warn("No tracks remaining after filtering",
+				slog.String("show_key", showKey))
+			result.Error = fmt.Errorf("no tracks remaining after filtering")
+			return result
+		}
 	}
 
 	// Generate show name with date substitution
@@ -327,107 +876,485 @@ func (sp *ShowProcessor) processingleShow(showKey string, showCfg *config.ShowCo
 		return result
 	}
 	result.ShowName = showName
+	result.ShowDate = sp.showDate(dateOverride)
 	sp.logger.Debug("Show name generated", slog.String("name", showName))
 
-	// Generate show URL
-	showURL := mixcloud.GenerateShowURL(sp.config.Station.MixcloudUsername, showName)
-	result.ShowURL = showURL
+	if err := sp.enforceRenderCheck(showKey, "show name", showName); err != nil {
+		result.Error = err
+		return result
+	}
 
-	// Select and format with template
-	var formattedTracklist string
-	if templateOverride != "" {
-		// Use template override
-		metadata := map[string]interface{}{
-			"show_title": showName,
-			"show_date":  time.Now().Format("January 2, 2006"),
+	// Generate the URL source separately - uploaders often add a date suffix to the
+	// slug that isn't part of the display title
+	urlSource, err := sp.generateURLSource(showCfg, showName, dateOverride)
+	if err != nil {
+		sp.logger.Error("URL source generation failed",
+			slog.String("show_key", showKey),
+			slog.String("error", err.Error()))
+		result.Error = fmt.Errorf("generating show URL: %w", err)
+		return result
+	}
+	result.URLSource = urlSource
+
+	if urlSource != showName {
+		if err := sp.enforceRenderCheck(showKey, "URL pattern", urlSource); err != nil {
+			result.Error = err
+			return result
 		}
-		formattedTracklist = sp.formatter.FormatTracklistWithTemplate(filteredTracks, sp.filter, templateOverride, metadata)
-		result.Template = templateOverride
-	} else {
-		// Use show-specific template selection
-		metadata := map[string]interface{}{
-			"show_title": showName,
-			"show_date":  time.Now().Format("January 2, 2006"),
+	}
+
+	// Expand the extra form fields sent with the update
+	result.UpdateFields, err = sp.expandUpdateFields(showCfg, dateOverride)
+	if err != nil {
+		sp.logger.Error("Update field expansion failed",
+			slog.String("show_key", showKey),
+			slog.String("error", err.Error()))
+		result.Error = err
+		return result
+	}
+	for name, value := range result.UpdateFields {
+		if err := sp.enforceRenderCheck(showKey, "update field "+name, value); err != nil {
+			result.Error = err
+			return result
 		}
-		formattedTracklist = sp.formatter.FormatTracklistWithShowConfig(filteredTracks, sp.filter, showCfg, metadata)
-		
-		// Determine which template was used
-		if selectedTemplate, err := sp.formatter.SelectTemplateForShow(showCfg); err == nil {
-			result.Template = selectedTemplate
-		} else {
-			result.Template = "classic"
+	}
+	genres := analysis.Genres(filteredTracks, 0)
+	if showCfg.TagsFromGenres {
+		addGenreTags(result.UpdateFields, genres, showCfg.GenreTagLimit())
+	}
+	result.PreserveName = showCfg.PreserveName
+	if showCfg.UpdateName {
+		if err := sp.prepareRename(&result, showCfg, dateOverride); err != nil {
+			sp.logger.Error("Show rename not prepared",
+				slog.String("show_key", showKey),
+				slog.String("error", err.Error()))
+			result.Error = err
+			return result
 		}
 	}
 
-	result.FormattedLength = len(formattedTracklist)
+	// Generate show URL
+	result.Account = showCfg.MixcloudUsername
+	result.ShowURL = mixcloud.GenerateShowURL(sp.config.MixcloudUsernameFor(showCfg), urlSource)
+	sp.followRename(&result)
+	showURL := result.ShowURL
+	sp.logger.Debug("Show URL generated",
+		slog.String("url_source", urlSource),
+		slog.String("url", showURL))
 
-	sp.logger.Info("Tracklist formatted",
-		slog.String("show_key", showKey),
-		slog.String("template", result.Template),
-		slog.Int("length", result.FormattedLength))
+	// Select and format with template
+	result.TrackOrder = sp.config.TrackOrderFor(showCfg)
+	result.Messages = showCfg.Messages()
+	metadata := template.Metadata{
+		ShowTitle: showName,
+		ShowDate:  sp.displayShowDate(dateOverride),
+		ShowKey:   showKey,
+		Catalog:   cueSheet.Catalog,
+		MaxLinks:  showCfg.MaxLinks,
+		// Lets footers acknowledge edits, e.g. "3 non-music items omitted"
+		HasCounts:       true,
+		IncludedCount:   result.FilteredTracks,
+		ExcludedCount:   result.ExcludedTracks,
+		ExcludedReasons: excludedReasons,
+		// Provenance for .Provenance and include_provenance
+		CueFile:           filepath.Base(cueFile),
+		GeneratedAt:       sp.now().In(sp.location).Format("2006-01-02 15:04"),
+		ToolVersion:       constants.Version,
+		IncludeProvenance: showCfg.IncludeProvenance,
+		// Newest first with track_order = "reverse"; the formatter orders each description
+		TrackOrder: result.TrackOrder,
+		// Artist before title with track_style = "artist-first"
+		TrackStyle: sp.config.TrackStyleFor(showCfg),
+		// Album hours as one entry per artist with collapse_same_artist
+		Collapse: template.ArtistCollapse{
+			Enabled:   showCfg.CollapseSameArtist,
+			Separator: showCfg.CollapseSeparator,
+			MaxTitles: showCfg.CollapseMaxTitles,
+		},
+		// compact_separator and compact_max_length, also when -template picks compact
+		Compact: template.CompactStyleFor(showCfg),
+		// Per-show description_max_length; the formatter is shared by every show
+		MaxLength: showCfg.DescriptionLimit(),
+		// station.announcement_source, above the tracks in classic mode with announcement_auto_prepend
+		Announcement:        sp.announcement,
+		AnnouncementPrepend: sp.config.Station.AnnouncementAutoPrepend,
+		// .Show and hasFlag, for templates shared by shows that vary a little
+		ShowFlags:    showCfg.Flags,
+		TemplateVars: showCfg.TemplateVars,
+		// The show's locale and strings overrides, for fallbacks and truncation markers
+		Messages: result.Messages,
+		// .Genres, from every filtered track even when a split part shows fewer
+		Genres: genres[:min(len(genres), sp.config.GenreLimit())],
+	}
+	if len(showCfg.SplitAt) > 0 {
+		if err := sp.prepareParts(&result, showCfg, templateOverride, filteredTracks, metadata); err != nil {
+			result.Error = err
+			return result
+		}
+	} else {
+		formattedTracklist := sp.formatDescription(&result, showCfg, templateOverride, filteredTracks, result.Placeholder, metadata)
+		result.FormattedLength = len(formattedTracklist)
+		result.RenderedLength = desclen.Rendered(formattedTracklist)
+		result.Description = formattedTracklist
 
-	if formattedTracklist == "" {
-		sp.logger.Error("Formatting produced empty result",
-			slog.String("show_key", showKey))
-		result.Error = fmt.Errorf("formatting produced empty result")
+		sp.logger.Info("Tracklist formatted",
+			slog.String("show_key", showKey),
+			slog.String("template", result.Template),
+			slog.Int("length", result.FormattedLength),
+			slog.Int("rendered_length", result.RenderedLength))
+
+		if err := sp.checkDescription(showKey, formattedTracklist, showCfg.DescriptionLimit()); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+	sp.emitStep(showKey, StepFormat, result.Template, map[string]int{"chars": result.FormattedLength})
+	if err := sp.checkAnomaly(&result); err != nil {
+		result.Error = err
 		return result
 	}
 
-	// Handle dry run
+	// Handle dry run - callers print the preview
 	if dryRun {
-		fmt.Printf("DRY RUN - Would update %s:\n", showName)
-		fmt.Printf("─────────────────────────────────────────\n")
-		fmt.Printf("%s\n", formattedTracklist)
-		fmt.Printf("─────────────────────────────────────────\n")
+		if len(sp.options.CompareTemplates) > 0 {
+			sp.compareTemplates(&result, showCfg, filteredTracks, metadata)
+			sp.writeComparison(result)
+		}
+		sp.writePreviewOutput(result)
+		if sp.options.Verify && len(result.Parts) > 0 {
+			reachedAPI = true
+			sp.verifyParts(&result, showCfg)
+			if err := result.partsError(); err != nil {
+				result.Error = err
+				return result
+			}
+		} else if sp.options.Verify {
+			reachedAPI = true
+			existing, err := sp.verifyDryRun(&result)
+			if err != nil {
+				sp.logger.Error("Show verification failed",
+					slog.String("show_key", showKey),
+					slog.String("url", showURL),
+					slog.String("error", err.Error()))
+				result.Error = fmt.Errorf("verifying show exists: %w", err)
+				return result
+			}
+			if err := sp.checkAudioLength(&result, showCfg, existing); err != nil {
+				result.Error = err
+				return result
+			}
+			if result.NewName != "" {
+				sp.checkRename(&result, result.LiveShowName)
+			}
+		}
 		result.Success = true
 		return result
 	}
 
-	// Verify show exists on Mixcloud with retry logic
+	// Offline runs stop here, before the API
+	if sp.offline() {
+		sp.queueOffline(&result)
+		return result
+	}
+
+	reachedAPI = true
+	sp.apiShows++
+	sp.verifyForPublish(&result, showCfg)
+	return result
+}
+
+// verifyForPublish looks a show about to be published up on Mixcloud with retry
+// logic, recording what the update needs from the live show. Split shows verify
+// each part, and only fail when no part resolves.
+func (sp *ShowProcessor) verifyForPublish(result *ProcessingResult, showCfg *config.ShowConfig) {
+	showKey := result.ShowKey
+	showURL := result.ShowURL
+	if len(result.Parts) > 0 {
+		// Parts that resolve are still updated when others don't
+		sp.verifyParts(result, showCfg)
+		if result.failedParts() == len(result.Parts) {
+			result.Error = result.partsError()
+		}
+		return
+	}
 	sp.logger.Debug("Verifying show exists on Mixcloud", slog.String("url", showURL))
-	_, err = sp.verifyShowWithRetry(showURL, 3)
+	existing, err := sp.verifyShowWithRetry(sp.runContext(), showURL)
 	if err != nil {
 		sp.logger.Error("Show verification failed",
 			slog.String("show_key", showKey),
 			slog.String("url", showURL),
 			slog.String("error", err.Error()))
 		result.Error = fmt.Errorf("verifying show exists: %w", err)
-		return result
+		return
+	}
+	if existing != nil {
+		result.PreviousDescription = existing.Description
+	}
+	if !sp.options.NoCache {
+		sp.saveState(showKey) // Keep the response cache for the next run
+	}
+	if err := sp.checkAudioLength(result, showCfg, existing); err != nil {
+		result.Error = err
+		return
+	}
+	sp.emitStep(showKey, StepVerify, showURL, nil)
+	if showCfg.PreserveName {
+		sp.preserveName(result, existing)
+	}
+	if result.NewName != "" && existing != nil {
+		sp.checkRename(result, existing.Name)
+	}
+}
+
+// publishShow updates a prepared show's description on Mixcloud
+func (sp *ShowProcessor) publishShow(result *ProcessingResult) {
+	if len(result.Parts) > 0 {
+		sp.publishParts(result)
+		return
 	}
 
-	// Update show description with retry logic
 	sp.logger.Info("Updating show description",
-		slog.String("show_key", showKey),
-		slog.String("url", showURL))
-	err = sp.updateShowWithRetry(showURL, formattedTracklist, 3)
+		slog.String("show_key", result.ShowKey),
+		slog.String("url", result.ShowURL))
+
+	editedURL, err := sp.editShowWithRetry(sp.runContext(), result.ShowURL, result.formFields(result.Description))
 	if err != nil {
 		sp.logger.Error("Show description update failed",
-			slog.String("show_key", showKey),
-			slog.String("url", showURL),
+			slog.String("show_key", result.ShowKey),
+			slog.String("url", result.ShowURL),
 			slog.String("error", err.Error()))
 		result.Error = fmt.Errorf("updating show description: %w", err)
-		return result
+		result.FailureCategory = CategorizeFailure(result.Error)
+		return
 	}
 
 	sp.logger.Info("Show description updated successfully",
-		slog.String("show_key", showKey),
-		slog.String("url", showURL))
+		slog.String("show_key", result.ShowKey),
+		slog.String("url", result.ShowURL))
+	if result.NewName != "" {
+		sp.completeRename(result, editedURL)
+	}
+	sp.emitStep(result.ShowKey, StepUpdate, result.ShowURL, map[string]int{"fields": len(result.UpdateFields) + 1})
 	result.Success = true
-	return result
+}
+
+// recordPublish saves a successful publish to the state and history files. Failures
+// are logged but don't fail the show - the update on Mixcloud already happened.
+func (sp *ShowProcessor) recordPublish(result *ProcessingResult) {
+	sp.dropPending(result)
+	if sp.state == nil {
+		return
+	}
+
+	publishedAt := time.Now()
+	sp.state.RecordPublish(result.ShowKey, state.ShowState{
+		LastPublished:   publishedAt,
+		ShowURL:         result.ShowURL,
+		TrackCount:      result.FilteredTracks,
+		DescriptionHash: state.HashDescription(result.Description),
+	})
+	sp.saveState(result.ShowKey)
+	sp.recordHistory(result, publishedAt)
+}
+
+// recordHistory appends a publish to the show's history. Failures are logged,
+// like saveState.
+func (sp *ShowProcessor) recordHistory(result *ProcessingResult, publishedAt time.Time) {
+	history, err := sp.loadHistory()
+	if err != nil {
+		// Don't overwrite a history that couldn't be read
+		sp.logger.Warn("Failed to load history file, not recording history",
+			slog.String("show_key", result.ShowKey),
+			slog.String("path", history.Path()),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	history.Append(result.ShowKey, state.HistoryEntry{
+		PublishedAt:       publishedAt,
+		ShowDate:          result.ShowDate,
+		CueFile:           result.CueFile,
+		ParsedTracks:      result.ParsedTracks,
+		Tracks:            result.FilteredTracks,
+		ExcludedTracks:    result.ExcludedTracks,
+		DescriptionLength: result.FormattedLength,
+		Truncated:         isDescriptionTruncated(result.Description, result.Messages),
+		Template:          result.Template,
+		ShowURL:           result.ShowURL,
+		DescriptionHash:   state.HashDescription(result.Description),
+		Length:            historyLength(result.LengthCheck),
+	}, sp.config.Processing.HistoryEntries)
+	if err := history.Save(); err != nil {
+		sp.logger.Warn("Failed to save history file",
+			slog.String("show_key", result.ShowKey),
+			slog.String("path", history.Path()),
+			slog.String("error", err.Error()))
+	}
+}
+
+// formatDescription renders tracks with the show's template (or templateOverride)
+// and sanitizes the result, recording the template and sanitized characters on
+// result. With placeholder set the placeholder line is rendered instead.
+func (sp *ShowProcessor) formatDescription(result *ProcessingResult, showCfg *config.ShowConfig, templateOverride string, tracks []cue.Track, placeholder bool, metadata template.Metadata) string {
+	// AIDEV-NOTE: tracks went through the show's filter already, so the formatter
+	// gets no filter - a second pass would only re-run every rule on every track
+	var formattedTracklist string
+	if templateOverride != "" {
+		// Use template override
+		result.Template = templateOverride
+		if placeholder {
+			formattedTracklist = sp.formatter.FormatPlaceholder(templateOverride, showCfg.EmptyTracklistPlaceholderText(), metadata)
+		} else {
+			formattedTracklist = sp.formatter.FormatTracklistWithTemplate(tracks, nil, templateOverride, metadata)
+		}
+	} else {
+		// Determine which template the show uses
+		if selectedTemplate, err := sp.formatter.SelectTemplateForShow(showCfg); err == nil {
+			result.Template = selectedTemplate
+		} else {
+			result.Template = "classic"
+		}
+
+		// Use show-specific template selection
+		if placeholder {
+			formattedTracklist = sp.formatter.FormatPlaceholder(result.Template, showCfg.EmptyTracklistPlaceholderText(), metadata)
+		} else {
+			formattedTracklist = sp.formatter.FormatTracklistWithShowConfig(tracks, nil, showCfg, metadata)
+		}
+	}
+
+	// Sanitize before measuring so the lengths are those of what is sent
+	formattedTracklist, sanitized := textnorm.SanitizeDescription(formattedTracklist, sp.config.Processing.StripZeroWidth)
+	if sanitized.Changed() {
+		sp.logger.Debug("Sanitized description",
+			slog.String("show_key", result.ShowKey),
+			slog.Int("control_chars", sanitized.ControlChars),
+			slog.Int("line_endings", sanitized.LineEndings),
+			slog.Int("non_breaking_spaces", sanitized.NonBreakingSpaces),
+			slog.Int("zero_width", sanitized.ZeroWidth))
+	}
+	result.Sanitized.ControlChars += sanitized.ControlChars
+	result.Sanitized.LineEndings += sanitized.LineEndings
+	result.Sanitized.NonBreakingSpaces += sanitized.NonBreakingSpaces
+	result.Sanitized.ZeroWidth += sanitized.ZeroWidth
+
+	return formattedTracklist
+}
+
+// checkDescription rejects a formatted description that is empty, shows render
+// artifacts or is over maxLength, the show's description limit
+func (sp *ShowProcessor) checkDescription(showKey, description string, maxLength int) error {
+	if description == "" {
+		sp.logger.Error("Formatting produced empty result",
+			slog.String("show_key", showKey))
+		return fmt.Errorf("formatting produced empty result")
+	}
+
+	if err := sp.enforceRenderCheck(showKey, descriptionSource, description); err != nil {
+		return err
+	}
+
+	lengthModel := sp.config.DescriptionLengthModel()
+	if length := lengthModel.Length(description); length > maxLength {
+		return fmt.Errorf("%w: %d characters (%s, max %d)",
+			mixcloud.ErrDescriptionTooLong, length, lengthModel, maxLength)
+	}
+	return nil
+}
+
+// checkTrackSpace checks each enabled show's template against the show's
+// description limit, so a min_track_space that can't be met stops the run at
+// startup rather than publishing a description with room for one track
+func checkTrackSpace(cfg *config.Config, trackFormatter *formatter.Formatter) error {
+	showKeys := make([]string, 0, len(cfg.Shows))
+	for showKey := range cfg.Shows {
+		showKeys = append(showKeys, showKey)
+	}
+	sort.Strings(showKeys)
+
+	var problems []string
+	for _, showKey := range showKeys {
+		showCfg := cfg.Shows[showKey]
+		if !showCfg.Enabled {
+			continue
+		}
+		templateName, err := trackFormatter.SelectTemplateForShow(&showCfg)
+		if err != nil {
+			continue // Reported when the show is processed
+		}
+		if err := trackFormatter.CheckTrackSpace(templateName, showCfg.DescriptionLimit()); err != nil {
+			problems = append(problems, fmt.Sprintf("show '%s': %v", showKey, err))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// saveState writes the state file, logging (not failing) on error
+func (sp *ShowProcessor) saveState(showKey string) {
+	if sp.state == nil {
+		return
+	}
+	if err := sp.state.Save(); err != nil {
+		sp.logger.Warn("Failed to save state file",
+			slog.String("show_key", showKey),
+			slog.String("path", sp.state.Path()),
+			slog.String("error", err.Error()))
+	}
+}
+
+// enforceRenderCheck runs the post-render sanity check on text and decides whether
+// the show fails. With the force option set, artifacts are only warned about.
+func (sp *ShowProcessor) enforceRenderCheck(showKey, source, text string) error {
+	checkErr := checkRenderArtifacts(source, text)
+	if checkErr == nil {
+		return nil
+	}
+
+	if sp.options.Force {
+		sp.logger.Warn("Rendered output contains template artifacts, continuing due to -force",
+			slog.String("show_key", showKey),
+			slog.String("source", source),
+			slog.String("error", checkErr.Error()))
+		console.Printf("⚠️  WARNING: %v (continuing because -force is set)\n", checkErr)
+		return nil
+	}
+
+	sp.logger.Error("Rendered output contains template artifacts",
+		slog.String("show_key", showKey),
+		slog.String("source", source),
+		slog.String("error", checkErr.Error()))
+	return fmt.Errorf("render sanity check failed (use -force to override): %w", checkErr)
 }
 
 // generateShowName generates the final show name with date substitution
 func (sp *ShowProcessor) generateShowName(showCfg *config.ShowConfig, cueFile string, dateOverride string) (string, error) {
-	showName := showCfg.ShowNamePattern
-	if showName == "" {
+	if showCfg.ShowNamePattern == "" {
 		return "", fmt.Errorf("show_name_pattern is required")
 	}
+	return sp.expandShowPattern(showCfg.ShowNamePattern, showCfg, dateOverride)
+}
 
+// generateURLSource returns the string the Mixcloud URL slug is built from.
+// Uses url_pattern when configured, otherwise falls back to the generated show name.
+func (sp *ShowProcessor) generateURLSource(showCfg *config.ShowConfig, showName string, dateOverride string) (string, error) {
+	if showCfg.URLPattern == "" {
+		return showName, nil
+	}
+	return sp.expandShowPattern(showCfg.URLPattern, showCfg, dateOverride)
+}
+
+// expandShowPattern replaces {date}, {date:FORMAT}, {station} and the calendar
+// placeholders (see SupportedPlaceholders) in a show name or URL pattern
+func (sp *ShowProcessor) expandShowPattern(pattern string, showCfg *config.ShowConfig, dateOverride string) (string, error) {
 	// Date handling with simple priority:
 	// 1. Command line date override (if provided)
 	// 2. Current date (default)
 	
 	var finalDate string
+	showDate := time.Now()
 	
 	if dateOverride != "" {
 		// Parse the date override and reformat according to show's date_format
@@ -439,6 +1366,7 @@ func (sp *ShowProcessor) generateShowName(showCfg *config.ShowConfig, cueFile st
 			}
 			goLayout := sp.convertDateFormatToGoLayout(showCfg.DateFormat)
 			finalDate = parsedDate.Format(goLayout)
+			showDate = parsedDate
 		} else {
 			// No format specified, use the override as-is
 			finalDate = dateOverride
@@ -447,19 +1375,54 @@ func (sp *ShowProcessor) generateShowName(showCfg *config.ShowConfig, cueFile st
 		// Use current date with configured format
 		if showCfg.DateFormat != "" {
 			goLayout := sp.convertDateFormatToGoLayout(showCfg.DateFormat)
-			finalDate = time.Now().Format(goLayout)
+			finalDate = showDate.Format(goLayout)
 		} else {
-			finalDate = time.Now().Format("01/02/2006")
+			finalDate = showDate.Format("01/02/2006")
 		}
 	}
+
+	// Replace {date:FORMAT} placeholders, which carry their own format
+	// AIDEV-NOTE: Used by url_pattern for slugs like "show-june-28-2025" while the
+	// title keeps date_format
+	var expandErr error
+	result := datePlaceholderRegex.ReplaceAllStringFunc(pattern, func(match string) string {
+		format := datePlaceholderRegex.FindStringSubmatch(match)[1]
+		if dateOverride != "" && showCfg.DateFormat == "" {
+			parsedDate, err := sp.parseFlexibleDate(dateOverride)
+			if err != nil {
+				expandErr = fmt.Errorf("invalid date format '%s' for %s: %w", dateOverride, match, err)
+				return match
+			}
+			return dateutil.FormatDateWithPattern(parsedDate, format)
+		}
+		return dateutil.FormatDateWithPattern(showDate, format)
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
 	
+	// Replace {week}, {year} and the other calendar placeholders
+	result, expandErr = expandCalendarPlaceholders(result, pattern, func() (time.Time, error) {
+		if dateOverride != "" && showCfg.DateFormat == "" {
+			parsedDate, err := sp.parseFlexibleDate(dateOverride)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid date format '%s': %w", dateOverride, err)
+			}
+			return parsedDate, nil
+		}
+		return showDate, nil
+	}, sp.options.Force)
+	if expandErr != nil {
+		return "", expandErr
+	}
+
 	// Replace the {date} placeholder with the final date
-	showName = strings.ReplaceAll(showName, "{date}", finalDate)
+	result = strings.ReplaceAll(result, "{date}", finalDate)
 
 	// Replace other placeholders
-	showName = strings.ReplaceAll(showName, "{station}", sp.config.Station.Name)
+	result = strings.ReplaceAll(result, "{station}", sp.config.Station.Name)
 
-	return showName, nil
+	return result, nil
 }
 
 
@@ -469,118 +1432,461 @@ func (sp *ShowProcessor) convertDateFormatToGoLayout(userFormat string) string {
 	return dateutil.FormatDateToGoLayout(userFormat)
 }
 
-// parseFlexibleDate attempts to parse a date string using various common formats
-// AIDEV-NOTE: Deprecated - replaced by dateutil.ParseFlexibleDate for consistency
+// showDay returns the day a show aired: the -date override (backfill passes
+// each episode's date as one), or today in the station timezone without one.
+// ok is false for an override no format reads.
+func (sp *ShowProcessor) showDay(dateOverride string) (day time.Time, ok bool) {
+	if dateOverride == "" {
+		return sp.stationNow(), true
+	}
+	parsed, err := sp.parseFlexibleDate(dateOverride)
+	return parsed, err == nil
+}
+
+// showDate returns the date a show aired as YYYY-MM-DD for the history, or an
+// override no format reads as given
+func (sp *ShowProcessor) showDate(dateOverride string) string {
+	if day, ok := sp.showDay(dateOverride); ok {
+		return day.Format("2006-01-02")
+	}
+	return dateOverride
+}
+
+// displayShowDate returns the same date as showDate for {{.ShowDate}}, e.g.
+// "June 28, 2025"
+func (sp *ShowProcessor) displayShowDate(dateOverride string) string {
+	if day, ok := sp.showDay(dateOverride); ok {
+		return day.Format("January 2, 2006")
+	}
+	return dateOverride
+}
+
+// resolveDateOverride reads a -date value for a show, printing and logging the
+// date it means and the format it was read in. Relative dates like "yesterday"
+// are resolved against today in the station timezone; with date_input_format
+// nothing else is accepted, otherwise any common format is, with a warning
+// when the value reads differently day-first. A value no format matches is
+// returned unchanged for a title to use as given.
+// AIDEV-NOTE: Relative and date_input_format dates are resolved once, as
+// MM/DD/YYYY like the no-override default, so a show without date_format
+// doesn't get "yesterday" in its title, every placeholder sees the same date
+// even if the run crosses midnight, and the month-first readers downstream
+// can't turn a day-first date around.
+func (sp *ShowProcessor) resolveDateOverride(showCfg *config.ShowConfig, dateOverride string) (string, error) {
+	if dateOverride == "" {
+		return "", nil
+	}
+	date, format, err := sp.parseDateInput(showCfg, dateOverride)
+	if err != nil {
+		if showCfg.DateInputFormat != "" {
+			return "", err
+		}
+		return dateOverride, nil
+	}
+
+	console.Printf("Date: %s = %s (%s)\n", dateOverride, date.Format("Monday 2006-01-02"), format)
+	sp.logger.Info("Resolved date override",
+		slog.String("date_override", dateOverride),
+		slog.String("format", format),
+		slog.String("date", date.Format("2006-01-02")),
+		slog.String("timezone", date.Location().String()))
+	if showCfg.DateInputFormat == "" && format != relativeDateInput {
+		if dayFirst, ambiguous := dateutil.AmbiguousDayMonth(dateOverride); ambiguous {
+			console.Printf("⚠️  Read day-first it would be %s; set date_input_format on the show to pick one\n", dayFirst.Format("Monday 2006-01-02"))
+			sp.logger.Warn("Ambiguous date override",
+				slog.String("date_override", dateOverride),
+				slog.String("date", date.Format("2006-01-02")),
+				slog.String("day_first", dayFirst.Format("2006-01-02")))
+		}
+		console.Printf("\n")
+		return dateOverride, nil
+	}
+	console.Printf("\n")
+	return date.Format("01/02/2006"), nil
+}
+
+// relativeDateInput is the format parseDateInput reports for relative dates
+const relativeDateInput = "relative date"
+
+// parseDateInput parses a -date value for a show: a relative date against the
+// station clock, else strictly in the show's date_input_format when it has one,
+// else in the first common format that matches. format names what matched.
+func (sp *ShowProcessor) parseDateInput(showCfg *config.ShowConfig, dateStr string) (date time.Time, format string, err error) {
+	if date, ok := dateutil.ParseRelativeDate(dateStr, sp.stationNow()); ok {
+		return date, relativeDateInput, nil
+	}
+	if showCfg.DateInputFormat != "" {
+		date, err := dateutil.ParseDateInFormat(dateStr, showCfg.DateInputFormat)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("%w (date_input_format), or a relative date: %s", err, dateutil.RelativeFormats)
+		}
+		return date, "date_input_format " + showCfg.DateInputFormat, nil
+	}
+	date, format, err = dateutil.ParseFlexibleDateFormat(dateStr)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w, or a relative date: %s", err, dateutil.RelativeFormats)
+	}
+	return date, format, nil
+}
+
+// parseFlexibleDate parses a -date value in any format dateutil.ParseDate
+// accepts, relative dates against the station clock
 func (sp *ShowProcessor) parseFlexibleDate(dateStr string) (time.Time, error) {
-	return dateutil.ParseFlexibleDate(dateStr)
+	return dateutil.ParseDate(dateStr, sp.stationNow())
+}
+
+// stationNow returns the current time in the station timezone, or the system
+// clock and timezone for a processor built without them
+func (sp *ShowProcessor) stationNow() time.Time {
+	if sp.now == nil || sp.location == nil {
+		return time.Now()
+	}
+	return sp.now().In(sp.location)
 }
 
 // printSingleResult displays results for single show processing
 func (sp *ShowProcessor) printSingleResult(result ProcessingResult) {
-	fmt.Printf("\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("\n")
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 	
 	if result.Error != nil {
-		fmt.Printf("❌ Failed: %s\n", result.ShowKey)
-		fmt.Printf("Error: %v\n", result.Error)
+		console.Printf("❌ Failed: %s\n", result.ShowKey)
+		console.Printf("Category: %s\n", result.FailureCategory)
+		console.Printf("Error: %v\n", result.Error)
+		for _, line := range partLines(result) {
+			console.Printf("  %s\n", line)
+		}
+	} else if result.Skipped {
+		console.Printf("⏭️  Skipped: %s\n", result.ShowKey)
+		console.Printf("Reason: %s\n", result.SkipReason)
+	} else if result.Queued {
+		console.Printf("📥 Queued offline: %s\n", result.ShowKey)
+		console.Printf("Show: %s\n", result.ShowName)
+		console.Printf("URL: %s\n", result.ShowURL)
+		console.Printf("Queued: %s\n", queuedSummary(result))
+		console.Printf("Publish it with -retry-failed once Mixcloud is reachable\n")
 	} else if result.Success {
-		fmt.Printf("✅ Success: %s\n", result.ShowKey)
-		fmt.Printf("Show: %s\n", result.ShowName)
-		fmt.Printf("URL: %s\n", result.ShowURL)
-		fmt.Printf("Tracks: %d/%d included (%.0f%%)\n", 
+		console.Printf("✅ Success: %s\n", result.ShowKey)
+		console.Printf("Show: %s\n", result.ShowName)
+		if result.URLSource != "" && result.URLSource != result.ShowName {
+			console.Printf("URL source: %s\n", result.URLSource)
+		}
+		if result.Sidecar != "" {
+			console.Printf("Sidecar: %s\n", sidecarSummary(result))
+		}
+		if len(result.Parts) > 0 {
+			console.Printf("Parts:\n")
+			for _, line := range partLines(result) {
+				console.Printf("  %s\n", line)
+			}
+		} else {
+			console.Printf("URL: %s\n", result.ShowURL)
+		}
+		if result.NewName != "" && !result.DryRun {
+			console.Printf("Renamed: %s\n", renameSummary(result))
+		}
+		console.Printf("Tracks: %d/%d included (%.0f%%)\n", 
 			result.FilteredTracks, result.ParsedTracks,
 			float64(result.FilteredTracks)/float64(result.ParsedTracks)*100)
-		fmt.Printf("Template: %s\n", result.Template)
-		fmt.Printf("Length: %d characters\n", result.FormattedLength)
+		if result.TrackWarnings > 0 {
+			console.Printf("Malformed CUE tracks skipped: %d\n", result.TrackWarnings)
+		}
+		if result.TrackGaps > 0 {
+			console.Printf("⚠️  Gaps between tracks: %d over max_track_gap_minutes (largest %s)\n", result.TrackGaps, result.LargestTrackGap)
+		}
+		if check := result.LengthCheck; check != nil && check.Verdict == LengthMismatch {
+			console.Printf("⚠️  Upload length: %s\n", check)
+		} else if check != nil && check.Verdict != "" {
+			console.Printf("Upload length: %s\n", check)
+		}
+		if check := result.Anomaly; check != nil && check.Verdict == AnomalyShrunk {
+			console.Printf("⚠️  Smaller than recent publishes: %s\n", check)
+		}
+		if result.Placeholder {
+			console.Printf("Published placeholder: no tracks remained after filtering\n")
+		}
+		console.Printf("Template: %s\n", result.Template)
+		if len(result.Parts) > 0 {
+			console.Printf("Length: %s (~%d rendered on Mixcloud)\n", partsLengthSummary(result), result.RenderedLength)
+		} else {
+			console.Printf("Length: %d characters (~%d rendered on Mixcloud)\n", result.FormattedLength, result.RenderedLength)
+		}
 	}
 	
-	fmt.Printf("Duration: %.1fs\n", result.Duration.Seconds())
+	console.Printf("Duration: %.1fs\n", result.Duration.Seconds())
+	if result.ReauthPause > 0 {
+		console.Printf("Paused for re-authentication: %.1fs\n", result.ReauthPause.Seconds())
+	}
 	
 	if result.DryRun {
-		fmt.Printf("\nDry run complete. Use --dry-run=false to apply changes.\n")
+		console.Printf("\nDry run complete. Use --dry-run=false to apply changes.\n")
 	}
 	
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 }
 
 // printBatchSummary displays summary for batch processing
 func (sp *ShowProcessor) printBatchSummary(result *BatchResult) {
-	fmt.Printf("\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	fmt.Printf("Batch Processing Summary\n")
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	fmt.Printf("Total Shows: %d\n", result.TotalShows)
-	fmt.Printf("Successful: %d\n", result.SuccessfulShows)
-	fmt.Printf("Failed: %d\n", result.FailedShows)
-	fmt.Printf("Skipped: %d\n", result.SkippedShows)
-	fmt.Printf("Duration: %.1fs\n", result.TotalDuration.Seconds())
+	console.Printf("\n")
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("Batch Processing Summary\n")
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("Total Shows: %d\n", result.TotalShows)
+	console.Printf("Successful: %d\n", result.SuccessfulShows)
+	console.Printf("Failed: %d\n", result.FailedShows)
+	console.Printf("Skipped: %d\n", result.SkippedShows)
+	if len(result.NotAttempted) > 0 {
+		console.Printf("Not attempted (-limit): %d (%s)\n", len(result.NotAttempted), strings.Join(result.NotAttempted, ", "))
+	}
+	if len(result.DeadlineSkipped) > 0 {
+		console.Printf("Not attempted (deadline exceeded): %d (%s)\n", len(result.DeadlineSkipped), strings.Join(result.DeadlineSkipped, ", "))
+	}
+	if len(result.ThresholdSkipped) > 0 {
+		console.Printf("Not attempted (failure threshold reached): %d (%s)\n", len(result.ThresholdSkipped), strings.Join(result.ThresholdSkipped, ", "))
+	}
+	if result.PlaceholderShows > 0 {
+		console.Printf("Published placeholder: %d\n", result.PlaceholderShows)
+	}
+	if result.QueuedShows > 0 {
+		console.Printf("Queued offline: %d (publish with -retry-failed)\n", result.QueuedShows)
+	}
+	if result.CarriedOverShows > 0 {
+		console.Printf("Carried over (-resume): %d (%s)\n", result.CarriedOverShows, strings.Join(result.carriedOverKeys(), ", "))
+	}
+	console.Printf("Duration: %.1fs\n", result.TotalDuration.Seconds())
+	if result.PacingDuration > 0 {
+		console.Printf("Time spent rate-pacing: %.1fs\n", result.PacingDuration.Seconds())
+	}
+	if result.ReauthDuration > 0 {
+		console.Printf("Paused for re-authentication: %.1fs\n", result.ReauthDuration.Seconds())
+	}
+	console.Printf("API quota remaining: %s\n", sp.apiQuotaRemaining())
+	
+	if result.GroupFailure != nil {
+		console.Printf("\n❌ %v\n", result.GroupFailure)
+	}
+	if result.FailureAbort != nil {
+		console.Printf("\n🛑 Run %s\n", result.FailureAbort)
+	}
+
+	if len(result.Duplicates) > 0 {
+		console.Printf("\n⚠️  Shows sharing a source:\n")
+		for _, duplicate := range result.Duplicates {
+			console.Printf("• %s\n", duplicate)
+		}
+	}
+
+	// Mismatches that failed their show are listed with the failures
+	var mismatched []string
+	for _, res := range result.Results {
+		if res.Error == nil && res.LengthCheck != nil && res.LengthCheck.Verdict == LengthMismatch {
+			mismatched = append(mismatched, fmt.Sprintf("%s: %s", res.ShowKey, res.LengthCheck))
+		}
+	}
+	if len(mismatched) > 0 {
+		console.Printf("\n⚠️  Upload length doesn't match the tracklist (wrong CUE file?):\n")
+		for _, line := range mismatched {
+			console.Printf("• %s\n", line)
+		}
+	}
+
+	// Like mismatches, shrunk shows held by anomaly_action = "fail" are failures
+	var shrunk []string
+	for _, res := range result.Results {
+		if res.Error == nil && res.Anomaly != nil && res.Anomaly.Verdict == AnomalyShrunk {
+			shrunk = append(shrunk, fmt.Sprintf("%s: %s", res.ShowKey, res.Anomaly))
+		}
+	}
+	if len(shrunk) > 0 {
+		console.Printf("\n⚠️  Much smaller than recent publishes (incomplete CUE file?):\n")
+		for _, line := range shrunk {
+			console.Printf("• %s\n", line)
+		}
+	}
 	
 	if result.FailedShows > 0 {
-		fmt.Printf("\nFailed Shows:\n")
-		for _, res := range result.Results {
-			if res.Error != nil {
-				fmt.Printf("• %s: %v\n", res.ShowKey, res.Error)
+		console.Printf("\nFailed Shows:\n")
+		for _, category := range failureCategoryOrder {
+			var failed []ProcessingResult
+			for _, res := range result.Results {
+				if res.Error != nil && res.FailureCategory == category {
+					failed = append(failed, res)
+				}
+			}
+			if len(failed) == 0 {
+				continue
+			}
+			console.Printf("\n[%s] %s\n", category, failureRemediation(category))
+			for _, res := range failed {
+				console.Printf("• %s: %v\n", res.ShowKey, res.Error)
 			}
 		}
 	}
 	
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 }
 
-// verifyShowWithRetry attempts to verify a show exists with exponential backoff retry
-func (sp *ShowProcessor) verifyShowWithRetry(showURL string, maxRetries int) (interface{}, error) {
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		show, err := sp.mixcloud.GetShow(showURL)
-		if err == nil {
-			return show, nil
+// apiQuotaRemaining describes the quota the last API responses reported, or
+// "unknown" when Mixcloud didn't send rate-limit headers
+func (sp *ShowProcessor) apiQuotaRemaining() string {
+	reporter, ok := sp.mixcloud.(statsReporter)
+	if !ok {
+		return "unknown"
+	}
+	return reporter.Stats().RemainingString()
+}
+
+// add records a show's result and updates the outcome counters
+func (br *BatchResult) add(result ProcessingResult) {
+	br.Results = append(br.Results, result)
+	if !result.CarriedOver {
+		br.ProcessedShows++
+	}
+	br.ReauthDuration += result.ReauthPause
+
+	switch {
+	case result.Error != nil:
+		br.FailedShows++
+	case result.Queued:
+		br.QueuedShows++
+	case result.Success:
+		br.SuccessfulShows++
+		if result.Placeholder {
+			br.PlaceholderShows++
+		}
+		if result.CarriedOver {
+			br.CarriedOverShows++
 		}
+	default:
+		br.SkippedShows++
+	}
+}
 
-		// Check if this is a retryable error
-		if !sp.isRetryableError(err) {
-			return nil, err
+// carriedOverKeys returns the keys of the shows carried over from an interrupted run
+func (br *BatchResult) carriedOverKeys() []string {
+	var keys []string
+	for _, result := range br.Results {
+		if result.CarriedOver {
+			keys = append(keys, result.ShowKey)
 		}
+	}
+	return keys
+}
 
-		if attempt < maxRetries {
-			backoffDuration := time.Duration(attempt*attempt) * time.Second
-			sp.logger.Warn("Show verification failed, retrying",
-				slog.String("url", showURL),
-				slog.Int("attempt", attempt),
-				slog.Int("max_retries", maxRetries),
-				slog.Duration("backoff", backoffDuration),
-				slog.String("error", err.Error()))
-			time.Sleep(backoffDuration)
+// FailedShowKeys returns the keys of the shows that failed, in processing order
+func (br *BatchResult) FailedShowKeys() []string {
+	var keys []string
+	for _, result := range br.Results {
+		if result.Error != nil {
+			keys = append(keys, result.ShowKey)
 		}
 	}
+	return keys
+}
 
-	return nil, fmt.Errorf("max retries (%d) exceeded", maxRetries)
+// applyLimit keeps the first limit shows and records the rest as not attempted.
+// A limit <= 0 keeps every show.
+func (br *BatchResult) applyLimit(showKeys []string, limit int) []string {
+	if limit <= 0 || limit >= len(showKeys) {
+		return showKeys
+	}
+	br.NotAttempted = append([]string(nil), showKeys[limit:]...)
+	br.NotAttemptedShows = len(br.NotAttempted)
+	return showKeys[:limit]
 }
 
-// updateShowWithRetry attempts to update a show description with exponential backoff retry
-func (sp *ShowProcessor) updateShowWithRetry(showURL, description string, maxRetries int) error {
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := sp.mixcloud.UpdateShowDescription(showURL, description)
-		if err == nil {
-			return nil
+// failureCategories counts failed shows per category
+func (br *BatchResult) failureCategories() map[FailureCategory]int {
+	categories := make(map[FailureCategory]int)
+	for _, res := range br.Results {
+		if res.Error != nil {
+			categories[res.FailureCategory]++
 		}
+	}
+	return categories
+}
 
-		// Check if this is a retryable error
-		if !sp.isRetryableError(err) {
-			return err
-		}
+// failureRemediation returns a one-line hint for fixing failures in a category
+func failureRemediation(category FailureCategory) string {
+	switch category {
+	case FailureAuth:
+		return "Re-authenticate with Mixcloud"
+	case FailureSource:
+		return "Check show config, CUE files and templates"
+	case FailureNotFound:
+		return "Check the show exists on Mixcloud and url_pattern matches its URL"
+	case FailureRateLimit:
+		return "Mixcloud rate limit - retry later"
+	case FailureServer:
+		return "Mixcloud server error - retry later"
+	case FailureNetwork:
+		return "Check network connectivity"
+	default:
+		return "Unrecognised error"
+	}
+}
+
+// verifyShowWithRetry fetches a show to verify it exists, retrying transient
+// failures with the retry policy. Retries stop once ctx is done.
+func (sp *ShowProcessor) verifyShowWithRetry(ctx context.Context, showURL string) (*mixcloud.Show, error) {
+	var show *mixcloud.Show
+	err := sp.mixcloudRetry("Show verification failed, retrying", showURL).Do(ctx, func() error {
+		var err error
+		show, err = sp.mixcloud.GetShowContext(ctx, showURL)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return show, nil
+}
+
+// updateShowWithRetry sends a show update, retrying transient failures with the
+// retry policy. Retries stop once ctx is done.
+func (sp *ShowProcessor) updateShowWithRetry(ctx context.Context, showURL string, fields map[string]string) error {
+	_, err := sp.editShowWithRetry(ctx, showURL, fields)
+	return err
+}
 
-		if attempt < maxRetries {
-			backoffDuration := time.Duration(attempt*attempt) * time.Second
-			sp.logger.Warn("Show update failed, retrying",
+// editShowWithRetry is updateShowWithRetry that also returns the show URL the
+// edit response reported, or "" when the client doesn't report one
+func (sp *ShowProcessor) editShowWithRetry(ctx context.Context, showURL string, fields map[string]string) (string, error) {
+	editor, reportsURL := sp.mixcloud.(showEditor)
+	var editedURL string
+	var lastErr error
+	err := sp.mixcloudRetry("Show update failed, retrying", showURL).Do(ctx, func() error {
+		if lastErr != nil && sp.updateAlreadyApplied(ctx, showURL, fields, lastErr) {
+			return nil
+		}
+		if waited := sp.pacer.Wait(); waited > 0 {
+			sp.logger.Debug("Rate-pacing show update",
 				slog.String("url", showURL),
-				slog.Int("attempt", attempt),
-				slog.Int("max_retries", maxRetries),
-				slog.Duration("backoff", backoffDuration),
-				slog.String("error", err.Error()))
-			time.Sleep(backoffDuration)
+				slog.Duration("waited", waited))
 		}
-	}
+		if reportsURL {
+			editedURL, lastErr = editor.EditShowContext(ctx, showURL, fields)
+		} else {
+			lastErr = sp.mixcloud.UpdateShowContext(ctx, showURL, fields)
+		}
+		return lastErr
+	})
+	return editedURL, err
+}
 
-	return fmt.Errorf("max retries (%d) exceeded", maxRetries)
+// mixcloudRetry returns the retry policy for one Mixcloud call, logging each retry
+// with message
+func (sp *ShowProcessor) mixcloudRetry(message, showURL string) retry.Policy {
+	policy := sp.retryPolicy
+	policy.Retryable = sp.isRetryableError
+	policy.OnRetry = func(attempt int, delay time.Duration, err error) {
+		sp.logger.Warn(message,
+			slog.String("url", showURL),
+			slog.Int("attempt", attempt),
+			slog.Int("max_attempts", policy.Attempts()),
+			slog.Duration("backoff", delay),
+			slog.String("error", err.Error()))
+	}
+	return policy
 }
 
 // isRetryableError determines if an error is worth retrying
@@ -589,6 +1895,10 @@ func (sp *ShowProcessor) isRetryableError(err error) bool {
 		return false
 	}
 
+	if errors.Is(err, mixcloud.ErrTimeout) {
+		return true
+	}
+
 	errStr := strings.ToLower(err.Error())
 	
 	// Network-related errors that might be transient
@@ -1,36 +1,44 @@
 package processor
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+	"github.com/nowwaveradio/mixcloud-updater/internal/state"
 )
 
 func TestNewShowProcessor(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name:             "Test Station",
 			MixcloudUsername: "testuser",
 		},
 		OAuth: struct {
-			ClientID     string `toml:"client_id"`
-			ClientSecret string `toml:"client_secret"`
-			AccessToken  string `toml:"access_token"`
-			RefreshToken string `toml:"refresh_token"`
+			ClientID        string `toml:"client_id"`
+			ClientSecret    string `toml:"client_secret"`
+			AccessToken     string `toml:"access_token"`
+			RefreshToken    string `toml:"refresh_token"`
+			CredentialStore string `toml:"credential_store"`
+			ExchangeTimeoutSeconds int `toml:"exchange_timeout_seconds"`
 		}{
 			ClientID:     "test-client-id",
 			ClientSecret: "test-client-secret",
 			AccessToken:  "test-access-token",
 		},
-		Processing: struct {
-			CueFileDirectory string `toml:"cue_file_directory"`
-			AutoProcess      bool   `toml:"auto_process"`
-			BatchSize        int    `toml:"batch_size"`
-		}{
+		Processing: config.ProcessingConfig{
 			CueFileDirectory: ".",
 			AutoProcess:      false,
 			BatchSize:        3,
@@ -96,17 +104,21 @@ func TestNewShowProcessorNilConfig(t *testing.T) {
 func TestNewShowProcessorInvalidShows(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name:             "Test Station",
 			MixcloudUsername: "testuser",
 		},
 		OAuth: struct {
-			ClientID     string `toml:"client_id"`
-			ClientSecret string `toml:"client_secret"`
-			AccessToken  string `toml:"access_token"`
-			RefreshToken string `toml:"refresh_token"`
+			ClientID        string `toml:"client_id"`
+			ClientSecret    string `toml:"client_secret"`
+			AccessToken     string `toml:"access_token"`
+			RefreshToken    string `toml:"refresh_token"`
+			CredentialStore string `toml:"credential_store"`
+			ExchangeTimeoutSeconds int `toml:"exchange_timeout_seconds"`
 		}{
 			ClientID:     "test-client-id",
 			ClientSecret: "test-client-secret",
@@ -130,8 +142,10 @@ func TestNewShowProcessorInvalidShows(t *testing.T) {
 func TestGenerateShowNameLegacy(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name: "Test Station",
 		},
@@ -213,27 +227,27 @@ func TestProcessShowValidation(t *testing.T) {
 
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name:             "Test Station",
 			MixcloudUsername: "testuser",
 		},
 		OAuth: struct {
-			ClientID     string `toml:"client_id"`
-			ClientSecret string `toml:"client_secret"`
-			AccessToken  string `toml:"access_token"`
-			RefreshToken string `toml:"refresh_token"`
+			ClientID        string `toml:"client_id"`
+			ClientSecret    string `toml:"client_secret"`
+			AccessToken     string `toml:"access_token"`
+			RefreshToken    string `toml:"refresh_token"`
+			CredentialStore string `toml:"credential_store"`
+			ExchangeTimeoutSeconds int `toml:"exchange_timeout_seconds"`
 		}{
 			ClientID:     "test-client-id",
 			ClientSecret: "test-client-secret",
 			AccessToken:  "test-access-token",
 		},
-		Processing: struct {
-			CueFileDirectory string `toml:"cue_file_directory"`
-			AutoProcess      bool   `toml:"auto_process"`
-			BatchSize        int    `toml:"batch_size"`
-		}{
+		Processing: config.ProcessingConfig{
 			CueFileDirectory: tmpDir,
 		},
 		Shows: map[string]config.ShowConfig{
@@ -263,27 +277,35 @@ func TestProcessShowValidation(t *testing.T) {
 		t.Error("ProcessShow() should return error for non-existent show")
 	}
 
-	// Test disabled show (should not error, but should skip)
+	// Test disabled show (nothing processed, but not a failure)
 	err = processor.ProcessShow("disabled-show", "", "", true)
-	if err != nil {
-		t.Errorf("ProcessShow() unexpected error for disabled show = %v", err)
+	var disabled *ShowDisabledError
+	if !errors.As(err, &disabled) || disabled.ShowKey != "disabled-show" {
+		t.Errorf("ProcessShow() error for disabled show = %v, want ShowDisabledError", err)
+	}
+	if !IsEmptyRun(err) {
+		t.Errorf("IsEmptyRun(%v) = false, want true", err)
 	}
 }
 
 func TestProcessAllShowsEmptyConfig(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name:             "Test Station",
 			MixcloudUsername: "testuser",
 		},
 		OAuth: struct {
-			ClientID     string `toml:"client_id"`
-			ClientSecret string `toml:"client_secret"`
-			AccessToken  string `toml:"access_token"`
-			RefreshToken string `toml:"refresh_token"`
+			ClientID        string `toml:"client_id"`
+			ClientSecret    string `toml:"client_secret"`
+			AccessToken     string `toml:"access_token"`
+			RefreshToken    string `toml:"refresh_token"`
+			CredentialStore string `toml:"credential_store"`
+			ExchangeTimeoutSeconds int `toml:"exchange_timeout_seconds"`
 		}{
 			ClientID:     "test-client-id",
 			ClientSecret: "test-client-secret",
@@ -297,27 +319,31 @@ func TestProcessAllShowsEmptyConfig(t *testing.T) {
 		t.Fatalf("NewShowProcessor() error = %v", err)
 	}
 
-	// Should not error when no shows are configured
+	// No shows configured is an empty run, not a failure
 	err = processor.ProcessAllShows(true)
-	if err != nil {
-		t.Errorf("ProcessAllShows() unexpected error = %v", err)
+	if !errors.Is(err, ErrNoEnabledShows) || !IsEmptyRun(err) {
+		t.Errorf("ProcessAllShows() error = %v, want ErrNoEnabledShows", err)
 	}
 }
 
 func TestProcessAllShowsWithDisabledShows(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name:             "Test Station",
 			MixcloudUsername: "testuser",
 		},
 		OAuth: struct {
-			ClientID     string `toml:"client_id"`
-			ClientSecret string `toml:"client_secret"`
-			AccessToken  string `toml:"access_token"`
-			RefreshToken string `toml:"refresh_token"`
+			ClientID        string `toml:"client_id"`
+			ClientSecret    string `toml:"client_secret"`
+			AccessToken     string `toml:"access_token"`
+			RefreshToken    string `toml:"refresh_token"`
+			CredentialStore string `toml:"credential_store"`
+			ExchangeTimeoutSeconds int `toml:"exchange_timeout_seconds"`
 		}{
 			ClientID:     "test-client-id",
 			ClientSecret: "test-client-secret",
@@ -344,10 +370,10 @@ func TestProcessAllShowsWithDisabledShows(t *testing.T) {
 		t.Fatalf("NewShowProcessor() error = %v", err)
 	}
 
-	// Should not error when all shows are disabled
+	// All shows disabled is an empty run, not a failure
 	err = processor.ProcessAllShows(true)
-	if err != nil {
-		t.Errorf("ProcessAllShows() unexpected error = %v", err)
+	if !errors.Is(err, ErrNoEnabledShows) || !IsEmptyRun(err) {
+		t.Errorf("ProcessAllShows() error = %v, want ErrNoEnabledShows", err)
 	}
 }
 
@@ -368,4 +394,388 @@ func containsInMiddle(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+func TestRecordPublishWritesState(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault"
+enabled = true
+`)
+	statePath := state.DefaultPath(sp.configPath)
+
+	// Dry runs never touch the state file
+	showCfg := sp.config.Shows["vault"]
+	if result := sp.processingleShow("vault", &showCfg, "", "", true); result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Fatalf("dry run should not create state file, stat err = %v", err)
+	}
+
+	sp.recordPublish(&ProcessingResult{
+		ShowKey:        "vault",
+		ShowURL:        "https://www.mixcloud.com/testuser/the-vault/",
+		FilteredTracks: 3,
+		Description:    "tracklist",
+	})
+
+	saved, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("state.Load() error = %v", err)
+	}
+	showState, ok := saved.Show("vault")
+	if !ok {
+		t.Fatal("state file missing show 'vault'")
+	}
+	if showState.TrackCount != 3 || showState.ShowURL != "https://www.mixcloud.com/testuser/the-vault/" {
+		t.Errorf("unexpected show state: %+v", showState)
+	}
+	if showState.DescriptionHash != state.HashDescription("tracklist") {
+		t.Errorf("DescriptionHash = %q, want hash of description", showState.DescriptionHash)
+	}
+}
+
+func TestRecordPublishAppendsHistory(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault - {date}"
+enabled = true
+`)
+	sp.mixcloud = newFakeMixcloud()
+	sp.config.Processing.HistoryEntries = 2
+	historyPath := state.DefaultHistoryPath(sp.configPath)
+
+	// A state file from before the history existed is migrated on the first publish
+	sp.state.RecordPublish("vault", state.ShowState{
+		LastPublished: time.Date(2025, 6, 7, 22, 0, 0, 0, time.UTC),
+		ShowURL:       "https://www.mixcloud.com/testuser/the-vault-672025/",
+		TrackCount:    9,
+	})
+
+	showCfg := sp.config.Shows["vault"]
+	for _, date := range []string{"2025-06-14", "06/21/2025"} {
+		if result := sp.processingleShow("vault", &showCfg, "", date, false); !result.Success {
+			t.Fatalf("processingleShow(%s) error = %v", date, result.Error)
+		}
+	}
+
+	history, err := state.LoadHistory(historyPath, nil)
+	if err != nil {
+		t.Fatalf("state.LoadHistory() error = %v", err)
+	}
+	entries := history.Entries("vault", 0)
+	if len(entries) != 2 {
+		t.Fatalf("history has %d entries, want 2 (migrated entry pruned by history_entries)", len(entries))
+	}
+	latest := entries[0]
+	if latest.ShowDate != "2025-06-21" || entries[1].ShowDate != "2025-06-14" {
+		t.Errorf("show dates = %q, %q, want 2025-06-21, 2025-06-14", latest.ShowDate, entries[1].ShowDate)
+	}
+	if latest.Migrated || latest.CueFile == "" || latest.Template == "" || latest.ShowURL == "" {
+		t.Errorf("latest entry missing publish details: %+v", latest)
+	}
+	if latest.ParsedTracks == 0 || latest.Tracks != latest.ParsedTracks-latest.ExcludedTracks {
+		t.Errorf("track counts = %d parsed, %d published, %d excluded", latest.ParsedTracks, latest.Tracks, latest.ExcludedTracks)
+	}
+	if latest.DescriptionLength == 0 || latest.Truncated {
+		t.Errorf("DescriptionLength, Truncated = %d, %v, want a short untruncated description", latest.DescriptionLength, latest.Truncated)
+	}
+}
+
+func TestBatchResultApplyLimit(t *testing.T) {
+	shows := []string{"a", "b", "c"}
+	tests := []struct {
+		name             string
+		limit            int
+		wantKept         []string
+		wantNotAttempted []string
+	}{
+		{"no limit", 0, []string{"a", "b", "c"}, nil},
+		{"negative means no limit", -1, []string{"a", "b", "c"}, nil},
+		{"limit above show count", 5, []string{"a", "b", "c"}, nil},
+		{"limit equals show count", 3, []string{"a", "b", "c"}, nil},
+		{"limit below show count", 1, []string{"a"}, []string{"b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := &BatchResult{TotalShows: len(shows)}
+			kept := br.applyLimit(shows, tt.limit)
+			if !reflect.DeepEqual(kept, tt.wantKept) {
+				t.Errorf("kept = %v, want %v", kept, tt.wantKept)
+			}
+			if !reflect.DeepEqual(br.NotAttempted, tt.wantNotAttempted) {
+				t.Errorf("NotAttempted = %v, want %v", br.NotAttempted, tt.wantNotAttempted)
+			}
+			if br.NotAttemptedShows != len(tt.wantNotAttempted) {
+				t.Errorf("NotAttemptedShows = %d, want %d", br.NotAttemptedShows, len(tt.wantNotAttempted))
+			}
+		})
+	}
+}
+
+func TestProcessAllShowsLimit(t *testing.T) {
+	sp, fake := newGroupTestProcessor(t)
+	sp.SetOptions(Options{Limit: 2})
+
+	if err := sp.ProcessAllShows(false); err != nil {
+		t.Fatalf("ProcessAllShows() error = %v", err)
+	}
+
+	// Only the two highest-priority shows are updated
+	if len(fake.updates) != 2 {
+		t.Fatalf("got %d updates, want 2: %v", len(fake.updates), fake.updates)
+	}
+	for i, day := range []string{"Friday", "Saturday"} {
+		if !strings.HasPrefix(fake.updates[i], festURL(day)+"=") {
+			t.Errorf("update %d = %q, want show %s", i, fake.updates[i], festURL(day))
+		}
+	}
+}
+
+func TestProcessAllShowsRunDeadline(t *testing.T) {
+	sp, fake := newGroupTestProcessor(t)
+	sp.runDeadline = 20 * time.Millisecond
+	fake.updateDelay = 50 * time.Millisecond // First show outlasts the deadline
+
+	err := sp.ProcessAllShows(false)
+	if !errors.Is(err, ErrRunDeadlineExceeded) {
+		t.Fatalf("ProcessAllShows() error = %v, want ErrRunDeadlineExceeded", err)
+	}
+	if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], festURL("Friday")+"=") {
+		t.Fatalf("updates = %v, want only the Friday show", fake.updates)
+	}
+}
+
+func TestShowCacheFollowsNoCacheOption(t *testing.T) {
+	sp, fake := newGroupTestProcessor(t)
+
+	sp.SetOptions(Options{})
+	if fake.cache == nil {
+		t.Fatal("GetShow cache should be the state file by default")
+	}
+
+	sp.SetOptions(Options{NoCache: true})
+	if fake.cache != nil {
+		t.Error("NoCache should detach the GetShow cache")
+	}
+}
+
+func TestDescriptionSanitizedBeforeMeasuring(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault"
+enabled = true
+`)
+	// A vertical tab in a title and CRLF line endings, as in some logger exports
+	cue := strings.ReplaceAll(strings.Replace(testCueContent, "When I Fall", "When I\x0b Fall", 1), "\n", "\r\n")
+	if err := os.WriteFile(filepath.Join(sp.config.Processing.CueFileDirectory, "TEST.cue"), []byte(cue), 0644); err != nil {
+		t.Fatalf("writing CUE file: %v", err)
+	}
+
+	showCfg := sp.config.Shows["vault"]
+	result := sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	if strings.ContainsAny(result.Description, "\x0b\r") {
+		t.Errorf("description still holds control characters: %q", result.Description)
+	}
+	if !strings.Contains(result.Description, "When I Fall") {
+		t.Errorf("description = %q, want the repaired title", result.Description)
+	}
+	if result.Sanitized.ControlChars != 1 {
+		t.Errorf("Sanitized = %+v, want 1 control character", result.Sanitized)
+	}
+	if result.FormattedLength != len(result.Description) {
+		t.Errorf("FormattedLength = %d, want the sanitized length %d", result.FormattedLength, len(result.Description))
+	}
+}
+
+func TestExcludedCountReachesTemplate(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault"
+template = "licensed"
+enabled = true
+
+[templates.config.licensed]
+track = "{{.Artist}} - {{.Title}}\n"
+footer = "{{if gt .ExcludedCount 0}}{{.ExcludedCount}} non-music items omitted.{{end}}"
+`)
+	showCfg := sp.config.Shows["vault"]
+
+	result := sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	if want := "Laura Dre - When I Fall\nPure Obsessions - Dive Deep Into the Night\nAirline Food - Conditional Love\n"; result.Description != want {
+		t.Errorf("description = %q, want %q without an omission note", result.Description, want)
+	}
+
+	// The default station patterns exclude station IDs
+	cue := strings.Replace(testCueContent, `PERFORMER "Laura Dre"`, `PERFORMER "Station ID"`, 1)
+	if err := os.WriteFile(filepath.Join(sp.config.Processing.CueFileDirectory, "TEST.cue"), []byte(cue), 0644); err != nil {
+		t.Fatalf("writing CUE file: %v", err)
+	}
+	result = sp.processingleShow("vault", &showCfg, "", "", true)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	if !strings.HasSuffix(result.Description, "1 non-music items omitted.") {
+		t.Errorf("description = %q, want the omission note", result.Description)
+	}
+}
+
+func TestIncludeProvenance(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.traced]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Traced Show"
+include_provenance = true
+enabled = true
+`)
+	sp.now = func() time.Time { return time.Date(2025, 6, 28, 22, 14, 0, 0, sp.location) }
+
+	showCfg := sp.config.Shows["traced"]
+	result := sp.processingleShow("traced", &showCfg, "", "", true)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	want := "\nGenerated 2025-06-28 22:14 from TEST.cue by mixcloud-updater v" + constants.Version
+	if !strings.HasSuffix(result.Description, want) {
+		t.Errorf("description doesn't end with %q:\n%s", want, result.Description)
+	}
+}
+
+// quotaMixcloud is a fakeMixcloud that reports an API quota
+type quotaMixcloud struct {
+	*fakeMixcloud
+	stats mixcloud.Stats
+}
+
+func (q *quotaMixcloud) Stats() mixcloud.Stats {
+	return q.stats
+}
+
+func TestAPIQuotaRemaining(t *testing.T) {
+	sp, fake := newGroupTestProcessor(t)
+	if got := sp.apiQuotaRemaining(); got != "unknown" {
+		t.Errorf("without Stats: apiQuotaRemaining() = %q, want unknown", got)
+	}
+
+	sp.mixcloud = &quotaMixcloud{fakeMixcloud: fake}
+	if got := sp.apiQuotaRemaining(); got != "unknown" {
+		t.Errorf("no headers seen: apiQuotaRemaining() = %q, want unknown", got)
+	}
+
+	sp.mixcloud = &quotaMixcloud{fakeMixcloud: fake, stats: mixcloud.Stats{Remaining: 37, Limit: 60, QuotaKnown: true}}
+	if got := sp.apiQuotaRemaining(); got != "37 of 60" {
+		t.Errorf("apiQuotaRemaining() = %q, want \"37 of 60\"", got)
+	}
+}
+
+func TestShowDescriptionMaxLength(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.short]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Short"
+description_max_length = 80
+enabled = true
+
+[shows.full]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Full"
+enabled = true
+`)
+
+	tests := []struct {
+		showKey   string
+		maxLength int
+		truncated bool
+	}{
+		{"short", 80, true},
+		{"full", constants.MixcloudDescriptionLimit, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.showKey, func(t *testing.T) {
+			showCfg := sp.config.Shows[tt.showKey]
+			result := sp.processingleShow(tt.showKey, &showCfg, "", "", true)
+			if result.Error != nil {
+				t.Fatalf("processingleShow() error = %v", result.Error)
+			}
+			if len(result.Description) > tt.maxLength {
+				t.Errorf("description length %d exceeds %d", len(result.Description), tt.maxLength)
+			}
+			if truncated := strings.Contains(result.Description, "... and more"); truncated != tt.truncated {
+				t.Errorf("truncated = %v, want %v:\n%s", truncated, tt.truncated, result.Description)
+			}
+		})
+	}
+
+	if err := sp.checkDescription("short", strings.Repeat("x", 81), 80); !errors.Is(err, mixcloud.ErrDescriptionTooLong) {
+		t.Errorf("checkDescription() over the show limit error = %v, want ErrDescriptionTooLong", err)
+	}
+}
+
+func TestNewShowProcessorMinTrackSpace(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Station.Name = "Test Station"
+	cfg.Station.MixcloudUsername = "testuser"
+	cfg.OAuth.ClientID = "test-client-id"
+	cfg.OAuth.ClientSecret = "test-client-secret"
+	cfg.OAuth.AccessToken = "test-access-token"
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"crowded": {
+			Header:        "{{repeat \"h\" 300}}\n",
+			Track:         "{{.Title}}\n",
+			MinTrackSpace: 200,
+		},
+	}
+	cfg.Shows = map[string]config.ShowConfig{
+		"legacy": {
+			CueFileMapping:       "TEST.cue",
+			ShowNamePattern:      "Legacy",
+			TemplateName:         "crowded",
+			DescriptionMaxLength: 500,
+			Enabled:              true,
+		},
+	}
+
+	_, err := NewShowProcessor(cfg, filepath.Join(t.TempDir(), "config.toml"))
+	if err == nil || !strings.Contains(err.Error(), "show 'legacy': template crowded leaves 149 characters") {
+		t.Fatalf("NewShowProcessor() error = %v, want the min_track_space failure", err)
+	}
+
+	legacy := cfg.Shows["legacy"]
+	legacy.DescriptionMaxLength = 0
+	cfg.Shows["legacy"] = legacy
+	if _, err := NewShowProcessor(cfg, filepath.Join(t.TempDir(), "config.toml")); err != nil {
+		t.Errorf("NewShowProcessor() at Mixcloud's limit error = %v", err)
+	}
+}
+
+func TestTemplateOverrideKeepsCompactOptions(t *testing.T) {
+	sp := newTestProcessor(t, `
+[shows.vault]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "The Vault"
+compact_separator = " | "
+compact_max_length = 50
+enabled = true
+`)
+
+	showCfg := sp.config.Shows["vault"]
+	result := sp.processingleShow("vault", &showCfg, "compact", "", true)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() unexpected error = %v", result.Error)
+	}
+	expected := "Laura Dre – When I Fall | +2 more"
+	if result.Description != expected {
+		t.Errorf("Description = %q, want %q", result.Description, expected)
+	}
+}
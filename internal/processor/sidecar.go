@@ -0,0 +1,178 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+// AIDEV-NOTE: Sidecars let producers who can't edit the central config change a
+// single episode, e.g. a special with a guest host, by dropping a file next to
+// its CUE file. Only the keys of Sidecar are accepted; anything else, or a file
+// that doesn't parse, fails the show rather than publishing without the
+// override. Command-line -template and -date still win over the sidecar.
+
+// overrideFileName is the sidecar read for every CUE file of its directory
+// that has no <cue file>.toml of its own
+const overrideFileName = "override.toml"
+
+// sidecarDateLayout is the only date format sidecars accept
+const sidecarDateLayout = "2006-01-02"
+
+// Sidecar holds the per-episode overrides of a sidecar file
+type Sidecar struct {
+	Path            string            `toml:"-"`
+	ShowName        string            `toml:"show_name"`        // Replaces show_name_pattern, same placeholders
+	Template        string            `toml:"template"`         // Template name, like the show's template
+	TemplateVars    map[string]string `toml:"template_vars"`    // Added to the show's template_vars
+	Date            string            `toml:"date"`             // YYYY-MM-DD, like -date
+	ExcludedArtists []string          `toml:"excluded_artists"` // Excluded on top of filtering.excluded_artists, exact matches
+}
+
+// sidecarPath returns the sidecar of cueFile: <cue file>.toml, e.g.
+// MYR40628.cue.toml, else override.toml in the same directory. It returns ""
+// when there is neither.
+func sidecarPath(cueFile string) (string, error) {
+	candidates := []string{cueFile + ".toml", filepath.Join(filepath.Dir(cueFile), overrideFileName)}
+	for _, path := range candidates {
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("checking sidecar %s: %w", path, err)
+		}
+	}
+	return "", nil
+}
+
+// loadSidecar parses the sidecar at path, rejecting unknown keys, templates the
+// config doesn't define and dates that aren't YYYY-MM-DD
+func (sp *ShowProcessor) loadSidecar(path string) (*Sidecar, error) {
+	sidecar := &Sidecar{Path: path}
+	meta, err := toml.DecodeFile(path, sidecar)
+	if err != nil {
+		return nil, fmt.Errorf("parsing sidecar %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return nil, fmt.Errorf("sidecar %s: unknown keys %s (allowed: show_name, template, template_vars, date, excluded_artists)",
+			path, strings.Join(keys, ", "))
+	}
+
+	if sidecar.Template != "" {
+		if _, ok := sp.config.Templates.Config[sidecar.Template]; !ok && !template.IsBuiltinTemplate(sidecar.Template) {
+			return nil, fmt.Errorf("sidecar %s: template %s not found (see -list-templates)", path, sidecar.Template)
+		}
+	}
+	if sidecar.Date != "" {
+		if _, err := time.Parse(sidecarDateLayout, sidecar.Date); err != nil {
+			return nil, fmt.Errorf("sidecar %s: date %q is not YYYY-MM-DD", path, sidecar.Date)
+		}
+	}
+	return sidecar, nil
+}
+
+// applySidecar applies the sidecar next to the result's CUE file, if any, and
+// returns the show config, date override and filter the show is processed with.
+// Without a sidecar they are those passed in and sp.filter.
+func (sp *ShowProcessor) applySidecar(result *ProcessingResult, showCfg *config.ShowConfig, templateOverride, dateOverride string) (*config.ShowConfig, string, *filter.Filter, error) {
+	path, err := sidecarPath(result.CueFile)
+	if err == nil && path == "" {
+		return showCfg, dateOverride, sp.filter, nil
+	}
+	var sidecar *Sidecar
+	if err == nil {
+		sidecar, err = sp.loadSidecar(path)
+	}
+	if err != nil {
+		sp.logger.Error("Sidecar overrides not applied",
+			slog.String("show_key", result.ShowKey),
+			slog.String("error", err.Error()))
+		return nil, "", nil, err
+	}
+
+	overridden := *showCfg
+	applied, ignored := sidecar.apply(&overridden, &dateOverride, templateOverride)
+	trackFilter := sp.filter
+	if len(sidecar.ExcludedArtists) > 0 {
+		trackFilter = sp.filter.WithExcludedArtists(sidecar.ExcludedArtists)
+	}
+
+	result.Sidecar = path
+	result.SidecarOverrides = applied
+	sp.logger.Info("Applied sidecar overrides",
+		slog.String("show_key", result.ShowKey),
+		slog.String("file", path),
+		slog.String("overrides", strings.Join(applied, ", ")))
+	if len(ignored) > 0 {
+		sp.logger.Info("Sidecar overrides replaced by command-line options",
+			slog.String("show_key", result.ShowKey),
+			slog.String("file", path),
+			slog.String("ignored", strings.Join(ignored, ", ")))
+	}
+	return &overridden, dateOverride, trackFilter, nil
+}
+
+// apply sets the sidecar's overrides on showCfg, a copy of the show's config,
+// and on dateOverride. It returns the keys applied and those left out because
+// -template or -date was given.
+func (s *Sidecar) apply(showCfg *config.ShowConfig, dateOverride *string, templateOverride string) (applied, ignored []string) {
+	if s.ShowName != "" {
+		showCfg.ShowNamePattern = s.ShowName
+		applied = append(applied, "show_name")
+	}
+	if s.Template != "" && templateOverride != "" {
+		ignored = append(ignored, "template")
+	} else if s.Template != "" {
+		// A named template only takes effect without the show's custom_template
+		showCfg.TemplateName = s.Template
+		showCfg.CustomTemplate = ""
+		applied = append(applied, "template")
+	}
+	if len(s.TemplateVars) > 0 {
+		vars := make(map[string]string, len(showCfg.TemplateVars)+len(s.TemplateVars))
+		for name, value := range showCfg.TemplateVars {
+			vars[name] = value
+		}
+		for name, value := range s.TemplateVars {
+			vars[name] = value
+		}
+		showCfg.TemplateVars = vars
+		applied = append(applied, "template_vars")
+	}
+	if s.Date != "" && *dateOverride != "" {
+		ignored = append(ignored, "date")
+	} else if s.Date != "" {
+		// Passed on as MM/DD/YYYY, like a resolved relative -date
+		date, _ := time.Parse(sidecarDateLayout, s.Date)
+		*dateOverride = date.Format("01/02/2006")
+		applied = append(applied, "date")
+	}
+	if len(s.ExcludedArtists) > 0 {
+		applied = append(applied, "excluded_artists")
+	}
+	return applied, ignored
+}
+
+// sidecarSummary describes the sidecar applied to a show, e.g.
+// "/cue/MYR40628.cue.toml (show_name, template)"
+func sidecarSummary(result ProcessingResult) string {
+	if len(result.SidecarOverrides) == 0 {
+		return result.Sidecar + " (no overrides)"
+	}
+	return fmt.Sprintf("%s (%s)", result.Sidecar, strings.Join(result.SidecarOverrides, ", "))
+}
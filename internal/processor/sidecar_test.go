@@ -0,0 +1,175 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sidecarTestConfig = `
+[templates.config.master]
+header = "{{.Show.Vars.host}}\n"
+track = "{{.Title}}\n"
+
+[templates.config.guest]
+header = "Guest {{.Show.Vars.guest}} with {{.Show.Vars.host}}\n"
+track = "{{.Title}}\n"
+
+[shows.night-one]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Night One"
+enabled = true
+template = "master"
+template_vars = { host = "DJ Example" }
+`
+
+func TestSidecarOverrides(t *testing.T) {
+	tests := []struct {
+		name             string
+		files            map[string]string
+		templateOverride string
+		dateOverride     string
+		wantErr          string
+		wantSidecar      string
+		wantOverrides    []string
+		wantName         string
+		wantTemplate     string
+		wantDescription  string
+		wantTracks       int
+		wantDate         string
+	}{
+		{
+			name:            "no sidecar",
+			wantName:        "Night One",
+			wantTemplate:    "master",
+			wantDescription: "DJ Example\nWhen I Fall\n",
+			wantTracks:      3,
+		},
+		{
+			name: "every override",
+			files: map[string]string{"TEST.cue.toml": `
+show_name = "Night One Special"
+template = "guest"
+date = "2025-06-28"
+excluded_artists = ["Laura Dre"]
+
+[template_vars]
+guest = "Ana"
+`},
+			wantSidecar:     "TEST.cue.toml",
+			wantOverrides:   []string{"show_name", "template", "template_vars", "date", "excluded_artists"},
+			wantName:        "Night One Special",
+			wantTemplate:    "guest",
+			wantDescription: "Guest Ana with DJ Example\nDive Deep Into the Night\n",
+			wantTracks:      2,
+			wantDate:        "2025-06-28",
+		},
+		{
+			name:            "override.toml in the CUE directory",
+			files:           map[string]string{"override.toml": "template = \"guest\"\ntemplate_vars = { guest = \"Ben\" }\n"},
+			wantSidecar:     "override.toml",
+			wantOverrides:   []string{"template", "template_vars"},
+			wantName:        "Night One",
+			wantTemplate:    "guest",
+			wantDescription: "Guest Ben with DJ Example\n",
+			wantTracks:      3,
+		},
+		{
+			name: "CUE file sidecar before override.toml",
+			files: map[string]string{
+				"TEST.cue.toml": `show_name = "From The CUE Sidecar"`,
+				"override.toml": `show_name = "From override.toml"`,
+			},
+			wantSidecar:     "TEST.cue.toml",
+			wantOverrides:   []string{"show_name"},
+			wantName:        "From The CUE Sidecar",
+			wantTemplate:    "master",
+			wantDescription: "DJ Example\n",
+			wantTracks:      3,
+		},
+		{
+			name:             "command line wins",
+			files:            map[string]string{"TEST.cue.toml": "template = \"guest\"\ndate = \"2025-06-28\"\n"},
+			templateOverride: "master",
+			dateOverride:     "07/04/2025",
+			wantSidecar:      "TEST.cue.toml",
+			wantName:         "Night One",
+			wantTemplate:     "master",
+			wantDescription:  "DJ Example\n",
+			wantTracks:       3,
+			wantDate:         "2025-07-04",
+		},
+		{
+			name:    "unknown key",
+			files:   map[string]string{"TEST.cue.toml": "show_name = \"Special\"\ntitle = \"Special\"\n"},
+			wantErr: "unknown keys title",
+		},
+		{
+			name:    "malformed",
+			files:   map[string]string{"TEST.cue.toml": `show_name = "Special`},
+			wantErr: "parsing sidecar",
+		},
+		{
+			name:    "undefined template",
+			files:   map[string]string{"override.toml": `template = "guest-host"`},
+			wantErr: "template guest-host not found",
+		},
+		{
+			name:    "date not YYYY-MM-DD",
+			files:   map[string]string{"TEST.cue.toml": `date = "June 28"`},
+			wantErr: `date "June 28" is not YYYY-MM-DD`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, sidecarTestConfig)
+			dir := sp.config.Processing.CueFileDirectory
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			showCfg := sp.config.Shows["night-one"]
+
+			result := sp.prepareShow("night-one", &showCfg, tt.templateOverride, tt.dateOverride, true)
+			if tt.wantErr != "" {
+				if result.Error == nil || !strings.Contains(result.Error.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to mention %q", result.Error, tt.wantErr)
+				}
+				if result.FailureCategory != FailureSource {
+					t.Errorf("failure category = %s, want %s", result.FailureCategory, FailureSource)
+				}
+				return
+			}
+			if result.Error != nil {
+				t.Fatalf("prepareShow() error = %v", result.Error)
+			}
+
+			wantSidecar := ""
+			if tt.wantSidecar != "" {
+				wantSidecar = filepath.Join(dir, tt.wantSidecar)
+			}
+			if result.Sidecar != wantSidecar || !reflect.DeepEqual(result.SidecarOverrides, tt.wantOverrides) {
+				t.Errorf("sidecar = %q %q, want %q %q", result.Sidecar, result.SidecarOverrides, wantSidecar, tt.wantOverrides)
+			}
+			if result.ShowName != tt.wantName || result.Template != tt.wantTemplate {
+				t.Errorf("show name, template = %q, %q, want %q, %q", result.ShowName, result.Template, tt.wantName, tt.wantTemplate)
+			}
+			if !strings.HasPrefix(result.Description, tt.wantDescription) {
+				t.Errorf("description = %q, want it to start %q", result.Description, tt.wantDescription)
+			}
+			if result.FilteredTracks != tt.wantTracks {
+				t.Errorf("tracks = %d, want %d", result.FilteredTracks, tt.wantTracks)
+			}
+			if tt.wantDate != "" && result.ShowDate != tt.wantDate {
+				t.Errorf("show date = %q, want %q", result.ShowDate, tt.wantDate)
+			}
+			if vars := sp.config.Shows["night-one"].TemplateVars; len(vars) != 1 {
+				t.Errorf("show config template_vars = %v, want the sidecar not to change the config", vars)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+// Summarize adds the run's totals and per-show outcomes to summary, in
+// processing order followed by the shows that weren't attempted
+func (br *BatchResult) Summarize(summary *logger.ExecutionSummary) {
+	summary.Totals = &logger.RunTotals{
+		Total:        br.TotalShows,
+		Processed:    br.ProcessedShows,
+		Successful:   br.SuccessfulShows,
+		Failed:       br.FailedShows,
+		Skipped:      br.SkippedShows,
+		Placeholders: br.PlaceholderShows,
+		Queued:       br.QueuedShows,
+		CarriedOver:  br.CarriedOverShows,
+		NotAttempted: br.NotAttemptedShows,
+	}
+
+	summary.Shows = make([]logger.ShowOutcome, 0, len(br.Results)+br.NotAttemptedShows)
+	for _, result := range br.Results {
+		summary.Shows = append(summary.Shows, showOutcome(result))
+	}
+	for _, keys := range [][]string{br.NotAttempted, br.DeadlineSkipped, br.ThresholdSkipped} {
+		for _, key := range keys {
+			summary.Shows = append(summary.Shows, logger.ShowOutcome{ShowKey: key, Status: logger.ShowNotAttempted})
+		}
+	}
+}
+
+// showOutcome converts a show's result for the execution summary
+func showOutcome(result ProcessingResult) logger.ShowOutcome {
+	outcome := logger.ShowOutcome{
+		ShowKey:     result.ShowKey,
+		Status:      logger.ShowStatus(resultStatus(result)), // Same values as show_finished
+		URL:         result.ShowURL,
+		DurationMS:  result.Duration.Milliseconds(),
+		CarriedOver: result.CarriedOver,
+	}
+	if result.Error != nil {
+		outcome.FailureCategory = string(result.FailureCategory)
+		outcome.Error = result.Error.Error()
+	}
+	if result.Anomaly != nil {
+		outcome.Anomaly = result.Anomaly.Verdict
+		outcome.AnomalyDetail = result.Anomaly.String()
+	}
+	return outcome
+}
@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/logger"
+)
+
+func TestBatchResultSummarize(t *testing.T) {
+	tests := []struct {
+		name       string
+		missing    []string // Days whose show Mixcloud doesn't find
+		limit      int
+		run        func(sp *ShowProcessor) error
+		wantErr    bool
+		wantShows  []logger.ShowOutcome // Compared without Error and DurationMS
+		wantTotals logger.RunTotals
+		wantFailed []string
+	}{
+		{
+			name: "single show success",
+			run:  func(sp *ShowProcessor) error { return sp.ProcessShow("fest-fri", "", "", false) },
+			wantShows: []logger.ShowOutcome{
+				{ShowKey: "fest-fri", Status: logger.ShowSuccess, URL: festURL("Friday")},
+			},
+			wantTotals: logger.RunTotals{Total: 1, Processed: 1, Successful: 1},
+		},
+		{
+			name:    "single show failure",
+			missing: []string{"Friday"},
+			run:     func(sp *ShowProcessor) error { return sp.ProcessShow("fest-fri", "", "", false) },
+			wantErr: true,
+			wantShows: []logger.ShowOutcome{
+				{ShowKey: "fest-fri", Status: logger.ShowFailed, URL: festURL("Friday"), FailureCategory: string(FailureNotFound)},
+			},
+			wantTotals: logger.RunTotals{Total: 1, Processed: 1, Failed: 1},
+			wantFailed: []string{"fest-fri"},
+		},
+		{
+			name:    "mixed batch",
+			missing: []string{"Saturday"},
+			limit:   2,
+			run:     func(sp *ShowProcessor) error { return sp.ProcessAllShows(false) },
+			wantErr: true,
+			wantShows: []logger.ShowOutcome{
+				{ShowKey: "fest-fri", Status: logger.ShowSuccess, URL: festURL("Friday")},
+				{ShowKey: "fest-sat", Status: logger.ShowFailed, URL: festURL("Saturday"), FailureCategory: string(FailureNotFound)},
+				{ShowKey: "fest-sun", Status: logger.ShowNotAttempted},
+			},
+			wantTotals: logger.RunTotals{Total: 3, Processed: 2, Successful: 1, Failed: 1, NotAttempted: 1},
+			wantFailed: []string{"fest-sat"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, fake := newGroupTestProcessor(t)
+			for _, day := range tt.missing {
+				fake.missing[festURL(day)] = true
+			}
+			sp.SetOptions(Options{Limit: tt.limit})
+
+			if err := tt.run(sp); (err != nil) != tt.wantErr {
+				t.Fatalf("run error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var summary logger.ExecutionSummary
+			sp.LastRun().Summarize(&summary)
+
+			if summary.Totals == nil || *summary.Totals != tt.wantTotals {
+				t.Errorf("totals = %+v, want %+v", summary.Totals, tt.wantTotals)
+			}
+			shows := make([]logger.ShowOutcome, len(summary.Shows))
+			for i, show := range summary.Shows {
+				if show.Status == logger.ShowFailed && !strings.Contains(show.Error, "not found") {
+					t.Errorf("%s error = %q, want the not-found error", show.ShowKey, show.Error)
+				}
+				if show.Status != logger.ShowFailed && show.Error != "" {
+					t.Errorf("%s error = %q, want none", show.ShowKey, show.Error)
+				}
+				show.Error, show.DurationMS = "", 0
+				shows[i] = show
+			}
+			if !reflect.DeepEqual(shows, tt.wantShows) {
+				t.Errorf("shows = %+v, want %+v", shows, tt.wantShows)
+			}
+			if failed := summary.FailedShowKeys(); !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("FailedShowKeys() = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+// AIDEV-NOTE: The time offset is applied right after parsing, so gap checks,
+// filters, split_at parts and every formatter see the start times of the upload
+// rather than those of the CUE log. Tracks dropped for starting in the trimmed
+// pre-roll count as excluded in the summary.
+
+// applyTimeOffset shifts tracks by the -time-offset override or the show's
+// time_offset, logging tracks dropped with drop_negative_offset_tracks
+func (sp *ShowProcessor) applyTimeOffset(showKey string, showCfg *config.ShowConfig, tracks []cue.Track) ([]cue.Track, error) {
+	setting := showCfg.TimeOffset
+	if sp.options.TimeOffset != "" {
+		setting = sp.options.TimeOffset
+	}
+	offset, err := config.ParseTimeOffset(setting)
+	if err != nil {
+		return nil, fmt.Errorf("time_offset: %w", err)
+	}
+	if offset == 0 {
+		return tracks, nil
+	}
+
+	shifted, dropped := cue.ShiftTracks(tracks, offset, showCfg.DropNegativeOffsetTracks)
+	sp.logger.Info("Track start times shifted",
+		slog.String("show_key", showKey),
+		slog.Duration("offset", offset),
+		slog.Int("dropped", len(dropped)))
+	if len(dropped) > 0 {
+		names := make([]string, len(dropped))
+		for i, track := range dropped {
+			names[i] = fmt.Sprintf("track %d at %s", track.Index, track.StartTime)
+		}
+		sp.logger.Warn("Dropped tracks that start before the upload",
+			slog.String("show_key", showKey),
+			slog.Duration("offset", offset),
+			slog.String("tracks", strings.Join(names, "; ")))
+	}
+	return shifted, nil
+}
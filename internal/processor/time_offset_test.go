@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTimeOffset(t *testing.T) {
+	tests := []struct {
+		name     string
+		show     string
+		override string
+		want     string // Tracklist lines after the show's URL
+		wantErr  bool
+	}{
+		{"no offset", ``, "", "00:25 - \"When I Fall\" by Laura Dre\n04:48 - \"Dive Deep Into the Night\" by Pure Obsessions", false},
+		{"clamped", `time_offset = "-00:01:30"`, "", "00:00 - \"When I Fall\" by Laura Dre\n03:18 - \"Dive Deep Into the Night\" by Pure Obsessions", false},
+		{"dropped", "time_offset = \"-00:01:30\"\ndrop_negative_offset_tracks = true", "", "03:18 - \"Dive Deep Into the Night\" by Pure Obsessions\n06:45", false},
+		{"override", `time_offset = "-00:01:30"`, "+00:00:05", "00:30 - \"When I Fall\" by Laura Dre\n04:53", false},
+		{"invalid override", ``, "-1:30", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, announcementTestConfig+tt.show+"\n")
+			sp.SetOptions(Options{TimeOffset: tt.override})
+			fake := newFakeMixcloud()
+			sp.mixcloud = fake
+
+			err := sp.ProcessShow("night-one", "", "", false)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ProcessShow() = nil, want a time_offset error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessShow() error = %v", err)
+			}
+			if len(fake.updates) != 1 || !strings.HasPrefix(fake.updates[0], nightOneURL+"="+tt.want) {
+				t.Errorf("updates = %q, want the tracklist to start %q", fake.updates, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/console"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+// AIDEV-NOTE: A token revoked in Mixcloud's settings fails updates exactly like
+// an expired one, and "run again to re-authenticate" loops forever while the
+// stored token keeps being sent. Without a Reauthorizer (no terminal), the run's
+// first auth failure asks /me/ about the token: if /me/ rejects it too, the
+// stored token is cleared so needsAuthorization starts the OAuth flow on the
+// next run. A token /me/ still accepts marks the failure as transient.
+
+// tokenProbeTimeout bounds the /me/ request after an auth failure
+const tokenProbeTimeout = 15 * time.Second
+
+// tokenChecker is implemented by clients that can check their access token
+// against /me/ and forget it
+type tokenChecker interface {
+	Me(ctx context.Context) (*mixcloud.User, error)
+	ClearToken() error
+}
+
+// TokenCheck is what the /me/ probe after a run's first auth failure found
+type TokenCheck struct {
+	Checked  bool  // The token was probed
+	Rejected bool  // /me/ rejected it too: expired or revoked
+	Cleared  bool  // The stored token was cleared; the next run asks for authorization
+	Err      error // Why the probe couldn't tell, or clearing the token failed
+}
+
+// TokenCheck returns the outcome of the run's token probe; Checked is false
+// when the run had no auth failure to probe
+func (sp *ShowProcessor) TokenCheck() TokenCheck {
+	return sp.tokenCheck
+}
+
+// checkRejectedToken probes the access token after the run's first auth
+// failure and clears it when Mixcloud rejects it for good
+func (sp *ShowProcessor) checkRejectedToken(result ProcessingResult) {
+	if sp.tokenCheck.Checked || result.FailureCategory != FailureAuth {
+		return
+	}
+	checker, ok := sp.mixcloud.(tokenChecker)
+	if !ok {
+		return
+	}
+	sp.tokenCheck.Checked = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenProbeTimeout)
+	defer cancel()
+	_, err := checker.Me(ctx)
+	switch {
+	case err == nil:
+		sp.logger.Warn("Authentication failed but the access token is still accepted, treating the failure as transient",
+			slog.String("show_key", result.ShowKey),
+			slog.String("error", result.Error.Error()))
+		console.Warnf("⚠️  %s failed authentication, but Mixcloud still accepts the access token - likely transient, the token was kept\n", result.ShowKey)
+		return
+	case !errors.Is(err, mixcloud.ErrAuthenticationFailed):
+		sp.tokenCheck.Err = err
+		sp.logger.Warn("Could not check the access token after an authentication failure",
+			slog.String("endpoint", mixcloud.MeEndpoint),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	sp.tokenCheck.Rejected = true
+	if err := checker.ClearToken(); err != nil {
+		sp.tokenCheck.Err = err
+		sp.logger.Error("Access token expired or revoked, clearing the stored token failed",
+			slog.String("error", err.Error()))
+		console.Errorf("🔑 Mixcloud rejected the access token (expired or revoked), but clearing the stored token failed: %v\n", err)
+		return
+	}
+	sp.tokenCheck.Cleared = true
+	sp.logger.Error("Access token expired or revoked, cleared stored token; next run will prompt for authorization",
+		slog.String("show_key", result.ShowKey))
+	console.Errorf("🔑 Access token expired or revoked - cleared stored token; next run will prompt for authorization\n")
+}
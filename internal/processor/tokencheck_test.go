@@ -0,0 +1,148 @@
+package processor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+const oauthExceptionBody = `{"error": {"type": "OAuthException", "message": "Invalid access token"}}`
+
+// revokedTokenServer rejects updates like Mixcloud does for a revoked token
+// and answers /me/ with meStatus and meBody, counting /me/ requests
+type revokedTokenServer struct {
+	mu         sync.Mutex
+	meStatus   int
+	meBody     string
+	meRequests int
+}
+
+func (s *revokedTokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == mixcloud.MeEndpoint:
+		s.mu.Lock()
+		s.meRequests++
+		s.mu.Unlock()
+		w.WriteHeader(s.meStatus)
+		w.Write([]byte(s.meBody))
+	case r.Method == http.MethodPost:
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(oauthExceptionBody))
+	default:
+		w.Write([]byte(`{"key": "/testuser/storm-show/", "name": "Storm Show", "description": "old"}`))
+	}
+}
+
+// TestRevokedTokenCleared runs a show against a server rejecting its updates
+// and checks the /me/ probe decides whether the stored token is cleared.
+func TestRevokedTokenCleared(t *testing.T) {
+	tests := []struct {
+		name        string
+		meStatus    int
+		meBody      string
+		wantCheck   TokenCheck // Err is only checked for nil
+		wantErr     bool
+		wantCleared bool // The access_token line in the config file is blanked
+	}{
+		{
+			name:        "revoked token answered with 401",
+			meStatus:    http.StatusUnauthorized,
+			meBody:      oauthExceptionBody,
+			wantCheck:   TokenCheck{Checked: true, Rejected: true, Cleared: true},
+			wantCleared: true,
+		},
+		{
+			name:        "revoked token answered with an OAuthException",
+			meStatus:    http.StatusBadRequest,
+			meBody:      oauthExceptionBody,
+			wantCheck:   TokenCheck{Checked: true, Rejected: true, Cleared: true},
+			wantCleared: true,
+		},
+		{
+			name:      "token still accepted",
+			meStatus:  http.StatusOK,
+			meBody:    `{"username": "testuser"}`,
+			wantCheck: TokenCheck{Checked: true},
+		},
+		{
+			name:      "probe unavailable",
+			meStatus:  http.StatusServiceUnavailable,
+			meBody:    `{}`,
+			wantCheck: TokenCheck{Checked: true},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &revokedTokenServer{meStatus: tt.meStatus, meBody: tt.meBody}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			sp := newTestProcessor(t, retryTestConfig)
+			sp.options.NoCache = true
+			sp.configureShowCache()
+			sp.mixcloud.(*mixcloud.Client).SetBaseURL(httpServer.URL)
+			before, err := os.ReadFile(sp.configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			showCfg := sp.config.Shows["storm"]
+			result := sp.processingleShow("storm", &showCfg, "", "", false)
+			if !errors.Is(result.Error, mixcloud.ErrAuthenticationFailed) || result.FailureCategory != FailureAuth {
+				t.Fatalf("processingleShow() error = %v [%s], want an auth failure", result.Error, result.FailureCategory)
+			}
+
+			check := sp.TokenCheck()
+			if (check.Err != nil) != tt.wantErr {
+				t.Errorf("TokenCheck().Err = %v, wantErr %v", check.Err, tt.wantErr)
+			}
+			check.Err = nil
+			if check != tt.wantCheck {
+				t.Errorf("TokenCheck() = %+v, want %+v", check, tt.wantCheck)
+			}
+
+			after, err := os.ReadFile(sp.configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := string(before)
+			if tt.wantCleared {
+				want = strings.Replace(want, `access_token = "test-access-token"`, `access_token = ""`, 1)
+			}
+			if string(after) != want {
+				t.Errorf("config file =\n%s\nwant\n%s", after, want)
+			}
+		})
+	}
+}
+
+// TestTokenProbedOncePerRun checks later auth failures of a run don't probe /me/ again
+func TestTokenProbedOncePerRun(t *testing.T) {
+	server := &revokedTokenServer{meStatus: http.StatusOK, meBody: `{"username": "testuser"}`}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	sp := newTestProcessor(t, retryTestConfig)
+	sp.options.NoCache = true
+	sp.configureShowCache()
+	sp.mixcloud.(*mixcloud.Client).SetBaseURL(httpServer.URL)
+
+	showCfg := sp.config.Shows["storm"]
+	for i := 0; i < 2; i++ {
+		if result := sp.processingleShow("storm", &showCfg, "", "", false); result.FailureCategory != FailureAuth {
+			t.Fatalf("processingleShow() #%d = %v [%s], want an auth failure", i+1, result.Error, result.FailureCategory)
+		}
+	}
+	if server.meRequests != 1 {
+		t.Errorf("/me/ requests = %d, want 1", server.meRequests)
+	}
+}
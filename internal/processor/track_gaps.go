@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+// AIDEV-NOTE: max_track_gap_minutes catches CUE files with a stretch missing after a
+// logger crash, which would otherwise publish as a continuous tracklist. The gap
+// counts are recorded even with gap_action = "warn" so reports can surface them.
+
+// checkTrackGaps records the gaps between consecutive track starts on result and
+// logs those over max_track_gap_minutes. With gap_action = "fail" it returns an
+// error when there are any.
+func (sp *ShowProcessor) checkTrackGaps(result *ProcessingResult, showCfg *config.ShowConfig, cueFile string, tracks []cue.Track) error {
+	if showCfg.MaxTrackGapMinutes <= 0 {
+		return nil
+	}
+	threshold := time.Duration(showCfg.MaxTrackGapMinutes) * time.Minute
+	report := cue.FindGaps(tracks, threshold)
+	result.LargestTrackGap = report.Largest
+	result.TrackGaps = len(report.Gaps)
+	if len(report.Gaps) == 0 {
+		return nil
+	}
+
+	gaps := make([]string, len(report.Gaps))
+	for i, gap := range report.Gaps {
+		gaps[i] = fmt.Sprintf("track %d at %s to track %d at %s (%s)",
+			gap.From.Index, gap.From.StartTime, gap.To.Index, gap.To.StartTime, gap.Length)
+	}
+	sp.logger.Warn("Gaps between CUE tracks exceed max_track_gap_minutes",
+		slog.String("show_key", result.ShowKey),
+		slog.String("file", cueFile),
+		slog.Int("max_track_gap_minutes", showCfg.MaxTrackGapMinutes),
+		slog.Int("gap_count", len(report.Gaps)),
+		slog.String("gaps", strings.Join(gaps, "; ")))
+
+	if showCfg.TrackGapAction() == config.GapActionFail {
+		return fmt.Errorf("%d gap(s) between CUE tracks exceed max_track_gap_minutes (%d), largest %s",
+			len(report.Gaps), showCfg.MaxTrackGapMinutes, report.Largest)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessShowTrackGaps(t *testing.T) {
+	// The logger stopped after track 02: track 03 starts 43m27s later
+	gappedCue := strings.Replace(testCueContent, "INDEX 01 08:15:02", "INDEX 01 48:15:02", 1)
+
+	tests := []struct {
+		name        string
+		config      string
+		wantError   string
+		wantGaps    int
+		wantLargest time.Duration
+		wantCounts  bool
+	}{
+		{"check disabled", "", "", 0, 0, false},
+		{"warn", "max_track_gap_minutes = 15\n", "", 1, 43*time.Minute + 27*time.Second, true},
+		{"fail", "max_track_gap_minutes = 15\ngap_action = \"fail\"\n", "1 gap(s) between CUE tracks exceed max_track_gap_minutes (15), largest 43m27s", 1, 43*time.Minute + 27*time.Second, true},
+		{"under threshold", "max_track_gap_minutes = 60\ngap_action = \"fail\"\n", "", 0, 43*time.Minute + 27*time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, `
+[shows.test]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Test Show"
+enabled = true
+`+tt.config)
+			cuePath := filepath.Join(sp.config.Processing.CueFileDirectory, "TEST.cue")
+			if err := os.WriteFile(cuePath, []byte(gappedCue), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var parseCounts map[string]int
+			sp.SetProgressObserver(ProgressFunc(func(event ProgressEvent) {
+				if event.Step == StepParse {
+					parseCounts = event.Counts
+				}
+			}))
+
+			showCfg := sp.config.Shows["test"]
+			result := sp.processingleShow("test", &showCfg, "", "", true)
+
+			if tt.wantError == "" && result.Error != nil {
+				t.Fatalf("Error = %v, want none", result.Error)
+			}
+			if tt.wantError != "" {
+				if result.Error == nil || !strings.Contains(result.Error.Error(), tt.wantError) {
+					t.Fatalf("Error = %v, want it to contain %q", result.Error, tt.wantError)
+				}
+				if result.FailureCategory != FailureSource {
+					t.Errorf("FailureCategory = %q, want %q", result.FailureCategory, FailureSource)
+				}
+			}
+			if result.TrackGaps != tt.wantGaps || result.LargestTrackGap != tt.wantLargest {
+				t.Errorf("TrackGaps, LargestTrackGap = %d, %v, want %d, %v",
+					result.TrackGaps, result.LargestTrackGap, tt.wantGaps, tt.wantLargest)
+			}
+
+			_, haveCounts := parseCounts["largest_gap_seconds"]
+			if haveCounts != tt.wantCounts {
+				t.Fatalf("parse step counts = %v, want gap counts %v", parseCounts, tt.wantCounts)
+			}
+			if haveCounts && (parseCounts["gaps"] != tt.wantGaps || parseCounts["largest_gap_seconds"] != int(tt.wantLargest.Seconds())) {
+				t.Errorf("parse step counts = %v, want %d gaps, largest %v", parseCounts, tt.wantGaps, tt.wantLargest)
+			}
+		})
+	}
+}
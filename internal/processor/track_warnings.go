@@ -0,0 +1,40 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+// AIDEV-NOTE: A crashed logger can leave a malformed TRACK block in an otherwise
+// good CUE file. Those tracks are skipped with a warning; the show only fails
+// when more than processing.max_broken_track_percent of them are bad.
+
+// logTrackWarnings logs each malformed track skipped while parsing a CUE file
+func (sp *ShowProcessor) logTrackWarnings(showKey, cueFile string, warnings []cue.TrackWarning) {
+	for _, warning := range warnings {
+		sp.logger.Warn("Skipped malformed CUE track",
+			slog.String("show_key", showKey),
+			slog.String("file", cueFile),
+			slog.Int("line_number", warning.Line),
+			slog.Int("track", warning.Track),
+			slog.String("reason", warning.Reason))
+	}
+}
+
+// checkTrackWarnings fails a show whose share of malformed tracks exceeds
+// processing.max_broken_track_percent
+func (sp *ShowProcessor) checkTrackWarnings(parsed, malformed int) error {
+	if malformed == 0 {
+		return nil
+	}
+	maxPercent := sp.config.Processing.MaxBrokenTrackPercent
+	total := parsed + malformed
+	percent := float64(malformed) / float64(total) * 100
+	if percent > float64(maxPercent) {
+		return fmt.Errorf("%d of %d CUE tracks are malformed (%.0f%%, max_broken_track_percent is %d)",
+			malformed, total, percent, maxPercent)
+	}
+	return nil
+}
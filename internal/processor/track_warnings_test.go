@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessShowMalformedTracks(t *testing.T) {
+	// Track 03 lost its INDEX line: 1 of 3 tracks (33%) is malformed
+	brokenCue := strings.Replace(testCueContent, "    INDEX 01 08:15:02\n", "", 1)
+
+	tests := []struct {
+		name         string
+		maxPercent   int // 0 keeps the default
+		strictConfig bool
+		strictOption bool
+		wantError    string
+		wantTracks   int
+		wantWarnings int
+	}{
+		{"over default threshold", 0, false, false, "1 of 3 CUE tracks are malformed", 0, 1},
+		{"within raised threshold", 50, false, false, "", 2, 1},
+		{"strict option", 50, false, true, "missing INDEX 01", 0, 0},
+		{"strict config", 50, true, false, "missing INDEX 01", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, `
+[shows.test]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Test Show"
+enabled = true
+`)
+			if tt.maxPercent > 0 {
+				sp.config.Processing.MaxBrokenTrackPercent = tt.maxPercent
+			}
+			sp.config.Processing.StrictCueParsing = tt.strictConfig
+			sp.SetOptions(Options{StrictCue: tt.strictOption})
+
+			cuePath := filepath.Join(sp.config.Processing.CueFileDirectory, "TEST.cue")
+			if err := os.WriteFile(cuePath, []byte(brokenCue), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			showCfg := sp.config.Shows["test"]
+			result := sp.processingleShow("test", &showCfg, "", "", true)
+
+			if tt.wantError == "" && result.Error != nil {
+				t.Fatalf("Error = %v, want none", result.Error)
+			}
+			if tt.wantError != "" && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.wantError)) {
+				t.Fatalf("Error = %v, want it to contain %q", result.Error, tt.wantError)
+			}
+			if result.TrackWarnings != tt.wantWarnings {
+				t.Errorf("TrackWarnings = %d, want %d", result.TrackWarnings, tt.wantWarnings)
+			}
+			if tt.wantError == "" && result.FilteredTracks != tt.wantTracks {
+				t.Errorf("FilteredTracks = %d, want %d", result.FilteredTracks, tt.wantTracks)
+			}
+		})
+	}
+}
+
+func TestProcessShowIndex00OnlyTracks(t *testing.T) {
+	// Track 03 has only a pregap, like an exporter's outro bed
+	outroCue := strings.Replace(testCueContent, "    INDEX 01 08:15:02\n", "    INDEX 00 58:40:00\n", 1)
+
+	tests := []struct {
+		name       string
+		skip       bool
+		wantTracks int
+	}{
+		{"starts at the pregap", false, 3},
+		{"skip_index00_only_tracks", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newTestProcessor(t, `
+[shows.test]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Test Show"
+enabled = true
+template = "classic"
+`)
+			sp.config.Processing.SkipIndex00OnlyTracks = tt.skip
+			cuePath := filepath.Join(sp.config.Processing.CueFileDirectory, "TEST.cue")
+			if err := os.WriteFile(cuePath, []byte(outroCue), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			showCfg := sp.config.Shows["test"]
+			result := sp.processingleShow("test", &showCfg, "", "", true)
+			if result.Error != nil {
+				t.Fatalf("Error = %v, want none", result.Error)
+			}
+			if result.TrackWarnings != 0 {
+				t.Errorf("TrackWarnings = %d, want 0", result.TrackWarnings)
+			}
+			if result.ParsedTracks != tt.wantTracks {
+				t.Errorf("ParsedTracks = %d, want %d", result.ParsedTracks, tt.wantTracks)
+			}
+			if listed := strings.Contains(result.Description, `58:40 - "Conditional Love"`); listed == tt.skip {
+				t.Errorf("description = %q, want the outro bed listed: %v", result.Description, !tt.skip)
+			}
+		})
+	}
+}
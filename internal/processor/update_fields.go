@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textcut"
+)
+
+// AIDEV-NOTE: Mixcloud's edit endpoint only changes the fields it receives, but
+// some accounts have had the title blanked by description-only edits. The
+// processor therefore builds the whole form: extra_update_fields from the config,
+// the current name when preserve_name is set, and the description last.
+
+// previewFieldLength is how much of each form field value dry-run shows
+const previewFieldLength = 60
+
+// expandUpdateFields expands the placeholders in a show's extra_update_fields
+func (sp *ShowProcessor) expandUpdateFields(showCfg *config.ShowConfig, dateOverride string) (map[string]string, error) {
+	fields := make(map[string]string, len(showCfg.ExtraUpdateFields)+1)
+	for name, pattern := range showCfg.ExtraUpdateFields {
+		value, err := sp.expandShowPattern(pattern, showCfg, dateOverride)
+		if err != nil {
+			return nil, fmt.Errorf("expanding extra_update_fields.%s: %w", name, err)
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// addGenreTags adds the show's most common genres to the update fields as
+// Mixcloud tags, tags-0-tag, tags-1-tag and so on
+func addGenreTags(fields map[string]string, genres []string, limit int) {
+	if len(genres) > limit {
+		genres = genres[:limit]
+	}
+	for i, genre := range genres {
+		fields[fmt.Sprintf("tags-%d-tag", i)] = genre
+	}
+}
+
+// preserveName adds the show's current title to the update fields. Without a
+// title to re-send the field is left out rather than sent blank.
+func (sp *ShowProcessor) preserveName(result *ProcessingResult, existing *mixcloud.Show) {
+	if existing == nil || existing.Name == "" {
+		sp.logger.Warn("preserve_name is set but Mixcloud returned no show name, sending update without it",
+			slog.String("show_key", result.ShowKey),
+			slog.String("url", result.ShowURL))
+		return
+	}
+	result.UpdateFields["name"] = existing.Name
+}
+
+// formFields returns the complete edit request for the show with the given description
+func (r *ProcessingResult) formFields(description string) map[string]string {
+	fields := make(map[string]string, len(r.UpdateFields)+1)
+	for name, value := range r.UpdateFields {
+		fields[name] = value
+	}
+	fields["description"] = description
+	return fields
+}
+
+// dryRunFieldLines lists the form fields a dry run would send, one "name: value"
+// line each in request order, with long values cut to previewFieldLength
+func dryRunFieldLines(result ProcessingResult, fields map[string]string) []string {
+	if result.PreserveName {
+		fields["name"] = "(current title, fetched from Mixcloud at update time)"
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		value := previewFieldValue(fields[name])
+		if name == "description" {
+			value += fmt.Sprintf(" (%d chars)", len(fields[name]))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", name, value))
+	}
+	return lines
+}
+
+// previewFieldValue shows a form value on one line, truncated to previewFieldLength runes
+func previewFieldValue(value string) string {
+	oneLine := strings.ReplaceAll(value, "\n", `\n`)
+	return textcut.Truncate(oneLine, previewFieldLength, "...")
+}
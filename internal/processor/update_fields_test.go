@@ -0,0 +1,177 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/mixcloud"
+)
+
+const updateFieldsTestConfig = `
+[shows.fields]
+cue_file_mapping = "TEST.cue"
+show_name_pattern = "Fields Show"
+preserve_name = true
+enabled = true
+
+[shows.fields.extra_update_fields]
+unlisted = "1"
+label = "{station} on {date:YYYY}"
+`
+
+func TestPublishSendsUpdateFields(t *testing.T) {
+	sp := newTestProcessor(t, updateFieldsTestConfig)
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	showCfg := sp.config.Shows["fields"]
+	result := sp.processingleShow("fields", &showCfg, "", "6/28/2025", false)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	if len(fake.sent) != 1 {
+		t.Fatalf("got %d updates, want 1", len(fake.sent))
+	}
+
+	showURL := mixcloud.GenerateShowURL("testuser", "Fields Show")
+	want := map[string]string{
+		"description": result.Description,
+		"name":        "Name " + showURL,
+		"unlisted":    "1",
+		"label":       "Test Station on 2025",
+	}
+	if !reflect.DeepEqual(fake.sent[0], want) {
+		t.Errorf("sent fields = %v, want %v", fake.sent[0], want)
+	}
+}
+
+const genreTagsTestConfig = `
+[formatting]
+max_genres = 2
+
+[templates.config.genres]
+header = "Genres: {{join \", \" .Genres}}\n"
+track = "{{.Artist}} - {{.Title}}\n"
+
+[shows.genres]
+cue_file_mapping = "GENRES.cue"
+show_name_pattern = "Genre Show"
+template = "genres"
+tags_from_genres = true
+max_genre_tags = 3
+enabled = true
+`
+
+// genreTagsCue has Post-Punk twice in two spellings, a station sweeper and a
+// track without a genre
+const genreTagsCue = `FILE "GENRES.wav" WAV
+  TRACK 01 AUDIO
+    TITLE "Uno"
+    PERFORMER "Grupo A"
+    REM GENRE "Synth-Pop"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Dos"
+    PERFORMER "Grupo B"
+    REM GENRE "Post-Punk"
+    INDEX 01 04:00:00
+  TRACK 03 AUDIO
+    TITLE "Station Sweeper"
+    PERFORMER "Radio"
+    REM GENRE "Sweepers"
+    INDEX 01 08:00:00
+  TRACK 04 AUDIO
+    TITLE "Tres"
+    PERFORMER "Grupo C"
+    REM GENRE "post-punk"
+    INDEX 01 08:10:00
+  TRACK 05 AUDIO
+    TITLE "Cuatro"
+    PERFORMER "Grupo D"
+    REM GENRE "Darkwave"
+    INDEX 01 12:00:00
+  TRACK 06 AUDIO
+    TITLE "Cinco"
+    PERFORMER "Grupo E"
+    INDEX 01 16:00:00
+  TRACK 07 AUDIO
+    TITLE "Seis"
+    PERFORMER "Grupo F"
+    REM GENRE "Coldwave"
+    INDEX 01 20:00:00
+`
+
+func TestPublishSendsGenreTags(t *testing.T) {
+	sp := newTestProcessor(t, genreTagsTestConfig)
+	if err := os.WriteFile(filepath.Join(sp.config.Processing.CueFileDirectory, "GENRES.cue"), []byte(genreTagsCue), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fake := newFakeMixcloud()
+	sp.mixcloud = fake
+
+	showCfg := sp.config.Shows["genres"]
+	result := sp.processingleShow("genres", &showCfg, "", "", false)
+	if result.Error != nil {
+		t.Fatalf("processingleShow() error = %v", result.Error)
+	}
+	if !strings.HasPrefix(result.Description, "Genres: Post-Punk, Synth-Pop\n") {
+		t.Errorf("description should list the 2 most common genres (max_genres):\n%s", result.Description)
+	}
+	if len(fake.sent) != 1 {
+		t.Fatalf("got %d updates, want 1", len(fake.sent))
+	}
+	want := map[string]string{
+		"description": result.Description,
+		"tags-0-tag":  "Post-Punk",
+		"tags-1-tag":  "Synth-Pop",
+		"tags-2-tag":  "Darkwave",
+	}
+	if !reflect.DeepEqual(fake.sent[0], want) {
+		t.Errorf("sent fields = %v, want %v", fake.sent[0], want)
+	}
+}
+
+func TestDryRunFieldLines(t *testing.T) {
+	longDescription := strings.Repeat("x", previewFieldLength) + "\nmore"
+
+	tests := []struct {
+		name   string
+		result ProcessingResult
+		want   []string
+	}{
+		{
+			name:   "description only",
+			result: ProcessingResult{Description: "1. A - B\n2. C - D"},
+			want:   []string{`description: 1. A - B\n2. C - D (17 chars)`},
+		},
+		{
+			name:   "long description is truncated",
+			result: ProcessingResult{Description: longDescription},
+			want:   []string{"description: " + strings.Repeat("x", previewFieldLength) + "... (65 chars)"},
+		},
+		{
+			name: "extra fields and preserved name",
+			result: ProcessingResult{
+				Description:  "d",
+				UpdateFields: map[string]string{"unlisted": "1"},
+				PreserveName: true,
+			},
+			want: []string{
+				"description: d (1 chars)",
+				"name: (current title, fetched from Mixcloud at update time)",
+				"unlisted: 1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dryRunFieldLines(tt.result, tt.result.formFields(tt.result.Description)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dryRunFieldLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package retry runs an operation again after transient failures, waiting with
+// exponential backoff between attempts.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Policy describes how often and how patiently an operation is retried. The
+// zero value makes a single attempt.
+type Policy struct {
+	MaxAttempts int           // Attempts including the first; below 1 means 1
+	BaseDelay   time.Duration // Wait after the first failure, doubled after each later one
+	MaxDelay    time.Duration // Cap on a single wait (0 = no cap)
+	Jitter      float64       // Each wait varies randomly by up to ± this fraction, e.g. 0.25
+
+	// Retryable reports whether an error is worth another attempt (nil = every error)
+	Retryable func(err error) bool
+	// RetryAfter returns a wait the server asked for, which replaces the backoff when > 0
+	RetryAfter func(err error) time.Duration
+	// OnRetry is called before each wait, e.g. to log the failure
+	OnRetry func(attempt int, delay time.Duration, err error)
+	// Sleep waits between attempts (nil = SleepContext); tests substitute a fake clock
+	Sleep func(ctx context.Context, d time.Duration) error
+	// Rand returns the jitter source in [0, 1) (nil = math/rand)
+	Rand func() float64
+}
+
+// ExhaustedError is returned when every attempt failed with a retryable error
+type ExhaustedError struct {
+	Attempts int
+	Err      error // The last attempt's error
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("max retries (%d) exceeded: %v", e.Attempts, e.Err)
+}
+
+func (e *ExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// Attempts returns the number of attempts the policy makes at most
+func (p Policy) Attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// Backoff returns the wait after failed attempt n (1-based), before jitter:
+// BaseDelay, then doubling up to MaxDelay
+func (p Policy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// Delay returns the wait after failed attempt n with err: the server's
+// RetryAfter when given, otherwise the backoff, then jittered
+func (p Policy) Delay(attempt int, err error) time.Duration {
+	delay := p.Backoff(attempt)
+	if p.RetryAfter != nil {
+		if requested := p.RetryAfter(err); requested > 0 {
+			delay = requested
+		}
+	}
+	if p.Jitter > 0 && delay > 0 {
+		random := rand.Float64
+		if p.Rand != nil {
+			random = p.Rand
+		}
+		delay += time.Duration((random()*2 - 1) * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// Do calls fn until it succeeds or returns an error that isn't retryable, the
+// attempts run out, or ctx is done. A non-retryable error is returned as is;
+// running out of attempts returns an *ExhaustedError wrapping the last error.
+// When ctx ends a wait, the last error is returned.
+func (p Policy) Do(ctx context.Context, fn func() error) error {
+	attempts := p.Attempts()
+	sleep := p.Sleep
+	if sleep == nil {
+		sleep = SleepContext
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if p.Retryable != nil && !p.Retryable(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if attempt >= attempts {
+			return &ExhaustedError{Attempts: attempts, Err: err}
+		}
+
+		delay := p.Delay(attempt, err)
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, delay, err)
+		}
+		if sleep(ctx, delay) != nil {
+			return err
+		}
+	}
+}
+
+// SleepContext waits for d, returning early with ctx's error if ctx is done first
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,211 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+// fakeClock records the waits a policy asks for instead of sleeping
+type fakeClock struct {
+	waits  []time.Duration
+	cancel context.CancelFunc // Called on the first wait when set
+}
+
+func (c *fakeClock) sleep(ctx context.Context, d time.Duration) error {
+	c.waits = append(c.waits, d)
+	if c.cancel != nil {
+		c.cancel()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestDoDelaySequence(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    Policy
+		failures  int // fn fails this many times, then succeeds
+		wantCalls int
+		wantWaits []time.Duration
+		wantErr   bool
+	}{
+		{
+			name:      "doubling",
+			policy:    Policy{MaxAttempts: 5, BaseDelay: time.Second},
+			failures:  10,
+			wantCalls: 5,
+			wantWaits: []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+			wantErr:   true,
+		},
+		{
+			name:      "capped",
+			policy:    Policy{MaxAttempts: 6, BaseDelay: time.Second, MaxDelay: 5 * time.Second},
+			failures:  10,
+			wantCalls: 6,
+			wantWaits: []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+			wantErr:   true,
+		},
+		{
+			name:      "succeeds on third attempt",
+			policy:    Policy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond},
+			failures:  2,
+			wantCalls: 3,
+			wantWaits: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:      "jitter at the low end",
+			policy:    Policy{MaxAttempts: 3, BaseDelay: time.Second, Jitter: 0.25, Rand: func() float64 { return 0 }},
+			failures:  10,
+			wantCalls: 3,
+			wantWaits: []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond},
+			wantErr:   true,
+		},
+		{
+			name:      "jitter at the midpoint",
+			policy:    Policy{MaxAttempts: 3, BaseDelay: time.Second, Jitter: 0.25, Rand: func() float64 { return 0.5 }},
+			failures:  10,
+			wantCalls: 3,
+			wantWaits: []time.Duration{1 * time.Second, 2 * time.Second},
+			wantErr:   true,
+		},
+		{
+			name: "retry-after replaces backoff",
+			policy: Policy{MaxAttempts: 3, BaseDelay: time.Second,
+				RetryAfter: func(error) time.Duration { return 7 * time.Second }},
+			failures:  10,
+			wantCalls: 3,
+			wantWaits: []time.Duration{7 * time.Second, 7 * time.Second},
+			wantErr:   true,
+		},
+		{
+			name:      "zero value makes one attempt",
+			policy:    Policy{},
+			failures:  10,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{}
+			tt.policy.Sleep = clock.sleep
+
+			calls := 0
+			err := tt.policy.Do(context.Background(), func() error {
+				calls++
+				if calls <= tt.failures {
+					return errTransient
+				}
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !reflect.DeepEqual(clock.waits, tt.wantWaits) {
+				t.Errorf("waits = %v, want %v", clock.waits, tt.wantWaits)
+			}
+		})
+	}
+}
+
+func TestDoExhaustedError(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, Sleep: (&fakeClock{}).sleep}
+	err := policy.Do(context.Background(), func() error { return errTransient })
+
+	var exhausted *ExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("Do() error = %v, want *ExhaustedError", err)
+	}
+	if exhausted.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", exhausted.Attempts)
+	}
+	if !errors.Is(err, errTransient) {
+		t.Error("ExhaustedError doesn't unwrap to the last error")
+	}
+	if want := "max retries (3) exceeded: transient"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestDoStopsOnPermanentError(t *testing.T) {
+	errPermanent := errors.New("permanent")
+	clock := &fakeClock{}
+	policy := Policy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		Retryable:   func(err error) bool { return errors.Is(err, errTransient) },
+		Sleep:       clock.sleep,
+	}
+
+	calls := 0
+	err := policy.Do(context.Background(), func() error {
+		calls++
+		if calls == 1 {
+			return errTransient
+		}
+		return errPermanent
+	})
+
+	if err != errPermanent {
+		t.Errorf("Do() error = %v, want the permanent error unwrapped", err)
+	}
+	if calls != 2 || len(clock.waits) != 1 {
+		t.Errorf("calls = %d, waits = %d, want 2 and 1", calls, len(clock.waits))
+	}
+}
+
+func TestDoStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := &fakeClock{cancel: cancel}
+	policy := Policy{MaxAttempts: 5, BaseDelay: time.Second, Sleep: clock.sleep}
+
+	calls := 0
+	err := policy.Do(ctx, func() error {
+		calls++
+		return errTransient
+	})
+
+	if err != errTransient {
+		t.Errorf("Do() error = %v, want the last attempt's error", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestOnRetry(t *testing.T) {
+	var attempts []int
+	policy := Policy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		Sleep:       (&fakeClock{}).sleep,
+		OnRetry:     func(attempt int, _ time.Duration, _ error) { attempts = append(attempts, attempt) },
+	}
+	policy.Do(context.Background(), func() error { return errTransient })
+
+	if want := []int{1, 2}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("OnRetry attempts = %v, want %v", attempts, want)
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := SleepContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("SleepContext() on a cancelled context = %v, want context.Canceled", err)
+	}
+	if err := SleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("SleepContext() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package runlock
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether a process with pid is running. Signal 0 checks
+// for the process without signalling it; EPERM means it exists under another user.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package runlock
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259 // STILL_ACTIVE exit code of a running process
+	errorInvalidParameter          = syscall.Errno(87)
+)
+
+// processAlive reports whether a process with pid is running. OpenProcess fails
+// with ERROR_INVALID_PARAMETER for a PID that doesn't exist; other failures
+// (access denied) mean the process exists.
+func processAlive(pid int) bool {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return !errors.Is(err, errorInvalidParameter)
+	}
+	defer syscall.CloseHandle(handle)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
@@ -0,0 +1,232 @@
+// Package runlock keeps two instances from running against the same config at
+// once. The lock is a small JSON file created with O_EXCL, which works the same
+// on Windows, macOS and Linux, unlike flock.
+package runlock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AIDEV-NOTE: Overlapping runs (an hourly cron and a manual run) both refresh
+// tokens and rewrite config.toml, which can interleave into an unparseable file.
+// A lock left by a crashed run is taken over when its PID no longer runs on this
+// host or it's older than Options.StaleAfter. Two instances taking over the same
+// stale lock at the same moment can still race; the re-read before removing it
+// keeps that window to a few microseconds, which is fine for cron overlap.
+
+// Suffix is appended to the config file path to name its lock file
+const Suffix = ".lock"
+
+// defaultPollInterval is how often a waiting instance retries the lock
+const defaultPollInterval = time.Second
+
+// corruptGrace is how long an unparseable lock file is left alone, in case its
+// holder is still writing it
+const corruptGrace = 5 * time.Second
+
+// Info is what a lock file records about the instance holding it
+type Info struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// same reports whether two Infos describe the same instance
+func (i Info) same(other Info) bool {
+	return i.PID == other.PID && i.Host == other.Host && i.StartedAt.Equal(other.StartedAt)
+}
+
+// HeldError is returned by Acquire when another instance holds the lock
+type HeldError struct {
+	Path   string
+	Holder Info
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("another instance is running (PID %d, started %s)", e.Holder.PID, describeStart(e.Holder.StartedAt, time.Now()))
+}
+
+// describeStart formats a start time as "00:12:03", with the date when it wasn't today
+func describeStart(startedAt, now time.Time) string {
+	startedAt = startedAt.Local()
+	if y, m, d := now.Local().Date(); startedAt.Year() != y || startedAt.Month() != m || startedAt.Day() != d {
+		return startedAt.Format("2006-01-02 15:04:05")
+	}
+	return startedAt.Format("15:04:05")
+}
+
+// Options control how Acquire treats a lock that is already held
+type Options struct {
+	StaleAfter time.Duration     // Take over a lock older than this even if its holder still runs, 0 = never
+	Wait       time.Duration     // Keep retrying a held lock this long before giving up, 0 = give up at once
+	OnWait     func(holder Info) // Called once when Acquire starts waiting, optional
+
+	// Tests substitute these; zero values use the real clock, process table and interval
+	now          func() time.Time
+	alive        func(pid int) bool
+	pollInterval time.Duration
+}
+
+// Lock is a held lock file
+type Lock struct {
+	path string
+	info Info
+}
+
+// PathFor returns the lock file used for a config file
+func PathFor(configPath string) string {
+	return filepath.Clean(configPath) + Suffix
+}
+
+// Acquire creates the lock file at path for the current process. When another
+// live instance holds it, Acquire waits up to opts.Wait and then returns a *HeldError.
+func Acquire(path string, opts Options) (*Lock, error) {
+	if opts.now == nil {
+		opts.now = time.Now
+	}
+	if opts.alive == nil {
+		opts.alive = processAlive
+	}
+	if opts.pollInterval <= 0 {
+		opts.pollInterval = defaultPollInterval
+	}
+
+	host, _ := os.Hostname()
+	info := Info{PID: os.Getpid(), Host: host, StartedAt: opts.now()}
+	deadline := opts.now().Add(opts.Wait)
+	waiting := false
+
+	for {
+		err := create(path, info)
+		if err == nil {
+			return &Lock{path: path, info: info}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		holder, raw, err := read(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue // Released between our create and read
+		case err != nil:
+			// A lock file reads as corrupt for a moment while its holder writes it
+			if !modifiedWithin(path, corruptGrace, opts.now()) {
+				if err := removeIfUnchanged(path, raw); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		case isStale(holder, host, opts):
+			if err := removeIfUnchanged(path, raw); err != nil {
+				return nil, err
+			}
+			continue
+		case !opts.now().Before(deadline):
+			return nil, &HeldError{Path: path, Holder: holder}
+		case !waiting:
+			waiting = true
+			if opts.OnWait != nil {
+				opts.OnWait(holder)
+			}
+		}
+		time.Sleep(opts.pollInterval)
+	}
+}
+
+// create writes the lock file, failing with os.ErrExist if it's already there
+func create(path string, info Info) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("encoding lock file: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return err
+		}
+		return fmt.Errorf("creating lock file %s: %w", path, err)
+	}
+	_, writeErr := file.Write(data)
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(path)
+		return fmt.Errorf("writing lock file %s: %w", path, errors.Join(writeErr, closeErr))
+	}
+	return nil
+}
+
+// read returns the holder recorded in the lock file and the file's raw contents.
+// An unparseable file (e.g. from a crash mid-write) is reported as an error with its contents.
+func read(path string) (Info, []byte, error) {
+	var info Info
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return info, nil, err
+	}
+	if err := json.Unmarshal(raw, &info); err != nil || info.PID <= 0 {
+		return info, raw, fmt.Errorf("lock file %s is corrupt", path)
+	}
+	return info, raw, nil
+}
+
+// isStale reports whether a held lock can be taken over
+func isStale(holder Info, host string, opts Options) bool {
+	if opts.StaleAfter > 0 && opts.now().Sub(holder.StartedAt) > opts.StaleAfter {
+		return true
+	}
+	// A PID from another host (config on a network share) can't be checked
+	return holder.Host == host && !opts.alive(holder.PID)
+}
+
+// modifiedWithin reports whether the file at path was modified less than d before now
+func modifiedWithin(path string, d time.Duration, now time.Time) bool {
+	info, err := os.Stat(path)
+	return err == nil && now.Sub(info.ModTime()) < d
+}
+
+// removeIfUnchanged removes a stale lock file if it still holds raw. A lock
+// that changed in the meantime was taken over by another instance and is kept.
+func removeIfUnchanged(path string, raw []byte) error {
+	current, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading lock file %s: %w", path, err)
+	}
+	if !bytes.Equal(current, raw) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing stale lock file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Path returns the lock file
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Release removes the lock file, unless another instance has since taken it over
+func (l *Lock) Release() error {
+	holder, _, err := read(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err == nil && !holder.same(l.info) {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing lock file %s: %w", l.path, err)
+	}
+	return nil
+}
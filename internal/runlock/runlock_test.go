@@ -0,0 +1,230 @@
+package runlock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeHolder writes a lock file as another instance would
+func writeHolder(t *testing.T, path string, holder Info) {
+	t.Helper()
+	data, err := json.Marshal(holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readHolder(t *testing.T, path string) Info {
+	t.Helper()
+	holder, _, err := read(path)
+	if err != nil {
+		t.Fatalf("read() error = %v", err)
+	}
+	return holder
+}
+
+func TestAcquireAndRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml.lock")
+
+	lock, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if holder := readHolder(t, path); holder.PID != os.Getpid() || holder.StartedAt.IsZero() {
+		t.Errorf("lock file holder = %+v, want this process", holder)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("lock file should be removed on release, stat err = %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Errorf("second Release() error = %v", err)
+	}
+}
+
+func TestAcquireHeldLock(t *testing.T) {
+	host, _ := os.Hostname()
+	now := time.Date(2025, 6, 28, 0, 30, 0, 0, time.Local)
+	started := time.Date(2025, 6, 28, 0, 12, 3, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		holder     Info
+		alive      bool
+		staleAfter time.Duration
+		wantHeld   bool
+	}{
+		{"live holder", Info{PID: 1234, Host: host, StartedAt: started}, true, time.Hour, true},
+		{"holder exited", Info{PID: 1234, Host: host, StartedAt: started}, false, time.Hour, false},
+		{"older than stale age", Info{PID: 1234, Host: host, StartedAt: started}, true, 10 * time.Minute, false},
+		{"no stale age", Info{PID: 1234, Host: host, StartedAt: started.Add(-72 * time.Hour)}, true, 0, true},
+		{"other host is not checked by PID", Info{PID: 1234, Host: "studio-pc", StartedAt: started}, false, time.Hour, true},
+		{"other host past stale age", Info{PID: 1234, Host: "studio-pc", StartedAt: started}, false, 10 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml.lock")
+			writeHolder(t, path, tt.holder)
+
+			lock, err := Acquire(path, Options{
+				StaleAfter: tt.staleAfter,
+				now:        func() time.Time { return now },
+				alive: func(pid int) bool {
+					if pid != tt.holder.PID {
+						t.Errorf("alive(%d), want the holder's PID %d", pid, tt.holder.PID)
+					}
+					return tt.alive
+				},
+			})
+
+			if !tt.wantHeld {
+				if err != nil {
+					t.Fatalf("Acquire() error = %v, want the stale lock taken over", err)
+				}
+				if holder := readHolder(t, path); holder.PID != os.Getpid() {
+					t.Errorf("lock file holder PID = %d, want %d", holder.PID, os.Getpid())
+				}
+				return
+			}
+
+			var held *HeldError
+			if !errors.As(err, &held) {
+				t.Fatalf("Acquire() error = %v, want *HeldError", err)
+			}
+			if held.Holder.PID != 1234 || held.Path != path {
+				t.Errorf("HeldError = %+v, want holder PID 1234 at %s", held, path)
+			}
+			if !strings.Contains(err.Error(), "another instance is running (PID 1234, started ") {
+				t.Errorf("error = %q", err)
+			}
+			if lock != nil {
+				t.Error("Acquire() returned a lock for a held lock file")
+			}
+			if holder := readHolder(t, path); holder.PID != 1234 {
+				t.Errorf("held lock file was overwritten: %+v", holder)
+			}
+		})
+	}
+}
+
+func TestAcquireWaitsForRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml.lock")
+	host, _ := os.Hostname()
+	writeHolder(t, path, Info{PID: 1234, Host: host, StartedAt: time.Now()})
+
+	var waitedFor []int
+	polls := 0
+	lock, err := Acquire(path, Options{
+		Wait:         time.Minute,
+		OnWait:       func(holder Info) { waitedFor = append(waitedFor, holder.PID) },
+		pollInterval: time.Millisecond,
+		alive: func(pid int) bool {
+			// The holder finishes after a few polls
+			polls++
+			if polls == 3 {
+				os.Remove(path)
+			}
+			return true
+		},
+	})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer lock.Release()
+
+	if len(waitedFor) != 1 || waitedFor[0] != 1234 {
+		t.Errorf("OnWait called for %v, want once for PID 1234", waitedFor)
+	}
+}
+
+func TestAcquireWaitTimesOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml.lock")
+	host, _ := os.Hostname()
+	writeHolder(t, path, Info{PID: 1234, Host: host, StartedAt: time.Now()})
+
+	start := time.Now()
+	_, err := Acquire(path, Options{
+		Wait:         20 * time.Millisecond,
+		pollInterval: time.Millisecond,
+		alive:        func(int) bool { return true },
+	})
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("Acquire() error = %v, want *HeldError", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("gave up after %v, want at least the 20ms wait", elapsed)
+	}
+}
+
+func TestAcquireCorruptLock(t *testing.T) {
+	// Left behind by a crash mid-write, long enough ago that nobody is still writing it
+	path := filepath.Join(t.TempDir(), "config.toml.lock")
+	if err := os.WriteFile(path, []byte(`{"pid":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v, want the corrupt lock replaced", err)
+	}
+	defer lock.Release()
+	if holder := readHolder(t, path); holder.PID != os.Getpid() {
+		t.Errorf("lock file holder PID = %d, want %d", holder.PID, os.Getpid())
+	}
+}
+
+func TestReleaseKeepsTakenOverLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml.lock")
+	lock, err := Acquire(path, Options{})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// Another instance decided this lock was stale and took it over
+	writeHolder(t, path, Info{PID: 1234, Host: "studio-pc", StartedAt: time.Now()})
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if holder := readHolder(t, path); holder.PID != 1234 {
+		t.Errorf("Release() removed another instance's lock, holder now %+v", holder)
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Error("processAlive() = false for this process")
+	}
+}
+
+func TestDescribeStart(t *testing.T) {
+	now := time.Date(2025, 6, 28, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		started time.Time
+		want    string
+	}{
+		{time.Date(2025, 6, 28, 0, 12, 3, 0, time.Local), "00:12:03"},
+		{time.Date(2025, 6, 27, 23, 59, 0, 0, time.Local), "2025-06-27 23:59:00"},
+	}
+	for _, tt := range tests {
+		if got := describeStart(tt.started, now); got != tt.want {
+			t.Errorf("describeStart(%v) = %q, want %q", tt.started, got, tt.want)
+		}
+	}
+}
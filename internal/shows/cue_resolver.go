@@ -6,13 +6,22 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/dateutil"
 )
 
+// AIDEV-NOTE: {date} in cue_file_pattern selects the episode of a given air
+// date (-date). Without a date it matches anything, so a dated pattern still
+// picks the newest file on a normal run.
+
+// cueDatePlaceholderRegex matches {date} and {date:FORMAT} in a cue_file_pattern
+var cueDatePlaceholderRegex = regexp.MustCompile(`\{date(?::([^{}]+))?\}`)
+
 // CueResolver handles CUE file detection and pattern matching
 type CueResolver struct {
 	baseDir string // Base directory for CUE file searches
@@ -25,9 +34,37 @@ func NewCueResolver(baseDir string) *CueResolver {
 	}
 }
 
+// HasDatePlaceholder reports whether the show's cue_file_pattern contains a
+// {date} placeholder, so each air date resolves its own CUE file
+func HasDatePlaceholder(showCfg *config.ShowConfig) bool {
+	return showCfg.CueFileMapping == "" && cueDatePlaceholderRegex.MatchString(showCfg.CueFilePattern)
+}
+
+// ExpandCuePattern fills the date placeholders of a cue_file_pattern in:
+// {date} as YYYY-MM-DD and {date:FORMAT} in FORMAT, e.g. {date:YYYYMMDD}.
+// A zero date expands them to "*".
+func ExpandCuePattern(pattern string, date time.Time) string {
+	return cueDatePlaceholderRegex.ReplaceAllStringFunc(pattern, func(match string) string {
+		if date.IsZero() {
+			return "*"
+		}
+		if format := cueDatePlaceholderRegex.FindStringSubmatch(match)[1]; format != "" {
+			return dateutil.FormatDateWithPattern(date, format)
+		}
+		return date.Format("2006-01-02")
+	})
+}
+
 // ResolveCueFile resolves a CUE file based on the show configuration
 // Returns the absolute path to the CUE file to use
 func (cr *CueResolver) ResolveCueFile(showCfg *config.ShowConfig) (string, error) {
+	return cr.ResolveCueFileForDate(showCfg, time.Time{})
+}
+
+// ResolveCueFileForDate resolves the CUE file of the episode aired on date,
+// which fills in the {date} placeholders of cue_file_pattern. A zero date
+// resolves like ResolveCueFile.
+func (cr *CueResolver) ResolveCueFileForDate(showCfg *config.ShowConfig, date time.Time) (string, error) {
 	if showCfg == nil {
 		return "", fmt.Errorf("show configuration cannot be nil")
 	}
@@ -39,7 +76,9 @@ func (cr *CueResolver) ResolveCueFile(showCfg *config.ShowConfig) (string, error
 
 	// Pattern-based matching
 	if showCfg.CueFilePattern != "" {
-		return cr.resolvePattern(showCfg.CueFilePattern)
+		dated := *showCfg
+		dated.CueFilePattern = ExpandCuePattern(showCfg.CueFilePattern, date)
+		return cr.resolvePattern(&dated)
 	}
 
 	return "", fmt.Errorf("no CUE file source configured (cue_file_pattern or cue_file_mapping required)")
@@ -66,8 +105,12 @@ func (cr *CueResolver) resolveDirectMapping(mapping string) (string, error) {
 	return fullPath, nil
 }
 
-// resolvePattern handles glob pattern matching and finds the latest file
-func (cr *CueResolver) resolvePattern(pattern string) (string, error) {
+// resolvePattern handles glob pattern matching and picks a file, newest first.
+// cue_file_weekday keeps only files aired on that day and cue_file_index then
+// skips the newest matches (1 = second newest).
+func (cr *CueResolver) resolvePattern(showCfg *config.ShowConfig) (string, error) {
+	pattern := showCfg.CueFilePattern
+
 	// Construct the full pattern path
 	var fullPattern string
 	if filepath.IsAbs(pattern) {
@@ -86,55 +129,102 @@ func (cr *CueResolver) resolvePattern(pattern string) (string, error) {
 		return "", fmt.Errorf("no files match pattern: %s", fullPattern)
 	}
 
-	// Return the most recent file
-	latestFile, err := cr.findLatestFile(matches)
+	weekday, byWeekday, err := showCfg.CueWeekday()
 	if err != nil {
-		return "", fmt.Errorf("finding latest file: %w", err)
+		return "", fmt.Errorf("cue_file_weekday: %w", err)
 	}
 
-	return latestFile, nil
-}
-
-// findLatestFile returns the most recently modified file from a list of file paths
-func (cr *CueResolver) findLatestFile(files []string) (string, error) {
-	if len(files) == 0 {
-		return "", fmt.Errorf("no files provided")
+	// The common case: the most recent file
+	if !byWeekday && showCfg.CueFileIndex == 0 {
+		latestFile, err := cr.findLatestFile(matches)
+		if err != nil {
+			return "", fmt.Errorf("finding latest file: %w", err)
+		}
+		return latestFile, nil
 	}
 
-	// Single file case
-	if len(files) == 1 {
-		return files[0], nil
+	candidates, err := sortNewestFirst(matches)
+	if err != nil {
+		return "", fmt.Errorf("sorting matches: %w", err)
 	}
-
-	// For multiple files, sort by modification time (newest first)
-	type fileInfo struct {
-		path    string
-		modTime time.Time
+	if byWeekday {
+		candidates = filterWeekday(candidates, weekday)
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("none of the %d files matching %s aired on a %s", len(matches), fullPattern, weekday)
+		}
 	}
+	if showCfg.CueFileIndex >= len(candidates) {
+		if byWeekday {
+			return "", fmt.Errorf("cue_file_index %d needs %d %s files matching %s, found %d",
+				showCfg.CueFileIndex, showCfg.CueFileIndex+1, weekday, fullPattern, len(candidates))
+		}
+		return "", fmt.Errorf("cue_file_index %d needs %d files matching %s, found %d",
+			showCfg.CueFileIndex, showCfg.CueFileIndex+1, fullPattern, len(candidates))
+	}
+
+	return candidates[showCfg.CueFileIndex].path, nil
+}
 
-	var fileInfos []fileInfo
+// datedFile is a CUE file with its modification time
+type datedFile struct {
+	path    string
+	modTime time.Time
+}
+
+// sortNewestFirst sorts files by modification time, newest first. Files that
+// can't be accessed are skipped.
+func sortNewestFirst(files []string) ([]datedFile, error) {
+	var dated []datedFile
 	for _, file := range files {
 		info, err := os.Stat(file)
 		if err != nil {
-			// Skip files that can't be accessed
 			continue
 		}
-		fileInfos = append(fileInfos, fileInfo{
-			path:    file,
-			modTime: info.ModTime(),
-		})
+		dated = append(dated, datedFile{path: file, modTime: info.ModTime()})
 	}
-
-	if len(fileInfos) == 0 {
-		return "", fmt.Errorf("no accessible files found")
+	if len(dated) == 0 {
+		return nil, fmt.Errorf("no accessible files found")
 	}
 
-	// Sort by modification time (newest first)
-	sort.Slice(fileInfos, func(i, j int) bool {
-		return fileInfos[i].modTime.After(fileInfos[j].modTime)
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dated[i].modTime.After(dated[j].modTime)
 	})
+	return dated, nil
+}
 
-	return fileInfos[0].path, nil
+// filterWeekday keeps the files aired on weekday: the date in the file name when
+// it has one, otherwise the modification time
+func filterWeekday(files []datedFile, weekday time.Weekday) []datedFile {
+	var kept []datedFile
+	for _, file := range files {
+		aired, ok := dateFromFileName(filepath.Base(file.path))
+		if !ok {
+			aired = file.modTime
+		}
+		if aired.Weekday() == weekday {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// findLatestFile returns the most recently modified file from a list of file paths
+func (cr *CueResolver) findLatestFile(files []string) (string, error) {
+	if len(files) == 0 {
+		return "", fmt.Errorf("no files provided")
+	}
+
+	// Single file case
+	if len(files) == 1 {
+		return files[0], nil
+	}
+
+	// For multiple files, sort by modification time (newest first)
+	sorted, err := sortNewestFirst(files)
+	if err != nil {
+		return "", err
+	}
+	return sorted[0].path, nil
 }
 
 // ValidateCueFile performs basic validation on a CUE file
@@ -223,6 +313,40 @@ func (cr *CueResolver) FindCueFilesByPattern(pattern string) ([]string, error) {
 	return validFiles, nil
 }
 
+// MatchesFile reports whether showCfg's CUE source covers path, using the same
+// rules as ResolveCueFile: a mapping names one file and takes precedence,
+// otherwise the pattern is matched with filepath.Glob semantics. Relative paths
+// are taken as inside the base directory. The file doesn't have to exist, and
+// cue_file_weekday/cue_file_index, which choose among matches, are ignored.
+func (cr *CueResolver) MatchesFile(showCfg *config.ShowConfig, path string) (bool, error) {
+	if showCfg == nil {
+		return false, fmt.Errorf("show configuration cannot be nil")
+	}
+	fullPath := cr.fullPath(path)
+
+	if showCfg.CueFileMapping != "" {
+		return cr.fullPath(showCfg.CueFileMapping) == fullPath, nil
+	}
+	if showCfg.CueFilePattern == "" {
+		return false, nil
+	}
+
+	fullPattern := cr.fullPath(ExpandCuePattern(showCfg.CueFilePattern, time.Time{}))
+	matched, err := filepath.Match(fullPattern, fullPath)
+	if err != nil {
+		return false, fmt.Errorf("invalid glob pattern %s: %w", fullPattern, err)
+	}
+	return matched, nil
+}
+
+// fullPath resolves a relative path or pattern against the base directory
+func (cr *CueResolver) fullPath(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(cr.baseDir, path)
+}
+
 // GetFileAge returns the age of a file as a time.Duration
 func (cr *CueResolver) GetFileAge(filePath string) (time.Duration, error) {
 	info, err := os.Stat(filePath)
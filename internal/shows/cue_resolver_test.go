@@ -3,6 +3,7 @@ package shows
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -526,4 +527,219 @@ func TestIsFileNewer(t *testing.T) {
 	if isNewer {
 		t.Error("IsFileNewer() should return false for older file")
 	}
-}
\ No newline at end of file
+}
+// writeDriveWeek writes one CUE file per name, each modified at noon on the given
+// day, and returns the directory
+func writeDriveWeek(t *testing.T, files map[string]time.Time) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	for name, day := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte("TEST"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		modTime := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.Local)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set modification time: %v", err)
+		}
+	}
+	return tmpDir
+}
+
+func TestResolvePatternIndexAndWeekday(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.Local) }
+
+	// Monday 23 June to Friday 27 June 2025, dated in the names
+	week := writeDriveWeek(t, map[string]time.Time{
+		"DRIVE_20250623.cue": day(23),
+		"DRIVE_20250624.cue": day(24),
+		"DRIVE_20250625.cue": day(25),
+		"DRIVE_20250626.cue": day(26),
+		"DRIVE_20250627.cue": day(27),
+	})
+	// The same week without dates in the names, so weekdays come from mtimes
+	undated := writeDriveWeek(t, map[string]time.Time{
+		"drive-a.cue": day(23),
+		"drive-b.cue": day(24),
+		"drive-c.cue": day(25),
+		"drive-d.cue": day(26),
+		"drive-e.cue": day(27),
+	})
+	// Saturday to Thursday across a week boundary, no Friday
+	boundary := writeDriveWeek(t, map[string]time.Time{
+		"DRIVE_20250628.cue": day(28),
+		"DRIVE_20250629.cue": day(29),
+		"DRIVE_20250630.cue": day(30),
+		"DRIVE_20250701.cue": day(30).AddDate(0, 0, 1),
+		"DRIVE_20250703.cue": day(30).AddDate(0, 0, 3),
+	})
+
+	tests := []struct {
+		name      string
+		dir       string
+		showCfg   config.ShowConfig
+		wantFile  string
+		wantError string
+	}{
+		{
+			name:     "newest by default",
+			dir:      week,
+			showCfg:  config.ShowConfig{CueFilePattern: "DRIVE_*.cue"},
+			wantFile: "DRIVE_20250627.cue",
+		},
+		{
+			name:     "second newest",
+			dir:      week,
+			showCfg:  config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileIndex: 1},
+			wantFile: "DRIVE_20250626.cue",
+		},
+		{
+			name:     "oldest of five",
+			dir:      week,
+			showCfg:  config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileIndex: 4},
+			wantFile: "DRIVE_20250623.cue",
+		},
+		{
+			name:      "index past the matches",
+			dir:       week,
+			showCfg:   config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileIndex: 5},
+			wantError: "needs 6 files",
+		},
+		{
+			name:     "friday",
+			dir:      week,
+			showCfg:  config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileWeekday: "friday"},
+			wantFile: "DRIVE_20250627.cue",
+		},
+		{
+			name:     "abbreviated weekday",
+			dir:      week,
+			showCfg:  config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileWeekday: "Tue"},
+			wantFile: "DRIVE_20250624.cue",
+		},
+		{
+			name:      "weekday and index combine",
+			dir:       week,
+			showCfg:   config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileWeekday: "friday", CueFileIndex: 1},
+			wantError: "needs 2 Friday files",
+		},
+		{
+			name:     "weekday from modification time",
+			dir:      undated,
+			showCfg:  config.ShowConfig{CueFilePattern: "drive-*.cue", CueFileWeekday: "wednesday"},
+			wantFile: "drive-c.cue",
+		},
+		{
+			name:      "no friday across the week boundary",
+			dir:       boundary,
+			showCfg:   config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileWeekday: "friday"},
+			wantError: "aired on a Friday",
+		},
+		{
+			name:     "sunday across the week boundary",
+			dir:      boundary,
+			showCfg:  config.ShowConfig{CueFilePattern: "DRIVE_*.cue", CueFileWeekday: "sunday"},
+			wantFile: "DRIVE_20250629.cue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewCueResolver(tt.dir)
+			result, err := resolver.ResolveCueFile(&tt.showCfg)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("ResolveCueFile() error = %v, want it to contain %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveCueFile() error = %v", err)
+			}
+			if filepath.Base(result) != tt.wantFile {
+				t.Errorf("ResolveCueFile() = %s, want %s", filepath.Base(result), tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestResolveCueFileForDate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.Local) }
+
+	// Modified in the opposite order to the names, so only the date can pick the file
+	dir := writeDriveWeek(t, map[string]time.Time{
+		"DRIVE_20250623.cue":   day(27),
+		"DRIVE_20250625.cue":   day(25),
+		"DRIVE_20250627.cue":   day(23),
+		"drive-2025-06-25.cue": day(25),
+	})
+
+	tests := []struct {
+		name      string
+		pattern   string
+		date      time.Time
+		wantFile  string
+		wantError string
+	}{
+		{
+			name:     "date with format",
+			pattern:  "DRIVE_{date:YYYYMMDD}.cue",
+			date:     day(25),
+			wantFile: "DRIVE_20250625.cue",
+		},
+		{
+			name:     "plain date is YYYY-MM-DD",
+			pattern:  "drive-{date}.cue",
+			date:     day(25),
+			wantFile: "drive-2025-06-25.cue",
+		},
+		{
+			name:     "no date takes the newest",
+			pattern:  "DRIVE_{date:YYYYMMDD}.cue",
+			wantFile: "DRIVE_20250623.cue",
+		},
+		{
+			name:      "no episode that day",
+			pattern:   "DRIVE_{date:YYYYMMDD}.cue",
+			date:      day(24),
+			wantError: "DRIVE_20250624.cue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewCueResolver(dir)
+			got, err := resolver.ResolveCueFileForDate(&config.ShowConfig{CueFilePattern: tt.pattern}, tt.date)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("ResolveCueFileForDate() error = %v, want one mentioning %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveCueFileForDate() error = %v", err)
+			}
+			if filepath.Base(got) != tt.wantFile {
+				t.Errorf("ResolveCueFileForDate() = %s, want %s", filepath.Base(got), tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestHasDatePlaceholder(t *testing.T) {
+	tests := []struct {
+		showCfg config.ShowConfig
+		want    bool
+	}{
+		{config.ShowConfig{CueFilePattern: "DRIVE_{date:YYYYMMDD}*.cue"}, true},
+		{config.ShowConfig{CueFilePattern: "drive-{date}.cue"}, true},
+		{config.ShowConfig{CueFilePattern: "DRIVE_*.cue"}, false},
+		{config.ShowConfig{CueFileMapping: "drive-{date}.cue"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := HasDatePlaceholder(&tt.showCfg); got != tt.want {
+			t.Errorf("HasDatePlaceholder(%+v) = %v, want %v", tt.showCfg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package shows
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// AIDEV-NOTE: Backfill needs every archived CUE file of a show, not just the
+// newest, plus the date each episode aired. Loggers name files after the air
+// date (MYR_20250628.cue, Show-2025-06-28.cue), which survives copying between
+// machines; the modification time is only a fallback.
+
+var (
+	// fileDateRegex matches year-first dates: 20250628, 2025-06-28, 2025_06_28, 2025.06.28
+	fileDateRegex = regexp.MustCompile(`(?:^|\D)(\d{4})[-_.]?(\d{2})[-_.]?(\d{2})(?:\D|$)`)
+
+	// usFileDateRegex matches month-first dates: 06-28-2025, 06_28_2025, 06.28.2025
+	usFileDateRegex = regexp.MustCompile(`(?:^|\D)(\d{2})[-_.](\d{2})[-_.](\d{4})(?:\D|$)`)
+)
+
+// Episode is an archived CUE file and the date the episode aired
+type Episode struct {
+	CueFile      string
+	Date         time.Time
+	DateFromName bool // False when the date fell back to the modification time
+}
+
+// FindEpisodes lists every CUE file of a show, oldest first. Files are matched
+// with the show's cue_file_pattern, in dir when given, otherwise in the CUE
+// directory. With a dir and no pattern every *.cue file in dir is an episode.
+func (cr *CueResolver) FindEpisodes(showCfg *config.ShowConfig, dir string) ([]Episode, error) {
+	pattern := ExpandCuePattern(showCfg.CueFilePattern, time.Time{})
+	switch {
+	case dir != "" && pattern != "":
+		pattern = filepath.Join(dir, filepath.Base(pattern))
+	case dir != "":
+		pattern = filepath.Join(dir, "*.cue")
+	case pattern == "":
+		return nil, fmt.Errorf("show has no cue_file_pattern to find archived episodes with; give a directory instead")
+	}
+
+	files, err := cr.FindCueFilesByPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make([]Episode, 0, len(files))
+	for _, file := range files {
+		date, fromName, err := EpisodeDate(file)
+		if err != nil {
+			return nil, err
+		}
+		episodes = append(episodes, Episode{CueFile: file, Date: date, DateFromName: fromName})
+	}
+
+	sort.SliceStable(episodes, func(i, j int) bool {
+		if !episodes[i].Date.Equal(episodes[j].Date) {
+			return episodes[i].Date.Before(episodes[j].Date)
+		}
+		return episodes[i].CueFile < episodes[j].CueFile
+	})
+	return episodes, nil
+}
+
+// EpisodeDate derives the date an episode aired from a date in its file name,
+// falling back to the file's modification time. fromName reports which was used.
+func EpisodeDate(path string) (date time.Time, fromName bool, err error) {
+	if date, ok := dateFromFileName(filepath.Base(path)); ok {
+		return date, true, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("getting file info for %s: %w", path, err)
+	}
+	modTime := info.ModTime()
+	return time.Date(modTime.Year(), modTime.Month(), modTime.Day(), 0, 0, 0, 0, time.UTC), false, nil
+}
+
+// dateFromFileName finds a valid calendar date in a file name
+func dateFromFileName(name string) (time.Time, bool) {
+	if m := fileDateRegex.FindStringSubmatch(name); m != nil {
+		if date, ok := validDate(m[1], m[2], m[3]); ok {
+			return date, true
+		}
+	}
+	if m := usFileDateRegex.FindStringSubmatch(name); m != nil {
+		if date, ok := validDate(m[3], m[1], m[2]); ok {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// validDate builds a date, rejecting impossible ones like 2025-13-40 or 2025-02-30
+func validDate(year, month, day string) (time.Time, bool) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if date.Year() != y || int(date.Month()) != m || date.Day() != d {
+		return time.Time{}, false
+	}
+	return date, true
+}
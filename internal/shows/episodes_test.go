@@ -0,0 +1,130 @@
+package shows
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+func TestEpisodeDateFromName(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantDate string
+		wantOK   bool
+	}{
+		{"MYR_20250628.cue", "2025-06-28", true},
+		{"Show-2025-06-28.cue", "2025-06-28", true},
+		{"show_2025_06_28_final.cue", "2025-06-28", true},
+		{"2025.06.28.cue", "2025-06-28", true},
+		{"NNW 06-28-2025.cue", "2025-06-28", true},
+		{"MYR_20251340.cue", "", false},  // month 13
+		{"MYR_20250230.cue", "", false},  // Feb 30
+		{"MYR_120250628.cue", "", false}, // part of a longer number
+		{"latest.cue", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, ok := dateFromFileName(tt.name)
+			if ok != tt.wantOK {
+				t.Fatalf("dateFromFileName(%q) ok = %v, want %v", tt.name, ok, tt.wantOK)
+			}
+			if ok && date.Format("2006-01-02") != tt.wantDate {
+				t.Errorf("dateFromFileName(%q) = %s, want %s", tt.name, date.Format("2006-01-02"), tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestEpisodeDateFallsBackToModTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "latest.cue")
+	if err := os.WriteFile(path, []byte("TEST"), 0644); err != nil {
+		t.Fatalf("writing CUE file: %v", err)
+	}
+	modTime := time.Date(2024, 11, 3, 21, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("setting modification time: %v", err)
+	}
+
+	date, fromName, err := EpisodeDate(path)
+	if err != nil {
+		t.Fatalf("EpisodeDate() error = %v", err)
+	}
+	if fromName {
+		t.Error("EpisodeDate() fromName = true, want false")
+	}
+	want := time.Date(modTime.Local().Year(), modTime.Local().Month(), modTime.Local().Day(), 0, 0, 0, 0, time.UTC)
+	if !date.Equal(want) {
+		t.Errorf("EpisodeDate() = %v, want %v", date, want)
+	}
+}
+
+func TestFindEpisodes(t *testing.T) {
+	cueDir := t.TempDir()
+	archiveDir := t.TempDir()
+	for _, name := range []string{"MYR_20250705.cue", "MYR_20250628.cue", "MYR_20250712.cue", "OTHER_20250601.cue"} {
+		if err := os.WriteFile(filepath.Join(cueDir, name), []byte("TEST"), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"MYR_20240101.cue", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(archiveDir, name), []byte("TEST"), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	resolver := NewCueResolver(cueDir)
+
+	tests := []struct {
+		name      string
+		showCfg   *config.ShowConfig
+		dir       string
+		wantFiles []string
+		wantError bool
+	}{
+		{
+			name:      "pattern in CUE directory, oldest first",
+			showCfg:   &config.ShowConfig{CueFilePattern: "MYR_*.cue"},
+			wantFiles: []string{"MYR_20250628.cue", "MYR_20250705.cue", "MYR_20250712.cue"},
+		},
+		{
+			name:      "pattern in another directory",
+			showCfg:   &config.ShowConfig{CueFilePattern: "MYR_*.cue"},
+			dir:       archiveDir,
+			wantFiles: []string{"MYR_20240101.cue"},
+		},
+		{
+			name:      "directory without pattern takes every CUE file",
+			showCfg:   &config.ShowConfig{CueFileMapping: "latest.cue"},
+			dir:       cueDir,
+			wantFiles: []string{"OTHER_20250601.cue", "MYR_20250628.cue", "MYR_20250705.cue", "MYR_20250712.cue"},
+		},
+		{
+			name:      "no pattern and no directory",
+			showCfg:   &config.ShowConfig{CueFileMapping: "latest.cue"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			episodes, err := resolver.FindEpisodes(tt.showCfg, tt.dir)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("FindEpisodes() error = %v, wantError %v", err, tt.wantError)
+			}
+			if len(episodes) != len(tt.wantFiles) {
+				t.Fatalf("FindEpisodes() = %d episodes, want %d", len(episodes), len(tt.wantFiles))
+			}
+			for i, episode := range episodes {
+				if filepath.Base(episode.CueFile) != tt.wantFiles[i] {
+					t.Errorf("episode %d = %s, want %s", i, filepath.Base(episode.CueFile), tt.wantFiles[i])
+				}
+				if !episode.DateFromName {
+					t.Errorf("episode %d date not taken from its name", i)
+				}
+			}
+		})
+	}
+}
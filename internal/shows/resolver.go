@@ -5,9 +5,13 @@ package shows
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/dateutil"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
 )
 
 // Resolver handles show alias resolution and lookup operations
@@ -45,13 +49,13 @@ func (r *Resolver) buildAliasMap() error {
 		r.showKeys = append(r.showKeys, showKey)
 		
 		// Add primary show key (case-insensitive)
-		normalizedKey := strings.ToLower(showKey)
+		normalizedKey := config.CanonicalShowKey(showKey)
 		r.aliasMap[normalizedKey] = showKey
 		conflictCheck[normalizedKey] = append(conflictCheck[normalizedKey], showKey)
 
 		// Add each alias (case-insensitive)
 		for _, alias := range showConfig.Aliases {
-			normalizedAlias := strings.ToLower(alias)
+			normalizedAlias := config.CanonicalShowKey(alias)
 			r.aliasMap[normalizedAlias] = showKey
 			conflictCheck[normalizedAlias] = append(conflictCheck[normalizedAlias], showKey)
 		}
@@ -76,7 +80,7 @@ func (r *Resolver) FindShowConfig(nameOrAlias string) *config.ShowConfig {
 	}
 
 	// Normalize the input for case-insensitive matching
-	normalized := strings.ToLower(strings.TrimSpace(nameOrAlias))
+	normalized := config.CanonicalShowKey(nameOrAlias)
 	
 	// Look up in alias map
 	if showKey, exists := r.aliasMap[normalized]; exists {
@@ -97,7 +101,7 @@ func (r *Resolver) FindShowKey(nameOrAlias string) string {
 	}
 
 	// Normalize the input for case-insensitive matching
-	normalized := strings.ToLower(strings.TrimSpace(nameOrAlias))
+	normalized := config.CanonicalShowKey(nameOrAlias)
 	
 	// Look up in alias map
 	if showKey, exists := r.aliasMap[normalized]; exists {
@@ -132,6 +136,46 @@ func (r *Resolver) ListEnabledShows(sortByPriority bool) []string {
 	return enabled
 }
 
+// ListGroupShows returns the enabled show keys whose show_group is group,
+// optionally sorted by priority
+func (r *Resolver) ListGroupShows(group string, sortByPriority bool) []string {
+	var members []string
+	for _, showKey := range r.ListEnabledShows(sortByPriority) {
+		if r.config.Shows[showKey].ShowGroup == group {
+			members = append(members, showKey)
+		}
+	}
+	return members
+}
+
+// IsGroupAtomic reports whether a show group is published all-or-nothing.
+// ValidateShows ensures all members agree.
+func (r *Resolver) IsGroupAtomic(group string) bool {
+	for _, showConfig := range r.config.Shows {
+		if showConfig.ShowGroup == group && showConfig.GroupAtomic {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchShowsForFile returns the enabled shows whose CUE source covers filename,
+// in processing order. A bare file name is looked up as if it were in the CUE
+// directory. More than one match means the shows would publish the same
+// tracklist. Shows with invalid patterns are skipped; -lint reports those.
+func (r *Resolver) MatchShowsForFile(filename string) []string {
+	cueResolver := NewCueResolver(r.config.CueDirectory())
+
+	var matches []string
+	for _, showKey := range r.ListEnabledShows(true) {
+		showConfig := r.config.Shows[showKey]
+		if matched, err := cueResolver.MatchesFile(&showConfig, filename); err == nil && matched {
+			matches = append(matches, showKey)
+		}
+	}
+	return matches
+}
+
 // sortByPriority sorts show keys by their priority (higher priority first)
 func (r *Resolver) sortByPriority(showKeys []string) []string {
 	// Create a copy and sort it
@@ -177,6 +221,21 @@ func (r *Resolver) ValidateShows() error {
 			errors = append(errors, fmt.Sprintf("show '%s': no CUE file source configured (cue_file_pattern or cue_file_mapping required)", showKey))
 		}
 
+		// Validate latest-N and weekday selection, which pick among pattern matches
+		if showConfig.CueFileIndex < 0 {
+			errors = append(errors, fmt.Sprintf("show '%s': cue_file_index must be non-negative, got %d", showKey, showConfig.CueFileIndex))
+		}
+		if _, _, err := showConfig.CueWeekday(); err != nil {
+			errors = append(errors, fmt.Sprintf("show '%s': cue_file_weekday: %v", showKey, err))
+		}
+		if (showConfig.CueFileIndex > 0 || showConfig.CueFileWeekday != "") && showConfig.CueFilePattern == "" {
+			errors = append(errors, fmt.Sprintf("show '%s': cue_file_index and cue_file_weekday require cue_file_pattern", showKey))
+		}
+
+		if _, err := ParsePublishWindow(&showConfig); err != nil {
+			errors = append(errors, fmt.Sprintf("show '%s': %v", showKey, err))
+		}
+
 		// Validate that show name pattern is provided
 		if strings.TrimSpace(showConfig.ShowNamePattern) == "" {
 			errors = append(errors, fmt.Sprintf("show '%s': show_name_pattern is required", showKey))
@@ -191,11 +250,163 @@ func (r *Resolver) ValidateShows() error {
 		if showConfig.TemplateName != "" && showConfig.CustomTemplate != "" {
 			errors = append(errors, fmt.Sprintf("show '%s': cannot specify both template and custom_template", showKey))
 		}
+		if showConfig.MaxLinks < 0 {
+			errors = append(errors, fmt.Sprintf("show '%s': max_links must be non-negative, got %d", showKey, showConfig.MaxLinks))
+		}
+
+		// Validate update form fields - the description is always set by the updater
+		if _, ok := showConfig.ExtraUpdateFields["description"]; ok {
+			errors = append(errors, fmt.Sprintf("show '%s': extra_update_fields cannot set description", showKey))
+		}
+		if _, ok := showConfig.ExtraUpdateFields["name"]; ok && showConfig.PreserveName {
+			errors = append(errors, fmt.Sprintf("show '%s': extra_update_fields cannot set name when preserve_name is enabled", showKey))
+		}
+		if showConfig.UpdateName {
+			if strings.TrimSpace(showConfig.NameTemplate) == "" {
+				errors = append(errors, fmt.Sprintf("show '%s': update_name requires name_template", showKey))
+			}
+			if showConfig.PreserveName {
+				errors = append(errors, fmt.Sprintf("show '%s': update_name and preserve_name cannot both be enabled", showKey))
+			}
+			if _, ok := showConfig.ExtraUpdateFields["name"]; ok {
+				errors = append(errors, fmt.Sprintf("show '%s': extra_update_fields cannot set name when update_name is enabled", showKey))
+			}
+		}
+		if showConfig.MaxGenreTags < 0 || showConfig.MaxGenreTags > constants.MixcloudMaxTags {
+			errors = append(errors, fmt.Sprintf("show '%s': max_genre_tags must be between 0 and %d (Mixcloud's limit), got %d", showKey, constants.MixcloudMaxTags, showConfig.MaxGenreTags))
+		}
+		if showConfig.TagsFromGenres {
+			for name := range showConfig.ExtraUpdateFields {
+				if strings.HasPrefix(name, "tags-") {
+					errors = append(errors, fmt.Sprintf("show '%s': extra_update_fields cannot set %s when tags_from_genres is enabled", showKey, name))
+				}
+			}
+		}
+		if _, ok := showConfig.ExtraUpdateFields[""]; ok {
+			errors = append(errors, fmt.Sprintf("show '%s': extra_update_fields has an empty field name", showKey))
+		}
+
+		// Validate empty tracklist outcome
+		switch showConfig.EmptyTracklistAction() {
+		case config.OnEmptyTracklistFail, config.OnEmptyTracklistSkip, config.OnEmptyTracklistPublishPlaceholder:
+		default:
+			errors = append(errors, fmt.Sprintf("show '%s': on_empty_tracklist must be \"fail\", \"skip\" or \"publish_placeholder\", got %q", showKey, showConfig.OnEmptyTracklist))
+		}
+
+		if !config.ValidTrackOrder(showConfig.TrackOrder) {
+			errors = append(errors, fmt.Sprintf("show '%s': track_order must be \"chronological\" or \"reverse\", got %q", showKey, showConfig.TrackOrder))
+		}
+
+		if !config.ValidTrackStyle(showConfig.TrackStyle) {
+			errors = append(errors, fmt.Sprintf("show '%s': track_style must be \"title-first\" or \"artist-first\", got %q", showKey, showConfig.TrackStyle))
+		}
+
+		if showConfig.DateInputFormat != "" {
+			if err := dateutil.ValidateDateInputFormat(showConfig.DateInputFormat); err != nil {
+				errors = append(errors, fmt.Sprintf("show '%s': date_input_format %v", showKey, err))
+			}
+		}
+
+		if showConfig.CollapseMaxTitles < 0 {
+			errors = append(errors, fmt.Sprintf("show '%s': collapse_max_titles must be non-negative, got %d", showKey, showConfig.CollapseMaxTitles))
+		}
+
+		if showConfig.MixcloudUsername != "" {
+			if err := config.ValidateMixcloudUsername(showConfig.MixcloudUsername); err != nil {
+				errors = append(errors, fmt.Sprintf("show '%s': mixcloud_username %q: %v", showKey, showConfig.MixcloudUsername, err))
+			}
+		}
+
+		if showConfig.DescriptionMaxLength < 0 || showConfig.DescriptionMaxLength > constants.MixcloudDescriptionLimit {
+			errors = append(errors, fmt.Sprintf("show '%s': description_max_length must be between 0 and %d (Mixcloud's limit), got %d", showKey, constants.MixcloudDescriptionLimit, showConfig.DescriptionMaxLength))
+		}
+
+		// Validate track gap check
+		if showConfig.MaxTrackGapMinutes < 0 {
+			errors = append(errors, fmt.Sprintf("show '%s': max_track_gap_minutes must be non-negative, got %d", showKey, showConfig.MaxTrackGapMinutes))
+		}
+		switch showConfig.TrackGapAction() {
+		case config.GapActionWarn, config.GapActionFail:
+		default:
+			errors = append(errors, fmt.Sprintf("show '%s': gap_action must be \"warn\" or \"fail\", got %q", showKey, showConfig.GapAction))
+		}
+
+		// Validate upload length check
+		if showConfig.DurationTolerancePercent < 0 {
+			errors = append(errors, fmt.Sprintf("show '%s': duration_tolerance_percent must be non-negative, got %d", showKey, showConfig.DurationTolerancePercent))
+		}
+		if showConfig.DurationTailMinutes < 0 {
+			errors = append(errors, fmt.Sprintf("show '%s': duration_tail_minutes must be non-negative, got %d", showKey, showConfig.DurationTailMinutes))
+		}
+		switch showConfig.LengthMismatchAction() {
+		case config.GapActionWarn, config.GapActionFail:
+		default:
+			errors = append(errors, fmt.Sprintf("show '%s': duration_mismatch_action must be \"warn\" or \"fail\", got %q", showKey, showConfig.DurationMismatchAction))
+		}
+
+		if _, err := showConfig.TrackTimeOffset(); err != nil {
+			errors = append(errors, fmt.Sprintf("show '%s': time_offset: %v", showKey, err))
+		}
+		if _, err := messages.ForLocale(showConfig.Locale); err != nil {
+			errors = append(errors, fmt.Sprintf("show '%s': locale: %v", showKey, err))
+		}
+
+		// Validate multi-part uploads
+		if _, err := showConfig.SplitOffsets(); err != nil {
+			errors = append(errors, fmt.Sprintf("show '%s': split_at: %v", showKey, err))
+		}
+		if len(showConfig.SplitAt) > 0 {
+			if showConfig.PartURLSuffixPattern != "" && !strings.Contains(showConfig.PartURLSuffixPattern, "{n}") {
+				errors = append(errors, fmt.Sprintf("show '%s': part_url_suffix_pattern must contain {n}, got %q", showKey, showConfig.PartURLSuffixPattern))
+			}
+			// Renames and group rollbacks work on a single URL per show
+			if showConfig.UpdateName {
+				errors = append(errors, fmt.Sprintf("show '%s': split_at and update_name cannot both be enabled", showKey))
+			}
+			if showConfig.GroupAtomic {
+				errors = append(errors, fmt.Sprintf("show '%s': split_at and group_atomic cannot both be enabled", showKey))
+			}
+		}
 	}
 
+	errors = append(errors, r.validateGroups()...)
+
 	if len(errors) > 0 {
 		return fmt.Errorf("show validation failed: %s", strings.Join(errors, "; "))
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// validateGroups checks that group_atomic is only set on grouped shows and that
+// every member of a group agrees on it
+func (r *Resolver) validateGroups() []string {
+	var errors []string
+	atomicByGroup := make(map[string]bool)
+	firstMember := make(map[string]string)
+
+	showKeys := r.ListShows()
+	sort.Strings(showKeys) // Deterministic error messages
+	for _, showKey := range showKeys {
+		showConfig := r.config.Shows[showKey]
+		if showConfig.ShowGroup == "" {
+			if showConfig.GroupAtomic {
+				errors = append(errors, fmt.Sprintf("show '%s': group_atomic requires show_group", showKey))
+			}
+			continue
+		}
+
+		first, seen := firstMember[showConfig.ShowGroup]
+		if !seen {
+			firstMember[showConfig.ShowGroup] = showKey
+			atomicByGroup[showConfig.ShowGroup] = showConfig.GroupAtomic
+			continue
+		}
+		if atomicByGroup[showConfig.ShowGroup] != showConfig.GroupAtomic {
+			errors = append(errors, fmt.Sprintf("show '%s': group_atomic differs from show '%s' in group '%s'",
+				showKey, first, showConfig.ShowGroup))
+		}
+	}
+
+	return errors
+}
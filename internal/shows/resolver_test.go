@@ -1,6 +1,10 @@
 package shows
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
@@ -176,6 +180,73 @@ func TestListShows(t *testing.T) {
 	}
 }
 
+func TestUnicodeShowKeys(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[shows."Música Nueva"]
+show_name_pattern = "Música Nueva"
+aliases = ["Nueva"]
+enabled = true
+priority = 1
+
+[shows."LATE NIGHT"]
+show_name_pattern = "Late Night"
+enabled = true
+priority = 2
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	resolver, err := NewResolver(cfg)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	if got := resolver.ListEnabledShows(true); !reflect.DeepEqual(got, []string{"late night", "música nueva"}) {
+		t.Errorf("ListEnabledShows() = %q, want the canonical keys by priority", got)
+	}
+
+	tests := []struct {
+		input   string
+		wantKey string
+	}{
+		{"Música Nueva", "música nueva"},
+		{"MÚSICA NUEVA", "música nueva"},
+		{"Mu\u0301sica Nueva", "música nueva"},
+		{"  música   nueva ", "música nueva"},
+		{"nueva", "música nueva"},
+		{"late night", "late night"},
+		{"Late Night", "late night"},
+		{"latenight", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := resolver.FindShowKey(tt.input); got != tt.wantKey {
+				t.Errorf("FindShowKey(%q) = %q, want %q", tt.input, got, tt.wantKey)
+			}
+			showCfg := resolver.FindShowConfig(tt.input)
+			if tt.wantKey == "" {
+				if showCfg != nil {
+					t.Errorf("FindShowConfig(%q) = %+v, want nil", tt.input, showCfg)
+				}
+				return
+			}
+			if showCfg == nil || showCfg.ShowNamePattern == "" {
+				t.Errorf("FindShowConfig(%q) = %+v, want the configured show", tt.input, showCfg)
+			}
+		})
+	}
+
+	// Messages name shows as the config writes them
+	if err := resolver.NotFoundError("musica"); err == nil || !strings.Contains(err.Error(), "Música Nueva") {
+		t.Errorf("NotFoundError() = %v, want a suggestion of Música Nueva", err)
+	}
+}
+
 func TestListEnabledShows(t *testing.T) {
 	cfg := &config.Config{
 		Shows: map[string]config.ShowConfig{
@@ -344,6 +415,19 @@ func TestValidateShows(t *testing.T) {
 			wantError: true,
 			errorText: "priority must be non-negative",
 		},
+		{
+			name: "negative max_links",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "Invalid Show",
+					MaxLinks:        -1,
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "max_links must be non-negative",
+		},
 		{
 			name: "both template and custom template",
 			shows: map[string]config.ShowConfig{
@@ -359,6 +443,410 @@ func TestValidateShows(t *testing.T) {
 			wantError: true,
 			errorText: "cannot specify both template and custom_template",
 		},
+		{
+			name: "invalid on_empty_tracklist",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:   "*.cue",
+					ShowNamePattern:  "Invalid Show",
+					OnEmptyTracklist: "ignore",
+					Enabled:          true,
+				},
+			},
+			wantError: true,
+			errorText: "on_empty_tracklist must be",
+		},
+		{
+			name: "group_atomic without show_group",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "Invalid Show",
+					GroupAtomic:     true,
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "group_atomic requires show_group",
+		},
+		{
+			name: "group members disagree on group_atomic",
+			shows: map[string]config.ShowConfig{
+				"fest-fri": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "Friday",
+					ShowGroup:       "fest",
+					GroupAtomic:     true,
+					Enabled:         true,
+				},
+				"fest-sat": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "Saturday",
+					ShowGroup:       "fest",
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "group_atomic differs from show 'fest-fri' in group 'fest'",
+		},
+		{
+			name: "extra_update_fields sets description",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:    "*.cue",
+					ShowNamePattern:   "Invalid Show",
+					ExtraUpdateFields: map[string]string{"description": "x"},
+					Enabled:           true,
+				},
+			},
+			wantError: true,
+			errorText: "extra_update_fields cannot set description",
+		},
+		{
+			name: "extra_update_fields sets name with preserve_name",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:    "*.cue",
+					ShowNamePattern:   "Invalid Show",
+					ExtraUpdateFields: map[string]string{"name": "{station} Show"},
+					PreserveName:      true,
+					Enabled:           true,
+				},
+			},
+			wantError: true,
+			errorText: "extra_update_fields cannot set name when preserve_name is enabled",
+		},
+		{
+			name: "extra_update_fields with name but no preserve_name",
+			shows: map[string]config.ShowConfig{
+				"valid-show": {
+					CueFilePattern:    "*.cue",
+					ShowNamePattern:   "Valid Show",
+					ExtraUpdateFields: map[string]string{"name": "{station} Show", "unlisted": "1"},
+					Enabled:           true,
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "max_genre_tags over Mixcloud's limit",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "Invalid Show",
+					TagsFromGenres:  true,
+					MaxGenreTags:    6,
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "max_genre_tags must be between 0 and 5",
+		},
+		{
+			name: "extra_update_fields sets tags with tags_from_genres",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:    "*.cue",
+					ShowNamePattern:   "Invalid Show",
+					ExtraUpdateFields: map[string]string{"tags-0-tag": "Radio"},
+					TagsFromGenres:    true,
+					Enabled:           true,
+				},
+			},
+			wantError: true,
+			errorText: "extra_update_fields cannot set tags-0-tag when tags_from_genres is enabled",
+		},
+		{
+			name: "update_name without name_template",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "Invalid Show",
+					UpdateName:      true,
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "update_name requires name_template",
+		},
+		{
+			name: "update_name with preserve_name",
+			shows: map[string]config.ShowConfig{
+				"invalid-show": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "Invalid Show",
+					UpdateName:      true,
+					NameTemplate:    "Invalid Show - {date}",
+					PreserveName:    true,
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "update_name and preserve_name cannot both be enabled",
+		},
+		{
+			name: "update_name with name_template",
+			shows: map[string]config.ShowConfig{
+				"valid-show": {
+					CueFilePattern:  "*.cue",
+					ShowNamePattern: "NNW {date}",
+					UpdateName:      true,
+					NameTemplate:    "The Newer New Wave Show - {date:MMMM D, YYYY}",
+					Enabled:         true,
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown cue_file_weekday",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:  "DRIVE_*.cue",
+					ShowNamePattern: "Drive",
+					CueFileWeekday:  "fryday",
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: `cue_file_weekday: unknown weekday "fryday"`,
+		},
+		{
+			name: "negative cue_file_index",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:  "DRIVE_*.cue",
+					ShowNamePattern: "Drive",
+					CueFileIndex:    -1,
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "cue_file_index must be non-negative",
+		},
+		{
+			name: "cue_file_index without a pattern",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFileMapping:  "latest.cue",
+					ShowNamePattern: "Drive",
+					CueFileIndex:    1,
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "require cue_file_pattern",
+		},
+		{
+			name: "unknown gap_action",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:     "DRIVE_*.cue",
+					ShowNamePattern:    "Drive",
+					MaxTrackGapMinutes: 15,
+					GapAction:          "skip",
+					Enabled:            true,
+				},
+			},
+			wantError: true,
+			errorText: `gap_action must be "warn" or "fail", got "skip"`,
+		},
+		{
+			name: "negative max_track_gap_minutes",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:     "DRIVE_*.cue",
+					ShowNamePattern:    "Drive",
+					MaxTrackGapMinutes: -5,
+					Enabled:            true,
+				},
+			},
+			wantError: true,
+			errorText: "max_track_gap_minutes must be non-negative",
+		},
+		{
+			name: "negative collapse_max_titles",
+			shows: map[string]config.ShowConfig{
+				"album-hour": {
+					CueFilePattern:     "ALBUM_*.cue",
+					ShowNamePattern:    "Album Hour",
+					CollapseSameArtist: true,
+					CollapseMaxTitles:  -1,
+					Enabled:            true,
+				},
+			},
+			wantError: true,
+			errorText: "collapse_max_titles must be non-negative",
+		},
+		{
+			name: "date_input_format without a day",
+			shows: map[string]config.ShowConfig{
+				"uk": {
+					CueFilePattern:  "UK_*.cue",
+					ShowNamePattern: "UK Show {date}",
+					DateInputFormat: "MM/YYYY",
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "date_input_format \"MM/YYYY\" needs a year, a month and a day",
+		},
+		{
+			name: "unknown duration_mismatch_action",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:           "DRIVE_*.cue",
+					ShowNamePattern:          "Drive",
+					DurationTolerancePercent: 15,
+					DurationMismatchAction:   "skip",
+					Enabled:                  true,
+				},
+			},
+			wantError: true,
+			errorText: `duration_mismatch_action must be "warn" or "fail", got "skip"`,
+		},
+		{
+			name: "mixcloud_username with a space",
+			shows: map[string]config.ShowConfig{
+				"guest": {
+					CueFilePattern:   "GUEST_*.cue",
+					ShowNamePattern:  "Guest Mix",
+					MixcloudUsername: "dj guest",
+					Enabled:          true,
+				},
+			},
+			wantError: true,
+			errorText: `mixcloud_username "dj guest"`,
+		},
+		{
+			name: "negative duration_tail_minutes",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:      "DRIVE_*.cue",
+					ShowNamePattern:     "Drive",
+					DurationTailMinutes: -4,
+					Enabled:             true,
+				},
+			},
+			wantError: true,
+			errorText: "duration_tail_minutes must be non-negative",
+		},
+		{
+			name: "description_max_length over Mixcloud's limit",
+			shows: map[string]config.ShowConfig{
+				"legacy": {
+					CueFilePattern:       "LEGACY_*.cue",
+					ShowNamePattern:      "Legacy",
+					DescriptionMaxLength: 2000,
+					Enabled:              true,
+				},
+			},
+			wantError: true,
+			errorText: "description_max_length must be between 0 and 1000",
+		},
+		{
+			name: "time_offset without sign",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:  "DRIVE_*.cue",
+					ShowNamePattern: "Drive",
+					TimeOffset:      "00:01:30",
+					Enabled:         true,
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "time_offset in minutes and seconds",
+			shows: map[string]config.ShowConfig{
+				"drive": {
+					CueFilePattern:  "DRIVE_*.cue",
+					ShowNamePattern: "Drive",
+					TimeOffset:      "-01:30",
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: `time_offset: time offset "-01:30" must be [+|-]HH:MM:SS`,
+		},
+		{
+			name: "locale with region",
+			shows: map[string]config.ShowConfig{
+				"noche": {
+					CueFilePattern:  "NOCHE_*.cue",
+					ShowNamePattern: "Noche",
+					Locale:          "es-MX",
+					Enabled:         true,
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown locale",
+			shows: map[string]config.ShowConfig{
+				"nuit": {
+					CueFilePattern:  "NUIT_*.cue",
+					ShowNamePattern: "Nuit",
+					Locale:          "fr",
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: `locale: unknown locale "fr" (available: en, es)`,
+		},
+		{
+			name: "split_at with two parts",
+			shows: map[string]config.ShowConfig{
+				"marathon": {
+					CueFilePattern:  "MARATHON_*.cue",
+					ShowNamePattern: "Marathon",
+					SplitAt:         config.SplitPoints{"02:00:00"},
+					Enabled:         true,
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "split_at out of order",
+			shows: map[string]config.ShowConfig{
+				"marathon": {
+					CueFilePattern:  "MARATHON_*.cue",
+					ShowNamePattern: "Marathon",
+					SplitAt:         config.SplitPoints{"02:00:00", "01:00:00"},
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: `split_at: split point "01:00:00" is not after "02:00:00"`,
+		},
+		{
+			name: "part_url_suffix_pattern without part number",
+			shows: map[string]config.ShowConfig{
+				"marathon": {
+					CueFilePattern:       "MARATHON_*.cue",
+					ShowNamePattern:      "Marathon",
+					SplitAt:              config.SplitPoints{"02:00:00"},
+					PartURLSuffixPattern: "-part",
+					Enabled:              true,
+				},
+			},
+			wantError: true,
+			errorText: "part_url_suffix_pattern must contain {n}",
+		},
+		{
+			name: "split_at with update_name",
+			shows: map[string]config.ShowConfig{
+				"marathon": {
+					CueFilePattern:  "MARATHON_*.cue",
+					ShowNamePattern: "Marathon",
+					SplitAt:         config.SplitPoints{"02:00:00"},
+					UpdateName:      true,
+					NameTemplate:    "Marathon {date}",
+					Enabled:         true,
+				},
+			},
+			wantError: true,
+			errorText: "split_at and update_name cannot both be enabled",
+		},
 	}
 
 	for _, tt := range tests {
@@ -402,4 +890,79 @@ func containsInMiddle(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+func TestListGroupShows(t *testing.T) {
+	cfg := &config.Config{
+		Shows: map[string]config.ShowConfig{
+			"fest-fri":  {ShowGroup: "fest", GroupAtomic: true, Priority: 3, Enabled: true},
+			"fest-sat":  {ShowGroup: "fest", GroupAtomic: true, Priority: 2, Enabled: true},
+			"fest-sun":  {ShowGroup: "fest", GroupAtomic: true, Priority: 1, Enabled: false},
+			"weekly":    {Priority: 5, Enabled: true},
+			"other-fri": {ShowGroup: "other", Enabled: true},
+		},
+	}
+	resolver, err := NewResolver(cfg)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	got := resolver.ListGroupShows("fest", true)
+	want := []string{"fest-fri", "fest-sat"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ListGroupShows() = %v, want %v", got, want)
+	}
+
+	if !resolver.IsGroupAtomic("fest") {
+		t.Error("IsGroupAtomic(fest) = false, want true")
+	}
+	if resolver.IsGroupAtomic("other") {
+		t.Error("IsGroupAtomic(other) = true, want false")
+	}
+	if got := resolver.ListGroupShows("missing", true); len(got) != 0 {
+		t.Errorf("ListGroupShows(missing) = %v, want none", got)
+	}
+}
+
+func TestMatchShowsForFile(t *testing.T) {
+	cfg := &config.Config{
+		Shows: map[string]config.ShowConfig{
+			"myriad-all":  {CueFilePattern: "MYR*.cue", Priority: 1, Enabled: true},
+			"myriad-407":  {CueFilePattern: "MYR407??.cue", Priority: 3, Enabled: true},
+			"mapped":      {CueFileMapping: "MYR40705.cue", Priority: 2, Enabled: true},
+			"mapped-wins": {CueFileMapping: "latest.cue", CueFilePattern: "MYR*.cue", Priority: 4, Enabled: true},
+			"archive":     {CueFilePattern: "archive/MYR*.cue", Priority: 5, Enabled: true},
+			"paused":      {CueFilePattern: "MYR*.cue", Priority: 6, Enabled: false},
+			"broken":      {CueFilePattern: "MYR[.cue", Priority: 7, Enabled: true},
+		},
+	}
+	cfg.Processing.CueFileDirectory = "/data/cue"
+
+	resolver, err := NewResolver(cfg)
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		want     []string
+	}{
+		{"overlapping patterns and mapping", "MYR40705.cue", []string{"myriad-407", "mapped", "myriad-all"}},
+		{"one pattern", "MYR50101.cue", []string{"myriad-all"}},
+		{"full path in the CUE directory", "/data/cue/MYR50101.cue", []string{"myriad-all"}},
+		{"mapping over pattern", "latest.cue", []string{"mapped-wins"}},
+		{"subdirectory pattern", "archive/MYR40705.cue", []string{"archive"}},
+		{"star does not cross directories", "/data/cue/archive/old/MYR1.cue", nil},
+		{"no match", "JAZZ_0705.cue", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolver.MatchShowsForFile(tt.filename)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("MatchShowsForFile(%q) = %v, want %v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
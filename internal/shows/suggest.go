@@ -0,0 +1,151 @@
+package shows
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// AIDEV-NOTE: Suggestions use the optimal string alignment distance, so a swapped
+// pair of letters ("nwn" for "nnw") costs 1 like a missing or extra letter.
+
+const (
+	maxSuggestions     = 3  // Most "did you mean" candidates offered
+	maxSuggestDistance = 3  // Cap on the edit distance allowed for long names
+	listAvailableUpTo  = 10 // Configs with at most this many shows list them all
+)
+
+// NotFoundError reports a show name or alias that matches no configured show
+type NotFoundError struct {
+	Name        string   // What was asked for
+	Suggestions []string // Closest show keys and aliases, best first
+	Available   []string // Every show with its aliases, e.g. "nnw (new-wave)"; only set for small configs
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("show not found: %s", e.Name)
+	if len(e.Suggestions) > 0 {
+		quoted := make([]string, len(e.Suggestions))
+		for i, suggestion := range e.Suggestions {
+			quoted[i] = "'" + suggestion + "'"
+		}
+		msg += " - did you mean " + joinOr(quoted) + "?"
+	}
+	if len(e.Available) > 0 {
+		msg += "; available shows: " + strings.Join(e.Available, ", ")
+	}
+	return msg
+}
+
+// NotFoundError builds the error for a name or alias FindShowConfig didn't match
+func (r *Resolver) NotFoundError(nameOrAlias string) *NotFoundError {
+	err := &NotFoundError{
+		Name:        nameOrAlias,
+		Suggestions: r.Suggest(nameOrAlias),
+	}
+	if len(r.showKeys) <= listAvailableUpTo {
+		err.Available = r.describeShows()
+	}
+	return err
+}
+
+// Suggest returns up to three show keys or aliases close to nameOrAlias, closest
+// first. Names further away than roughly a third of their length aren't offered.
+func (r *Resolver) Suggest(nameOrAlias string) []string {
+	input := config.CanonicalShowKey(nameOrAlias)
+	if input == "" {
+		return nil
+	}
+	maxDistance := len([]rune(input))/3 + 1
+	if maxDistance > maxSuggestDistance {
+		maxDistance = maxSuggestDistance
+	}
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	for _, showKey := range r.showKeys {
+		names := append([]string{r.config.ShowDisplayKey(showKey)}, r.config.Shows[showKey].Aliases...)
+		for _, name := range names {
+			normalized := config.CanonicalShowKey(name)
+			if seen[normalized] {
+				continue
+			}
+			seen[normalized] = true
+			if distance := editDistance(input, normalized); distance <= maxDistance {
+				candidates = append(candidates, candidate{name, distance})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.name
+	}
+	return suggestions
+}
+
+// describeShows lists every show key with its aliases, sorted by key
+func (r *Resolver) describeShows() []string {
+	keys := r.ListShows()
+	sort.Strings(keys)
+	described := make([]string, len(keys))
+	for i, showKey := range keys {
+		described[i] = r.config.ShowDisplayKey(showKey)
+		if aliases := r.config.Shows[showKey].Aliases; len(aliases) > 0 {
+			described[i] += " (" + strings.Join(aliases, ", ") + ")"
+		}
+	}
+	return described
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and adjacent transpositions each cost 1
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+// joinOr joins items as "a", "a or b" or "a, b or c"
+func joinOr(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " or " + items[len(items)-1]
+}
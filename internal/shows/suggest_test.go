@@ -0,0 +1,120 @@
+package shows
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+func newSuggestTestResolver(t *testing.T, shows map[string]config.ShowConfig) *Resolver {
+	t.Helper()
+	resolver, err := NewResolver(&config.Config{Shows: shows})
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	return resolver
+}
+
+func suggestTestShows() map[string]config.ShowConfig {
+	return map[string]config.ShowConfig{
+		"newer-new-wave": {Aliases: []string{"nnw", "new-wave"}},
+		"sounds-like":    {Aliases: []string{"sl", "sounds"}},
+		"the-vault":      {Aliases: []string{"vault"}},
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	resolver := newSuggestTestResolver(t, suggestTestShows())
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"extra character", "nnww", []string{"nnw"}},
+		{"transposition", "nwn", []string{"nnw"}},
+		{"transposition in long name", "sounds-lkie", []string{"sounds-like"}},
+		{"missing character", "sonds", []string{"sounds"}},
+		{"case insensitive", "VALT", []string{"vault"}},
+		{"distance too large", "jazz", []string{}},
+		{"empty input", "  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolver.Suggest(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestLimitsAndOrdersCandidates(t *testing.T) {
+	resolver := newSuggestTestResolver(t, map[string]config.ShowConfig{
+		"show-a": {Aliases: []string{"abc"}},
+		"show-b": {Aliases: []string{"abd"}},
+		"show-c": {Aliases: []string{"abe", "xbcd"}},
+		"show-d": {Aliases: []string{"abcd"}},
+	})
+
+	// abcd matches exactly; abc, abd and xbcd tie at 1 and sort by name
+	got := resolver.Suggest("abcd ")
+	want := []string{"abcd", "abc", "abd"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest() = %v, want %v", got, want)
+	}
+}
+
+func TestNotFoundError(t *testing.T) {
+	resolver := newSuggestTestResolver(t, suggestTestShows())
+
+	err := resolver.NotFoundError("nnww")
+	want := "show not found: nnww - did you mean 'nnw'?; available shows: " +
+		"newer-new-wave (nnw, new-wave), sounds-like (sl, sounds), the-vault (vault)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q\nwant %q", err.Error(), want)
+	}
+
+	err = resolver.NotFoundError("jazz")
+	if strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("Error() = %q, want no suggestion", err.Error())
+	}
+}
+
+func TestNotFoundErrorOmitsListForLargeConfigs(t *testing.T) {
+	shows := make(map[string]config.ShowConfig)
+	for i := 0; i <= listAvailableUpTo; i++ {
+		shows[fmt.Sprintf("show-%02d", i)] = config.ShowConfig{}
+	}
+	resolver := newSuggestTestResolver(t, shows)
+
+	err := resolver.NotFoundError("show-1")
+	if len(err.Available) != 0 {
+		t.Errorf("Available = %v, want none for %d shows", err.Available, len(shows))
+	}
+	if len(err.Suggestions) != maxSuggestions {
+		t.Errorf("Suggestions = %v, want %d", err.Suggestions, maxSuggestions)
+	}
+}
+
+func TestJoinOr(t *testing.T) {
+	tests := []struct {
+		items []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"'a'"}, "'a'"},
+		{[]string{"'a'", "'b'"}, "'a' or 'b'"},
+		{[]string{"'a'", "'b'", "'c'"}, "'a', 'b' or 'c'"},
+	}
+
+	for _, tt := range tests {
+		if got := joinOr(tt.items); got != tt.want {
+			t.Errorf("joinOr(%v) = %q, want %q", tt.items, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package shows
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// AIDEV-NOTE: Publish windows let one frequent cron entry serve every show
+// without publishing a tracklist while the show is still on air. Times are wall
+// clock times in the station timezone; every occurrence is built with time.Date,
+// so a window keeps its local times across DST changes (a time skipped by the
+// spring-forward gap moves forward by the gap, 02:30 becoming 03:30).
+
+// weekTime is a time of day, on one weekday or every day
+type weekTime struct {
+	weekday time.Weekday
+	daily   bool // No weekday given: every day
+	hour    int
+	minute  int
+}
+
+// PublishWindow is the part of each week (or day) a show may be published in:
+// from publish_after until publish_before, or until the end of that day
+type PublishWindow struct {
+	after     weekTime
+	before    weekTime
+	hasBefore bool
+}
+
+// ParsePublishWindow parses a show's publish_after and publish_before, e.g.
+// "FRI 20:00" or "20:00" for every day. It returns nil when publish_after is unset.
+func ParsePublishWindow(showCfg *config.ShowConfig) (*PublishWindow, error) {
+	if strings.TrimSpace(showCfg.PublishAfter) == "" {
+		if strings.TrimSpace(showCfg.PublishBefore) != "" {
+			return nil, fmt.Errorf("publish_before requires publish_after")
+		}
+		return nil, nil
+	}
+
+	after, err := parseWeekTime(showCfg.PublishAfter)
+	if err != nil {
+		return nil, fmt.Errorf("publish_after: %w", err)
+	}
+	window := &PublishWindow{after: after}
+
+	if strings.TrimSpace(showCfg.PublishBefore) != "" {
+		before, err := parseWeekTime(showCfg.PublishBefore)
+		if err != nil {
+			return nil, fmt.Errorf("publish_before: %w", err)
+		}
+		if before.daily != after.daily {
+			return nil, fmt.Errorf("publish_after and publish_before must both have a weekday or both leave it out")
+		}
+		if before == after {
+			return nil, fmt.Errorf("publish_before must differ from publish_after")
+		}
+		window.before = before
+		window.hasBefore = true
+	}
+	return window, nil
+}
+
+// parseWeekTime parses "FRI 20:00", "friday 8:30" or "20:00"
+func parseWeekTime(s string) (weekTime, error) {
+	fields := strings.Fields(s)
+	var wt weekTime
+	switch len(fields) {
+	case 1:
+		wt.daily = true
+	case 2:
+		weekday, ok := config.ParseWeekday(fields[0])
+		if !ok {
+			return weekTime{}, fmt.Errorf("unknown weekday %q in %q", fields[0], s)
+		}
+		wt.weekday = weekday
+		fields = fields[1:]
+	default:
+		return weekTime{}, fmt.Errorf("%q is not a weekday and time like \"FRI 20:00\"", s)
+	}
+
+	hour, minute, ok := strings.Cut(fields[0], ":")
+	h, hErr := strconv.Atoi(hour)
+	m, mErr := strconv.Atoi(minute)
+	if !ok || hErr != nil || mErr != nil || h < 0 || h > 23 || m < 0 || m > 59 || len(minute) != 2 {
+		return weekTime{}, fmt.Errorf("invalid time %q in %q, want 24-hour HH:MM", fields[0], s)
+	}
+	wt.hour, wt.minute = h, m
+	return wt, nil
+}
+
+// Contains reports whether t falls inside the window, evaluated in loc. When it
+// doesn't, next is when the window opens next.
+func (w *PublishWindow) Contains(t time.Time, loc *time.Location) (open bool, next time.Time) {
+	t = t.In(loc)
+	opened := w.after.latestAtOrBefore(t)
+	var closes time.Time
+	if w.hasBefore {
+		closes = w.before.firstAfter(opened)
+	} else {
+		closes = time.Date(opened.Year(), opened.Month(), opened.Day()+1, 0, 0, 0, 0, loc)
+	}
+	if t.Before(closes) {
+		return true, time.Time{}
+	}
+	return false, w.after.firstAfter(t)
+}
+
+// String describes the window, e.g. "Fri 20:00 - Sat 02:00"
+func (w *PublishWindow) String() string {
+	if w.hasBefore {
+		return w.after.String() + " - " + w.before.String()
+	}
+	return w.after.String() + " - midnight"
+}
+
+// String formats the time like "Fri 20:00", or "20:00" when daily
+func (wt weekTime) String() string {
+	clock := fmt.Sprintf("%02d:%02d", wt.hour, wt.minute)
+	if wt.daily {
+		return clock
+	}
+	return wt.weekday.String()[:3] + " " + clock
+}
+
+// on returns the occurrence on the day offset days from t's date
+func (wt weekTime) on(t time.Time, offset int) (time.Time, bool) {
+	day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, t.Location())
+	if !wt.daily && day.Weekday() != wt.weekday {
+		return time.Time{}, false
+	}
+	occurrence := time.Date(day.Year(), day.Month(), day.Day(), wt.hour, wt.minute, 0, 0, t.Location())
+
+	// time.Date may resolve a time skipped by a spring-forward gap to before
+	// the gap (02:30 → 01:30 EST); move it past the gap instead (03:30 EDT)
+	want := wt.hour*60 + wt.minute
+	if got := occurrence.Hour()*60 + occurrence.Minute(); occurrence.Day() == day.Day() && got < want {
+		occurrence = occurrence.Add(time.Duration(want-got) * time.Minute)
+	}
+	return occurrence, true
+}
+
+// latestAtOrBefore returns the most recent occurrence at or before t
+func (wt weekTime) latestAtOrBefore(t time.Time) time.Time {
+	for offset := 0; offset >= -8; offset-- {
+		if occurrence, ok := wt.on(t, offset); ok && !occurrence.After(t) {
+			return occurrence
+		}
+	}
+	return time.Time{} // Unreachable: every weekday recurs within 8 days
+}
+
+// firstAfter returns the first occurrence strictly after t
+func (wt weekTime) firstAfter(t time.Time) time.Time {
+	for offset := 0; offset <= 8; offset++ {
+		if occurrence, ok := wt.on(t, offset); ok && occurrence.After(t) {
+			return occurrence
+		}
+	}
+	return time.Time{} // Unreachable: every weekday recurs within 8 days
+}
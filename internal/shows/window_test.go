@@ -0,0 +1,131 @@
+package shows
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // America/New_York on systems without zoneinfo
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+func TestParsePublishWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		after     string
+		before    string
+		want      string
+		wantError string
+	}{
+		{name: "no window"},
+		{name: "after only", after: "FRI 20:00", want: "Fri 20:00 - midnight"},
+		{name: "full weekday names", after: "friday 22:00", before: "Saturday 2:00", want: "Fri 22:00 - Sat 02:00"},
+		{name: "daily", after: "17:00", before: "19:30", want: "17:00 - 19:30"},
+		{name: "before without after", before: "SAT 02:00", wantError: "publish_before requires publish_after"},
+		{name: "unknown weekday", after: "FRY 20:00", wantError: `unknown weekday "FRY"`},
+		{name: "bad time", after: "FRI 25:00", wantError: "invalid time"},
+		{name: "missing minutes", after: "FRI 20", wantError: "invalid time"},
+		{name: "daily and weekly mixed", after: "FRI 20:00", before: "23:00", wantError: "both have a weekday"},
+		{name: "empty window", after: "FRI 20:00", before: "fri 20:00", wantError: "must differ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := ParsePublishWindow(&config.ShowConfig{PublishAfter: tt.after, PublishBefore: tt.before})
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("ParsePublishWindow() error = %v, want it to contain %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePublishWindow() error = %v", err)
+			}
+			if tt.want == "" {
+				if window != nil {
+					t.Errorf("ParsePublishWindow() = %v, want nil", window)
+				}
+				return
+			}
+			if window.String() != tt.want {
+				t.Errorf("window = %q, want %q", window.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishWindowContains(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("loading timezone: %v", err)
+	}
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, newYork)
+	}
+
+	tests := []struct {
+		name     string
+		after    string
+		before   string
+		now      time.Time
+		wantOpen bool
+		wantNext time.Time
+	}{
+		// 27 June 2025 is a Friday
+		{"before the show ends", "FRI 20:00", "", at(6, 27, 19, 59), false, at(6, 27, 20, 0)},
+		{"as the window opens", "FRI 20:00", "", at(6, 27, 20, 0), true, time.Time{}},
+		{"open until midnight", "FRI 20:00", "", at(6, 27, 23, 59), true, time.Time{}},
+		{"closed after midnight", "FRI 20:00", "", at(6, 28, 0, 0), false, at(7, 4, 20, 0)},
+		{"closed midweek", "FRI 20:00", "", at(7, 1, 12, 0), false, at(7, 4, 20, 0)},
+		{"crossing midnight, evening", "FRI 22:00", "SAT 02:00", at(6, 27, 23, 0), true, time.Time{}},
+		{"crossing midnight, after midnight", "FRI 22:00", "SAT 02:00", at(6, 28, 1, 30), true, time.Time{}},
+		{"crossing midnight, closed", "FRI 22:00", "SAT 02:00", at(6, 28, 2, 0), false, at(7, 4, 22, 0)},
+		{"crossing the week boundary", "SAT 22:00", "MON 06:00", at(6, 30, 5, 0), true, time.Time{}},
+		{"daily, open", "23:00", "01:00", at(6, 25, 0, 30), true, time.Time{}},
+		{"daily, closed", "23:00", "01:00", at(6, 25, 12, 0), false, at(6, 25, 23, 0)},
+		// Clocks sprang forward at 02:00 on 9 March 2025: 02:00-03:00 doesn't exist
+		{"spring forward, inside", "SUN 01:30", "SUN 03:30", at(3, 9, 3, 15), true, time.Time{}},
+		{"spring forward, closed", "SUN 01:30", "SUN 03:30", at(3, 9, 3, 30), false, at(3, 16, 1, 30)},
+		{"spring forward, skipped opening", "SUN 02:30", "", at(3, 9, 3, 45), true, time.Time{}},
+		{"spring forward, before skipped opening", "SUN 02:30", "", at(3, 9, 1, 59), false, at(3, 9, 3, 30)},
+		// Clocks fell back at 02:00 on 2 November 2025: 01:00-02:00 happens twice
+		{"fall back, local times hold", "SUN 00:30", "SUN 02:30", at(11, 2, 2, 15), true, time.Time{}},
+		{"fall back, closed", "SUN 00:30", "SUN 02:30", at(11, 2, 2, 30), false, at(11, 9, 0, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := ParsePublishWindow(&config.ShowConfig{PublishAfter: tt.after, PublishBefore: tt.before})
+			if err != nil {
+				t.Fatalf("ParsePublishWindow() error = %v", err)
+			}
+			open, next := window.Contains(tt.now, newYork)
+			if open != tt.wantOpen {
+				t.Fatalf("Contains(%v) open = %v, want %v", tt.now, open, tt.wantOpen)
+			}
+			if !next.Equal(tt.wantNext) {
+				t.Errorf("Contains(%v) next = %v, want %v", tt.now, next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestPublishWindowEvaluatedInLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("loading timezone: %v", err)
+	}
+	window, err := ParsePublishWindow(&config.ShowConfig{PublishAfter: "FRI 20:00"})
+	if err != nil {
+		t.Fatalf("ParsePublishWindow() error = %v", err)
+	}
+
+	// 00:30 UTC on Saturday is 20:30 on Friday in New York
+	now := time.Date(2025, 6, 28, 0, 30, 0, 0, time.UTC)
+	if open, _ := window.Contains(now, newYork); !open {
+		t.Error("window closed in New York at 20:30 Friday")
+	}
+	if open, _ := window.Contains(now, time.UTC); open {
+		t.Error("window open in UTC at 00:30 Saturday")
+	}
+}
@@ -0,0 +1,145 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AIDEV-NOTE: A batch run records each show's outcome here as it finishes and
+// marks the checkpoint complete when the run ends normally. A checkpoint left
+// incomplete means the process died mid-run (power loss, kill -9), and -resume
+// skips the shows it published instead of re-sending identical descriptions.
+// The file is rewritten whole after every show through writeJSONAtomic, so a
+// crash leaves either the old or the new checkpoint, never half of one.
+
+// DefaultCheckpointFilename is the checkpoint file name used when processing.checkpoint_file is not set
+const DefaultCheckpointFilename = "mixcloud-updater-checkpoint.json"
+
+// currentCheckpointVersion is written to the checkpoint file to allow future format changes
+const currentCheckpointVersion = 1
+
+// CheckpointSuccess is the CheckpointEntry.Status of a published show
+const CheckpointSuccess = "success"
+
+// CheckpointEntry is the outcome of one show of a checkpointed run
+type CheckpointEntry struct {
+	ShowKey    string    `json:"show_key"`
+	Status     string    `json:"status"` // The processor's show status: "success", "failed", "skipped" or "queued"
+	ShowURL    string    `json:"show_url,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Checkpoint records the progress of one batch run
+type Checkpoint struct {
+	Version   int               `json:"version"`
+	RunID     string            `json:"run_id"` // Derived from StartedAt, e.g. "20250628T220500"
+	StartedAt time.Time         `json:"started_at"`
+	Shows     []string          `json:"shows"`    // Shows the run set out to process, in order
+	Complete  bool              `json:"complete"` // The run ended normally
+	Entries   []CheckpointEntry `json:"entries"`  // Finished shows, in order
+
+	path string
+}
+
+// DefaultCheckpointPath returns the checkpoint file path for a config file when none is configured
+func DefaultCheckpointPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), DefaultCheckpointFilename)
+}
+
+// ResolveCheckpointPath returns the checkpoint file to use: the configured path
+// (relative paths are resolved against the config file's directory) or DefaultCheckpointPath
+func ResolveCheckpointPath(configured, configPath string) string {
+	if configured == "" {
+		return DefaultCheckpointPath(configPath)
+	}
+	return ResolvePath(configured, configPath)
+}
+
+// RunID returns the run id of a run started at startedAt
+func RunID(startedAt time.Time) string {
+	return startedAt.UTC().Format("20060102T150405")
+}
+
+// NewCheckpoint starts the checkpoint of a run started at startedAt that will
+// process shows. Nothing is written until Save.
+func NewCheckpoint(path string, startedAt time.Time, shows []string) *Checkpoint {
+	return &Checkpoint{
+		Version:   currentCheckpointVersion,
+		RunID:     RunID(startedAt),
+		StartedAt: startedAt,
+		Shows:     shows,
+		Entries:   []CheckpointEntry{},
+		path:      path,
+	}
+}
+
+// LoadCheckpoint reads the checkpoint file at path. A missing file yields nil
+// and no error.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading checkpoint file %s: %w", path, err)
+	}
+
+	c := &Checkpoint{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint file %s: %w", path, err)
+	}
+	c.Version = currentCheckpointVersion
+	c.path = path
+
+	return c, nil
+}
+
+// Path returns the file the checkpoint is loaded from and saved to
+func (c *Checkpoint) Path() string {
+	return c.path
+}
+
+// Save writes the checkpoint file atomically (write to temp file, then rename)
+func (c *Checkpoint) Save() error {
+	if c.path == "" {
+		return fmt.Errorf("checkpoint file path not set")
+	}
+	return writeJSONAtomic(c.path, c, "checkpoint")
+}
+
+// Record appends a finished show and saves the checkpoint
+func (c *Checkpoint) Record(entry CheckpointEntry) error {
+	c.Entries = append(c.Entries, entry)
+	return c.Save()
+}
+
+// Finish marks the run as ended normally and saves the checkpoint
+func (c *Checkpoint) Finish() error {
+	c.Complete = true
+	return c.Save()
+}
+
+// Interrupted reports whether the run ended without Finish and started no
+// longer than window before now
+func (c *Checkpoint) Interrupted(now time.Time, window time.Duration) bool {
+	return !c.Complete && now.Sub(c.StartedAt) <= window
+}
+
+// Published returns the shows the run published successfully, keyed by show
+// key. A show retried later in the run counts by its last outcome.
+func (c *Checkpoint) Published() map[string]CheckpointEntry {
+	published := make(map[string]CheckpointEntry)
+	for _, entry := range c.Entries {
+		if entry.Status == CheckpointSuccess {
+			published[entry.ShowKey] = entry
+		} else {
+			delete(published, entry.ShowKey)
+		}
+	}
+	return published
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckpointRecordAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "checkpoint.json")
+	startedAt := time.Date(2025, 6, 28, 22, 5, 0, 0, time.UTC)
+	c := NewCheckpoint(path, startedAt, []string{"nnw", "jazz", "myr"})
+	if c.RunID != "20250628T220500" {
+		t.Errorf("RunID = %q, want 20250628T220500", c.RunID)
+	}
+
+	entry := CheckpointEntry{
+		ShowKey:    "nnw",
+		Status:     CheckpointSuccess,
+		ShowURL:    "https://www.mixcloud.com/station/nnw-6282025/",
+		FinishedAt: startedAt.Add(time.Minute),
+	}
+	if err := c.Record(entry); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary checkpoint file should not be left behind")
+	}
+
+	reloaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if reloaded.RunID != c.RunID || reloaded.Complete || !reflect.DeepEqual(reloaded.Shows, c.Shows) {
+		t.Errorf("reloaded checkpoint = %+v, want %+v", reloaded, c)
+	}
+	if !reflect.DeepEqual(reloaded.Entries, []CheckpointEntry{entry}) {
+		t.Errorf("reloaded entries = %+v, want [%+v]", reloaded.Entries, entry)
+	}
+
+	if err := reloaded.Finish(); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	finished, _ := LoadCheckpoint(path)
+	if !finished.Complete {
+		t.Error("checkpoint should be complete after Finish()")
+	}
+}
+
+func TestLoadCheckpointMissingFile(t *testing.T) {
+	c, err := LoadCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"))
+	if c != nil || err != nil {
+		t.Errorf("LoadCheckpoint() = %v, %v, want nil, nil", c, err)
+	}
+}
+
+func TestCheckpointInterrupted(t *testing.T) {
+	startedAt := time.Date(2025, 6, 28, 22, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		complete bool
+		now      time.Time
+		want     bool
+	}{
+		{"recent and incomplete", false, startedAt.Add(time.Hour), true},
+		{"complete", true, startedAt.Add(time.Hour), false},
+		{"older than the window", false, startedAt.Add(13 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCheckpoint("", startedAt, nil)
+			c.Complete = tt.complete
+			if got := c.Interrupted(tt.now, 12*time.Hour); got != tt.want {
+				t.Errorf("Interrupted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointPublishedUsesLastOutcome(t *testing.T) {
+	c := NewCheckpoint("", time.Now(), nil)
+	c.Entries = []CheckpointEntry{
+		{ShowKey: "nnw", Status: CheckpointSuccess},
+		{ShowKey: "jazz", Status: "failed"},
+		{ShowKey: "myr", Status: "skipped"},
+		{ShowKey: "jazz", Status: CheckpointSuccess},
+		{ShowKey: "nnw", Status: "failed"},
+	}
+
+	published := c.Published()
+	if len(published) != 1 {
+		t.Fatalf("Published() = %v, want only jazz", published)
+	}
+	if _, ok := published["jazz"]; !ok {
+		t.Errorf("Published() = %v, want jazz", published)
+	}
+}
+
+func TestResolveCheckpointPath(t *testing.T) {
+	configPath := filepath.Join("/etc", "mixcloud", "config.toml")
+	tests := []struct {
+		configured string
+		want       string
+	}{
+		{"", filepath.Join("/etc", "mixcloud", DefaultCheckpointFilename)},
+		{"checkpoint.json", filepath.Join("/etc", "mixcloud", "checkpoint.json")},
+		{"/var/lib/checkpoint.json", "/var/lib/checkpoint.json"},
+	}
+	for _, tt := range tests {
+		if got := ResolveCheckpointPath(tt.configured, configPath); got != tt.want {
+			t.Errorf("ResolveCheckpointPath(%q) = %q, want %q", tt.configured, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// AIDEV-NOTE: The history grows with every publish, so it lives in its own file
+// rather than in the state file that every run loads for caching and status.
+// The processor only loads it when a show is published, and -history reads it
+// offline. Entries are appended oldest first and pruned to the newest
+// processing.history_entries per show on each append.
+
+// DefaultHistoryFilename is the history file name used when processing.history_file is not set
+const DefaultHistoryFilename = "mixcloud-updater-history.json"
+
+// currentHistoryVersion is written to the history file to allow future format changes
+const currentHistoryVersion = 1
+
+// HistoryEntry records one successful publish of a show
+type HistoryEntry struct {
+	PublishedAt       time.Time     `json:"published_at"`
+	ShowDate          string        `json:"show_date,omitempty"` // Date the show aired, YYYY-MM-DD
+	CueFile           string        `json:"cue_file,omitempty"`
+	ParsedTracks      int           `json:"parsed_tracks"`
+	Tracks            int           `json:"tracks"` // Tracks published after filtering
+	ExcludedTracks    int           `json:"excluded_tracks"`
+	DescriptionLength int           `json:"description_length"`
+	Truncated         bool          `json:"truncated"`
+	Template          string        `json:"template,omitempty"`
+	ShowURL           string        `json:"show_url"`
+	DescriptionHash   string        `json:"description_hash,omitempty"`
+	Length            *LengthRecord `json:"length,omitempty"`   // Upload length check, with duration_tolerance_percent
+	Migrated          bool          `json:"migrated,omitempty"` // Seeded from the state file's last publish, which recorded fewer fields
+}
+
+// LengthRecord is the upload length check of a publish
+type LengthRecord struct {
+	TrackSpanSeconds   int     `json:"track_span_seconds"`
+	TailSeconds        int     `json:"tail_seconds"`
+	AudioLengthSeconds int     `json:"audio_length_seconds"`
+	DeviationPercent   float64 `json:"deviation_percent"`
+	Verdict            string  `json:"verdict"` // "match", "mismatch" or "unknown"
+}
+
+// History holds the publish history of all shows, keyed by show key, oldest entry first
+type History struct {
+	Version int                       `json:"version"`
+	Shows   map[string][]HistoryEntry `json:"shows"`
+
+	path string
+}
+
+// DefaultHistoryPath returns the history file path for a config file when none is configured
+func DefaultHistoryPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), DefaultHistoryFilename)
+}
+
+// ResolveHistoryPath returns the history file to use: the configured path
+// (relative paths are resolved against the config file's directory) or DefaultHistoryPath
+func ResolveHistoryPath(configured, configPath string) string {
+	if configured == "" {
+		return DefaultHistoryPath(configPath)
+	}
+	return ResolvePath(configured, configPath)
+}
+
+// LoadHistory reads the history file at path. When the file doesn't exist yet,
+// the history is seeded from legacy's last-publish records (if legacy is non-nil)
+// so shows published before the history existed don't start out blank.
+func LoadHistory(path string, legacy *State) (*History, error) {
+	h := &History{
+		Version: currentHistoryVersion,
+		Shows:   make(map[string][]HistoryEntry),
+		path:    path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			h.migrate(legacy)
+			return h, nil
+		}
+		return h, fmt.Errorf("reading history file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return h, fmt.Errorf("parsing history file %s: %w", path, err)
+	}
+	if h.Shows == nil {
+		h.Shows = make(map[string][]HistoryEntry)
+	}
+	h.foldShowKeys()
+	h.Version = currentHistoryVersion
+	h.path = path
+
+	return h, nil
+}
+
+// migrate adds a Migrated entry for each show published in legacy
+func (h *History) migrate(legacy *State) {
+	if legacy == nil {
+		return
+	}
+	for showKey, showState := range legacy.Shows {
+		if showState.LastPublished.IsZero() {
+			continue
+		}
+		h.Shows[showKey] = []HistoryEntry{{
+			PublishedAt:     showState.LastPublished,
+			Tracks:          showState.TrackCount,
+			ShowURL:         showState.ShowURL,
+			DescriptionHash: showState.DescriptionHash,
+			Migrated:        true,
+		}}
+	}
+}
+
+// Path returns the file the history is loaded from and saved to
+func (h *History) Path() string {
+	return h.path
+}
+
+// Save writes the history file atomically (write to temp file, then rename)
+func (h *History) Save() error {
+	if h.path == "" {
+		return fmt.Errorf("history file path not set")
+	}
+	return writeJSONAtomic(h.path, h, "history")
+}
+
+// Append adds an entry to a show's history, dropping the oldest entries beyond
+// limit (limit <= 0 keeps them all)
+func (h *History) Append(showKey string, entry HistoryEntry, limit int) {
+	entries := append(h.Shows[showKey], entry)
+	if limit > 0 && len(entries) > limit {
+		entries = append([]HistoryEntry(nil), entries[len(entries)-limit:]...)
+	}
+	h.Shows[showKey] = entries
+}
+
+// Entries returns up to n of a show's most recent entries, newest first (n <= 0 returns all)
+func (h *History) Entries(showKey string, n int) []HistoryEntry {
+	entries := h.Shows[showKey]
+	if n <= 0 || n > len(entries) {
+		n = len(entries)
+	}
+
+	recent := make([]HistoryEntry, 0, n)
+	for i := len(entries) - 1; i >= len(entries)-n; i-- {
+		recent = append(recent, entries[i])
+	}
+	return recent
+}
+
+// ShowKeys returns the keys of all shows with recorded history, sorted
+func (h *History) ShowKeys() []string {
+	keys := make([]string, 0, len(h.Shows))
+	for key := range h.Shows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
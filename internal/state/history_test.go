@@ -0,0 +1,191 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func historyEntry(day int) HistoryEntry {
+	return HistoryEntry{
+		PublishedAt: time.Date(2025, 6, day, 22, 0, 0, 0, time.UTC),
+		ShowDate:    time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+		Tracks:      day,
+	}
+}
+
+func entryDays(entries []HistoryEntry) []int {
+	days := make([]int, len(entries))
+	for i, entry := range entries {
+		days[i] = entry.Tracks
+	}
+	return days
+}
+
+func TestHistoryAppendPrunesToLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		appends int
+		limit   int
+		want    []int // Days kept, oldest first
+	}{
+		{"under limit", 2, 3, []int{1, 2}},
+		{"at limit", 3, 3, []int{1, 2, 3}},
+		{"over limit drops oldest", 5, 3, []int{3, 4, 5}},
+		{"no limit", 5, 0, []int{1, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := LoadHistory(filepath.Join(t.TempDir(), "history.json"), nil)
+			if err != nil {
+				t.Fatalf("LoadHistory() error = %v", err)
+			}
+			for day := 1; day <= tt.appends; day++ {
+				h.Append("nnw", historyEntry(day), tt.limit)
+			}
+			if got := entryDays(h.Shows["nnw"]); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept days %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryEntriesNewestFirst(t *testing.T) {
+	h, _ := LoadHistory(filepath.Join(t.TempDir(), "history.json"), nil)
+	for day := 1; day <= 4; day++ {
+		h.Append("nnw", historyEntry(day), 0)
+	}
+
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{2, []int{4, 3}},
+		{10, []int{4, 3, 2, 1}},
+		{0, []int{4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		if got := entryDays(h.Entries("nnw", tt.n)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Entries(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	if got := h.Entries("jazz", 10); len(got) != 0 {
+		t.Errorf("Entries() for a show without history = %v, want none", got)
+	}
+}
+
+func TestHistorySaveAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.json")
+	h, _ := LoadHistory(path, nil)
+	entry := HistoryEntry{
+		PublishedAt:       time.Date(2025, 6, 14, 22, 5, 0, 0, time.UTC),
+		ShowDate:          "2025-06-14",
+		CueFile:           "NNW0614.cue",
+		ParsedTracks:      24,
+		Tracks:            21,
+		ExcludedTracks:    3,
+		DescriptionLength: 987,
+		Truncated:         true,
+		Template:          "classic",
+		ShowURL:           "https://www.mixcloud.com/station/nnw-6142025/",
+	}
+	h.Append("nnw", entry, 10)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary history file should not be left behind")
+	}
+
+	reloaded, err := LoadHistory(path, nil)
+	if err != nil {
+		t.Fatalf("LoadHistory() after save error = %v", err)
+	}
+	got := reloaded.Entries("nnw", 1)
+	if len(got) != 1 || !reflect.DeepEqual(got[0], entry) {
+		t.Errorf("reloaded entries = %+v, want [%+v]", got, entry)
+	}
+}
+
+func TestLoadHistoryMigratesLegacyState(t *testing.T) {
+	dir := t.TempDir()
+	legacy, _ := Load(filepath.Join(dir, "state.json"))
+	published := time.Date(2025, 6, 28, 14, 3, 0, 0, time.UTC)
+	legacy.RecordPublish("jazz", ShowState{
+		LastPublished:   published,
+		ShowURL:         "https://www.mixcloud.com/station/jazz-6282025/",
+		TrackCount:      14,
+		DescriptionHash: "abc",
+	})
+	legacy.RecordPublish("never", ShowState{})
+
+	historyPath := filepath.Join(dir, "history.json")
+	h, err := LoadHistory(historyPath, legacy)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if got := h.ShowKeys(); !reflect.DeepEqual(got, []string{"jazz"}) {
+		t.Fatalf("migrated shows = %v, want [jazz]", got)
+	}
+	want := HistoryEntry{
+		PublishedAt:     published,
+		Tracks:          14,
+		ShowURL:         "https://www.mixcloud.com/station/jazz-6282025/",
+		DescriptionHash: "abc",
+		Migrated:        true,
+	}
+	if got := h.Entries("jazz", 0); !reflect.DeepEqual(got, []HistoryEntry{want}) {
+		t.Errorf("migrated entries = %+v, want [%+v]", got, want)
+	}
+
+	// Once the history file exists it is the record; the state isn't merged in again
+	h.Append("jazz", historyEntry(29), 10)
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	legacy.RecordPublish("blues", ShowState{LastPublished: published})
+	reloaded, err := LoadHistory(historyPath, legacy)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if got := reloaded.ShowKeys(); !reflect.DeepEqual(got, []string{"jazz"}) {
+		t.Errorf("shows after reload = %v, want [jazz]", got)
+	}
+	if got := len(reloaded.Entries("jazz", 0)); got != 2 {
+		t.Errorf("jazz has %d entries after reload, want 2", got)
+	}
+}
+
+func TestLoadHistoryCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := LoadHistory(path, nil)
+	if err == nil {
+		t.Fatal("LoadHistory() should fail on a corrupt file")
+	}
+	if h == nil || h.Shows == nil || h.Path() != path {
+		t.Errorf("LoadHistory() should still return a usable history, got %+v", h)
+	}
+}
+
+func TestResolveHistoryPath(t *testing.T) {
+	configPath := filepath.Join("/etc", "mixcloud", "config.toml")
+	tests := []struct {
+		configured string
+		want       string
+	}{
+		{"", filepath.Join("/etc", "mixcloud", DefaultHistoryFilename)},
+		{"history.json", filepath.Join("/etc", "mixcloud", "history.json")},
+		{"/var/lib/history.json", "/var/lib/history.json"},
+	}
+	for _, tt := range tests {
+		if got := ResolveHistoryPath(tt.configured, configPath); got != tt.want {
+			t.Errorf("ResolveHistoryPath(%q) = %q, want %q", tt.configured, got, tt.want)
+		}
+	}
+}
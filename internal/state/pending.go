@@ -0,0 +1,147 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AIDEV-NOTE: Offline runs render every show without touching Mixcloud and
+// queue the descriptions here; -retry-failed publishes them once Mixcloud is
+// reachable again. The queue keeps the rendered text rather than re-rendering,
+// since "latest.cue" style mappings will point at a newer file by then.
+// Entries are keyed by show key and generated URL, so re-running offline for
+// the same episode replaces its entry instead of queueing it twice.
+
+// DefaultPendingFilename is the pending-updates file name used when processing.pending_file is not set
+const DefaultPendingFilename = "mixcloud-updater-pending.json"
+
+// currentPendingVersion is written to the pending-updates file to allow future format changes
+const currentPendingVersion = 1
+
+// PendingUpdate is a rendered description waiting to be published
+type PendingUpdate struct {
+	ShowKey        string            `json:"show_key"`
+	ShowName       string            `json:"show_name"`
+	ShowDate       string            `json:"show_date,omitempty"` // Date the show aired, YYYY-MM-DD
+	ShowURL        string            `json:"show_url"`            // Where the update goes, after any recorded rename
+	GeneratedURL   string            `json:"generated_url"`       // URL generated from the config, the queue key
+	CueFile        string            `json:"cue_file,omitempty"`
+	Template       string            `json:"template,omitempty"`
+	Description    string            `json:"description"`
+	UpdateFields   map[string]string `json:"update_fields,omitempty"` // Extra form fields, including a name_template rename
+	NewName        string            `json:"new_name,omitempty"`      // update_name rename, also in UpdateFields
+	PreserveName   bool              `json:"preserve_name,omitempty"`
+	Parts          []PendingPart     `json:"parts,omitempty"` // Split shows, one description per part
+	ParsedTracks   int               `json:"parsed_tracks"`
+	Tracks         int               `json:"tracks"`
+	ExcludedTracks int               `json:"excluded_tracks"`
+	Placeholder    bool              `json:"placeholder,omitempty"`
+	QueuedAt       time.Time         `json:"queued_at"`
+	Reason         string            `json:"reason"` // Why the run was offline: "-offline" or the network error
+}
+
+// PendingPart is one part of a queued split show
+type PendingPart struct {
+	Number      int    `json:"number"`
+	ShowURL     string `json:"show_url"`
+	Tracks      int    `json:"tracks"`
+	Description string `json:"description"`
+	Placeholder bool   `json:"placeholder,omitempty"`
+}
+
+// Pending holds the queued updates, oldest first
+type Pending struct {
+	Version int             `json:"version"`
+	Updates []PendingUpdate `json:"updates"`
+
+	path string
+}
+
+// DefaultPendingPath returns the pending-updates file path for a config file when none is configured
+func DefaultPendingPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), DefaultPendingFilename)
+}
+
+// ResolvePendingPath returns the pending-updates file to use: the configured path
+// (relative paths are resolved against the config file's directory) or DefaultPendingPath
+func ResolvePendingPath(configured, configPath string) string {
+	if configured == "" {
+		return DefaultPendingPath(configPath)
+	}
+	return ResolvePath(configured, configPath)
+}
+
+// LoadPending reads the pending-updates file at path. A missing file yields an empty queue.
+func LoadPending(path string) (*Pending, error) {
+	p := &Pending{
+		Version: currentPendingVersion,
+		path:    path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return p, nil
+		}
+		return p, fmt.Errorf("reading pending updates file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, p); err != nil {
+		return p, fmt.Errorf("parsing pending updates file %s: %w", path, err)
+	}
+	p.foldShowKeys()
+	p.Version = currentPendingVersion
+	p.path = path
+
+	return p, nil
+}
+
+// Path returns the file the queue is loaded from and saved to
+func (p *Pending) Path() string {
+	return p.path
+}
+
+// Save writes the pending-updates file atomically (write to temp file, then rename)
+func (p *Pending) Save() error {
+	if p.path == "" {
+		return fmt.Errorf("pending updates file path not set")
+	}
+	return writeJSONAtomic(p.path, p, "pending updates")
+}
+
+// Len returns the number of queued updates
+func (p *Pending) Len() int {
+	return len(p.Updates)
+}
+
+// Queue adds an update, replacing an earlier one for the same show and generated URL
+func (p *Pending) Queue(update PendingUpdate) {
+	if i := p.index(update.ShowKey, update.GeneratedURL); i >= 0 {
+		p.Updates[i] = update
+		return
+	}
+	p.Updates = append(p.Updates, update)
+}
+
+// Remove drops the update for a show and generated URL, reporting whether there was one
+func (p *Pending) Remove(showKey, generatedURL string) bool {
+	i := p.index(showKey, generatedURL)
+	if i < 0 {
+		return false
+	}
+	p.Updates = append(p.Updates[:i], p.Updates[i+1:]...)
+	return true
+}
+
+func (p *Pending) index(showKey, generatedURL string) int {
+	for i, update := range p.Updates {
+		if update.ShowKey == showKey && update.GeneratedURL == generatedURL {
+			return i
+		}
+	}
+	return -1
+}
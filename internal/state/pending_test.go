@@ -0,0 +1,108 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func pendingUpdate(showKey, url, description string) PendingUpdate {
+	return PendingUpdate{
+		ShowKey:      showKey,
+		ShowName:     showKey,
+		ShowURL:      url,
+		GeneratedURL: url,
+		Description:  description,
+		QueuedAt:     time.Date(2025, 6, 28, 22, 0, 0, 0, time.UTC),
+		Reason:       "-offline",
+	}
+}
+
+func TestPendingQueueReplacesSameEpisode(t *testing.T) {
+	p, err := LoadPending(filepath.Join(t.TempDir(), "pending.json"))
+	if err != nil {
+		t.Fatalf("LoadPending() error = %v", err)
+	}
+
+	p.Queue(pendingUpdate("nnw", "https://www.mixcloud.com/station/nnw-6282025/", "first"))
+	p.Queue(pendingUpdate("jazz", "https://www.mixcloud.com/station/jazz-6282025/", "jazz"))
+	p.Queue(pendingUpdate("nnw", "https://www.mixcloud.com/station/nnw-6282025/", "second"))
+	p.Queue(pendingUpdate("nnw", "https://www.mixcloud.com/station/nnw-6212025/", "last week"))
+
+	var got []string
+	for _, update := range p.Updates {
+		got = append(got, update.Description)
+	}
+	if want := []string{"second", "jazz", "last week"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queued descriptions = %v, want %v", got, want)
+	}
+
+	if !p.Remove("nnw", "https://www.mixcloud.com/station/nnw-6282025/") {
+		t.Error("Remove() = false for a queued update")
+	}
+	if p.Remove("nnw", "https://www.mixcloud.com/station/nnw-6282025/") {
+		t.Error("Remove() = true for an update already removed")
+	}
+	if p.Len() != 2 {
+		t.Errorf("Len() = %d after remove, want 2", p.Len())
+	}
+}
+
+func TestPendingSaveAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "pending.json")
+	p, _ := LoadPending(path)
+	update := pendingUpdate("nnw", "https://www.mixcloud.com/station/nnw-6282025/", "00:00 - \"Song\" by Artist")
+	update.UpdateFields = map[string]string{"name": "New Name"}
+	update.Parts = []PendingPart{
+		{Number: 1, ShowURL: "https://www.mixcloud.com/station/nnw-part-1-6282025/", Tracks: 10, Description: "part one"},
+		{Number: 2, ShowURL: "https://www.mixcloud.com/station/nnw-part-2-6282025/", Tracks: 11, Description: "part two"},
+	}
+	p.Queue(update)
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary pending file should not be left behind")
+	}
+
+	reloaded, err := LoadPending(path)
+	if err != nil {
+		t.Fatalf("LoadPending() after save error = %v", err)
+	}
+	if !reflect.DeepEqual(reloaded.Updates, []PendingUpdate{update}) {
+		t.Errorf("reloaded updates = %+v, want [%+v]", reloaded.Updates, update)
+	}
+}
+
+func TestLoadPendingCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPending(path)
+	if err == nil {
+		t.Fatal("LoadPending() should fail on a corrupt file")
+	}
+	if p == nil || p.Path() != path {
+		t.Errorf("LoadPending() should still return a usable queue, got %+v", p)
+	}
+}
+
+func TestResolvePendingPath(t *testing.T) {
+	configPath := filepath.Join("/etc", "mixcloud", "config.toml")
+	tests := []struct {
+		configured string
+		want       string
+	}{
+		{"", filepath.Join("/etc", "mixcloud", DefaultPendingFilename)},
+		{"pending.json", filepath.Join("/etc", "mixcloud", "pending.json")},
+		{"/var/lib/pending.json", "/var/lib/pending.json"},
+	}
+	for _, tt := range tests {
+		if got := ResolvePendingPath(tt.configured, configPath); got != tt.want {
+			t.Errorf("ResolvePendingPath(%q) = %q, want %q", tt.configured, got, tt.want)
+		}
+	}
+}
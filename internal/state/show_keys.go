@@ -0,0 +1,52 @@
+package state
+
+import (
+	"sort"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/textnorm"
+)
+
+// AIDEV-NOTE: The config stores shows under canonical keys (lower case, NFC,
+// see config.CanonicalShowKey). Files written while keys were used as typed,
+// e.g. "Late Night", are re-keyed on load so a show keeps its last publish,
+// history and queued updates.
+
+// foldShowKeys re-keys s.Shows by canonical key. When two keys fold together
+// the more recent publish is kept.
+func (s *State) foldShowKeys() {
+	for key, show := range s.Shows {
+		canonical := textnorm.FoldKey(key)
+		if canonical == key {
+			continue
+		}
+		delete(s.Shows, key)
+		if existing, ok := s.Shows[canonical]; ok && existing.LastPublished.After(show.LastPublished) {
+			continue
+		}
+		s.Shows[canonical] = show
+	}
+}
+
+// foldShowKeys re-keys h.Shows by canonical key, merging the entries of keys
+// that fold together oldest first
+func (h *History) foldShowKeys() {
+	for key, entries := range h.Shows {
+		canonical := textnorm.FoldKey(key)
+		if canonical == key {
+			continue
+		}
+		delete(h.Shows, key)
+		merged := append(h.Shows[canonical], entries...)
+		sort.SliceStable(merged, func(i, j int) bool {
+			return merged[i].PublishedAt.Before(merged[j].PublishedAt)
+		})
+		h.Shows[canonical] = merged
+	}
+}
+
+// foldShowKeys rewrites each queued update's show key in canonical form
+func (p *Pending) foldShowKeys() {
+	for i := range p.Updates {
+		p.Updates[i].ShowKey = textnorm.FoldKey(p.Updates[i].ShowKey)
+	}
+}
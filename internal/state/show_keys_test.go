@@ -0,0 +1,60 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeJSON writes content to name in a temp dir and returns its path
+func writeJSON(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFoldsShowKeys(t *testing.T) {
+	statePath := writeJSON(t, "state.json", `{"version": 1, "shows": {
+		"Late Night": {"last_published": "2025-06-20T22:00:00Z", "track_count": 10},
+		"late night": {"last_published": "2025-06-27T22:00:00Z", "track_count": 12},
+		"Música Nueva": {"last_published": "2025-06-28T22:00:00Z", "track_count": 9}
+	}}`)
+	s, err := Load(statePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := s.ShowKeys(); !reflect.DeepEqual(got, []string{"late night", "música nueva"}) {
+		t.Errorf("state show keys = %q, want the canonical keys", got)
+	}
+	if show, _ := s.Show("late night"); show.TrackCount != 12 {
+		t.Errorf("late night = %+v, want the more recent publish kept", show)
+	}
+
+	historyPath := writeJSON(t, "history.json", `{"version": 1, "shows": {
+		"Late Night": [{"published_at": "2025-06-13T22:00:00Z", "tracks": 13}, {"published_at": "2025-06-27T22:00:00Z", "tracks": 27}],
+		"late night": [{"published_at": "2025-06-20T22:00:00Z", "tracks": 20}]
+	}}`)
+	h, err := LoadHistory(historyPath, nil)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if got := entryDays(h.Shows["late night"]); !reflect.DeepEqual(got, []int{13, 20, 27}) || len(h.Shows) != 1 {
+		t.Errorf("late night history = %v (%d shows), want both keys' entries merged oldest first", got, len(h.Shows))
+	}
+
+	pendingPath := writeJSON(t, "pending.json", `{"version": 1, "updates": [{"show_key": "Música Nueva", "generated_url": "https://www.mixcloud.com/test/musica-nueva/"}]}`)
+	p, err := LoadPending(pendingPath)
+	if err != nil {
+		t.Fatalf("LoadPending() error = %v", err)
+	}
+	if p.Updates[0].ShowKey != "música nueva" {
+		t.Errorf("pending show key = %q, want música nueva", p.Updates[0].ShowKey)
+	}
+	if !p.Remove("música nueva", "https://www.mixcloud.com/test/musica-nueva/") {
+		t.Error("Remove() by canonical key found nothing")
+	}
+}
@@ -0,0 +1,246 @@
+// Package state persists per-show run history between invocations, such as when
+// each show was last published successfully. State lives in a small JSON file
+// next to the config so operators can answer "did the show update this week?"
+// without grepping logs.
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultFilename is the state file name used when processing.state_file is not set
+const DefaultFilename = "mixcloud-updater-state.json"
+
+// currentVersion is written to the state file to allow future format changes
+const currentVersion = 1
+
+// ShowState records the last successful publish of a single show
+type ShowState struct {
+	LastPublished   time.Time `json:"last_published"`
+	ShowURL         string    `json:"show_url"`
+	TrackCount      int       `json:"track_count"`
+	DescriptionHash string    `json:"description_hash"`
+}
+
+// CachedShow is a Mixcloud GetShow response kept with its HTTP cache validators,
+// so later runs can make conditional requests and reuse it on 304 Not Modified
+type CachedShow struct {
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	FetchedAt    time.Time       `json:"fetched_at"`
+	Body         json.RawMessage `json:"body"` // Response body as returned by the API
+}
+
+// UpdateCheck records the last query for a newer release
+type UpdateCheck struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	LatestVersion string    `json:"latest_version,omitempty"`
+	ReleaseURL    string    `json:"release_url,omitempty"`
+}
+
+// State holds run history for all shows, keyed by show key
+type State struct {
+	Version     int                   `json:"version"`
+	Shows       map[string]ShowState  `json:"shows"`
+	Cache       map[string]CachedShow `json:"cache,omitempty"`       // Keyed by cloudcast key
+	Renames     map[string]string     `json:"renames,omitempty"`     // URL generated from the show config → URL after update_name renamed it
+	UpdateCheck UpdateCheck           `json:"update_check,omitzero"` // Last processing.update_check or -check-update
+
+	path string
+}
+
+// DefaultPath returns the state file path for a config file when none is configured
+func DefaultPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), DefaultFilename)
+}
+
+// ResolvePath returns the state file to use: the configured path (relative paths
+// are resolved against the config file's directory) or DefaultPath
+func ResolvePath(configured, configPath string) string {
+	if configured == "" {
+		return DefaultPath(configPath)
+	}
+	if filepath.IsAbs(configured) {
+		return configured
+	}
+	return filepath.Join(filepath.Dir(configPath), configured)
+}
+
+// Load reads the state file at path. A missing file yields empty state.
+func Load(path string) (*State, error) {
+	s := &State{
+		Version: currentVersion,
+		Shows:   make(map[string]ShowState),
+		Cache:   make(map[string]CachedShow),
+		Renames: make(map[string]string),
+		path:    path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, fmt.Errorf("reading state file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, fmt.Errorf("parsing state file %s: %w", path, err)
+	}
+	if s.Shows == nil {
+		s.Shows = make(map[string]ShowState)
+	}
+	if s.Cache == nil {
+		s.Cache = make(map[string]CachedShow)
+	}
+	if s.Renames == nil {
+		s.Renames = make(map[string]string)
+	}
+	s.foldShowKeys()
+	s.path = path
+
+	return s, nil
+}
+
+// Path returns the file the state is loaded from and saved to
+func (s *State) Path() string {
+	return s.path
+}
+
+// Save writes the state file atomically (write to temp file, then rename)
+func (s *State) Save() error {
+	if s.path == "" {
+		return fmt.Errorf("state file path not set")
+	}
+
+	return writeJSONAtomic(s.path, s, "state")
+}
+
+// writeJSONAtomic writes v as indented JSON to path via a temp file and rename,
+// so a crash mid-write never leaves a half-written file. what names the file in errors.
+func writeJSONAtomic(path string, v interface{}, what string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", what, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s directory: %w", what, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing %s file %s: %w", what, tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("replacing %s file %s: %w", what, path, err)
+	}
+
+	return nil
+}
+
+// Show returns the recorded state for a show and whether it has ever been published
+func (s *State) Show(showKey string) (ShowState, bool) {
+	showState, ok := s.Shows[showKey]
+	return showState, ok
+}
+
+// RecordPublish stores a successful publish for a show
+func (s *State) RecordPublish(showKey string, showState ShowState) {
+	s.Shows[showKey] = showState
+}
+
+// CachedShow returns the cached GetShow response for a cloudcast key
+func (s *State) CachedShow(cloudcastKey string) (CachedShow, bool) {
+	cached, ok := s.Cache[cloudcastKey]
+	return cached, ok
+}
+
+// StoreCachedShow caches a GetShow response for a cloudcast key
+func (s *State) StoreCachedShow(cloudcastKey string, cached CachedShow) {
+	if s.Cache == nil {
+		s.Cache = make(map[string]CachedShow)
+	}
+	s.Cache[cloudcastKey] = cached
+}
+
+// RenamedURL returns where a show generated at showURL lives after update_name
+// renamed it on Mixcloud
+func (s *State) RenamedURL(showURL string) (string, bool) {
+	renamed, ok := s.Renames[showURL]
+	return renamed, ok
+}
+
+// RecordRename stores that the show generated at showURL now lives at newURL.
+// Renaming back to the generated URL drops the entry.
+func (s *State) RecordRename(showURL, newURL string) {
+	if s.Renames == nil {
+		s.Renames = make(map[string]string)
+	}
+	if newURL == showURL {
+		delete(s.Renames, showURL)
+		return
+	}
+	s.Renames[showURL] = newURL
+}
+
+// RecordUpdateCheck stores the result of a release check
+func (s *State) RecordUpdateCheck(check UpdateCheck) {
+	s.UpdateCheck = check
+}
+
+// ShowKeys returns the keys of all shows with recorded state, sorted
+func (s *State) ShowKeys() []string {
+	keys := make([]string, 0, len(s.Shows))
+	for key := range s.Shows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// HashDescription returns a stable hash of a published description
+func HashDescription(description string) string {
+	sum := sha256.Sum256([]byte(description))
+	return hex.EncodeToString(sum[:])
+}
+
+// FormatRelative renders the time since t in a short form like "3d ago"
+func FormatRelative(t, now time.Time) string {
+	age := now.Sub(t)
+	switch {
+	case age < 0:
+		return "in the future"
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
+
+// IsStale reports whether a show published at lastPublished is overdue given its
+// expected interval. One day of grace is allowed so a weekly show is only flagged
+// after 8 days. Shows without an expected interval are never stale.
+func IsStale(lastPublished time.Time, expectedIntervalDays int, now time.Time) bool {
+	if expectedIntervalDays <= 0 {
+		return false
+	}
+	if lastPublished.IsZero() {
+		return true
+	}
+	threshold := time.Duration(expectedIntervalDays+1) * 24 * time.Hour
+	return now.Sub(lastPublished) > threshold
+}
@@ -0,0 +1,256 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFileReturnsEmptyState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(s.Shows) != 0 {
+		t.Errorf("expected empty state, got %d shows", len(s.Shows))
+	}
+	if _, ok := s.Show("jazz"); ok {
+		t.Error("Show() should report missing show as not published")
+	}
+}
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	published := time.Date(2025, 6, 28, 14, 3, 0, 0, time.UTC)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	s.RecordPublish("jazz", ShowState{
+		LastPublished:   published,
+		ShowURL:         "https://www.mixcloud.com/station/jazz-6282025/",
+		TrackCount:      14,
+		DescriptionHash: HashDescription("00:00 - \"Song\" by Artist"),
+	})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary state file should not be left behind")
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after save error = %v", err)
+	}
+	got, ok := reloaded.Show("jazz")
+	if !ok {
+		t.Fatal("reloaded state is missing show 'jazz'")
+	}
+	if !got.LastPublished.Equal(published) {
+		t.Errorf("LastPublished = %v, want %v", got.LastPublished, published)
+	}
+	if got.TrackCount != 14 || got.ShowURL == "" || got.DescriptionHash == "" {
+		t.Errorf("reloaded show state incomplete: %+v", got)
+	}
+}
+
+func TestCachedShowRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	const key = "station/jazz-6282025"
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, ok := s.CachedShow(key); ok {
+		t.Fatal("CachedShow() should report nothing cached without a state file")
+	}
+
+	s.StoreCachedShow(key, CachedShow{
+		ETag:         `"abc123"`,
+		LastModified: "Sat, 28 Jun 2025 14:03:00 GMT",
+		FetchedAt:    time.Date(2025, 6, 28, 14, 3, 0, 0, time.UTC),
+		Body:         []byte(`{"key":"/station/jazz-6282025/","name":"Jazz"}`),
+	})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after save error = %v", err)
+	}
+	got, ok := reloaded.CachedShow(key)
+	if !ok {
+		t.Fatal("reloaded state is missing the cached show")
+	}
+	if got.ETag != `"abc123"` || got.LastModified == "" {
+		t.Errorf("reloaded validators = %q / %q", got.ETag, got.LastModified)
+	}
+	// The body is re-indented with the state file but must stay the same JSON
+	var body bytes.Buffer
+	if err := json.Compact(&body, got.Body); err != nil || body.String() != `{"key":"/station/jazz-6282025/","name":"Jazz"}` {
+		t.Errorf("reloaded body = %s (err %v)", got.Body, err)
+	}
+}
+
+func TestUpdateCheckRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// Installs without update_check keep the state file free of the field
+	if data, _ := os.ReadFile(path); bytes.Contains(data, []byte("update_check")) {
+		t.Errorf("state without a check contains update_check:\n%s", data)
+	}
+
+	check := UpdateCheck{
+		CheckedAt:     time.Date(2025, 6, 28, 14, 3, 0, 0, time.UTC),
+		LatestVersion: "v1.2.0",
+		ReleaseURL:    "https://github.com/nowwaveradio/mixcloud-updater/releases/tag/v1.2.0",
+	}
+	s.RecordUpdateCheck(check)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after save error = %v", err)
+	}
+	if !reloaded.UpdateCheck.CheckedAt.Equal(check.CheckedAt) || reloaded.UpdateCheck.LatestVersion != check.LatestVersion ||
+		reloaded.UpdateCheck.ReleaseURL != check.ReleaseURL {
+		t.Errorf("reloaded UpdateCheck = %+v, want %+v", reloaded.UpdateCheck, check)
+	}
+}
+
+func TestLoadCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(path)
+	if err == nil {
+		t.Error("Load() expected error for corrupt state file")
+	}
+	if s == nil || s.Shows == nil || s.Path() != path {
+		t.Error("Load() should still return usable empty state on error")
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		configPath string
+		expected   string
+	}{
+		{"default next to config", "", "/etc/mixcloud/config.toml", "/etc/mixcloud/" + DefaultFilename},
+		{"relative to config dir", "state/run.json", "/etc/mixcloud/config.toml", "/etc/mixcloud/state/run.json"},
+		{"absolute path", "/var/lib/mixcloud/state.json", "/etc/mixcloud/config.toml", "/var/lib/mixcloud/state.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolvePath(tt.configured, tt.configPath); got != filepath.FromSlash(tt.expected) {
+				t.Errorf("ResolvePath() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatRelative(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected string
+	}{
+		{"seconds", now.Add(-30 * time.Second), "just now"},
+		{"minutes", now.Add(-15 * time.Minute), "15m ago"},
+		{"hours", now.Add(-5 * time.Hour), "5h ago"},
+		{"days", now.Add(-3*24*time.Hour - time.Hour), "3d ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatRelative(tt.t, now); got != tt.expected {
+				t.Errorf("FormatRelative() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		lastPublished time.Time
+		intervalDays  int
+		expected      bool
+	}{
+		{"no interval configured", now.Add(-30 * 24 * time.Hour), 0, false},
+		{"weekly show within grace", now.Add(-8 * 24 * time.Hour), 7, false},
+		{"weekly show overdue", now.Add(-8*24*time.Hour - time.Hour), 7, true},
+		{"never published", time.Time{}, 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStale(tt.lastPublished, tt.intervalDays, now); got != tt.expected {
+				t.Errorf("IsStale() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenameRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	const (
+		generated = "https://www.mixcloud.com/station/nnw-6282025/"
+		renamed   = "https://www.mixcloud.com/station/the-newer-new-wave-show-june-28-2025/"
+	)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, ok := s.RenamedURL(generated); ok {
+		t.Fatal("RenamedURL() should report nothing without a state file")
+	}
+
+	s.RecordRename(generated, renamed)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after save error = %v", err)
+	}
+	if got, ok := reloaded.RenamedURL(generated); !ok || got != renamed {
+		t.Errorf("RenamedURL() = %q, %v, want %q", got, ok, renamed)
+	}
+
+	// Renaming back to the generated slug needs no redirect
+	reloaded.RecordRename(generated, generated)
+	if _, ok := reloaded.RenamedURL(generated); ok {
+		t.Error("RenamedURL() still set after renaming back to the generated URL")
+	}
+}
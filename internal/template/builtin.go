@@ -0,0 +1,130 @@
+package template
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
+)
+
+// AIDEV-NOTE: Built-in templates ship with the binary, so template = "numbered"
+// works without a [templates.config] block. A user-defined template of the same
+// name replaces the built-in one. "classic" and "compact" are output modes the
+// formatter renders itself ("classic" reserves room for the announcement and
+// provenance lines and truncates with "... and more"), but classic still lists
+// each track through the classic template's track line, so the line format is
+// defined once, here. track_style = "artist-first" swaps in artistFirstTrackLine
+// for classic output and the trackLine template function.
+
+// Built-in template names
+const (
+	ClassicTemplateName  = "classic"
+	CompactTemplateName  = "compact"
+	NumberedTemplateName = "numbered"
+	DetailedTemplateName = "detailed"
+)
+
+// classicTrackLine is a track in classic format: 00:25 - "When I Fall" by Laura Dre
+const classicTrackLine = `{{.StartTime}} - "{{.Title}}" by {{.Artist}}`
+
+// artistFirstTrackLine is a track in artist-first style: 00:25 - Laura Dre - "When I Fall"
+const artistFirstTrackLine = `{{.StartTime}} - {{.Artist}} - "{{.Title}}"`
+
+// builtinTemplates are the built-in templates written as templates; compact has
+// no template text
+var builtinTemplates = map[string]config.TemplateConfig{
+	ClassicTemplateName: {
+		Track: classicTrackLine + "\n",
+	},
+	NumberedTemplateName: {
+		Track:  "{{.Index}}. " + classicTrackLine + "\n",
+		Footer: "\n{{.TrackCount}} tracks\n",
+	},
+	DetailedTemplateName: {
+		Header: "{{.ShowTitle}} - {{.ShowDate}}\n\n",
+		Track:  classicTrackLine + "{{with .Genre}} ({{.}}){{end}}\n",
+		Footer: "{{with .StationName}}\n{{.}}\n{{end}}",
+	},
+}
+
+// trackLines render a track in each track_style, for the formatter's classic
+// mode and trackLine
+var trackLines = map[string]*template.Template{
+	config.TrackStyleTitleFirst:  template.Must(template.New(config.TrackStyleTitleFirst).Parse(classicTrackLine)),
+	config.TrackStyleArtistFirst: template.Must(template.New(config.TrackStyleArtistFirst).Parse(artistFirstTrackLine)),
+}
+
+// IsBuiltinTemplate reports whether name is a built-in template or output mode
+func IsBuiltinTemplate(name string) bool {
+	_, ok := builtinTemplates[name]
+	return ok || name == CompactTemplateName
+}
+
+// BuiltinTemplateNames returns the names of the built-in templates, sorted
+func BuiltinTemplateNames() []string {
+	names := []string{CompactTemplateName}
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinTemplate returns the definition of a built-in template; false for
+// compact, which isn't written as a template
+func BuiltinTemplate(name string) (config.TemplateConfig, bool) {
+	tmpl, ok := builtinTemplates[name]
+	return tmpl, ok
+}
+
+// OverriddenBuiltins returns the built-in templates cfg defines its own
+// template for, sorted
+func OverriddenBuiltins(cfg *config.Config) []string {
+	var names []string
+	for _, name := range BuiltinTemplateNames() {
+		if _, ok := cfg.Templates.Config[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// RenderTrackLine renders one track's fields as they are in a track_style,
+// without a newline; an unknown style renders title-first. The formatter's
+// classic mode fills in missing fields first.
+func RenderTrackLine(track FormattedTrack, style string) string {
+	tmpl, ok := trackLines[style]
+	if !ok {
+		tmpl = trackLines[config.TrackStyleTitleFirst]
+	}
+	var line strings.Builder
+	// Only plain string fields are read, so executing can't fail
+	_ = tmpl.Execute(&line, track)
+	return line.String()
+}
+
+// TrackLine renders a track the way classic output lists it, for the trackLine
+// template function: a missing start time becomes 00:00, a missing title or
+// artist msgs' stand-in, and double quotes in the title single quotes
+func TrackLine(track FormattedTrack, style string, msgs messages.Catalog) string {
+	startTime := strings.TrimSpace(track.StartTime)
+	title := strings.TrimSpace(track.Title)
+	artist := strings.TrimSpace(track.Artist)
+	if startTime == "" {
+		startTime = "00:00"
+	}
+	if title == "" {
+		title = msgs.UnknownTitle
+	}
+	if artist == "" {
+		artist = msgs.UnknownArtist
+	}
+
+	return RenderTrackLine(FormattedTrack{
+		StartTime: startTime,
+		Title:     strings.ReplaceAll(title, `"`, `'`),
+		Artist:    artist,
+	}, style)
+}
@@ -0,0 +1,127 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/links"
+)
+
+// AIDEV-NOTE: collapse_same_artist is for album hours, where four tracks in a
+// row by one artist read as a list of repeats. Artists are compared with the
+// links table's normalization, so "the cure" and "The  Cure" merge, and so does
+// a featuring credit - "X feat. Y" is the same artist as "X" there. Tracks
+// without an artist never merge: "(Unknown Artist)" isn't one artist.
+
+// DefaultCollapseSeparator joins the titles of a collapsed entry
+const DefaultCollapseSeparator = " / "
+
+// ArtistCollapse configures collapse_same_artist and the groupByArtist template function
+type ArtistCollapse struct {
+	Enabled   bool   // collapse_same_artist: the formatter collapses every track mode
+	Separator string // Between titles (default: DefaultCollapseSeparator)
+	MaxTitles int    // Titles listed before "+N more" (0 = all)
+}
+
+// SameArtist reports whether two artist credits collapse into one entry:
+// both are set and links.NormalizeArtist makes them equal
+func SameArtist(a, b string) bool {
+	a, b = links.NormalizeArtist(a), links.NormalizeArtist(b)
+	return a != "" && a == b
+}
+
+// CollapseTracks merges each run of consecutive tracks by the same artist into
+// one track with the run's first start time and artist and its titles joined,
+// e.g. "Title 1 / Title 2 / +2 more". Single tracks are returned unchanged.
+func CollapseTracks(tracks []cue.Track, collapse ArtistCollapse) []cue.Track {
+	runs := artistRuns(len(tracks), func(i int) string { return tracks[i].Artist })
+	if len(runs) == len(tracks) {
+		return tracks
+	}
+
+	result := make([]cue.Track, 0, len(runs))
+	start := 0
+	for _, end := range runs {
+		merged := tracks[start]
+		if end-start > 1 {
+			titles := make([]string, 0, end-start)
+			for _, track := range tracks[start:end] {
+				titles = append(titles, track.Title)
+				if track.Label != merged.Label || track.Year != merged.Year {
+					merged.Label, merged.Year = "", "" // Only a label and year every title shares
+				}
+			}
+			merged.Title = collapse.joinTitles(titles)
+			merged.ISRC = "" // One code can't stand for several recordings
+		}
+		result = append(result, merged)
+		start = end
+	}
+	return result
+}
+
+// GroupByArtist is CollapseTracks for template tracks, renumbered and relinked
+// to their new neighbours. Tracks listed newest first keep the newest start time.
+func GroupByArtist(tracks []FormattedTrack, collapse ArtistCollapse) []FormattedTrack {
+	runs := artistRuns(len(tracks), func(i int) string { return tracks[i].Artist })
+	if len(runs) == len(tracks) {
+		return tracks
+	}
+
+	result := make([]FormattedTrack, 0, len(runs))
+	start := 0
+	for _, end := range runs {
+		merged := tracks[start]
+		merged.Index = len(result) + 1
+		merged.PrevTrack, merged.NextTrack = nil, nil
+		if end-start > 1 {
+			titles := make([]string, 0, end-start)
+			for _, track := range tracks[start:end] {
+				titles = append(titles, track.Title)
+				if track.Label != merged.Label || track.Year != merged.Year {
+					merged.Label, merged.Year = "", ""
+				}
+			}
+			merged.Title = collapse.joinTitles(titles)
+			merged.ISRC = ""
+			merged.Duration = ""
+		}
+		result = append(result, merged)
+		start = end
+	}
+	linkNeighbours(result)
+	return result
+}
+
+// artistRuns returns the end index (exclusive) of each run of consecutive
+// entries by the same artist among n entries
+func artistRuns(n int, artist func(int) string) []int {
+	var runs []int
+	for i := 1; i <= n; i++ {
+		if i == n || !SameArtist(artist(i-1), artist(i)) {
+			runs = append(runs, i)
+		}
+	}
+	return runs
+}
+
+// joinTitles joins a run's titles with the separator, listing at most
+// MaxTitles and counting the rest as "+N more". Empty titles are left out.
+func (c ArtistCollapse) joinTitles(titles []string) string {
+	separator := c.Separator
+	if separator == "" {
+		separator = DefaultCollapseSeparator
+	}
+
+	var kept []string
+	for _, title := range titles {
+		if title = strings.TrimSpace(title); title != "" {
+			kept = append(kept, title)
+		}
+	}
+	if c.MaxTitles > 0 && len(kept) > c.MaxTitles {
+		kept = append(kept[:c.MaxTitles], fmt.Sprintf("+%d more", len(kept)-c.MaxTitles))
+	}
+	return strings.Join(kept, separator)
+}
@@ -0,0 +1,158 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+func TestSameArtist(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"The Cure", "The Cure", true},
+		{"The Cure", "the  cure ", true},
+		{"The Cure", "Cure", true},
+		{"Laura Dre feat. Kid Moxie", "Laura Dre", true},
+		{"Laura Dre (ft. Kid Moxie)", "laura dre featuring Someone Else", true},
+		{"Laura Dre & Kid Moxie", "Laura Dre", false},
+		{"Laura Dre", "Laura Dree", false},
+		{"", "", false},
+		{" ", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameArtist(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameArtist(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCollapseTracks(t *testing.T) {
+	// Each track is "artist:title"; entries come back as "start artist:title"
+	tests := []struct {
+		name     string
+		tracks   []string
+		collapse ArtistCollapse
+		want     []string
+	}{
+		{
+			name:   "album hour",
+			tracks: []string{"Laura Dre:One", "Laura Dre:Two", "Laura Dre:Three", "Laura Dre:Four"},
+			want:   []string{"00:00 Laura Dre:One / Two / Three / Four"},
+		},
+		{
+			name:   "mixed sequence",
+			tracks: []string{"A:1", "B:2", "B:3", "A:4", "C:5", "C:6", "C:7", "A:8"},
+			want:   []string{"00:00 A:1", "01:00 B:2 / 3", "03:00 A:4", "04:00 C:5 / 6 / 7", "07:00 A:8"},
+		},
+		{
+			name:   "case and whitespace differences",
+			tracks: []string{"Pure Obsessions:1", "pure  obsessions:2", " PURE OBSESSIONS:3"},
+			want:   []string{"00:00 Pure Obsessions:1 / 2 / 3"},
+		},
+		{
+			name:   "featuring credit",
+			tracks: []string{"Laura Dre:1", "Laura Dre feat. Kid Moxie:2", "Laura Dre & Kid Moxie:3"},
+			want:   []string{"00:00 Laura Dre:1 / 2", "02:00 Laura Dre & Kid Moxie:3"},
+		},
+		{
+			name:   "unknown artists stay apart",
+			tracks: []string{":1", ":2", "A:3"},
+			want:   []string{"00:00 :1", "01:00 :2", "02:00 A:3"},
+		},
+		{
+			name:     "separator and cap",
+			tracks:   []string{"A:1", "A:2", "A:3", "A:4", "A:5", "B:6"},
+			collapse: ArtistCollapse{Separator: "; ", MaxTitles: 2},
+			want:     []string{"00:00 A:1; 2; +3 more", "05:00 B:6"},
+		},
+		{
+			name:     "cap not reached",
+			tracks:   []string{"A:1", "A:2"},
+			collapse: ArtistCollapse{MaxTitles: 2},
+			want:     []string{"00:00 A:1 / 2"},
+		},
+		{
+			name:   "empty title left out",
+			tracks: []string{"A:1", "A:", "A:3"},
+			want:   []string{"00:00 A:1 / 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tracks []cue.Track
+			for i, spec := range tt.tracks {
+				artist, title, _ := strings.Cut(spec, ":")
+				tracks = append(tracks, cue.Track{
+					Index:     i + 1,
+					StartTime: "0" + string(rune('0'+i)) + ":00",
+					Artist:    artist,
+					Title:     title,
+					ISRC:      "ISRC",
+				})
+			}
+
+			collapsed := CollapseTracks(tracks, tt.collapse)
+			var got []string
+			for _, track := range collapsed {
+				got = append(got, track.StartTime+" "+track.Artist+":"+track.Title)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("CollapseTracks() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if tracks[0].Title != strings.SplitN(tt.tracks[0], ":", 2)[1] {
+				t.Error("CollapseTracks changed the caller's tracks")
+			}
+		})
+	}
+}
+
+func TestGroupByArtist(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"grouped": {
+			Track: `{{/* listed by artist in the footer */}}`,
+			Footer: `{{range groupByArtist .Tracks}}{{.Index}}. {{.StartTime}} {{.Artist}} - {{.Title}}` +
+				`{{with .NextTrack}} (then {{.Artist}}){{end}}` + "\n{{end}}",
+		},
+	}
+	tf := NewTemplateFormatter(cfg)
+	if err := tf.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	tracks := []cue.Track{
+		{StartTime: "21:00", Artist: "Laura Dre", Title: "One"},
+		{StartTime: "21:04", Artist: "laura dre", Title: "Two"},
+		{StartTime: "21:08", Artist: "Laura Dre", Title: "Three"},
+		{StartTime: "21:12", Artist: "Pure Obsessions", Title: "Four"},
+	}
+
+	tests := []struct {
+		name     string
+		collapse ArtistCollapse
+		want     string
+	}{
+		{"default separator", ArtistCollapse{},
+			"1. 21:00 Laura Dre - One / Two / Three (then Pure Obsessions)\n2. 21:12 Pure Obsessions - Four\n"},
+		{"show's separator and cap", ArtistCollapse{Separator: " · ", MaxTitles: 1},
+			"1. 21:00 Laura Dre - One · +2 more (then Pure Obsessions)\n2. 21:12 Pure Obsessions - Four\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tf.FormatWithTemplate("grouped", tracks, nil, Metadata{Collapse: tt.collapse})
+			if err != nil {
+				t.Fatalf("FormatWithTemplate() error = %v", err)
+			}
+			if !strings.HasSuffix(got, tt.want) {
+				t.Errorf("result = %q, want it to end with %q", got, tt.want)
+			}
+		})
+	}
+}
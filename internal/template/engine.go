@@ -6,19 +6,25 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"sync"
 	"text/template"
-	"time"
 
+	"github.com/nowwaveradio/mixcloud-updater/internal/analysis"
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
 	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
 	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/desclen"
 	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/links"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
+	"github.com/nowwaveradio/mixcloud-updater/internal/textcut"
 )
 
 // TemplateFormatter provides template-based tracklist formatting
 type TemplateFormatter struct {
 	templates map[string]*template.Template
 	config    *config.Config
+	links     *links.Table // artistLink lookups; nil when no links_file is configured
 }
 
 // TemplateData represents the data structure passed to templates for execution
@@ -28,17 +34,90 @@ type TemplateData struct {
 	TrackCount   int              `json:"track_count"`
 	Tracks       []FormattedTrack `json:"tracks"`
 	StationName  string           `json:"station_name"`
+	Announcement string           `json:"announcement"` // station.announcement_source text, "" when there is none
+	Catalog      string           `json:"catalog"` // CUE sheet CATALOG, "" if absent
 	Custom       map[string]interface{} `json:"custom"` // user-defined variables
+	Show         ShowInfo         `json:"show"`    // The show being rendered, empty outside show runs
+
+	// Filtering outcome, so descriptions can note omitted items
+	IncludedCount   int            `json:"included_count"`   // Tracks left after filtering
+	ExcludedCount   int            `json:"excluded_count"`   // Tracks removed by the content filters
+	ExcludedReasons map[string]int `json:"excluded_reasons"` // Excluded tracks per filter reason, e.g. "excluded_genre"
+
+	// Provenance, for tracing a published tracklist back to its source
+	CueFileName string `json:"cue_file_name"` // Base name of the CUE file, e.g. "MYR40628.cue"
+	GeneratedAt string `json:"generated_at"`  // When the description was rendered, e.g. "2025-06-28 22:14"
+	ToolVersion string `json:"tool_version"`  // mixcloud-updater version, e.g. "1.0.0"
+	Provenance  string `json:"provenance"`    // The three above as one line, see ProvenanceLine
+
+	// Position of this description in a show uploaded in parts (split_at),
+	// 1 of 1 for shows that aren't split
+	PartNumber int `json:"part_number"`
+	PartCount  int `json:"part_count"`
+
+	// Tracks grouped by the hour they start in, see hour_header
+	Hours []HourGroup `json:"hours"`
+
+	// Distinct genres of the show's filtered tracks, most common first and
+	// capped by formatting.max_genres, e.g. ["Post-Punk", "Synth-Pop"]
+	Genres []string `json:"genres"`
+}
+
+// ShowInfo exposes a show's configuration to templates, so one template can
+// vary per show: {{.Show.Vars.host}} or {{if hasFlag "genres"}}
+type ShowInfo struct {
+	Key   string            `json:"key"`   // Show key from the config, e.g. "newer-new-wave"
+	Vars  map[string]string `json:"vars"`  // The show's template_vars
+	Flags []string          `json:"flags"` // The show's flags, e.g. ["genres", "social"]
+}
+
+// HasFlag reports whether the show sets flag
+func (s ShowInfo) HasFlag(flag string) bool {
+	for _, f := range s.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// ProvenanceLine formats the provenance footer, e.g.
+// "Generated 2025-06-28 22:14 from MYR40628.cue by mixcloud-updater v1.0.0".
+// Parts without a value are left out.
+func ProvenanceLine(cueFileName, generatedAt, toolVersion string) string {
+	line := "Generated"
+	if generatedAt != "" {
+		line += " " + generatedAt
+	}
+	if cueFileName != "" {
+		line += " from " + cueFileName
+	}
+	line += " by mixcloud-updater"
+	if toolVersion != "" {
+		line += " v" + toolVersion
+	}
+	return line
 }
 
 // FormattedTrack represents a single track for template processing
 type FormattedTrack struct {
-	Index     int    `json:"index"`
-	StartTime string `json:"start_time"`
-	Artist    string `json:"artist"`
-	Title     string `json:"title"`
-	Genre     string `json:"genre"`
-	Duration  string `json:"duration"`
+	Index         int    `json:"index"`          // Position as printed, 1 for the first track listed
+	OriginalIndex int    `json:"original_index"` // Position in CUE order; differs from Index with track_order = "reverse"
+	StartTime     string `json:"start_time"`
+	Artist        string `json:"artist"`
+	Title         string `json:"title"`
+	Genre         string `json:"genre"`
+	Duration      string `json:"duration"`
+	ISRC          string `json:"isrc"` // "" when the CUE file has none, never "<no value>"
+	Label         string `json:"label"` // From the [enrichment] database, "" when it has no match
+	Year          string `json:"year"`  // From the [enrichment] database, "" when it has no match
+
+	// Neighbouring tracks for segue lines, nil for the first and last track.
+	// AIDEV-NOTE: These are copies without their own links, so .NextTrack.NextTrack
+	// is always nil and the structure can't form a cycle; they're also left out of
+	// JSON so marshalled tracks don't repeat their neighbours.
+	PrevTrack *FormattedTrack `json:"-"`
+	NextTrack *FormattedTrack `json:"-"`
 }
 
 // getTemplateFuncMap returns the shared function map for all templates
@@ -49,11 +128,9 @@ func getTemplateFuncMap() template.FuncMap {
 		"upper":  strings.ToUpper,
 		"lower":  strings.ToLower,
 		"title":  strings.Title,
+		// Counts characters, not bytes, and never cuts one apart
 		"truncate": func(s string, n int) string {
-			if len(s) <= n {
-				return s
-			}
-			return s[:n] + "..."
+			return textcut.Truncate(s, n, "...")
 		},
 		"printf": fmt.Sprintf,
 		"join": func(sep string, items []string) string {
@@ -65,6 +142,30 @@ func getTemplateFuncMap() template.FuncMap {
 		"sub": func(a, b int) int {
 			return a - b
 		},
+		// Bound per render by withRenderFuncs; without a links table there are no
+		// links, and without a show no flags
+		"artistLink": func(artist string) string {
+			return ""
+		},
+		"hasFlag": func(flag string) bool {
+			return false
+		},
+		// A track as classic output lists it; bound per render to the show's
+		// track_style and unknown-field strings
+		"trackLine": func(track FormattedTrack) string {
+			return TrackLine(track, config.TrackStyleTitleFirst, messages.Default())
+		},
+		// Consecutive tracks by one artist as one entry with their titles joined;
+		// bound per render to the show's collapse_separator and collapse_max_titles
+		"groupByArtist": func(tracks []FormattedTrack) []FormattedTrack {
+			return GroupByArtist(tracks, ArtistCollapse{})
+		},
+		// For HTML or Markdown output; output_mode applies them to every value
+		"escapeHTML":         escapeHTML,
+		"escapeMarkdown":     escapeMarkdown,
+		"stripHTML":          stripHTML,
+		"autoEscapeHTML":     autoEscapeHTML,
+		"autoEscapeMarkdown": autoEscapeMarkdown,
 	}
 }
 
@@ -76,6 +177,64 @@ func NewTemplateFormatter(cfg *config.Config) *TemplateFormatter {
 	}
 }
 
+// SetLinks sets the artist table used by the artistLink template function
+func (tf *TemplateFormatter) SetLinks(table *links.Table) {
+	tf.links = table
+}
+
+// withRenderFuncs returns a copy of tmpl with the functions that depend on the
+// render bound: artistLink looks artists up in the links table, handing out at
+// most maxLinks URLs per render (0 = no cap), hasFlag checks the show's flags,
+// trackLine uses the show's track_style and strings and groupByArtist its
+// collapse_separator and collapse_max_titles
+// AIDEV-NOTE: The copy keeps the link count per render, so concurrent renders
+// of the same template can't share a cap. hasFlag is a function rather than a
+// .Show lookup so track templates, whose dot is the track, can use it too.
+func (tf *TemplateFormatter) withRenderFuncs(tmpl *template.Template, metadata Metadata) (*template.Template, error) {
+	maxLinks, show := metadata.MaxLinks, metadata.Show()
+	funcs := template.FuncMap{}
+	if tf.links.Len() > 0 {
+		given := 0
+		funcs["artistLink"] = func(artist string) string {
+			if maxLinks > 0 && given >= maxLinks {
+				return ""
+			}
+			url := tf.links.Lookup(artist)
+			if url != "" {
+				given++
+			}
+			return url
+		}
+	}
+	if len(show.Flags) > 0 {
+		funcs["hasFlag"] = show.HasFlag
+	}
+	style := metadata.TrackStyle
+	if style == "" {
+		style = tf.config.TrackStyleFor(nil)
+	}
+	if style != config.TrackStyleTitleFirst || metadata.Messages != (messages.Catalog{}) {
+		msgs := metadata.Localized()
+		funcs["trackLine"] = func(track FormattedTrack) string {
+			return TrackLine(track, style, msgs)
+		}
+	}
+	if collapse := metadata.Collapse; collapse.Separator != "" || collapse.MaxTitles > 0 {
+		funcs["groupByArtist"] = func(tracks []FormattedTrack) []FormattedTrack {
+			return GroupByArtist(tracks, collapse)
+		}
+	}
+	if len(funcs) == 0 {
+		return tmpl, nil
+	}
+
+	bound, err := tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("cloning template: %w", err)
+	}
+	return bound.Funcs(funcs), nil
+}
+
 // LoadTemplates parses template definitions from config and registers custom functions
 func (tf *TemplateFormatter) LoadTemplates() error {
 	if tf.config == nil {
@@ -88,6 +247,17 @@ func (tf *TemplateFormatter) LoadTemplates() error {
 	// Get shared function map
 	funcMap := getTemplateFuncMap()
 
+	// Built-in templates first, so user-defined ones of the same name replace
+	// them; classic is rendered by the formatter
+	for name, templateConfig := range builtinTemplates {
+		if name == ClassicTemplateName {
+			continue
+		}
+		if err := tf.loadSingleTemplate(name, templateConfig, funcMap); err != nil {
+			return fmt.Errorf("loading built-in template %s: %w", name, err)
+		}
+	}
+
 	// Load each template from config
 	for name, templateConfig := range tf.config.Templates.Config {
 		if err := tf.loadSingleTemplate(name, templateConfig, funcMap); err != nil {
@@ -114,10 +284,23 @@ func (tf *TemplateFormatter) loadSingleTemplate(name string, templateConfig conf
 	if templateConfig.Track == "" {
 		return fmt.Errorf("track template is required")
 	}
+	if !config.ValidOutputMode(templateConfig.OutputMode) {
+		return fmt.Errorf(`output_mode %q must be "plain", "html" or "markdown"`, templateConfig.OutputMode)
+	}
+	if err := CheckProtectedSpace(tf.config, name, constants.MixcloudDescriptionLimit); err != nil {
+		return err
+	}
 	templateText.WriteString("{{define \"track\"}}")
 	templateText.WriteString(templateConfig.Track)
 	templateText.WriteString("{{end}}")
 	
+	// Add hour header template, rendered before each hour's first track
+	if templateConfig.HourHeader != "" {
+		templateText.WriteString("{{define \"hour\"}}")
+		templateText.WriteString(templateConfig.HourHeader)
+		templateText.WriteString("{{end}}")
+	}
+
 	// Add footer template
 	if templateConfig.Footer != "" {
 		templateText.WriteString("{{define \"footer\"}}")
@@ -130,13 +313,14 @@ func (tf *TemplateFormatter) loadSingleTemplate(name string, templateConfig conf
 	if err != nil {
 		return fmt.Errorf("parsing template: %w", err)
 	}
+	applyOutputMode(tmpl, templateConfig.OutputMode)
 
 	tf.templates[name] = tmpl
 	return nil
 }
 
 // FormatWithTemplate executes a template with track data while respecting character limits
-func (tf *TemplateFormatter) FormatWithTemplate(templateName string, tracks []cue.Track, fltr *filter.Filter, metadata map[string]interface{}) (string, error) {
+func (tf *TemplateFormatter) FormatWithTemplate(templateName string, tracks []cue.Track, fltr *filter.Filter, metadata Metadata) (string, error) {
 	// Check if template exists
 	tmpl, exists := tf.templates[templateName]
 	if !exists {
@@ -146,15 +330,22 @@ func (tf *TemplateFormatter) FormatWithTemplate(templateName string, tracks []cu
 	// Build template data
 	templateData := tf.buildTemplateData(tracks, metadata)
 
+	tmpl, err := tf.withRenderFuncs(tmpl, metadata)
+	if err != nil {
+		return "", err
+	}
+
+	// The protected lines' space is set aside before anything else is measured
+	protected := ProtectedFor(tf.config, templateName)
+	maxLength := protected.Reserve(metadata.Limit(constants.MixcloudDescriptionLimit))
 	var result strings.Builder
+	result.Grow(maxLength)
 
 	// Execute header template if it exists
 	if tmpl.Lookup("header") != nil {
-		var headerBuf bytes.Buffer
-		if err := tmpl.ExecuteTemplate(&headerBuf, "header", templateData); err != nil {
+		if err := tmpl.ExecuteTemplate(&result, "header", templateData); err != nil {
 			return "", fmt.Errorf("executing header template: %w", err)
 		}
-		result.WriteString(headerBuf.String())
 	}
 
 	// Execute track template for each track with smart truncation
@@ -163,8 +354,8 @@ func (tf *TemplateFormatter) FormatWithTemplate(templateName string, tracks []cu
 		return "", fmt.Errorf("track template not found")
 	}
 
-	const maxLength = constants.MixcloudDescriptionLimit
-	currentLength := result.Len()
+	measure := tf.config.DescriptionLengthModel().Length
+	currentLength := measure(result.String())
 
 	// Pre-calculate footer size to reserve space
 	var footerOutput string
@@ -175,46 +366,64 @@ func (tf *TemplateFormatter) FormatWithTemplate(templateName string, tracks []cu
 			return "", fmt.Errorf("executing footer template: %w", err)
 		}
 		footerOutput = footerBuf.String()
-		footerLength = len(footerOutput)
+		footerLength = measure(footerOutput)
 	}
 
-	// Reserve space for footer and potential truncation message
-	const truncationMargin = 50 // Space for "... and more tracks" type messages
-	availableLength := maxLength - currentLength - footerLength - truncationMargin
+	// Reserve space for footer and potential truncation message, measured in the
+	// show's language with the widest count it can carry
+	msgs := metadata.Localized()
+	margin := truncationMargin
+	if needed := measure(msgs.MoreTracksText(len(templateData.Tracks)) + "\n"); needed > margin {
+		margin = needed
+	}
+	availableLength := maxLength - currentLength - footerLength - margin
 
-	var trackOutputs []string
+	// Track positions that open an hour, when the template has an hour header
+	hourStarts := make(map[int]HourGroup)
+	if tmpl.Lookup("hour") != nil {
+		position := 0
+		for _, group := range templateData.Hours {
+			hourStarts[position] = group
+			position += len(group.Tracks)
+		}
+	}
+
+	// Tracks are rendered one at a time straight into result, stopping at the
+	// first that doesn't fit: the tracks after it are counted, never rendered
+	trackBuf := trackBufferPool.Get().(*bytes.Buffer)
+	defer trackBufferPool.Put(trackBuf)
+	raw := tf.config.DescriptionLengthModel() == desclen.ModelRaw
 	totalTrackLength := 0
 
-	// Generate all track outputs and calculate total length
-	for _, track := range templateData.Tracks {
-		var trackBuf bytes.Buffer
-		if err := tmpl.ExecuteTemplate(&trackBuf, "track", track); err != nil {
+	for i, track := range templateData.Tracks {
+		trackBuf.Reset()
+		// The hour header is kept or dropped together with the hour's first track
+		if group, ok := hourStarts[i]; ok {
+			if err := tmpl.ExecuteTemplate(trackBuf, "hour", group); err != nil {
+				return "", fmt.Errorf("executing hour header template: %w", err)
+			}
+		}
+		if err := tmpl.ExecuteTemplate(trackBuf, "track", track); err != nil {
 			return "", fmt.Errorf("executing track template: %w", err)
 		}
 
-		trackOutput := trackBuf.String()
-		
+		trackLength := trackBuf.Len()
+		if !raw {
+			trackLength = measure(trackBuf.String())
+		}
+
 		// Check if adding this track would exceed available space
-		if totalTrackLength+len(trackOutput) > availableLength {
-			// Try smart truncation - find the last complete line
-			if len(trackOutputs) > 0 {
-				// Add truncation indicator if we had to skip tracks
-				skippedCount := len(templateData.Tracks) - len(trackOutputs)
-				if skippedCount > 0 {
-					truncationMsg := fmt.Sprintf("... and %d more tracks\n", skippedCount)
-					trackOutputs = append(trackOutputs, truncationMsg)
-				}
+		if totalTrackLength+trackLength > availableLength {
+			// Add truncation indicator if we had to skip tracks after at least one
+			if i > 0 {
+				result.WriteString(msgs.MoreTracksText(len(templateData.Tracks) - i))
+				result.WriteString("\n")
 			}
 			break
 		}
 
-		trackOutputs = append(trackOutputs, trackOutput)
-		totalTrackLength += len(trackOutput)
-	}
-
-	// Write all accepted track outputs
-	for _, trackOutput := range trackOutputs {
-		result.WriteString(trackOutput)
+		result.Write(trackBuf.Bytes())
+		totalTrackLength += trackLength
 	}
 
 	// Add footer (space was pre-reserved during truncation calculation)
@@ -222,88 +431,243 @@ func (tf *TemplateFormatter) FormatWithTemplate(templateName string, tracks []cu
 		result.WriteString(footerOutput)
 	}
 
-	return result.String(), nil
+	return protected.Wrap(result.String()), nil
+}
+
+// trackBufferPool holds the buffers tracks are rendered into, reused across the
+// shows of a batch
+var trackBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// truncationMargin is reserved below the limit for "... and N more tracks", or
+// the show's longer localized message
+const truncationMargin = 50
+
+// TrackSpace returns the characters a template leaves for tracks under limit
+// once its protected lines, header, footer and truncation margin are reserved. The header and
+// footer are rendered with ValidateTemplate's sample data, so real show titles
+// can take a little more.
+func (tf *TemplateFormatter) TrackSpace(name string, limit int) (int, error) {
+	tmpl, exists := tf.templates[name]
+	if !exists {
+		return 0, fmt.Errorf("template %s not found", name)
+	}
+
+	data := sampleTemplateData()
+	measure := tf.config.DescriptionLengthModel().Length
+	space := ProtectedFor(tf.config, name).Reserve(limit) - truncationMargin
+	for _, part := range []string{"header", "footer"} {
+		partTmpl := tmpl.Lookup(part)
+		if partTmpl == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := partTmpl.Execute(&buf, data); err != nil {
+			return 0, fmt.Errorf("executing %s template: %w", part, err)
+		}
+		space -= measure(buf.String())
+	}
+	return space, nil
+}
+
+// CheckTrackSpace fails when a template's min_track_space doesn't fit under
+// limit, instead of publishing a description with room for one track.
+// Templates without min_track_space, built-ins and custom_template pass.
+func (tf *TemplateFormatter) CheckTrackSpace(name string, limit int) error {
+	minSpace := tf.config.Templates.Config[name].MinTrackSpace
+	if minSpace <= 0 || !tf.HasTemplate(name) {
+		return nil
+	}
+
+	space, err := tf.TrackSpace(name, limit)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", name, err)
+	}
+	if space < minSpace {
+		return fmt.Errorf("template %s leaves %d characters for tracks under the %d-character limit, min_track_space is %d",
+			name, space, limit, minSpace)
+	}
+	return nil
+}
+
+// FormatPlaceholder renders a template's header and footer around a single
+// placeholder line, for episodes with nothing left to list after filtering
+func (tf *TemplateFormatter) FormatPlaceholder(templateName string, placeholder string, metadata Metadata) (string, error) {
+	tmpl, exists := tf.templates[templateName]
+	if !exists {
+		return "", fmt.Errorf("template %s not found", templateName)
+	}
+
+	templateData := tf.buildTemplateData(nil, metadata)
+
+	tmpl, err := tf.withRenderFuncs(tmpl, metadata)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+
+	if tmpl.Lookup("header") != nil {
+		if err := tmpl.ExecuteTemplate(&result, "header", templateData); err != nil {
+			return "", fmt.Errorf("executing header template: %w", err)
+		}
+	}
+
+	result.WriteString(placeholder)
+	result.WriteString("\n")
+
+	if tmpl.Lookup("footer") != nil {
+		if err := tmpl.ExecuteTemplate(&result, "footer", templateData); err != nil {
+			return "", fmt.Errorf("executing footer template: %w", err)
+		}
+	}
+
+	return ProtectedFor(tf.config, templateName).Wrap(result.String()), nil
 }
 
 // buildTemplateData converts tracks and metadata into TemplateData structure
-func (tf *TemplateFormatter) buildTemplateData(tracks []cue.Track, metadata map[string]interface{}) TemplateData {
+func (tf *TemplateFormatter) buildTemplateData(tracks []cue.Track, metadata Metadata) TemplateData {
 	formattedTracks := make([]FormattedTrack, len(tracks))
 	
+	// The formatter hands reversed tracklists over already newest first
+	reversed := metadata.Reversed()
 	for i, track := range tracks {
+		originalIndex := i + 1
+		if reversed {
+			originalIndex = len(tracks) - i
+		}
 		formattedTracks[i] = FormattedTrack{
-			Index:     i + 1,
-			StartTime: track.StartTime,
-			Artist:    track.Artist,
-			Title:     track.Title,
-			Genre:     track.Genre,
-			Duration:  "", // TODO: Calculate duration if available
+			Index:         i + 1,
+			OriginalIndex: originalIndex,
+			StartTime:     track.StartTime,
+			Artist:        track.Artist,
+			Title:         track.Title,
+			Genre:         track.Genre,
+			Duration:      "", // TODO: Calculate duration if available
+			ISRC:          track.ISRC,
+			Label:         track.Label,
+			Year:          track.Year,
 		}
 	}
-
-	// Extract show title and date from metadata or use defaults
-	showTitle := "Radio Show"
-	if title, ok := metadata["show_title"].(string); ok && title != "" {
-		showTitle = title
-	}
-
-	showDate := time.Now().Format("January 2, 2006")
-	if date, ok := metadata["show_date"].(string); ok && date != "" {
-		showDate = date
-	}
+	linkNeighbours(formattedTracks)
 
 	stationName := ""
 	if tf.config != nil {
 		stationName = tf.config.Station.Name
 	}
 
-	// Extract custom variables from metadata
-	custom := make(map[string]interface{})
-	if metadata != nil {
-		for key, value := range metadata {
-			if key != "show_title" && key != "show_date" {
-				custom[key] = value
-			}
-		}
+	// The processor passes the whole show's genres, so every part of a split
+	// show lists the same ones
+	genres := metadata.Genres
+	if genres == nil {
+		genres = analysis.Genres(tracks, tf.config.GenreLimit())
+	}
+	if genres == nil {
+		genres = []string{} // Marshals as [], not null
+	}
+
+	custom := make(map[string]interface{}, len(metadata.Custom))
+	for key, value := range metadata.Custom {
+		custom[key] = value
 	}
 
+	partNumber, partCount := metadata.Part()
+
 	return TemplateData{
-		ShowTitle:   showTitle,
-		ShowDate:    showDate,
+		ShowTitle:   metadata.Title(),
+		ShowDate:    metadata.Date(),
 		TrackCount:  len(tracks),
 		Tracks:      formattedTracks,
-		StationName: stationName,
-		Custom:      custom,
+		StationName:  stationName,
+		Announcement: metadata.Announcement,
+		Catalog:      metadata.Catalog,
+		Custom:       custom,
+		Show:         metadata.Show(),
+
+		IncludedCount:   metadata.Included(len(tracks)),
+		ExcludedCount:   metadata.ExcludedCount,
+		ExcludedReasons: metadata.Reasons(),
+
+		CueFileName: metadata.CueFile,
+		GeneratedAt: metadata.GeneratedAt,
+		ToolVersion: metadata.Version(),
+		Provenance:  metadata.Provenance(),
+
+		PartNumber: partNumber,
+		PartCount:  partCount,
+
+		Hours: groupByHour(tracks, formattedTracks),
+
+		Genres: genres,
 	}
 }
 
-// ValidateTemplate checks template syntax and required variables
-func (tf *TemplateFormatter) ValidateTemplate(name string) error {
-	tmpl, exists := tf.templates[name]
-	if !exists {
-		return fmt.Errorf("template %s not found", name)
+// linkNeighbours sets each track's PrevTrack and NextTrack to copies of the
+// tracks around it
+func linkNeighbours(tracks []FormattedTrack) {
+	neighbours := make([]FormattedTrack, len(tracks))
+	copy(neighbours, tracks)
+	for i := range tracks {
+		if i > 0 {
+			tracks[i].PrevTrack = &neighbours[i-1]
+		}
+		if i < len(tracks)-1 {
+			tracks[i].NextTrack = &neighbours[i+1]
+		}
 	}
+}
 
-	// Create test data to validate template execution
-	testData := TemplateData{
-		ShowTitle:   "Test Show",
-		ShowDate:    "Test Date",
-		TrackCount:  1,
-		StationName: "Test Station",
+// sampleTemplateData is the data ValidateTemplate and TrackSpace render templates with
+func sampleTemplateData() TemplateData {
+	data := TemplateData{
+		ShowTitle:    "Test Show",
+		ShowDate:     "Test Date",
+		TrackCount:   2,
+		StationName:  "Test Station",
+		Announcement: "Test Announcement",
 		Tracks: []FormattedTrack{
 			{
-				Index:     1,
-				StartTime: "00:00",
-				Artist:    "Test Artist",
-				Title:     "Test Title",
-				Genre:     "Test Genre",
-				Duration:  "3:30",
+				Index:         1,
+				OriginalIndex: 1,
+				StartTime:     "00:00",
+				Artist:        "Test Artist",
+				Title:         "Test Title",
+				Genre:         "Test Genre",
+				Duration:      "3:30",
+			},
+			{
+				Index:         2,
+				OriginalIndex: 2,
+				StartTime:     "03:30",
+				Artist:        "Next Artist",
+				Title:         "Next Title",
+				Genre:         "Test Genre",
+				Duration:      "4:00",
 			},
 		},
 		Custom: map[string]interface{}{
 			"test": "value",
 		},
+		Show: ShowInfo{
+			Key:  "test-show",
+			Vars: map[string]string{"test": "value"},
+		},
+	}
+
+	linkNeighbours(data.Tracks)
+	return data
+}
+
+// ValidateTemplate checks template syntax and required variables
+func (tf *TemplateFormatter) ValidateTemplate(name string) error {
+	tmpl, exists := tf.templates[name]
+	if !exists {
+		return fmt.Errorf("template %s not found", name)
 	}
 
+	testData := sampleTemplateData()
+
 	// Try to execute each template component
 	if headerTmpl := tmpl.Lookup("header"); headerTmpl != nil {
 		var buf bytes.Buffer
@@ -313,14 +677,25 @@ func (tf *TemplateFormatter) ValidateTemplate(name string) error {
 	}
 
 	if trackTmpl := tmpl.Lookup("track"); trackTmpl != nil {
-		var buf bytes.Buffer
-		if err := trackTmpl.Execute(&buf, testData.Tracks[0]); err != nil {
-			return fmt.Errorf("track template validation failed: %w", err)
+		// Both tracks, so the first runs with a NextTrack and the last without
+		for _, track := range testData.Tracks {
+			var buf bytes.Buffer
+			if err := trackTmpl.Execute(&buf, track); err != nil {
+				return fmt.Errorf("track template validation failed: %w", err)
+			}
 		}
 	} else {
 		return fmt.Errorf("track template is required")
 	}
 
+	if hourTmpl := tmpl.Lookup("hour"); hourTmpl != nil {
+		var buf bytes.Buffer
+		group := HourGroup{Hour: 1, Label: "Hour 1", Tracks: testData.Tracks}
+		if err := hourTmpl.Execute(&buf, group); err != nil {
+			return fmt.Errorf("hour header template validation failed: %w", err)
+		}
+	}
+
 	if footerTmpl := tmpl.Lookup("footer"); footerTmpl != nil {
 		var buf bytes.Buffer
 		if err := footerTmpl.Execute(&buf, testData); err != nil {
@@ -401,7 +776,7 @@ func (tf *TemplateFormatter) SelectTemplateForShow(showCfg *config.ShowConfig) (
 
 	// Priority 2: Named template reference
 	if showCfg.TemplateName != "" {
-		if tf.HasTemplate(showCfg.TemplateName) {
+		if tf.HasTemplate(showCfg.TemplateName) || IsBuiltinTemplate(showCfg.TemplateName) {
 			return showCfg.TemplateName, nil
 		}
 		return "", fmt.Errorf("referenced template %s not found", showCfg.TemplateName)
@@ -409,7 +784,7 @@ func (tf *TemplateFormatter) SelectTemplateForShow(showCfg *config.ShowConfig) (
 
 	// Priority 3: Default template
 	defaultName := tf.GetDefaultTemplateName()
-	if defaultName != "classic" && tf.HasTemplate(defaultName) {
+	if defaultName != "classic" && (tf.HasTemplate(defaultName) || IsBuiltinTemplate(defaultName)) {
 		return defaultName, nil
 	}
 
@@ -418,15 +793,15 @@ func (tf *TemplateFormatter) SelectTemplateForShow(showCfg *config.ShowConfig) (
 }
 
 // FormatWithShowConfig formats tracks using template selection based on show configuration
-func (tf *TemplateFormatter) FormatWithShowConfig(tracks []cue.Track, showCfg *config.ShowConfig, metadata map[string]interface{}) (string, error) {
+func (tf *TemplateFormatter) FormatWithShowConfig(tracks []cue.Track, showCfg *config.ShowConfig, metadata Metadata) (string, error) {
 	templateName, err := tf.SelectTemplateForShow(showCfg)
 	if err != nil {
 		return "", fmt.Errorf("selecting template: %w", err)
 	}
 
-	// If we got "classic", return empty string to signal fallback needed
-	if templateName == "classic" {
-		return "", fmt.Errorf("classic formatting requested")
+	// Built-in modes are rendered by the caller - signal fallback needed
+	if (templateName == ClassicTemplateName || templateName == CompactTemplateName) && !tf.HasTemplate(templateName) {
+		return "", fmt.Errorf("%s formatting requested", templateName)
 	}
 
 	return tf.FormatWithTemplate(templateName, tracks, nil, metadata)
@@ -443,6 +818,7 @@ func (tf *TemplateFormatter) GetTemplateInfo(name string) (map[string]bool, erro
 		"has_header": tmpl.Lookup("header") != nil,
 		"has_track":  tmpl.Lookup("track") != nil,
 		"has_footer": tmpl.Lookup("footer") != nil,
+		"has_hour":   tmpl.Lookup("hour") != nil,
 	}
 
 	return info, nil
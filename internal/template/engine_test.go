@@ -1,13 +1,22 @@
 package template
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
 	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
 	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/links"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
 )
 
 func TestNewTemplateFormatter(t *testing.T) {
@@ -56,9 +65,10 @@ func TestLoadTemplates(t *testing.T) {
 		t.Fatalf("LoadTemplates failed: %v", err)
 	}
 
-	// Check that templates were loaded
-	if len(formatter.templates) != 2 {
-		t.Errorf("Expected 2 templates, got %d", len(formatter.templates))
+	// Check that templates were loaded, "detailed" replacing the built-in one
+	// and "numbered" built in
+	if len(formatter.templates) != 3 {
+		t.Errorf("Expected 3 templates, got %d", len(formatter.templates))
 	}
 
 	if !formatter.HasTemplate("minimal") {
@@ -101,8 +111,10 @@ func TestLoadTemplatesWithMissingTrack(t *testing.T) {
 func TestFormatWithTemplate(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name: "Test Radio",
 		},
@@ -141,9 +153,9 @@ func TestFormatWithTemplate(t *testing.T) {
 		},
 	}
 
-	metadata := map[string]interface{}{
-		"show_title": "Test Show",
-		"show_date":  "2024-01-01",
+	metadata := Metadata{
+		ShowTitle: "Test Show",
+		ShowDate:  "2024-01-01",
 	}
 
 	// Create a filter for testing
@@ -176,7 +188,7 @@ func TestFormatWithNonExistentTemplate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewFilter failed: %v", err)
 	}
-	result, err := formatter.FormatWithTemplate("nonexistent", tracks, testFilter, nil)
+	result, err := formatter.FormatWithTemplate("nonexistent", tracks, testFilter, Metadata{})
 	if err == nil {
 		t.Error("Expected error for non-existent template")
 	}
@@ -256,7 +268,7 @@ func TestTemplateFunctions(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewFilter failed: %v", err)
 	}
-	result, err := formatter.FormatWithTemplate("functions", tracks, testFilter, nil)
+	result, err := formatter.FormatWithTemplate("functions", tracks, testFilter, Metadata{})
 	if err != nil {
 		t.Fatalf("FormatWithTemplate failed: %v", err)
 	}
@@ -267,6 +279,49 @@ func TestTemplateFunctions(t *testing.T) {
 	}
 }
 
+func TestTruncateFunctionMultibyte(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		n     int
+		want  string
+	}{
+		{"arabic", "أُمّ كُلْثُوم", 4, "أُمّ..."},
+		{"arabic fits", "أُمّ", 4, "أُمّ"},
+		{"japanese", "夜に駆ける", 2, "夜に..."},
+		{"emoji with skin tone", "👍🏽 Good Vibes", 1, "..."},
+		{"emoji", "🎉 Party", 1, "🎉..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewTemplateFormatter(&config.Config{})
+			if err := formatter.LoadCustomTemplate("short", fmt.Sprintf("{{truncate .Title %d}}", tt.n)); err != nil {
+				t.Fatalf("LoadCustomTemplate failed: %v", err)
+			}
+			result, err := formatter.FormatWithTemplate("short", []cue.Track{{Title: tt.title}}, nil, Metadata{})
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("truncate %q %d = %q, want %q", tt.title, tt.n, result, tt.want)
+			}
+		})
+	}
+
+	// Every cut of every title is valid UTF-8 within n characters plus the ellipsis
+	titles := []string{"مَرْحَبًا بِكُمْ", "夜に駆ける (YOASOBI)", "Family 👩\u200d👩\u200d👧 🇯🇵 Song"}
+	truncate := getTemplateFuncMap()["truncate"].(func(string, int) string)
+	for _, title := range titles {
+		for n := 0; n <= utf8.RuneCountInString(title); n++ {
+			got := truncate(title, n)
+			if !utf8.ValidString(got) || utf8.RuneCountInString(got) > n+len("...") {
+				t.Errorf("truncate %q %d = %q: invalid or too long", title, n, got)
+			}
+		}
+	}
+}
+
 func TestCharacterLimitEnforcement(t *testing.T) {
 	cfg := &config.Config{
 		Templates: struct {
@@ -301,7 +356,7 @@ func TestCharacterLimitEnforcement(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewFilter failed: %v", err)
 	}
-	result, err := formatter.FormatWithTemplate("long", tracks, testFilter, nil)
+	result, err := formatter.FormatWithTemplate("long", tracks, testFilter, Metadata{})
 	if err != nil {
 		t.Fatalf("FormatWithTemplate failed: %v", err)
 	}
@@ -312,11 +367,104 @@ func TestCharacterLimitEnforcement(t *testing.T) {
 	}
 }
 
+func TestPerCallMaxLength(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"plain": {Track: "{{.StartTime}} {{.Artist}} - {{.Title}}\n", Footer: "#station"},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	tracks := make([]cue.Track, 60)
+	for i := range tracks {
+		tracks[i] = cue.Track{StartTime: "00:00", Artist: "Some Artist", Title: "Some Fairly Long Title"}
+	}
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		want     int
+	}{
+		{"Mixcloud limit by default", Metadata{}, constants.MixcloudDescriptionLimit},
+		{"show limit", Metadata{MaxLength: 300}, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.FormatWithTemplate("plain", tracks, nil, tt.metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if len(result) > tt.want || len(result) < tt.want-100 {
+				t.Errorf("length = %d, want close to the %d limit", len(result), tt.want)
+			}
+			if !strings.HasSuffix(result, "#station") {
+				t.Errorf("footer should survive truncation:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestCheckTrackSpace(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"crowded": {
+			Header:        "{{repeat \"h\" 200}}\n",
+			Track:         "{{.Title}}\n",
+			Footer:        "{{repeat \"f\" 100}}",
+			MinTrackSpace: 300,
+		},
+		"unchecked": {Header: "{{repeat \"h\" 900}}", Track: "{{.Title}}\n"},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	// 201 header + 100 footer + 50 truncation margin
+	if space, err := formatter.TrackSpace("crowded", 1000); err != nil || space != 649 {
+		t.Errorf("TrackSpace() = %d, %v, want 649", space, err)
+	}
+
+	tests := []struct {
+		name    string
+		limit   int
+		wantErr string
+	}{
+		{"room under the Mixcloud limit", 1000, ""},
+		{"exactly enough", 651, ""},
+		{"show limit too short", 500, "template crowded leaves 149 characters for tracks under the 500-character limit, min_track_space is 300"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := formatter.CheckTrackSpace("crowded", tt.limit)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckTrackSpace() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("CheckTrackSpace() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	for _, name := range []string{"unchecked", ClassicTemplateName, CompactTemplateName} {
+		if err := formatter.CheckTrackSpace(name, 100); err != nil {
+			t.Errorf("CheckTrackSpace(%q) error = %v, want no check without min_track_space", name, err)
+		}
+	}
+}
+
 func TestSmartTruncationWithFooter(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name: "Test Station",
 		},
@@ -350,8 +498,8 @@ func TestSmartTruncationWithFooter(t *testing.T) {
 		}
 	}
 
-	metadata := map[string]interface{}{
-		"show_title": "Test Show",
+	metadata := Metadata{
+		ShowTitle: "Test Show",
 	}
 
 	testFilter, err := filter.NewFilter(&config.Config{})
@@ -404,9 +552,10 @@ func TestListTemplates(t *testing.T) {
 		t.Fatalf("LoadTemplates failed: %v", err)
 	}
 
+	// The two configured plus the built-in numbered and detailed
 	templates := formatter.ListTemplates()
-	if len(templates) != 2 {
-		t.Errorf("Expected 2 templates, got %d", len(templates))
+	if len(templates) != 4 {
+		t.Errorf("Expected 4 templates, got %d", len(templates))
 	}
 
 	// Check that both templates are present
@@ -457,8 +606,10 @@ func TestGetDefaultTemplateName(t *testing.T) {
 func TestBuildTemplateData(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name: "Test Station",
 		},
@@ -481,10 +632,10 @@ func TestBuildTemplateData(t *testing.T) {
 		},
 	}
 
-	metadata := map[string]interface{}{
-		"show_title": "My Show",
-		"show_date":  "2024-01-01",
-		"custom_var": "custom_value",
+	metadata := Metadata{
+		ShowTitle: "My Show",
+		ShowDate:  "2024-01-01",
+		Custom:    map[string]interface{}{"custom_var": "custom_value"},
 	}
 
 	templateData := formatter.buildTemplateData(tracks, metadata)
@@ -629,6 +780,14 @@ func TestSelectTemplateForShow(t *testing.T) {
 			wantError: false,
 			expected:  "named-template",
 		},
+		{
+			name: "built-in compact reference",
+			showCfg: &config.ShowConfig{
+				TemplateName: "compact",
+			},
+			wantError: false,
+			expected:  "compact",
+		},
 		{
 			name: "non-existent named template",
 			showCfg: &config.ShowConfig{
@@ -674,8 +833,10 @@ func TestSelectTemplateForShow(t *testing.T) {
 func TestFormatWithShowConfig(t *testing.T) {
 	cfg := &config.Config{
 		Station: struct {
-			Name             string `toml:"name"`
-			MixcloudUsername string `toml:"mixcloud_username"`
+			Name                    string `toml:"name"`
+			MixcloudUsername        string `toml:"mixcloud_username"`
+			AnnouncementSource      string `toml:"announcement_source"`
+			AnnouncementAutoPrepend bool   `toml:"announcement_auto_prepend"`
 		}{
 			Name: "Test Station",
 		},
@@ -708,9 +869,9 @@ func TestFormatWithShowConfig(t *testing.T) {
 		TemplateName: "test-template",
 	}
 
-	metadata := map[string]interface{}{
-		"show_title": "Test Show",
-		"show_date":  "2023-01-01",
+	metadata := Metadata{
+		ShowTitle: "Test Show",
+		ShowDate:  "2023-01-01",
 	}
 
 	result, err := formatter.FormatWithShowConfig(tracks, showCfg, metadata)
@@ -780,4 +941,851 @@ func TestGetTemplateInfo(t *testing.T) {
 	if err == nil {
 		t.Error("GetTemplateInfo() should return error for non-existent template")
 	}
-}
\ No newline at end of file
+}
+func TestISRCAndCatalogRenderEmptyWhenMissing(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"reporting": {
+			Header: "Catalog: [{{.Catalog}}]\n",
+			Track:  "{{.Artist}} - {{.Title}} [{{.ISRC}}]\n",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	tracks := []cue.Track{
+		{Index: 1, Artist: "Laura Dre", Title: "When I Fall", ISRC: "USABC1234567"},
+		{Index: 2, Artist: "Airline Food", Title: "Conditional Love"},
+	}
+	result, err := formatter.FormatWithTemplate("reporting", tracks, nil, Metadata{})
+	if err != nil {
+		t.Fatalf("FormatWithTemplate failed: %v", err)
+	}
+
+	want := "Catalog: []\nLaura Dre - When I Fall [USABC1234567]\nAirline Food - Conditional Love []\n"
+	if result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+	if strings.Contains(result, "<no value>") {
+		t.Error("missing ISRC/catalog rendered as <no value>")
+	}
+
+	result, err = formatter.FormatWithTemplate("reporting", tracks[:1], nil, Metadata{Catalog: "0724384960650"})
+	if err != nil {
+		t.Fatalf("FormatWithTemplate failed: %v", err)
+	}
+	if !strings.HasPrefix(result, "Catalog: [0724384960650]\n") {
+		t.Errorf("catalog not rendered: %q", result)
+	}
+}
+
+func TestArtistLink(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"linked": {
+			Track: "{{.Artist}} - {{.Title}}{{with artistLink .Artist}} ({{.}}){{end}}\n",
+		},
+	}
+
+	linksPath := filepath.Join(t.TempDir(), "links.csv")
+	csv := "Laura Dre,https://lauradre.bandcamp.com\nChameleons,https://chameleons.bandcamp.com\n"
+	if err := os.WriteFile(linksPath, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := links.Load(linksPath)
+	if err != nil {
+		t.Fatalf("links.Load() error = %v", err)
+	}
+
+	tracks := []cue.Track{
+		{Index: 1, Artist: "Laura Dre", Title: "When I Fall"},
+		{Index: 2, Artist: "Airline Food", Title: "Conditional Love"},
+		{Index: 3, Artist: "The Chameleons feat. Laura Dre", Title: "Swamp Thing"},
+	}
+
+	tests := []struct {
+		name     string
+		table    *links.Table
+		maxLinks int
+		want     string
+	}{
+		{
+			name: "no links file",
+			want: "Laura Dre - When I Fall\nAirline Food - Conditional Love\nThe Chameleons feat. Laura Dre - Swamp Thing\n",
+		},
+		{
+			name:  "uncapped",
+			table: table,
+			want: "Laura Dre - When I Fall (https://lauradre.bandcamp.com)\nAirline Food - Conditional Love\n" +
+				"The Chameleons feat. Laura Dre - Swamp Thing (https://chameleons.bandcamp.com)\n",
+		},
+		{
+			name:     "max_links caps per render",
+			table:    table,
+			maxLinks: 1,
+			want:     "Laura Dre - When I Fall (https://lauradre.bandcamp.com)\nAirline Food - Conditional Love\nThe Chameleons feat. Laura Dre - Swamp Thing\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewTemplateFormatter(cfg)
+			if err := formatter.LoadTemplates(); err != nil {
+				t.Fatalf("LoadTemplates failed: %v", err)
+			}
+			formatter.SetLinks(tt.table)
+
+			metadata := Metadata{MaxLinks: tt.maxLinks}
+			for run := 0; run < 2; run++ { // The cap must reset between renders
+				result, err := formatter.FormatWithTemplate("linked", tracks, nil, metadata)
+				if err != nil {
+					t.Fatalf("FormatWithTemplate failed: %v", err)
+				}
+				if result != tt.want {
+					t.Errorf("render %d = %q, want %q", run+1, result, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestArtistLinkLinesTruncateLikeAnyOther(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"linked": {Track: "{{.Title}}{{with artistLink .Artist}} {{.}}{{end}}\n"},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	linksPath := filepath.Join(t.TempDir(), "links.toml")
+	url := "https://example.bandcamp.com/" + strings.Repeat("x", 300)
+	if err := os.WriteFile(linksPath, []byte(fmt.Sprintf("%q = %q\n", "Linked", url)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := links.Load(linksPath)
+	if err != nil {
+		t.Fatalf("links.Load() error = %v", err)
+	}
+	formatter.SetLinks(table)
+
+	var tracks []cue.Track
+	for i := 1; i <= 6; i++ {
+		tracks = append(tracks, cue.Track{Index: i, Artist: "Linked", Title: fmt.Sprintf("Track %d", i)})
+	}
+
+	result, err := formatter.FormatWithTemplate("linked", tracks, nil, Metadata{})
+	if err != nil {
+		t.Fatalf("FormatWithTemplate failed: %v", err)
+	}
+	if len(result) > constants.MixcloudDescriptionLimit {
+		t.Errorf("result is %d characters, over the %d limit", len(result), constants.MixcloudDescriptionLimit)
+	}
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "... and ") {
+		t.Errorf("last line = %q, want the truncation message", last)
+	}
+	for _, line := range lines[:len(lines)-1] {
+		if !strings.HasSuffix(line, url) {
+			t.Errorf("line %q was cut mid-link", line)
+		}
+	}
+}
+
+func TestLengthModelRendered(t *testing.T) {
+	url := "https://example.bandcamp.com/" + strings.Repeat("x", 300)
+	var tracks []cue.Track
+	for i := 1; i <= 6; i++ {
+		tracks = append(tracks, cue.Track{Index: i, Artist: "Artist", Title: fmt.Sprintf("Track %d", i)})
+	}
+
+	tests := []struct {
+		model         string
+		wantTruncated bool
+	}{
+		{"raw", true},
+		{"rendered", false}, // Each URL counts as 23 characters
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Processing.LengthModel = tt.model
+			cfg.Templates.Config = map[string]config.TemplateConfig{
+				"linked": {Track: "{{.Title}} " + url + "\n"},
+			}
+			formatter := NewTemplateFormatter(cfg)
+			if err := formatter.LoadTemplates(); err != nil {
+				t.Fatalf("LoadTemplates failed: %v", err)
+			}
+
+			result, err := formatter.FormatWithTemplate("linked", tracks, nil, Metadata{})
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if truncated := strings.Contains(result, "more tracks"); truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestExcludedCountFooter(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"licensed": {
+			Track:  "{{.Artist}} - {{.Title}}\n",
+			Footer: "{{if gt .ExcludedCount 0}}{{.ExcludedCount}} non-music items omitted.{{end}}",
+		},
+		"reasons": {
+			Track:  "{{.Title}}\n",
+			Footer: "{{.IncludedCount}} played, {{index .ExcludedReasons \"excluded_artist_regex\"}} idents, {{index .ExcludedReasons \"excluded_genre\"}} ads",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	tracks := []cue.Track{
+		{Index: 1, Artist: "Laura Dre", Title: "When I Fall"},
+		{Index: 2, Artist: "Airline Food", Title: "Conditional Love"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		metadata Metadata
+		want     string
+	}{
+		{
+			name:     "exclusions",
+			template: "licensed",
+			metadata: Metadata{
+				HasCounts:       true,
+				IncludedCount:   2,
+				ExcludedCount:   3,
+				ExcludedReasons: map[string]int{"excluded_artist_regex": 3},
+			},
+			want: "Laura Dre - When I Fall\nAirline Food - Conditional Love\n3 non-music items omitted.",
+		},
+		{
+			name:     "nothing excluded",
+			template: "licensed",
+			metadata: Metadata{
+				HasCounts:       true,
+				IncludedCount:   2,
+				ExcludedCount:   0,
+				ExcludedReasons: map[string]int{},
+			},
+			want: "Laura Dre - When I Fall\nAirline Food - Conditional Love\n",
+		},
+		{
+			name:     "no filtering metadata",
+			template: "licensed",
+			metadata: Metadata{},
+			want:     "Laura Dre - When I Fall\nAirline Food - Conditional Love\n",
+		},
+		{
+			name:     "reasons",
+			template: "reasons",
+			metadata: Metadata{
+				HasCounts:       true,
+				IncludedCount:   2,
+				ExcludedCount:   4,
+				ExcludedReasons: map[string]int{"excluded_artist_regex": 3, "excluded_genre": 1},
+			},
+			want: "When I Fall\nConditional Love\n2 played, 3 idents, 1 ads",
+		},
+		{
+			name:     "missing reason renders zero",
+			template: "reasons",
+			metadata: Metadata{},
+			want:     "When I Fall\nConditional Love\n2 played, 0 idents, 0 ads",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.FormatWithTemplate(tt.template, tracks, nil, tt.metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvenanceFields(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"traced": {
+			Track:  "{{.StartTime}} {{.Artist}} - {{.Title}}\n",
+			Footer: "{{.Provenance}}",
+		},
+		"custom": {
+			Track:  "{{.Title}}\n",
+			Footer: "[{{.CueFileName}} @ {{.GeneratedAt}}, v{{.ToolVersion}}]",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	metadata := Metadata{
+		CueFile:     "MYR40628.cue",
+		GeneratedAt: "2025-06-28 22:14",
+		ToolVersion: "1.0.0",
+	}
+
+	// Far more tracks than fit, so the footer's reserved space is what keeps it
+	var tracks []cue.Track
+	for i := 0; i < 80; i++ {
+		tracks = append(tracks, cue.Track{StartTime: "00:00", Artist: "A Fairly Long Artist Name", Title: "A Fairly Long Song Title"})
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"traced", "\nGenerated 2025-06-28 22:14 from MYR40628.cue by mixcloud-updater v1.0.0"},
+		{"custom", "\n[MYR40628.cue @ 2025-06-28 22:14, v1.0.0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			result, err := formatter.FormatWithTemplate(tt.template, tracks, nil, metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if !strings.HasSuffix(result, tt.want) {
+				t.Errorf("result doesn't end with %q:\n%s", tt.want, result)
+			}
+			if !strings.Contains(result, "more tracks") {
+				t.Errorf("expected the tracklist to be truncated")
+			}
+			if len(result) > constants.MixcloudDescriptionLimit {
+				t.Errorf("length %d exceeds the Mixcloud limit", len(result))
+			}
+		})
+	}
+}
+
+func TestLocalizedTruncationMessage(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"numbered": {Track: "Track {{.Index}}\n"},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	var tracks []cue.Track
+	for i := 0; i < 60; i++ {
+		tracks = append(tracks, cue.Track{StartTime: "00:00", Artist: "Grupo", Title: "Canción"})
+	}
+	spanish, _ := messages.ForLocale("es")
+	tests := []struct {
+		name    string
+		catalog messages.Catalog
+		want    *regexp.Regexp
+	}{
+		{"bundled spanish", spanish, regexp.MustCompile(`\n\.\.\. y \d+ temas más\n$`)},
+		// Longer than truncationMargin, so the margin has to grow to fit it
+		{"long override", spanish.With(messages.Catalog{MoreTracks: "... y {count} temas más que no caben aquí: escúchalos completos en la radio"}),
+			regexp.MustCompile(`\n\.\.\. y \d+ temas más que no caben aquí: escúchalos completos en la radio\n$`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := Metadata{MaxLength: 300, Messages: tt.catalog}
+			result, err := formatter.FormatWithTemplate("numbered", tracks, nil, metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if !tt.want.MatchString(result) {
+				t.Errorf("result doesn't end with the localized message:\n%s", result)
+			}
+			if len(result) > 300 {
+				t.Errorf("length %d exceeds max_length 300", len(result))
+			}
+		})
+	}
+}
+
+func TestAnnouncementField(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"announced": {
+			Header: "{{if .Announcement}}{{.Announcement}}\n\n{{end}}",
+			Track:  "{{.StartTime}} {{.Artist}} - {{.Title}}\n",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	var tracks []cue.Track
+	for i := 0; i < 80; i++ {
+		tracks = append(tracks, cue.Track{StartTime: "00:00", Artist: "A Fairly Long Artist Name", Title: "A Fairly Long Song Title"})
+	}
+
+	announcement := strings.Repeat("Pledge drive all week! ", 20)
+	tests := []struct {
+		name         string
+		announcement string
+		wantPrefix   string
+	}{
+		{"announcement", announcement, announcement + "\n\n00:00 "},
+		{"no announcement", "", "00:00 "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := Metadata{Announcement: tt.announcement, AnnouncementPrepend: true}
+			result, err := formatter.FormatWithTemplate("announced", tracks, nil, metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if !strings.HasPrefix(result, tt.wantPrefix) {
+				t.Errorf("result doesn't start with %q:\n%s", tt.wantPrefix, result)
+			}
+			if !strings.Contains(result, "more tracks") {
+				t.Errorf("expected the tracklist to be truncated")
+			}
+			if len(result) > constants.MixcloudDescriptionLimit {
+				t.Errorf("length %d exceeds the Mixcloud limit", len(result))
+			}
+		})
+	}
+
+	data := formatter.buildTemplateData(tracks[:1], Metadata{Announcement: "Hi", AnnouncementPrepend: true})
+	if data.Announcement != "Hi" || len(data.Custom) != 0 {
+		t.Errorf("Announcement = %q, Custom = %v; want the announcement keys kept out of Custom", data.Announcement, data.Custom)
+	}
+}
+
+func TestProvenanceLine(t *testing.T) {
+	tests := []struct {
+		file, at, version string
+		want              string
+	}{
+		{"MYR40628.cue", "2025-06-28 22:14", "1.0.0", "Generated 2025-06-28 22:14 from MYR40628.cue by mixcloud-updater v1.0.0"},
+		{"", "", "1.0.0", "Generated by mixcloud-updater v1.0.0"},
+	}
+	for _, tt := range tests {
+		if got := ProvenanceLine(tt.file, tt.at, tt.version); got != tt.want {
+			t.Errorf("ProvenanceLine(%q, %q, %q) = %q, want %q", tt.file, tt.at, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestPartFields(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"parts": {
+			Header: "Part {{.PartNumber}} of {{.PartCount}}\n",
+			Track:  "{{.Title}}\n",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+	tracks := []cue.Track{{Title: "Song"}}
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		want     string
+	}{
+		{"split show", Metadata{PartNumber: 2, PartCount: 3}, "Part 2 of 3\n"},
+		{"unsplit show", Metadata{}, "Part 1 of 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.FormatWithTemplate("parts", tracks, nil, tt.metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if !strings.HasPrefix(result, tt.want) {
+				t.Errorf("result = %q, want prefix %q", result, tt.want)
+			}
+		})
+	}
+}
+
+// loadThreeHourShow parses the three-hour fixture: tracks 1-3 start in the
+// first hour, 4-5 in the second and 6-8 in the third
+func loadThreeHourShow(t *testing.T) []cue.Track {
+	t.Helper()
+	sheet, err := cue.ParseCueFile(filepath.Join("testdata", "three_hours.cue"))
+	if err != nil {
+		t.Fatalf("ParseCueFile failed: %v", err)
+	}
+	return sheet.Tracks
+}
+
+func TestHourGroups(t *testing.T) {
+	tracks := loadThreeHourShow(t)
+	data := NewTemplateFormatter(&config.Config{}).buildTemplateData(tracks, Metadata{})
+
+	type group struct {
+		Label  string
+		Titles []string
+	}
+	var got []group
+	for _, hour := range data.Hours {
+		g := group{Label: hour.Label}
+		for _, track := range hour.Tracks {
+			g.Titles = append(g.Titles, track.Title)
+		}
+		got = append(got, g)
+	}
+	want := []group{
+		{"Hour 1", []string{"Opening", "Second", "Third"}},
+		{"Hour 2", []string{"Top of Hour Two", "Fifth"}},
+		{"Hour 3", []string{"Top of Hour Three", "Seventh", "Closing"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hours = %+v, want %+v", got, want)
+	}
+
+	// A track without a start time stays in the hour of the track before it
+	tracks[3].StartTime = ""
+	data = NewTemplateFormatter(&config.Config{}).buildTemplateData(tracks, Metadata{})
+	if n := len(data.Hours[0].Tracks); n != 4 {
+		t.Errorf("first hour has %d tracks, want 4 with the untimed track", n)
+	}
+}
+
+func TestHourHeaderRendering(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"hourly": {
+			Header:     "{{.ShowTitle}} in {{len .Hours}} hours\n",
+			HourHeader: "\n{{.Label}}\n",
+			Track:      "{{.StartTime}} {{.Artist}} - {{.Title}}\n",
+			Footer:     "\nThanks for listening",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	result, err := formatter.FormatWithTemplate("hourly", loadThreeHourShow(t), nil,
+		Metadata{ShowTitle: "The Long Wave"})
+	if err != nil {
+		t.Fatalf("FormatWithTemplate failed: %v", err)
+	}
+	want := "The Long Wave in 3 hours\n" +
+		"\nHour 1\n00:00 Artist One - Opening\n24:30 Artist Two - Second\n51:10 Artist Three - Third\n" +
+		"\nHour 2\n60:05 Artist Four - Top of Hour Two\n95:40 Artist Five - Fifth\n" +
+		"\nHour 3\n120:00 Artist Six - Top of Hour Three\n148:15 Artist Seven - Seventh\n176:50 Artist Eight - Closing\n" +
+		"\nThanks for listening"
+	if result != want {
+		t.Errorf("result =\n%s\nwant\n%s", result, want)
+	}
+}
+
+func TestHourHeaderTruncation(t *testing.T) {
+	tracks := loadThreeHourShow(t)
+
+	// Widen the tracks step by step so the cut lands at every position,
+	// including right after an hour header
+	cutAtHour, cutInHour := 0, 0
+	for padding := 60; padding <= 300; padding += 5 {
+		cfg := &config.Config{}
+		cfg.Templates.Config = map[string]config.TemplateConfig{
+			"hourly": {
+				HourHeader: "\n{{.Label}}\n",
+				Track:      fmt.Sprintf("{{.StartTime}} {{.Title}} {{repeat \"~\" %d}}\n", padding),
+			},
+		}
+		formatter := NewTemplateFormatter(cfg)
+		if err := formatter.LoadTemplates(); err != nil {
+			t.Fatalf("LoadTemplates failed: %v", err)
+		}
+		result, err := formatter.FormatWithTemplate("hourly", tracks, nil, Metadata{})
+		if err != nil {
+			t.Fatalf("FormatWithTemplate failed: %v", err)
+		}
+		if len(result) > constants.MixcloudDescriptionLimit {
+			t.Errorf("padding %d: length %d over the limit", padding, len(result))
+		}
+		if !strings.Contains(result, "more tracks") {
+			continue
+		}
+
+		lines := strings.Split(strings.TrimSpace(result), "\n")
+		for i, line := range lines {
+			if !strings.HasPrefix(line, "Hour ") {
+				continue
+			}
+			if i+1 >= len(lines) || strings.HasPrefix(lines[i+1], "...") {
+				t.Errorf("padding %d: %q left without tracks:\n%s", padding, line, result)
+			}
+		}
+
+		// Count whether the cut fell at an hour boundary or inside an hour
+		last := lines[len(lines)-2]
+		if strings.Contains(last, "Third") || strings.Contains(last, "Fifth") {
+			cutAtHour++
+		} else {
+			cutInHour++
+		}
+	}
+	if cutAtHour == 0 || cutInHour == 0 {
+		t.Errorf("cuts at an hour boundary: %d, inside an hour: %d; want both exercised", cutAtHour, cutInHour)
+	}
+}
+
+func TestNeighbourTracks(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"segue": {
+			Track: "{{.StartTime}} {{.Title}}" +
+				"{{if .PrevTrack}} (after {{.PrevTrack.Title}}){{end}}" +
+				"{{if .NextTrack}} → segue into {{.NextTrack.Title}}{{end}}\n",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+	if err := formatter.ValidateTemplate("segue"); err != nil {
+		t.Errorf("ValidateTemplate() error = %v", err)
+	}
+
+	tracks := loadThreeHourShow(t)[:3]
+	result, err := formatter.FormatWithTemplate("segue", tracks, nil, Metadata{})
+	if err != nil {
+		t.Fatalf("FormatWithTemplate failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(result, "\n"), "\n")
+	tests := []struct {
+		name string
+		line int
+		want string
+	}{
+		{"first track", 0, "00:00 Opening → segue into Second"},
+		{"middle track", 1, "24:30 Second (after Opening) → segue into Third"},
+		{"last track", 2, "51:10 Third (after Second)"},
+	}
+	if len(lines) != len(tests) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tests), result)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if lines[tt.line] != tt.want {
+				t.Errorf("line %d = %q, want %q", tt.line+1, lines[tt.line], tt.want)
+			}
+		})
+	}
+
+	t.Run("single track has no neighbours", func(t *testing.T) {
+		result, err := formatter.FormatWithTemplate("segue", tracks[:1], nil, Metadata{})
+		if err != nil {
+			t.Fatalf("FormatWithTemplate failed: %v", err)
+		}
+		if result != "00:00 Opening\n" {
+			t.Errorf("result = %q, want %q", result, "00:00 Opening\n")
+		}
+	})
+}
+
+func TestNeighbourTracksValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		track   string
+		wantErr bool
+	}{
+		{"guarded next track", "{{.Title}}{{if .NextTrack}} → {{.NextTrack.Title}}{{end}}\n", false},
+		{"guarded previous track", "{{with .PrevTrack}}{{.Title}} → {{end}}{{.Title}}\n", false},
+		// Fails on the last track of every show, so validation must catch it
+		{"unguarded next track", "{{.Title}} → {{.NextTrack.Title}}\n", true},
+		{"unguarded previous track", "{{.PrevTrack.Title}} → {{.Title}}\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := NewTemplateFormatter(&config.Config{})
+			if err := formatter.LoadCustomTemplate("segue", tt.track); err != nil {
+				t.Fatalf("LoadCustomTemplate failed: %v", err)
+			}
+			err := formatter.ValidateTemplate("segue")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNeighbourTracksJSON(t *testing.T) {
+	data := NewTemplateFormatter(&config.Config{}).buildTemplateData(loadThreeHourShow(t), Metadata{})
+	if data.Tracks[1].NextTrack.NextTrack != nil || data.Tracks[1].PrevTrack.PrevTrack != nil {
+		t.Error("neighbour copies carry their own neighbours")
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if strings.Contains(string(encoded), "Track\"") {
+		t.Errorf("neighbours marshalled: %s", encoded)
+	}
+}
+
+func TestShowFlags(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"master": {
+			Header: "{{with .Show.Vars.host}}Hosted by {{.}}\n{{end}}",
+			Track:  "{{.Artist}} - {{.Title}}{{if hasFlag \"genres\"}} ({{.Genre}}){{end}}\n",
+			Footer: "{{if hasFlag \"social\"}}Follow us @nowwave{{end}}",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+	tracks := []cue.Track{{StartTime: "00:00", Artist: "Laura Dre", Title: "When I Fall", Genre: "Synthpop"}}
+
+	tests := []struct {
+		name     string
+		metadata Metadata
+		want     string
+	}{
+		{
+			name: "flags present",
+			metadata: Metadata{
+				ShowKey:      "specialty",
+				ShowFlags:    []string{"genres", "social"},
+				TemplateVars: map[string]string{"host": "DJ Example"},
+			},
+			want: "Hosted by DJ Example\nLaura Dre - When I Fall (Synthpop)\nFollow us @nowwave",
+		},
+		{
+			name:     "flag absent",
+			metadata: Metadata{ShowKey: "daily", ShowFlags: []string{"social"}},
+			want:     "Laura Dre - When I Fall\nFollow us @nowwave",
+		},
+		{
+			name:     "no show",
+			metadata: Metadata{},
+			want:     "Laura Dre - When I Fall\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatter.FormatWithTemplate("master", tracks, nil, tt.metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackLineFunction(t *testing.T) {
+	tracks := []cue.Track{
+		{StartTime: "00:25", Artist: "Laura Dre", Title: `When "I" Fall`},
+		{StartTime: "04:10", Title: "No Artist"},
+		{StartTime: "08:00", Artist: "No Title"},
+	}
+
+	tests := []struct {
+		name     string
+		global   string
+		metadata Metadata
+		want     string
+	}{
+		{
+			name: "default title-first",
+			want: "1. 00:25 - \"When 'I' Fall\" by Laura Dre\n" +
+				"2. 04:10 - \"No Artist\" by (Unknown Artist)\n" +
+				"3. 08:00 - \"(Unknown Title)\" by No Title\n",
+		},
+		{
+			name:     "show artist-first",
+			metadata: Metadata{TrackStyle: config.TrackStyleArtistFirst},
+			want: "1. 00:25 - Laura Dre - \"When 'I' Fall\"\n" +
+				"2. 04:10 - (Unknown Artist) - \"No Artist\"\n" +
+				"3. 08:00 - No Title - \"(Unknown Title)\"\n",
+		},
+		{
+			name:   "global artist-first",
+			global: config.TrackStyleArtistFirst,
+			want: "1. 00:25 - Laura Dre - \"When 'I' Fall\"\n" +
+				"2. 04:10 - (Unknown Artist) - \"No Artist\"\n" +
+				"3. 08:00 - No Title - \"(Unknown Title)\"\n",
+		},
+		{
+			name:     "show strings",
+			metadata: Metadata{Messages: messages.Catalog{UnknownArtist: "Anon", UnknownTitle: "Untitled"}},
+			want: "1. 00:25 - \"When 'I' Fall\" by Laura Dre\n" +
+				"2. 04:10 - \"No Artist\" by Anon\n" +
+				"3. 08:00 - \"Untitled\" by No Title\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Formatting.TrackStyle = tt.global
+			cfg.Templates.Config = map[string]config.TemplateConfig{
+				"simple": {Track: "{{.Index}}. {{trackLine .}}\n"},
+			}
+			formatter := NewTemplateFormatter(cfg)
+			if err := formatter.LoadTemplates(); err != nil {
+				t.Fatalf("LoadTemplates failed: %v", err)
+			}
+
+			result, err := formatter.FormatWithTemplate("simple", tracks, nil, tt.metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowFlagsPlaceholder(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"master": {
+			Header: "{{.Show.Key}}: ",
+			Track:  "{{.Title}}\n",
+			Footer: "{{if hasFlag \"social\"}}Follow us{{end}}",
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	metadata := Metadata{ShowKey: "daily", ShowFlags: []string{"social"}}
+	result, err := formatter.FormatPlaceholder("master", "Tracklist unavailable", metadata)
+	if err != nil {
+		t.Fatalf("FormatPlaceholder failed: %v", err)
+	}
+	if want := "daily: Tracklist unavailable\nFollow us"; result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+}
@@ -0,0 +1,135 @@
+package template
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// AIDEV-NOTE: output_mode escapes what the template interpolates, never its own
+// literal text, so an HTML template can write "<li>{{.Title}}</li>". Like
+// html/template, it appends an escaper to every {{...}} action that prints a
+// value. Actions that already end in escapeHTML, escapeMarkdown or stripHTML
+// are left alone, so they aren't escaped twice. Mixcloud displays descriptions
+// as plain text, so templates that publish there keep the default plain mode.
+
+// markdownEscaper backslash-escapes the characters that start Markdown
+// formatting anywhere in a line
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`#`, `\#`,
+	`|`, `\|`,
+	`~`, `\~`,
+)
+
+// htmlTagRegex matches an HTML tag or comment
+var htmlTagRegex = regexp.MustCompile(`<!--[\s\S]*?-->|</?[a-zA-Z][^>]*>`)
+
+// escapeHTML replaces & < > ' " with HTML entities
+func escapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// escapeMarkdown backslash-escapes Markdown formatting characters
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// stripHTML removes HTML tags and decodes entities, e.g. "<b>Rock &amp; Roll</b>"
+// becomes "Rock & Roll"
+func stripHTML(s string) string {
+	return html.UnescapeString(htmlTagRegex.ReplaceAllString(s, ""))
+}
+
+// escapeFuncs are the template functions whose output needs no further escaping
+var escapeFuncs = map[string]bool{
+	"escapeHTML":     true,
+	"escapeMarkdown": true,
+	"stripHTML":      true,
+}
+
+// autoEscapers names the function output_mode appends to each action. They
+// take any value since actions print numbers as well as strings.
+var autoEscapers = map[string]string{
+	config.OutputModeHTML:     "autoEscapeHTML",
+	config.OutputModeMarkdown: "autoEscapeMarkdown",
+}
+
+func autoEscapeHTML(value interface{}) string {
+	return escapeHTML(fmt.Sprint(value))
+}
+
+func autoEscapeMarkdown(value interface{}) string {
+	return escapeMarkdown(fmt.Sprint(value))
+}
+
+// applyOutputMode rewrites every template in tmpl's set so the values its
+// actions print are escaped for mode. The plain mode leaves tmpl unchanged.
+func applyOutputMode(tmpl *template.Template, mode string) {
+	escaper, ok := autoEscapers[mode]
+	if !ok {
+		return
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && t.Tree.Root != nil {
+			escapeActions(t.Tree.Root, escaper)
+		}
+	}
+}
+
+// escapeActions appends escaper to the printing actions under node
+func escapeActions(node parse.Node, escaper string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeActions(child, escaper)
+		}
+	case *parse.ActionNode:
+		// {{$x := ...}} prints nothing
+		if len(n.Pipe.Decl) > 0 || endsInEscaper(n.Pipe) {
+			return
+		}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(escaper).SetTree(nil).SetPos(n.Pos)},
+		})
+	case *parse.IfNode:
+		escapeActions(n.List, escaper)
+		escapeActions(n.ElseList, escaper)
+	case *parse.RangeNode:
+		escapeActions(n.List, escaper)
+		escapeActions(n.ElseList, escaper)
+	case *parse.WithNode:
+		escapeActions(n.List, escaper)
+		escapeActions(n.ElseList, escaper)
+	}
+}
+
+// endsInEscaper reports whether a pipeline's last command is an escaping function
+func endsInEscaper(pipe *parse.PipeNode) bool {
+	if len(pipe.Cmds) == 0 {
+		return false
+	}
+	last := pipe.Cmds[len(pipe.Cmds)-1]
+	if len(last.Args) == 0 {
+		return false
+	}
+	ident, ok := last.Args[0].(*parse.IdentifierNode)
+	return ok && escapeFuncs[ident.Ident]
+}
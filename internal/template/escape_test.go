@@ -0,0 +1,113 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+func TestEscapeFunctions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{"escapeHTML", escapeHTML, `Rock & <Roll> "Live"`, `Rock &amp; &lt;Roll&gt; &#34;Live&#34;`},
+		{"escapeMarkdown", escapeMarkdown, "*Hits* _of_ `1985` [remix] #1", "\\*Hits\\* \\_of\\_ \\`1985\\` \\[remix\\] \\#1"},
+		{"escapeMarkdown backslash", escapeMarkdown, `AC\DC`, `AC\\DC`},
+		{"stripHTML", stripHTML, `<b>Rock &amp; Roll</b><!-- note --> <br/>Live`, "Rock & Roll Live"},
+		{"stripHTML keeps comparisons", stripHTML, "3 < 4 > 2", "3 < 4 > 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.in); got != tt.want {
+				t.Errorf("%s(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputMode(t *testing.T) {
+	tracks := []cue.Track{
+		{StartTime: "00:00", Artist: "Simon & Garfunkel", Title: "<Cecilia>"},
+		{StartTime: "03:00", Artist: "The *Stars*", Title: "snake_case `code`"},
+	}
+	metadata := Metadata{ShowTitle: "R&B <Night>"}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"", "<h1>R&B <Night></h1>\n<li>1. Simon & Garfunkel - <Cecilia></li>\n<li>2. The *Stars* - snake_case `code`</li>\n"},
+		{config.OutputModePlain, "<h1>R&B <Night></h1>\n<li>1. Simon & Garfunkel - <Cecilia></li>\n<li>2. The *Stars* - snake_case `code`</li>\n"},
+		{config.OutputModeHTML, "<h1>R&amp;B &lt;Night&gt;</h1>\n<li>1. Simon &amp; Garfunkel - &lt;Cecilia&gt;</li>\n<li>2. The *Stars* - snake_case `code`</li>\n"},
+		{config.OutputModeMarkdown, "<h1>R&B \\<Night\\></h1>\n<li>1. Simon & Garfunkel - \\<Cecilia\\></li>\n<li>2. The \\*Stars\\* - snake\\_case \\`code\\`</li>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode "+tt.mode, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Templates.Config = map[string]config.TemplateConfig{
+				"archive": {
+					Header:     "<h1>{{.ShowTitle}}</h1>\n",
+					Track:      "<li>{{.Index}}. {{.Artist}} - {{.Title}}</li>\n",
+					OutputMode: tt.mode,
+				},
+			}
+			formatter := NewTemplateFormatter(cfg)
+			if err := formatter.LoadTemplates(); err != nil {
+				t.Fatalf("LoadTemplates failed: %v", err)
+			}
+
+			result, err := formatter.FormatWithTemplate("archive", tracks, nil, metadata)
+			if err != nil {
+				t.Fatalf("FormatWithTemplate failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputModeLeavesExplicitEscapesAndControlFlow(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"archive": {
+			Track: `{{$title := .Title}}{{if .Artist}}{{.Artist | upper}}{{else}}?{{end}}: {{escapeHTML $title}}` +
+				`{{with .NextTrack}} > {{.Title}}{{end}}{{"\n"}}`,
+			OutputMode: config.OutputModeHTML,
+		},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates failed: %v", err)
+	}
+
+	tracks := []cue.Track{
+		{StartTime: "00:00", Artist: "A&B", Title: "One & Two"},
+		{StartTime: "03:00", Artist: "C<D", Title: "Three > Two"},
+	}
+	result, err := formatter.FormatWithTemplate("archive", tracks, nil, Metadata{})
+	if err != nil {
+		t.Fatalf("FormatWithTemplate failed: %v", err)
+	}
+	want := "A&amp;B: One &amp; Two > Three &gt; Two\nC&lt;D: Three &gt; Two\n" // The literal " > " stays
+	if result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+}
+
+func TestOutputModeInvalid(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"archive": {Track: "{{.Title}}\n", OutputMode: "rtf"},
+	}
+	formatter := NewTemplateFormatter(cfg)
+	if err := formatter.LoadTemplates(); err == nil || !strings.Contains(err.Error(), "output_mode") {
+		t.Errorf("LoadTemplates() error = %v, want an output_mode error", err)
+	}
+}
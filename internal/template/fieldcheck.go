@@ -0,0 +1,166 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// AIDEV-NOTE: text/template only notices a misspelled field ({{.Titel}}) when
+// the template runs, so linting walks the parse tree instead. Header and footer
+// execute against TemplateData, track against FormattedTrack and hour_header
+// against HourGroup.
+
+// UnknownFields returns the field references in a template definition that
+// don't exist on the data the template is executed with, e.g. "track: .Titel"
+func UnknownFields(templateConfig config.TemplateConfig) ([]string, error) {
+	parts := []struct {
+		name string
+		body string
+		data reflect.Type
+	}{
+		{"header", templateConfig.Header, reflect.TypeOf(TemplateData{})},
+		{"track", templateConfig.Track, reflect.TypeOf(FormattedTrack{})},
+		{"hour_header", templateConfig.HourHeader, reflect.TypeOf(HourGroup{})},
+		{"footer", templateConfig.Footer, reflect.TypeOf(TemplateData{})},
+	}
+
+	seen := make(map[string]bool)
+	var unknown []string
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+
+		tmpl, err := template.New(part.name).Funcs(getTemplateFuncMap()).Parse(part.body)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s template: %w", part.name, err)
+		}
+
+		checker := &fieldChecker{root: part.data}
+		checker.walk(tmpl.Tree.Root, part.data)
+		for _, field := range checker.unknown {
+			ref := part.name + ": " + field
+			if !seen[ref] {
+				seen[ref] = true
+				unknown = append(unknown, ref)
+			}
+		}
+	}
+
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// fieldChecker walks a template parse tree tracking the type of dot.
+// A nil type means dot is unknown at that point and fields aren't checked.
+type fieldChecker struct {
+	root    reflect.Type
+	unknown []string
+}
+
+func (c *fieldChecker) walk(node parse.Node, dot reflect.Type) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		c.checkPipe(n.Pipe, dot)
+	case *parse.IfNode:
+		c.checkPipe(n.Pipe, dot)
+		c.walk(n.List, dot)
+		c.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		c.checkPipe(n.Pipe, dot)
+		c.walk(n.List, c.pipeType(n.Pipe, dot))
+		c.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		c.checkPipe(n.Pipe, dot)
+		c.walk(n.List, elemType(c.pipeType(n.Pipe, dot)))
+		c.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		c.checkPipe(n.Pipe, dot)
+	}
+}
+
+func (c *fieldChecker) checkPipe(pipe *parse.PipeNode, dot reflect.Type) {
+	if pipe == nil {
+		return
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				c.resolve(dot, "", a.Ident)
+			case *parse.VariableNode:
+				// $.Field refers to the root data; other variables are untyped here
+				if len(a.Ident) > 1 && a.Ident[0] == "$" {
+					c.resolve(c.root, "$", a.Ident[1:])
+				}
+			case *parse.PipeNode:
+				c.checkPipe(a, dot)
+			}
+		}
+	}
+}
+
+// pipeType returns the type a pipeline evaluates to when it is a single field
+// reference, or nil when that can't be determined
+func (c *fieldChecker) pipeType(pipe *parse.PipeNode, dot reflect.Type) reflect.Type {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil
+	}
+	switch a := pipe.Cmds[0].Args[0].(type) {
+	case *parse.FieldNode:
+		return c.resolve(dot, "", a.Ident)
+	case *parse.DotNode:
+		return dot
+	}
+	return nil
+}
+
+// resolve follows a field chain from t, recording the first unknown field.
+// It returns the type of the last field, or nil if it can't be determined.
+func (c *fieldChecker) resolve(t reflect.Type, prefix string, idents []string) reflect.Type {
+	for i, ident := range idents {
+		if t == nil {
+			return nil
+		}
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Map, reflect.Interface:
+			return nil // Any key is allowed, e.g. .Custom.host
+		case reflect.Struct:
+			if field, ok := t.FieldByName(ident); ok {
+				t = field.Type
+				continue
+			}
+		}
+		c.unknown = append(c.unknown, prefix+"."+strings.Join(idents[:i+1], "."))
+		return nil
+	}
+	return t
+}
+
+// elemType returns the element type ranged over, or nil if unknown
+func elemType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package template
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+func TestUnknownFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		template config.TemplateConfig
+		want     []string
+	}{
+		{
+			name: "all fields known",
+			template: config.TemplateConfig{
+				Header: "{{.ShowTitle}} ({{.TrackCount}} tracks) {{.Custom.host}}\n",
+				Track:  "{{.Index}}. {{.StartTime}} {{.Artist | upper}} - {{.Title}}\n",
+				Footer: "{{range .Tracks}}{{.Artist}}{{end}}{{with .StationName}}{{.}}{{end}}",
+			},
+			want: nil,
+		},
+		{
+			name: "misspelled fields",
+			template: config.TemplateConfig{
+				Header: "{{.ShowName}}\n",
+				Track:  "{{if .Publisher}}{{.Publisher}}{{end}} {{.Titel}}\n",
+			},
+			want: []string{"header: .ShowName", "track: .Publisher", "track: .Titel"},
+		},
+		{
+			name: "fields inside range use the element type",
+			template: config.TemplateConfig{
+				Footer: "{{range .Tracks}}{{.Artist}} {{.Album}} {{$.ShowTitle}} {{$.ShowDat}}{{end}}",
+			},
+			want: []string{"footer: $.ShowDat", "footer: .Album"},
+		},
+		{
+			name: "hour header uses HourGroup, header ranges over Hours",
+			template: config.TemplateConfig{
+				Header:     "{{range .Hours}}{{.Label}}: {{len .Tracks}} {{.Titel}}{{end}}\n",
+				Track:      "{{.Title}}\n",
+				HourHeader: "\n{{.Label}} ({{.Hour}}) {{.ShowTitle}}\n",
+			},
+			want: []string{"header: .Titel", "hour_header: .ShowTitle"},
+		},
+		{
+			name: "neighbour tracks",
+			template: config.TemplateConfig{
+				Track: "{{.Title}}{{if .NextTrack}} → {{.NextTrack.Titel}}{{end}}{{with .PrevTrack}}{{.Artist}}{{end}}\n",
+			},
+			want: []string{"track: .NextTrack.Titel"},
+		},
+		{
+			name: "field of a string",
+			template: config.TemplateConfig{
+				Track: "{{.Artist.Name}}",
+			},
+			want: []string{"track: .Artist.Name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnknownFields(tt.template)
+			if err != nil {
+				t.Fatalf("UnknownFields() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnknownFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownFieldsParseError(t *testing.T) {
+	if _, err := UnknownFields(config.TemplateConfig{Track: "{{.Title"}); err == nil {
+		t.Error("UnknownFields() expected parse error")
+	}
+}
@@ -0,0 +1,47 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+// AIDEV-NOTE: Long shows read better as "Hour 1", "Hour 2" sections. Groups are
+// precomputed into TemplateData.Hours so header/footer can summarize them, and
+// a template's hour_header is rendered by FormatWithTemplate together with the
+// first track of each hour - truncation drops that pair as a unit, so a header
+// never dangles at the end of a cut tracklist.
+
+// HourGroup is the tracks starting in one hour of the show, the data an
+// hour_header template is executed with
+type HourGroup struct {
+	Hour   int              `json:"hour"`  // 1 for tracks starting in the first hour
+	Label  string           `json:"label"` // e.g. "Hour 1"
+	Tracks []FormattedTrack `json:"tracks"`
+}
+
+// groupByHour splits formatted tracks into consecutive groups by the hour of the
+// matching cue track's start time. A track without a usable start time stays in
+// the group of the track before it; hours without tracks have no group. Each
+// group's Tracks shares formatted's backing array rather than copying it.
+func groupByHour(tracks []cue.Track, formatted []FormattedTrack) []HourGroup {
+	var groups []HourGroup
+	start := 0
+	for i, track := range tracks {
+		hour := 1
+		if len(groups) > 0 {
+			hour = groups[len(groups)-1].Hour
+		}
+		if offset, ok := track.StartOffset(); ok {
+			hour = int(offset.Hours()) + 1
+		}
+
+		if len(groups) == 0 || groups[len(groups)-1].Hour != hour {
+			start = i
+			groups = append(groups, HourGroup{Hour: hour, Label: fmt.Sprintf("Hour %d", hour)})
+		}
+		// Capped so appending to a group can't overwrite the next one
+		groups[len(groups)-1].Tracks = formatted[start : i+1 : i+1]
+	}
+	return groups
+}
@@ -0,0 +1,217 @@
+package template
+
+import (
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
+)
+
+// AIDEV-NOTE: Metadata replaced a map[string]interface{} keyed by strings, where
+// a typo like "show_tile" silently rendered the default title. Defaults live in
+// the methods below, so an unset field and its fallback are read in one place.
+
+// DefaultShowTitle is rendered as .ShowTitle when the metadata has none
+const DefaultShowTitle = "Radio Show"
+
+// Metadata describes the show a tracklist is formatted for. The zero value is
+// valid: every field falls back to a default.
+type Metadata struct {
+	ShowTitle string // "" renders DefaultShowTitle
+	ShowDate  string // "" renders today, e.g. "June 28, 2025"
+	ShowKey   string // Show key from the config, for .Show.Key
+	Catalog   string // CUE sheet CATALOG
+
+	// Provenance, for .Provenance and include_provenance
+	CueFile           string // Base name of the CUE file, e.g. "MYR40628.cue"
+	GeneratedAt       string // e.g. "2025-06-28 22:14"
+	ToolVersion       string // "" is the running version
+	IncludeProvenance bool   // Classic formatting appends the provenance line
+
+	// Filtering outcome; without HasCounts every track counts as included
+	HasCounts       bool
+	IncludedCount   int
+	ExcludedCount   int
+	ExcludedReasons map[string]int
+
+	// Position in a show uploaded in parts (split_at); 0 is 1 of 1
+	PartNumber int
+	PartCount  int
+
+	Announcement        string // station.announcement_source text
+	AnnouncementPrepend bool   // Classic formatting puts the announcement above the tracks
+
+	MaxLinks     int               // artistLink limit per description, 0 = none
+	MaxLength    int               // Per-show description_max_length, 0 = the formatter's limit
+	TrackOrder   string            // config.TrackOrderReverse lists newest first
+	TrackStyle   string            // track_style of classic lines and trackLine, "" = formatting.track_style
+	Collapse     ArtistCollapse    // collapse_same_artist, and groupByArtist's separator and cap
+	Compact      CompactStyle      // compact_separator and compact_max_length of the built-in compact mode
+	ShowFlags    []string          // For hasFlag
+	TemplateVars map[string]string // For .Show.Vars
+	Messages     messages.Catalog  // The show's locale and strings overrides
+	Genres       []string          // The whole show's genres; nil derives them from the tracks
+
+	// Custom is rendered as .Custom, e.g. metadata.toml custom values
+	Custom map[string]interface{}
+}
+
+// CompactStyle is a show's compact_separator and compact_max_length; zero
+// values leave the compact formatter's defaults
+type CompactStyle struct {
+	Separator string
+	MaxLength int
+}
+
+// CompactStyleFor returns the compact options of showCfg
+func CompactStyleFor(showCfg *config.ShowConfig) CompactStyle {
+	return CompactStyle{Separator: showCfg.CompactSeparator, MaxLength: showCfg.CompactMaxLength}
+}
+
+// Title returns the show title, DefaultShowTitle when unset
+func (m Metadata) Title() string {
+	if m.ShowTitle != "" {
+		return m.ShowTitle
+	}
+	return DefaultShowTitle
+}
+
+// Date returns the show date, today when unset
+func (m Metadata) Date() string {
+	if m.ShowDate != "" {
+		return m.ShowDate
+	}
+	return time.Now().Format("January 2, 2006")
+}
+
+// Version returns the tool version for provenance, the running one when unset
+func (m Metadata) Version() string {
+	if m.ToolVersion != "" {
+		return m.ToolVersion
+	}
+	return constants.Version
+}
+
+// Provenance returns the provenance line, see ProvenanceLine
+func (m Metadata) Provenance() string {
+	return ProvenanceLine(m.CueFile, m.GeneratedAt, m.Version())
+}
+
+// Included returns the tracks left after filtering, trackCount without counts
+func (m Metadata) Included(trackCount int) int {
+	if m.HasCounts {
+		return m.IncludedCount
+	}
+	return trackCount
+}
+
+// Reasons returns the excluded tracks per filter reason, never nil so it
+// marshals as {}
+func (m Metadata) Reasons() map[string]int {
+	if m.ExcludedReasons == nil {
+		return map[string]int{}
+	}
+	return m.ExcludedReasons
+}
+
+// Part returns the description's part number and the show's part count,
+// 1 of 1 when the show isn't split
+func (m Metadata) Part() (number, count int) {
+	number, count = 1, 1
+	if m.PartNumber > 0 {
+		number = m.PartNumber
+	}
+	if m.PartCount > 0 {
+		count = m.PartCount
+	}
+	return number, count
+}
+
+// Limit returns the show's description limit, fallback when it has none
+func (m Metadata) Limit(fallback int) int {
+	if m.MaxLength > 0 {
+		return m.MaxLength
+	}
+	return fallback
+}
+
+// Reversed reports whether tracks are listed newest first
+func (m Metadata) Reversed() bool {
+	return m.TrackOrder == config.TrackOrderReverse
+}
+
+// Localized returns the show's strings over the English defaults
+func (m Metadata) Localized() messages.Catalog {
+	return messages.Default().With(m.Messages)
+}
+
+// Show returns the show's configuration for .Show
+func (m Metadata) Show() ShowInfo {
+	return ShowInfo{Key: m.ShowKey, Vars: m.TemplateVars, Flags: m.ShowFlags}
+}
+
+// MetadataFromMap converts the map form metadata had before Metadata, keyed
+// "show_title", "cue_file_name" and so on. Values of the wrong type are
+// ignored as they were then, and keys it doesn't know become Custom.
+//
+// Deprecated: build a Metadata.
+func MetadataFromMap(values map[string]interface{}) Metadata {
+	var m Metadata
+	for key, value := range values {
+		switch key {
+		case "show_title":
+			m.ShowTitle, _ = value.(string)
+		case "show_date":
+			m.ShowDate, _ = value.(string)
+		case "show_key":
+			m.ShowKey, _ = value.(string)
+		case "catalog":
+			m.Catalog, _ = value.(string)
+		case "cue_file_name":
+			m.CueFile, _ = value.(string)
+		case "generated_at":
+			m.GeneratedAt, _ = value.(string)
+		case "tool_version":
+			m.ToolVersion, _ = value.(string)
+		case "include_provenance":
+			m.IncludeProvenance, _ = value.(bool)
+		case "included_count":
+			m.IncludedCount, m.HasCounts = value.(int)
+		case "excluded_count":
+			m.ExcludedCount, _ = value.(int)
+		case "excluded_reasons":
+			m.ExcludedReasons, _ = value.(map[string]int)
+		case "part_number":
+			m.PartNumber, _ = value.(int)
+		case "part_count":
+			m.PartCount, _ = value.(int)
+		case "announcement":
+			m.Announcement, _ = value.(string)
+		case "announcement_prepend":
+			m.AnnouncementPrepend, _ = value.(bool)
+		case "max_links":
+			m.MaxLinks, _ = value.(int)
+		case "max_length":
+			m.MaxLength, _ = value.(int)
+		case "track_order":
+			m.TrackOrder, _ = value.(string)
+		case "track_style":
+			m.TrackStyle, _ = value.(string)
+		case "show_flags":
+			m.ShowFlags, _ = value.([]string)
+		case "template_vars":
+			m.TemplateVars, _ = value.(map[string]string)
+		case "messages":
+			m.Messages, _ = value.(messages.Catalog)
+		case "genres":
+			m.Genres, _ = value.([]string)
+		default:
+			if m.Custom == nil {
+				m.Custom = make(map[string]interface{})
+			}
+			m.Custom[key] = value
+		}
+	}
+	return m
+}
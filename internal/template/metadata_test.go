@@ -0,0 +1,123 @@
+package template
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/constants"
+)
+
+func TestMetadataDefaults(t *testing.T) {
+	var m Metadata
+
+	if got := m.Title(); got != DefaultShowTitle {
+		t.Errorf("Title() = %q, want %q", got, DefaultShowTitle)
+	}
+	if got, want := m.Date(), time.Now().Format("January 2, 2006"); got != want {
+		t.Errorf("Date() = %q, want %q", got, want)
+	}
+	if got := m.Version(); got != constants.Version {
+		t.Errorf("Version() = %q, want %q", got, constants.Version)
+	}
+	if got := m.Included(7); got != 7 {
+		t.Errorf("Included(7) without counts = %d, want 7", got)
+	}
+	if got := m.Reasons(); got == nil || len(got) != 0 {
+		t.Errorf("Reasons() = %v, want an empty map", got)
+	}
+	if number, count := m.Part(); number != 1 || count != 1 {
+		t.Errorf("Part() = %d of %d, want 1 of 1", number, count)
+	}
+	if got := m.Limit(1000); got != 1000 {
+		t.Errorf("Limit(1000) = %d, want 1000", got)
+	}
+	if m.Reversed() {
+		t.Error("Reversed() = true, want false")
+	}
+}
+
+func TestMetadataSetFields(t *testing.T) {
+	m := Metadata{
+		ShowTitle:     "Mind Your Rhythm",
+		ShowDate:      "June 28, 2025",
+		ToolVersion:   "1.2.3",
+		HasCounts:     true,
+		IncludedCount: 0,
+		PartNumber:    2,
+		PartCount:     3,
+		MaxLength:     500,
+		TrackOrder:    config.TrackOrderReverse,
+	}
+
+	if got := m.Title(); got != "Mind Your Rhythm" {
+		t.Errorf("Title() = %q", got)
+	}
+	if got := m.Date(); got != "June 28, 2025" {
+		t.Errorf("Date() = %q", got)
+	}
+	if got := m.Version(); got != "1.2.3" {
+		t.Errorf("Version() = %q", got)
+	}
+	// A show whose tracks were all filtered out includes none
+	if got := m.Included(7); got != 0 {
+		t.Errorf("Included(7) = %d, want 0", got)
+	}
+	if number, count := m.Part(); number != 2 || count != 3 {
+		t.Errorf("Part() = %d of %d, want 2 of 3", number, count)
+	}
+	if got := m.Limit(1000); got != 500 {
+		t.Errorf("Limit(1000) = %d, want 500", got)
+	}
+	if !m.Reversed() {
+		t.Error("Reversed() = false, want true")
+	}
+}
+
+func TestMetadataFromMap(t *testing.T) {
+	got := MetadataFromMap(map[string]interface{}{
+		"show_title":       "Mind Your Rhythm",
+		"show_date":        "June 28, 2025",
+		"show_key":         "myr",
+		"cue_file_name":    "MYR40628.cue",
+		"generated_at":     "2025-06-28 22:14",
+		"included_count":   4,
+		"excluded_reasons": map[string]int{"artist": 2},
+		"max_links":        3,
+		"show_flags":       []string{"social"},
+		"show_tile":        "Typo",
+		"guest":            "DJ Example",
+	})
+
+	want := Metadata{
+		ShowTitle:       "Mind Your Rhythm",
+		ShowDate:        "June 28, 2025",
+		ShowKey:         "myr",
+		CueFile:         "MYR40628.cue",
+		GeneratedAt:     "2025-06-28 22:14",
+		HasCounts:       true,
+		IncludedCount:   4,
+		ExcludedReasons: map[string]int{"artist": 2},
+		MaxLinks:        3,
+		ShowFlags:       []string{"social"},
+		Custom:          map[string]interface{}{"show_tile": "Typo", "guest": "DJ Example"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MetadataFromMap() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMetadataFromMapWrongTypes(t *testing.T) {
+	got := MetadataFromMap(map[string]interface{}{
+		"show_title":     42,
+		"included_count": "4",
+	})
+
+	if got.ShowTitle != "" || got.HasCounts {
+		t.Errorf("MetadataFromMap() = %+v, want wrong types ignored", got)
+	}
+	if got.Title() != DefaultShowTitle {
+		t.Errorf("Title() = %q, want %q", got.Title(), DefaultShowTitle)
+	}
+}
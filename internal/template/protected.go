@@ -0,0 +1,65 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+// AIDEV-NOTE: Protected lines are for text that must be in every description,
+// e.g. a license attribution and contact address. The template footer can't
+// carry them: classic formatting drops a footer that doesn't fit. Their space
+// is taken out of the limit before anything else is measured, so tracks, the
+// template header and footer and the truncation marker all share what's left.
+
+// Protected holds the lines a description always starts and ends with
+type Protected struct {
+	Header string
+	Footer string
+	length int // Characters both take, newlines included
+}
+
+// ProtectedFor returns the protected lines of descriptions rendered with
+// templateName, see config.Config.ProtectedFor
+func ProtectedFor(cfg *config.Config, templateName string) Protected {
+	header, footer := cfg.ProtectedFor(templateName)
+	return Protected{Header: header, Footer: footer, length: cfg.ProtectedLength(templateName)}
+}
+
+// Reserve returns limit less the protected lines' share of it. It can be
+// negative when the lines alone are over the limit; CheckProtectedSpace
+// reports that at startup.
+func (p Protected) Reserve(limit int) int {
+	return limit - p.length
+}
+
+// Wrap puts the protected header above body and the footer below it, each on
+// its own line. They are there even when no track fit in what they left.
+func (p Protected) Wrap(body string) string {
+	if p.Header == "" && p.Footer == "" {
+		return body
+	}
+	var result strings.Builder
+	if p.Header != "" {
+		result.WriteString(p.Header)
+		result.WriteString("\n")
+	}
+	result.WriteString(body)
+	if p.Footer != "" {
+		if body != "" && !strings.HasSuffix(body, "\n") {
+			result.WriteString("\n")
+		}
+		result.WriteString(p.Footer)
+	}
+	return result.String()
+}
+
+// CheckProtectedSpace fails when the protected lines of templateName alone
+// are over limit, so they could never be published whole
+func CheckProtectedSpace(cfg *config.Config, templateName string, limit int) error {
+	if length := cfg.ProtectedLength(templateName); length > limit {
+		return fmt.Errorf("protected_header and protected_footer take %d characters, over the description limit of %d", length, limit)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+)
+
+func TestProtectedWrap(t *testing.T) {
+	tests := []struct {
+		name      string
+		protected Protected
+		body      string
+		want      string
+	}{
+		{"none", Protected{}, "tracks\n", "tracks\n"},
+		{"both", Protected{Header: "H", Footer: "F"}, "tracks", "H\ntracks\nF"},
+		{"body ends with a newline", Protected{Header: "H", Footer: "F"}, "tracks\n", "H\ntracks\nF"},
+		{"footer only", Protected{Footer: "F"}, "tracks", "tracks\nF"},
+		{"no room for tracks", Protected{Header: "H", Footer: "F"}, "", "H\nF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.protected.Wrap(tt.body); got != tt.want {
+				t.Errorf("Wrap(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectedLinesSurviveTemplateTruncation(t *testing.T) {
+	header := "Licensed under CC BY-NC 4.0 - nowwave.radio/license"
+	footer := "Questions about this tracklist: music@nowwave.radio"
+	protected := len(header) + 1 + len(footer) + 1
+
+	cfg := &config.Config{}
+	cfg.Formatting.ProtectedHeader = header
+	cfg.Formatting.ProtectedFooter = footer
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"station": {
+			Header: "{{.ShowTitle}}\n",
+			Track:  "{{.Index}}. {{.Artist}} - {{.Title}}\n",
+			Footer: "Broadcast by Now Wave Radio\n",
+		},
+		"own": {
+			Track:           "{{.Title}}\n",
+			ProtectedFooter: "(c) The Show's Own Label",
+		},
+	}
+	tf := NewTemplateFormatter(cfg)
+	if err := tf.LoadTemplates(); err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	tracks := make([]cue.Track, 60)
+	for i := range tracks {
+		tracks[i] = cue.Track{Artist: fmt.Sprintf("Artist %d", i+1), Title: "A Fairly Long Song Title"}
+	}
+
+	// The template's header and footer and the truncation margin take 88
+	// characters and a track 39, so the last limits leave room for one or none
+	tests := []struct {
+		name      string
+		maxLength int
+		wantTrack bool
+	}{
+		{"Mixcloud limit", 0, true},
+		{"tracks squeezed to one", protected + 130, true},
+		{"no room for tracks", protected + 60, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tf.FormatWithTemplate("station", tracks, nil, Metadata{ShowTitle: "Test Show", MaxLength: tt.maxLength})
+			if err != nil {
+				t.Fatalf("FormatWithTemplate() error = %v", err)
+			}
+			if !strings.HasPrefix(result, header+"\nTest Show\n") || !strings.HasSuffix(result, "Broadcast by Now Wave Radio\n"+footer) {
+				t.Errorf("protected lines missing or cut:\n%s", result)
+			}
+			limit := tt.maxLength
+			if limit == 0 {
+				limit = 1000
+			}
+			if len(result) > limit {
+				t.Errorf("length %d exceeds the limit of %d:\n%s", len(result), limit, result)
+			}
+			if hasTrack := strings.Contains(result, "1. Artist 1"); hasTrack != tt.wantTrack {
+				t.Errorf("first track listed = %v, want %v:\n%s", hasTrack, tt.wantTrack, result)
+			}
+		})
+	}
+
+	// A template's own protected lines replace formatting's one by one
+	result, err := tf.FormatWithTemplate("own", tracks[:1], nil, Metadata{})
+	if err != nil {
+		t.Fatalf("FormatWithTemplate() error = %v", err)
+	}
+	if want := header + "\nA Fairly Long Song Title\n(c) The Show's Own Label"; result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+	placeholder, err := tf.FormatPlaceholder("own", "Tracklist soon", Metadata{})
+	if err != nil {
+		t.Fatalf("FormatPlaceholder() error = %v", err)
+	}
+	if want := header + "\nTracklist soon\n(c) The Show's Own Label"; placeholder != want {
+		t.Errorf("FormatPlaceholder() = %q, want %q", placeholder, want)
+	}
+
+	// 50 margin + the header and footer with sample data, after the protected lines
+	if space, err := tf.TrackSpace("station", 1000); err != nil || space != 1000-protected-50-len("Test Show\n")-len("Broadcast by Now Wave Radio\n") {
+		t.Errorf("TrackSpace() = %d, %v, want the protected lines reserved", space, err)
+	}
+}
+
+func TestProtectedLinesOverTheLimit(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"legal": {Track: "{{.Title}}\n", ProtectedFooter: strings.Repeat("x", 1000)},
+	}
+	err := NewTemplateFormatter(cfg).LoadTemplates()
+	if err == nil || !strings.Contains(err.Error(), "protected_header and protected_footer take 1001 characters") {
+		t.Errorf("LoadTemplates() error = %v, want the protected lines reported over the limit", err)
+	}
+}
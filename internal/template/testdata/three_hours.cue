@@ -0,0 +1,35 @@
+PERFORMER "Now Wave Radio"
+TITLE "The Long Wave"
+FILE "LongWave.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "Opening"
+    PERFORMER "Artist One"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Second"
+    PERFORMER "Artist Two"
+    INDEX 01 24:30:00
+  TRACK 03 AUDIO
+    TITLE "Third"
+    PERFORMER "Artist Three"
+    INDEX 01 51:10:00
+  TRACK 04 AUDIO
+    TITLE "Top of Hour Two"
+    PERFORMER "Artist Four"
+    INDEX 01 60:05:00
+  TRACK 05 AUDIO
+    TITLE "Fifth"
+    PERFORMER "Artist Five"
+    INDEX 01 95:40:00
+  TRACK 06 AUDIO
+    TITLE "Top of Hour Three"
+    PERFORMER "Artist Six"
+    INDEX 01 120:00:00
+  TRACK 07 AUDIO
+    TITLE "Seventh"
+    PERFORMER "Artist Seven"
+    INDEX 01 148:15:00
+  TRACK 08 AUDIO
+    TITLE "Closing"
+    PERFORMER "Artist Eight"
+    INDEX 01 176:50:00
@@ -0,0 +1,137 @@
+package templatetest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is one line of an edit script
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// UnifiedDiff returns a unified diff turning a into b, or "" if they are equal.
+// Fixtures are small, so a plain LCS table is fast enough.
+func UnifiedDiff(nameA, nameB, a, b string) string {
+	if a == b {
+		return ""
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// Extend the hunk until a run of unchanged lines is long enough to split on
+		last := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			} else if i-last > 2*diffContext {
+				break
+			}
+		}
+
+		hunkStart := max(first-diffContext, start)
+		hunkEnd := min(last+diffContext+1, len(ops))
+		writeHunk(&out, ops, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return out.String()
+}
+
+// writeHunk writes ops[from:to] with its @@ header
+func writeHunk(out *strings.Builder, ops []diffOp, from, to int) {
+	lineA, lineB := 1, 1
+	for _, op := range ops[:from] {
+		if op.kind != '+' {
+			lineA++
+		}
+		if op.kind != '-' {
+			lineB++
+		}
+	}
+
+	countA, countB := 0, 0
+	for _, op := range ops[from:to] {
+		if op.kind != '+' {
+			countA++
+		}
+		if op.kind != '-' {
+			countB++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", lineA, countA, lineB, countB)
+	for _, op := range ops[from:to] {
+		fmt.Fprintf(out, "%c%s\n", op.kind, op.line)
+	}
+}
+
+// diffLines computes a line edit script from a to b via longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines splits text into lines, marking a missing final newline the way diff(1) does
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n\\ No newline at end of file"
+	return lines
+}
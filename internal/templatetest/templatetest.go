@@ -0,0 +1,237 @@
+// Package templatetest runs golden-file regression tests for tracklist
+// templates. Each case is a directory holding an input.cue fixture, an optional
+// metadata.toml and the expected.txt the template must render. The harness is
+// shared by the -test-templates CLI mode and Go tests (see Check).
+package templatetest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+	"github.com/nowwaveradio/mixcloud-updater/internal/cue"
+	"github.com/nowwaveradio/mixcloud-updater/internal/filter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/formatter"
+	"github.com/nowwaveradio/mixcloud-updater/internal/messages"
+	"github.com/nowwaveradio/mixcloud-updater/internal/template"
+)
+
+// Fixture file names inside a case directory
+const (
+	InputFile    = "input.cue"
+	MetadataFile = "metadata.toml"
+	ExpectedFile = "expected.txt"
+)
+
+// DefaultShowDate is rendered as {{.ShowDate}} when metadata.toml doesn't set
+// show_date, so expectations don't depend on the day the tests run
+const DefaultShowDate = "January 2, 2006"
+
+// Metadata is the optional per-case metadata.toml
+type Metadata struct {
+	Template  string                 `toml:"template"`   // Defaults to templates.default
+	ShowTitle string                 `toml:"show_title"` // {{.ShowTitle}}
+	ShowDate  string                 `toml:"show_date"`  // {{.ShowDate}}, defaults to DefaultShowDate
+	Locale    string                 `toml:"locale"`     // Language of "... and N more tracks" and fallbacks, like a show's locale
+	Custom    map[string]interface{} `toml:"custom"`     // {{.Custom.<key>}}
+}
+
+// Result is the outcome of a single case
+type Result struct {
+	Name     string // Case directory name
+	Template string
+	Passed   bool
+	Updated  bool   // expected.txt was written by update mode
+	Diff     string // Unified diff from expected to actual output on mismatch
+	Err      error  // Set when the case couldn't be rendered or compared
+}
+
+// Discover returns the case directories under dir: its subdirectories that
+// contain an input.cue, sorted by name
+func Discover(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading template test directory: %w", err)
+	}
+
+	var cases []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		caseDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(caseDir, InputFile)); err == nil {
+			cases = append(cases, caseDir)
+		}
+	}
+	sort.Strings(cases)
+	return cases, nil
+}
+
+// Run renders every case under dir with cfg's templates and filters. With
+// update set, expected.txt is rewritten instead of compared.
+func Run(cfg *config.Config, dir string, update bool) ([]Result, error) {
+	cases, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no template test cases (subdirectories with %s) in %s", InputFile, dir)
+	}
+
+	results := make([]Result, 0, len(cases))
+	for _, caseDir := range cases {
+		results = append(results, RunCase(cfg, caseDir, update))
+	}
+	return results, nil
+}
+
+// RunCase renders a single case directory and compares (or, with update set,
+// writes) its expected.txt
+func RunCase(cfg *config.Config, caseDir string, update bool) Result {
+	result := Result{Name: filepath.Base(caseDir)}
+
+	metadata, err := loadMetadata(caseDir)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Template = metadata.Template
+	if result.Template == "" {
+		result.Template = cfg.Templates.Default
+	}
+
+	actual, err := Render(cfg, filepath.Join(caseDir, InputFile), result.Template, metadata)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	expectedPath := filepath.Join(caseDir, ExpectedFile)
+	if update {
+		if err := os.WriteFile(expectedPath, []byte(actual), 0644); err != nil {
+			result.Err = fmt.Errorf("writing %s: %w", ExpectedFile, err)
+			return result
+		}
+		result.Updated = true
+		result.Passed = true
+		return result
+	}
+
+	expected, err := os.ReadFile(expectedPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			result.Err = fmt.Errorf("%s missing (run with -update-golden to create it)", ExpectedFile)
+		} else {
+			result.Err = fmt.Errorf("reading %s: %w", ExpectedFile, err)
+		}
+		return result
+	}
+
+	if string(expected) == actual {
+		result.Passed = true
+		return result
+	}
+	result.Diff = UnifiedDiff(ExpectedFile, "actual", string(expected), actual)
+	return result
+}
+
+// Render produces the description a show would publish for the CUE file, using
+// the same filtering as a real run. Unlike a real run, template errors are
+// returned instead of falling back to the classic format.
+func Render(cfg *config.Config, cuePath, templateName string, metadata Metadata) (string, error) {
+	cueSheet, _, err := cue.ParseCueFileWithOptions(cuePath, cue.ParseOptions{
+		Strict:          true,
+		SkipIndex00Only: cfg.Processing.SkipIndex00OnlyTracks,
+	})
+	if err != nil {
+		return "", fmt.Errorf("parsing %s: %w", InputFile, err)
+	}
+
+	trackFilter, err := filter.NewFilter(cfg)
+	if err != nil {
+		return "", fmt.Errorf("creating content filter: %w", err)
+	}
+
+	tracks, excludedReasons := trackFilter.Apply(cueSheet.Tracks)
+
+	catalog, err := messages.ForLocale(metadata.Locale)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", MetadataFile, err)
+	}
+
+	templateMetadata := template.Metadata{
+		ShowTitle:       metadata.ShowTitle,
+		ShowDate:        metadata.ShowDate,
+		Catalog:         cueSheet.Catalog,
+		HasCounts:       true,
+		IncludedCount:   len(tracks),
+		ExcludedCount:   len(cueSheet.Tracks) - len(tracks),
+		ExcludedReasons: excludedReasons,
+		Messages:        catalog,
+		Custom:          metadata.Custom,
+	}
+	if metadata.ShowDate == "" {
+		templateMetadata.ShowDate = DefaultShowDate
+	}
+
+	if _, defined := cfg.Templates.Config[templateName]; !defined {
+		if !template.IsBuiltinTemplate(templateName) {
+			return "", fmt.Errorf("template %s not found", templateName)
+		}
+		return formatter.NewFormatterWithConfig(cfg).FormatTracklistWithTemplate(tracks, trackFilter, templateName, templateMetadata), nil
+	}
+
+	templateFormatter := template.NewTemplateFormatter(cfg)
+	if err := templateFormatter.LoadTemplates(); err != nil {
+		return "", fmt.Errorf("loading templates: %w", err)
+	}
+	return templateFormatter.FormatWithTemplate(templateName, tracks, trackFilter, templateMetadata)
+}
+
+// loadMetadata reads the case's metadata.toml; a missing file yields empty metadata
+func loadMetadata(caseDir string) (Metadata, error) {
+	var metadata Metadata
+	path := filepath.Join(caseDir, MetadataFile)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return metadata, nil
+	}
+	if _, err := toml.DecodeFile(path, &metadata); err != nil {
+		return metadata, fmt.Errorf("reading %s: %w", MetadataFile, err)
+	}
+	return metadata, nil
+}
+
+// TB is the part of testing.TB used by Check
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Check runs the cases under dir from a Go test, reporting each failing case
+// with its diff:
+//
+//	func TestTemplates(t *testing.T) {
+//		cfg, _ := config.LoadConfig("config.toml")
+//		templatetest.Check(t, cfg, "testdata/templates", *updateGolden)
+//	}
+func Check(t TB, cfg *config.Config, dir string, update bool) {
+	t.Helper()
+	results, err := Run(cfg, dir, update)
+	if err != nil {
+		t.Fatalf("template tests: %v", err)
+	}
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			t.Errorf("template case %s: %v", result.Name, result.Err)
+		case !result.Passed:
+			t.Errorf("template case %s (%s) output differs:\n%s", result.Name, result.Template, result.Diff)
+		}
+	}
+}
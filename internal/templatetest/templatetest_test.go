@@ -0,0 +1,177 @@
+package templatetest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nowwaveradio/mixcloud-updater/internal/config"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "Rewrite testdata/golden expectations")
+
+func testConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Station.Name = "Now Wave Radio"
+	cfg.Filtering.ExcludedArtists = []string{"Now Wave Radio"}
+	cfg.Templates.Default = "minimal"
+	cfg.Templates.Config = map[string]config.TemplateConfig{
+		"detailed": {
+			Header: "{{.ShowTitle}} ({{.ShowDate}}) with {{.Custom.host}}\n",
+			Track:  "{{.Index}}. {{.StartTime}} {{.Artist}} - {{.Title}}\n",
+			Footer: "{{.TrackCount}} tracks on {{.StationName}}\n",
+		},
+		"minimal": {Track: "{{.Artist}} - {{.Title}}\n"},
+	}
+	return cfg
+}
+
+func TestGoldenFixtures(t *testing.T) {
+	Check(t, testConfig(), filepath.Join("testdata", "golden"), *updateGolden)
+}
+
+func TestDiscoverSkipsDirectoriesWithoutInput(t *testing.T) {
+	cases, err := Discover(filepath.Join("testdata", "golden"))
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	var names []string
+	for _, caseDir := range cases {
+		names = append(names, filepath.Base(caseDir))
+	}
+	if got := strings.Join(names, ","); got != "classic,detailed,minimal,numbered" {
+		t.Errorf("Discover() = %s, want classic,detailed,minimal,numbered", got)
+	}
+}
+
+// copyCase copies a golden case into a temp dir so it can be modified
+func copyCase(t *testing.T, name string) string {
+	t.Helper()
+	src := filepath.Join("testdata", "golden", name)
+	dst := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dst
+}
+
+func TestRunCaseMismatchAndUpdate(t *testing.T) {
+	caseDir := copyCase(t, "detailed")
+	cfg := testConfig()
+	detailed := cfg.Templates.Config["detailed"]
+	detailed.Track = "{{.Index}}) {{.Artist}} - {{.Title}}\n"
+	cfg.Templates.Config["detailed"] = detailed
+
+	result := RunCase(cfg, caseDir, false)
+	if result.Passed || result.Err != nil {
+		t.Fatalf("RunCase() = %+v, want a mismatch", result)
+	}
+	for _, want := range []string{"--- expected.txt", "+++ actual", "-1. 00:25 Laura Dre - When I Fall", "+1) Laura Dre - When I Fall"} {
+		if !strings.Contains(result.Diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, result.Diff)
+		}
+	}
+
+	if result := RunCase(cfg, caseDir, true); !result.Updated || result.Err != nil {
+		t.Fatalf("RunCase(update) = %+v", result)
+	}
+	if result := RunCase(cfg, caseDir, false); !result.Passed {
+		t.Errorf("RunCase() after update = %+v, want pass", result)
+	}
+}
+
+func TestRunCaseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(caseDir string) error
+		wantErr string
+	}{
+		{
+			name:    "missing expected.txt",
+			modify:  func(caseDir string) error { return os.Remove(filepath.Join(caseDir, ExpectedFile)) },
+			wantErr: "-update-golden",
+		},
+		{
+			name: "unknown template",
+			modify: func(caseDir string) error {
+				return os.WriteFile(filepath.Join(caseDir, MetadataFile), []byte(`template = "nope"`), 0644)
+			},
+			wantErr: "template nope not found",
+		},
+		{
+			name: "bad metadata",
+			modify: func(caseDir string) error {
+				return os.WriteFile(filepath.Join(caseDir, MetadataFile), []byte(`template = `), 0644)
+			},
+			wantErr: MetadataFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caseDir := copyCase(t, "detailed")
+			if err := tt.modify(caseDir); err != nil {
+				t.Fatal(err)
+			}
+			result := RunCase(testConfig(), caseDir, false)
+			if result.Err == nil || !strings.Contains(result.Err.Error(), tt.wantErr) {
+				t.Errorf("RunCase() error = %v, want %q", result.Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 12; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+	}
+	b = append(b, a...)
+	b[1] = "changed 2"
+	b = append(b[:10:10], "inserted", "line 11", "line 12")
+
+	got := UnifiedDiff("a", "b", strings.Join(a, "\n")+"\n", strings.Join(b, "\n")+"\n")
+	want := `--- a
++++ b
+@@ -1,5 +1,5 @@
+ line 1
+-line 2
++changed 2
+ line 3
+ line 4
+ line 5
+@@ -8,5 +8,6 @@
+ line 8
+ line 9
+ line 10
++inserted
+ line 11
+ line 12
+`
+	if got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := UnifiedDiff("a", "b", "same\n", "same\n"); got != "" {
+		t.Errorf("UnifiedDiff() of equal text = %q, want empty", got)
+	}
+	if got := UnifiedDiff("a", "b", "x\n", "x"); !strings.Contains(got, `\ No newline at end of file`) {
+		t.Errorf("UnifiedDiff() should note a missing final newline:\n%s", got)
+	}
+}
@@ -0,0 +1,15 @@
+PERFORMER ""
+TITLE ""
+FILE "input.cue" WAV
+  TRACK 01 AUDIO
+    TITLE "When I Fall"
+    PERFORMER "Laura Dre"
+    INDEX 01 00:25:40
+  TRACK 02 AUDIO
+    TITLE "Station ID"
+    PERFORMER "Now Wave Radio"
+    INDEX 01 03:10:00
+  TRACK 03 AUDIO
+    TITLE "Conditional Love"
+    PERFORMER "Airline Food"
+    INDEX 01 08:15:02
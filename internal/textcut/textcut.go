@@ -0,0 +1,91 @@
+// Package textcut shortens text without breaking characters apart: cuts land
+// on rune boundaries and never separate a character from the combining marks,
+// joiners and modifiers that belong to it.
+package textcut
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// AIDEV-NOTE: Slicing a string by byte index can cut a multibyte character in
+// half, and Mixcloud renders the invalid UTF-8 as replacement characters. Even a
+// rune-safe cut can strip the vowel marks off an Arabic letter or split an emoji
+// ZWJ sequence, so cuts are made between characters as a reader sees them.
+// Neither the standard library nor x/text segments grapheme clusters; clusterLen
+// covers the rules that matter for track titles (combining marks, ZWJ
+// sequences, emoji modifiers and flags, CRLF) rather than all of UAX #29.
+
+const zeroWidthJoiner = '\u200d'
+
+// Prefix returns the longest start of s with at most n runes that doesn't split
+// a character
+func Prefix(s string, n int) string {
+	return prefix(s, n, func(cluster string) int { return utf8.RuneCountInString(cluster) })
+}
+
+// PrefixBytes is Prefix for limits measured in bytes
+func PrefixBytes(s string, n int) string {
+	return prefix(s, n, func(cluster string) int { return len(cluster) })
+}
+
+// Truncate returns s unchanged if it has at most n runes, and otherwise its
+// Prefix of n runes followed by ellipsis
+func Truncate(s string, n int, ellipsis string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return Prefix(s, n) + ellipsis
+}
+
+func prefix(s string, n int, size func(cluster string) int) string {
+	end, used := 0, 0
+	for end < len(s) {
+		length := clusterLen(s[end:])
+		used += size(s[end : end+length])
+		if used > n {
+			break
+		}
+		end += length
+	}
+	return s[:end]
+}
+
+// clusterLen returns the length in bytes of the character s starts with,
+// including the runes that must stay attached to it
+func clusterLen(s string) int {
+	first, i := utf8.DecodeRuneInString(s)
+	if first == '\r' && len(s) > i && s[i] == '\n' {
+		return i + 1
+	}
+
+	prev := first
+	unpairedFlag := isRegionalIndicator(first)
+	for i < len(s) {
+		next, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case extends(next):
+		case prev == zeroWidthJoiner:
+		case unpairedFlag && isRegionalIndicator(next):
+			unpairedFlag = false
+		default:
+			return i
+		}
+		prev = next
+		i += size
+	}
+	return i
+}
+
+// extends reports whether r attaches to the character before it
+func extends(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zeroWidthJoiner ||
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // Emoji skin tone modifiers
+		(r >= 0xE0020 && r <= 0xE007F) // Tag characters in subdivision flags
+}
+
+// isRegionalIndicator reports whether r is one of the letters flag emoji are made of
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -0,0 +1,123 @@
+package textcut
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// Titles from the world-music show, plus the emoji sequences a cut must not split
+var titles = map[string]string{
+	"arabic":            "مَرْحَبًا بِكُمْ - أُمّ كُلْثُوم",
+	"japanese":          "夜に駆ける (YOASOBI) ～ 群青",
+	"emoji":             "Party 🎉👍🏽 Mix",
+	"zwj family":        "Family 👩\u200d👩\u200d👧\u200d👦 Song",
+	"flags":             "🇯🇵🇲🇦🇧🇷 World Tour",
+	"decomposed accent": "Cafe\u0301 del Mar",
+	"crlf":              "Line\r\nBreak",
+}
+
+func TestPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"fits", "Song", 10, "Song"},
+		{"ascii", "Song Title", 4, "Song"},
+		{"zero", "Song", 0, ""},
+		{"negative", "Song", -1, ""},
+		{"japanese", "夜に駆ける", 2, "夜に"},
+		{"arabic keeps vowel marks", "مَرْحَبًا", 1, ""}, // "مَ" is two runes
+		{"arabic letter with its mark", "مَرْحَبًا", 2, "مَ"},
+		{"decomposed accent", "Cafe\u0301", 4, "Caf"},
+		{"skin tone", "👍🏽!", 1, ""},
+		{"skin tone fits", "👍🏽!", 2, "👍🏽"},
+		{"zwj sequence", "👩\u200d👧 hi", 2, ""},
+		{"zwj sequence fits", "👩\u200d👧 hi", 3, "👩\u200d👧"},
+		{"flag pairs", "🇯🇵🇲🇦", 3, "🇯🇵"},
+		{"crlf", "a\r\nb", 2, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prefix(tt.s, tt.n); got != tt.want {
+				t.Errorf("Prefix(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixBytes(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"... and more", 3, "..."},
+		{"夜に駆ける", 7, "夜に"}, // 3 bytes each
+		{"夜に駆ける", 5, "夜"},
+		{"Cafe\u0301", 5, "Caf"},
+	}
+
+	for _, tt := range tests {
+		if got := PrefixBytes(tt.s, tt.n); got != tt.want {
+			t.Errorf("PrefixBytes(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestCutsAreValid cuts every title at every length and checks that the result
+// is valid UTF-8, within the limit, and leaves no mark or joiner at the cut
+func TestCutsAreValid(t *testing.T) {
+	for name, title := range titles {
+		for n := 0; n <= len(title)+1; n++ {
+			for _, cut := range []struct {
+				kind   string
+				got    string
+				length int
+			}{
+				{"Prefix", Prefix(title, n), utf8.RuneCountInString(Prefix(title, n))},
+				{"PrefixBytes", PrefixBytes(title, n), len(PrefixBytes(title, n))},
+			} {
+				if !utf8.ValidString(cut.got) {
+					t.Errorf("%s: %s(%d) = %q is not valid UTF-8", name, cut.kind, n, cut.got)
+				}
+				if cut.length > n {
+					t.Errorf("%s: %s(%d) = %q is %d long", name, cut.kind, n, cut.got, cut.length)
+				}
+				if !strings.HasPrefix(title, cut.got) {
+					t.Errorf("%s: %s(%d) = %q is not a prefix", name, cut.kind, n, cut.got)
+				}
+				if rest := title[len(cut.got):]; rest != "" {
+					if next, _ := utf8.DecodeRuneInString(rest); extends(next) {
+						t.Errorf("%s: %s(%d) = %q cuts off %U from its character", name, cut.kind, n, cut.got, next)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"fits", "夜に駆ける", 5, "夜に駆ける"},
+		{"japanese", "夜に駆ける", 3, "夜に駆..."},
+		{"arabic", "أُمّ كُلْثُوم", 4, "أُمّ..."},
+		{"emoji", "🎉👍🏽 Mix", 2, "🎉..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.s, tt.n, "..."); got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+			}
+		})
+	}
+}
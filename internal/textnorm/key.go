@@ -0,0 +1,18 @@
+package textnorm
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// AIDEV-NOTE: An accented key can reach us composed ("ú", as typed in most
+// terminals) or decomposed ("u" plus a combining accent, as some macOS editors
+// save it). Both look identical, so keys are compared in NFC.
+
+// FoldKey returns the canonical form of a config key such as a show key:
+// NFC-normalized, lower case, trimmed and with runs of whitespace collapsed to
+// one space, e.g. " Música  Nueva" → "música nueva"
+func FoldKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(norm.NFC.String(key)), " "))
+}
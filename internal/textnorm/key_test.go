@@ -0,0 +1,26 @@
+package textnorm
+
+import "testing"
+
+func TestFoldKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"already canonical", "sounds-like", "sounds-like"},
+		{"upper case", "Late Night", "late night"},
+		{"composed accent", "Música Nueva", "música nueva"},
+		{"decomposed accent", "Mu\u0301sica Nueva", "música nueva"},
+		{"surrounding and repeated whitespace", "  Música \t Nueva ", "música nueva"},
+		{"non-latin script", "Ночной Эфир", "ночной эфир"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FoldKey(tt.input); got != tt.expected {
+				t.Errorf("FoldKey(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package textnorm
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// AIDEV-NOTE: Mixcloud's edit endpoint accepts control characters, but its web UI
+// cuts the description off at the first one (a 0x0B vertical tab in a CUE title
+// hid the rest of a tracklist). Descriptions are sanitized before the length
+// check so the counts match what is sent.
+
+// zeroWidth lists invisible characters stripped with stripZeroWidth. Opt-in,
+// since removing the zero-width joiner also splits emoji sequences.
+var zeroWidth = map[rune]bool{
+	'\u200b': true, // Zero-width space
+	'\u200c': true, // Zero-width non-joiner
+	'\u200d': true, // Zero-width joiner
+	'\u2060': true, // Word joiner
+	'\ufeff': true, // Byte order mark
+}
+
+// SanitizeReport counts what SanitizeDescription changed
+type SanitizeReport struct {
+	ControlChars      int // Removed control characters (a tab becomes a space)
+	LineEndings       int // \r\n and \r converted to \n
+	NonBreakingSpaces int // Replaced with regular spaces
+	ZeroWidth         int // Removed zero-width characters and BOMs
+}
+
+// Changed reports whether anything was sanitized
+func (r SanitizeReport) Changed() bool {
+	return r.ControlChars+r.LineEndings+r.NonBreakingSpaces+r.ZeroWidth > 0
+}
+
+// String summarizes the changes, e.g. "1 control character, 2 line endings"
+func (r SanitizeReport) String() string {
+	var parts []string
+	add := func(n int, what string) {
+		if n == 1 {
+			parts = append(parts, "1 "+what)
+		} else if n > 1 {
+			parts = append(parts, fmt.Sprintf("%d %ss", n, what))
+		}
+	}
+	add(r.ControlChars, "control character")
+	add(r.LineEndings, "line ending")
+	add(r.NonBreakingSpaces, "non-breaking space")
+	add(r.ZeroWidth, "zero-width character")
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SanitizeDescription prepares text for a Mixcloud description: \r\n and \r become
+// \n, tabs become spaces, other control characters are removed and non-breaking
+// spaces become regular spaces. With stripZeroWidth, zero-width characters and
+// BOMs are removed too.
+func SanitizeDescription(s string, stripZeroWidth bool) (string, SanitizeReport) {
+	var report SanitizeReport
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteRune(r)
+		case r == '\r':
+			report.LineEndings++
+			if !strings.HasPrefix(s[i+1:], "\n") {
+				b.WriteByte('\n')
+			}
+		case r == '\t':
+			report.ControlChars++
+			b.WriteByte(' ')
+		case unicode.IsControl(r):
+			report.ControlChars++
+		case r == '\u00a0':
+			report.NonBreakingSpaces++
+			b.WriteByte(' ')
+		case stripZeroWidth && zeroWidth[r]:
+			report.ZeroWidth++
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	if !report.Changed() {
+		return s, report
+	}
+	return b.String(), report
+}
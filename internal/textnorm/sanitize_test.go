@@ -0,0 +1,95 @@
+package textnorm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSanitizeDescription(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          []byte
+		stripZeroWidth bool
+		want           []byte
+		wantReport     SanitizeReport
+	}{
+		{
+			name:  "clean text unchanged",
+			input: []byte("1. Artist - Title\n2. Café - Über 🎵\n"),
+			want:  []byte("1. Artist - Title\n2. Café - Über 🎵\n"),
+		},
+		{
+			name:       "vertical tab in a title",
+			input:      []byte("1. Artist - Ti\x0btle\n2. Next - Song"),
+			want:       []byte("1. Artist - Title\n2. Next - Song"),
+			wantReport: SanitizeReport{ControlChars: 1},
+		},
+		{
+			name:       "other control characters",
+			input:      []byte("a\x00b\x07c\x1bd\x7fe\xc2\x85f"), // NUL, BEL, ESC, DEL, C1 NEL
+			want:       []byte("abcdef"),
+			wantReport: SanitizeReport{ControlChars: 5},
+		},
+		{
+			name:       "tab becomes a space",
+			input:      []byte("Artist\tTitle"),
+			want:       []byte("Artist Title"),
+			wantReport: SanitizeReport{ControlChars: 1},
+		},
+		{
+			name:       "CRLF and CR line endings",
+			input:      []byte("one\r\ntwo\rthree\r\n\r\nfour"),
+			want:       []byte("one\ntwo\nthree\n\nfour"),
+			wantReport: SanitizeReport{LineEndings: 4},
+		},
+		{
+			name:       "non-breaking spaces",
+			input:      []byte("Artist\xc2\xa0-\xc2\xa0Title"),
+			want:       []byte("Artist - Title"),
+			wantReport: SanitizeReport{NonBreakingSpaces: 2},
+		},
+		{
+			name:  "zero-width characters kept by default",
+			input: []byte("\xef\xbb\xbfA\xe2\x80\x8bB"),
+			want:  []byte("\xef\xbb\xbfA\xe2\x80\x8bB"),
+		},
+		{
+			name:           "zero-width characters stripped",
+			input:          []byte("\xef\xbb\xbfA\xe2\x80\x8bB\xe2\x80\x8cC\xe2\x80\x8dD\xe2\x81\xa0E"),
+			stripZeroWidth: true,
+			want:           []byte("ABCDE"),
+			wantReport:     SanitizeReport{ZeroWidth: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report := SanitizeDescription(string(tt.input), tt.stripZeroWidth)
+			if !bytes.Equal([]byte(got), tt.want) {
+				t.Errorf("SanitizeDescription() = % x, want % x", got, tt.want)
+			}
+			if report != tt.wantReport {
+				t.Errorf("report = %+v, want %+v", report, tt.wantReport)
+			}
+			if report.Changed() != (tt.wantReport != SanitizeReport{}) {
+				t.Errorf("Changed() = %v", report.Changed())
+			}
+		})
+	}
+}
+
+func TestSanitizeReportString(t *testing.T) {
+	tests := []struct {
+		report SanitizeReport
+		want   string
+	}{
+		{SanitizeReport{}, "nothing"},
+		{SanitizeReport{ControlChars: 1}, "1 control character"},
+		{SanitizeReport{LineEndings: 3, ZeroWidth: 1}, "3 line endings, 1 zero-width character"},
+	}
+	for _, tt := range tests {
+		if got := tt.report.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Package textnorm repairs the characters Windows editors and word processors
+// substitute into hand-edited text files: byte order marks, typographic quotes,
+// non-breaking spaces and Windows-1252 encoding.
+package textnorm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// AIDEV-NOTE: Config and CUE files are plain ASCII syntax, so these characters are
+// almost always accidental. Callers decide whether to reject them (config) or
+// repair them silently (CUE lines).
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// typographic maps substituted characters to their names and ASCII equivalents
+var typographic = map[rune]struct{ name, ascii string }{
+	'\u201c': {"left double quote “", `"`},
+	'\u201d': {"right double quote ”", `"`},
+	'\u2018': {"left single quote ‘", "'"},
+	'\u2019': {"right single quote ’", "'"},
+	'\u00a0': {"non-breaking space", " "},
+}
+
+var asciiReplacer = func() *strings.Replacer {
+	var pairs []string
+	for r, sub := range typographic {
+		pairs = append(pairs, string(r), sub.ascii)
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// windows1252 holds the 0x80-0x9F range, where Windows-1252 differs from Latin-1.
+// Unassigned bytes decode to U+FFFD.
+var windows1252 = [32]rune{
+	'€', '\ufffd', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\ufffd', 'Ž', '\ufffd',
+	'\ufffd', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\ufffd', 'ž', 'Ÿ',
+}
+
+// Issue is a substituted character found on a line
+type Issue struct {
+	Line int    // 1-based
+	Char string // e.g. "left double quote “"
+}
+
+// HasBOM reports whether data starts with a UTF-8 byte order mark
+func HasBOM(data []byte) bool {
+	return bytes.HasPrefix(data, utf8BOM)
+}
+
+// StripBOM removes a leading UTF-8 byte order mark
+func StripBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, utf8BOM)
+}
+
+// FromWindows1252 decodes bytes that aren't valid UTF-8 as Windows-1252, so
+// files mixing both encodings (one line pasted from elsewhere) come out as UTF-8.
+// Valid UTF-8 is returned unchanged.
+func FromWindows1252(data []byte) []byte {
+	if utf8.Valid(data) {
+		return data
+	}
+	var buf bytes.Buffer
+	buf.Grow(len(data) + len(data)/4)
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		switch {
+		case r != utf8.RuneError || size > 1:
+			buf.Write(data[:size])
+		case data[0] < 0xA0:
+			buf.WriteRune(windows1252[data[0]-0x80])
+		default:
+			buf.WriteRune(rune(data[0])) // Latin-1
+		}
+		data = data[size:]
+	}
+	return buf.Bytes()
+}
+
+// windows1252Byte maps each rune of Windows-1252's 0x80-0x9F range back to its byte
+var windows1252Byte = func() map[rune]byte {
+	m := make(map[rune]byte, len(windows1252))
+	for i, r := range windows1252 {
+		if r != '\ufffd' {
+			m[r] = byte(0x80 + i)
+		}
+	}
+	return m
+}()
+
+// RepairMojibake undoes UTF-8 text that was decoded as Windows-1252 and saved
+// as UTF-8 again, e.g. "Donâ€™t" → "Don’t". s is only changed when every rune
+// maps back to a Windows-1252 byte and those bytes are valid UTF-8 holding a
+// non-ASCII character, so genuine accented text is left alone.
+func RepairMojibake(s string) string {
+	suspect := false
+	raw := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch b, ok := windows1252Byte[r]; {
+		case r < 0x80:
+			raw = append(raw, byte(r))
+		case ok:
+			raw = append(raw, b)
+			suspect = true
+		case r >= 0xA0 && r <= 0xFF:
+			raw = append(raw, byte(r))
+			suspect = true
+		default:
+			return s
+		}
+	}
+	if !suspect || !utf8.Valid(raw) {
+		return s
+	}
+	return string(raw)
+}
+
+// ToASCII replaces typographic quotes and non-breaking spaces with plain ASCII
+func ToASCII(s string) string {
+	return asciiReplacer.Replace(s)
+}
+
+// Normalize strips a BOM, decodes Windows-1252 and replaces typographic quotes
+// and non-breaking spaces with plain ASCII
+func Normalize(data []byte) []byte {
+	return []byte(ToASCII(string(FromWindows1252(StripBOM(data)))))
+}
+
+// Scan lists the lines of data holding Windows-1252 bytes, typographic quotes
+// or non-breaking spaces. A leading BOM is not reported.
+func Scan(data []byte) []Issue {
+	var issues []Issue
+	for i, line := range bytes.Split(StripBOM(data), []byte("\n")) {
+		if !utf8.Valid(line) {
+			issues = append(issues, Issue{Line: i + 1, Char: "Windows-1252 text"})
+			line = FromWindows1252(line)
+		}
+		seen := make(map[rune]bool)
+		for _, r := range string(line) {
+			if sub, ok := typographic[r]; ok && !seen[r] {
+				seen[r] = true
+				issues = append(issues, Issue{Line: i + 1, Char: sub.name})
+			}
+		}
+	}
+	return issues
+}
+
+// Describe summarizes issues by line, e.g. "line 3 (left double quote “, right double quote ”)"
+func Describe(issues []Issue) string {
+	byLine := make(map[int][]string)
+	for _, issue := range issues {
+		byLine[issue.Line] = append(byLine[issue.Line], issue.Char)
+	}
+	lines := make([]int, 0, len(byLine))
+	for line := range byLine {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+
+	parts := make([]string, len(lines))
+	for i, line := range lines {
+		parts[i] = fmt.Sprintf("line %d (%s)", line, strings.Join(byLine[line], ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Lines returns the distinct line numbers of issues in ascending order
+func Lines(issues []Issue) []int {
+	seen := make(map[int]bool)
+	var lines []int
+	for _, issue := range issues {
+		if !seen[issue.Line] {
+			seen[issue.Line] = true
+			lines = append(lines, issue.Line)
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
@@ -0,0 +1,90 @@
+package textnorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromWindows1252(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"valid UTF-8 unchanged", []byte("Café “quoted”"), "Café “quoted”"},
+		{"smart quotes", []byte("\x93Hi\x94 Don\x92t"), "“Hi” Don’t"},
+		{"latin-1 range", []byte("Caf\xe9\xa0x"), "Café\u00a0x"},
+		{"mixed encodings", []byte("“UTF-8” and \x93cp1252\x94"), "“UTF-8” and “cp1252”"},
+		{"unassigned byte", []byte("a\x81b"), "a�b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(FromWindows1252(tt.input)); got != tt.expected {
+				t.Errorf("FromWindows1252() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepairMojibake(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"apostrophe", "Donâ€™t Stop", "Don’t Stop"},
+		{"en dash and ellipsis", "Rock â€“ Rollâ€¦", "Rock – Roll…"},
+		{"low double quote", "â€žHalloâ€œ", "„Hallo“"},
+		{"accented letter", "CafÃ©", "Café"},
+		{"plain ASCII unchanged", "Don't Stop", "Don't Stop"},
+		{"genuine accents unchanged", "Café Müller", "Café Müller"},
+		{"genuine curly quote unchanged", "Don’t Stop", "Don’t Stop"},
+		{"rune outside Windows-1252 unchanged", "Donâ€™t ★", "Donâ€™t ★"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RepairMojibake(tt.input); got != tt.expected {
+				t.Errorf("RepairMojibake(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	input := []byte("\xef\xbb\xbfname\u00a0= “Now Wave” # Don\x92t")
+	expected := `name = "Now Wave" # Don't`
+	if got := string(Normalize(input)); got != expected {
+		t.Errorf("Normalize() = %q, want %q", got, expected)
+	}
+}
+
+func TestScan(t *testing.T) {
+	data := []byte("\xef\xbb\xbf[station]\nname = “Now Wave”\nok = \"plain\"\nkey\u00a0= \"Don\x92t\"\n")
+
+	issues := Scan(data)
+	expected := []Issue{
+		{Line: 2, Char: "left double quote “"},
+		{Line: 2, Char: "right double quote ”"},
+		{Line: 4, Char: "Windows-1252 text"},
+		{Line: 4, Char: "non-breaking space"},
+		{Line: 4, Char: "right single quote ’"},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Scan() = %+v, want %+v", issues, expected)
+	}
+
+	if got := Lines(issues); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Lines() = %v, want [2 4]", got)
+	}
+	wantDescription := "line 2 (left double quote “, right double quote ”), " +
+		"line 4 (Windows-1252 text, non-breaking space, right single quote ’)"
+	if got := Describe(issues); got != wantDescription {
+		t.Errorf("Describe() = %q, want %q", got, wantDescription)
+	}
+
+	if issues := Scan([]byte("\xef\xbb\xbfplain = \"ascii\"\n")); len(issues) != 0 {
+		t.Errorf("Scan() of BOM-only data = %+v, want none", issues)
+	}
+}
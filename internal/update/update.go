@@ -0,0 +1,192 @@
+// Package update checks GitHub releases for a newer version of the updater.
+// It only reports; downloading and replacing the binary is left to the operator.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReleasesURL is the GitHub API endpoint for the latest published release
+const ReleasesURL = "https://api.github.com/repos/nowwaveradio/mixcloud-updater/releases/latest"
+
+// Timeout bounds a whole check; offline hosts give up quickly instead of stalling a run
+const Timeout = 5 * time.Second
+
+// Interval is how often processing.update_check looks for a new release
+const Interval = 24 * time.Hour
+
+// Release is the latest published release
+type Release struct {
+	Version string // Tag name, e.g. "v1.2.0"
+	URL     string // Release notes page
+}
+
+// Checker queries the releases API
+type Checker struct {
+	Client *http.Client // Defaults to a client with Timeout
+	URL    string       // Defaults to ReleasesURL
+}
+
+// Latest fetches the latest release. Drafts and pre-releases are never returned
+// by the endpoint, so operators are only pointed at stable builds.
+func (c Checker) Latest(ctx context.Context) (Release, error) {
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: Timeout}
+	}
+	url := c.URL
+	if url == "" {
+		url = ReleasesURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Release{}, fmt.Errorf("creating release request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("fetching latest release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("fetching latest release: HTTP %d", resp.StatusCode)
+	}
+
+	var body struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return Release{}, fmt.Errorf("parsing latest release: %w", err)
+	}
+	if body.TagName == "" {
+		return Release{}, fmt.Errorf("latest release has no tag")
+	}
+
+	return Release{Version: body.TagName, URL: body.HTMLURL}, nil
+}
+
+// Newer reports whether latest is a higher semantic version than current
+func Newer(current, latest string) (bool, error) {
+	cmp, err := Compare(latest, current)
+	if err != nil {
+		return false, err
+	}
+	return cmp > 0, nil
+}
+
+// Due reports whether a periodic check last made at lastCheck should run again
+func Due(lastCheck, now time.Time) bool {
+	return lastCheck.IsZero() || now.Sub(lastCheck) >= Interval || now.Before(lastCheck)
+}
+
+// version is a parsed semantic version; build metadata is dropped since it
+// doesn't affect precedence
+type version struct {
+	core       [3]int
+	prerelease []string
+}
+
+// Compare orders two semantic versions ("v" prefix optional), returning -1, 0 or 1
+func Compare(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.core {
+		if va.core[i] != vb.core[i] {
+			return sign(va.core[i] - vb.core[i]), nil
+		}
+	}
+
+	// A release outranks its pre-releases: 1.2.0 > 1.2.0-rc.1
+	switch {
+	case len(va.prerelease) == 0 && len(vb.prerelease) == 0:
+		return 0, nil
+	case len(va.prerelease) == 0:
+		return 1, nil
+	case len(vb.prerelease) == 0:
+		return -1, nil
+	}
+
+	for i := 0; i < len(va.prerelease) && i < len(vb.prerelease); i++ {
+		if cmp := comparePrereleaseIdentifier(va.prerelease[i], vb.prerelease[i]); cmp != 0 {
+			return cmp, nil
+		}
+	}
+	return sign(len(va.prerelease) - len(vb.prerelease)), nil
+}
+
+// parseVersion parses MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]
+func parseVersion(s string) (version, error) {
+	var v version
+
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.prerelease = strings.Split(rest[i+1:], ".")
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return version{}, fmt.Errorf("invalid version %q: want MAJOR.MINOR.PATCH", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, fmt.Errorf("invalid version %q: %q is not a number", s, part)
+		}
+		v.core[i] = n
+	}
+	for _, id := range v.prerelease {
+		if id == "" {
+			return version{}, fmt.Errorf("invalid version %q: empty pre-release identifier", s)
+		}
+	}
+
+	return v, nil
+}
+
+// comparePrereleaseIdentifier orders numeric identifiers numerically and below
+// alphanumeric ones, which compare as strings
+func comparePrereleaseIdentifier(a, b string) int {
+	na, aErr := strconv.Atoi(a)
+	nb, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(na - nb)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"1.0.1", "1.0.0", 1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-rc.1", "1.0.0-beta", 1},
+		{"1.0.0-beta.11", "1.0.0-beta.2", 1},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1},
+		{"1.0.0-alpha.beta", "1.0.0-alpha.1", 1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := Compare(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if reverse, _ := Compare(tt.b, tt.a); reverse != -tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, reverse, -tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareInvalid(t *testing.T) {
+	for _, v := range []string{"", "1.0", "1.0.0.0", "latest", "1.x.0", "1.0.0-"} {
+		if _, err := Compare(v, "1.0.0"); err == nil {
+			t.Errorf("Compare(%q) error = nil, want error", v)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    Release
+		wantErr bool
+	}{
+		{
+			name:   "release",
+			status: http.StatusOK,
+			body:   `{"tag_name": "v1.2.0", "html_url": "https://github.com/nowwaveradio/mixcloud-updater/releases/tag/v1.2.0"}`,
+			want:   Release{Version: "v1.2.0", URL: "https://github.com/nowwaveradio/mixcloud-updater/releases/tag/v1.2.0"},
+		},
+		{name: "no releases", status: http.StatusNotFound, body: `{"message": "Not Found"}`, wantErr: true},
+		{name: "rate limited", status: http.StatusForbidden, body: `{}`, wantErr: true},
+		{name: "missing tag", status: http.StatusOK, body: `{"html_url": "x"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := Checker{URL: server.URL}.Latest(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Latest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Latest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	checker := Checker{Client: &http.Client{Timeout: 50 * time.Millisecond}, URL: server.URL}
+	start := time.Now()
+	if _, err := checker.Latest(context.Background()); err == nil {
+		t.Fatal("Latest() error = nil, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Latest() took %s, want it to give up at the client timeout", elapsed)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2025, 6, 28, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		lastCheck time.Time
+		want      bool
+	}{
+		{"never checked", time.Time{}, true},
+		{"checked an hour ago", now.Add(-time.Hour), false},
+		{"checked yesterday", now.Add(-Interval), true},
+		{"clock moved backwards", now.Add(time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Due(tt.lastCheck, now); got != tt.want {
+				t.Errorf("Due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}